@@ -8,11 +8,13 @@ import (
 	"flag"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"strconv"
 
 	"github.com/golang/snappy"
+	pkgerrors "github.com/pkg/errors"
 
 	"k8s.io/klog/v2"
 
@@ -20,6 +22,7 @@ import (
 	metrics "kubevirt.io/containerized-data-importer/pkg/monitoring/metrics/cdi-cloner"
 	"kubevirt.io/containerized-data-importer/pkg/util"
 	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
+	"kubevirt.io/containerized-data-importer/pkg/util/sparse"
 )
 
 var (
@@ -55,7 +58,7 @@ func (er *execReader) Close() error {
 }
 
 func init() {
-	flag.StringVar(&contentType, "content-type", "", "filesystem-clone|blockdevice-clone")
+	flag.StringVar(&contentType, "content-type", "", "filesystem-clone|blockdevice-clone|blockdevice-sparse-clone|blockdevice-delta-clone")
 	flag.StringVar(&mountPoint, "mount", "", "pvc mount point")
 	flag.Uint64Var(&uploadBytes, "upload-bytes", 0, "approx number of bytes in input")
 	klog.InitFlags(nil)
@@ -130,9 +133,113 @@ func pipeToSnappy(reader io.ReadCloser) io.ReadCloser {
 	return pr
 }
 
+// pipeSparseToSnappy writes the sparse stream of the block device to snappy, the data is read through reader
+func pipeSparseToSnappy(device *os.File, reader io.ReadCloser, size int64) io.ReadCloser {
+	pr, pw := io.Pipe()
+	sbw := snappy.NewBufferedWriter(pw)
+
+	go func() {
+		if err := sparse.Encode(sbw, device, reader, size); err != nil {
+			klog.Fatalf("Error %s writing the sparse stream", err)
+		}
+		if err := sbw.Close(); err != nil {
+			klog.Fatalf("Error closing snappy writer %+v", err)
+		}
+		if err := pw.Close(); err != nil {
+			klog.Fatalf("Error closing pipe writer %+v", err)
+		}
+	}()
+
+	return pr
+}
+
+// newSparseBlockReader returns the stream of the data of the block device, skipping its holes and blocks of zeros. The
+// progress is reported against the bytes which are not in a hole, which is the whole device if its holes can not be
+// found.
+func newSparseBlockReader(ownerUID string) io.ReadCloser {
+	device, err := os.Open(mountPoint)
+	if err != nil {
+		klog.Fatalf("Error opening block device %q: %+v", mountPoint, err)
+	}
+	size, err := device.Seek(0, io.SeekEnd)
+	if err != nil {
+		klog.Fatalf("Error getting the size of block device %q: %+v", mountPoint, err)
+	}
+	allocated := sparse.AllocatedBytes(device, size)
+	klog.Infof("Reading %d bytes of the %d bytes of %q", allocated, size, mountPoint)
+
+	progressReader, err := createProgressReader(device, ownerUID, uint64(allocated))
+	if err != nil {
+		klog.Fatalf("Error creating progress reader: %v", err)
+	}
+	return pipeSparseToSnappy(device, progressReader, size)
+}
+
+// getTargetHashes returns the hashes of the blocks of the target of the upload, or nil if the target can not send them
+func getTargetHashes(client *http.Client, uploadURL string) (*sparse.Hashes, error) {
+	hashesURL, err := url.Parse(uploadURL)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "invalid upload URL %q", uploadURL)
+	}
+	hashesURL.Path = common.UploadPathHashes
+
+	response, err := client.Get(hashesURL.String())
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "error getting the hashes from %s", hashesURL)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, pkgerrors.Errorf("unexpected status code %d getting the hashes", response.StatusCode)
+	}
+	return sparse.ReadHashes(response.Body)
+}
+
+// newDeltaBlockReader returns the stream of the blocks of the block device which differ from the target, the whole
+// device is read to find them
+func newDeltaBlockReader(ownerUID string, hashes *sparse.Hashes) io.ReadCloser {
+	device, err := os.Open(mountPoint)
+	if err != nil {
+		klog.Fatalf("Error opening block device %q: %+v", mountPoint, err)
+	}
+	size, err := device.Seek(0, io.SeekEnd)
+	if err == nil {
+		_, err = device.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		klog.Fatalf("Error getting the size of block device %q: %+v", mountPoint, err)
+	}
+	klog.Infof("Comparing the %d bytes of %q with the %d bytes of the target", size, mountPoint, hashes.Size())
+
+	progressReader, err := createProgressReader(device, ownerUID, uint64(size))
+	if err != nil {
+		klog.Fatalf("Error creating progress reader: %v", err)
+	}
+
+	pr, pw := io.Pipe()
+	sbw := snappy.NewBufferedWriter(pw)
+
+	go func() {
+		if err := sparse.EncodeDelta(sbw, progressReader, size, hashes); err != nil {
+			klog.Fatalf("Error %s writing the delta stream", err)
+		}
+		if err := sbw.Close(); err != nil {
+			klog.Fatalf("Error closing snappy writer %+v", err)
+		}
+		if err := pw.Close(); err != nil {
+			klog.Fatalf("Error closing pipe writer %+v", err)
+		}
+	}()
+
+	return pr
+}
+
 func validateContentType() {
 	switch contentType {
-	case "filesystem-clone", "blockdevice-clone":
+	case "filesystem-clone", "blockdevice-clone", "blockdevice-sparse-clone", "blockdevice-delta-clone":
 	default:
 		klog.Fatalf("Invalid content-type %q", contentType)
 	}
@@ -241,16 +348,34 @@ func main() {
 
 	klog.V(1).Infoln("Starting cloner target")
 
-	progressReader, err := createProgressReader(getInputStream(preallocation), ownerUID, uploadBytes)
-	if err != nil {
-		klog.Fatalf("Error creating progress reader: %v", err)
+	client := createHTTPClient(clientKey, clientCert, serverCert)
+
+	var reader io.ReadCloser
+	switch contentType {
+	case common.BlockdeviceDeltaClone:
+		hashes, err := getTargetHashes(client, url)
+		if err != nil {
+			klog.Fatalf("Error getting the hashes of the target: %+v", err)
+		}
+		if hashes != nil {
+			reader = newDeltaBlockReader(ownerUID, hashes)
+			break
+		}
+		klog.Infof("The target can not send the hashes of its blocks, sending all the data")
+		contentType = common.BlockdeviceSparseClone
+		reader = newSparseBlockReader(ownerUID)
+	case common.BlockdeviceSparseClone:
+		reader = newSparseBlockReader(ownerUID)
+	default:
+		progressReader, err := createProgressReader(getInputStream(preallocation), ownerUID, uploadBytes)
+		if err != nil {
+			klog.Fatalf("Error creating progress reader: %v", err)
+		}
+		reader = pipeToSnappy(progressReader)
 	}
-	reader := pipeToSnappy(progressReader)
 
 	startPrometheus()
 
-	client := createHTTPClient(clientKey, clientCert, serverCert)
-
 	req, _ := http.NewRequest(http.MethodPost, url, reader)
 
 	if contentType != "" {
@@ -1,15 +1,24 @@
 package main
 
 import (
+	"bytes"
 	"errors"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/golang/snappy"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
 	prometheusutil "kubevirt.io/containerized-data-importer/pkg/util/prometheus"
+	"kubevirt.io/containerized-data-importer/pkg/util/sparse"
 )
 
 var _ = Describe("Prometheus Endpoint", func() {
@@ -29,6 +38,79 @@ var _ = Describe("Prometheus Endpoint", func() {
 	})
 })
 
+var _ = Describe("Sparse block clone", func() {
+	It("Should only send the data of the device", func() {
+		const size = 8 * sparse.BlockSize
+		device, err := os.Create(filepath.Join(GinkgoT().TempDir(), "disk.img"))
+		Expect(err).ToNot(HaveOccurred())
+		defer device.Close()
+		Expect(device.Truncate(size)).To(Succeed())
+		data := bytes.Repeat([]byte{1}, sparse.BlockSize)
+		_, err = device.WriteAt(data, 2*sparse.BlockSize)
+		Expect(err).ToNot(HaveOccurred())
+
+		counter := &util.CountingReader{Reader: device}
+		stream, err := io.ReadAll(snappy.NewReader(pipeSparseToSnappy(device, counter, size)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(counter.Current).To(Equal(uint64(sparse.AllocatedBytes(device, size))))
+
+		sr, err := sparse.NewReader(bytes.NewReader(stream))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sr.Size()).To(Equal(int64(size)))
+		offset, length, err := sr.Next()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(offset).To(Equal(int64(2 * sparse.BlockSize)))
+		Expect(length).To(Equal(int64(sparse.BlockSize)))
+		extent, err := io.ReadAll(sr)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(extent).To(Equal(data))
+		_, _, err = sr.Next()
+		Expect(err).To(Equal(io.EOF))
+	})
+})
+
+var _ = Describe("Delta block clone", func() {
+	var image []byte
+
+	serveHashes := func(status int) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal(common.UploadPathHashes))
+			w.WriteHeader(status)
+			if status == http.StatusOK {
+				Expect(sparse.WriteHashes(w, bytes.NewReader(image), int64(len(image)))).To(Succeed())
+			}
+		}))
+		DeferCleanup(server.Close)
+		return server
+	}
+
+	BeforeEach(func() {
+		image = bytes.Repeat([]byte{1, 2, 3}, sparse.HashBlockSize)
+	})
+
+	It("Should get the hashes of the target", func() {
+		server := serveHashes(http.StatusOK)
+		hashes, err := getTargetHashes(server.Client(), server.URL+common.UploadPathSync)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hashes).ToNot(BeNil())
+		Expect(hashes.Size()).To(Equal(int64(len(image))))
+		Expect(hashes.Matches(0, image[:sparse.HashBlockSize])).To(BeTrue())
+	})
+
+	It("Should not get hashes from a target which can not send them", func() {
+		server := serveHashes(http.StatusNotFound)
+		hashes, err := getTargetHashes(server.Client(), server.URL+common.UploadPathSync)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hashes).To(BeNil())
+	})
+
+	It("Should fail if the target fails to send the hashes", func() {
+		server := serveHashes(http.StatusServiceUnavailable)
+		_, err := getTargetHashes(server.Client(), server.URL+common.UploadPathSync)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 func isDirEmpty(dirName string) (bool, error) {
 	f, err := os.Open(dirName)
 	if err != nil {
@@ -29,11 +29,13 @@ fi
 echo "VOLUME_MODE=$VOLUME_MODE"
 echo "MOUNT_POINT=$MOUNT_POINT"
 
-if [ "$VOLUME_MODE" == "block" ]; then
-    UPLOAD_BYTES=$(blockdev --getsize64 $MOUNT_POINT)
-    echo "UPLOAD_BYTES=$UPLOAD_BYTES"
-
-    /usr/bin/cdi-cloner -v=3 -alsologtostderr -content-type blockdevice-clone -upload-bytes $UPLOAD_BYTES -mount $MOUNT_POINT
+if [ "$VOLUME_MODE" == "block" ] && [ "${DELTA_SYNC:-}" == "true" ]; then
+    # Only the blocks which differ from the existing target are sent
+    echo "Syncing the changed blocks of the target"
+    /usr/bin/cdi-cloner -v=3 -alsologtostderr -content-type blockdevice-delta-clone -mount $MOUNT_POINT
+elif [ "$VOLUME_MODE" == "block" ]; then
+    # Only the data of the device is sent, the progress is reported against the bytes which are read
+    /usr/bin/cdi-cloner -v=3 -alsologtostderr -content-type blockdevice-sparse-clone -mount $MOUNT_POINT
 else
     pushd $MOUNT_POINT
     if [ "$PREALLOCATION" == "true" ]; then
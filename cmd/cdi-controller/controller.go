@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"crypto/rsa"
 	"crypto/tls"
 	"flag"
 	"fmt"
@@ -50,9 +49,9 @@ import (
 	dvc "kubevirt.io/containerized-data-importer/pkg/controller/datavolume"
 	"kubevirt.io/containerized-data-importer/pkg/controller/populators"
 	"kubevirt.io/containerized-data-importer/pkg/controller/transfer"
+	"kubevirt.io/containerized-data-importer/pkg/keys"
 	metrics "kubevirt.io/containerized-data-importer/pkg/monitoring/metrics/cdi-controller"
 	"kubevirt.io/containerized-data-importer/pkg/util"
-	"kubevirt.io/containerized-data-importer/pkg/util/cert"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/generator"
 	cryptowatch "kubevirt.io/containerized-data-importer/pkg/util/tls-crypto-watch"
@@ -255,6 +254,9 @@ func start() {
 	}
 	uploadServerCertGenerator := &generator.FetchCertGenerator{Fetcher: uploadServerCAFetcher}
 
+	// the token keys are rotated by the operator, the mounted secret is reread as it changes
+	tokenKeys := keys.NewFileKeySource(common.TokenKeyDir)
+
 	if _, err := controller.NewConfigController(mgr, log, uploadProxyServiceName, configName, installerLabels); err != nil {
 		klog.Errorf("Unable to setup config controller: %v", err)
 		os.Exit(1)
@@ -282,12 +284,12 @@ func start() {
 		os.Exit(1)
 	}
 	if _, err := dvc.NewPvcCloneController(ctx, mgr, log,
-		clonerImage, importerImage, pullPolicy, getTokenPublicKey(), getTokenPrivateKey(), installerLabels); err != nil {
+		clonerImage, importerImage, pullPolicy, tokenKeys, installerLabels); err != nil {
 		klog.Errorf("Unable to setup datavolume pvc clone controller: %v", err)
 		os.Exit(1)
 	}
 	if _, err := dvc.NewSnapshotCloneController(ctx, mgr, log,
-		clonerImage, importerImage, pullPolicy, getTokenPublicKey(), getTokenPrivateKey(), installerLabels); err != nil {
+		clonerImage, importerImage, pullPolicy, tokenKeys, installerLabels); err != nil {
 		klog.Errorf("Unable to setup datavolume snapshot clone controller: %v", err)
 		os.Exit(1)
 	}
@@ -301,7 +303,7 @@ func start() {
 		os.Exit(1)
 	}
 
-	if _, err := controller.NewCloneController(mgr, log, clonerImage, pullPolicy, verbose, uploadClientCertGenerator, uploadServerBundleFetcher, getTokenPublicKey(), installerLabels); err != nil {
+	if _, err := controller.NewCloneController(mgr, log, clonerImage, pullPolicy, verbose, uploadClientCertGenerator, uploadServerBundleFetcher, tokenKeys, installerLabels); err != nil {
 		klog.Errorf("Unable to setup clone controller: %v", err)
 		os.Exit(1)
 	}
@@ -311,6 +313,21 @@ func start() {
 		os.Exit(1)
 	}
 
+	if err := controller.NewUploadTokenRecordsCleaner(mgr, log, namespace); err != nil {
+		klog.Errorf("Unable to setup upload token records cleaner: %v", err)
+		os.Exit(1)
+	}
+
+	if _, err := controller.NewExportController(mgr, log, uploadServerImage, pullPolicy, verbose, uploadServerCertGenerator, uploadClientBundleFetcher, installerLabels); err != nil {
+		klog.Errorf("Unable to setup export controller: %v", err)
+		os.Exit(1)
+	}
+
+	if _, err := controller.NewExportJobController(mgr, log, importerImage, pullPolicy, verbose, installerLabels); err != nil {
+		klog.Errorf("Unable to setup export job controller: %v", err)
+		os.Exit(1)
+	}
+
 	if _, err := transfer.NewObjectTransferController(mgr, log, installerLabels); err != nil {
 		klog.Errorf("Unable to setup transfer controller: %v", err)
 		os.Exit(1)
@@ -324,6 +341,10 @@ func start() {
 		klog.Errorf("Unable to setup datasource controller: %v", err)
 		os.Exit(1)
 	}
+	if _, err := controller.NewUploadDataSourceController(mgr, log, installerLabels); err != nil {
+		klog.Errorf("Unable to setup upload datasource controller: %v", err)
+		os.Exit(1)
+	}
 	// Populator controllers and indexes
 	if err := populators.CreateCommonPopulatorIndexes(mgr); err != nil {
 		klog.Errorf("Unable to create common populator indexes: %v", err)
@@ -337,7 +358,7 @@ func start() {
 		klog.Errorf("Unable to setup upload populator: %v", err)
 		os.Exit(1)
 	}
-	if _, err := populators.NewClonePopulator(ctx, mgr, log, clonerImage, pullPolicy, installerLabels, getTokenPublicKey()); err != nil {
+	if _, err := populators.NewClonePopulator(ctx, mgr, log, clonerImage, pullPolicy, installerLabels, tokenKeys); err != nil {
 		klog.Errorf("Unable to setup clone populator: %v", err)
 		os.Exit(1)
 	}
@@ -399,39 +420,6 @@ func deleteReadyFile() {
 	os.Remove(readyFile)
 }
 
-func getTokenPublicKey() *rsa.PublicKey {
-	keyBytes, err := os.ReadFile(controller.TokenPublicKeyPath)
-	if err != nil {
-		klog.Fatalf("Error reading apiserver public key")
-	}
-
-	key, err := controller.DecodePublicKey(keyBytes)
-	if err != nil {
-		klog.Fatalf("Error decoding public key")
-	}
-
-	return key
-}
-
-func getTokenPrivateKey() *rsa.PrivateKey {
-	bytes, err := os.ReadFile(controller.TokenPrivateKeyPath)
-	if err != nil {
-		klog.Fatalf("Error reading private key")
-	}
-
-	obj, err := cert.ParsePrivateKeyPEM(bytes)
-	if err != nil {
-		klog.Fatalf("Error decoding private key")
-	}
-
-	key, ok := obj.(*rsa.PrivateKey)
-	if !ok {
-		klog.Fatalf("Invalid private key format")
-	}
-
-	return key
-}
-
 // Restricts some types in the cache's ListWatch to specific fields/labels per GVK at the specified object,
 // other types will continue working normally.
 // Note: objects you read once with the controller runtime client are cached.
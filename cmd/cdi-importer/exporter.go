@@ -0,0 +1,75 @@
+package main
+
+// exporter.go implements the export mode of the importer, used by the pods of ExportJobs. The PVC is mounted
+// read-only at the usual importer path and its image is pushed to an S3 bucket or a container registry.
+// This mode is selected by the EXPORTER_TARGET env variable.
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	"kubevirt.io/containerized-data-importer/pkg/importer"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	exportCompleteMessage = "Export Complete"
+)
+
+func handleExport(target string) int {
+	klog.V(1).Infoln("begin export process")
+
+	opts := importer.ExportOptions{
+		Source: common.ImporterWritePath,
+	}
+	if _, err := os.Stat(common.WriteBlockPath); err == nil {
+		opts.Source = common.WriteBlockPath
+	}
+	if _, err := os.Stat(common.ScratchDataDir); err == nil {
+		opts.ScratchDir = common.ScratchDataDir
+	}
+	format, _ := util.ParseEnvVar(common.ExporterFormat, false)
+	opts.Format = cdiv1.ExportJobFormat(format)
+	opts.Compress, _ = strconv.ParseBool(os.Getenv(common.ExporterCompress))
+	opts.Endpoint, _ = util.ParseEnvVar(common.ExporterEndpoint, false)
+	opts.AccessKey, _ = util.ParseEnvVar(common.ImporterAccessKeyID, false)
+	opts.SecKey, _ = util.ParseEnvVar(common.ImporterSecretKey, false)
+	opts.CertDir, _ = util.ParseEnvVar(common.ImporterCertDirVar, false)
+	opts.InsecureTLS, _ = strconv.ParseBool(os.Getenv(common.InsecureTLSVar))
+
+	var digest string
+	var err error
+	switch target {
+	case cc.SourceS3:
+		digest, err = importer.ExportToS3(&opts)
+	case cc.SourceRegistry:
+		digest, err = importer.ExportToRegistry(&opts)
+	default:
+		err = errors.Errorf("unknown export target %s", target)
+	}
+	if err != nil {
+		klog.Errorf("%+v", err)
+		if err := util.WriteTerminationMessage(err.Error()); err != nil {
+			klog.Errorf("%+v", err)
+		}
+		return 1
+	}
+
+	msg := &common.TerminationMessage{
+		Message:  ptr.To(exportCompleteMessage),
+		Checksum: ptr.To(digest),
+	}
+	if err := writeTerminationMessage(msg); err != nil {
+		klog.Errorf("%+v", err)
+		return 1
+	}
+	return 0
+}
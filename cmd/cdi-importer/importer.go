@@ -107,6 +107,13 @@ func main() {
 	prometheusutil.StartPrometheusEndpoint(certsDirectory)
 	klog.V(1).Infoln("Starting importer")
 
+	if target, _ := util.ParseEnvVar(common.ExporterTarget, false); target != "" {
+		if exitCode := handleExport(target); exitCode != 0 {
+			os.Exit(exitCode)
+		}
+		return
+	}
+
 	source, _ := util.ParseEnvVar(common.ImporterSource, false)
 	contentType, _ := util.ParseEnvVar(common.ImporterContentType, false)
 	imageSize, _ := util.ParseEnvVar(common.ImporterImageSize, false)
@@ -190,13 +197,16 @@ func handleImport(
 
 	scratchSpaceRequired := errors.Is(err, importer.ErrRequiresScratchSpace)
 	checksumMismatch := errors.Is(err, importer.ErrChecksumMismatch)
+	signatureVerificationFailed := errors.Is(err, importer.ErrSignatureVerification)
 	if err != nil && !scratchSpaceRequired {
 		klog.Errorf("%+v", err)
-		// Special-case checksum validation failures: exit 0 to avoid kubelet restart loops
+		// Special-case checksum and signature validation failures: exit 0 to avoid kubelet restart loops
 		// and let the controller read termination message to mark fatal.
-		if checksumMismatch {
-			// Remove the downloaded file(s) so we do not leave potentially malicious or corrupt data on the PVC.
-			removeDownloadedFileOnChecksumFailure(contentType, volumeMode)
+		if checksumMismatch || signatureVerificationFailed {
+			if checksumMismatch {
+				// Remove the downloaded file(s) so we do not leave potentially malicious or corrupt data on the PVC.
+				removeDownloadedFileOnChecksumFailure(contentType, volumeMode)
+			}
 			// Write JSON-formatted termination message so parseTerminationMessage can unmarshal it
 			termMsgStruct := &common.TerminationMessage{
 				Message: ptr.To(fmt.Sprintf("Unable to process data: %v", err.Error())),
@@ -288,10 +298,28 @@ func newDataSource(source string, contentType string, volumeMode v1.PersistentVo
 	previousCheckpoint, _ := util.ParseEnvVar(common.ImporterPreviousCheckpoint, false)
 	finalCheckpoint, _ := util.ParseEnvVar(common.ImporterFinalCheckpoint, false)
 	checksum, _ := util.ParseEnvVar(common.ImporterChecksum, false)
+	checksumURL, _ := util.ParseEnvVar(common.ImporterChecksumURL, false)
+	checksumKeyring, _ := util.ParseEnvVar(common.ImporterChecksumKeyring, false)
+	diskPath, _ := util.ParseEnvVar(common.ImporterDiskPath, false)
+	diskIndex := -1
+	if index := os.Getenv(common.ImporterDiskIndex); index != "" {
+		var err error
+		if diskIndex, err = strconv.Atoi(index); err != nil {
+			errorCannotConnectDataSource(err, source)
+		}
+	}
 
 	switch source {
 	case cc.SourceHTTP:
-		ds, err := importer.NewHTTPDataSource(getHTTPEp(ep), acc, sec, certDir, cdiv1.DataVolumeContentType(contentType), checksum, insecureTLS)
+		httpEp := getHTTPEp(ep)
+		if checksum == "" && checksumURL != "" {
+			var err error
+			checksum, err = importer.FetchChecksum(checksumURL, checksumKeyring, httpEp, acc, sec, certDir, insecureTLS)
+			if err != nil {
+				errorCannotConnectDataSource(err, "http")
+			}
+		}
+		ds, err := importer.NewHTTPDataSource(httpEp, acc, sec, certDir, cdiv1.DataVolumeContentType(contentType), checksum, diskPath, diskIndex, insecureTLS)
 		if err != nil {
 			errorCannotConnectDataSource(err, "http")
 		}
@@ -303,20 +331,44 @@ func newDataSource(source string, contentType string, volumeMode v1.PersistentVo
 		}
 		return ds
 	case cc.SourceRegistry:
-		ds := importer.NewRegistryDataSource(ep, acc, sec, registryImageArchitecture, certDir, insecureTLS)
+		signaturePolicy, err := importer.LoadSignaturePolicy(os.Getenv(common.ImporterSignaturePolicy), common.ImporterSignatureDir,
+			os.Getenv(common.ImporterSignatureIssuer), os.Getenv(common.ImporterSignatureSubject))
+		if err != nil {
+			errorCannotConnectDataSource(err, "registry")
+		}
+		ds := importer.NewRegistryDataSource(ep, acc, sec, registryImageArchitecture, certDir, insecureTLS, signaturePolicy,
+			diskPath, diskIndex)
 		return ds
 	case cc.SourceS3:
-		ds, err := importer.NewS3DataSource(ep, acc, sec, certDir)
+		ds, err := importer.NewS3DataSource(ep, acc, sec, certDir, checksum)
 		if err != nil {
 			errorCannotConnectDataSource(err, "s3")
 		}
 		return ds
 	case cc.SourceGCS:
-		ds, err := importer.NewGCSDataSource(ep, keyf)
+		ds, err := importer.NewGCSDataSource(ep, keyf, checksum)
 		if err != nil {
 			errorCannotConnectDataSource(err, "gcs")
 		}
 		return ds
+	case cc.SourceSFTP:
+		ds, err := importer.NewSFTPDataSource(ep, common.ImporterSFTPCredentialDir, common.ImporterSFTPHostKeyDir, checksum)
+		if err != nil {
+			errorCannotConnectDataSource(err, "sftp")
+		}
+		return ds
+	case cc.SourceSMB:
+		ds, err := importer.NewSMBDataSource(ep, acc, sec, checksum)
+		if err != nil {
+			errorCannotConnectDataSource(err, "smb")
+		}
+		return ds
+	case cc.SourceNFS:
+		ds, err := importer.NewNFSDataSource(ep, common.ImporterNFSDir, checksum)
+		if err != nil {
+			errorCannotConnectDataSource(err, "nfs")
+		}
+		return ds
 	case cc.SourceVDDK:
 		ds, err := importer.NewVDDKDataSource(importer.VDDKDataSourceConfig{
 			Endpoint: ep, AccessKey: acc, SecKey: sec, Thumbprint: thumbprint, UUID: uuid,
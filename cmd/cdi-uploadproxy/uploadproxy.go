@@ -15,6 +15,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
 	cdiclient "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/keys"
 	"kubevirt.io/containerized-data-importer/pkg/uploadproxy"
 	"kubevirt.io/containerized-data-importer/pkg/util"
 	certfetcher "kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
@@ -89,11 +91,6 @@ func main() {
 
 	ctx := signals.SetupSignalHandler()
 
-	apiServerPublicKey, err := getAPIServerPublicKey()
-	if err != nil {
-		klog.Fatalf("Unable to get apiserver public key %v\n", errors.WithStack(err))
-	}
-
 	cdiConfigTLSWatcher, err := cryptowatch.NewCdiConfigTLSWatcher(ctx, cdiClient)
 	if err != nil {
 		klog.Fatalf("Unable to create cdiConfigTLSWatcher: %v\n", errors.WithStack(err))
@@ -112,12 +109,13 @@ func main() {
 
 	uploadProxy, err := uploadproxy.NewUploadProxy(defaultHost,
 		defaultPort,
-		apiServerPublicKey,
+		keys.NewFileKeySource(common.TokenKeyDir),
 		cdiConfigTLSWatcher,
 		certWatcher,
 		clientCertFetcher,
 		serverCAFetcher,
-		client)
+		client,
+		namespace)
 	if err != nil {
 		klog.Fatalf("UploadProxy failed to initialize: %v\n", errors.WithStack(err))
 	}
@@ -136,12 +134,3 @@ func main() {
 		klog.Fatalf("TLS server failed: %v\n", errors.WithStack(err))
 	}
 }
-
-func getAPIServerPublicKey() (string, error) {
-	const envName = "APISERVER_PUBLIC_KEY"
-	val, ok := os.LookupEnv(envName)
-	if !ok {
-		return "", errors.Errorf("%s not defined", envName)
-	}
-	return val, nil
-}
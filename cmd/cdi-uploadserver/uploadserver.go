@@ -32,6 +32,7 @@ import (
 	"k8s.io/utils/ptr"
 
 	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/importer"
 	"kubevirt.io/containerized-data-importer/pkg/uploadserver"
 	"kubevirt.io/containerized-data-importer/pkg/util"
 	cryptowatch "kubevirt.io/containerized-data-importer/pkg/util/tls-crypto-watch"
@@ -60,20 +61,27 @@ func main() {
 
 	filesystemOverhead, _ := strconv.ParseFloat(os.Getenv(common.FilesystemOverheadVar), 64)
 	preallocation, _ := strconv.ParseBool(os.Getenv(common.Preallocation))
+	export, _ := strconv.ParseBool(os.Getenv(common.ExportMode))
+	scratchDir := getScratchDir()
+	cloneTransformation := getCloneTransformation()
 
 	config := &uploadserver.Config{
-		BindAddress:        listenAddress,
-		BindPort:           listenPort,
-		Destination:        destination,
-		ServerKeyFile:      os.Getenv("TLS_KEY_FILE"),
-		ServerCertFile:     os.Getenv("TLS_CERT_FILE"),
-		ClientCertFile:     os.Getenv("CLIENT_CERT_FILE"),
-		ClientName:         os.Getenv("CLIENT_NAME"),
-		ImageSize:          os.Getenv(common.UploadImageSize),
-		FilesystemOverhead: filesystemOverhead,
-		Preallocation:      preallocation,
-		CryptoConfig:       cryptoConfig,
-		Deadline:           deadline,
+		BindAddress:         listenAddress,
+		BindPort:            listenPort,
+		Destination:         destination,
+		ServerKeyFile:       os.Getenv("TLS_KEY_FILE"),
+		ServerCertFile:      os.Getenv("TLS_CERT_FILE"),
+		ClientCertFile:      os.Getenv("CLIENT_CERT_FILE"),
+		ClientName:          os.Getenv("CLIENT_NAME"),
+		ImageSize:           os.Getenv(common.UploadImageSize),
+		FilesystemOverhead:  filesystemOverhead,
+		Preallocation:       preallocation,
+		CryptoConfig:        cryptoConfig,
+		Deadline:            deadline,
+		SessionDir:          scratchDir,
+		Export:              export,
+		ScratchDir:          scratchDir,
+		CloneTransformation: cloneTransformation,
 	}
 
 	server := uploadserver.NewUploadServer(config)
@@ -95,6 +103,9 @@ func main() {
 			termMsg.Message = ptr.To("Upload Complete")
 		}
 		termMsg.PreallocationApplied = ptr.To(result.PreallocationApplied)
+		if result.Checksum != "" {
+			termMsg.Checksum = ptr.To(result.Checksum)
+		}
 	} else {
 		termMsg.Message = ptr.To("Deadline Passed")
 		termMsg.DeadlinePassed = ptr.To(true)
@@ -155,6 +166,25 @@ func getDestination() string {
 	return destination
 }
 
+// getScratchDir returns the scratch space directory when it is mounted, clone targets have no scratch space and
+// do not support resumable uploads
+func getScratchDir() string {
+	if _, err := os.Stat(common.ScratchDataDir); err != nil {
+		return ""
+	}
+	return common.ScratchDataDir
+}
+
+// getCloneTransformation returns the changes made to the disk image of a host assisted clone
+func getCloneTransformation() importer.CloneTransformation {
+	resize, _ := strconv.ParseBool(os.Getenv(common.CloneResize))
+	sectorSize, _ := strconv.ParseInt(os.Getenv(common.CloneLogicalSectorSize), 10, 64)
+	return importer.CloneTransformation{
+		Resize:            resize,
+		LogicalSectorSize: sectorSize,
+	}
+}
+
 func getDeadline() *time.Time {
 	dl := os.Getenv("DEADLINE")
 	if dl != "" {
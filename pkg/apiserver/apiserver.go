@@ -21,12 +21,12 @@ package apiserver
 
 import (
 	"context"
-	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"reflect"
 	"sort"
@@ -35,6 +35,7 @@ import (
 	"time"
 
 	restful "github.com/emicklei/go-restful/v3"
+	"github.com/google/uuid"
 	snapclient "github.com/kubernetes-csi/external-snapshotter/client/v6/clientset/versioned"
 	"github.com/pkg/errors"
 
@@ -54,6 +55,7 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/keys"
 	"kubevirt.io/containerized-data-importer/pkg/token"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/checksum"
 	"kubevirt.io/containerized-data-importer/pkg/util/openapi"
 	cryptowatch "kubevirt.io/containerized-data-importer/pkg/util/tls-crypto-watch"
 	cdiversion "kubevirt.io/containerized-data-importer/pkg/version"
@@ -67,6 +69,9 @@ const (
 	//nolint:gosec // This is not a real token
 	uploadTokenGroup = "upload.cdi.kubevirt.io"
 
+	// maxUploadTokenLifetime is the longest lifetime of an upload token requested with an UploadTokenRequest
+	maxUploadTokenLifetime = 24 * time.Hour
+
 	dvValidatePath = "/datavolume-validate"
 
 	dvMutatePath = "/datavolume-mutate"
@@ -109,7 +114,7 @@ type cdiAPIApp struct {
 	snapClient              snapclient.Interface
 	controllerRuntimeClient client.Client
 
-	privateSigningKey *rsa.PrivateKey
+	tokenKeys token.KeySource
 
 	container *restful.Container
 
@@ -225,8 +230,8 @@ func NewCdiAPIServer(bindAddress string,
 	return app, nil
 }
 
-func newUploadTokenGenerator(key *rsa.PrivateKey) token.Generator {
-	return token.NewGenerator(common.UploadTokenIssuer, key, 5*time.Minute)
+func newUploadTokenGenerator(keys token.SigningKeySource) token.Generator {
+	return token.NewGeneratorForKeys(common.UploadTokenIssuer, keys, 5*time.Minute)
 }
 
 func (app *cdiAPIApp) Start(ch <-chan struct{}) error {
@@ -236,14 +241,14 @@ func (app *cdiAPIApp) Start(ch <-chan struct{}) error {
 func (app *cdiAPIApp) getKeysAndCerts() error {
 	namespace := util.GetNamespace()
 
-	privateKey, err := keys.GetOrCreatePrivateKey(app.client, namespace, APISigningKeySecretName, app.installerLabels)
-	if err != nil {
+	if _, err := keys.GetOrCreatePrivateKey(app.client, namespace, APISigningKeySecretName, app.installerLabels); err != nil {
 		return errors.Wrap(err, "Error getting/creating signing key")
 	}
 
-	app.privateSigningKey = privateKey
+	// the operator rotates the signing key, so it is read from the secret rather than kept in memory
+	app.tokenKeys = keys.NewSecretKeySource(app.client, namespace, APISigningKeySecretName)
 
-	app.tokenGenerator = newUploadTokenGenerator(privateKey)
+	app.tokenGenerator = newUploadTokenGenerator(app.tokenKeys)
 
 	return nil
 }
@@ -316,19 +321,27 @@ func (app *cdiAPIApp) startTLS(stopChan <-chan struct{}) error {
 	}
 }
 
-func (app *cdiAPIApp) uploadHandler(request *restful.Request, response *restful.Response) {
+// authorize returns true if the user of the request is allowed to create the token request
+func (app *cdiAPIApp) authorize(request *restful.Request, response *restful.Response) bool {
 	allowed, reason, err := app.authorizer.Authorize(request)
 
 	if err != nil {
 		klog.Error(err)
 		response.WriteHeader(http.StatusInternalServerError)
-		return
+		return false
 	} else if !allowed {
 		klog.Infof("Rejected Request: %s", reason)
 		writeErr := response.WriteErrorString(http.StatusUnauthorized, reason)
 		if writeErr != nil {
-			klog.Error("uploadHandler: failed to send response", err)
+			klog.Error("authorize: failed to send response", err)
 		}
+		return false
+	}
+	return true
+}
+
+func (app *cdiAPIApp) uploadHandler(request *restful.Request, response *restful.Response) {
+	if !app.authorize(request, response) {
 		return
 	}
 
@@ -358,17 +371,103 @@ func (app *cdiAPIApp) uploadHandler(request *restful.Request, response *restful.
 		},
 	}
 
-	tkn, err := app.tokenGenerator.Generate(tokenData)
+	if uploadToken.Spec.Checksum != "" {
+		if _, _, err := checksum.ParseAndValidate(uploadToken.Spec.Checksum); err != nil {
+			writeErrorResponse(response, http.StatusBadRequest, err)
+			return
+		}
+		tokenData.Params = map[string]string{common.UploadChecksumParam: uploadToken.Spec.Checksum}
+	}
+
+	if err := setUploadTokenRestrictions(tokenData, &uploadToken.Spec); err != nil {
+		writeErrorResponse(response, http.StatusBadRequest, err)
+		return
+	}
+
+	// Upload tokens have an ID so they can be revoked
+	tokenData.ID = uuid.NewString()
+
+	tkn, issued, err := app.tokenGenerator.Generate(tokenData)
 	if err != nil {
 		writeErrorResponse(response, http.StatusInternalServerError, err)
 		return
 	}
 
 	uploadToken.Status.Token = tkn
+	uploadToken.Status.TokenID = issued.ID
+	if !issued.Expiry.IsZero() {
+		uploadToken.Status.ExpirationTimestamp = &metav1.Time{Time: issued.Expiry}
+	}
 	response.Header().Set(xContentTypeOptions, nosniff)
 	writeJSONResponse(response, uploadToken)
 }
 
+// setUploadTokenRestrictions sets the lifetime, single use and client restrictions of the upload token request
+func setUploadTokenRestrictions(tokenData *token.Payload, spec *cdiuploadv1.UploadTokenRequestSpec) error {
+	if spec.Lifetime != nil {
+		if spec.Lifetime.Duration <= 0 || spec.Lifetime.Duration > maxUploadTokenLifetime {
+			return errors.Errorf("token lifetime must be positive and at most %s", maxUploadTokenLifetime)
+		}
+		tokenData.Lifetime = spec.Lifetime.Duration
+	}
+	for _, cidr := range spec.ClientCIDRs {
+		if !strings.Contains(cidr, "/") {
+			if net.ParseIP(cidr) == nil {
+				return errors.Errorf("invalid client address %s", cidr)
+			}
+			continue
+		}
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.Wrapf(err, "invalid client CIDR %s", cidr)
+		}
+	}
+	tokenData.ClientCIDRs = spec.ClientCIDRs
+	tokenData.SingleUse = spec.SingleUse
+	return nil
+}
+
+func (app *cdiAPIApp) exportHandler(request *restful.Request, response *restful.Response) {
+	if !app.authorize(request, response) {
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	defer request.Request.Body.Close()
+	body, err := io.ReadAll(request.Request.Body)
+	if err != nil {
+		writeErrorResponse(response, http.StatusBadRequest, err)
+		return
+	}
+
+	exportToken := &cdiuploadv1.ExportTokenRequest{}
+	err = json.Unmarshal(body, exportToken)
+	if err != nil {
+		writeErrorResponse(response, http.StatusBadRequest, err)
+		return
+	}
+
+	tokenData := &token.Payload{
+		Operation: token.OperationExport,
+		Name:      exportToken.Spec.PvcName,
+		Namespace: namespace,
+		Resource: metav1.GroupVersionResource{
+			Group:    "",
+			Version:  "v1",
+			Resource: "persistentvolumeclaims",
+		},
+	}
+
+	tkn, _, err := app.tokenGenerator.Generate(tokenData)
+	if err != nil {
+		writeErrorResponse(response, http.StatusInternalServerError, err)
+		return
+	}
+
+	exportToken.Status.Token = tkn
+	response.Header().Set(xContentTypeOptions, nosniff)
+	writeJSONResponse(response, exportToken)
+}
+
 func uploadTokenAPIGroup() metav1.APIGroup {
 	apiGroup := metav1.APIGroup{
 		Name: uploadTokenGroup,
@@ -399,8 +498,14 @@ func (app *cdiAPIApp) composeUploadTokenAPI() {
 	objKind := "UploadTokenRequest"
 	resource := "uploadtokenrequests"
 
+	exportObjPointer := &cdiuploadv1.ExportTokenRequest{}
+	exportObjExample := reflect.ValueOf(exportObjPointer).Elem().Interface()
+	exportObjKind := "ExportTokenRequest"
+	exportResource := "exporttokenrequests"
+
 	groupPath := fmt.Sprintf("/apis/%s", uploadTokenGroup)
 	createPath := fmt.Sprintf("/namespaces/{namespace:[a-z0-9][a-z0-9\\-]*}/%s", resource)
+	exportCreatePath := fmt.Sprintf("/namespaces/{namespace:[a-z0-9][a-z0-9\\-]*}/%s", exportResource)
 
 	app.container = restful.NewContainer()
 
@@ -427,6 +532,18 @@ func (app *cdiAPIApp) composeUploadTokenAPI() {
 			Returns(http.StatusUnauthorized, "Unauthorized", "").
 			Param(uploadTokenWs.PathParameter("namespace", "Object name and auth scope, such as for teams and projects").Required(true)))
 
+		uploadTokenWs.Route(uploadTokenWs.POST(exportCreatePath).
+			Produces("application/json").
+			Consumes("application/json").
+			Operation("createNamespaced"+exportObjKind+"-"+v).
+			To(app.exportHandler).Reads(exportObjExample).Writes(exportObjExample).
+			Doc("Create an ExportTokenRequest object.").
+			Returns(http.StatusOK, "OK", exportObjExample).
+			Returns(http.StatusCreated, "Created", exportObjExample).
+			Returns(http.StatusAccepted, "Accepted", exportObjExample).
+			Returns(http.StatusUnauthorized, "Unauthorized", "").
+			Param(uploadTokenWs.PathParameter("namespace", "Object name and auth scope, such as for teams and projects").Required(true)))
+
 		uploadTokenWs.Route(uploadTokenWs.GET("/").
 			Produces("application/json").Writes(metav1.APIResourceList{}).
 			To(func(request *restful.Request, response *restful.Response) {
@@ -443,6 +560,15 @@ func (app *cdiAPIApp) composeUploadTokenAPI() {
 					Kind:         "UploadTokenRequest",
 					Verbs:        []string{"create"},
 					ShortNames:   []string{"utr", "utrs"},
+				}, metav1.APIResource{
+					Name:         "exporttokenrequests",
+					SingularName: "exporttokenrequest",
+					Namespaced:   true,
+					Group:        uploadTokenGroup,
+					Version:      uploadTokenVersion,
+					Kind:         "ExportTokenRequest",
+					Verbs:        []string{"create"},
+					ShortNames:   []string{"etr", "etrs"},
 				})
 				response.Header().Set(xContentTypeOptions, nosniff)
 				writeJSONResponse(response, list)
@@ -541,7 +667,7 @@ func (app *cdiAPIApp) createDataVolumeValidatingWebhook() error {
 }
 
 func (app *cdiAPIApp) createDataVolumeMutatingWebhook() error {
-	app.container.ServeMux.Handle(dvMutatePath, webhooks.NewDataVolumeMutatingWebhook(app.client, app.cdiClient, app.privateSigningKey))
+	app.container.ServeMux.Handle(dvMutatePath, webhooks.NewDataVolumeMutatingWebhook(app.client, app.cdiClient, app.tokenKeys))
 	return nil
 }
 
@@ -27,6 +27,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -41,7 +42,9 @@ import (
 	core "k8s.io/client-go/testing"
 
 	cdiuploadv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/upload/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/keys/keystest"
+	"kubevirt.io/containerized-data-importer/pkg/token"
 )
 
 type testAuthorizer struct {
@@ -203,11 +206,14 @@ var _ = Describe("API server tests", func() {
 
 		err := app.getKeysAndCerts()
 		Expect(err).ToNot(HaveOccurred())
+		signingKey, err := app.tokenKeys.SigningKey()
+		Expect(err).ToNot(HaveOccurred())
 
 		actions := []core.Action{}
 		actions = append(actions, signingKeySecretGetAction())
 		actions = append(actions, cdiConfigGetAction())
-		actions = append(actions, signingKeySecretCreateAction(app.privateSigningKey))
+		actions = append(actions, signingKeySecretCreateAction(signingKey.Key.(*rsa.PrivateKey)))
+		actions = append(actions, signingKeySecretGetAction())
 
 		checkActions(actions, client.Actions())
 	})
@@ -236,6 +242,16 @@ var _ = Describe("API server tests", func() {
 					Verbs:        []string{"create"},
 					ShortNames:   []string{"utr", "utrs"},
 				},
+				{
+					Name:         "exporttokenrequests",
+					SingularName: "exporttokenrequest",
+					Namespaced:   true,
+					Group:        "upload.cdi.kubevirt.io",
+					Version:      version,
+					Kind:         "ExportTokenRequest",
+					Verbs:        []string{"create"},
+					ShortNames:   []string{"etr", "etrs"},
+				},
 			},
 		}
 
@@ -346,9 +362,9 @@ var _ = Describe("API server tests", func() {
 		client := k8sfake.NewSimpleClientset(kubeobjects...)
 
 		app := &cdiAPIApp{client: client,
-			privateSigningKey: signingKey,
-			authorizer:        args.authorizer,
-			tokenGenerator:    newUploadTokenGenerator(signingKey)}
+			tokenKeys:      token.NewStaticKeySource(signingKey),
+			authorizer:     args.authorizer,
+			tokenGenerator: newUploadTokenGenerator(token.NewStaticKeySource(signingKey))}
 		app.composeUploadTokenAPI()
 
 		req, err := http.NewRequest(http.MethodPost,
@@ -399,4 +415,144 @@ var _ = Describe("API server tests", func() {
 			http.StatusOK,
 			true),
 	)
+
+	DescribeTable("Get token with checksum", func(checksum string, expectedStatus int) {
+		client := k8sfake.NewSimpleClientset(pvc)
+		app := &cdiAPIApp{client: client,
+			tokenKeys:      token.NewStaticKeySource(signingKey),
+			authorizer:     authorizeSuccess,
+			tokenGenerator: newUploadTokenGenerator(token.NewStaticKeySource(signingKey))}
+		app.composeUploadTokenAPI()
+
+		checksumRequest := request.DeepCopy()
+		checksumRequest.Spec.Checksum = checksum
+		body, err := json.Marshal(checksumRequest)
+		Expect(err).ToNot(HaveOccurred())
+		req, err := http.NewRequest(http.MethodPost,
+			"/apis/upload.cdi.kubevirt.io/v1beta1/namespaces/default/uploadtokenrequests",
+			bytes.NewReader(body))
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		app.container.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(expectedStatus))
+		if expectedStatus != http.StatusOK {
+			return
+		}
+
+		uploadTokenRequest := &cdiuploadv1.UploadTokenRequest{}
+		Expect(json.Unmarshal(rr.Body.Bytes(), uploadTokenRequest)).To(Succeed())
+		payload, err := token.NewValidator(common.UploadTokenIssuer, &signingKey.PublicKey, time.Minute).Validate(uploadTokenRequest.Status.Token)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(payload.Params).To(HaveKeyWithValue(common.UploadChecksumParam, checksum))
+	},
+		Entry("valid checksum", "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", http.StatusOK),
+		Entry("invalid checksum", "sha256:invalid", http.StatusBadRequest),
+	)
+
+	DescribeTable("Get token with restrictions", func(mutate func(*cdiuploadv1.UploadTokenRequestSpec), expectedStatus int) {
+		client := k8sfake.NewSimpleClientset(pvc)
+		app := &cdiAPIApp{client: client,
+			tokenKeys:      token.NewStaticKeySource(signingKey),
+			authorizer:     authorizeSuccess,
+			tokenGenerator: newUploadTokenGenerator(token.NewStaticKeySource(signingKey))}
+		app.composeUploadTokenAPI()
+
+		restrictedRequest := request.DeepCopy()
+		mutate(&restrictedRequest.Spec)
+		body, err := json.Marshal(restrictedRequest)
+		Expect(err).ToNot(HaveOccurred())
+		req, err := http.NewRequest(http.MethodPost,
+			"/apis/upload.cdi.kubevirt.io/v1beta1/namespaces/default/uploadtokenrequests",
+			bytes.NewReader(body))
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		app.container.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(expectedStatus))
+		if expectedStatus != http.StatusOK {
+			return
+		}
+
+		uploadTokenRequest := &cdiuploadv1.UploadTokenRequest{}
+		Expect(json.Unmarshal(rr.Body.Bytes(), uploadTokenRequest)).To(Succeed())
+		payload, err := token.NewValidator(common.UploadTokenIssuer, &signingKey.PublicKey, time.Minute).Validate(uploadTokenRequest.Status.Token)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(uploadTokenRequest.Status.TokenID).To(Equal(payload.ID))
+		Expect(uploadTokenRequest.Status.ExpirationTimestamp.Unix()).To(Equal(payload.Expiry.Unix()))
+		Expect(payload.SingleUse).To(Equal(restrictedRequest.Spec.SingleUse))
+		Expect(payload.ClientCIDRs).To(Equal(restrictedRequest.Spec.ClientCIDRs))
+		lifetime := 5 * time.Minute
+		if restrictedRequest.Spec.Lifetime != nil {
+			lifetime = restrictedRequest.Spec.Lifetime.Duration
+		}
+		Expect(payload.Expiry.Sub(payload.IssuedAt)).To(Equal(lifetime))
+	},
+		Entry("default token", func(spec *cdiuploadv1.UploadTokenRequestSpec) {}, http.StatusOK),
+		Entry("custom lifetime", func(spec *cdiuploadv1.UploadTokenRequestSpec) {
+			spec.Lifetime = &metav1.Duration{Duration: 2 * time.Hour}
+		}, http.StatusOK),
+		Entry("too long lifetime", func(spec *cdiuploadv1.UploadTokenRequestSpec) {
+			spec.Lifetime = &metav1.Duration{Duration: 48 * time.Hour}
+		}, http.StatusBadRequest),
+		Entry("negative lifetime", func(spec *cdiuploadv1.UploadTokenRequestSpec) {
+			spec.Lifetime = &metav1.Duration{Duration: -time.Minute}
+		}, http.StatusBadRequest),
+		Entry("single use and client restrictions", func(spec *cdiuploadv1.UploadTokenRequestSpec) {
+			spec.SingleUse = true
+			spec.ClientCIDRs = []string{"10.0.0.0/8", "192.168.1.10", "fd00::/64"}
+		}, http.StatusOK),
+		Entry("invalid client CIDR", func(spec *cdiuploadv1.UploadTokenRequestSpec) {
+			spec.ClientCIDRs = []string{"10.0.0.0/64"}
+		}, http.StatusBadRequest),
+		Entry("invalid client address", func(spec *cdiuploadv1.UploadTokenRequestSpec) {
+			spec.ClientCIDRs = []string{"myhost"}
+		}, http.StatusBadRequest),
+	)
+
+	DescribeTable("Get export token", func(authorizer *testAuthorizer, expectedStatus int) {
+		client := k8sfake.NewSimpleClientset(pvc)
+		app := &cdiAPIApp{client: client,
+			tokenKeys:      token.NewStaticKeySource(signingKey),
+			authorizer:     authorizer,
+			tokenGenerator: newUploadTokenGenerator(token.NewStaticKeySource(signingKey))}
+		app.composeUploadTokenAPI()
+
+		exportRequest := &cdiuploadv1.ExportTokenRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-token",
+				Namespace: "default",
+			},
+			Spec: cdiuploadv1.ExportTokenRequestSpec{
+				PvcName: "test-pvc",
+			},
+		}
+		body, err := json.Marshal(exportRequest)
+		Expect(err).ToNot(HaveOccurred())
+		req, err := http.NewRequest(http.MethodPost,
+			"/apis/upload.cdi.kubevirt.io/v1beta1/namespaces/default/exporttokenrequests",
+			bytes.NewReader(body))
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		app.container.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(expectedStatus))
+		if expectedStatus != http.StatusOK {
+			return
+		}
+
+		exportTokenRequest := &cdiuploadv1.ExportTokenRequest{}
+		Expect(json.Unmarshal(rr.Body.Bytes(), exportTokenRequest)).To(Succeed())
+		payload, err := token.NewValidator(common.UploadTokenIssuer, &signingKey.PublicKey, time.Minute).Validate(exportTokenRequest.Status.Token)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(payload.Operation).To(Equal(token.OperationExport))
+		Expect(payload.Name).To(Equal("test-pvc"))
+		Expect(payload.Namespace).To(Equal("default"))
+	},
+		Entry("export allowed", authorizeSuccess, http.StatusOK),
+		Entry("authoriser not allowed", &testAuthorizer{allowed: false, reason: "bad person", err: nil}, http.StatusUnauthorized),
+	)
 })
@@ -131,7 +131,7 @@ func (wh *dataVolumeMutatingWebhook) Admit(ar admissionv1.AdmissionReview) *admi
 		},
 	}
 
-	token, err := wh.tokenGenerator.Generate(tokenData)
+	token, _, err := wh.tokenGenerator.Generate(tokenData)
 	if err != nil {
 		return toAdmissionResponseError(err)
 	}
@@ -42,6 +42,7 @@ import (
 	cdicorev1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	cdiclientfake "kubevirt.io/containerized-data-importer/pkg/client/clientset/versioned/fake"
 	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	"kubevirt.io/containerized-data-importer/pkg/token"
 )
 
 var _ = Describe("Mutating DataVolume Webhook", func() {
@@ -359,6 +360,37 @@ var _ = Describe("Mutating DataVolume Webhook", func() {
 			Entry("succeed with empty namespace", ""),
 		)
 
+		DescribeTable("should authorize a remote cluster clone against the kubeconfig secret", func(isAuthorized bool) {
+			dataVolume := newPVCDataVolume("testDV", "noNamespace", "test")
+			dataVolume.Spec.Source.PVC.Cluster = &cdicorev1.CloneSourceCluster{KubeconfigSecretRef: "remote-kubeconfig"}
+			dvBytes, _ := json.Marshal(&dataVolume)
+
+			ar := &admissionv1.AdmissionReview{
+				Request: &admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Resource: metav1.GroupVersionResource{
+						Group:    cdicorev1.SchemeGroupVersion.Group,
+						Version:  cdicorev1.SchemeGroupVersion.Version,
+						Resource: "datavolumes",
+					},
+					Object: runtime.RawExtension{
+						Raw: dvBytes,
+					},
+				},
+			}
+
+			resp := mutateDVs(key, ar, isAuthorized)
+			Expect(resp.Allowed).To(Equal(isAuthorized))
+			if isAuthorized {
+				Expect(resp.Patch).ToNot(BeNil())
+			} else {
+				Expect(resp.Patch).To(BeNil())
+			}
+		},
+			Entry("and accept it if the user can get the secret", true),
+			Entry("and reject it if the user cannot get the secret", false),
+		)
+
 		It("should allow update to DataVolume with sourceRef DataSource reference with clone token", func() {
 			dsRef := &cdicorev1.DataSource{
 				ObjectMeta: metav1.ObjectMeta{
@@ -436,6 +468,6 @@ func mutateDVs(key *rsa.PrivateKey, ar *admissionv1.AdmissionReview, isAuthorize
 	})
 
 	cdiClient := cdiclientfake.NewSimpleClientset(cdiObjects...)
-	wh := NewDataVolumeMutatingWebhook(client, cdiClient, key)
+	wh := NewDataVolumeMutatingWebhook(client, cdiClient, token.NewStaticKeySource(key))
 	return serve(ar, wh)
 }
@@ -146,6 +146,10 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *admission
 		return causes
 	}
 
+	if causes := validateCloneStrategy(spec.CloneStrategy, field); causes != nil {
+		return causes
+	}
+
 	if (spec.Source == nil && spec.SourceRef == nil) || (spec.Source != nil && spec.SourceRef != nil) {
 		causes = append(causes, metav1.StatusCause{
 			Type:    metav1.CauseTypeFieldValueInvalid,
@@ -173,7 +177,7 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *admission
 
 	// Validate import sources
 	if http := spec.Source.HTTP; http != nil {
-		if causes := validateHTTPSource(http, field); causes != nil {
+		if causes := validateHTTPSource(http, spec.ContentType, field); causes != nil {
 			return causes
 		}
 	}
@@ -187,6 +191,21 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *admission
 			return causes
 		}
 	}
+	if sftp := spec.Source.SFTP; sftp != nil {
+		if causes := validateSFTPSource(sftp, field); causes != nil {
+			return causes
+		}
+	}
+	if smb := spec.Source.SMB; smb != nil {
+		if causes := validateSMBSource(smb, field); causes != nil {
+			return causes
+		}
+	}
+	if nfs := spec.Source.NFS; nfs != nil {
+		if causes := validateNFSSource(nfs, field); causes != nil {
+			return causes
+		}
+	}
 	if blank := spec.Source.Blank; blank != nil {
 		if causes := validateBlankSource(spec.ContentType, field); causes != nil {
 			return causes
@@ -207,6 +226,11 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *admission
 			return causes
 		}
 	}
+	if upload := spec.Source.Upload; upload != nil {
+		if causes := validateUploadSource(upload, field); causes != nil {
+			return causes
+		}
+	}
 
 	// Validate clone sources
 	if spec.Source.PVC != nil {
@@ -218,6 +242,29 @@ func (wh *dataVolumeValidatingWebhook) validateDataVolumeSpec(request *admission
 			})
 			return causes
 		}
+		if cluster := spec.Source.PVC.Cluster; cluster != nil && cluster.KubeconfigSecretRef == "" {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s kubeconfigSecretRef is required", field.Child("source", "PVC", "cluster").String()),
+				Field:   field.Child("source", "PVC", "cluster", "kubeconfigSecretRef").String(),
+			})
+			return causes
+		}
+		if cluster := spec.Source.PVC.Cluster; cluster != nil && cluster.ServiceDomain != "" {
+			if err := cc.ValidateServiceDomain(cluster.ServiceDomain); err != nil {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: fmt.Sprintf("%s %v", field.Child("source", "PVC", "cluster", "serviceDomain").String(), err),
+					Field:   field.Child("source", "PVC", "cluster", "serviceDomain").String(),
+				})
+				return causes
+			}
+		}
+		if transformation := spec.Source.PVC.Transformation; transformation != nil {
+			if causes := validateCloneTransformation(transformation, spec.ContentType, field); causes != nil {
+				return causes
+			}
+		}
 	}
 	if spec.Source.Snapshot != nil {
 		if spec.Source.Snapshot.Namespace == "" || spec.Source.Snapshot.Name == "" {
@@ -86,6 +86,103 @@ var _ = Describe("Validating Webhook", func() {
 			Entry("reject DataVolume with special characters in hash", "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7a!", false),
 		)
 
+		DescribeTable("should validate HTTP source checksumURL field", func(checksum, checksumURL string, expected bool) {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com/images/disk.img")
+			dataVolume.Spec.Source.HTTP.Checksum = checksum
+			dataVolume.Spec.Source.HTTP.ChecksumURL = checksumURL
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(expected))
+		},
+			Entry("accept DataVolume with http checksum URL", "", "http://www.example.com/images/SHA256SUMS", true),
+			Entry("accept DataVolume with https checksum URL", "", "https://www.example.com/images/SHA256SUMS", true),
+			Entry("reject DataVolume with checksum URL and checksum", "md5:5d41402abc4b2a76b9719d911017c592", "https://www.example.com/images/SHA256SUMS", false),
+			Entry("reject DataVolume with relative checksum URL", "", "SHA256SUMS", false),
+			Entry("reject DataVolume with unsupported checksum URL scheme", "", "gs://bucket/SHA256SUMS", false),
+		)
+
+		DescribeTable("should validate HTTP source checksumKeyringSecretRef field", func(checksumURL string, expected bool) {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com/images/disk.img")
+			dataVolume.Spec.Source.HTTP.ChecksumURL = checksumURL
+			dataVolume.Spec.Source.HTTP.ChecksumKeyringSecretRef = "keyring"
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(expected))
+		},
+			Entry("accept DataVolume with checksum URL", "https://www.example.com/images/CHECKSUM", true),
+			Entry("reject DataVolume without checksum URL", "", false),
+		)
+
+		DescribeTable("should validate HTTP source disk selection", func(contentType cdiv1.DataVolumeContentType, diskPath *string, diskIndex *int32, expected bool) {
+			dataVolume := newHTTPDataVolume("testDV", "http://www.example.com/images/appliance.ova")
+			dataVolume.Spec.ContentType = contentType
+			dataVolume.Spec.Source.HTTP.DiskPath = diskPath
+			dataVolume.Spec.Source.HTTP.DiskIndex = diskIndex
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(expected))
+		},
+			Entry("accept diskPath", cdiv1.DataVolumeKubeVirt, ptr.To("disks/data.qcow2"), nil, true),
+			Entry("accept diskIndex", cdiv1.DataVolumeContentType(""), nil, ptr.To[int32](1), true),
+			Entry("reject diskPath and diskIndex", cdiv1.DataVolumeKubeVirt, ptr.To("disks/data.qcow2"), ptr.To[int32](1), false),
+			Entry("reject absolute diskPath", cdiv1.DataVolumeKubeVirt, ptr.To("/disks/data.qcow2"), nil, false),
+			Entry("reject negative diskIndex", cdiv1.DataVolumeKubeVirt, nil, ptr.To[int32](-1), false),
+			Entry("reject the archive content type", cdiv1.DataVolumeArchive, ptr.To("disks/data.qcow2"), nil, false),
+		)
+
+		DescribeTable("should validate S3 and GCS source checksum field", func(source cdiv1.DataVolumeSource, expected bool) {
+			dataVolume := newDataVolume("testDV", source, newPVCSpec(pvcSizeDefault))
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(expected))
+		},
+			Entry("accept S3 source with valid checksum",
+				cdiv1.DataVolumeSource{S3: &cdiv1.DataVolumeSourceS3{URL: "http://s3.examples3.com/bucket/disk.img", Checksum: "md5:5d41402abc4b2a76b9719d911017c592"}}, true),
+			Entry("reject S3 source with invalid checksum",
+				cdiv1.DataVolumeSource{S3: &cdiv1.DataVolumeSourceS3{URL: "http://s3.examples3.com/bucket/disk.img", Checksum: "sha256:abc123"}}, false),
+			Entry("accept GCS source with valid checksum",
+				cdiv1.DataVolumeSource{GCS: &cdiv1.DataVolumeSourceGCS{URL: "gs://bucket/disk.img", Checksum: "sha1:2fd4e1c67a2d28fced849ee1bb76e7391b93eb12"}}, true),
+			Entry("reject GCS source with invalid checksum",
+				cdiv1.DataVolumeSource{GCS: &cdiv1.DataVolumeSourceGCS{URL: "gs://bucket/disk.img", Checksum: "crc32c:abc123"}}, false),
+		)
+
+		DescribeTable("should validate file share sources", func(source cdiv1.DataVolumeSource, expected bool) {
+			dataVolume := newDataVolume("testDV", source, newPVCSpec(pvcSizeDefault))
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(expected))
+		},
+			Entry("accept SFTP source",
+				cdiv1.DataVolumeSource{SFTP: &cdiv1.DataVolumeSourceSFTP{URL: "sftp://fileserver/images/disk.img", SecretRef: "creds", HostKeySecretRef: "hostkeys"}}, true),
+			Entry("accept SFTP source with a port and a path relative to the home directory",
+				cdiv1.DataVolumeSource{SFTP: &cdiv1.DataVolumeSourceSFTP{URL: "sftp://fileserver:2222/~/disk.img", SecretRef: "creds", HostKeySecretRef: "hostkeys"}}, true),
+			Entry("reject SFTP source without host key secret",
+				cdiv1.DataVolumeSource{SFTP: &cdiv1.DataVolumeSourceSFTP{URL: "sftp://fileserver/images/disk.img", SecretRef: "creds"}}, false),
+			Entry("reject SFTP source without secret",
+				cdiv1.DataVolumeSource{SFTP: &cdiv1.DataVolumeSourceSFTP{URL: "sftp://fileserver/images/disk.img", HostKeySecretRef: "hostkeys"}}, false),
+			Entry("reject SFTP source with invalid scheme",
+				cdiv1.DataVolumeSource{SFTP: &cdiv1.DataVolumeSourceSFTP{URL: "ssh://fileserver/images/disk.img", SecretRef: "creds", HostKeySecretRef: "hostkeys"}}, false),
+			Entry("reject SFTP source with a directory",
+				cdiv1.DataVolumeSource{SFTP: &cdiv1.DataVolumeSourceSFTP{URL: "sftp://fileserver/images/", SecretRef: "creds", HostKeySecretRef: "hostkeys"}}, false),
+			Entry("reject SFTP source with invalid checksum",
+				cdiv1.DataVolumeSource{SFTP: &cdiv1.DataVolumeSourceSFTP{URL: "sftp://fileserver/images/disk.img", SecretRef: "creds", HostKeySecretRef: "hostkeys", Checksum: "sha256:abc123"}}, false),
+			Entry("accept SMB source", cdiv1.DataVolumeSource{SMB: &cdiv1.DataVolumeSourceSMB{URL: "smb://fileserver/images/disk.qcow2"}}, true),
+			Entry("reject SMB source without file", cdiv1.DataVolumeSource{SMB: &cdiv1.DataVolumeSourceSMB{URL: "smb://fileserver/images"}}, false),
+			Entry("reject SMB source without host", cdiv1.DataVolumeSource{SMB: &cdiv1.DataVolumeSourceSMB{URL: "smb:///images/disk.qcow2"}}, false),
+			Entry("accept NFS source", cdiv1.DataVolumeSource{NFS: &cdiv1.DataVolumeSourceNFS{ClaimName: "images", Path: "vms/disk.img"}}, true),
+			Entry("reject NFS source without claim name", cdiv1.DataVolumeSource{NFS: &cdiv1.DataVolumeSourceNFS{Path: "vms/disk.img"}}, false),
+			Entry("reject NFS source with absolute path", cdiv1.DataVolumeSource{NFS: &cdiv1.DataVolumeSourceNFS{ClaimName: "images", Path: "/vms/disk.img"}}, false),
+			Entry("reject NFS source with path outside the volume", cdiv1.DataVolumeSource{NFS: &cdiv1.DataVolumeSourceNFS{ClaimName: "images", Path: "../disk.img"}}, false),
+			Entry("reject NFS source with a directory", cdiv1.DataVolumeSource{NFS: &cdiv1.DataVolumeSourceNFS{ClaimName: "images", Path: "vms/"}}, false),
+		)
+
+		DescribeTable("should validate upload source with a managed DataSource", func(upload *cdiv1.DataVolumeSourceUpload, expected bool) {
+			dataVolume := newDataVolume("testDV", cdiv1.DataVolumeSource{Upload: upload}, newPVCSpec(pvcSizeDefault))
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(expected))
+		},
+			Entry("accept upload source without DataSource", &cdiv1.DataVolumeSourceUpload{}, true),
+			Entry("accept upload source with DataSource", &cdiv1.DataVolumeSourceUpload{ManagedDataSource: "golden", UploadsToKeep: ptr.To[int32](2)}, true),
+			Entry("reject upload source with invalid DataSource name", &cdiv1.DataVolumeSourceUpload{ManagedDataSource: "Golden.Image"}, false),
+			Entry("reject upload source with too long DataSource name", &cdiv1.DataVolumeSourceUpload{ManagedDataSource: strings.Repeat("a", 64)}, false),
+			Entry("reject upload source without uploads to keep", &cdiv1.DataVolumeSourceUpload{ManagedDataSource: "golden", UploadsToKeep: ptr.To[int32](0)}, false),
+		)
+
 		It("should accept DataVolume with GS source on create", func() {
 			dataVolume := newGCSDataVolume("testDV", "gs://www.example.com")
 			resp := validateDataVolumeCreate(dataVolume)
@@ -195,6 +292,62 @@ var _ = Describe("Validating Webhook", func() {
 			Expect(resp.Allowed).To(BeTrue())
 		})
 
+		DescribeTable("should validate Registry source signatureVerification field", func(url string, pullMethod cdiv1.RegistryPullMethod, sv *cdiv1.RegistrySignatureVerification, expected bool) {
+			dataVolume := newRegistryDataVolume("testDV", url)
+			if pullMethod != "" {
+				dataVolume.Spec.Source.Registry.PullMethod = &pullMethod
+			}
+			dataVolume.Spec.Source.Registry.SignatureVerification = sv
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(expected))
+		},
+			Entry("accept public key in a ConfigMap", "docker://registry:5000/test", cdiv1.RegistryPullMethod(""),
+				&cdiv1.RegistrySignatureVerification{PublicKey: &cdiv1.SignaturePublicKey{ConfigMapRef: ptr.To("cosign-key")}}, true),
+			Entry("accept public key in a Secret", "docker://registry:5000/test", cdiv1.RegistryPullPod,
+				&cdiv1.RegistrySignatureVerification{PublicKey: &cdiv1.SignaturePublicKey{SecretRef: ptr.To("cosign-key")}}, true),
+			Entry("accept keyless", "docker://registry:5000/test", cdiv1.RegistryPullMethod(""),
+				&cdiv1.RegistrySignatureVerification{Keyless: &cdiv1.SignatureKeyless{TrustedRootConfigMapRef: "sigstore-root", Issuer: "https://accounts.example.com", Subject: "signer@example.com"}}, true),
+			Entry("reject public key and keyless", "docker://registry:5000/test", cdiv1.RegistryPullMethod(""),
+				&cdiv1.RegistrySignatureVerification{
+					PublicKey: &cdiv1.SignaturePublicKey{ConfigMapRef: ptr.To("cosign-key")},
+					Keyless:   &cdiv1.SignatureKeyless{TrustedRootConfigMapRef: "sigstore-root", Issuer: "https://accounts.example.com", Subject: "signer@example.com"},
+				}, false),
+			Entry("reject neither public key nor keyless", "docker://registry:5000/test", cdiv1.RegistryPullMethod(""),
+				&cdiv1.RegistrySignatureVerification{}, false),
+			Entry("reject public key in both a ConfigMap and a Secret", "docker://registry:5000/test", cdiv1.RegistryPullMethod(""),
+				&cdiv1.RegistrySignatureVerification{PublicKey: &cdiv1.SignaturePublicKey{ConfigMapRef: ptr.To("cosign-key"), SecretRef: ptr.To("cosign-key")}}, false),
+			Entry("reject public key without a reference", "docker://registry:5000/test", cdiv1.RegistryPullMethod(""),
+				&cdiv1.RegistrySignatureVerification{PublicKey: &cdiv1.SignaturePublicKey{}}, false),
+			Entry("reject keyless without a subject", "docker://registry:5000/test", cdiv1.RegistryPullMethod(""),
+				&cdiv1.RegistrySignatureVerification{Keyless: &cdiv1.SignatureKeyless{TrustedRootConfigMapRef: "sigstore-root", Issuer: "https://accounts.example.com"}}, false),
+			Entry("reject node pull method", "docker://registry:5000/test", cdiv1.RegistryPullNode,
+				&cdiv1.RegistrySignatureVerification{PublicKey: &cdiv1.SignaturePublicKey{ConfigMapRef: ptr.To("cosign-key")}}, false),
+			Entry("reject oci-archive source", "oci-archive://registry:5000/test", cdiv1.RegistryPullMethod(""),
+				&cdiv1.RegistrySignatureVerification{PublicKey: &cdiv1.SignaturePublicKey{ConfigMapRef: ptr.To("cosign-key")}}, false),
+		)
+
+		DescribeTable("should validate Registry source disk selection", func(pullMethod cdiv1.RegistryPullMethod, diskPath *string, diskIndex *int32, expected bool) {
+			dataVolume := newRegistryDataVolume("testDV", "docker://registry:5000/test")
+			if pullMethod != "" {
+				dataVolume.Spec.Source.Registry.PullMethod = &pullMethod
+			}
+			dataVolume.Spec.Source.Registry.DiskPath = diskPath
+			dataVolume.Spec.Source.Registry.DiskIndex = diskIndex
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(expected))
+		},
+			Entry("accept diskPath", cdiv1.RegistryPullMethod(""), ptr.To("data.qcow2"), nil, true),
+			Entry("accept nested diskPath", cdiv1.RegistryPullMethod(""), ptr.To("data/disk.img"), nil, true),
+			Entry("accept diskPath with node pull method", cdiv1.RegistryPullNode, ptr.To("data.qcow2"), nil, true),
+			Entry("accept diskIndex", cdiv1.RegistryPullPod, nil, ptr.To[int32](1), true),
+			Entry("reject diskPath and diskIndex", cdiv1.RegistryPullMethod(""), ptr.To("data.qcow2"), ptr.To[int32](1), false),
+			Entry("reject empty diskPath", cdiv1.RegistryPullMethod(""), ptr.To(""), nil, false),
+			Entry("reject absolute diskPath", cdiv1.RegistryPullMethod(""), ptr.To("/disk/data.qcow2"), nil, false),
+			Entry("reject diskPath outside the disk directory", cdiv1.RegistryPullMethod(""), ptr.To("../etc/passwd"), nil, false),
+			Entry("reject negative diskIndex", cdiv1.RegistryPullMethod(""), nil, ptr.To[int32](-1), false),
+			Entry("reject diskIndex with node pull method", cdiv1.RegistryPullNode, nil, ptr.To[int32](0), false),
+		)
+
 		It("should accept DataVolume with PVC source on create", func() {
 			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
 			pvc := &corev1.PersistentVolumeClaim{
@@ -324,6 +477,76 @@ var _ = Describe("Validating Webhook", func() {
 			Expect(resp.Allowed).To(BeTrue())
 		})
 
+		It("should accept DataVolume with remote cluster PVC source on create", func() {
+			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
+			dataVolume.Spec.Source.PVC.Cluster = &cdiv1.CloneSourceCluster{KubeconfigSecretRef: "remote-kubeconfig"}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(BeTrue())
+		})
+
+		It("should reject DataVolume with remote cluster PVC source without kubeconfig secret on create", func() {
+			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
+			dataVolume.Spec.Source.PVC.Cluster = &cdiv1.CloneSourceCluster{ServiceDomain: "cluster.local"}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(BeFalse())
+		})
+
+		DescribeTable("should validate the service domain of a remote cluster PVC source on create", func(domain string, allowed bool) {
+			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
+			dataVolume.Spec.Source.PVC.Cluster = &cdiv1.CloneSourceCluster{KubeconfigSecretRef: "remote-kubeconfig", ServiceDomain: domain}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(Equal(allowed))
+		},
+			Entry("accept a DNS domain", "clusterset.local", true),
+			Entry("reject a wildcard", "*.example.com", false),
+			Entry("reject a domain with a slash", "example.com/path", false),
+		)
+
+		It("should accept DataVolume with clone strategy policy on create", func() {
+			fallback := cdiv1.CloneFallbackFail
+			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
+			dataVolume.Spec.CloneStrategy = &cdiv1.CloneStrategyPolicy{
+				Strategies: []cdiv1.CDICloneStrategy{cdiv1.CloneStrategyCsiClone, cdiv1.CloneStrategySnapshot},
+				Fallback:   &fallback,
+			}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(BeTrue())
+		})
+
+		DescribeTable("should reject DataVolume with invalid clone strategy policy on create", func(strategies ...cdiv1.CDICloneStrategy) {
+			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
+			dataVolume.Spec.CloneStrategy = &cdiv1.CloneStrategyPolicy{Strategies: strategies}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(BeFalse())
+		},
+			Entry("with unknown strategy", cdiv1.CloneStrategySnapshot, cdiv1.CDICloneStrategy("rsync")),
+			Entry("with duplicate strategy", cdiv1.CloneStrategySnapshot, cdiv1.CloneStrategyCsiClone, cdiv1.CloneStrategySnapshot),
+		)
+
+		It("should accept DataVolume with transformed PVC source on create", func() {
+			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
+			dataVolume.Spec.Source.PVC.Transformation = &cdiv1.CloneTransformation{Resize: true, LogicalSectorSize: ptr.To[int32](4096)}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(BeTrue())
+		})
+
+		It("should reject DataVolume with transformed PVC source and invalid logical sector size on create", func() {
+			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
+			dataVolume.Spec.Source.PVC.Transformation = &cdiv1.CloneTransformation{LogicalSectorSize: ptr.To[int32](1024)}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Message).To(ContainSubstring("Logical sector size 1024"))
+		})
+
+		It("should reject DataVolume with transformed PVC source and archive content type on create", func() {
+			dataVolume := newPVCDataVolume("testDV", "testNamespace", "test")
+			dataVolume.Spec.ContentType = cdiv1.DataVolumeArchive
+			dataVolume.Spec.Source.PVC.Transformation = &cdiv1.CloneTransformation{Resize: true}
+			resp := validateDataVolumeCreate(dataVolume)
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Message).To(ContainSubstring("clone source is transformed"))
+		})
+
 		It("should reject invalid DataVolume source PVC namespace on create", func() {
 			dataVolume := newPVCDataVolume("testDV", "", "test")
 			resp := validateDataVolumeCreate(dataVolume)
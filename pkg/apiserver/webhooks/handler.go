@@ -20,7 +20,6 @@
 package webhooks
 
 import (
-	"crypto/rsa"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -66,8 +65,8 @@ func NewDataVolumeValidatingWebhook(k8sClient kubernetes.Interface, cdiClient cd
 }
 
 // NewDataVolumeMutatingWebhook creates a new DataVolumeMutation webhook
-func NewDataVolumeMutatingWebhook(k8sClient kubernetes.Interface, cdiClient cdiclient.Interface, key *rsa.PrivateKey) http.Handler {
-	generator := newCloneTokenGenerator(key)
+func NewDataVolumeMutatingWebhook(k8sClient kubernetes.Interface, cdiClient cdiclient.Interface, keys token.SigningKeySource) http.Handler {
+	generator := newCloneTokenGenerator(keys)
 	return newAdmissionHandler(&dataVolumeMutatingWebhook{k8sClient: k8sClient, cdiClient: cdiClient, tokenGenerator: generator})
 }
 
@@ -96,8 +95,8 @@ func NewPopulatorValidatingWebhook(k8sClient kubernetes.Interface, cdiClient cdi
 	return newAdmissionHandler(&populatorValidatingWebhook{dataVolumeValidatingWebhook{k8sClient: k8sClient, cdiClient: cdiClient}})
 }
 
-func newCloneTokenGenerator(key *rsa.PrivateKey) token.Generator {
-	return token.NewGenerator(common.CloneTokenIssuer, key, 5*time.Minute)
+func newCloneTokenGenerator(keys token.SigningKeySource) token.Generator {
+	return token.NewGeneratorForKeys(common.CloneTokenIssuer, keys, 5*time.Minute)
 }
 
 func newAdmissionHandler(a Admitter) http.Handler {
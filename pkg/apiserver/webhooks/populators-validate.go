@@ -178,7 +178,7 @@ func (wh *populatorValidatingWebhook) validateVolumeImportSourceSpec(field *k8sf
 
 	// Validate import sources
 	if http := spec.Source.HTTP; http != nil {
-		return validateHTTPSource(http, field)
+		return validateHTTPSource(http, spec.ContentType, field)
 	}
 	if s3 := spec.Source.S3; s3 != nil {
 		return validateS3Source(s3, field)
@@ -186,6 +186,15 @@ func (wh *populatorValidatingWebhook) validateVolumeImportSourceSpec(field *k8sf
 	if gcs := spec.Source.GCS; gcs != nil {
 		return validateGCSSource(gcs, field)
 	}
+	if sftp := spec.Source.SFTP; sftp != nil {
+		return validateSFTPSource(sftp, field)
+	}
+	if smb := spec.Source.SMB; smb != nil {
+		return validateSMBSource(smb, field)
+	}
+	if nfs := spec.Source.NFS; nfs != nil {
+		return validateNFSSource(nfs, field)
+	}
 	if blank := spec.Source.Blank; blank != nil {
 		return validateBlankSource(spec.ContentType, field)
 	}
@@ -22,9 +22,13 @@ package webhooks
 import (
 	"fmt"
 	neturl "net/url"
+	"path"
+	"path/filepath"
 	"reflect"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	field "k8s.io/apimachinery/pkg/util/validation/field"
 
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
@@ -69,6 +73,49 @@ func validateContentType(contentType cdiv1.DataVolumeContentType, field *field.P
 	return nil
 }
 
+func validateCloneStrategy(policy *cdiv1.CloneStrategyPolicy, field *field.Path) []metav1.StatusCause {
+	if policy == nil {
+		return nil
+	}
+	seen := map[cdiv1.CDICloneStrategy]bool{}
+	for i, strategy := range policy.Strategies {
+		if strategy != cdiv1.CloneStrategyHostAssisted && strategy != cdiv1.CloneStrategySnapshot && strategy != cdiv1.CloneStrategyCsiClone {
+			return []metav1.StatusCause{{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("Clone strategy not one of: %s, %s, %s", cdiv1.CloneStrategyHostAssisted, cdiv1.CloneStrategySnapshot, cdiv1.CloneStrategyCsiClone),
+				Field:   field.Child("cloneStrategy", "strategies").Index(i).String(),
+			}}
+		}
+		if seen[strategy] {
+			return []metav1.StatusCause{{
+				Type:    metav1.CauseTypeFieldValueDuplicate,
+				Message: fmt.Sprintf("Duplicate clone strategy %s", strategy),
+				Field:   field.Child("cloneStrategy", "strategies").Index(i).String(),
+			}}
+		}
+		seen[strategy] = true
+	}
+	return nil
+}
+
+func validateCloneTransformation(transformation *cdiv1.CloneTransformation, contentType cdiv1.DataVolumeContentType, field *field.Path) []metav1.StatusCause {
+	if contentType != "" && contentType != cdiv1.DataVolumeKubeVirt {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("ContentType must be %s when the clone source is transformed", cdiv1.DataVolumeKubeVirt),
+			Field:   field.Child("contentType").String(),
+		}}
+	}
+	if sectorSize := transformation.LogicalSectorSize; sectorSize != nil && *sectorSize != 512 && *sectorSize != 4096 {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Logical sector size %d not one of: 512, 4096", *sectorSize),
+			Field:   field.Child("source", "PVC", "transformation", "logicalSectorSize").String(),
+		}}
+	}
+	return nil
+}
+
 func validateBlankSource(contentType cdiv1.DataVolumeContentType, field *field.Path) []metav1.StatusCause {
 	if string(contentType) == string(cdiv1.DataVolumeArchive) {
 		sourceType := field.Child("contentType").String()
@@ -158,28 +205,217 @@ func validateDataVolumeSourceRegistry(sourceRegistry *cdiv1.DataVolumeSourceRegi
 		return causes
 	}
 
+	if sourceRegistry.SignatureVerification != nil {
+		causes = append(causes, validateRegistrySignatureVerification(sourceRegistry, field)...)
+	}
+
+	causes = append(causes, validateRegistryDiskSelection(sourceRegistry, field)...)
+
 	return causes
 }
 
-// if source types are HTTP, Imageio, S3, GCS or VDDK, check if URL is valid
+func validateRegistryDiskSelection(sourceRegistry *cdiv1.DataVolumeSourceRegistry, field *field.Path) []metav1.StatusCause {
+	causes := validateDiskSelection(sourceRegistry.DiskPath, sourceRegistry.DiskIndex, "registry", field.Child("source", "Registry"))
+	if sourceRegistry.DiskIndex != nil && sourceRegistry.PullMethod != nil && *sourceRegistry.PullMethod == cdiv1.RegistryPullNode {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "Source registry diskIndex is not supported with node pull import method",
+			Field:   field.Child("source", "Registry", "diskIndex").String(),
+		})
+	}
+	return causes
+}
 
-func validateHTTPSource(http *cdiv1.DataVolumeSourceHTTP, field *field.Path) []metav1.StatusCause {
+// validateDiskSelection validates the diskPath and diskIndex fields selecting the disk to import from a registry image
+// or an archive
+func validateDiskSelection(diskPath *string, diskIndex *int32, sourceName string, sourceField *field.Path) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	if diskPath != nil && diskIndex != nil {
+		return append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Source %s should have either diskPath or diskIndex", sourceName),
+			Field:   sourceField.String(),
+		})
+	}
+	if diskPath != nil && (*diskPath == "" || !filepath.IsLocal(*diskPath)) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Source %s diskPath %q is not a relative path", sourceName, *diskPath),
+			Field:   sourceField.Child("diskPath").String(),
+		})
+	}
+	if diskIndex != nil && *diskIndex < 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Source %s diskIndex cannot be negative", sourceName),
+			Field:   sourceField.Child("diskIndex").String(),
+		})
+	}
+	return causes
+}
+
+func validateRegistrySignatureVerification(sourceRegistry *cdiv1.DataVolumeSourceRegistry, field *field.Path) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	sv := sourceRegistry.SignatureVerification
+	svField := field.Child("source", "Registry", "signatureVerification")
+
+	if sourceRegistry.PullMethod != nil && *sourceRegistry.PullMethod == cdiv1.RegistryPullNode {
+		return append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "Signature verification is not supported with node pull import method",
+			Field:   svField.String(),
+		})
+	}
+	if sourceRegistry.URL != nil && strings.HasPrefix(*sourceRegistry.URL, cdiv1.RegistrySchemeOci+"://") {
+		return append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "Signature verification is not supported for oci-archive sources",
+			Field:   svField.String(),
+		})
+	}
+	if (sv.PublicKey == nil) == (sv.Keyless == nil) {
+		return append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "Signature verification should have either publicKey or keyless",
+			Field:   svField.String(),
+		})
+	}
+	if pk := sv.PublicKey; pk != nil {
+		hasConfigMap := pk.ConfigMapRef != nil && *pk.ConfigMapRef != ""
+		hasSecret := pk.SecretRef != nil && *pk.SecretRef != ""
+		if hasConfigMap == hasSecret {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "Signature public key should have either configMapRef or secretRef",
+				Field:   svField.Child("publicKey").String(),
+			})
+		}
+	}
+	if kl := sv.Keyless; kl != nil {
+		if kl.TrustedRootConfigMapRef == "" || kl.Issuer == "" || kl.Subject == "" {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "Keyless signature verification requires trustedRootConfigMapRef, issuer and subject",
+				Field:   svField.Child("keyless").String(),
+			})
+		}
+	}
+	return causes
+}
+
+// if source types are HTTP, Imageio, S3, GCS, SFTP, SMB or VDDK, check if URL is valid
+
+func validateHTTPSource(http *cdiv1.DataVolumeSourceHTTP, contentType cdiv1.DataVolumeContentType, field *field.Path) []metav1.StatusCause {
 	var causes []metav1.StatusCause
 	if urlCauses := checkSourceURL(http.URL, "HTTP", field); urlCauses != nil {
 		causes = append(causes, urlCauses...)
 	}
+	if http.DiskPath != nil || http.DiskIndex != nil {
+		if contentType != "" && contentType != cdiv1.DataVolumeKubeVirt {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("ContentType must be %s when Source HTTP selects a disk", cdiv1.DataVolumeKubeVirt),
+				Field:   field.Child("contentType").String(),
+			})
+		}
+		causes = append(causes, validateDiskSelection(http.DiskPath, http.DiskIndex, "HTTP", field.Child("source", "HTTP"))...)
+	}
 	if checksumCauses := validateChecksum(http.Checksum, field, "HTTP"); checksumCauses != nil {
 		causes = append(causes, checksumCauses...)
 	}
+	if checksumURLCauses := validateChecksumURL(http, field); checksumURLCauses != nil {
+		causes = append(causes, checksumURLCauses...)
+	}
 	return causes
 }
 
 func validateS3Source(s3 *cdiv1.DataVolumeSourceS3, field *field.Path) []metav1.StatusCause {
-	return checkSourceURL(s3.URL, "S3", field)
+	var causes []metav1.StatusCause
+	if urlCauses := checkSourceURL(s3.URL, "S3", field); urlCauses != nil {
+		causes = append(causes, urlCauses...)
+	}
+	if checksumCauses := validateChecksum(s3.Checksum, field, "S3"); checksumCauses != nil {
+		causes = append(causes, checksumCauses...)
+	}
+	return causes
 }
 
 func validateGCSSource(gcs *cdiv1.DataVolumeSourceGCS, field *field.Path) []metav1.StatusCause {
-	return checkSourceURL(gcs.URL, "GCS", field)
+	var causes []metav1.StatusCause
+	if urlCauses := checkSourceURL(gcs.URL, "GCS", field); urlCauses != nil {
+		causes = append(causes, urlCauses...)
+	}
+	if checksumCauses := validateChecksum(gcs.Checksum, field, "GCS"); checksumCauses != nil {
+		causes = append(causes, checksumCauses...)
+	}
+	return causes
+}
+
+func validateSFTPSource(sftp *cdiv1.DataVolumeSourceSFTP, field *field.Path) []metav1.StatusCause {
+	if sftp.SecretRef == "" || sftp.HostKeySecretRef == "" {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s source SFTP requires SecretRef and HostKeySecretRef", field.Child("source", "SFTP").String()),
+			Field:   field.Child("source", "SFTP").String(),
+		}}
+	}
+	var causes []metav1.StatusCause
+	if urlCauses := checkFileShareURL(sftp.URL, "sftp", 1, "SFTP", field); urlCauses != nil {
+		causes = append(causes, urlCauses...)
+	}
+	if checksumCauses := validateChecksum(sftp.Checksum, field, "SFTP"); checksumCauses != nil {
+		causes = append(causes, checksumCauses...)
+	}
+	return causes
+}
+
+func validateSMBSource(smb *cdiv1.DataVolumeSourceSMB, field *field.Path) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	// the first path element is the share name
+	if urlCauses := checkFileShareURL(smb.URL, "smb", 2, "SMB", field); urlCauses != nil {
+		causes = append(causes, urlCauses...)
+	}
+	if checksumCauses := validateChecksum(smb.Checksum, field, "SMB"); checksumCauses != nil {
+		causes = append(causes, checksumCauses...)
+	}
+	return causes
+}
+
+func validateNFSSource(nfs *cdiv1.DataVolumeSourceNFS, field *field.Path) []metav1.StatusCause {
+	// the path must stay inside the volume of the claim
+	if nfs.ClaimName == "" || !filepath.IsLocal(nfs.Path) || path.Clean(nfs.Path) != nfs.Path {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s source NFS requires a claim name and the relative path of a file in its volume", field.Child("source", "NFS").String()),
+			Field:   field.Child("source", "NFS").String(),
+		}}
+	}
+	return validateChecksum(nfs.Checksum, field, "NFS")
+}
+
+// checkFileShareURL checks that the URL of a file share source has the expected scheme, a host and
+// a file path of at least minElements elements
+func checkFileShareURL(sourceURL, scheme string, minElements int, sourceType string, field *field.Path) []metav1.StatusCause {
+	errString := ""
+	url, err := neturl.Parse(sourceURL)
+	switch {
+	case sourceURL == "":
+		errString = "source URL is empty"
+	case err != nil || url.Host == "":
+		errString = fmt.Sprintf("Invalid source URL: %s", sourceURL)
+	case url.Scheme != scheme:
+		errString = fmt.Sprintf("Invalid source URL scheme: %s", sourceURL)
+	case len(strings.FieldsFunc(url.Path, func(r rune) bool { return r == '/' })) < minElements || strings.HasSuffix(url.Path, "/"):
+		errString = fmt.Sprintf("Invalid source URL path: %s", sourceURL)
+	}
+	if errString != "" {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s %s", field.Child("source").String(), errString),
+			Field:   field.Child("source", sourceType, "url").String(),
+		}}
+	}
+	return nil
 }
 
 func validateImageIOSource(imageio *cdiv1.DataVolumeSourceImageIO, field *field.Path) []metav1.StatusCause {
@@ -213,6 +449,32 @@ func validateVDDKSource(vddk *cdiv1.DataVolumeSourceVDDK, field *field.Path) []m
 	return checkSourceURL(vddk.URL, "VDDK", field)
 }
 
+func validateUploadSource(upload *cdiv1.DataVolumeSourceUpload, field *field.Path) []metav1.StatusCause {
+	if upload.UploadsToKeep != nil && *upload.UploadsToKeep < 1 {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s must be at least 1", field.Child("source", "upload", "uploadsToKeep").String()),
+			Field:   field.Child("source", "upload", "uploadsToKeep").String(),
+		}}
+	}
+	if upload.ManagedDataSource == "" {
+		return nil
+	}
+	// The DataSource name is used as a label value on the uploads
+	if cause := validateNameLength(upload.ManagedDataSource, validation.DNS1035LabelMaxLength); cause != nil {
+		cause.Field = field.Child("source", "upload", "managedDataSource").String()
+		return []metav1.StatusCause{*cause}
+	}
+	if errs := validation.IsDNS1123Label(upload.ManagedDataSource); len(errs) > 0 {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s is not valid: %s", field.Child("source", "upload", "managedDataSource").String(), strings.Join(errs, ", ")),
+			Field:   field.Child("source", "upload", "managedDataSource").String(),
+		}}
+	}
+	return nil
+}
+
 func checkSourceURL(url, sourceType string, field *field.Path) []metav1.StatusCause {
 	if errString := validateSourceURL(url); errString != "" {
 		return []metav1.StatusCause{{
@@ -253,3 +515,33 @@ func validateChecksum(checksumStr string, field *field.Path, sourceType string)
 	}
 	return nil
 }
+
+func validateChecksumURL(http *cdiv1.DataVolumeSourceHTTP, field *field.Path) []metav1.StatusCause {
+	if http.ChecksumURL == "" {
+		if http.ChecksumKeyringSecretRef != "" {
+			return []metav1.StatusCause{{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "checksumKeyringSecretRef requires checksumURL",
+				Field:   field.Child("source", "HTTP", "checksumKeyringSecretRef").String(),
+			}}
+		}
+		return nil
+	}
+
+	if http.Checksum != "" {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "checksum and checksumURL are mutually exclusive",
+			Field:   field.Child("source", "HTTP", "checksumURL").String(),
+		}}
+	}
+	url, err := neturl.ParseRequestURI(http.ChecksumURL)
+	if err != nil || (url.Scheme != "http" && url.Scheme != "https") {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Invalid checksum URL: %s", http.ChecksumURL),
+			Field:   field.Child("source", "HTTP", "checksumURL").String(),
+		}}
+	}
+	return nil
+}
@@ -33,6 +33,7 @@ type CdiV1beta1Interface interface {
 	DataImportCronsGetter
 	DataSourcesGetter
 	DataVolumesGetter
+	ExportJobsGetter
 	ObjectTransfersGetter
 	StorageProfilesGetter
 	VolumeCloneSourcesGetter
@@ -65,6 +66,10 @@ func (c *CdiV1beta1Client) DataVolumes(namespace string) DataVolumeInterface {
 	return newDataVolumes(c, namespace)
 }
 
+func (c *CdiV1beta1Client) ExportJobs(namespace string) ExportJobInterface {
+	return newExportJobs(c, namespace)
+}
+
 func (c *CdiV1beta1Client) ObjectTransfers() ObjectTransferInterface {
 	return newObjectTransfers(c)
 }
@@ -48,6 +48,10 @@ func (c *FakeCdiV1beta1) DataVolumes(namespace string) v1beta1.DataVolumeInterfa
 	return &FakeDataVolumes{c, namespace}
 }
 
+func (c *FakeCdiV1beta1) ExportJobs(namespace string) v1beta1.ExportJobInterface {
+	return &FakeExportJobs{c, namespace}
+}
+
 func (c *FakeCdiV1beta1) ObjectTransfers() v1beta1.ObjectTransferInterface {
 	return &FakeObjectTransfers{c}
 }
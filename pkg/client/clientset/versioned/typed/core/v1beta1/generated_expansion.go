@@ -28,6 +28,8 @@ type DataSourceExpansion interface{}
 
 type DataVolumeExpansion interface{}
 
+type ExportJobExpansion interface{}
+
 type ObjectTransferExpansion interface{}
 
 type StorageProfileExpansion interface{}
@@ -28,6 +28,10 @@ type FakeUploadV1beta1 struct {
 	*testing.Fake
 }
 
+func (c *FakeUploadV1beta1) ExportTokenRequests(namespace string) v1beta1.ExportTokenRequestInterface {
+	return &FakeExportTokenRequests{c, namespace}
+}
+
 func (c *FakeUploadV1beta1) UploadTokenRequests(namespace string) v1beta1.UploadTokenRequestInterface {
 	return &FakeUploadTokenRequests{c, namespace}
 }
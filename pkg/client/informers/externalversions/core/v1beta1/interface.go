@@ -34,6 +34,8 @@ type Interface interface {
 	DataSources() DataSourceInformer
 	// DataVolumes returns a DataVolumeInformer.
 	DataVolumes() DataVolumeInformer
+	// ExportJobs returns a ExportJobInformer.
+	ExportJobs() ExportJobInformer
 	// ObjectTransfers returns a ObjectTransferInformer.
 	ObjectTransfers() ObjectTransferInformer
 	// StorageProfiles returns a StorageProfileInformer.
@@ -82,6 +84,11 @@ func (v *version) DataVolumes() DataVolumeInformer {
 	return &dataVolumeInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// ExportJobs returns a ExportJobInformer.
+func (v *version) ExportJobs() ExportJobInformer {
+	return &exportJobInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // ObjectTransfers returns a ObjectTransferInformer.
 func (v *version) ObjectTransfers() ObjectTransferInformer {
 	return &objectTransferInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
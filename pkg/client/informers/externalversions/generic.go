@@ -70,6 +70,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().DataSources().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("datavolumes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().DataVolumes().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("exportjobs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().ExportJobs().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("objecttransfers"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cdi().V1beta1().ObjectTransfers().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("storageprofiles"):
@@ -88,6 +90,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Forklift().V1beta1().OvirtVolumePopulators().Informer()}, nil
 
 		// Group=upload.cdi.kubevirt.io, Version=v1beta1
+	case uploadv1beta1.SchemeGroupVersion.WithResource("exporttokenrequests"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Upload().V1beta1().ExportTokenRequests().Informer()}, nil
 	case uploadv1beta1.SchemeGroupVersion.WithResource("uploadtokenrequests"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Upload().V1beta1().UploadTokenRequests().Informer()}, nil
 
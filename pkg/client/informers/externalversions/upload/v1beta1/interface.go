@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// ExportTokenRequests returns a ExportTokenRequestInformer.
+	ExportTokenRequests() ExportTokenRequestInformer
 	// UploadTokenRequests returns a UploadTokenRequestInformer.
 	UploadTokenRequests() UploadTokenRequestInformer
 }
@@ -39,6 +41,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// ExportTokenRequests returns a ExportTokenRequestInformer.
+func (v *version) ExportTokenRequests() ExportTokenRequestInformer {
+	return &exportTokenRequestInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // UploadTokenRequests returns a UploadTokenRequestInformer.
 func (v *version) UploadTokenRequests() UploadTokenRequestInformer {
 	return &uploadTokenRequestInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -50,6 +50,14 @@ type DataVolumeListerExpansion interface{}
 // DataVolumeNamespaceLister.
 type DataVolumeNamespaceListerExpansion interface{}
 
+// ExportJobListerExpansion allows custom methods to be added to
+// ExportJobLister.
+type ExportJobListerExpansion interface{}
+
+// ExportJobNamespaceListerExpansion allows custom methods to be added to
+// ExportJobNamespaceLister.
+type ExportJobNamespaceListerExpansion interface{}
+
 // ObjectTransferListerExpansion allows custom methods to be added to
 // ObjectTransferLister.
 type ObjectTransferListerExpansion interface{}
@@ -18,6 +18,14 @@ limitations under the License.
 
 package v1beta1
 
+// ExportTokenRequestListerExpansion allows custom methods to be added to
+// ExportTokenRequestLister.
+type ExportTokenRequestListerExpansion interface{}
+
+// ExportTokenRequestNamespaceListerExpansion allows custom methods to be added to
+// ExportTokenRequestNamespaceLister.
+type ExportTokenRequestNamespaceListerExpansion interface{}
+
 // UploadTokenRequestListerExpansion allows custom methods to be added to
 // UploadTokenRequestLister.
 type UploadTokenRequestListerExpansion interface{}
@@ -63,6 +63,8 @@ const (
 
 	// UploadTargetLabel has the UID of upload target PVC
 	UploadTargetLabel = CDIComponentLabel + "/uploadTarget"
+	// UploadDataSourceLabel has the name of the DataSource managed by the upload of the labeled resource
+	UploadDataSourceLabel = CDIComponentLabel + "/uploadDataSource"
 
 	// DataImportCronLabel has the name of the DataImportCron responsible for the labeled resource
 	DataImportCronLabel = CDIComponentLabel + "/dataImportCron"
@@ -94,6 +96,9 @@ const (
 	ImporterDataDir = "/data"
 	// ScratchDataDir provides a constant for the controller pkg to use as a hardcoded path to where scratch space is located.
 	ScratchDataDir = "/scratch"
+	// TokenKeyDir is where the secret containing the keys signing and verifying tokens is mounted
+	//nolint:gosec // This is a path, not the key itself
+	TokenKeyDir = "/var/run/cdi/token/keys"
 	// ImporterCertDir is where the configmap containing certs will be mounted
 	ImporterCertDir = "/certs"
 	// DefaultPullPolicy imports k8s "IfNotPresent" string for the import_controller_gingko_test and the cdi-controller executable
@@ -104,6 +109,18 @@ const (
 	ImportProxyConfigMapKey = "ca.crt"
 	// ImporterProxyCertDir is where the configmap containing proxy certs will be mounted
 	ImporterProxyCertDir = "/proxycerts/"
+	// ImporterSignatureDir is where the configmap or secret containing the image signature verification keys will be mounted
+	ImporterSignatureDir = "/signature"
+	// ImporterChecksumKeyringDir is where the secret containing the keyring verifying the checksum file will be mounted
+	ImporterChecksumKeyringDir = "/checksum-keyring"
+	// ChecksumKeyringFile is the key of the armored PGP public keys in the checksum keyring secret
+	ChecksumKeyringFile = "keyring.asc"
+	// ImporterSFTPCredentialDir is where the secret containing the SFTP credentials will be mounted
+	ImporterSFTPCredentialDir = "/sftp/credentials"
+	// ImporterSFTPHostKeyDir is where the secret containing the SFTP server host keys will be mounted
+	ImporterSFTPHostKeyDir = "/sftp/hostkeys"
+	// ImporterNFSDir is where the volume of the NFS export containing the imported file will be mounted
+	ImporterNFSDir = "/nfs"
 
 	// PullPolicy provides a constant to capture our env variable "PULL_POLICY" (only used by cmd/cdi-controller/controller.go)
 	PullPolicy = "PULL_POLICY"
@@ -149,12 +166,32 @@ const (
 	ImporterFinalCheckpoint = "IMPORTER_FINAL_CHECKPOINT"
 	// ImporterChecksum provides a constant to capture our env variable "IMPORTER_CHECKSUM"
 	ImporterChecksum = "IMPORTER_CHECKSUM"
+	// ImporterChecksumURL provides a constant to capture our env variable "IMPORTER_CHECKSUM_URL"
+	ImporterChecksumURL = "IMPORTER_CHECKSUM_URL"
+	// ImporterChecksumKeyring provides a constant to capture our env variable "IMPORTER_CHECKSUM_KEYRING"
+	ImporterChecksumKeyring = "IMPORTER_CHECKSUM_KEYRING"
+	// ImporterSignaturePolicy provides a constant to capture our env variable "IMPORTER_SIGNATURE_POLICY"
+	ImporterSignaturePolicy = "IMPORTER_SIGNATURE_POLICY"
+	// ImporterSignatureIssuer provides a constant to capture our env variable "IMPORTER_SIGNATURE_ISSUER"
+	ImporterSignatureIssuer = "IMPORTER_SIGNATURE_ISSUER"
+	// ImporterSignatureSubject provides a constant to capture our env variable "IMPORTER_SIGNATURE_SUBJECT"
+	ImporterSignatureSubject = "IMPORTER_SIGNATURE_SUBJECT"
+	// SignaturePolicyPublicKey is the value of "IMPORTER_SIGNATURE_POLICY" to verify image signatures with a public key
+	SignaturePolicyPublicKey = "publicKey"
+	// SignaturePolicyKeyless is the value of "IMPORTER_SIGNATURE_POLICY" to verify keyless image signatures
+	SignaturePolicyKeyless = "keyless"
 	// CacheMode provides a constant to capture our env variable "CACHE_MODE"
 	CacheMode = "CACHE_MODE"
 	// CacheModeTryNone provides a constant to capture our env variable value for "CACHE_MODE" that tries O_DIRECT writing if target supports it
 	CacheModeTryNone = "TRYNONE"
 	// Preallocation provides a constant to capture out env variable "PREALLOCATION"
 	Preallocation = "PREALLOCATION"
+	// DeltaSync provides a constant to capture our env variable "DELTA_SYNC"
+	DeltaSync = "DELTA_SYNC"
+	// CloneResize provides a constant to capture our env variable "CLONE_RESIZE"
+	CloneResize = "CLONE_RESIZE"
+	// CloneLogicalSectorSize provides a constant to capture our env variable "CLONE_LOGICAL_SECTOR_SIZE"
+	CloneLogicalSectorSize = "CLONE_LOGICAL_SECTOR_SIZE"
 	// ImportProxyHTTP provides a constant to capture our env variable "http_proxy"
 	ImportProxyHTTP = "http_proxy"
 	// ImportProxyHTTPS provides a constant to capture our env variable "https_proxy"
@@ -173,6 +210,10 @@ const (
 	ImporterSecretExtraHeadersDir = "/extraheaders"
 	// ImporterRegistryImageArchitecture provides a constant to capture our env variable "IMPORTER_REGISTRY_IMAGE_ARCHITECTURE"
 	ImporterRegistryImageArchitecture = "IMPORTER_REGISTRY_IMAGE_ARCHITECTURE"
+	// ImporterDiskPath provides a constant to capture our env variable "IMPORTER_DISK_PATH"
+	ImporterDiskPath = "IMPORTER_DISK_PATH"
+	// ImporterDiskIndex provides a constant to capture our env variable "IMPORTER_DISK_INDEX"
+	ImporterDiskIndex = "IMPORTER_DISK_INDEX"
 
 	// ImporterGoogleCredentialFileVar provides a constant to capture our env variable "GOOGLE_APPLICATION_CREDENTIALS"
 	//nolint:gosec // This is not a real credential
@@ -183,6 +224,18 @@ const (
 	//nolint:gosec // This is not the credential itself
 	ImporterGoogleCredentialFile = "/google/credentials.json"
 
+	// ExporterTarget provides a constant to capture our env variable "EXPORTER_TARGET", the importer exports the PVC
+	// to the target instead of importing when it is set
+	ExporterTarget = "EXPORTER_TARGET"
+	// ExporterEndpoint provides a constant to capture our env variable "EXPORTER_ENDPOINT"
+	ExporterEndpoint = "EXPORTER_ENDPOINT"
+	// ExporterFormat provides a constant to capture our env variable "EXPORTER_FORMAT"
+	ExporterFormat = "EXPORTER_FORMAT"
+	// ExporterCompress provides a constant to capture our env variable "EXPORTER_COMPRESS"
+	ExporterCompress = "EXPORTER_COMPRESS"
+	// ExportJobPodName provides a constant to use as a prefix for the pods of ExportJobs (controller only)
+	ExportJobPodName = "cdi-export-job"
+
 	// CloningLabelValue provides a constant to use as a label value for pod affinity (controller pkg only)
 	CloningLabelValue = "host-assisted-cloning"
 	// CloningTopologyKey  (controller pkg only)
@@ -220,6 +273,12 @@ const (
 	// UploadImageSize provides a constant to capture our env variable "UPLOAD_IMAGE_SIZE"
 	UploadImageSize = "UPLOAD_IMAGE_SIZE"
 
+	// ExportPodName (controller pkg only)
+	ExportPodName = "cdi-export"
+	// ExportMode provides a constant to capture our env variable "EXPORT_MODE", set to run the upload server as an
+	// export server
+	ExportMode = "EXPORT_MODE"
+
 	// FilesystemOverheadVar provides a constant to capture our env variable "FILESYSTEM_OVERHEAD"
 	FilesystemOverheadVar = "FILESYSTEM_OVERHEAD"
 	// DefaultGlobalOverhead is the amount of space reserved on Filesystem volumes by default
@@ -235,6 +294,10 @@ const (
 	KeyAccess = "accessKeyId"
 	// KeySecret provides a constant to the secretKey label using in controller pkg and transport_test.go
 	KeySecret = "secretKey"
+	// KeySSHPrivateKey provides a constant to the ssh-privatekey label of the SFTP credentials secret
+	KeySSHPrivateKey = v1.SSHAuthPrivateKey
+	// KeyKnownHosts provides a constant to the known_hosts label of the SFTP host key secret
+	KeyKnownHosts = "known_hosts"
 
 	// DefaultResyncPeriod sets a 10 minute resync period, used in the controller pkg and the controller cmd executable
 	DefaultResyncPeriod = 10 * time.Minute
@@ -244,6 +307,16 @@ const (
 
 	// UploadTokenIssuer is the JWT issuer of upload tokens
 	UploadTokenIssuer = "cdi-apiserver"
+	// UploadTokensConfigMap is the config map in the CDI namespace recording the revoked upload token IDs
+	UploadTokensConfigMap = "cdi-upload-tokens"
+	// UsedUploadTokenPrefix is the name prefix of the config maps in the CDI namespace recording a used single use upload token
+	UsedUploadTokenPrefix = "cdi-upload-token-"
+	// UsedUploadTokenLabel labels the config maps recording a used single use upload token
+	UsedUploadTokenLabel = CDIComponentLabel + "/usedUploadToken"
+	// UsedUploadTokenIDKey is the key of the token ID in a used upload token record
+	UsedUploadTokenIDKey = "id"
+	// UsedUploadTokenExpiryKey is the key of the token expiry, in seconds since the epoch, in a used upload token record
+	UsedUploadTokenExpiryKey = "expiry"
 
 	// CloneTokenIssuer is the JWT issuer for clone tokens
 	CloneTokenIssuer = "cdi-apiserver"
@@ -278,12 +351,34 @@ const (
 	// UploadContentTypeHeader is the header upload clients may use to set the content type explicitly
 	UploadContentTypeHeader = "x-cdi-content-type"
 
+	// UploadChecksumHeader is the header the upload proxy passes the checksum of the upload token in, in the
+	// "algorithm:hash" format
+	UploadChecksumHeader = "x-cdi-checksum"
+
+	// UploadArchiveManifestHeader is the header archive upload clients may use to set the path of the manifest in the
+	// archive, a checksum file listing the checksums of all the files of the archive
+	UploadArchiveManifestHeader = "x-cdi-archive-manifest"
+
+	// UploadArchivePreserveHeader is the header archive upload clients may use to select the attributes of the archive
+	// entries applied to the extracted files, a comma separated list of "permissions", "ownership" and "xattrs"
+	UploadArchivePreserveHeader = "x-cdi-archive-preserve"
+
+	// UploadChecksumParam is the upload token parameter holding the expected checksum of the upload
+	UploadChecksumParam = "checksum"
+
 	// FilesystemCloneContentType is the content type when cloning a filesystem
 	FilesystemCloneContentType = "filesystem-clone"
 
 	// BlockdeviceClone is the content type when cloning a block device
 	BlockdeviceClone = "blockdevice-clone"
 
+	// BlockdeviceSparseClone is the content type when cloning the data of a block device as a sparse stream
+	BlockdeviceSparseClone = "blockdevice-sparse-clone"
+
+	// BlockdeviceDeltaClone is the content type when cloning the blocks of a block device which differ from the
+	// existing target as a sparse stream, the blocks missing from the stream are unchanged
+	BlockdeviceDeltaClone = "blockdevice-delta-clone"
+
 	// UploadPathSync is the path to POST CDI uploads
 	UploadPathSync = "/v1beta1/upload"
 
@@ -302,6 +397,46 @@ const (
 	// UploadFormAsync is the path to POST CDI uploads as form data in async mode
 	UploadFormAsync = "/v1beta1/upload-form-async"
 
+	// UploadPathResumable is the path to POST to create a resumable upload session, the chunks are sent to the
+	// session URL returned in the Location header
+	UploadPathResumable = "/v1beta1/upload-resumable"
+
+	// UploadOffsetHeader is the header holding the offset of a resumable upload
+	UploadOffsetHeader = "Upload-Offset"
+
+	// UploadLengthHeader is the header holding the total size of a resumable upload
+	UploadLengthHeader = "Upload-Length"
+
+	// UploadPathRange is the path to PUT the ranges of a raw image uploaded over concurrent connections
+	UploadPathRange = "/v1beta1/upload-range"
+
+	// UploadPathStatus is the path to GET the status of an upload
+	UploadPathStatus = "/v1beta1/upload-status"
+
+	// UploadPathHashes is the path to GET the hashes of the blocks of the existing target of a delta clone
+	UploadPathHashes = "/v1beta1/upload-hashes"
+
+	// ExportPath is the path to GET the contents of an exported PVC
+	ExportPath = "/v1beta1/export"
+
+	// ExportFormatParam is the query parameter selecting the format of an export
+	ExportFormatParam = "format"
+
+	// ExportSparseParam is the query parameter requesting a sparse export, only the data of the image is sent
+	ExportSparseParam = "sparse"
+
+	// ExportFormatRaw exports the image as a raw disk image
+	ExportFormatRaw = "raw"
+
+	// ExportFormatGzip exports the image as a gzip compressed raw disk image
+	ExportFormatGzip = "gzip"
+
+	// ExportFormatQcow2 exports the image as a qcow2 disk image
+	ExportFormatQcow2 = "qcow2"
+
+	// ExportLengthHeader is the header holding the size of the image of a sparse export
+	ExportLengthHeader = "Export-Length"
+
 	// PreallocationApplied is a string inserted into importer's/uploader's exit message
 	PreallocationApplied = "Preallocation applied"
 
@@ -341,7 +476,7 @@ const (
 
 // ProxyPaths are all supported paths
 var ProxyPaths = append(
-	append(SyncUploadPaths, AsyncUploadPaths...),
+	append(append(append(SyncUploadPaths, AsyncUploadPaths...), ResumableUploadPaths...), RangeUploadPaths...),
 	append(SyncUploadFormPaths, AsyncUploadFormPaths...)...,
 )
 
@@ -363,6 +498,18 @@ var ArchiveUploadPaths = []string{
 	UploadArchiveAlphaPath,
 }
 
+// ResumableUploadPaths are the paths of resumable uploads, the path creating sessions and the subtree of the
+// session URLs
+var ResumableUploadPaths = []string{
+	UploadPathResumable,
+	UploadPathResumable + "/",
+}
+
+// RangeUploadPaths are paths to PUT the ranges of raw images
+var RangeUploadPaths = []string{
+	UploadPathRange,
+}
+
 // SyncUploadFormPaths are paths to POST CDI uploads as form data
 var SyncUploadFormPaths = []string{
 	UploadFormSync,
@@ -389,6 +536,7 @@ type TerminationMessage struct {
 	VddkInfo             *VddkInfo         `json:"vddkInfo,omitempty"`
 	Labels               map[string]string `json:"labels,omitempty"`
 	Message              *string           `json:"message,omitempty"`
+	Checksum             *string           `json:"checksum,omitempty"`
 }
 
 func (it *TerminationMessage) String() (string, error) {
@@ -405,6 +553,39 @@ func (it *TerminationMessage) String() (string, error) {
 	return string(msg), nil
 }
 
+// UploadPhase is the phase of an upload
+type UploadPhase string
+
+const (
+	// UploadPhasePending means the upload server is waiting for the upload to start
+	UploadPhasePending UploadPhase = "Pending"
+	// UploadPhaseUploading means the upload server is receiving the data
+	UploadPhaseUploading UploadPhase = "Uploading"
+	// UploadPhaseProcessing means all the data was received and the image is being processed
+	UploadPhaseProcessing UploadPhase = "Processing"
+	// UploadPhaseSucceeded means the image was written to the PVC
+	UploadPhaseSucceeded UploadPhase = "Succeeded"
+	// UploadPhaseFailed means the upload failed, it may be retried unless the upload pod failed
+	UploadPhaseFailed UploadPhase = "Failed"
+)
+
+// UploadStatus contains data to be serialized and used as the body of responses to the upload status endpoint.
+type UploadStatus struct {
+	Phase UploadPhase `json:"phase"`
+	// BytesReceived is the amount of data received by the upload server
+	BytesReceived int64 `json:"bytesReceived"`
+	// BytesTotal is the size of the upload, if it is known
+	BytesTotal *int64 `json:"bytesTotal,omitempty"`
+	// ProcessingPhase is the phase of the processing of the image by the upload server
+	ProcessingPhase string `json:"processingPhase,omitempty"`
+	// Checksum is the checksum of the uploaded data, if it was validated
+	Checksum string `json:"checksum,omitempty"`
+	// Message describes why the upload failed
+	Message string `json:"message,omitempty"`
+	// Restarts is the number of times the upload server restarted, a processing failure restarts it
+	Restarts int `json:"restarts,omitempty"`
+}
+
 // ServerInfo contains data to be serialized and used as the body of responses to the info endpoint of the containerimage-server.
 type ServerInfo struct {
 	Env []string `json:"env,omitempty"`
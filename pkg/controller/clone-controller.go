@@ -2,7 +2,6 @@ package controller
 
 import (
 	"context"
-	"crypto/rsa"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -12,6 +11,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -34,6 +34,7 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
 	"kubevirt.io/containerized-data-importer/pkg/operator"
+	"kubevirt.io/containerized-data-importer/pkg/token"
 	"kubevirt.io/containerized-data-importer/pkg/util"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/generator"
@@ -41,21 +42,18 @@ import (
 )
 
 const (
-	// TokenKeyDir is the path to the apiserver public key dir
-	//nolint:gosec // This is a path, not the key itself
-	TokenKeyDir = "/var/run/cdi/token/keys"
-
-	// TokenPublicKeyPath is the path to the apiserver public key
-	TokenPublicKeyPath = TokenKeyDir + "/id_rsa.pub"
-
-	// TokenPrivateKeyPath is the path to the apiserver private key
-	TokenPrivateKeyPath = TokenKeyDir + "/id_rsa"
-
 	// CloneSucceededPVC provides a const to indicate a clone to the PVC succeeded
 	CloneSucceededPVC = "CloneSucceeded"
 
 	cloneSourcePodFinalizer = "cdi.kubevirt.io/cloneSource"
 
+	// the pods of remote clusters are not watched, they are polled at an interval growing with the time waited
+	remotePollMinInterval = 2 * time.Second
+	remotePollMaxInterval = time.Minute
+
+	// remoteCleanupTimeout is how long the cleanup of a deleted target waits for the remote cluster of its source
+	remoteCleanupTimeout = 10 * time.Minute
+
 	hostAssistedCloneSource = "cdi.kubevirt.io/hostAssistedSourcePodCloneSource"
 )
 
@@ -72,6 +70,7 @@ type CloneReconciler struct {
 	verbose             string
 	pullPolicy          string
 	installerLabels     map[string]string
+	sourceClient        cc.CloneSourceClientFunc
 }
 
 // NewCloneController creates a new instance of the config controller.
@@ -81,13 +80,13 @@ func NewCloneController(mgr manager.Manager,
 	verbose string,
 	clientCertGenerator generator.CertGenerator,
 	serverCAFetcher fetcher.CertBundleFetcher,
-	apiServerKey *rsa.PublicKey,
+	tokenKeys token.VerificationKeySource,
 	installerLabels map[string]string) (controller.Controller, error) {
 	reconciler := &CloneReconciler{
 		client:              mgr.GetClient(),
 		scheme:              mgr.GetScheme(),
 		log:                 log.WithName("clone-controller"),
-		multiTokenValidator: cc.NewMultiTokenValidator(apiServerKey),
+		multiTokenValidator: cc.NewMultiTokenValidator(tokenKeys),
 		image:               image,
 		verbose:             verbose,
 		pullPolicy:          pullPolicy,
@@ -95,6 +94,7 @@ func NewCloneController(mgr manager.Manager,
 		clientCertGenerator: clientCertGenerator,
 		serverCAFetcher:     serverCAFetcher,
 		installerLabels:     installerLabels,
+		sourceClient:        cc.GetCloneSourceClient,
 	}
 	cloneController, err := controller.New("clone-controller", mgr, controller.Options{
 		MaxConcurrentReconciles: 3,
@@ -179,7 +179,13 @@ func (r *CloneReconciler) Reconcile(ctx context.Context, req reconcile.Request)
 		if cc.HasFinalizer(pvc, cloneSourcePodFinalizer) || pvc.DeletionTimestamp != nil {
 			// Clone completed, remove source pod and/or finalizer
 			if err := r.cleanup(pvc, log); err != nil {
-				return reconcile.Result{}, err
+				if cc.GetCloneSourceCluster(pvc) == nil || pvc.DeletionTimestamp == nil {
+					return reconcile.Result{}, err
+				}
+				return r.retryRemoteCleanup(pvc, err, log)
+			}
+			if cc.GetCloneSourceCluster(pvc) != nil && cc.HasFinalizer(pvc, cloneSourcePodFinalizer) {
+				return reconcile.Result{RequeueAfter: remotePollInterval(pvc.CreationTimestamp.Time)}, nil
 			}
 		}
 		return reconcile.Result{}, nil
@@ -226,17 +232,83 @@ func (r *CloneReconciler) Reconcile(ctx context.Context, req reconcile.Request)
 	if err := r.updatePvcFromPod(sourcePod, pvc, log); err != nil {
 		return reconcile.Result{}, err
 	}
+
+	if cc.GetCloneSourceCluster(pvc) != nil && pvc.Annotations[cc.AnnCloneOf] != "true" {
+		return reconcile.Result{RequeueAfter: remotePollInterval(pvc.CreationTimestamp.Time)}, nil
+	}
 	return reconcile.Result{}, nil
 }
 
+// remotePollInterval returns the interval polling the pods of a remote cluster, a tenth of the time waited since start
+// bounded by remotePollMinInterval and remotePollMaxInterval
+func remotePollInterval(start time.Time) time.Duration {
+	interval := time.Since(start) / 10
+	if interval < remotePollMinInterval {
+		return remotePollMinInterval
+	}
+	if interval > remotePollMaxInterval {
+		return remotePollMaxInterval
+	}
+	return interval
+}
+
+// retryRemoteCleanup retries the cleanup of a deleted target until remoteCleanupTimeout, then the finalizer is removed
+// so that an unreachable remote cluster doesn't block the deletion, leaving the source pod in the remote cluster
+func (r *CloneReconciler) retryRemoteCleanup(pvc *corev1.PersistentVolumeClaim, cleanupErr error, log logr.Logger) (reconcile.Result, error) {
+	if time.Since(pvc.DeletionTimestamp.Time) < remoteCleanupTimeout {
+		log.Error(cleanupErr, "Unable to clean up the clone source in the remote cluster, retrying")
+		return reconcile.Result{RequeueAfter: remotePollInterval(pvc.DeletionTimestamp.Time)}, nil
+	}
+	r.recorder.Eventf(pvc, corev1.EventTypeWarning, cc.RemoteCloneCleanupFailed,
+		"Unable to delete the clone source pod %s in the remote cluster, it must be deleted manually: %v", pvc.Annotations[cc.AnnCloneSourcePod], cleanupErr)
+	cc.RemoveFinalizer(pvc, cloneSourcePodFinalizer)
+	return reconcile.Result{}, r.updatePVC(pvc)
+}
+
+// getSourceClient returns the client of the cluster of the clone source of the target PVC
+func (r *CloneReconciler) getSourceClient(ctx context.Context, targetPvc *corev1.PersistentVolumeClaim) (client.Client, error) {
+	cluster := cc.GetCloneSourceCluster(targetPvc)
+	if cluster == nil {
+		return r.client, nil
+	}
+	return r.sourceClient(ctx, r.client, targetPvc.Namespace, cluster)
+}
+
+// authorizeRemoteSource checks the user of the kubeconfig of the remote cluster can clone the source PVC, like the
+// clone token does for the user creating the clone
+func (r *CloneReconciler) authorizeRemoteSource(ctx context.Context, sourceClient client.Client, sourcePvc, targetPvc *corev1.PersistentVolumeClaim) error {
+	if cc.GetCloneSourceCluster(targetPvc) == nil {
+		return nil
+	}
+
+	createSelfSar := func(sar *authorizationv1.SelfSubjectAccessReview) (*authorizationv1.SelfSubjectAccessReview, error) {
+		err := sourceClient.Create(ctx, sar)
+		return sar, err
+	}
+	allowed, reason, err := cdiv1.CanClonePVCInCluster(createSelfSar, sourcePvc.Namespace, sourcePvc.Name)
+	if err != nil {
+		return errors.Wrap(err, "error authorizing the clone in the source cluster")
+	}
+	if !allowed {
+		r.recorder.Event(targetPvc, corev1.EventTypeWarning, cc.ErrUnauthorizedCloneSource, reason)
+		return errors.New(reason)
+	}
+	return nil
+}
+
 func (r *CloneReconciler) reconcileSourcePod(ctx context.Context, sourcePod *corev1.Pod, targetPvc *corev1.PersistentVolumeClaim, log logr.Logger) (time.Duration, error) {
 	if sourcePod == nil {
+		sourceClient, err := r.getSourceClient(ctx, targetPvc)
+		if err != nil {
+			return 0, err
+		}
+
 		sourcePvc, err := r.getCloneRequestSourcePVC(targetPvc)
 		if err != nil {
 			return 0, err
 		}
 
-		sourcePopulated, err := cc.IsPopulated(sourcePvc, r.client)
+		sourcePopulated, err := cc.IsPopulated(sourcePvc, sourceClient)
 		if err != nil {
 			return 0, err
 		}
@@ -248,7 +320,11 @@ func (r *CloneReconciler) reconcileSourcePod(ctx context.Context, sourcePod *cor
 			return 0, err
 		}
 
-		pods, err := cc.GetPodsUsingPVCs(ctx, r.client, sourcePvc.Namespace, sets.New(sourcePvc.Name), true)
+		if err := r.authorizeRemoteSource(ctx, sourceClient, sourcePvc, targetPvc); err != nil {
+			return 0, err
+		}
+
+		pods, err := cc.GetPodsUsingPVCs(ctx, sourceClient, sourcePvc.Namespace, sets.New(sourcePvc.Name), true)
 		if err != nil {
 			return 0, err
 		}
@@ -325,7 +401,11 @@ func (r *CloneReconciler) ensureCertSecret(sourcePod *corev1.Pod, targetPvc *cor
 
 	util.SetRecommendedLabels(secret, r.installerLabels, "cdi-controller")
 
-	err = r.client.Create(context.TODO(), secret)
+	sourceClient, err := r.getSourceClient(context.TODO(), targetPvc)
+	if err != nil {
+		return err
+	}
+	err = sourceClient.Create(context.TODO(), secret)
 	if err != nil && !k8serrors.IsAlreadyExists(err) {
 		return errors.Wrap(err, "error creating cert secret")
 	}
@@ -403,8 +483,13 @@ func (r *CloneReconciler) findCloneSourcePod(pvc *corev1.PersistentVolumeClaim)
 		return nil, errors.Wrap(err, "error creating label selector")
 	}
 
+	sourceClient, err := r.getSourceClient(context.TODO(), pvc)
+	if err != nil {
+		return nil, err
+	}
+
 	podList := &corev1.PodList{}
-	if err := r.client.List(context.TODO(), podList, &client.ListOptions{Namespace: sourceNamespace, LabelSelector: selector}); err != nil {
+	if err := sourceClient.List(context.TODO(), podList, &client.ListOptions{Namespace: sourceNamespace, LabelSelector: selector}); err != nil {
 		return nil, errors.Wrap(err, "error listing pods")
 	}
 
@@ -442,8 +527,12 @@ func (r *CloneReconciler) getCloneRequestSourcePVC(pvc *corev1.PersistentVolumeC
 	if !exists {
 		return nil, errors.New("error parsing clone request annotation")
 	}
+	sourceClient, err := r.getSourceClient(context.TODO(), pvc)
+	if err != nil {
+		return nil, err
+	}
 	pvc = &corev1.PersistentVolumeClaim{}
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, pvc); err != nil {
+	if err := sourceClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, pvc); err != nil {
 		return nil, errors.Wrap(err, "error getting clone source PVC")
 	}
 	return pvc, nil
@@ -454,7 +543,11 @@ func (r *CloneReconciler) cleanup(pvc *corev1.PersistentVolumeClaim, log logr.Lo
 
 	pod, err := r.findCloneSourcePod(pvc)
 	if err != nil {
-		return err
+		// the source pod of a remote cluster can't be found once its kubeconfig secret is gone
+		if cc.GetCloneSourceCluster(pvc) == nil || !k8serrors.IsNotFound(err) {
+			return err
+		}
+		log.Info("Kubeconfig secret of the clone source cluster not found, not deleting the source pod", "error", err)
 	}
 
 	if pod != nil && pod.DeletionTimestamp == nil {
@@ -464,7 +557,11 @@ func (r *CloneReconciler) cleanup(pvc *corev1.PersistentVolumeClaim, log logr.Lo
 		}
 		if cc.ShouldDeletePod(pvc) {
 			log.V(3).Info("Deleting pod", "pod.Name", pod.Name)
-			if err = r.client.Delete(context.TODO(), pod); err != nil {
+			sourceClient, err := r.getSourceClient(context.TODO(), pvc)
+			if err != nil {
+				return err
+			}
+			if err = sourceClient.Delete(context.TODO(), pod); err != nil {
 				if !k8serrors.IsNotFound(err) {
 					return errors.Wrap(err, "error deleting clone source pod")
 				}
@@ -524,7 +621,11 @@ func (r *CloneReconciler) CreateCloneSourcePod(image, pullPolicy string, pvc *co
 	pod := MakeCloneSourcePodSpec(sourceVolumeMode, image, pullPolicy, ownerKey, imagePullSecrets, serverCABundle, pvc, sourcePvc, podResourceRequirements, workloadNodePlacement)
 	util.SetRecommendedLabels(pod, r.installerLabels, "cdi-controller")
 
-	if err := r.client.Create(context.TODO(), pod); err != nil {
+	sourceClient, err := r.getSourceClient(context.TODO(), pvc)
+	if err != nil {
+		return nil, err
+	}
+	if err := sourceClient.Create(context.TODO(), pod); err != nil {
 		return nil, errors.Wrap(err, "source pod API create errored")
 	}
 
@@ -544,6 +645,10 @@ func MakeCloneSourcePodSpec(sourceVolumeMode corev1.PersistentVolumeMode, image,
 	var ownerID string
 	cloneSourcePodName := targetPvc.Annotations[cc.AnnCloneSourcePod]
 	url := GetUploadServerURL(targetPvc.Namespace, targetPvc.Name, common.UploadPathSync)
+	remoteCluster := cc.GetCloneSourceCluster(targetPvc)
+	if remoteCluster != nil {
+		url = getUploadServerURLInDomain(targetPvc.Namespace, targetPvc.Name, remoteCluster.ServiceDomain, common.UploadPathSync)
+	}
 	pvcOwner := metav1.GetControllerOf(targetPvc)
 	if pvcOwner != nil && pvcOwner.Kind == "DataVolume" {
 		ownerID = string(pvcOwner.UID)
@@ -652,6 +757,14 @@ func MakeCloneSourcePodSpec(sourceVolumeMode corev1.PersistentVolumeMode, image,
 		},
 	}
 
+	if remoteCluster != nil {
+		// the placement and priority of the workloads of the local cluster don't apply to the remote cluster
+		pod.Spec.NodeSelector = nil
+		pod.Spec.Tolerations = nil
+		pod.Spec.Affinity = nil
+		pod.Spec.PriorityClassName = ""
+	}
+
 	if pod.Spec.Affinity == nil {
 		pod.Spec.Affinity = &corev1.Affinity{}
 	}
@@ -679,25 +792,28 @@ func MakeCloneSourcePodSpec(sourceVolumeMode corev1.PersistentVolumeMode, image,
 		)
 	}
 
-	pod.Spec.Affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
-		pod.Spec.Affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
-		corev1.WeightedPodAffinityTerm{
-			Weight: 100,
-			PodAffinityTerm: corev1.PodAffinityTerm{
-				LabelSelector: &metav1.LabelSelector{
-					MatchExpressions: []metav1.LabelSelectorRequirement{
-						{
-							Key:      common.UploadTargetLabel,
-							Operator: metav1.LabelSelectorOpIn,
-							Values:   []string{string(targetPvc.UID)},
+	// the upload pod is in another cluster for a remote source
+	if remoteCluster == nil {
+		pod.Spec.Affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			pod.Spec.Affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			corev1.WeightedPodAffinityTerm{
+				Weight: 100,
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					LabelSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{
+								Key:      common.UploadTargetLabel,
+								Operator: metav1.LabelSelectorOpIn,
+								Values:   []string{string(targetPvc.UID)},
+							},
 						},
 					},
+					Namespaces:  []string{targetPvc.Namespace},
+					TopologyKey: corev1.LabelHostname,
 				},
-				Namespaces:  []string{targetPvc.Namespace},
-				TopologyKey: corev1.LabelHostname,
 			},
-		},
-	)
+		)
+	}
 
 	if resourceRequirements != nil {
 		pod.Spec.Containers[0].Resources = *resourceRequirements
@@ -717,6 +833,12 @@ func MakeCloneSourcePodSpec(sourceVolumeMode corev1.PersistentVolumeMode, image,
 				Value: common.WriteBlockPath,
 			},
 		}
+		if metav1.HasAnnotation(targetPvc.ObjectMeta, cc.AnnCloneDeltaSync) {
+			addVars = append(addVars, corev1.EnvVar{
+				Name:  common.DeltaSync,
+				Value: "true",
+			})
+		}
 	} else {
 		pod.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{
 			{
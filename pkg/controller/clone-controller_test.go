@@ -22,10 +22,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pkg/errors"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,7 +38,9 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -62,7 +68,7 @@ func (cg *fakeCertGenerator) MakeClientCert(name string, groups []string, durati
 	return []byte("foo"), []byte("bar"), nil
 }
 
-func (cg *fakeCertGenerator) MakeServerCert(namespace, service string, duration time.Duration) ([]byte, []byte, error) {
+func (cg *fakeCertGenerator) MakeServerCert(namespace, service string, duration time.Duration, domains ...string) ([]byte, []byte, error) {
 	if cg.expectedDuration != 0 {
 		Expect(duration).To(Equal(cg.expectedDuration))
 	}
@@ -313,6 +319,135 @@ var _ = Describe("Clone controller reconcile loop", func() {
 		}),
 	)
 
+	It("Should create the source pod syncing only the changed blocks of a delta sync target", func() {
+		blockMode := corev1.PersistentVolumeBlock
+		testPvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnCloneRequest:   "default/source",
+			cc.AnnPodReady:       "true",
+			cc.AnnCloneToken:     "foobaz",
+			AnnUploadClientName:  "uploadclient",
+			cc.AnnCloneSourcePod: "default-testPvc1-source-pod",
+			cc.AnnCloneDeltaSync: "true"}, nil)
+		testPvc.Spec.VolumeMode = &blockMode
+		sourcePvc := cc.CreatePvc("source", "default", map[string]string{}, nil)
+		sourcePvc.Spec.VolumeMode = &blockMode
+		reconciler = createCloneReconciler(testPvc, sourcePvc)
+		reconciler.multiTokenValidator.ShortTokenValidator.(*cc.FakeValidator).Match = "foobaz"
+		reconciler.multiTokenValidator.ShortTokenValidator.(*cc.FakeValidator).Name = "source"
+		reconciler.multiTokenValidator.ShortTokenValidator.(*cc.FakeValidator).Namespace = "default"
+		reconciler.multiTokenValidator.ShortTokenValidator.(*cc.FakeValidator).Params["targetNamespace"] = "default"
+		reconciler.multiTokenValidator.ShortTokenValidator.(*cc.FakeValidator).Params["targetName"] = "testPvc1"
+		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		sourcePod, err := reconciler.findCloneSourcePod(testPvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sourcePod).ToNot(BeNil())
+		Expect(sourcePod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.DeltaSync, Value: "true"}))
+	})
+
+	DescribeTable("Should create the source pod in the remote cluster", func(allowed bool) {
+		testPvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnCloneRequest:             "remote-ns/source",
+			cc.AnnPodReady:                 "true",
+			cc.AnnCloneToken:               "foobaz",
+			AnnUploadClientName:            "uploadclient",
+			cc.AnnCloneSourcePod:           "default-testPvc1-source-pod",
+			cc.AnnCloneSourceKubeconfig:    "remote-kubeconfig",
+			cc.AnnCloneSourceServiceDomain: "remote.example.com"}, nil)
+		reconciler = createCloneReconciler(testPvc)
+		By("Setting up the match token")
+		reconciler.multiTokenValidator.ShortTokenValidator.(*cc.FakeValidator).Match = "foobaz"
+		reconciler.multiTokenValidator.ShortTokenValidator.(*cc.FakeValidator).Name = "source"
+		reconciler.multiTokenValidator.ShortTokenValidator.(*cc.FakeValidator).Namespace = "remote-ns"
+		reconciler.multiTokenValidator.ShortTokenValidator.(*cc.FakeValidator).Params["targetNamespace"] = "default"
+		reconciler.multiTokenValidator.ShortTokenValidator.(*cc.FakeValidator).Params["targetName"] = "testPvc1"
+		By("Setting up the remote cluster")
+		remoteClient := fake.NewClientBuilder().
+			WithScheme(reconciler.scheme).
+			WithRuntimeObjects(cc.CreatePvc("source", "remote-ns", map[string]string{}, nil)).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					if sar, ok := obj.(*authorizationv1.SelfSubjectAccessReview); ok {
+						sar.Status.Allowed = allowed
+						return nil
+					}
+					return c.Create(ctx, obj, opts...)
+				},
+			}).
+			Build()
+		reconciler.sourceClient = func(_ context.Context, _ client.Client, namespace string, cluster *cdiv1.CloneSourceCluster) (client.Client, error) {
+			Expect(namespace).To(Equal("default"))
+			Expect(cluster.KubeconfigSecretRef).To(Equal("remote-kubeconfig"))
+			return remoteClient, nil
+		}
+
+		result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		podList := &corev1.PodList{}
+		Expect(reconciler.client.List(context.TODO(), podList)).To(Succeed())
+		Expect(podList.Items).To(BeEmpty())
+		Expect(remoteClient.List(context.TODO(), podList)).To(Succeed())
+		if !allowed {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("insufficient permissions in clone source namespace remote-ns"))
+			Expect(podList.Items).To(BeEmpty())
+			event := <-reconciler.recorder.(*record.FakeRecorder).Events
+			Expect(event).To(ContainSubstring(cc.ErrUnauthorizedCloneSource))
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).ToNot(BeZero())
+		Expect(podList.Items).To(HaveLen(1))
+		sourcePod := podList.Items[0]
+		Expect(sourcePod.Namespace).To(Equal("remote-ns"))
+		Expect(sourcePod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name:  "UPLOAD_URL",
+			Value: fmt.Sprintf("https://cdi-upload-testPvc1.default.svc.remote.example.com:%d%s", common.UploadServerPort, common.UploadPathSync),
+		}))
+		Expect(sourcePod.Spec.Affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(BeEmpty())
+	},
+		Entry("if the kubeconfig user can clone the source", true),
+		Entry("not if the kubeconfig user cannot clone the source", false),
+	)
+
+	DescribeTable("Should retry the cleanup of a deleted target when the remote cluster is unreachable", func(deletedAgo time.Duration, released bool) {
+		testPvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnCloneRequest:          "remote-ns/source",
+			cc.AnnCloneSourcePod:        "default-testPvc1-source-pod",
+			cc.AnnCloneSourceKubeconfig: "remote-kubeconfig"}, nil)
+		testPvc.Finalizers = []string{cloneSourcePodFinalizer}
+		testPvc.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-deletedAgo)}
+		reconciler = createCloneReconciler(testPvc)
+		reconciler.sourceClient = func(_ context.Context, _ client.Client, _ string, _ *cdiv1.CloneSourceCluster) (client.Client, error) {
+			return nil, errors.New("connection refused")
+		}
+
+		result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, testPvc)
+		if !released {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cc.HasFinalizer(testPvc, cloneSourcePodFinalizer)).To(BeTrue())
+			Expect(result.RequeueAfter).To(BeNumerically(">=", remotePollMinInterval))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", remotePollMaxInterval))
+			return
+		}
+		// the finalizer was the last one, the PVC is gone
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		Expect(result.RequeueAfter).To(BeZero())
+		event := <-reconciler.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(cc.RemoteCloneCleanupFailed))
+		Expect(event).To(ContainSubstring("connection refused"))
+	},
+		Entry("until the timeout", time.Minute, false),
+		Entry("and release the finalizer after the timeout", remoteCleanupTimeout+time.Minute, true),
+	)
+
+	It("Should poll the remote cluster with a growing interval", func() {
+		Expect(remotePollInterval(time.Now())).To(Equal(remotePollMinInterval))
+		Expect(remotePollInterval(time.Now().Add(-5 * time.Minute))).To(BeNumerically("~", 30*time.Second, time.Second))
+		Expect(remotePollInterval(time.Now().Add(-time.Hour))).To(Equal(remotePollMaxInterval))
+	})
+
 	It("Should error with missing upload client name annotation if none provided", func() {
 		testPvc := cc.CreatePvc("testPvc1", "default", map[string]string{
 			cc.AnnCloneRequest: "default/source", cc.AnnPodReady: "true", cc.AnnCloneToken: "foobaz", cc.AnnCloneSourcePod: "default-testPvc1-source-pod"}, nil)
@@ -684,7 +819,7 @@ var _ = Describe("Update PVC", func() {
 
 var _ = Describe("TokenValidation", func() {
 	g := token.NewGenerator(common.CloneTokenIssuer, cc.GetAPIServerKey(), 5*time.Minute)
-	v := cc.NewCloneTokenValidator(common.CloneTokenIssuer, &cc.GetAPIServerKey().PublicKey)
+	v := cc.NewCloneTokenValidator(common.CloneTokenIssuer, token.NewStaticVerificationKeySource(&cc.GetAPIServerKey().PublicKey))
 
 	goodTokenData := func() *token.Payload {
 		return &token.Payload{
@@ -730,7 +865,7 @@ var _ = Describe("TokenValidation", func() {
 	missingParams.Params = nil
 
 	DescribeTable("should", func(p *token.Payload, expectedSuccess bool) {
-		tokenString, err := g.Generate(p)
+		tokenString, _, err := g.Generate(p)
 		if err != nil {
 			panic("error generating token")
 		}
@@ -128,6 +128,19 @@ func GetGlobalCloneStrategyOverride(ctx context.Context, c client.Client) (*cdiv
 	return cr.Spec.CloneStrategyOverride, nil
 }
 
+// HostAssistedAllowed returns true if the clone strategy policy accepts a host-assisted copy
+func HostAssistedAllowed(policy *cdiv1.CloneStrategyPolicy) bool {
+	if policy == nil || policy.Fallback == nil || *policy.Fallback != cdiv1.CloneFallbackFail {
+		return true
+	}
+	for _, strategy := range policy.Strategies {
+		if strategy == cdiv1.CloneStrategyHostAssisted {
+			return true
+		}
+	}
+	return false
+}
+
 // GetStorageClassForClaim returns the storageclass for a PVC
 func GetStorageClassForClaim(ctx context.Context, c client.Client, pvc *corev1.PersistentVolumeClaim) (*storagev1.StorageClass, error) {
 	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
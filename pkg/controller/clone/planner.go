@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/go-logr/logr"
@@ -71,6 +72,24 @@ const (
 
 	// MessageIncompatibleProvisioners reports that the provisioners are incompatible (message)
 	MessageIncompatibleProvisioners = "Provisioners are incompatible"
+
+	// UnsupportedCloneStrategy reports that the clone strategy is not supported for the source (reason)
+	UnsupportedCloneStrategy = "UnsupportedCloneStrategy"
+
+	// MessageUnsupportedCloneStrategy reports that the clone strategy is not supported for the source (message)
+	MessageUnsupportedCloneStrategy = "The clone strategy is not supported for the source"
+
+	// NoCloneStrategy reports that none of the acceptable clone strategies is possible (reason)
+	NoCloneStrategy = "NoCloneStrategy"
+
+	// MessageNoCloneStrategy reports that none of the acceptable clone strategies is possible (message)
+	MessageNoCloneStrategy = "None of the acceptable clone strategies is possible: %s"
+
+	// CloneStrategyChosen reports the strategy chosen for a clone (reason)
+	CloneStrategyChosen = "CloneStrategyChosen"
+
+	// MessageCloneStrategyChosen reports the strategy chosen for a clone (message)
+	MessageCloneStrategyChosen = "Cloning with strategy %s"
 )
 
 // Planner plans clone operations
@@ -295,8 +314,6 @@ func (p *Planner) watchOwned(log logr.Logger, obj client.Object) error {
 }
 
 func (p *Planner) computeStrategyForSourcePVC(ctx context.Context, args *ChooseStrategyArgs) (*ChooseStrategyResult, error) {
-	res := &ChooseStrategyResult{}
-
 	if ok, err := p.validateTargetStorageClassAssignment(ctx, args); !ok || err != nil {
 		return nil, err
 	}
@@ -320,6 +337,22 @@ func (p *Planner) computeStrategyForSourcePVC(ctx context.Context, args *ChooseS
 		return nil, err
 	}
 
+	strategies, err := p.getAcceptableStrategies(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.chooseFirstPossibleStrategy(args, strategies, func(strategy cdiv1.CDICloneStrategy) (*strategyRejection, error) {
+		return p.checkStrategyForSourcePVC(ctx, args, sourceClaim, strategy)
+	})
+}
+
+// getAcceptableStrategies returns the strategies of the clone policy, or else the preferred strategy of the target
+func (p *Planner) getAcceptableStrategies(ctx context.Context, args *ChooseStrategyArgs) ([]cdiv1.CDICloneStrategy, error) {
+	if policy := args.DataSource.Spec.CloneStrategy; policy != nil && len(policy.Strategies) > 0 {
+		return policy.Strategies, nil
+	}
+
 	strategy := cdiv1.CloneStrategySnapshot
 	cs, err := GetGlobalCloneStrategyOverride(ctx, p.Client)
 	if err != nil {
@@ -344,32 +377,79 @@ func (p *Planner) computeStrategyForSourcePVC(ctx context.Context, args *ChooseS
 		}
 	}
 
-	if strategy == cdiv1.CloneStrategySnapshot {
-		n, err := GetCompatibleVolumeSnapshotClass(ctx, p.Client, args.Log, p.Recorder, sourceClaim, args.TargetClaim)
+	return []cdiv1.CDICloneStrategy{strategy}, nil
+}
+
+// strategyRejection tells why a clone strategy is not possible
+type strategyRejection struct {
+	reason  string
+	message string
+}
+
+// chooseFirstPossibleStrategy returns the first of the strategies which is possible, or else falls back to host-assisted
+// unless the clone policy prevents it
+func (p *Planner) chooseFirstPossibleStrategy(args *ChooseStrategyArgs, strategies []cdiv1.CDICloneStrategy,
+	check func(cdiv1.CDICloneStrategy) (*strategyRejection, error)) (*ChooseStrategyResult, error) {
+	var rejections []string
+	for _, strategy := range strategies {
+		rejection, err := check(strategy)
 		if err != nil {
 			return nil, err
 		}
+		if rejection == nil {
+			p.Recorder.Eventf(args.TargetClaim, corev1.EventTypeNormal, CloneStrategyChosen, MessageCloneStrategyChosen, strategy)
+			return newChooseStrategyResult(strategy, rejections), nil
+		}
 
-		if n == nil {
-			p.fallbackToHostAssisted(args.TargetClaim, res, NoVolumeSnapshotClass, MessageNoVolumeSnapshotClass)
-			return res, nil
+		args.Log.V(3).Info("clone strategy not possible", "strategy", strategy, "reason", rejection.reason)
+		p.Recorder.Event(args.TargetClaim, corev1.EventTypeWarning, rejection.reason, rejection.message)
+		if len(strategies) > 1 {
+			rejections = append(rejections, fmt.Sprintf("%s: %s", strategy, rejection.message))
+		} else {
+			rejections = append(rejections, rejection.message)
 		}
 	}
 
-	res.Strategy = strategy
-	if strategy == cdiv1.CloneStrategySnapshot ||
-		strategy == cdiv1.CloneStrategyCsiClone {
-		if err := p.validateAdvancedClonePVC(ctx, args, res, sourceClaim); err != nil {
+	if policy := args.DataSource.Spec.CloneStrategy; policy != nil && policy.Fallback != nil && *policy.Fallback == cdiv1.CloneFallbackFail {
+		message := fmt.Sprintf(MessageNoCloneStrategy, strings.Join(rejections, "; "))
+		p.Recorder.Event(args.TargetClaim, corev1.EventTypeWarning, NoCloneStrategy, message)
+		return nil, fmt.Errorf("%s", message)
+	}
+
+	p.Recorder.Eventf(args.TargetClaim, corev1.EventTypeNormal, CloneStrategyChosen, MessageCloneStrategyChosen, cdiv1.CloneStrategyHostAssisted)
+	return newChooseStrategyResult(cdiv1.CloneStrategyHostAssisted, rejections), nil
+}
+
+func newChooseStrategyResult(strategy cdiv1.CDICloneStrategy, rejections []string) *ChooseStrategyResult {
+	res := &ChooseStrategyResult{Strategy: strategy}
+	if len(rejections) > 0 {
+		reason := strings.Join(rejections, "; ")
+		res.FallbackReason = &reason
+	}
+	return res
+}
+
+func (p *Planner) checkStrategyForSourcePVC(ctx context.Context, args *ChooseStrategyArgs, sourceClaim *corev1.PersistentVolumeClaim, strategy cdiv1.CDICloneStrategy) (*strategyRejection, error) {
+	switch strategy {
+	case cdiv1.CloneStrategyHostAssisted:
+		return nil, nil
+	case cdiv1.CloneStrategySnapshot:
+		n, err := GetCompatibleVolumeSnapshotClass(ctx, p.Client, args.Log, p.Recorder, sourceClaim, args.TargetClaim)
+		if err != nil {
 			return nil, err
 		}
-	}
 
-	return res, nil
+		if n == nil {
+			return &strategyRejection{NoVolumeSnapshotClass, MessageNoVolumeSnapshotClass}, nil
+		}
+		return p.checkAdvancedClonePVC(ctx, args, sourceClaim)
+	case cdiv1.CloneStrategyCsiClone:
+		return p.checkAdvancedClonePVC(ctx, args, sourceClaim)
+	}
+	return nil, fmt.Errorf("unknown clone strategy %s", strategy)
 }
 
 func (p *Planner) computeStrategyForSourceSnapshot(ctx context.Context, args *ChooseStrategyArgs) (*ChooseStrategyResult, error) {
-	res := &ChooseStrategyResult{}
-
 	if ok, err := p.validateTargetStorageClassAssignment(ctx, args); !ok || err != nil {
 		return nil, err
 	}
@@ -399,14 +479,34 @@ func (p *Planner) computeStrategyForSourceSnapshot(ctx context.Context, args *Ch
 	if targetStorageClass == nil {
 		return nil, fmt.Errorf("target claim's storageclass doesn't exist, clone will not work")
 	}
+
+	strategies := []cdiv1.CDICloneStrategy{cdiv1.CloneStrategySnapshot}
+	if policy := args.DataSource.Spec.CloneStrategy; policy != nil && len(policy.Strategies) > 0 {
+		strategies = policy.Strategies
+	}
+
+	return p.chooseFirstPossibleStrategy(args, strategies, func(strategy cdiv1.CDICloneStrategy) (*strategyRejection, error) {
+		switch strategy {
+		case cdiv1.CloneStrategyHostAssisted:
+			return nil, nil
+		case cdiv1.CloneStrategySnapshot:
+			return p.checkSmartCloneFromSnapshot(args, sourceSnapshot, vsc, targetStorageClass)
+		case cdiv1.CloneStrategyCsiClone:
+			return &strategyRejection{UnsupportedCloneStrategy, MessageUnsupportedCloneStrategy}, nil
+		}
+		return nil, fmt.Errorf("unknown clone strategy %s", strategy)
+	})
+}
+
+func (p *Planner) checkSmartCloneFromSnapshot(args *ChooseStrategyArgs, sourceSnapshot *snapshotv1.VolumeSnapshot,
+	vsc *snapshotv1.VolumeSnapshotContent, targetStorageClass *storagev1.StorageClass) (*strategyRejection, error) {
 	valid, err := cc.ValidateSnapshotCloneProvisioners(vsc, targetStorageClass)
 	if err != nil {
 		return nil, err
 	}
 	if !valid {
-		p.fallbackToHostAssisted(args.TargetClaim, res, NoProvisionerMatch, MessageNoProvisionerMatch)
 		args.Log.V(3).Info("Provisioner differs, need to fall back to host assisted")
-		return res, nil
+		return &strategyRejection{NoProvisionerMatch, MessageNoProvisionerMatch}, nil
 	}
 
 	// do size validation
@@ -415,19 +515,16 @@ func (p *Planner) computeStrategyForSourceSnapshot(ctx context.Context, args *Ch
 		return nil, err
 	}
 	if !valid {
-		p.fallbackToHostAssisted(args.TargetClaim, res, NoVolumeExpansion, MessageNoVolumeExpansion)
-		return res, nil
+		return &strategyRejection{NoVolumeExpansion, MessageNoVolumeExpansion}, nil
 	}
 
 	// Lastly, do volume mode validation to determine whether to use dumb or smart cloning
 	if !SameVolumeMode(vsc.Spec.SourceVolumeMode, args.TargetClaim) {
-		p.fallbackToHostAssisted(args.TargetClaim, res, IncompatibleVolumeModes, MessageIncompatibleVolumeModes)
 		args.Log.V(3).Info("Volume modes differs, need to fall back to host assisted - Snapshot")
-		return res, nil
+		return &strategyRejection{IncompatibleVolumeModes, MessageIncompatibleVolumeModes}, nil
 	}
 
-	res.Strategy = cdiv1.CloneStrategySnapshot
-	return res, nil
+	return nil, nil
 }
 
 func (p *Planner) validateTargetStorageClassAssignment(ctx context.Context, args *ChooseStrategyArgs) (bool, error) {
@@ -474,53 +571,45 @@ func (p *Planner) validateSourcePVC(args *ChooseStrategyArgs, sourceClaim *corev
 	return nil
 }
 
-func (p *Planner) validateAdvancedClonePVC(ctx context.Context, args *ChooseStrategyArgs, res *ChooseStrategyResult, sourceClaim *corev1.PersistentVolumeClaim) error {
+func (p *Planner) checkAdvancedClonePVC(ctx context.Context, args *ChooseStrategyArgs, sourceClaim *corev1.PersistentVolumeClaim) (*strategyRejection, error) {
 	driver, err := GetCommonDriver(ctx, p.Client, sourceClaim, args.TargetClaim)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if driver == nil {
-		p.fallbackToHostAssisted(args.TargetClaim, res, IncompatibleProvisioners, MessageIncompatibleProvisioners)
 		args.Log.V(3).Info("CSIDrivers not compatible for advanced clone")
-		return nil
+		return &strategyRejection{IncompatibleProvisioners, MessageIncompatibleProvisioners}, nil
 	}
 
 	if !SameVolumeMode(sourceClaim.Spec.VolumeMode, args.TargetClaim) {
-		p.fallbackToHostAssisted(args.TargetClaim, res, IncompatibleVolumeModes, MessageIncompatibleVolumeModes)
 		args.Log.V(3).Info("volume modes not compatible for advanced clone")
-		return nil
+		return &strategyRejection{IncompatibleVolumeModes, MessageIncompatibleVolumeModes}, nil
 	}
 
 	sc, err := GetStorageClassForClaim(ctx, p.Client, args.TargetClaim)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if sc == nil {
 		args.Log.V(3).Info("target storage class not found")
-		return fmt.Errorf("target storage class not found")
+		return nil, fmt.Errorf("target storage class not found")
 	}
 
 	srcCapacity, hasSrcCapacity := sourceClaim.Status.Capacity[corev1.ResourceStorage]
 	targetRequest, hasTargetRequest := args.TargetClaim.Spec.Resources.Requests[corev1.ResourceStorage]
 	allowExpansion := sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion
 	if !hasSrcCapacity || !hasTargetRequest {
-		return fmt.Errorf("source/target size info missing")
+		return nil, fmt.Errorf("source/target size info missing")
 	}
 
 	if srcCapacity.Cmp(targetRequest) < 0 && !allowExpansion {
-		p.fallbackToHostAssisted(args.TargetClaim, res, NoVolumeExpansion, MessageNoVolumeExpansion)
 		args.Log.V(3).Info("advanced clone not possible, no volume expansion")
+		return &strategyRejection{NoVolumeExpansion, MessageNoVolumeExpansion}, nil
 	}
 
-	return nil
-}
-
-func (p *Planner) fallbackToHostAssisted(targetClaim *corev1.PersistentVolumeClaim, res *ChooseStrategyResult, reason, message string) {
-	res.Strategy = cdiv1.CloneStrategyHostAssisted
-	res.FallbackReason = &message
-	p.Recorder.Event(targetClaim, corev1.EventTypeWarning, reason, message)
+	return nil, nil
 }
 
 func (p *Planner) planHostAssistedFromPVC(ctx context.Context, args *PlanArgs) ([]Phase, error) {
@@ -18,6 +18,7 @@ package clone
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -525,6 +526,73 @@ var _ = Describe("Planner test", func() {
 				Expect(csr.FallbackReason).ToNot(BeNil())
 				Expect(*csr.FallbackReason).To(Equal(MessageIncompatibleProvisioners))
 			})
+
+			It("should return the first possible strategy of the clone policy", func() {
+				dataSource := createPVCDataSource()
+				dataSource.Spec.CloneStrategy = &cdiv1.CloneStrategyPolicy{
+					Strategies: []cdiv1.CDICloneStrategy{cdiv1.CloneStrategySnapshot, cdiv1.CloneStrategyCsiClone},
+				}
+				args := &ChooseStrategyArgs{
+					TargetClaim: createTargetClaim(),
+					DataSource:  dataSource,
+					Log:         log,
+				}
+				planner = createPlanner(createStorageClass(), createSourceClaim(), createSourceVolume())
+				csr, err := planner.ChooseStrategy(context.Background(), args)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(csr).ToNot(BeNil())
+				Expect(csr.Strategy).To(Equal(cdiv1.CloneStrategyCsiClone))
+				Expect(csr.FallbackReason).ToNot(BeNil())
+				Expect(*csr.FallbackReason).To(Equal("snapshot: " + MessageNoVolumeSnapshotClass))
+				expectEvent(planner, CloneStrategyChosen)
+			})
+
+			It("should prefer the clone policy over the storage profile", func() {
+				cs := cdiv1.CloneStrategyCsiClone
+				dataSource := createPVCDataSource()
+				dataSource.Spec.CloneStrategy = &cdiv1.CloneStrategyPolicy{
+					Strategies: []cdiv1.CDICloneStrategy{cdiv1.CloneStrategySnapshot},
+				}
+				args := &ChooseStrategyArgs{
+					TargetClaim: createTargetClaim(),
+					DataSource:  dataSource,
+					Log:         log,
+				}
+				sp := &cdiv1.StorageProfile{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: storageClassName,
+					},
+					Status: cdiv1.StorageProfileStatus{
+						CloneStrategy: &cs,
+					},
+				}
+				planner = createPlanner(sp, createStorageClass(), createSourceClaim(), createVolumeSnapshotClass(), createSourceVolume())
+				csr, err := planner.ChooseStrategy(context.Background(), args)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(csr).ToNot(BeNil())
+				Expect(csr.Strategy).To(Equal(cdiv1.CloneStrategySnapshot))
+				Expect(csr.FallbackReason).To(BeNil())
+			})
+
+			It("should fail if no strategy of the clone policy is possible and fallback is not allowed", func() {
+				fallback := cdiv1.CloneFallbackFail
+				dataSource := createPVCDataSource()
+				dataSource.Spec.CloneStrategy = &cdiv1.CloneStrategyPolicy{
+					Strategies: []cdiv1.CDICloneStrategy{cdiv1.CloneStrategySnapshot},
+					Fallback:   &fallback,
+				}
+				args := &ChooseStrategyArgs{
+					TargetClaim: createTargetClaim(),
+					DataSource:  dataSource,
+					Log:         log,
+				}
+				planner = createPlanner(createStorageClass(), createSourceClaim())
+				csr, err := planner.ChooseStrategy(context.Background(), args)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(fmt.Sprintf(MessageNoCloneStrategy, MessageNoVolumeSnapshotClass)))
+				Expect(csr).To(BeNil())
+				expectEvent(planner, NoCloneStrategy)
+			})
 		})
 
 		Context("Snapshot source", func() {
@@ -677,6 +745,32 @@ var _ = Describe("Planner test", func() {
 				Expect(csr).ToNot(BeNil())
 				Expect(csr.Strategy).To(Equal(cdiv1.CloneStrategySnapshot))
 			})
+
+			It("should skip csi-clone of the clone policy", func() {
+				source := createSourceSnapshot(sourceName, "test-snapshot-content-name", "vsc")
+				fm := corev1.PersistentVolumeFilesystem
+				target := createTargetClaim()
+				target.Spec.VolumeMode = &fm
+				dataSource := createSnapshotDataSource()
+				dataSource.Spec.CloneStrategy = &cdiv1.CloneStrategyPolicy{
+					Strategies: []cdiv1.CDICloneStrategy{cdiv1.CloneStrategyCsiClone, cdiv1.CloneStrategySnapshot},
+				}
+				args := &ChooseStrategyArgs{
+					TargetClaim: target,
+					DataSource:  dataSource,
+					Log:         log,
+				}
+				vsc := createDefaultVolumeSnapshotContent("driver")
+				vsc.Spec.SourceVolumeMode = &fm
+				planner = createPlanner(createStorageClass(), source, vsc)
+				csr, err := planner.ChooseStrategy(context.Background(), args)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(csr).ToNot(BeNil())
+				Expect(csr.Strategy).To(Equal(cdiv1.CloneStrategySnapshot))
+				Expect(csr.FallbackReason).ToNot(BeNil())
+				Expect(*csr.FallbackReason).To(Equal("csi-clone: " + MessageUnsupportedCloneStrategy))
+				expectEvent(planner, UnsupportedCloneStrategy)
+			})
 		})
 	})
 
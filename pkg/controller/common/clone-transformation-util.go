@@ -0,0 +1,59 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// GetDataVolumeCloneTransformation returns the transformation of the disk image of the clone source of a DataVolume,
+// or nil if the disk image is cloned as is
+func GetDataVolumeCloneTransformation(dv *cdiv1.DataVolume) *cdiv1.CloneTransformation {
+	if dv.Spec.Source == nil || dv.Spec.Source.PVC == nil {
+		return nil
+	}
+	return dv.Spec.Source.PVC.Transformation
+}
+
+// SetCloneTransformation sets the transformation of the disk image of the clone source of a claim
+func SetCloneTransformation(obj metav1.Object, transformation *cdiv1.CloneTransformation) {
+	if transformation.Resize {
+		AddAnnotation(obj, AnnCloneResize, "true")
+	}
+	if transformation.LogicalSectorSize != nil {
+		AddAnnotation(obj, AnnCloneLogicalSectorSize, strconv.Itoa(int(*transformation.LogicalSectorSize)))
+	}
+}
+
+// GetCloneTransformation returns the transformation of the disk image of the clone source of a claim, or nil if the
+// disk image is cloned as is
+func GetCloneTransformation(obj metav1.Object) *cdiv1.CloneTransformation {
+	transformation := &cdiv1.CloneTransformation{}
+	transformation.Resize, _ = strconv.ParseBool(obj.GetAnnotations()[AnnCloneResize])
+	if sectorSize, err := strconv.ParseInt(obj.GetAnnotations()[AnnCloneLogicalSectorSize], 10, 32); err == nil {
+		transformation.LogicalSectorSize = ptr.To(int32(sectorSize))
+	}
+	if !transformation.Resize && transformation.LogicalSectorSize == nil {
+		return nil
+	}
+	return transformation
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// KubeconfigSecretKey is the key of the kubeconfig in the secret of a remote cluster
+const KubeconfigSecretKey = "kubeconfig"
+
+// remoteClusterClientTTL is how long the client of a remote cluster is kept once it is not used anymore
+const remoteClusterClientTTL = time.Hour
+
+// CloneSourceClientFunc returns the client of the cluster of a clone source, cluster is nil for the local cluster
+type CloneSourceClientFunc func(ctx context.Context, c client.Client, namespace string, cluster *cdiv1.CloneSourceCluster) (client.Client, error)
+
+type remoteClusterClient struct {
+	resourceVersion string
+	client          client.Client
+	lastUsed        time.Time
+}
+
+var (
+	remoteClusterClientsLock sync.Mutex
+	remoteClusterClients     = map[types.NamespacedName]remoteClusterClient{}
+)
+
+// GetCloneSourceCluster returns the remote cluster of the clone source of a claim, or nil if the source is local
+func GetCloneSourceCluster(obj metav1.Object) *cdiv1.CloneSourceCluster {
+	secretName, ok := obj.GetAnnotations()[AnnCloneSourceKubeconfig]
+	if !ok {
+		return nil
+	}
+	return &cdiv1.CloneSourceCluster{
+		KubeconfigSecretRef: secretName,
+		ServiceDomain:       obj.GetAnnotations()[AnnCloneSourceServiceDomain],
+	}
+}
+
+// GetDataVolumeCloneSourceCluster returns the remote cluster of the clone source of a DataVolume, or nil if the source
+// is local
+func GetDataVolumeCloneSourceCluster(dv *cdiv1.DataVolume) *cdiv1.CloneSourceCluster {
+	if dv.Spec.Source == nil || dv.Spec.Source.PVC == nil {
+		return nil
+	}
+	return dv.Spec.Source.PVC.Cluster
+}
+
+// SetCloneSourceCluster sets the remote cluster of the clone source of a claim
+func SetCloneSourceCluster(obj metav1.Object, cluster *cdiv1.CloneSourceCluster) {
+	AddAnnotation(obj, AnnCloneSourceKubeconfig, cluster.KubeconfigSecretRef)
+	if cluster.ServiceDomain != "" {
+		AddAnnotation(obj, AnnCloneSourceServiceDomain, cluster.ServiceDomain)
+	}
+}
+
+// GetCloneSourceClient returns c for a local clone source, and a client of the remote cluster built from the kubeconfig
+// secret in namespace otherwise. The clients of the remote clusters are kept until their secret changes.
+func GetCloneSourceClient(ctx context.Context, c client.Client, namespace string, cluster *cdiv1.CloneSourceCluster) (client.Client, error) {
+	if cluster == nil {
+		return c, nil
+	}
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: namespace, Name: cluster.KubeconfigSecretRef}
+	err := c.Get(ctx, key, secret)
+
+	remoteClusterClientsLock.Lock()
+	defer remoteClusterClientsLock.Unlock()
+	evictRemoteClusterClients()
+
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			delete(remoteClusterClients, key)
+		}
+		return nil, errors.Wrapf(err, "error getting kubeconfig secret %s", key)
+	}
+	if cached, ok := remoteClusterClients[key]; ok && cached.resourceVersion == secret.ResourceVersion {
+		cached.lastUsed = time.Now()
+		remoteClusterClients[key] = cached
+		return cached.client, nil
+	}
+	delete(remoteClusterClients, key)
+
+	kubeconfig, ok := secret.Data[KubeconfigSecretKey]
+	if !ok {
+		return nil, errors.Errorf("kubeconfig secret %s has no %s key", key, KubeconfigSecretKey)
+	}
+	config, err := RemoteClusterRESTConfig(kubeconfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing kubeconfig secret %s", key)
+	}
+	remoteClient, err := client.New(config, client.Options{Scheme: c.Scheme()})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error creating client from kubeconfig secret %s", key)
+	}
+
+	remoteClusterClients[key] = remoteClusterClient{resourceVersion: secret.ResourceVersion, client: remoteClient, lastUsed: time.Now()}
+	return remoteClient, nil
+}
+
+// evictRemoteClusterClients removes the clients not used for remoteClusterClientTTL, the lock must be held
+func evictRemoteClusterClients() {
+	for key, cached := range remoteClusterClients {
+		if time.Since(cached.lastUsed) > remoteClusterClientTTL {
+			delete(remoteClusterClients, key)
+		}
+	}
+}
+
+// RemoteClusterRESTConfig builds the config of a remote cluster from a kubeconfig supplied by a user. The config is
+// used by the controller, so only the server, its CA and the inline credentials are taken: exec and auth provider
+// plugins, references to files, proxies, and impersonation are refused.
+func RemoteClusterRESTConfig(kubeconfig []byte) (*rest.Config, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	kubeContext, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return nil, errors.Errorf("current context %q not found", config.CurrentContext)
+	}
+	cluster, ok := config.Clusters[kubeContext.Cluster]
+	if !ok {
+		return nil, errors.Errorf("cluster %q not found", kubeContext.Cluster)
+	}
+	authInfo, ok := config.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		return nil, errors.Errorf("user %q not found", kubeContext.AuthInfo)
+	}
+	if err := validateRemoteCluster(cluster); err != nil {
+		return nil, errors.Wrapf(err, "cluster %q", kubeContext.Cluster)
+	}
+	if err := validateRemoteAuthInfo(authInfo); err != nil {
+		return nil, errors.Wrapf(err, "user %q", kubeContext.AuthInfo)
+	}
+	return &rest.Config{
+		Host:        cluster.Server,
+		BearerToken: authInfo.Token,
+		TLSClientConfig: rest.TLSClientConfig{
+			ServerName: cluster.TLSServerName,
+			Insecure:   cluster.InsecureSkipTLSVerify,
+			CAData:     cluster.CertificateAuthorityData,
+			CertData:   authInfo.ClientCertificateData,
+			KeyData:    authInfo.ClientKeyData,
+		},
+	}, nil
+}
+
+func validateRemoteCluster(cluster *clientcmdapi.Cluster) error {
+	server, err := url.Parse(cluster.Server)
+	if err != nil {
+		return errors.Wrap(err, "invalid server")
+	}
+	if server.Scheme != "https" || server.Host == "" {
+		return errors.Errorf("server %q is not an https URL", cluster.Server)
+	}
+	if cluster.CertificateAuthority != "" {
+		return errors.New("certificate-authority files are not supported, use certificate-authority-data")
+	}
+	if cluster.ProxyURL != "" {
+		return errors.New("proxy-url is not supported")
+	}
+	return nil
+}
+
+func validateRemoteAuthInfo(authInfo *clientcmdapi.AuthInfo) error {
+	switch {
+	case authInfo.Exec != nil:
+		return errors.New("exec credential plugins are not supported")
+	case authInfo.AuthProvider != nil:
+		return errors.New("auth providers are not supported")
+	case authInfo.TokenFile != "":
+		return errors.New("tokenFile is not supported, use token")
+	case authInfo.ClientCertificate != "" || authInfo.ClientKey != "":
+		return errors.New("client-certificate and client-key files are not supported, use client-certificate-data and client-key-data")
+	case authInfo.Username != "" || authInfo.Password != "":
+		return errors.New("basic authentication is not supported")
+	case authInfo.Impersonate != "" || authInfo.ImpersonateUID != "" || len(authInfo.ImpersonateGroups) > 0 || len(authInfo.ImpersonateUserExtra) > 0:
+		return errors.New("impersonation is not supported")
+	}
+	hasCert := len(authInfo.ClientCertificateData) > 0 || len(authInfo.ClientKeyData) > 0
+	if hasCert && (len(authInfo.ClientCertificateData) == 0 || len(authInfo.ClientKeyData) == 0) {
+		return errors.New("client-certificate-data and client-key-data must be set together")
+	}
+	if authInfo.Token == "" && !hasCert {
+		return errors.New("a token or a client certificate is required")
+	}
+	return nil
+}
+
+// ValidateServiceDomain checks the service domain of a remote cluster is a DNS name, the names of the services in the
+// domain are added to their server certificates
+func ValidateServiceDomain(domain string) error {
+	if errs := validation.IsDNS1123Subdomain(domain); len(errs) > 0 {
+		return errors.Errorf("invalid service domain %q: %s", domain, strings.Join(errs, ", "))
+	}
+	return nil
+}
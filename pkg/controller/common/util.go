@@ -175,6 +175,10 @@ const (
 	AnnSecret = AnnAPIGroup + "/storage.import.secretName"
 	// AnnCertConfigMap is the name of a configmap containing tls certs
 	AnnCertConfigMap = AnnAPIGroup + "/storage.import.certConfigMap"
+	// AnnHostKeySecret is the name of a secret containing the host keys of an SFTP server
+	AnnHostKeySecret = AnnAPIGroup + "/storage.import.hostKeySecretName"
+	// AnnNFSClaimName is the name of the PVC bound to the NFS export of an NFS import
+	AnnNFSClaimName = AnnAPIGroup + "/storage.import.nfsClaimName"
 	// AnnRegistryImportMethod provides a const for registry import method annotation
 	AnnRegistryImportMethod = AnnAPIGroup + "/storage.import.registryImportMethod"
 	// AnnRegistryImageStream provides a const for registry image stream annotation
@@ -197,8 +201,26 @@ const (
 	AnnSecretExtraHeaders = AnnAPIGroup + "/storage.import.secretExtraHeaders"
 	// AnnChecksum provides a const for our PVC checksum annotation
 	AnnChecksum = AnnAPIGroup + "/storage.import.checksum"
+	// AnnChecksumURL provides a const for our PVC checksum file URL annotation
+	AnnChecksumURL = AnnAPIGroup + "/storage.import.checksumURL"
+	// AnnChecksumKeyringSecret provides a const for our PVC checksum file keyring secret annotation
+	AnnChecksumKeyringSecret = AnnAPIGroup + "/storage.import.checksumKeyringSecret"
+	// AnnSignaturePublicKeyConfigMap provides a const for our PVC image signature public key configmap annotation
+	AnnSignaturePublicKeyConfigMap = AnnAPIGroup + "/storage.import.signature.publicKeyConfigMap"
+	// AnnSignaturePublicKeySecret provides a const for our PVC image signature public key secret annotation
+	AnnSignaturePublicKeySecret = AnnAPIGroup + "/storage.import.signature.publicKeySecret"
+	// AnnSignatureTrustedRootConfigMap provides a const for our PVC keyless image signature trusted root configmap annotation
+	AnnSignatureTrustedRootConfigMap = AnnAPIGroup + "/storage.import.signature.trustedRootConfigMap"
+	// AnnSignatureIssuer provides a const for our PVC keyless image signature issuer annotation
+	AnnSignatureIssuer = AnnAPIGroup + "/storage.import.signature.issuer"
+	// AnnSignatureSubject provides a const for our PVC keyless image signature subject annotation
+	AnnSignatureSubject = AnnAPIGroup + "/storage.import.signature.subject"
 	// AnnRegistryImageArchitecture provides a const for our PVC registryImageArchitecture annotation
 	AnnRegistryImageArchitecture = AnnAPIGroup + "/storage.import.registryImageArchitecture"
+	// AnnDiskPath provides a const for our PVC diskPath annotation, selecting a disk of a registry image or an archive
+	AnnDiskPath = AnnAPIGroup + "/storage.import.diskPath"
+	// AnnDiskIndex provides a const for our PVC diskIndex annotation, selecting a disk of a registry image or an archive
+	AnnDiskIndex = AnnAPIGroup + "/storage.import.diskIndex"
 
 	// AnnCloneToken is the annotation containing the clone token
 	AnnCloneToken = AnnAPIGroup + "/storage.clone.token"
@@ -212,9 +234,32 @@ const (
 	AnnCloneType = AnnAPIGroup + "/cloneType"
 	// AnnCloneSourcePod name of the source clone pod
 	AnnCloneSourcePod = AnnAPIGroup + "/storage.sourceClonePodName"
+	// AnnCloneSourceKubeconfig is the name of the secret with the kubeconfig of the remote cluster of the clone source
+	AnnCloneSourceKubeconfig = AnnAPIGroup + "/storage.clone.sourceKubeconfig"
+	// AnnCloneSourceServiceDomain is the domain through which the remote cluster of the clone source reaches the local services
+	AnnCloneSourceServiceDomain = AnnAPIGroup + "/storage.clone.sourceServiceDomain"
+	// AnnCloneDeltaSync tells a clone into an existing block PVC to only send the blocks which differ from the PVC
+	AnnCloneDeltaSync = AnnAPIGroup + "/storage.clone.deltaSync"
+	// AnnCloneResize tells a clone target to fit the disk image of the clone source to its size
+	AnnCloneResize = AnnAPIGroup + "/storage.clone.resize"
+	// AnnCloneLogicalSectorSize is the logical sector size the clone target converts the disk image of the source to
+	AnnCloneLogicalSectorSize = AnnAPIGroup + "/storage.clone.logicalSectorSize"
 
 	// AnnUploadRequest marks that a PVC should be made available for upload
 	AnnUploadRequest = AnnAPIGroup + "/storage.upload.target"
+	// AnnUploadChecksum provides a const for the PVC annotation holding the validated checksum of the uploaded data
+	AnnUploadChecksum = AnnAPIGroup + "/storage.upload.checksum"
+	// AnnUploadTokensNotBefore provides a const for the PVC annotation revoking the tokens of the PVC issued before a time
+	AnnUploadTokensNotBefore = AnnAPIGroup + "/storage.upload.tokensNotBefore"
+
+	// AnnExportRequest marks that the contents of a PVC should be made available for export
+	AnnExportRequest = AnnAPIGroup + "/storage.export.source"
+	// AnnExportPod is the name of the export pod of a PVC
+	AnnExportPod = AnnAPIGroup + "/storage.export.podName"
+	// AnnExportPodPhase is a PVC annotation indicating the phase of the export pod
+	AnnExportPodPhase = AnnAPIGroup + "/storage.export.pod.phase"
+	// AnnExportPodReady tells whether the export pod is ready
+	AnnExportPodReady = AnnAPIGroup + "/storage.export.pod.ready"
 
 	// AnnCheckStaticVolume checks if a statically allocated PV exists before creating the target PVC.
 	// If so, PVC is still created but population is skipped
@@ -284,6 +329,10 @@ const (
 
 	// CloneSourceInUse is reason for event created when clone source pvc is in use
 	CloneSourceInUse = "CloneSourceInUse"
+	// ErrUnauthorizedCloneSource is reason for event created when the clone source pvc of a remote cluster can not be cloned
+	ErrUnauthorizedCloneSource = "ErrUnauthorizedCloneSource"
+	// RemoteCloneCleanupFailed is reason for event created when the clone source pod of a remote cluster could not be deleted
+	RemoteCloneCleanupFailed = "RemoteCloneCleanupFailed"
 
 	// CloneComplete message
 	CloneComplete = "Clone Complete"
@@ -310,6 +359,12 @@ const (
 	SourceS3 = "s3"
 	// SourceGCS is the source type GCS
 	SourceGCS = "gcs"
+	// SourceSFTP is the source type SFTP
+	SourceSFTP = "sftp"
+	// SourceSMB is the source type SMB
+	SourceSMB = "smb"
+	// SourceNFS is the source type NFS
+	SourceNFS = "nfs"
 	// SourceGlance is the source type of glance
 	SourceGlance = "glance"
 	// SourceNone means there is no source.
@@ -496,16 +551,16 @@ func (mtv *MultiTokenValidator) getTokenAndValidator(pvc *corev1.PersistentVolum
 }
 
 // NewMultiTokenValidator returns a new multi token validator
-func NewMultiTokenValidator(key *rsa.PublicKey) *MultiTokenValidator {
+func NewMultiTokenValidator(keys token.VerificationKeySource) *MultiTokenValidator {
 	return &MultiTokenValidator{
-		ShortTokenValidator: NewCloneTokenValidator(common.CloneTokenIssuer, key),
-		LongTokenValidator:  NewCloneTokenValidator(common.ExtendedCloneTokenIssuer, key),
+		ShortTokenValidator: NewCloneTokenValidator(common.CloneTokenIssuer, keys),
+		LongTokenValidator:  NewCloneTokenValidator(common.ExtendedCloneTokenIssuer, keys),
 	}
 }
 
 // NewCloneTokenValidator returns a new token validator
-func NewCloneTokenValidator(issuer string, key *rsa.PublicKey) token.Validator {
-	return token.NewValidator(issuer, key, cloneTokenLeeway)
+func NewCloneTokenValidator(issuer string, keys token.VerificationKeySource) token.Validator {
+	return token.NewValidatorForKeys(issuer, keys, cloneTokenLeeway)
 }
 
 // GetRequestedImageSize returns the PVC requested size
@@ -925,7 +980,8 @@ func HasFinalizer(object metav1.Object, value string) bool {
 
 // ValidateCloneTokenPVC validates clone token for source and target PVCs
 func ValidateCloneTokenPVC(t string, v token.Validator, source, target *corev1.PersistentVolumeClaim) error {
-	if source.Namespace == target.Namespace {
+	// a clone from a remote cluster always needs a token, even from a namespace of the same name
+	if source.Namespace == target.Namespace && GetCloneSourceCluster(target) == nil {
 		return nil
 	}
 
@@ -943,7 +999,7 @@ func ValidateCloneTokenPVC(t string, v token.Validator, source, target *corev1.P
 // ValidateCloneTokenDV validates clone token for DV
 func ValidateCloneTokenDV(validator token.Validator, dv *cdiv1.DataVolume) error {
 	_, sourceName, sourceNamespace := GetCloneSourceInfo(dv)
-	if sourceNamespace == "" || sourceNamespace == dv.Namespace {
+	if sourceNamespace == "" || (sourceNamespace == dv.Namespace && GetDataVolumeCloneSourceCluster(dv) == nil) {
 		return nil
 	}
 
@@ -1091,6 +1147,9 @@ func GetSource(pvc *corev1.PersistentVolumeClaim) string {
 		SourceHTTP,
 		SourceS3,
 		SourceGCS,
+		SourceSFTP,
+		SourceSMB,
+		SourceNFS,
 		SourceGlance,
 		SourceNone,
 		SourceRegistry,
@@ -1756,6 +1815,13 @@ func UpdateHTTPAnnotations(annotations map[string]string, http *cdiv1.DataVolume
 	if http.Checksum != "" {
 		annotations[AnnChecksum] = http.Checksum
 	}
+	if http.ChecksumURL != "" {
+		annotations[AnnChecksumURL] = http.ChecksumURL
+	}
+	if http.ChecksumKeyringSecretRef != "" {
+		annotations[AnnChecksumKeyringSecret] = http.ChecksumKeyringSecretRef
+	}
+	updateDiskSelectionAnnotations(annotations, http.DiskPath, http.DiskIndex)
 	if http.InsecureSkipVerify != nil && *http.InsecureSkipVerify {
 		annotations[AnnInsecureSkipVerify] = "true"
 	}
@@ -1767,6 +1833,17 @@ func UpdateHTTPAnnotations(annotations map[string]string, http *cdiv1.DataVolume
 	}
 }
 
+// updateDiskSelectionAnnotations updates the passed annotations with the disk selected from a registry image or an
+// archive
+func updateDiskSelectionAnnotations(annotations map[string]string, diskPath *string, diskIndex *int32) {
+	if diskPath != nil && *diskPath != "" {
+		annotations[AnnDiskPath] = *diskPath
+	}
+	if diskIndex != nil {
+		annotations[AnnDiskIndex] = strconv.Itoa(int(*diskIndex))
+	}
+}
+
 // UpdateS3Annotations updates the passed annotations for proper S3 import
 func UpdateS3Annotations(annotations map[string]string, s3 *cdiv1.DataVolumeSourceS3) {
 	annotations[AnnEndpoint] = s3.URL
@@ -1777,6 +1854,9 @@ func UpdateS3Annotations(annotations map[string]string, s3 *cdiv1.DataVolumeSour
 	if s3.CertConfigMap != "" {
 		annotations[AnnCertConfigMap] = s3.CertConfigMap
 	}
+	if s3.Checksum != "" {
+		annotations[AnnChecksum] = s3.Checksum
+	}
 }
 
 // UpdateGCSAnnotations updates the passed annotations for proper GCS import
@@ -1786,6 +1866,42 @@ func UpdateGCSAnnotations(annotations map[string]string, gcs *cdiv1.DataVolumeSo
 	if gcs.SecretRef != "" {
 		annotations[AnnSecret] = gcs.SecretRef
 	}
+	if gcs.Checksum != "" {
+		annotations[AnnChecksum] = gcs.Checksum
+	}
+}
+
+// UpdateSFTPAnnotations updates the passed annotations for proper SFTP import
+func UpdateSFTPAnnotations(annotations map[string]string, sftp *cdiv1.DataVolumeSourceSFTP) {
+	annotations[AnnEndpoint] = sftp.URL
+	annotations[AnnSource] = SourceSFTP
+	annotations[AnnSecret] = sftp.SecretRef
+	annotations[AnnHostKeySecret] = sftp.HostKeySecretRef
+	if sftp.Checksum != "" {
+		annotations[AnnChecksum] = sftp.Checksum
+	}
+}
+
+// UpdateSMBAnnotations updates the passed annotations for proper SMB import
+func UpdateSMBAnnotations(annotations map[string]string, smb *cdiv1.DataVolumeSourceSMB) {
+	annotations[AnnEndpoint] = smb.URL
+	annotations[AnnSource] = SourceSMB
+	if smb.SecretRef != "" {
+		annotations[AnnSecret] = smb.SecretRef
+	}
+	if smb.Checksum != "" {
+		annotations[AnnChecksum] = smb.Checksum
+	}
+}
+
+// UpdateNFSAnnotations updates the passed annotations for proper NFS import
+func UpdateNFSAnnotations(annotations map[string]string, nfs *cdiv1.DataVolumeSourceNFS) {
+	annotations[AnnEndpoint] = nfs.Path
+	annotations[AnnSource] = SourceNFS
+	annotations[AnnNFSClaimName] = nfs.ClaimName
+	if nfs.Checksum != "" {
+		annotations[AnnChecksum] = nfs.Checksum
+	}
 }
 
 // UpdateRegistryAnnotations updates the passed annotations for proper registry import
@@ -1817,6 +1933,24 @@ func UpdateRegistryAnnotations(annotations map[string]string, registry *cdiv1.Da
 	if registry.Platform != nil && registry.Platform.Architecture != "" {
 		annotations[AnnRegistryImageArchitecture] = registry.Platform.Architecture
 	}
+
+	updateDiskSelectionAnnotations(annotations, registry.DiskPath, registry.DiskIndex)
+
+	if sv := registry.SignatureVerification; sv != nil {
+		if sv.PublicKey != nil {
+			if sv.PublicKey.ConfigMapRef != nil && *sv.PublicKey.ConfigMapRef != "" {
+				annotations[AnnSignaturePublicKeyConfigMap] = *sv.PublicKey.ConfigMapRef
+			}
+			if sv.PublicKey.SecretRef != nil && *sv.PublicKey.SecretRef != "" {
+				annotations[AnnSignaturePublicKeySecret] = *sv.PublicKey.SecretRef
+			}
+		}
+		if sv.Keyless != nil {
+			annotations[AnnSignatureTrustedRootConfigMap] = sv.Keyless.TrustedRootConfigMapRef
+			annotations[AnnSignatureIssuer] = sv.Keyless.Issuer
+			annotations[AnnSignatureSubject] = sv.Keyless.Subject
+		}
+	}
 }
 
 // UpdateVDDKAnnotations updates the passed annotations for proper VDDK import
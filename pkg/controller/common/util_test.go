@@ -2,6 +2,7 @@ package common
 
 import (
 	"context"
+	"encoding/base64"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -12,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -56,6 +58,37 @@ var _ = Describe("UpdateHTTPAnnotations", func() {
 		Expect(exists).To(BeFalse())
 	})
 
+	It("Should set AnnChecksumURL when DataVolumeSourceHTTP.ChecksumURL is set", func() {
+		annotations := map[string]string{}
+		UpdateHTTPAnnotations(annotations, &cdiv1.DataVolumeSourceHTTP{
+			URL:         "http://example.com/disk.img",
+			ChecksumURL: "http://example.com/SHA256SUMS",
+		})
+		Expect(annotations[AnnChecksumURL]).To(Equal("http://example.com/SHA256SUMS"))
+		_, exists := annotations[AnnChecksum]
+		Expect(exists).To(BeFalse())
+	})
+
+	It("Should set AnnChecksumKeyringSecret when DataVolumeSourceHTTP.ChecksumKeyringSecretRef is set", func() {
+		annotations := map[string]string{}
+		UpdateHTTPAnnotations(annotations, &cdiv1.DataVolumeSourceHTTP{
+			URL:                      "http://example.com/disk.img",
+			ChecksumURL:              "http://example.com/CHECKSUM",
+			ChecksumKeyringSecretRef: "keyring",
+		})
+		Expect(annotations[AnnChecksumKeyringSecret]).To(Equal("keyring"))
+	})
+
+	It("Should set the disk selection annotations when DataVolumeSourceHTTP selects a disk", func() {
+		annotations := map[string]string{}
+		UpdateHTTPAnnotations(annotations, &cdiv1.DataVolumeSourceHTTP{
+			URL:       "http://example.com/appliance.ova",
+			DiskIndex: ptr.To[int32](1),
+		})
+		Expect(annotations[AnnDiskIndex]).To(Equal("1"))
+		Expect(annotations).ToNot(HaveKey(AnnDiskPath))
+	})
+
 	It("Should not set AnnInsecureSkipVerify when DataVolumeSourceHTTP.InsecureSkipVerify is absent", func() {
 		annotations := map[string]string{}
 		UpdateHTTPAnnotations(annotations, &cdiv1.DataVolumeSourceHTTP{
@@ -66,6 +99,287 @@ var _ = Describe("UpdateHTTPAnnotations", func() {
 	})
 })
 
+var _ = Describe("Update object storage annotations", func() {
+	It("Should set AnnChecksum when DataVolumeSourceS3.Checksum is set", func() {
+		annotations := map[string]string{}
+		UpdateS3Annotations(annotations, &cdiv1.DataVolumeSourceS3{
+			URL:      "http://s3.example.com/bucket/disk.img",
+			Checksum: "md5:5d41402abc4b2a76b9719d911017c592",
+		})
+		Expect(annotations[AnnChecksum]).To(Equal("md5:5d41402abc4b2a76b9719d911017c592"))
+	})
+
+	It("Should set AnnChecksum when DataVolumeSourceGCS.Checksum is set", func() {
+		annotations := map[string]string{}
+		UpdateGCSAnnotations(annotations, &cdiv1.DataVolumeSourceGCS{
+			URL:      "gs://bucket/disk.img",
+			Checksum: "md5:5d41402abc4b2a76b9719d911017c592",
+		})
+		Expect(annotations[AnnChecksum]).To(Equal("md5:5d41402abc4b2a76b9719d911017c592"))
+	})
+
+	It("Should not set AnnChecksum when no checksum is set", func() {
+		annotations := map[string]string{}
+		UpdateS3Annotations(annotations, &cdiv1.DataVolumeSourceS3{URL: "http://s3.example.com/bucket/disk.img"})
+		UpdateGCSAnnotations(annotations, &cdiv1.DataVolumeSourceGCS{URL: "gs://bucket/disk.img"})
+		_, exists := annotations[AnnChecksum]
+		Expect(exists).To(BeFalse())
+	})
+})
+
+var _ = Describe("Update registry signature annotations", func() {
+	It("Should set the public key annotations", func() {
+		annotations := map[string]string{}
+		UpdateRegistryAnnotations(annotations, &cdiv1.DataVolumeSourceRegistry{
+			URL: ptr.To("docker://registry:5000/test"),
+			SignatureVerification: &cdiv1.RegistrySignatureVerification{
+				PublicKey: &cdiv1.SignaturePublicKey{SecretRef: ptr.To("cosign-key")},
+			},
+		})
+		Expect(annotations[AnnSignaturePublicKeySecret]).To(Equal("cosign-key"))
+		Expect(annotations).ToNot(HaveKey(AnnSignaturePublicKeyConfigMap))
+		Expect(annotations).ToNot(HaveKey(AnnSignatureTrustedRootConfigMap))
+	})
+
+	It("Should set the keyless annotations", func() {
+		annotations := map[string]string{}
+		UpdateRegistryAnnotations(annotations, &cdiv1.DataVolumeSourceRegistry{
+			URL: ptr.To("docker://registry:5000/test"),
+			SignatureVerification: &cdiv1.RegistrySignatureVerification{
+				Keyless: &cdiv1.SignatureKeyless{
+					TrustedRootConfigMapRef: "sigstore-root",
+					Issuer:                  "https://accounts.example.com",
+					Subject:                 "signer@example.com",
+				},
+			},
+		})
+		Expect(annotations[AnnSignatureTrustedRootConfigMap]).To(Equal("sigstore-root"))
+		Expect(annotations[AnnSignatureIssuer]).To(Equal("https://accounts.example.com"))
+		Expect(annotations[AnnSignatureSubject]).To(Equal("signer@example.com"))
+	})
+
+	It("Should not set signature annotations without signature verification", func() {
+		annotations := map[string]string{}
+		UpdateRegistryAnnotations(annotations, &cdiv1.DataVolumeSourceRegistry{URL: ptr.To("docker://registry:5000/test")})
+		for key := range annotations {
+			Expect(key).ToNot(HavePrefix(AnnAPIGroup + "/storage.import.signature"))
+		}
+	})
+})
+
+var _ = Describe("Update registry disk selection annotations", func() {
+	It("Should set the disk path annotation", func() {
+		annotations := map[string]string{}
+		UpdateRegistryAnnotations(annotations, &cdiv1.DataVolumeSourceRegistry{
+			URL:      ptr.To("docker://registry:5000/test"),
+			DiskPath: ptr.To("data.qcow2"),
+		})
+		Expect(annotations[AnnDiskPath]).To(Equal("data.qcow2"))
+		Expect(annotations).ToNot(HaveKey(AnnDiskIndex))
+	})
+
+	It("Should set the disk index annotation", func() {
+		annotations := map[string]string{}
+		UpdateRegistryAnnotations(annotations, &cdiv1.DataVolumeSourceRegistry{
+			URL:       ptr.To("docker://registry:5000/test"),
+			DiskIndex: ptr.To[int32](0),
+		})
+		Expect(annotations[AnnDiskIndex]).To(Equal("0"))
+		Expect(annotations).ToNot(HaveKey(AnnDiskPath))
+	})
+})
+
+var _ = Describe("CloneSourceCluster", func() {
+	const kubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: remote
+  cluster:
+    server: https://remote.example.com:6443
+contexts:
+- name: remote
+  context:
+    cluster: remote
+    user: remote
+current-context: remote
+users:
+- name: remote
+  user:
+    token: remote-token
+`
+
+	createKubeconfigSecret := func(name string, data map[string][]byte) *v1.Secret {
+		return &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "default",
+				ResourceVersion: "1",
+			},
+			Data: data,
+		}
+	}
+
+	It("Should round trip the remote cluster through the claim annotations", func() {
+		pvc := CreatePvc("testPVC", "default", nil, nil)
+		Expect(GetCloneSourceCluster(pvc)).To(BeNil())
+
+		SetCloneSourceCluster(pvc, &cdiv1.CloneSourceCluster{KubeconfigSecretRef: "remote", ServiceDomain: "remote.local"})
+		Expect(pvc.Annotations[AnnCloneSourceKubeconfig]).To(Equal("remote"))
+		Expect(pvc.Annotations[AnnCloneSourceServiceDomain]).To(Equal("remote.local"))
+		Expect(GetCloneSourceCluster(pvc)).To(Equal(&cdiv1.CloneSourceCluster{KubeconfigSecretRef: "remote", ServiceDomain: "remote.local"}))
+	})
+
+	It("Should return the local client for a local source", func() {
+		c := CreateClient()
+		sourceClient, err := GetCloneSourceClient(context.Background(), c, "default", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sourceClient).To(BeIdenticalTo(c))
+	})
+
+	It("Should fail if the kubeconfig secret does not exist", func() {
+		_, err := GetCloneSourceClient(context.Background(), CreateClient(), "default", &cdiv1.CloneSourceCluster{KubeconfigSecretRef: "missing"})
+		Expect(err).To(HaveOccurred())
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should fail if the kubeconfig secret has no kubeconfig key", func() {
+		c := CreateClient(createKubeconfigSecret("no-key", map[string][]byte{"config": []byte(kubeconfig)}))
+		_, err := GetCloneSourceClient(context.Background(), c, "default", &cdiv1.CloneSourceCluster{KubeconfigSecretRef: "no-key"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("has no kubeconfig key"))
+	})
+
+	It("Should build and cache the client of the remote cluster", func() {
+		c := CreateClient(createKubeconfigSecret("remote", map[string][]byte{KubeconfigSecretKey: []byte(kubeconfig)}))
+		cluster := &cdiv1.CloneSourceCluster{KubeconfigSecretRef: "remote"}
+		sourceClient, err := GetCloneSourceClient(context.Background(), c, "default", cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sourceClient).ToNot(BeIdenticalTo(c))
+
+		cached, err := GetCloneSourceClient(context.Background(), c, "default", cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cached).To(BeIdenticalTo(sourceClient))
+	})
+
+	It("Should evict the client of the remote cluster once its secret is gone", func() {
+		secret := createKubeconfigSecret("evicted", map[string][]byte{KubeconfigSecretKey: []byte(kubeconfig)})
+		c := CreateClient(secret)
+		cluster := &cdiv1.CloneSourceCluster{KubeconfigSecretRef: "evicted"}
+		_, err := GetCloneSourceClient(context.Background(), c, "default", cluster)
+		Expect(err).ToNot(HaveOccurred())
+		key := types.NamespacedName{Namespace: "default", Name: "evicted"}
+		Expect(remoteClusterClients).To(HaveKey(key))
+
+		Expect(c.Delete(context.Background(), secret)).To(Succeed())
+		_, err = GetCloneSourceClient(context.Background(), c, "default", cluster)
+		Expect(err).To(HaveOccurred())
+		Expect(remoteClusterClients).ToNot(HaveKey(key))
+	})
+
+	It("Should evict the clients not used anymore", func() {
+		key := types.NamespacedName{Namespace: "default", Name: "unused"}
+		remoteClusterClientsLock.Lock()
+		remoteClusterClients[key] = remoteClusterClient{lastUsed: time.Now().Add(-remoteClusterClientTTL - time.Minute)}
+		remoteClusterClientsLock.Unlock()
+
+		_, err := GetCloneSourceClient(context.Background(), CreateClient(), "default", &cdiv1.CloneSourceCluster{KubeconfigSecretRef: "missing"})
+		Expect(err).To(HaveOccurred())
+		Expect(remoteClusterClients).ToNot(HaveKey(key))
+	})
+})
+
+var _ = Describe("RemoteClusterRESTConfig", func() {
+	kubeconfig := func(cluster, user string) []byte {
+		return []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: remote
+  cluster:
+` + cluster + `
+contexts:
+- name: remote
+  context:
+    cluster: remote
+    user: remote
+current-context: remote
+users:
+- name: remote
+  user:
+` + user + `
+`)
+	}
+	const server = "    server: https://remote.example.com:6443"
+	certData := base64.StdEncoding.EncodeToString([]byte("cert"))
+	keyData := base64.StdEncoding.EncodeToString([]byte("key"))
+
+	It("Should build the config with an inline token", func() {
+		config, err := RemoteClusterRESTConfig(kubeconfig(server+"\n    certificate-authority-data: "+certData, "    token: remote-token"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.Host).To(Equal("https://remote.example.com:6443"))
+		Expect(config.BearerToken).To(Equal("remote-token"))
+		Expect(config.CAData).To(Equal([]byte("cert")))
+		Expect(config.ExecProvider).To(BeNil())
+		Expect(config.AuthProvider).To(BeNil())
+		Expect(config.BearerTokenFile).To(BeEmpty())
+	})
+
+	It("Should build the config with an inline client certificate", func() {
+		config, err := RemoteClusterRESTConfig(kubeconfig(server, "    client-certificate-data: "+certData+"\n    client-key-data: "+keyData))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.CertData).To(Equal([]byte("cert")))
+		Expect(config.KeyData).To(Equal([]byte("key")))
+	})
+
+	DescribeTable("Should refuse", func(cluster, user, message string) {
+		_, err := RemoteClusterRESTConfig(kubeconfig(cluster, user))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(message))
+	},
+		Entry("an exec credential plugin", server, "    exec:\n      apiVersion: client.authentication.k8s.io/v1\n      command: /bin/sh", "exec credential plugins"),
+		Entry("an auth provider", server, "    auth-provider:\n      name: oidc", "auth providers"),
+		Entry("a token file", server, "    tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token", "tokenFile"),
+		Entry("a client certificate file", server, "    client-certificate: /etc/cert\n    client-key-data: "+keyData, "client-certificate and client-key files"),
+		Entry("a client key file", server, "    client-certificate-data: "+certData+"\n    client-key: /etc/key", "client-certificate and client-key files"),
+		Entry("a certificate authority file", server+"\n    certificate-authority: /etc/ca.crt", "    token: remote-token", "certificate-authority files"),
+		Entry("a proxy", server+"\n    proxy-url: http://attacker.example.com", "    token: remote-token", "proxy-url"),
+		Entry("basic authentication", server, "    username: admin\n    password: secret", "basic authentication"),
+		Entry("impersonation", server, "    token: remote-token\n    as: system:admin", "impersonation"),
+		Entry("a plain http server", "    server: http://remote.example.com", "    token: remote-token", "not an https URL"),
+		Entry("missing credentials", server, "    {}", "a token or a client certificate is required"),
+	)
+})
+
+var _ = Describe("ValidateServiceDomain", func() {
+	It("Should accept a DNS domain", func() {
+		Expect(ValidateServiceDomain("clusterset.local")).To(Succeed())
+	})
+
+	It("Should refuse names which are not DNS domains", func() {
+		Expect(ValidateServiceDomain("*.example.com")).ToNot(Succeed())
+		Expect(ValidateServiceDomain("example.com,evil.com")).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("CloneTransformation", func() {
+	It("Should round trip the transformation through the claim annotations", func() {
+		pvc := CreatePvc("testPVC", "default", nil, nil)
+		Expect(GetCloneTransformation(pvc)).To(BeNil())
+
+		transformation := &cdiv1.CloneTransformation{Resize: true, LogicalSectorSize: ptr.To[int32](4096)}
+		SetCloneTransformation(pvc, transformation)
+		Expect(pvc.Annotations[AnnCloneResize]).To(Equal("true"))
+		Expect(pvc.Annotations[AnnCloneLogicalSectorSize]).To(Equal("4096"))
+		Expect(GetCloneTransformation(pvc)).To(Equal(transformation))
+	})
+
+	It("Should only annotate the requested changes", func() {
+		pvc := CreatePvc("testPVC", "default", nil, nil)
+		SetCloneTransformation(pvc, &cdiv1.CloneTransformation{LogicalSectorSize: ptr.To[int32](512)})
+		Expect(pvc.Annotations).ToNot(HaveKey(AnnCloneResize))
+		Expect(GetCloneTransformation(pvc)).To(Equal(&cdiv1.CloneTransformation{LogicalSectorSize: ptr.To[int32](512)}))
+	})
+})
+
 var _ = Describe("GetStorageClassByName", func() {
 	It("Should return the default storage class name", func() {
 		client := CreateClient(
@@ -1113,10 +1113,16 @@ func (r *DataImportCronReconciler) handleSnapshotClassChange(ctx context.Context
 
 // getSnapshotClassForDataImportCron returns the VolumeSnapshotClass name to use for DataImportCron snapshots.
 func (r *DataImportCronReconciler) getSnapshotClassForDataImportCron(pvc *corev1.PersistentVolumeClaim, storageProfile *cdiv1.StorageProfile) (string, error) {
+	return getSnapshotClassForBootSource(pvc, storageProfile, r.log, r.client, r.recorder)
+}
+
+// getSnapshotClassForBootSource returns the VolumeSnapshotClass name to use for the snapshots of boot sources,
+// which are created by DataImportCrons and uploads to DataSources.
+func getSnapshotClassForBootSource(pvc *corev1.PersistentVolumeClaim, storageProfile *cdiv1.StorageProfile, log logr.Logger, c client.Client, recorder record.EventRecorder) (string, error) {
 	if vscName := storageProfile.Annotations[cc.AnnSnapshotClassForDataImportCron]; vscName != "" {
 		return vscName, nil
 	}
-	return cc.GetSnapshotClassForSmartClone(pvc, &storageProfile.Name, storageProfile.Status.SnapshotClass, r.log, r.client, r.recorder)
+	return cc.GetSnapshotClassForSmartClone(pvc, &storageProfile.Name, storageProfile.Status.SnapshotClass, log, c, recorder)
 }
 
 func (r *DataImportCronReconciler) updateDataImportCronSuccessCondition(dataImportCron *cdiv1.DataImportCron, format cdiv1.DataImportCronSourceFormat, snapshot *snapshotv1.VolumeSnapshot) error {
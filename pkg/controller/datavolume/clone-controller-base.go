@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -109,6 +110,10 @@ const (
 	CloneWithoutSource = "CloneWithoutSource"
 	// MessageCloneWithoutSource reports that the source of a clone doesn't exists (message)
 	MessageCloneWithoutSource = "The source %s %s doesn't exist"
+	// DeltaSyncUnsupported reports that the changed blocks can not be synced into the existing PVC (reason)
+	DeltaSyncUnsupported = "DeltaSyncUnsupported"
+	// MessageDeltaSyncUnsupported reports that the changed blocks can not be synced into the existing PVC (message)
+	MessageDeltaSyncUnsupported = "Only the changed blocks of a PersistentVolumeClaim in block mode can be synced, %s is not"
 	// PrepClaimInProgress is const representing target PVC prep
 	PrepClaimInProgress = "PrepClaimInProgress"
 	// MessagePrepClaimInProgress is a const for reporting target prep
@@ -145,6 +150,7 @@ type CloneReconcilerBase struct {
 	shortTokenValidator token.Validator
 	longTokenValidator  token.Validator
 	tokenGenerator      token.Generator
+	tokenKeys           token.SigningKeySource
 }
 
 func (r *CloneReconcilerBase) addVolumeCloneSourceWatch(mgr manager.Manager, datavolumeController controller.Controller) error {
@@ -221,7 +227,7 @@ func (r *CloneReconcilerBase) ensureExtendedTokenDV(dv *cdiv1.DataVolume) (bool,
 
 	_, ok := dv.Annotations[cc.AnnExtendedCloneToken]
 	if ok {
-		return false, nil
+		return r.reissueExtendedToken(dv)
 	}
 
 	token, ok := dv.Annotations[cc.AnnCloneToken]
@@ -239,7 +245,7 @@ func (r *CloneReconcilerBase) ensureExtendedTokenDV(dv *cdiv1.DataVolume) (bool,
 	}
 	payload.Params["uid"] = string(dv.UID)
 
-	newToken, err := r.tokenGenerator.Generate(payload)
+	newToken, _, err := r.tokenGenerator.Generate(payload)
 	if err != nil {
 		return false, err
 	}
@@ -249,6 +255,26 @@ func (r *CloneReconcilerBase) ensureExtendedTokenDV(dv *cdiv1.DataVolume) (bool,
 	return true, nil
 }
 
+// validateHostAssistedClone fails a clone which can only be host-assisted when the clone strategy of the DataVolume
+// doesn't accept it
+func (r *CloneReconcilerBase) validateHostAssistedClone(syncState *dvSyncState) error {
+	dv := syncState.dvMutated
+	if clone.HostAssistedAllowed(dv.Spec.CloneStrategy) {
+		return nil
+	}
+
+	message := fmt.Sprintf(clone.MessageNoCloneStrategy, NoPopulatorMessage)
+	if err := r.syncDataVolumeStatusPhaseWithEvent(syncState, cdiv1.Pending, nil,
+		Event{
+			eventType: corev1.EventTypeWarning,
+			reason:    clone.NoCloneStrategy,
+			message:   message,
+		}); err != nil {
+		r.log.Error(err, "failed to sync DataVolume status with event")
+	}
+	return errors.New(message)
+}
+
 func (r *CloneReconcilerBase) fallbackToHostAssisted(pvc *corev1.PersistentVolumeClaim) error {
 	pvcCpy := pvc.DeepCopy()
 
@@ -272,7 +298,10 @@ func (r *CloneReconcilerBase) ensureExtendedTokenPVC(dv *cdiv1.DataVolume, pvc *
 
 	_, ok := pvc.Annotations[cc.AnnExtendedCloneToken]
 	if ok {
-		return nil
+		if reissued, err := r.reissueExtendedToken(pvc); err != nil || !reissued {
+			return err
+		}
+		return r.updatePVC(pvc)
 	}
 
 	token, ok := dv.Annotations[cc.AnnExtendedCloneToken]
@@ -292,7 +321,7 @@ func (r *CloneReconcilerBase) ensureExtendedTokenPVC(dv *cdiv1.DataVolume, pvc *
 	// now use pvc uid
 	payload.Params["uid"] = string(pvc.UID)
 
-	newToken, err := r.tokenGenerator.Generate(payload)
+	newToken, _, err := r.tokenGenerator.Generate(payload)
 	if err != nil {
 		return err
 	}
@@ -306,6 +335,41 @@ func (r *CloneReconcilerBase) ensureExtendedTokenPVC(dv *cdiv1.DataVolume, pvc *
 	return nil
 }
 
+// reissueExtendedToken signs the extended token of the object again with the current signing key when it was signed
+// with a key being rotated out. Extended tokens outlive the overlap of a key rotation, so they are moved to the new key
+// while the previous one is still trusted. The reissued token keeps the expiry of the original token.
+func (r *CloneReconcilerBase) reissueExtendedToken(obj metav1.Object) (bool, error) {
+	tok := obj.GetAnnotations()[cc.AnnExtendedCloneToken]
+	kid, err := token.KeyID(tok)
+	if err != nil {
+		// not a signed token, it is rejected when validated
+		return false, nil
+	}
+
+	key, err := r.tokenKeys.SigningKey()
+	if err != nil {
+		return false, err
+	}
+	if kid == key.KeyID {
+		return false, nil
+	}
+
+	payload, err := r.longTokenValidator.Validate(tok)
+	if err != nil {
+		return false, err
+	}
+	payload.Lifetime = time.Until(payload.Expiry)
+
+	newToken, _, err := r.tokenGenerator.Generate(payload)
+	if err != nil {
+		return false, err
+	}
+
+	obj.GetAnnotations()[cc.AnnExtendedCloneToken] = newToken
+
+	return true, nil
+}
+
 func (r *CloneReconcilerBase) reconcileVolumeCloneSourceCR(syncState *dvSyncState) error {
 	dv := syncState.dvMutated
 	volumeCloneSource := &cdiv1.VolumeCloneSource{}
@@ -345,6 +409,7 @@ func (r *CloneReconcilerBase) reconcileVolumeCloneSourceCR(syncState *dvSyncStat
 				Name:     sourceName,
 			},
 			Preallocation: dv.Spec.Preallocation,
+			CloneStrategy: dv.Spec.CloneStrategy,
 		},
 	}
 
@@ -536,7 +601,8 @@ func (r *CloneReconcilerBase) populateSourceIfSourceRef(dv *cdiv1.DataVolume) er
 func isCrossNamespaceClone(dv *cdiv1.DataVolume) bool {
 	_, _, sourceNamespace := cc.GetCloneSourceInfo(dv)
 
-	return sourceNamespace != "" && sourceNamespace != dv.Namespace
+	// a clone from a remote cluster is authorized like a cross namespace clone
+	return sourceNamespace != "" && (sourceNamespace != dv.Namespace || cc.GetDataVolumeCloneSourceCluster(dv) != nil)
 }
 
 // addCloneWithoutSourceWatch reconciles clones created without source once the matching PVC is created
@@ -24,13 +24,18 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/controller/clone"
 	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	"kubevirt.io/containerized-data-importer/pkg/controller/populators"
 )
 
 const (
 	transferRunning = "TransferRunning"
 	pvcBound        = "Bound"
 	pvcPending      = "Pending"
+
+	cloneStrategyPreferred = "PreferredStrategy"
+	cloneStrategyFallback  = "FallbackStrategy"
 )
 
 // FindConditionByType finds condition by type
@@ -119,6 +124,19 @@ func updateWithTargetNotRunning(conditions []cdiv1.DataVolumeCondition, anno map
 	return conditions
 }
 
+// updateCloneStrategyCondition reports the strategy of a clone, and why the preferred ones were not possible
+func updateCloneStrategyCondition(conditions []cdiv1.DataVolumeCondition, anno map[string]string) []cdiv1.DataVolumeCondition {
+	strategy, ok := anno[cc.AnnCloneType]
+	if !ok {
+		return conditions
+	}
+	if reason, ok := anno[populators.AnnCloneFallbackReason]; ok {
+		message := fmt.Sprintf("Cloning with strategy %s, the preferred strategies are not possible: %s", strategy, reason)
+		return updateCondition(conditions, cdiv1.DataVolumeCloneStrategy, corev1.ConditionTrue, message, cloneStrategyFallback)
+	}
+	return updateCondition(conditions, cdiv1.DataVolumeCloneStrategy, corev1.ConditionTrue, fmt.Sprintf(clone.MessageCloneStrategyChosen, strategy), cloneStrategyPreferred)
+}
+
 // UpdateReadyCondition updates the ready condition
 func UpdateReadyCondition(conditions []cdiv1.DataVolumeCondition, status corev1.ConditionStatus, message, reason string) []cdiv1.DataVolumeCondition {
 	return updateCondition(conditions, cdiv1.DataVolumeReady, status, message, reason)
@@ -24,6 +24,7 @@ import (
 
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	. "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	"kubevirt.io/containerized-data-importer/pkg/controller/populators"
 )
 
 var _ = Describe("findConditionByType", func() {
@@ -132,6 +133,37 @@ var _ = Describe("updateReadyCondition", func() {
 	})
 })
 
+var _ = Describe("updateCloneStrategyCondition", func() {
+	It("should not create condition if no strategy was chosen", func() {
+		conditions := make([]cdiv1.DataVolumeCondition, 0)
+		conditions = updateCloneStrategyCondition(conditions, map[string]string{})
+		Expect(conditions).To(BeEmpty())
+	})
+
+	It("should create condition with the preferred strategy", func() {
+		conditions := make([]cdiv1.DataVolumeCondition, 0)
+		conditions = updateCloneStrategyCondition(conditions, map[string]string{AnnCloneType: "snapshot"})
+		Expect(conditions).To(HaveLen(1))
+		Expect(conditions[0].Type).To(Equal(cdiv1.DataVolumeCloneStrategy))
+		Expect(conditions[0].Message).To(Equal("Cloning with strategy snapshot"))
+		Expect(conditions[0].Reason).To(Equal(cloneStrategyPreferred))
+		Expect(conditions[0].Status).To(Equal(corev1.ConditionTrue))
+	})
+
+	It("should create condition with the reason of the fallback", func() {
+		conditions := make([]cdiv1.DataVolumeCondition, 0)
+		conditions = updateCloneStrategyCondition(conditions, map[string]string{
+			AnnCloneType:                      "copy",
+			populators.AnnCloneFallbackReason: "csi-clone: Provisioners are incompatible",
+		})
+		Expect(conditions).To(HaveLen(1))
+		Expect(conditions[0].Type).To(Equal(cdiv1.DataVolumeCloneStrategy))
+		Expect(conditions[0].Message).To(Equal("Cloning with strategy copy, the preferred strategies are not possible: csi-clone: Provisioners are incompatible"))
+		Expect(conditions[0].Reason).To(Equal(cloneStrategyFallback))
+		Expect(conditions[0].Status).To(Equal(corev1.ConditionTrue))
+	})
+})
+
 var _ = Describe("updateBoundCondition", func() {
 	It("should create condition if it doesn't exist", func() {
 		conditions := make([]cdiv1.DataVolumeCondition, 0)
@@ -18,7 +18,6 @@ package datavolume
 
 import (
 	"context"
-	"crypto/rsa"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -385,7 +384,7 @@ func getDataVolumeOp(ctx context.Context, log logr.Logger, dv *cdiv1.DataVolume,
 	if src.Upload != nil {
 		return dataVolumeUpload
 	}
-	if src.HTTP != nil || src.S3 != nil || src.GCS != nil || src.Registry != nil || src.Blank != nil || src.Imageio != nil || src.VDDK != nil {
+	if src.HTTP != nil || src.S3 != nil || src.GCS != nil || src.SFTP != nil || src.SMB != nil || src.NFS != nil || src.Registry != nil || src.Blank != nil || src.Imageio != nil || src.VDDK != nil {
 		return dataVolumeImport
 	}
 
@@ -1040,6 +1039,7 @@ func (r *ReconcilerBase) updateConditions(dataVolume *cdiv1.DataVolume, pvc *cor
 	dataVolume.Status.Conditions = updateBoundCondition(dataVolume.Status.Conditions, pvc, message, reason)
 	dataVolume.Status.Conditions = UpdateReadyCondition(dataVolume.Status.Conditions, readyStatus, message, reason)
 	dataVolume.Status.Conditions = updateRunningCondition(dataVolume.Status.Conditions, anno)
+	dataVolume.Status.Conditions = updateCloneStrategyCondition(dataVolume.Status.Conditions, anno)
 }
 
 func (r *ReconcilerBase) emitConditionEvent(dataVolume *cdiv1.DataVolume, originalCond []cdiv1.DataVolumeCondition) {
@@ -1232,8 +1232,8 @@ func (r *ReconcilerBase) updatePVC(pvc *corev1.PersistentVolumeClaim) error {
 	return r.client.Update(context.TODO(), pvc)
 }
 
-func newLongTermCloneTokenGenerator(key *rsa.PrivateKey) token.Generator {
-	return token.NewGenerator(common.ExtendedCloneTokenIssuer, key, 10*365*24*time.Hour)
+func newLongTermCloneTokenGenerator(keys token.SigningKeySource) token.Generator {
+	return token.NewGeneratorForKeys(common.ExtendedCloneTokenIssuer, keys, 10*365*24*time.Hour)
 }
 
 // storageClassWaitForFirstConsumer returns if the binding mode of a given storage class is WFFC
@@ -1313,8 +1313,17 @@ func (r *ReconcilerBase) handlePvcCreation(log logr.Logger, syncState *dvSyncSta
 // Currently it will use populators only if:
 // * storageClass used is CSI storageClass
 // * annotation cdi.kubevirt.io/storage.usePopulator is not set by user to "false"
+// * the clone source is not in a remote cluster
+// * the changed blocks are not synced into an existing PVC
+// * the disk image of the clone source is not transformed
 func (r *ReconcilerBase) shouldUseCDIPopulator(syncState *dvSyncState) (bool, error) {
 	dv := syncState.dvMutated
+	if cc.GetDataVolumeCloneSourceCluster(dv) != nil || cc.GetDataVolumeCloneTransformation(dv) != nil {
+		return false, nil
+	}
+	if syncState.pvc != nil && metav1.HasAnnotation(syncState.pvc.ObjectMeta, cc.AnnCloneDeltaSync) {
+		return false, nil
+	}
 	if usePopulator, ok := dv.Annotations[cc.AnnUsePopulator]; ok {
 		boolUsePopulator, err := strconv.ParseBool(usePopulator)
 		if err != nil {
@@ -121,6 +121,9 @@ func (r *ImportReconciler) updatePVCForPopulation(dataVolume *cdiv1.DataVolume,
 	if dataVolume.Spec.Source.HTTP == nil &&
 		dataVolume.Spec.Source.S3 == nil &&
 		dataVolume.Spec.Source.GCS == nil &&
+		dataVolume.Spec.Source.SFTP == nil &&
+		dataVolume.Spec.Source.SMB == nil &&
+		dataVolume.Spec.Source.NFS == nil &&
 		dataVolume.Spec.Source.Registry == nil &&
 		dataVolume.Spec.Source.Imageio == nil &&
 		dataVolume.Spec.Source.VDDK == nil &&
@@ -160,6 +163,18 @@ func (r *ImportReconciler) updateAnnotations(dataVolume *cdiv1.DataVolume, pvc *
 		cc.UpdateGCSAnnotations(annotations, gcs)
 		return nil
 	}
+	if sftp := dataVolume.Spec.Source.SFTP; sftp != nil {
+		cc.UpdateSFTPAnnotations(annotations, sftp)
+		return nil
+	}
+	if smb := dataVolume.Spec.Source.SMB; smb != nil {
+		cc.UpdateSMBAnnotations(annotations, smb)
+		return nil
+	}
+	if nfs := dataVolume.Spec.Source.NFS; nfs != nil {
+		cc.UpdateNFSAnnotations(annotations, nfs)
+		return nil
+	}
 	if registry := dataVolume.Spec.Source.Registry; registry != nil {
 		cc.UpdateRegistryAnnotations(annotations, registry)
 		return nil
@@ -366,6 +381,12 @@ func (r *ImportReconciler) reconcileVolumeImportSourceCR(syncState *dvSyncState)
 		source.S3 = s3
 	} else if gcs := dv.Spec.Source.GCS; gcs != nil {
 		source.GCS = gcs
+	} else if sftp := dv.Spec.Source.SFTP; sftp != nil {
+		source.SFTP = sftp
+	} else if smb := dv.Spec.Source.SMB; smb != nil {
+		source.SMB = smb
+	} else if nfs := dv.Spec.Source.NFS; nfs != nil {
+		source.NFS = nfs
 	} else if registry := dv.Spec.Source.Registry; registry != nil {
 		source.Registry = registry
 	} else if imageio := dv.Spec.Source.Imageio; imageio != nil {
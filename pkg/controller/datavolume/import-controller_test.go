@@ -1919,7 +1919,14 @@ func dvPhaseTest(reconciler ReconcilerBase, dvc dvController, testDv runtime.Obj
 	err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
 	Expect(err).ToNot(HaveOccurred())
 	Expect(dv.Status.Phase).To(Equal(expected))
-	Expect(dv.Status.Conditions).To(HaveLen(3))
+	expectedConditions := 3
+	if dv.Spec.Source != nil && dv.Spec.Source.PVC != nil {
+		expectedConditions++
+		cloneStrategyCondition := FindConditionByType(cdiv1.DataVolumeCloneStrategy, dv.Status.Conditions)
+		Expect(cloneStrategyCondition).ToNot(BeNil())
+		Expect(cloneStrategyCondition.Status).To(Equal(corev1.ConditionTrue))
+	}
+	Expect(dv.Status.Conditions).To(HaveLen(expectedConditions))
 	boundCondition := FindConditionByType(cdiv1.DataVolumeBound, dv.Status.Conditions)
 	Expect(boundCondition.Status).To(Equal(boundStatusByPVCPhase(pvcPhase)))
 	Expect(boundCondition.Message).To(Equal(boundMessageByPVCPhase(pvcPhase, "test-dv")))
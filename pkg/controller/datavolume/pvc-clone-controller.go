@@ -18,7 +18,6 @@ package datavolume
 
 import (
 	"context"
-	"crypto/rsa"
 	"fmt"
 	"strconv"
 	"time"
@@ -43,6 +42,8 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
 	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
+	"kubevirt.io/containerized-data-importer/pkg/token"
+	"kubevirt.io/containerized-data-importer/pkg/util"
 )
 
 const (
@@ -59,6 +60,7 @@ var ErrInvalidTermMsg = fmt.Errorf("the termination message from the size-detect
 // PvcCloneReconciler members
 type PvcCloneReconciler struct {
 	CloneReconcilerBase
+	sourceClient cc.CloneSourceClientFunc
 }
 
 // NewPvcCloneController creates a new instance of the datavolume clone controller
@@ -69,8 +71,7 @@ func NewPvcCloneController(
 	clonerImage string,
 	importerImage string,
 	pullPolicy string,
-	tokenPublicKey *rsa.PublicKey,
-	tokenPrivateKey *rsa.PrivateKey,
+	tokenKeys token.KeySource,
 	installerLabels map[string]string,
 ) (controller.Controller, error) {
 	client := mgr.GetClient()
@@ -89,11 +90,13 @@ func NewPvcCloneController(
 			importerImage:       importerImage,
 			pullPolicy:          pullPolicy,
 			cloneSourceKind:     "PersistentVolumeClaim",
-			shortTokenValidator: cc.NewCloneTokenValidator(common.CloneTokenIssuer, tokenPublicKey),
-			longTokenValidator:  cc.NewCloneTokenValidator(common.ExtendedCloneTokenIssuer, tokenPublicKey),
+			shortTokenValidator: cc.NewCloneTokenValidator(common.CloneTokenIssuer, tokenKeys),
+			longTokenValidator:  cc.NewCloneTokenValidator(common.ExtendedCloneTokenIssuer, tokenKeys),
 			// for long term tokens to handle cross namespace dumb clones
-			tokenGenerator: newLongTermCloneTokenGenerator(tokenPrivateKey),
+			tokenGenerator: newLongTermCloneTokenGenerator(tokenKeys),
+			tokenKeys:      tokenKeys,
 		},
+		sourceClient: cc.GetCloneSourceClient,
 	}
 
 	dataVolumeCloneController, err := controller.New(pvcCloneControllerName, mgr, controller.Options{
@@ -142,6 +145,49 @@ func (r *PvcCloneReconciler) prepare(syncState *dvSyncState) error {
 	if err := r.populateSourceIfSourceRef(dv); err != nil {
 		return err
 	}
+	return r.adoptClaimForDeltaSync(syncState)
+}
+
+// adoptClaimForDeltaSync takes over an existing target PVC which may be adopted so that it is cloned into again,
+// sending only the blocks which differ from the PVC
+func (r *PvcCloneReconciler) adoptClaimForDeltaSync(syncState *dvSyncState) error {
+	dv := syncState.dvMutated
+	pvc := syncState.pvc
+	if pvc == nil || metav1.IsControlledBy(pvc, dv) {
+		return nil
+	}
+	if deltaSync, _ := strconv.ParseBool(dv.Annotations[cc.AnnCloneDeltaSync]); !deltaSync {
+		return nil
+	}
+	if canAdopt, err := cc.AllowClaimAdoption(r.client, pvc, dv); err != nil || !canAdopt {
+		return err
+	}
+
+	if cc.GetVolumeMode(pvc) != corev1.PersistentVolumeBlock {
+		msg := fmt.Sprintf(MessageDeltaSyncUnsupported, pvc.Name)
+		r.recorder.Event(dv, corev1.EventTypeWarning, DeltaSyncUnsupported, msg)
+		return errors.New(msg)
+	}
+
+	r.log.V(1).Info("Adopting PVC to sync the changed blocks", "namespace", pvc.Namespace, "name", pvc.Name)
+	pvcCopy := pvc.DeepCopy()
+	if pvcCopy.Annotations == nil {
+		pvcCopy.Annotations = map[string]string{}
+	}
+	// forget the previous population, the PVC is populated again for this DataVolume
+	for _, anno := range []string{cc.AnnPopulatedFor, cc.AnnCloneOf, cc.AnnPodPhase, cc.AnnPodReady, cc.AnnPodRestarts,
+		cc.AnnCloneSourcePod, cc.AnnRunningCondition, cc.AnnRunningConditionMessage, cc.AnnRunningConditionReason} {
+		delete(pvcCopy.Annotations, anno)
+	}
+	pvcCopy.Annotations[cc.AnnCreatedForDataVolume] = string(dv.UID)
+	pvcCopy.Annotations[cc.AnnCloneDeltaSync] = "true"
+	if err := r.updateAnnotations(dv, pvcCopy); err != nil {
+		return err
+	}
+	if err := r.addOwnerRef(pvcCopy, dv); err != nil {
+		return err
+	}
+	syncState.pvc = pvcCopy
 	return nil
 }
 
@@ -172,6 +218,10 @@ func (r *PvcCloneReconciler) cleanupSizeDetectionPod(dv *cdiv1.DataVolume, pvc *
 	if pvc != nil && !cc.ShouldDeletePod(pvc) {
 		return nil
 	}
+	// size detection pods are never created in remote clusters
+	if cc.GetDataVolumeCloneSourceCluster(dv) != nil {
+		return nil
+	}
 
 	nn := types.NamespacedName{Namespace: dv.Spec.Source.PVC.Namespace, Name: dv.Spec.Source.PVC.Name}
 	sourcePvc := &corev1.PersistentVolumeClaim{}
@@ -258,6 +308,16 @@ func (r *PvcCloneReconciler) updateAnnotations(dataVolume *cdiv1.DataVolume, pvc
 		sourceNamespace = dataVolume.Namespace
 	}
 	pvc.Annotations[cc.AnnCloneRequest] = sourceNamespace + "/" + dataVolume.Spec.Source.PVC.Name
+	if cluster := dataVolume.Spec.Source.PVC.Cluster; cluster != nil {
+		cc.SetCloneSourceCluster(pvc, cluster)
+	}
+	if transformation := dataVolume.Spec.Source.PVC.Transformation; transformation != nil {
+		cc.SetCloneTransformation(pvc, transformation)
+		// a resized target may be smaller than the source, the clone target checks the data fits
+		if transformation.Resize {
+			pvc.Annotations[cc.AnnPermissiveClone] = "true"
+		}
+	}
 	return nil
 }
 
@@ -334,6 +394,8 @@ func (r *PvcCloneReconciler) syncClone(log logr.Logger, req reconcile.Request) (
 				}
 			}
 			pvcModifier = r.updatePVCForPopulation
+		} else if err := r.validateHostAssistedClone(&syncRes); err != nil {
+			return syncRes, err
 		}
 
 		newPvc, err := r.createPvcForDatavolume(datavolume, pvcSpec, pvcModifier)
@@ -365,8 +427,12 @@ func (r *PvcCloneReconciler) syncClone(log logr.Logger, req reconcile.Request) (
 		}
 	} else {
 		cc.AddAnnotation(datavolume, cc.AnnCloneType, string(cdiv1.CloneStrategyHostAssisted))
-		if err := r.fallbackToHostAssisted(pvc); err != nil {
-			return syncRes, err
+		// clones from remote clusters, delta syncs and transformations are always host assisted, they do not fall back to it
+		if cc.GetDataVolumeCloneSourceCluster(datavolume) == nil && !metav1.HasAnnotation(pvc.ObjectMeta, cc.AnnCloneDeltaSync) &&
+			cc.GetDataVolumeCloneTransformation(datavolume) == nil {
+			if err := r.fallbackToHostAssisted(pvc); err != nil {
+				return syncRes, err
+			}
 		}
 	}
 
@@ -379,15 +445,23 @@ func (r *PvcCloneReconciler) syncClone(log logr.Logger, req reconcile.Request) (
 
 // Verify that the source PVC has been completely populated.
 func (r *PvcCloneReconciler) isSourcePVCPopulated(dv *cdiv1.DataVolume) (bool, error) {
+	sourceClient, err := r.getSourceClient(dv)
+	if err != nil {
+		return false, err
+	}
 	sourcePvc := &corev1.PersistentVolumeClaim{}
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: dv.Spec.Source.PVC.Name, Namespace: dv.Spec.Source.PVC.Namespace}, sourcePvc); err != nil {
+	if err := sourceClient.Get(context.TODO(), types.NamespacedName{Name: dv.Spec.Source.PVC.Name, Namespace: dv.Spec.Source.PVC.Namespace}, sourcePvc); err != nil {
 		return false, err
 	}
-	return cc.IsPopulated(sourcePvc, r.client)
+	return cc.IsPopulated(sourcePvc, sourceClient)
 }
 
 func (r *PvcCloneReconciler) sourceInUse(dv *cdiv1.DataVolume, eventReason string) (bool, error) {
-	pods, err := cc.GetPodsUsingPVCs(context.TODO(), r.client, dv.Spec.Source.PVC.Namespace, sets.New(dv.Spec.Source.PVC.Name), false)
+	sourceClient, err := r.getSourceClient(dv)
+	if err != nil {
+		return false, err
+	}
+	pods, err := cc.GetPodsUsingPVCs(context.TODO(), sourceClient, dv.Spec.Source.PVC.Namespace, sets.New(dv.Spec.Source.PVC.Name), false)
 	if err != nil {
 		return false, err
 	}
@@ -414,8 +488,13 @@ func (r *PvcCloneReconciler) findSourcePvc(dataVolume *cdiv1.DataVolume) (*corev
 		sourcePvcNs = dataVolume.Namespace
 	}
 
+	sourceClient, err := r.getSourceClient(dataVolume)
+	if err != nil {
+		return nil, err
+	}
+
 	pvc := &corev1.PersistentVolumeClaim{}
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: sourcePvcNs, Name: sourcePvcSpec.Name}, pvc); err != nil {
+	if err := sourceClient.Get(context.TODO(), types.NamespacedName{Namespace: sourcePvcNs, Name: sourcePvcSpec.Name}, pvc); err != nil {
 		if k8serrors.IsNotFound(err) {
 			r.log.V(3).Info("Source PVC is missing", "source namespace", sourcePvcSpec.Namespace, "source name", sourcePvcSpec.Name)
 		}
@@ -424,6 +503,15 @@ func (r *PvcCloneReconciler) findSourcePvc(dataVolume *cdiv1.DataVolume) (*corev
 	return pvc, nil
 }
 
+// getSourceClient returns the client of the cluster of the source PVC
+func (r *PvcCloneReconciler) getSourceClient(dataVolume *cdiv1.DataVolume) (client.Client, error) {
+	cluster := cc.GetDataVolumeCloneSourceCluster(dataVolume)
+	if cluster == nil {
+		return r.client, nil
+	}
+	return r.sourceClient(context.TODO(), r.client, dataVolume.Namespace, cluster)
+}
+
 // validateCloneAndSourcePVC checks if the source PVC of a clone exists and does proper validation
 func (r *PvcCloneReconciler) validateCloneAndSourcePVC(syncState *dvSyncState, log logr.Logger) (bool, error) {
 	datavolume := syncState.dvMutated
@@ -440,12 +528,16 @@ func (r *PvcCloneReconciler) validateCloneAndSourcePVC(syncState *dvSyncState, l
 			if syncErr != nil {
 				log.Error(syncErr, "failed to sync DataVolume status with event")
 			}
+			// the PVCs of remote clusters are not watched
+			if cc.GetDataVolumeCloneSourceCluster(datavolume) != nil {
+				syncState.result = &reconcile.Result{RequeueAfter: sourceInUseRequeueDuration}
+			}
 			return false, nil
 		}
 		return false, err
 	}
 
-	err = r.validateClone(sourcePvc, &datavolume.Spec)
+	err = r.validateClone(sourcePvc, &datavolume.Spec, syncState.pvcSpec)
 	if err != nil {
 		syncErr := r.syncDataVolumeStatusPhaseWithEvent(syncState, datavolume.Status.Phase, nil,
 			Event{
@@ -462,8 +554,9 @@ func (r *PvcCloneReconciler) validateCloneAndSourcePVC(syncState *dvSyncState, l
 	return true, nil
 }
 
-// validateClone compares a clone spec against its source PVC to validate its creation
-func (r *PvcCloneReconciler) validateClone(sourcePVC *corev1.PersistentVolumeClaim, spec *cdiv1.DataVolumeSpec) error {
+// validateClone compares a clone spec against its source PVC to validate its creation, pvcSpec is the rendered spec of
+// the target PVC if known
+func (r *PvcCloneReconciler) validateClone(sourcePVC *corev1.PersistentVolumeClaim, spec *cdiv1.DataVolumeSpec, pvcSpec *corev1.PersistentVolumeClaimSpec) error {
 	var targetResources corev1.VolumeResourceRequirements
 	var err error
 
@@ -484,6 +577,11 @@ func (r *PvcCloneReconciler) validateClone(sourcePVC *corev1.PersistentVolumeCla
 		}
 	}
 
+	// a resized filesystem target may be smaller than the source, the clone target checks the data fits
+	if isResizedFilesystemClone(spec, pvcSpec) {
+		return nil
+	}
+
 	// TODO: Spec.Storage API needs a better more complex check to validate clone size - to account for fsOverhead
 	// simple size comparison will not work here
 	if (hasSize && cc.GetVolumeMode(sourcePVC) == corev1.PersistentVolumeBlock) || explicitPvcRequest {
@@ -495,6 +593,23 @@ func (r *PvcCloneReconciler) validateClone(sourcePVC *corev1.PersistentVolumeCla
 	return nil
 }
 
+// isResizedFilesystemClone returns true if the disk image of the clone source is resized to a filesystem target. The
+// volume mode of a storage spec defaults to the one of the storage profile, so it is taken from the rendered pvcSpec,
+// and the target is not known to be a filesystem until the storage profile was applied.
+func isResizedFilesystemClone(spec *cdiv1.DataVolumeSpec, pvcSpec *corev1.PersistentVolumeClaimSpec) bool {
+	if spec.Source == nil || spec.Source.PVC == nil || spec.Source.PVC.Transformation == nil || !spec.Source.PVC.Transformation.Resize {
+		return false
+	}
+	if spec.PVC != nil {
+		return util.ResolveVolumeMode(spec.PVC.VolumeMode) == corev1.PersistentVolumeFilesystem
+	}
+	volumeMode := spec.Storage.VolumeMode
+	if pvcSpec != nil && pvcSpec.VolumeMode != nil {
+		volumeMode = pvcSpec.VolumeMode
+	}
+	return volumeMode != nil && *volumeMode == corev1.PersistentVolumeFilesystem
+}
+
 // validateContentTypes compares the content type of a clone DV against its source PVC's one
 func validateContentTypes(sourcePVC *corev1.PersistentVolumeClaim, spec *cdiv1.DataVolumeSpec) (bool, cdiv1.DataVolumeContentType, cdiv1.DataVolumeContentType) {
 	sourceContentType := cc.GetPVCContentType(sourcePVC)
@@ -554,9 +669,9 @@ func (r *PvcCloneReconciler) detectCloneSize(syncState *dvSyncState) (bool, erro
 	// Due to possible filesystem overhead complications when cloning
 	// using host-assisted strategy, we create a pod that automatically
 	// collects the size of the original virtual image with 'qemu-img'.
-	// If the original PVC's volume mode is "block",
-	// we simply extract the value from the original PVC's spec.
-	if sourceIsFilesystem && sourceIsKubevirt {
+	// If the original PVC's volume mode is "block", or the original PVC
+	// is in a remote cluster, we simply extract the value from the original PVC's spec.
+	if sourceIsFilesystem && sourceIsKubevirt && cc.GetDataVolumeCloneSourceCluster(syncState.dvMutated) == nil {
 		var available bool
 		// If available, we first try to get the virtual size from previous iterations
 		targetSize, available = getSizeFromAnnotations(sourcePvc)
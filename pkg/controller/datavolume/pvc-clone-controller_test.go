@@ -18,6 +18,8 @@ package datavolume
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"fmt"
 	"reflect"
 	"strings"
@@ -26,6 +28,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	jose "github.com/go-jose/go-jose/v3"
 	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
 
 	corev1 "k8s.io/api/core/v1"
@@ -101,6 +104,65 @@ var _ = Describe("All DataVolume Tests", func() {
 				Expect(dv.Annotations).To(HaveKey(AnnExtendedCloneToken))
 			})
 
+			It("should reissue the extended tokens signed by a rotated key", func() {
+				newKey := func(kid string) (*jose.JSONWebKey, jose.JSONWebKey) {
+					public, private, err := ed25519.GenerateKey(rand.Reader)
+					Expect(err).ToNot(HaveOccurred())
+					return &jose.JSONWebKey{Key: private, KeyID: kid}, jose.JSONWebKey{Key: public, KeyID: kid}
+				}
+				oldKey, oldPublicKey := newKey("old")
+				keys := &FakeKeySource{signingKey: oldKey, verificationKeys: []jose.JSONWebKey{oldPublicKey}}
+				generator := newLongTermCloneTokenGenerator(keys)
+				validator := NewCloneTokenValidator(common.ExtendedCloneTokenIssuer, keys)
+
+				dv := newCloneDataVolumeWithPVCNS("test-dv", "source-ns")
+				pvc := CreatePvc("test-dv", metav1.NamespaceDefault, map[string]string{}, nil)
+				pvc.UID = "pvc-uid"
+				dvToken, _, err := generator.Generate(&token.Payload{Name: "test", Namespace: "source-ns", Params: map[string]string{"uid": string(dv.UID)}})
+				Expect(err).ToNot(HaveOccurred())
+				pvcToken, _, err := generator.Generate(&token.Payload{Name: "test", Namespace: "source-ns", Params: map[string]string{"uid": string(pvc.UID)}})
+				Expect(err).ToNot(HaveOccurred())
+				dv.Annotations[AnnExtendedCloneToken] = dvToken
+				pvc.Annotations[AnnExtendedCloneToken] = pvcToken
+				original, err := validator.Validate(dvToken)
+				Expect(err).ToNot(HaveOccurred())
+
+				reconciler = createCloneReconciler(dv, pvc)
+				reconciler.longTokenValidator = validator
+				reconciler.tokenGenerator = generator
+				reconciler.tokenKeys = keys
+
+				By("Not reissuing the tokens of the current key")
+				reissued, err := reconciler.ensureExtendedTokenDV(dv)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(reissued).To(BeFalse())
+				Expect(dv.Annotations[AnnExtendedCloneToken]).To(Equal(dvToken))
+
+				By("Rotating the key, the previous key is still trusted during the overlap")
+				newSigningKey, newPublicKey := newKey("new")
+				keys.signingKey = newSigningKey
+				keys.verificationKeys = []jose.JSONWebKey{newPublicKey, oldPublicKey}
+				reissued, err = reconciler.ensureExtendedTokenDV(dv)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(reissued).To(BeTrue())
+				Expect(reconciler.ensureExtendedTokenPVC(dv, pvc)).To(Succeed())
+
+				By("Retiring the previous key, the reissued tokens still validate")
+				keys.verificationKeys = []jose.JSONWebKey{newPublicKey}
+				_, err = validator.Validate(dvToken)
+				Expect(err).To(HaveOccurred())
+				payload, err := validator.Validate(dv.Annotations[AnnExtendedCloneToken])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(payload.Params).To(HaveKeyWithValue("uid", string(dv.UID)))
+				Expect(payload.Expiry).To(BeTemporally("~", original.Expiry, time.Second))
+
+				pvc = &corev1.PersistentVolumeClaim{}
+				Expect(reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)).To(Succeed())
+				payload, err = validator.Validate(pvc.Annotations[AnnExtendedCloneToken])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(payload.Params).To(HaveKeyWithValue("uid", "pvc-uid"))
+			})
+
 			It("should add finalizer for cross namespace clone", func() {
 				dv := newCloneDataVolumeWithPVCNS("test-dv", "source-ns")
 				dv.Annotations[AnnExtendedCloneToken] = "test-token"
@@ -163,6 +225,70 @@ var _ = Describe("All DataVolume Tests", func() {
 				Entry("with different namespace", "source-ns"),
 			)
 
+			It("should pass the clone strategy policy to the VolumeCloneSource CR", func() {
+				fallback := cdiv1.CloneFallbackFail
+				dv := newCloneDataVolume("test-dv")
+				dv.Spec.CloneStrategy = &cdiv1.CloneStrategyPolicy{
+					Strategies: []cdiv1.CDICloneStrategy{cdiv1.CloneStrategyCsiClone, cdiv1.CloneStrategySnapshot},
+					Fallback:   &fallback,
+				}
+				srcPvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+				reconciler = createCloneReconcilerWFFCDisabled(storageClass, csiDriver, dv, srcPvc)
+				_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+				Expect(err).ToNot(HaveOccurred())
+				vcs := &cdiv1.VolumeCloneSource{}
+				err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: volumeCloneSourceName(dv), Namespace: metav1.NamespaceDefault}, vcs)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(vcs.Spec.CloneStrategy).To(Equal(dv.Spec.CloneStrategy))
+			})
+
+			It("should create a host-assisted clone PVC for a remote cluster source", func() {
+				dv := newCloneDataVolumeWithPVCNS("test-dv", "source-ns")
+				dv.Spec.Source.PVC.Cluster = &cdiv1.CloneSourceCluster{KubeconfigSecretRef: "remote-kubeconfig", ServiceDomain: "remote.example.com"}
+				dv.Annotations[AnnExtendedCloneToken] = "foobar"
+				dv.Finalizers = append(dv.Finalizers, crossNamespaceFinalizer)
+				srcPvc := CreatePvcInStorageClass("test", "source-ns", &scName, nil, nil, corev1.ClaimBound)
+				reconciler = createCloneReconcilerWFFCDisabled(storageClass, csiDriver, dv)
+				remoteClient := fake.NewClientBuilder().WithScheme(reconciler.scheme).WithObjects(srcPvc).Build()
+				reconciler.sourceClient = func(_ context.Context, _ client.Client, namespace string, cluster *cdiv1.CloneSourceCluster) (client.Client, error) {
+					Expect(namespace).To(Equal(metav1.NamespaceDefault))
+					Expect(cluster.KubeconfigSecretRef).To(Equal("remote-kubeconfig"))
+					return remoteClient, nil
+				}
+				_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+				Expect(err).ToNot(HaveOccurred())
+				pvc := &corev1.PersistentVolumeClaim{}
+				err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pvc.Spec.DataSourceRef).To(BeNil())
+				Expect(pvc.Annotations[AnnUsePopulator]).ToNot(Equal("true"))
+				Expect(pvc.Annotations[AnnCloneRequest]).To(Equal("source-ns/test"))
+				Expect(pvc.Annotations[AnnCloneSourceKubeconfig]).To(Equal("remote-kubeconfig"))
+				Expect(pvc.Annotations[AnnCloneSourceServiceDomain]).To(Equal("remote.example.com"))
+				vcsList := &cdiv1.VolumeCloneSourceList{}
+				Expect(reconciler.client.List(context.TODO(), vcsList)).To(Succeed())
+				Expect(vcsList.Items).To(BeEmpty())
+			})
+
+			It("should create a host-assisted clone PVC for a transformed clone", func() {
+				dv := newCloneDataVolume("test-dv")
+				dv.Spec.Source.PVC.Transformation = &cdiv1.CloneTransformation{Resize: true, LogicalSectorSize: ptr.To[int32](4096)}
+				dv.Annotations[AnnExtendedCloneToken] = "foobar"
+				srcPvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+				reconciler = createCloneReconcilerWFFCDisabled(storageClass, csiDriver, dv, srcPvc)
+				_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+				Expect(err).ToNot(HaveOccurred())
+				pvc := &corev1.PersistentVolumeClaim{}
+				err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pvc.Spec.DataSourceRef).To(BeNil())
+				Expect(pvc.Annotations[AnnCloneRequest]).To(Equal("default/test"))
+				Expect(pvc.Annotations).To(HaveKeyWithValue(AnnCloneResize, "true"))
+				Expect(pvc.Annotations).To(HaveKeyWithValue(AnnCloneLogicalSectorSize, "4096"))
+				Expect(pvc.Annotations).To(HaveKeyWithValue(AnnPermissiveClone, "true"))
+				Expect(pvc.Annotations).ToNot(HaveKey(populators.AnnCloneFallbackReason))
+			})
+
 			It("should add cloneType annotation", func() {
 				dv := newCloneDataVolume("test-dv")
 				anno := map[string]string{
@@ -518,7 +644,7 @@ var _ = Describe("All DataVolume Tests", func() {
 			sourcePvc.Annotations[AnnContentType] = string(cdiv1.DataVolumeKubeVirt)
 			dvSpec := &cdiv1.DataVolumeSpec{ContentType: cdiv1.DataVolumeArchive}
 
-			err := r.validateClone(sourcePvc, dvSpec)
+			err := r.validateClone(sourcePvc, dvSpec, nil)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring(
 				fmt.Sprintf("Source contentType (%s) and target contentType (%s) do not match", cdiv1.DataVolumeKubeVirt, cdiv1.DataVolumeArchive)))
@@ -536,7 +662,7 @@ var _ = Describe("All DataVolume Tests", func() {
 			}
 			dvSpec := &cdiv1.DataVolumeSpec{Storage: storageSpec}
 
-			err := r.validateClone(sourcePvc, dvSpec)
+			err := r.validateClone(sourcePvc, dvSpec, nil)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("target resources requests storage size is smaller than the source"))
 		})
@@ -553,7 +679,7 @@ var _ = Describe("All DataVolume Tests", func() {
 			}
 			dvSpec := &cdiv1.DataVolumeSpec{Storage: storageSpec}
 
-			err := r.validateClone(sourcePvc, dvSpec)
+			err := r.validateClone(sourcePvc, dvSpec, nil)
 			Expect(err).ToNot(HaveOccurred())
 		})
 
@@ -563,7 +689,7 @@ var _ = Describe("All DataVolume Tests", func() {
 			storageSpec := &cdiv1.StorageSpec{}
 			dvSpec := &cdiv1.DataVolumeSpec{Storage: storageSpec}
 
-			err := r.validateClone(sourcePvc, dvSpec)
+			err := r.validateClone(sourcePvc, dvSpec, nil)
 			Expect(err).ToNot(HaveOccurred())
 		})
 
@@ -578,12 +704,63 @@ var _ = Describe("All DataVolume Tests", func() {
 			}
 			dvSpec := &cdiv1.DataVolumeSpec{PVC: pvcSpec}
 
-			err := r.validateClone(sourcePvc, dvSpec)
+			err := r.validateClone(sourcePvc, dvSpec, nil)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("target resources requests storage size is smaller than the source"))
 
 		})
 
+		It("Should validate the clone when a resized filesystem target is smaller than the source (PVC API)", func() {
+			sourcePvc.Annotations[AnnContentType] = string(cdiv1.DataVolumeKubeVirt)
+			pvcSpec := &corev1.PersistentVolumeClaimSpec{
+				VolumeMode: &fsVM,
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("1Mi"), // Less than the source's one (1Gi)
+					},
+				},
+			}
+			dvSpec := &cdiv1.DataVolumeSpec{
+				Source: &cdiv1.DataVolumeSource{PVC: &cdiv1.DataVolumeSourcePVC{Transformation: &cdiv1.CloneTransformation{Resize: true}}},
+				PVC:    pvcSpec,
+			}
+
+			err := r.validateClone(sourcePvc, dvSpec, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			pvcSpec.VolumeMode = &blockVM
+			err = r.validateClone(sourcePvc, dvSpec, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("target resources requests storage size is smaller than the source"))
+		})
+
+		It("Should validate the size of a resized clone against the volume mode of the storage profile (Storage API)", func() {
+			sourcePvc.Annotations[AnnContentType] = string(cdiv1.DataVolumeKubeVirt)
+			sourcePvc.Spec.VolumeMode = &blockVM
+			storageSpec := &cdiv1.StorageSpec{
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("1Mi"), // Less than the source's one (1Gi)
+					},
+				},
+			}
+			dvSpec := &cdiv1.DataVolumeSpec{
+				Source:  &cdiv1.DataVolumeSource{PVC: &cdiv1.DataVolumeSourcePVC{Transformation: &cdiv1.CloneTransformation{Resize: true}}},
+				Storage: storageSpec,
+			}
+
+			err := r.validateClone(sourcePvc, dvSpec, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("target resources requests storage size is smaller than the source"))
+
+			err = r.validateClone(sourcePvc, dvSpec, &corev1.PersistentVolumeClaimSpec{VolumeMode: &blockVM})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("target resources requests storage size is smaller than the source"))
+
+			err = r.validateClone(sourcePvc, dvSpec, &corev1.PersistentVolumeClaimSpec{VolumeMode: &fsVM})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
 		It("Should validate the clone when both sizes are compatible (PVC API)", func() {
 			sourcePvc.Annotations[AnnContentType] = string(cdiv1.DataVolumeKubeVirt)
 			pvcSpec := &corev1.PersistentVolumeClaimSpec{
@@ -595,7 +772,7 @@ var _ = Describe("All DataVolume Tests", func() {
 			}
 			dvSpec := &cdiv1.DataVolumeSpec{PVC: pvcSpec}
 
-			err := r.validateClone(sourcePvc, dvSpec)
+			err := r.validateClone(sourcePvc, dvSpec, nil)
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
@@ -861,6 +1038,115 @@ var _ = Describe("All DataVolume Tests", func() {
 		})
 	})
 
+	var _ = Describe("Clone delta sync", func() {
+		scName := "testsc"
+		sc := CreateStorageClassWithProvisioner(scName, map[string]string{
+			AnnDefaultStorageClass: "true",
+		}, map[string]string{}, "csi-plugin")
+
+		It("Should adopt a block PVC to sync the changed blocks", func() {
+			dv := newCloneDataVolume("test-dv")
+			AddAnnotation(dv, AnnAllowClaimAdoption, "true")
+			AddAnnotation(dv, AnnCloneDeltaSync, "true")
+			storageProfile := createStorageProfile(scName, nil, BlockMode)
+			pvc := CreatePvcInStorageClass("test-dv", metav1.NamespaceDefault, &scName, map[string]string{
+				AnnPopulatedFor: "old-dv",
+				AnnCloneOf:      "true",
+				AnnPodPhase:     string(corev1.PodSucceeded),
+			}, nil, corev1.ClaimBound)
+			pvc.Spec.VolumeMode = &BlockMode
+			reconciler = createCloneReconciler(dv, pvc, storageProfile, sc)
+
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, dv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dv.Status.Phase).ToNot(Equal(cdiv1.Succeeded))
+			Expect(dv.Annotations[AnnCloneType]).To(Equal(string(cdiv1.CloneStrategyHostAssisted)))
+
+			pvc = &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(metav1.IsControlledBy(pvc, dv)).To(BeTrue())
+			Expect(pvc.Annotations).To(HaveKeyWithValue(AnnCreatedForDataVolume, string(dv.UID)))
+			Expect(pvc.Annotations).To(HaveKeyWithValue(AnnCloneDeltaSync, "true"))
+			Expect(pvc.Annotations).To(HaveKeyWithValue(AnnCloneRequest, "default/test"))
+			Expect(pvc.Annotations).ToNot(HaveKey(AnnPopulatedFor))
+			Expect(pvc.Annotations).ToNot(HaveKey(AnnCloneOf))
+			Expect(pvc.Annotations).ToNot(HaveKey(AnnPodPhase))
+		})
+
+		It("Should not adopt a filesystem PVC to sync the changed blocks", func() {
+			dv := newCloneDataVolume("test-dv")
+			AddAnnotation(dv, AnnAllowClaimAdoption, "true")
+			AddAnnotation(dv, AnnCloneDeltaSync, "true")
+			storageProfile := createStorageProfile(scName, nil, FilesystemMode)
+			pvc := CreatePvcInStorageClass("test-dv", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+			reconciler = createCloneReconciler(dv, pvc, storageProfile, sc)
+
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("can be synced"))
+			event := <-reconciler.recorder.(*record.FakeRecorder).Events
+			Expect(event).To(ContainSubstring(DeltaSyncUnsupported))
+
+			pvc = &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.OwnerReferences).To(BeEmpty())
+		})
+	})
+
+	var _ = Describe("Clone strategy policy", func() {
+		scName := "testsc"
+		sc := CreateStorageClassWithProvisioner(scName, map[string]string{
+			AnnDefaultStorageClass: "true",
+		}, map[string]string{}, "csi-plugin")
+
+		It("Should not create a host-assisted clone PVC if fallback is not allowed", func() {
+			fallback := cdiv1.CloneFallbackFail
+			dv := newCloneDataVolume("test-dv")
+			dv.Spec.CloneStrategy = &cdiv1.CloneStrategyPolicy{
+				Strategies: []cdiv1.CDICloneStrategy{cdiv1.CloneStrategySnapshot},
+				Fallback:   &fallback,
+			}
+			storageProfile := createStorageProfile(scName, nil, BlockMode)
+			srcPvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+			reconciler = createCloneReconciler(dv, srcPvc, storageProfile, sc)
+
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(fmt.Sprintf(clone.MessageNoCloneStrategy, NoPopulatorMessage)))
+			event := <-reconciler.recorder.(*record.FakeRecorder).Events
+			Expect(event).To(ContainSubstring(clone.NoCloneStrategy))
+
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("Should create a host-assisted clone PVC if the clone policy accepts copy", func() {
+			fallback := cdiv1.CloneFallbackFail
+			dv := newCloneDataVolume("test-dv")
+			dv.Spec.CloneStrategy = &cdiv1.CloneStrategyPolicy{
+				Strategies: []cdiv1.CDICloneStrategy{cdiv1.CloneStrategySnapshot, cdiv1.CloneStrategyHostAssisted},
+				Fallback:   &fallback,
+			}
+			storageProfile := createStorageProfile(scName, nil, BlockMode)
+			srcPvc := CreatePvcInStorageClass("test", metav1.NamespaceDefault, &scName, nil, nil, corev1.ClaimBound)
+			reconciler = createCloneReconciler(dv, srcPvc, storageProfile, sc)
+
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}})
+			Expect(err).ToNot(HaveOccurred())
+
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "test-dv", Namespace: metav1.NamespaceDefault}, pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.Annotations[AnnCloneType]).To(Equal(string(cdiv1.CloneStrategyHostAssisted)))
+		})
+	})
+
 	var _ = Describe("Clone with empty storage size", func() {
 		scName := "testsc"
 		accessMode := []corev1.PersistentVolumeAccessMode{corev1.ReadOnlyMany}
@@ -1176,6 +1462,7 @@ func createCloneReconcilerWithoutConfig(objects ...client.Object) *PvcCloneRecon
 			shortTokenValidator: &FakeValidator{Match: "foobar"},
 			longTokenValidator:  &FakeValidator{Match: "foobar", Params: map[string]string{"uid": "uid"}},
 			tokenGenerator:      &FakeGenerator{token: "foobar"},
+			tokenKeys:           &FakeKeySource{},
 			cloneSourceKind:     "PersistentVolumeClaim",
 		},
 	}
@@ -1340,6 +1627,22 @@ type FakeGenerator struct {
 	token string
 }
 
-func (g *FakeGenerator) Generate(*token.Payload) (string, error) {
-	return g.token, nil
+func (g *FakeGenerator) Generate(payload *token.Payload) (string, *token.Payload, error) {
+	return g.token, payload, nil
+}
+
+type FakeKeySource struct {
+	signingKey       *jose.JSONWebKey
+	verificationKeys []jose.JSONWebKey
+}
+
+func (s *FakeKeySource) SigningKey() (*jose.JSONWebKey, error) {
+	if s.signingKey == nil {
+		return nil, fmt.Errorf("no signing key")
+	}
+	return s.signingKey, nil
+}
+
+func (s *FakeKeySource) VerificationKeys() ([]jose.JSONWebKey, error) {
+	return s.verificationKeys, nil
 }
@@ -18,7 +18,6 @@ package datavolume
 
 import (
 	"context"
-	"crypto/rsa"
 	"fmt"
 
 	"github.com/go-logr/logr"
@@ -44,6 +43,7 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
 	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
+	"kubevirt.io/containerized-data-importer/pkg/token"
 	"kubevirt.io/containerized-data-importer/pkg/util"
 )
 
@@ -69,8 +69,7 @@ func NewSnapshotCloneController(
 	clonerImage string,
 	importerImage string,
 	pullPolicy string,
-	tokenPublicKey *rsa.PublicKey,
-	tokenPrivateKey *rsa.PrivateKey,
+	tokenKeys token.KeySource,
 	installerLabels map[string]string,
 ) (controller.Controller, error) {
 	client := mgr.GetClient()
@@ -90,10 +89,11 @@ func NewSnapshotCloneController(
 			pullPolicy:          pullPolicy,
 			cloneSourceAPIGroup: ptr.To[string](snapshotv1.GroupName),
 			cloneSourceKind:     "VolumeSnapshot",
-			shortTokenValidator: cc.NewCloneTokenValidator(common.CloneTokenIssuer, tokenPublicKey),
-			longTokenValidator:  cc.NewCloneTokenValidator(common.ExtendedCloneTokenIssuer, tokenPublicKey),
+			shortTokenValidator: cc.NewCloneTokenValidator(common.CloneTokenIssuer, tokenKeys),
+			longTokenValidator:  cc.NewCloneTokenValidator(common.ExtendedCloneTokenIssuer, tokenKeys),
 			// for long term tokens to handle cross namespace dumb clones
-			tokenGenerator: newLongTermCloneTokenGenerator(tokenPrivateKey),
+			tokenGenerator: newLongTermCloneTokenGenerator(tokenKeys),
+			tokenKeys:      tokenKeys,
 		},
 	}
 
@@ -232,6 +232,9 @@ func (r *SnapshotCloneReconciler) syncSnapshotClone(log logr.Logger, req reconci
 			}
 			pvcModifier = r.updatePVCForPopulation
 		} else {
+			if err := r.validateHostAssistedClone(&syncRes); err != nil {
+				return syncRes, err
+			}
 			if err := r.initLegacyClone(&syncRes); err != nil {
 				return syncRes, err
 			}
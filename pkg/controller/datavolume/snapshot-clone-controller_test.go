@@ -777,6 +777,7 @@ func createSnapshotCloneReconcilerWithoutConfig(objects ...client.Object) *Snaps
 			shortTokenValidator: &FakeValidator{Match: "foobar"},
 			longTokenValidator:  &FakeValidator{Match: "foobar", Params: map[string]string{"uid": "uid"}},
 			tokenGenerator:      &FakeGenerator{token: "foobar"},
+			tokenKeys:           &FakeKeySource{},
 			cloneSourceAPIGroup: ptr.To[string]("snapshot.storage.k8s.io"),
 			cloneSourceKind:     "VolumeSnapshot",
 		},
@@ -527,7 +527,7 @@ func updateDataVolumeDefaultInstancetypeLabels(client client.Client, syncState *
 			return nil
 		}
 	}
-	if dv.Spec.Source != nil && dv.Spec.Source.PVC != nil {
+	if dv.Spec.Source != nil && dv.Spec.Source.PVC != nil && dv.Spec.Source.PVC.Cluster == nil {
 		pvc := &v1.PersistentVolumeClaim{}
 		key := types.NamespacedName{
 			Name:      dv.Spec.Source.PVC.Name,
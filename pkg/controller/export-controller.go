@@ -0,0 +1,288 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/fetcher"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert/generator"
+	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+)
+
+const (
+	// ExportSourceInUse is reason for event created when an export pvc is in use
+	ExportSourceInUse = "ExportSourceInUse"
+)
+
+// ExportReconciler runs an upload server in export mode for the PVCs annotated with cc.AnnExportRequest, the upload
+// proxy streams the contents of the PVC from it
+type ExportReconciler struct {
+	*UploadReconciler
+}
+
+// Reconcile the reconcile loop for the export PVCs.
+func (r *ExportReconciler) Reconcile(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("PVC", req.NamespacedName)
+	log.V(1).Info("reconciling Export PVCs")
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, pvc); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if _, isExport := pvc.Annotations[cc.AnnExportRequest]; !isExport || pvc.DeletionTimestamp != nil {
+		return reconcile.Result{}, r.cleanupExport(pvc)
+	}
+	if err := ExportPossibleForPVC(pvc); err != nil {
+		log.V(1).Info("not exporting PVC yet", "reason", err.Error())
+		return reconcile.Result{}, nil
+	}
+
+	log.Info("Calling Export reconcile PVC")
+	return r.reconcileExport(log, pvc)
+}
+
+func (r *ExportReconciler) reconcileExport(log logr.Logger, pvc *corev1.PersistentVolumeClaim) (reconcile.Result, error) {
+	pvcCopy := pvc.DeepCopy()
+	anno := pvcCopy.Annotations
+
+	pod, err := r.findExportPodForPvc(pvc)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if pod == nil {
+		// The export server mounts the PVC read only, it can share it with other readers
+		podsUsingPVC, err := cc.GetPodsUsingPVCs(context.TODO(), r.client, pvc.Namespace, sets.New(pvc.Name), true)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if len(podsUsingPVC) > 0 {
+			for _, pod := range podsUsingPVC {
+				log.V(1).Info("can't create export pod, pvc in use by other pod",
+					"namespace", pvc.Namespace, "name", pvc.Name, "pod", pod.Name)
+				r.recorder.Eventf(pvc, corev1.EventTypeWarning, ExportSourceInUse,
+					"pod %s/%s using PersistentVolumeClaim %s", pod.Namespace, pod.Name, pvc.Name)
+			}
+			return reconcile.Result{Requeue: true}, nil
+		}
+
+		podName, ok := anno[cc.AnnExportPod]
+		if !ok {
+			anno[cc.AnnExportPod] = createExportResourceName(pvc.Name)
+			if err := r.updatePVC(pvcCopy); err != nil {
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{Requeue: true}, nil
+		}
+		pod, err = r.createServerPodForPvc(pvc, podName, uploadServerClientName, naming.GetResourceName(podName, common.ScratchNameSuffix), true)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if scratchPVCName, exists := getScratchNameFromPod(pod); exists {
+		if err := r.getOrCreateExportScratchPvc(pvc, pod, scratchPVCName); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if _, err := r.getOrCreateUploadService(pvc, naming.GetServiceNameFromResourceName(pod.Name)); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	termMsg, err := parseTerminationMessage(pod)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// The export server runs until its deadline, a new one is created with fresh certificates
+	if termMsg != nil && termMsg.DeadlinePassed != nil && *termMsg.DeadlinePassed {
+		if pod.DeletionTimestamp == nil {
+			log.V(1).Info("Deleting export pod because deadline exceeded")
+			if err := r.client.Delete(context.TODO(), pod); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		anno[cc.AnnExportPodPhase] = ""
+		anno[cc.AnnExportPodReady] = "false"
+	} else {
+		anno[cc.AnnExportPodPhase] = string(pod.Status.Phase)
+		anno[cc.AnnExportPodReady] = strconv.FormatBool(isPodReady(pod))
+	}
+
+	if !reflect.DeepEqual(pvc, pvcCopy) {
+		if err := r.updatePVC(pvcCopy); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
+
+func (r *ExportReconciler) findExportPodForPvc(pvc *corev1.PersistentVolumeClaim) (*corev1.Pod, error) {
+	podName := getExportResourceNameFromPvc(pvc)
+	pod := &corev1.Pod{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: podName, Namespace: pvc.Namespace}, pod); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "error getting export pod %s/%s", pvc.Namespace, podName)
+		}
+		return nil, nil
+	}
+
+	if !metav1.IsControlledBy(pod, pvc) {
+		return nil, errors.Errorf("%s pod not controlled by pvc %s", podName, pvc.Name)
+	}
+
+	return pod, nil
+}
+
+// getOrCreateExportScratchPvc creates the scratch PVC the export server converts the qcow2 images in
+func (r *ExportReconciler) getOrCreateExportScratchPvc(pvc *corev1.PersistentVolumeClaim, pod *corev1.Pod, name string) error {
+	scratchPvc := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: pvc.Namespace}, scratchPvc); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return errors.Wrap(err, "error getting scratch PVC")
+		}
+		storageClassName := GetScratchPvcStorageClass(r.client, pvc)
+		_, err = createScratchPersistentVolumeClaim(r.client, pvc, MakePodOwnerReference(pod), name, storageClassName, r.installerLabels, r.recorder)
+		return err
+	}
+	if !metav1.IsControlledBy(scratchPvc, pod) {
+		return errors.Errorf("%s scratch PVC not controlled by pod %s", scratchPvc.Name, pod.Name)
+	}
+	return nil
+}
+
+func (r *ExportReconciler) cleanupExport(pvc *corev1.PersistentVolumeClaim) error {
+	resourceName := getExportResourceNameFromPvc(pvc)
+	if err := r.deleteService(pvc.Namespace, naming.GetServiceNameFromResourceName(resourceName)); err != nil {
+		return err
+	}
+
+	pod := &corev1.Pod{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: resourceName, Namespace: pvc.Namespace}, pod); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if pod.DeletionTimestamp == nil && metav1.IsControlledBy(pod, pvc) {
+		if err := r.client.Delete(context.TODO(), pod); cc.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewExportController creates a new instance of the export controller.
+func NewExportController(mgr manager.Manager, log logr.Logger, uploadImage, pullPolicy, verbose string, serverCertGenerator generator.CertGenerator, clientCAFetcher fetcher.CertBundleFetcher, installerLabels map[string]string) (controller.Controller, error) {
+	client := mgr.GetClient()
+	reconciler := &ExportReconciler{
+		UploadReconciler: &UploadReconciler{
+			client:              client,
+			scheme:              mgr.GetScheme(),
+			log:                 log.WithName("export-controller"),
+			image:               uploadImage,
+			verbose:             verbose,
+			pullPolicy:          pullPolicy,
+			recorder:            mgr.GetEventRecorderFor("export-controller"),
+			serverCertGenerator: serverCertGenerator,
+			clientCAFetcher:     clientCAFetcher,
+			featureGates:        featuregates.NewFeatureGates(client),
+			installerLabels:     installerLabels,
+		},
+	}
+	exportController, err := controller.New("export-controller", mgr, controller.Options{
+		MaxConcurrentReconciles: 3,
+		Reconciler:              reconciler,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := addUploadControllerWatches(mgr, exportController); err != nil {
+		return nil, err
+	}
+
+	return exportController, nil
+}
+
+// getExportResourceNameFromPvc returns the name given to export resources
+func getExportResourceNameFromPvc(pvc *corev1.PersistentVolumeClaim) string {
+	if podName, ok := pvc.Annotations[cc.AnnExportPod]; ok {
+		return podName
+	}
+	return createExportResourceName(pvc.Name)
+}
+
+// createExportResourceName returns the name given to export resources
+func createExportResourceName(name string) string {
+	return naming.GetResourceName(common.ExportPodName, name)
+}
+
+// ExportPossibleForPVC is called by the upload proxy to see whether the contents of a PVC can be exported
+func ExportPossibleForPVC(pvc *corev1.PersistentVolumeClaim) error {
+	if _, ok := pvc.Annotations[cc.AnnExportRequest]; !ok {
+		return errors.Errorf("PVC %s is not an export source", pvc.Name)
+	}
+	if pvc.Status.Phase != corev1.ClaimBound {
+		return errors.Errorf("PVC %s is not bound", pvc.Name)
+	}
+	if !isPopulatedForExport(pvc) {
+		return errors.Errorf("PVC %s is not populated yet", pvc.Name)
+	}
+	return nil
+}
+
+// isPopulatedForExport returns false while CDI is still populating the PVC
+func isPopulatedForExport(pvc *corev1.PersistentVolumeClaim) bool {
+	for _, ann := range []string{cc.AnnUploadRequest, cc.AnnEndpoint, cc.AnnCloneRequest} {
+		if _, ok := pvc.Annotations[ann]; ok && !podSucceededFromPVC(pvc) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetExportServerURL returns the url the proxy should get the contents of a particular pvc from
+func GetExportServerURL(namespace, pvc, exportPath string) string {
+	serviceName := naming.GetServiceNameFromResourceName(createExportResourceName(pvc))
+	return fmt.Sprintf("https://%s.%s.svc:%d%s", serviceName, namespace, common.UploadServerPort, exportPath)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+)
+
+var _ = Describe("Export controller reconcile loop", func() {
+	const (
+		testPvcName    = "testPvc1"
+		exportPodName  = "cdi-export-testPvc1"
+		exportScratch  = "cdi-export-testPvc1-scratch"
+		testNamespace  = "default"
+		exportedPvcAnn = cc.AnnExportRequest
+	)
+
+	createExportReconciler := func(objects ...runtime.Object) *ExportReconciler {
+		return &ExportReconciler{UploadReconciler: createUploadReconciler(objects...)}
+	}
+
+	reconcileExport := func(r *ExportReconciler) reconcile.Result {
+		result, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: testPvcName, Namespace: testNamespace}})
+		Expect(err).ToNot(HaveOccurred())
+		return result
+	}
+
+	getPvc := func(r *ExportReconciler) *corev1.PersistentVolumeClaim {
+		pvc := &corev1.PersistentVolumeClaim{}
+		Expect(r.client.Get(context.TODO(), types.NamespacedName{Name: testPvcName, Namespace: testNamespace}, pvc)).To(Succeed())
+		return pvc
+	}
+
+	getPod := func(r *ExportReconciler) (*corev1.Pod, error) {
+		pod := &corev1.Pod{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: exportPodName, Namespace: testNamespace}, pod)
+		return pod, err
+	}
+
+	It("Should create the export pod, service and scratch PVC", func() {
+		r := createExportReconciler(cc.CreatePvc(testPvcName, testNamespace, map[string]string{exportedPvcAnn: ""}, nil))
+
+		Expect(reconcileExport(r).Requeue).To(BeTrue())
+		Expect(getPvc(r).Annotations[cc.AnnExportPod]).To(Equal(exportPodName))
+		reconcileExport(r)
+
+		pod, err := getPod(r)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Labels).ToNot(HaveKey(common.UploadTargetLabel))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ExportMode, Value: "true"}))
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: cc.DataVolName, MountPath: common.UploadServerDataDir, ReadOnly: true}))
+		for _, volume := range pod.Spec.Volumes {
+			if volume.Name == cc.DataVolName {
+				Expect(volume.PersistentVolumeClaim.ReadOnly).To(BeTrue())
+			}
+		}
+
+		service := &corev1.Service{}
+		Expect(r.client.Get(context.TODO(), types.NamespacedName{Name: naming.GetServiceNameFromResourceName(exportPodName), Namespace: testNamespace}, service)).To(Succeed())
+		scratchPvc := &corev1.PersistentVolumeClaim{}
+		Expect(r.client.Get(context.TODO(), types.NamespacedName{Name: exportScratch, Namespace: testNamespace}, scratchPvc)).To(Succeed())
+
+		pvc := getPvc(r)
+		Expect(pvc.Annotations[cc.AnnExportPodPhase]).To(BeEquivalentTo(pod.Status.Phase))
+		Expect(pvc.Annotations[cc.AnnExportPodReady]).To(Equal("false"))
+	})
+
+	It("Should not export a PVC before the upload succeeded", func() {
+		r := createExportReconciler(cc.CreatePvc(testPvcName, testNamespace, map[string]string{exportedPvcAnn: "", cc.AnnUploadRequest: "", cc.AnnExportPod: exportPodName}, nil))
+		reconcileExport(r)
+		_, err := getPod(r)
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should wait for the pods writing to the PVC", func() {
+		pvc := cc.CreatePvc(testPvcName, testNamespace, map[string]string{exportedPvcAnn: "", cc.AnnExportPod: exportPodName}, nil)
+		r := createExportReconciler(pvc, podUsingPVC(pvc, false))
+		Expect(reconcileExport(r).Requeue).To(BeTrue())
+		_, err := getPod(r)
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should share the PVC with read only pods", func() {
+		pvc := cc.CreatePvc(testPvcName, testNamespace, map[string]string{exportedPvcAnn: "", cc.AnnExportPod: exportPodName}, nil)
+		r := createExportReconciler(pvc, podUsingPVC(pvc, true))
+		reconcileExport(r)
+		_, err := getPod(r)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should delete the export pod when the deadline passed", func() {
+		r := createExportReconciler(cc.CreatePvc(testPvcName, testNamespace, map[string]string{exportedPvcAnn: "", cc.AnnExportPod: exportPodName}, nil))
+		reconcileExport(r)
+		pod, err := getPod(r)
+		Expect(err).ToNot(HaveOccurred())
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						Message: `{"deadlinePassed": true}`,
+					},
+				},
+			},
+		}
+		Expect(r.client.Status().Update(context.TODO(), pod)).To(Succeed())
+
+		reconcileExport(r)
+		_, err = getPod(r)
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		Expect(getPvc(r).Annotations[cc.AnnExportPodReady]).To(Equal("false"))
+	})
+
+	It("Should remove the export pod and service when the export annotation is removed", func() {
+		r := createExportReconciler(cc.CreatePvc(testPvcName, testNamespace, map[string]string{exportedPvcAnn: "", cc.AnnExportPod: exportPodName}, nil))
+		reconcileExport(r)
+		_, err := getPod(r)
+		Expect(err).ToNot(HaveOccurred())
+
+		pvc := getPvc(r)
+		delete(pvc.Annotations, exportedPvcAnn)
+		Expect(r.client.Update(context.TODO(), pvc)).To(Succeed())
+		reconcileExport(r)
+
+		_, err = getPod(r)
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		err = r.client.Get(context.TODO(), types.NamespacedName{Name: naming.GetServiceNameFromResourceName(exportPodName), Namespace: testNamespace}, &corev1.Service{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	DescribeTable("ExportPossibleForPVC", func(annotations map[string]string, phase corev1.PersistentVolumeClaimPhase, possible bool) {
+		pvc := cc.CreatePvcInStorageClass(testPvcName, testNamespace, nil, annotations, nil, phase)
+		if possible {
+			Expect(ExportPossibleForPVC(pvc)).To(Succeed())
+		} else {
+			Expect(ExportPossibleForPVC(pvc)).ToNot(Succeed())
+		}
+	},
+		Entry("export source", map[string]string{exportedPvcAnn: ""}, corev1.ClaimBound, true),
+		Entry("not an export source", map[string]string{}, corev1.ClaimBound, false),
+		Entry("unbound PVC", map[string]string{exportedPvcAnn: ""}, corev1.ClaimPending, false),
+		Entry("upload in progress", map[string]string{exportedPvcAnn: "", cc.AnnUploadRequest: "", cc.AnnPodPhase: string(corev1.PodRunning)}, corev1.ClaimBound, false),
+		Entry("upload succeeded", map[string]string{exportedPvcAnn: "", cc.AnnUploadRequest: "", cc.AnnPodPhase: string(corev1.PodSucceeded)}, corev1.ClaimBound, true),
+		Entry("import in progress", map[string]string{exportedPvcAnn: "", cc.AnnEndpoint: "http://example.com"}, corev1.ClaimBound, false),
+	)
+
+	It("Should return the URL of the export server", func() {
+		Expect(GetExportServerURL(testNamespace, testPvcName, common.ExportPath)).To(Equal("https://cdi-export-testPvc1.default.svc:8443/v1beta1/export"))
+	})
+})
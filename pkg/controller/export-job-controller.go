@@ -0,0 +1,546 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/naming"
+)
+
+const (
+	exportJobControllerName = "export-job-controller"
+
+	exportJobSourceField = "spec.source.name"
+
+	// ExportJobSourceInUse is reason for event created when the source of an ExportJob is in use
+	ExportJobSourceInUse = "ExportJobSourceInUse"
+	// ExportJobSucceeded is reason for event created when an ExportJob succeeds
+	ExportJobSucceeded = "ExportJobSucceeded"
+	// ExportJobFailed is reason for event created when an ExportJob fails
+	ExportJobFailed = "ExportJobFailed"
+
+	// MessageExportJobSucceeded provides a const to form the ExportJob succeeded message
+	MessageExportJobSucceeded = "Successfully exported %s"
+)
+
+// ExportJobReconciler runs an importer pod in export mode for each ExportJob, pushing the contents of the source PVC
+// to the target of the ExportJob
+type ExportJobReconciler struct {
+	client          client.Client
+	recorder        record.EventRecorder
+	scheme          *runtime.Scheme
+	log             logr.Logger
+	image           string
+	verbose         string
+	pullPolicy      string
+	installerLabels map[string]string
+}
+
+// Reconcile the reconcile loop for ExportJobs.
+func (r *ExportJobReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("ExportJob", req.NamespacedName)
+	log.V(1).Info("reconciling ExportJob")
+
+	job := &cdiv1.ExportJob{}
+	if err := r.client.Get(ctx, req.NamespacedName, job); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if job.DeletionTimestamp != nil || isExportJobDone(job) {
+		return reconcile.Result{}, nil
+	}
+
+	jobCopy := job.DeepCopy()
+	res, err := r.reconcileExportJob(ctx, log, job)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !reflect.DeepEqual(job.Status, jobCopy.Status) {
+		if err := r.client.Status().Update(ctx, job); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return res, nil
+}
+
+func (r *ExportJobReconciler) reconcileExportJob(ctx context.Context, log logr.Logger, job *cdiv1.ExportJob) (reconcile.Result, error) {
+	if err := validateExportJob(job); err != nil {
+		setExportJobPhase(job, cdiv1.ExportJobFailed, err.Error())
+		r.recorder.Event(job, corev1.EventTypeWarning, ExportJobFailed, err.Error())
+		return reconcile.Result{}, nil
+	}
+
+	pod, err := r.findExportJobPod(ctx, job)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if pod == nil {
+		pvc, err := r.getExportJobSource(ctx, job)
+		if err != nil {
+			if !errors.Is(err, errExportJobSourceNotReady) {
+				return reconcile.Result{}, err
+			}
+			log.V(1).Info("export source not ready", "reason", err.Error())
+			setExportJobPhase(job, cdiv1.ExportJobPending, err.Error())
+			return reconcile.Result{}, nil
+		}
+
+		// The exporter mounts the PVC read only, it can share it with other readers
+		podsUsingPVC, err := cc.GetPodsUsingPVCs(ctx, r.client, pvc.Namespace, sets.New(pvc.Name), true)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if len(podsUsingPVC) > 0 {
+			for _, pod := range podsUsingPVC {
+				log.V(1).Info("can't create export pod, pvc in use by other pod",
+					"namespace", pvc.Namespace, "name", pvc.Name, "pod", pod.Name)
+				r.recorder.Eventf(job, corev1.EventTypeWarning, ExportJobSourceInUse,
+					"pod %s/%s using PersistentVolumeClaim %s", pod.Namespace, pod.Name, pvc.Name)
+			}
+			setExportJobPhase(job, cdiv1.ExportJobPending, "Source PVC in use by other pods")
+			return reconcile.Result{Requeue: true}, nil
+		}
+
+		if pod, err = r.createExportJobPod(ctx, job, pvc); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if scratchPVCName, exists := getScratchNameFromPod(pod); exists {
+		if err := r.getOrCreateExportJobScratchPvc(ctx, job, pod, scratchPVCName); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	return reconcile.Result{}, r.updateExportJobFromPod(ctx, job, pod)
+}
+
+func (r *ExportJobReconciler) updateExportJobFromPod(ctx context.Context, job *cdiv1.ExportJob, pod *corev1.Pod) error {
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		termMsg, err := parseTerminationMessage(pod)
+		if err != nil {
+			return err
+		}
+		if termMsg != nil && termMsg.Checksum != nil {
+			job.Status.Digest = *termMsg.Checksum
+		}
+		msg := fmt.Sprintf(MessageExportJobSucceeded, job.Spec.Source.Name)
+		setExportJobPhase(job, cdiv1.ExportJobSucceeded, msg)
+		r.recorder.Event(job, corev1.EventTypeNormal, ExportJobSucceeded, msg)
+		return r.deleteExportJobPod(ctx, pod)
+	case corev1.PodFailed:
+		msg := "Export failed"
+		if len(pod.Status.ContainerStatuses) > 0 && pod.Status.ContainerStatuses[0].State.Terminated != nil &&
+			pod.Status.ContainerStatuses[0].State.Terminated.Message != "" {
+			msg = pod.Status.ContainerStatuses[0].State.Terminated.Message
+		}
+		setExportJobPhase(job, cdiv1.ExportJobFailed, msg)
+		r.recorder.Event(job, corev1.EventTypeWarning, ExportJobFailed, msg)
+	default:
+		setExportJobPhase(job, cdiv1.ExportJobRunning, "")
+	}
+	return nil
+}
+
+// getOrCreateExportJobScratchPvc creates the scratch PVC the image is converted to qcow2 in
+func (r *ExportJobReconciler) getOrCreateExportJobScratchPvc(ctx context.Context, job *cdiv1.ExportJob, pod *corev1.Pod, name string) error {
+	scratchPvc := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: name, Namespace: job.Namespace}, scratchPvc); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return errors.Wrap(err, "error getting scratch PVC")
+		}
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: job.Spec.Source.Name, Namespace: job.Namespace}, pvc); err != nil {
+			return err
+		}
+		storageClassName := GetScratchPvcStorageClass(r.client, pvc)
+		_, err = createScratchPersistentVolumeClaim(r.client, pvc, MakePodOwnerReference(pod), name, storageClassName, r.installerLabels, r.recorder)
+		return err
+	}
+	if !metav1.IsControlledBy(scratchPvc, pod) {
+		return errors.Errorf("%s scratch PVC not controlled by pod %s", scratchPvc.Name, pod.Name)
+	}
+	return nil
+}
+
+func (r *ExportJobReconciler) deleteExportJobPod(ctx context.Context, pod *corev1.Pod) error {
+	if pod.DeletionTimestamp != nil {
+		return nil
+	}
+	return cc.IgnoreNotFound(r.client.Delete(ctx, pod))
+}
+
+func (r *ExportJobReconciler) findExportJobPod(ctx context.Context, job *cdiv1.ExportJob) (*corev1.Pod, error) {
+	podName := createExportJobPodName(job)
+	pod := &corev1.Pod{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: podName, Namespace: job.Namespace}, pod); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "error getting export pod %s/%s", job.Namespace, podName)
+		}
+		return nil, nil
+	}
+
+	if !metav1.IsControlledBy(pod, job) {
+		return nil, errors.Errorf("%s pod not controlled by ExportJob %s", podName, job.Name)
+	}
+
+	return pod, nil
+}
+
+var errExportJobSourceNotReady = errors.New("export source not ready")
+
+// getExportJobSource returns the PVC to export, or errExportJobSourceNotReady if it can't be exported yet
+func (r *ExportJobReconciler) getExportJobSource(ctx context.Context, job *cdiv1.ExportJob) (*corev1.PersistentVolumeClaim, error) {
+	key := types.NamespacedName{Name: job.Spec.Source.Name, Namespace: job.Namespace}
+	if job.Spec.Source.Kind == "DataVolume" {
+		dv := &cdiv1.DataVolume{}
+		if err := r.client.Get(ctx, key, dv); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil, errors.Wrapf(errExportJobSourceNotReady, "DataVolume %s not found", key.Name)
+			}
+			return nil, err
+		}
+		if dv.Status.Phase != cdiv1.Succeeded {
+			return nil, errors.Wrapf(errExportJobSourceNotReady, "DataVolume %s is not populated yet", key.Name)
+		}
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(ctx, key, pvc); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, errors.Wrapf(errExportJobSourceNotReady, "PVC %s not found", key.Name)
+		}
+		return nil, err
+	}
+	if pvc.Status.Phase != corev1.ClaimBound {
+		return nil, errors.Wrapf(errExportJobSourceNotReady, "PVC %s is not bound", key.Name)
+	}
+	if !isPopulatedForExport(pvc) {
+		return nil, errors.Wrapf(errExportJobSourceNotReady, "PVC %s is not populated yet", key.Name)
+	}
+	return pvc, nil
+}
+
+func (r *ExportJobReconciler) createExportJobPod(ctx context.Context, job *cdiv1.ExportJob, pvc *corev1.PersistentVolumeClaim) (*corev1.Pod, error) {
+	resourceRequirements, err := cc.GetDefaultPodResourceRequirements(r.client)
+	if err != nil {
+		return nil, err
+	}
+	imagePullSecrets, err := cc.GetImagePullSecrets(r.client)
+	if err != nil {
+		return nil, err
+	}
+	workloadNodePlacement, err := cc.GetWorkloadNodePlacement(ctx, r.client)
+	if err != nil {
+		return nil, err
+	}
+
+	insecureTLS := false
+	if job.Spec.Target.Registry != nil {
+		cdiConfig := &cdiv1.CDIConfig{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: common.ConfigName}, cdiConfig); err != nil {
+			return nil, err
+		}
+		if insecureTLS, err = IsInsecureTLS(job.Spec.Target.Registry.URL, cdiConfig, r.log); err != nil {
+			return nil, err
+		}
+	}
+
+	pod := makeExportJobPodSpec(job, pvc, r.image, r.verbose, r.pullPolicy, insecureTLS)
+	pod.Spec.NodeSelector = workloadNodePlacement.NodeSelector
+	pod.Spec.Tolerations = workloadNodePlacement.Tolerations
+	pod.Spec.Affinity = workloadNodePlacement.Affinity
+	pod.Spec.ImagePullSecrets = imagePullSecrets
+	if resourceRequirements != nil {
+		pod.Spec.Containers[0].Resources = *resourceRequirements
+	}
+	util.SetRecommendedLabels(pod, r.installerLabels, "cdi-controller")
+
+	if err := r.client.Create(ctx, pod); err != nil {
+		return nil, err
+	}
+	r.log.V(3).Info("export pod created", "pod.Name", pod.Name, "pod.Namespace", pod.Namespace)
+	return pod, nil
+}
+
+// makeExportJobPodSpec returns the spec of the importer pod exporting the PVC of the ExportJob
+func makeExportJobPodSpec(job *cdiv1.ExportJob, pvc *corev1.PersistentVolumeClaim, image, verbose, pullPolicy string, insecureTLS bool) *corev1.Pod {
+	podName := createExportJobPodName(job)
+	target, endpoint, secretName, certConfigMap := cc.SourceS3, "", "", ""
+	if s3 := job.Spec.Target.S3; s3 != nil {
+		endpoint, secretName, certConfigMap = s3.URL, s3.SecretRef, s3.CertConfigMap
+	} else if registry := job.Spec.Target.Registry; registry != nil {
+		target = cc.SourceRegistry
+		endpoint, secretName, certConfigMap = registry.URL, registry.SecretRef, registry.CertConfigMap
+	}
+	format := cdiv1.ExportJobFormatRaw
+	if job.Spec.Format != nil {
+		format = *job.Spec.Format
+	}
+
+	container := corev1.Container{
+		Name:            common.ExportJobPodName,
+		Image:           image,
+		ImagePullPolicy: corev1.PullPolicy(pullPolicy),
+		Args:            []string{"-v=" + verbose},
+		Env: []corev1.EnvVar{
+			{Name: common.ExporterTarget, Value: target},
+			{Name: common.ExporterEndpoint, Value: endpoint},
+			{Name: common.ExporterFormat, Value: string(format)},
+			{Name: common.ExporterCompress, Value: strconv.FormatBool(ptr.Deref(job.Spec.Compress, false))},
+			{Name: common.InsecureTLSVar, Value: strconv.FormatBool(insecureTLS)},
+		},
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+	volumes := []corev1.Volume{
+		{
+			Name: cc.DataVolName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: pvc.Name,
+					ReadOnly:  true,
+				},
+			},
+		},
+	}
+	if cc.GetVolumeMode(pvc) == corev1.PersistentVolumeBlock {
+		container.VolumeDevices = cc.AddVolumeDevices()
+	} else {
+		container.VolumeMounts = []corev1.VolumeMount{
+			{
+				Name:      cc.DataVolName,
+				MountPath: common.ImporterDataDir,
+				ReadOnly:  true,
+			},
+		}
+	}
+	if format == cdiv1.ExportJobFormatQcow2 {
+		scratchPvcName := naming.GetResourceName(podName, common.ScratchNameSuffix)
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      cc.ScratchVolName,
+			MountPath: common.ScratchDataDir,
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: cc.ScratchVolName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: scratchPvcName,
+				},
+			},
+		})
+	}
+	if secretName != "" {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name: common.ImporterAccessKeyID,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secretName,
+					},
+					Key: common.KeyAccess,
+				},
+			},
+		}, corev1.EnvVar{
+			Name: common.ImporterSecretKey,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secretName,
+					},
+					Key: common.KeySecret,
+				},
+			},
+		})
+	}
+	if certConfigMap != "" {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  common.ImporterCertDirVar,
+			Value: common.ImporterCertDir,
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      CertVolName,
+			MountPath: common.ImporterCertDir,
+		})
+		volumes = append(volumes, createConfigMapVolume(CertVolName, certConfigMap))
+	}
+
+	pod := &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Pod",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      podName,
+			Namespace: job.Namespace,
+			Annotations: map[string]string{
+				cc.AnnCreatedBy: "yes",
+			},
+			Labels: map[string]string{
+				common.CDILabelKey:       common.CDILabelValue,
+				common.CDIComponentLabel: common.ExportJobPodName,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(job, cdiv1.SchemeGroupVersion.WithKind("ExportJob")),
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers:         []corev1.Container{container},
+			Volumes:            volumes,
+			RestartPolicy:      corev1.RestartPolicyNever,
+			PriorityClassName:  cc.GetPriorityClass(pvc),
+			EnableServiceLinks: ptr.To(false),
+		},
+	}
+	cc.SetRestrictedSecurityContext(&pod.Spec)
+	return pod
+}
+
+// validateExportJob checks the parts of the spec the CRD schema can't
+func validateExportJob(job *cdiv1.ExportJob) error {
+	switch job.Spec.Source.Kind {
+	case "PersistentVolumeClaim", "DataVolume":
+	default:
+		return errors.Errorf("unsupported source kind %s, only PersistentVolumeClaim and DataVolume can be exported", job.Spec.Source.Kind)
+	}
+	if (job.Spec.Target.S3 == nil) == (job.Spec.Target.Registry == nil) {
+		return errors.New("exactly one of the s3 and registry targets has to be set")
+	}
+	if job.Spec.Target.Registry != nil && ptr.Deref(job.Spec.Compress, false) {
+		return errors.New("compress is only supported for s3 targets")
+	}
+	if format := job.Spec.Format; format != nil && *format != cdiv1.ExportJobFormatRaw && *format != cdiv1.ExportJobFormatQcow2 {
+		return errors.Errorf("unsupported format %s", *format)
+	}
+	return nil
+}
+
+func setExportJobPhase(job *cdiv1.ExportJob, phase cdiv1.ExportJobPhase, message string) {
+	job.Status.Phase = phase
+	job.Status.Message = message
+}
+
+func isExportJobDone(job *cdiv1.ExportJob) bool {
+	return job.Status.Phase == cdiv1.ExportJobSucceeded || job.Status.Phase == cdiv1.ExportJobFailed
+}
+
+// createExportJobPodName returns the name of the pod exporting the source of the ExportJob
+func createExportJobPodName(job *cdiv1.ExportJob) string {
+	return naming.GetResourceName(common.ExportJobPodName, job.Name)
+}
+
+// NewExportJobController creates a new instance of the ExportJob controller.
+func NewExportJobController(mgr manager.Manager, log logr.Logger, importerImage, pullPolicy, verbose string, installerLabels map[string]string) (controller.Controller, error) {
+	reconciler := &ExportJobReconciler{
+		client:          mgr.GetClient(),
+		recorder:        mgr.GetEventRecorderFor(exportJobControllerName),
+		scheme:          mgr.GetScheme(),
+		log:             log.WithName(exportJobControllerName),
+		image:           importerImage,
+		verbose:         verbose,
+		pullPolicy:      pullPolicy,
+		installerLabels: installerLabels,
+	}
+	exportJobController, err := controller.New(exportJobControllerName, mgr, controller.Options{
+		MaxConcurrentReconciles: 3,
+		Reconciler:              reconciler,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := addExportJobControllerWatches(mgr, exportJobController); err != nil {
+		return nil, err
+	}
+	log.Info("Initialized ExportJob controller")
+	return exportJobController, nil
+}
+
+func addExportJobControllerWatches(mgr manager.Manager, c controller.Controller) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.TODO(), &cdiv1.ExportJob{}, exportJobSourceField, func(obj client.Object) []string {
+		return []string{obj.(*cdiv1.ExportJob).Spec.Source.Name}
+	}); err != nil {
+		return err
+	}
+
+	if err := c.Watch(source.Kind(mgr.GetCache(), &cdiv1.ExportJob{}, &handler.TypedEnqueueRequestForObject[*cdiv1.ExportJob]{})); err != nil {
+		return err
+	}
+	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Pod{}, handler.TypedEnqueueRequestForOwner[*corev1.Pod](
+		mgr.GetScheme(), mgr.GetClient().RESTMapper(), &cdiv1.ExportJob{}, handler.OnlyControllerOwner()))); err != nil {
+		return err
+	}
+
+	// The sources of pending ExportJobs are watched to start the export once they are populated
+	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.PersistentVolumeClaim{},
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, obj *corev1.PersistentVolumeClaim) []reconcile.Request {
+			return mapToExportJobs(ctx, mgr.GetClient(), obj)
+		}))); err != nil {
+		return err
+	}
+	if err := c.Watch(source.Kind(mgr.GetCache(), &cdiv1.DataVolume{},
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, obj *cdiv1.DataVolume) []reconcile.Request {
+			return mapToExportJobs(ctx, mgr.GetClient(), obj)
+		}))); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func mapToExportJobs(ctx context.Context, c client.Client, obj client.Object) []reconcile.Request {
+	jobs := &cdiv1.ExportJobList{}
+	if err := c.List(ctx, jobs, client.InNamespace(obj.GetNamespace()), client.MatchingFields{exportJobSourceField: obj.GetName()}); err != nil {
+		return nil
+	}
+	var reqs []reconcile.Request
+	for _, job := range jobs.Items {
+		if !isExportJobDone(&job) {
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: job.Name, Namespace: job.Namespace}})
+		}
+	}
+	return reqs
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+var (
+	exportJobLog = logf.Log.WithName("export-job-controller-test")
+)
+
+var _ = Describe("ExportJob controller reconcile loop", func() {
+	const (
+		testJobName      = "testjob"
+		testPvcName      = "testpvc"
+		testNamespace    = "default"
+		exportJobPod     = "cdi-export-job-testjob"
+		exportJobScratch = "cdi-export-job-testjob-scratch"
+	)
+
+	createExportJob := func() *cdiv1.ExportJob {
+		return &cdiv1.ExportJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testJobName,
+				Namespace: testNamespace,
+				UID:       "job-uid",
+			},
+			Spec: cdiv1.ExportJobSpec{
+				Source: corev1.TypedLocalObjectReference{Kind: "PersistentVolumeClaim", Name: testPvcName},
+				Target: cdiv1.ExportJobTarget{
+					S3: &cdiv1.ExportJobTargetS3{
+						URL:           "http://minio:9000/bucket/disk.img",
+						SecretRef:     "s3-secret",
+						CertConfigMap: "s3-certs",
+					},
+				},
+			},
+		}
+	}
+
+	reconcileExportJob := func(r *ExportJobReconciler) reconcile.Result {
+		result, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: testJobName, Namespace: testNamespace}})
+		Expect(err).ToNot(HaveOccurred())
+		return result
+	}
+
+	getExportJob := func(r *ExportJobReconciler) *cdiv1.ExportJob {
+		job := &cdiv1.ExportJob{}
+		Expect(r.client.Get(context.TODO(), types.NamespacedName{Name: testJobName, Namespace: testNamespace}, job)).To(Succeed())
+		return job
+	}
+
+	getPod := func(r *ExportJobReconciler) (*corev1.Pod, error) {
+		pod := &corev1.Pod{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: exportJobPod, Namespace: testNamespace}, pod)
+		return pod, err
+	}
+
+	terminatePod := func(r *ExportJobReconciler, phase corev1.PodPhase, exitCode int32, message string) {
+		pod, err := getPod(r)
+		Expect(err).ToNot(HaveOccurred())
+		pod.Status.Phase = phase
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						ExitCode: exitCode,
+						Message:  message,
+					},
+				},
+			},
+		}
+		Expect(r.client.Status().Update(context.TODO(), pod)).To(Succeed())
+	}
+
+	It("Should export a PVC to S3", func() {
+		r := createExportJobReconciler(createExportJob(), cc.CreatePvc(testPvcName, testNamespace, nil, nil))
+		reconcileExportJob(r)
+
+		pod, err := getPod(r)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metav1.IsControlledBy(pod, getExportJob(r))).To(BeTrue())
+		Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		container := pod.Spec.Containers[0]
+		Expect(container.Image).To(Equal("test/myimage"))
+		Expect(container.Env).To(ContainElements(
+			corev1.EnvVar{Name: common.ExporterTarget, Value: cc.SourceS3},
+			corev1.EnvVar{Name: common.ExporterEndpoint, Value: "http://minio:9000/bucket/disk.img"},
+			corev1.EnvVar{Name: common.ExporterFormat, Value: string(cdiv1.ExportJobFormatRaw)},
+			corev1.EnvVar{Name: common.ImporterCertDirVar, Value: common.ImporterCertDir},
+		))
+		Expect(container.VolumeMounts).To(ContainElements(
+			corev1.VolumeMount{Name: cc.DataVolName, MountPath: common.ImporterDataDir, ReadOnly: true},
+			corev1.VolumeMount{Name: CertVolName, MountPath: common.ImporterCertDir},
+		))
+		Expect(pod.Spec.Volumes[0].PersistentVolumeClaim.ReadOnly).To(BeTrue())
+		_, hasScratch := getScratchNameFromPod(pod)
+		Expect(hasScratch).To(BeFalse())
+		Expect(getExportJob(r).Status.Phase).To(Equal(cdiv1.ExportJobRunning))
+
+		terminatePod(r, corev1.PodSucceeded, 0, `{"message": "Export Complete", "checksum": "sha256:1234"}`)
+		reconcileExportJob(r)
+		job := getExportJob(r)
+		Expect(job.Status.Phase).To(Equal(cdiv1.ExportJobSucceeded))
+		Expect(job.Status.Digest).To(Equal("sha256:1234"))
+		_, err = getPod(r)
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should export a block PVC to a registry in qcow2", func() {
+		job := createExportJob()
+		job.Spec.Target = cdiv1.ExportJobTarget{
+			Registry: &cdiv1.ExportJobTargetRegistry{URL: "docker://registry:5000/disks/disk:latest"},
+		}
+		job.Spec.Format = ptr.To(cdiv1.ExportJobFormatQcow2)
+		pvc := cc.CreatePvc(testPvcName, testNamespace, nil, nil)
+		pvc.Spec.VolumeMode = ptr.To(corev1.PersistentVolumeBlock)
+		r := createExportJobReconciler(job, pvc)
+		reconcileExportJob(r)
+
+		pod, err := getPod(r)
+		Expect(err).ToNot(HaveOccurred())
+		container := pod.Spec.Containers[0]
+		Expect(container.Env).To(ContainElements(
+			corev1.EnvVar{Name: common.ExporterTarget, Value: cc.SourceRegistry},
+			corev1.EnvVar{Name: common.ExporterFormat, Value: string(cdiv1.ExportJobFormatQcow2)},
+			corev1.EnvVar{Name: common.InsecureTLSVar, Value: "true"},
+		))
+		Expect(container.VolumeDevices).To(Equal(cc.AddVolumeDevices()))
+		Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: cc.ScratchVolName, MountPath: common.ScratchDataDir}))
+		scratchPvc := &corev1.PersistentVolumeClaim{}
+		Expect(r.client.Get(context.TODO(), types.NamespacedName{Name: exportJobScratch, Namespace: testNamespace}, scratchPvc)).To(Succeed())
+		Expect(metav1.IsControlledBy(scratchPvc, pod)).To(BeTrue())
+	})
+
+	It("Should fail the ExportJob when the export pod fails", func() {
+		r := createExportJobReconciler(createExportJob(), cc.CreatePvc(testPvcName, testNamespace, nil, nil))
+		reconcileExportJob(r)
+		terminatePod(r, corev1.PodFailed, 1, "Access Denied")
+		reconcileExportJob(r)
+		job := getExportJob(r)
+		Expect(job.Status.Phase).To(Equal(cdiv1.ExportJobFailed))
+		Expect(job.Status.Message).To(Equal("Access Denied"))
+
+		// Failed ExportJobs are not retried
+		Expect(r.client.Delete(context.TODO(), &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: exportJobPod, Namespace: testNamespace}})).To(Succeed())
+		reconcileExportJob(r)
+		_, err := getPod(r)
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	DescribeTable("Should reject invalid ExportJobs", func(mutate func(*cdiv1.ExportJob), message string) {
+		job := createExportJob()
+		mutate(job)
+		r := createExportJobReconciler(job, cc.CreatePvc(testPvcName, testNamespace, nil, nil))
+		reconcileExportJob(r)
+		job = getExportJob(r)
+		Expect(job.Status.Phase).To(Equal(cdiv1.ExportJobFailed))
+		Expect(job.Status.Message).To(ContainSubstring(message))
+		_, err := getPod(r)
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	},
+		Entry("with both targets", func(job *cdiv1.ExportJob) {
+			job.Spec.Target.Registry = &cdiv1.ExportJobTargetRegistry{URL: "docker://registry/disk"}
+		}, "exactly one"),
+		Entry("without a target", func(job *cdiv1.ExportJob) {
+			job.Spec.Target.S3 = nil
+		}, "exactly one"),
+		Entry("with compressed registry target", func(job *cdiv1.ExportJob) {
+			job.Spec.Target = cdiv1.ExportJobTarget{Registry: &cdiv1.ExportJobTargetRegistry{URL: "docker://registry/disk"}}
+			job.Spec.Compress = ptr.To(true)
+		}, "compress"),
+		Entry("with unknown format", func(job *cdiv1.ExportJob) {
+			job.Spec.Format = ptr.To(cdiv1.ExportJobFormat("vmdk"))
+		}, "unsupported format"),
+		Entry("with unknown source kind", func(job *cdiv1.ExportJob) {
+			job.Spec.Source.Kind = "VolumeSnapshot"
+		}, "unsupported source kind"),
+	)
+
+	It("Should wait for the source PVC to be populated", func() {
+		pvc := cc.CreatePvc(testPvcName, testNamespace, map[string]string{cc.AnnEndpoint: "http://example.com/disk.img"}, nil)
+		r := createExportJobReconciler(createExportJob(), pvc)
+		reconcileExportJob(r)
+		_, err := getPod(r)
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		job := getExportJob(r)
+		Expect(job.Status.Phase).To(Equal(cdiv1.ExportJobPending))
+		Expect(job.Status.Message).To(ContainSubstring("not populated yet"))
+
+		pvc.Annotations[cc.AnnPodPhase] = string(corev1.PodSucceeded)
+		Expect(r.client.Update(context.TODO(), pvc)).To(Succeed())
+		reconcileExportJob(r)
+		_, err = getPod(r)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should wait for the source DataVolume to succeed", func() {
+		job := createExportJob()
+		job.Spec.Source = corev1.TypedLocalObjectReference{APIGroup: ptr.To(cdiv1.SchemeGroupVersion.Group), Kind: "DataVolume", Name: testPvcName}
+		dv := cc.NewImportDataVolume(testPvcName)
+		dv.Status.Phase = cdiv1.ImportInProgress
+		r := createExportJobReconciler(job, dv, cc.CreatePvc(testPvcName, testNamespace, nil, nil))
+		reconcileExportJob(r)
+		_, err := getPod(r)
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		Expect(getExportJob(r).Status.Message).To(ContainSubstring("DataVolume testpvc is not populated yet"))
+
+		dv.Status.Phase = cdiv1.Succeeded
+		Expect(r.client.Update(context.TODO(), dv)).To(Succeed())
+		reconcileExportJob(r)
+		_, err = getPod(r)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should wait for the pods writing to the source PVC", func() {
+		pvc := cc.CreatePvc(testPvcName, testNamespace, nil, nil)
+		r := createExportJobReconciler(createExportJob(), pvc, podUsingPVC(pvc, false))
+		Expect(reconcileExportJob(r).Requeue).To(BeTrue())
+		_, err := getPod(r)
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		Expect(getExportJob(r).Status.Phase).To(Equal(cdiv1.ExportJobPending))
+	})
+})
+
+func createExportJobReconciler(objects ...runtime.Object) *ExportJobReconciler {
+	objs := []runtime.Object{}
+	objs = append(objs, objects...)
+	objs = append(objs, cc.MakeEmptyCDICR())
+	cdiConfig := cc.MakeEmptyCDIConfigSpec(common.ConfigName)
+	cdiConfig.Spec.InsecureRegistries = []string{"registry:5000"}
+	objs = append(objs, cdiConfig)
+
+	s := scheme.Scheme
+	_ = cdiv1.AddToScheme(s)
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objs...).WithStatusSubresource(&cdiv1.ExportJob{}).Build()
+
+	return &ExportJobReconciler{
+		client:     cl,
+		scheme:     s,
+		log:        exportJobLog,
+		recorder:   record.NewFakeRecorder(10),
+		image:      "test/myimage",
+		verbose:    "5",
+		pullPolicy: "Always",
+	}
+}
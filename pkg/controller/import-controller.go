@@ -105,6 +105,17 @@ type importPodEnvVar struct {
 	cacheMode                 string
 	registryImageArchitecture string
 	checksum                  string
+	checksumURL               string
+	checksumKeyringSecret     string
+	diskPath                  string
+	diskIndex                 string
+	signaturePolicy           string
+	signatureIssuer           string
+	signatureSubject          string
+	signatureConfigMap        string
+	signatureSecret           string
+	hostKeySecret             string
+	nfsClaimName              string
 }
 
 type importerPodArgs struct {
@@ -378,6 +389,22 @@ func (r *ImportReconciler) initPvcPodName(pvc *corev1.PersistentVolumeClaim, log
 	return nil
 }
 
+// getFatalImportErrorReason returns the running condition reason of a permanent importer error reported in the
+// termination message, or an empty string if the termination message doesn't report one. The importer reports
+// ErrChecksumMismatch and ErrSignatureVerification as permanent errors.
+func getFatalImportErrorReason(termMsg *common.TerminationMessage) string {
+	if termMsg == nil || termMsg.Message == nil {
+		return ""
+	}
+	switch {
+	case strings.Contains(*termMsg.Message, "checksum mismatch"):
+		return "ChecksumError"
+	case strings.Contains(*termMsg.Message, "signature verification failed"):
+		return "SignatureVerificationFailed"
+	}
+	return ""
+}
+
 func (r *ImportReconciler) updatePvcFromPod(pvc *corev1.PersistentVolumeClaim, pod *corev1.Pod, log logr.Logger) error {
 	// Keep a copy of the original for comparison later.
 	currentPvcCopy := pvc.DeepCopyObject()
@@ -394,16 +421,15 @@ func (r *ImportReconciler) updatePvcFromPod(pvc *corev1.PersistentVolumeClaim, p
 	// Handle fatal errors reported via termination message even when the container exited with code 0.
 	// In this branch we intentionally override the actual Pod phase (Succeeded) and mark the PVC as Failed + fatal
 	// to stop retries; this keeps DV status aligned with the fatal outcome rather than the Pod phase.
-	// Check for "checksum mismatch" which is the error message from ErrChecksumMismatch.
-	if termMsg != nil && termMsg.Message != nil && strings.Contains(*termMsg.Message, "checksum mismatch") {
-		log.Info("Checksum validation failed via termination message (permanent error, will not retry)", "pod.Name", pod.Name)
+	if reason := getFatalImportErrorReason(termMsg); reason != "" {
+		log.Info("Import failed with permanent error via termination message (will not retry)", "pod.Name", pod.Name, "reason", reason)
 		anno[cc.AnnPodPhase] = string(corev1.PodFailed) // override Succeeded to reflect fatal state
 		anno[cc.AnnImportFatalError] = "true"
 		anno[cc.AnnRunningCondition] = "false"
 		if anno[cc.AnnRunningConditionMessage] == "" {
 			anno[cc.AnnRunningConditionMessage] = simplifyKnownMessage(*termMsg.Message)
 		}
-		anno[cc.AnnRunningConditionReason] = "ChecksumError"
+		anno[cc.AnnRunningConditionReason] = reason
 		r.recorder.Event(pvc, corev1.EventTypeWarning, ErrImportFailedPVC, simplifyKnownMessage(*termMsg.Message))
 	}
 
@@ -660,6 +686,18 @@ func (r *ImportReconciler) createImportEnvVar(pvc *corev1.PersistentVolumeClaim)
 		podEnvVar.finalCheckpoint = getValueFromAnnotation(pvc, cc.AnnFinalCheckpoint)
 		podEnvVar.registryImageArchitecture = getValueFromAnnotation(pvc, cc.AnnRegistryImageArchitecture)
 		podEnvVar.checksum = getValueFromAnnotation(pvc, cc.AnnChecksum)
+		podEnvVar.checksumURL = getValueFromAnnotation(pvc, cc.AnnChecksumURL)
+		podEnvVar.checksumKeyringSecret = getValueFromAnnotation(pvc, cc.AnnChecksumKeyringSecret)
+		podEnvVar.diskPath = getValueFromAnnotation(pvc, cc.AnnDiskPath)
+		podEnvVar.diskIndex = getValueFromAnnotation(pvc, cc.AnnDiskIndex)
+		setSignaturePolicyEnvVars(pvc, podEnvVar)
+		podEnvVar.hostKeySecret = getValueFromAnnotation(pvc, cc.AnnHostKeySecret)
+		if podEnvVar.source == cc.SourceNFS {
+			podEnvVar.nfsClaimName = getValueFromAnnotation(pvc, cc.AnnNFSClaimName)
+			if podEnvVar.nfsClaimName == "" {
+				return nil, errors.Errorf("NFS import of %s/%s has no claim name", pvc.Namespace, pvc.Name)
+			}
+		}
 
 		for annotation, value := range pvc.Annotations {
 			if strings.HasPrefix(annotation, cc.AnnExtraHeaders) {
@@ -828,7 +866,7 @@ func (r *ImportReconciler) createScratchPvcForPod(pvc *corev1.PersistentVolumeCl
 
 		storageClassName := GetScratchPvcStorageClass(r.client, pvc)
 		// Scratch PVC doesn't exist yet, create it. Determine which storage class to use.
-		_, err = createScratchPersistentVolumeClaim(r.client, pvc, pod, scratchPVCName, storageClassName, r.installerLabels, r.recorder)
+		_, err = createScratchPersistentVolumeClaim(r.client, pvc, MakePodOwnerReference(pod), scratchPVCName, storageClassName, r.installerLabels, r.recorder)
 		if err != nil {
 			return err
 		}
@@ -914,6 +952,22 @@ func getValueFromAnnotation(pvc *corev1.PersistentVolumeClaim, annotation string
 	return pvc.Annotations[annotation]
 }
 
+// setSignaturePolicyEnvVars sets the image signature verification policy from the PVC annotations
+func setSignaturePolicyEnvVars(pvc *corev1.PersistentVolumeClaim, podEnvVar *importPodEnvVar) {
+	if configMap := getValueFromAnnotation(pvc, cc.AnnSignatureTrustedRootConfigMap); configMap != "" {
+		podEnvVar.signaturePolicy = common.SignaturePolicyKeyless
+		podEnvVar.signatureConfigMap = configMap
+		podEnvVar.signatureIssuer = getValueFromAnnotation(pvc, cc.AnnSignatureIssuer)
+		podEnvVar.signatureSubject = getValueFromAnnotation(pvc, cc.AnnSignatureSubject)
+		return
+	}
+	podEnvVar.signatureConfigMap = getValueFromAnnotation(pvc, cc.AnnSignaturePublicKeyConfigMap)
+	podEnvVar.signatureSecret = getValueFromAnnotation(pvc, cc.AnnSignaturePublicKeySecret)
+	if podEnvVar.signatureConfigMap != "" || podEnvVar.signatureSecret != "" {
+		podEnvVar.signaturePolicy = common.SignaturePolicyPublicKey
+	}
+}
+
 // If this pod is going to transfer one checkpoint in a multi-stage import, attach the checkpoint name to the pod name so
 // that each checkpoint gets a unique pod. That way each pod can be inspected using the retainAfterCompletion annotation.
 func podNameWithCheckpoint(pvc *corev1.PersistentVolumeClaim) string {
@@ -1103,11 +1157,15 @@ func makeImporterContainerSpec(args *importerPodArgs) []corev1.Container {
 		containers[0].VolumeMounts = cc.AddImportVolumeMounts()
 	}
 	if isRegistryNodeImport(args) {
+		serverCommand := []string{"/shared/server", "-p", "8100", "-image-dir", "/disk", "-ready-file", "/shared/ready", "-done-file", "/shared/done"}
+		if args.podEnvVar.diskPath != "" {
+			serverCommand = append(serverCommand, "-disk-path", args.podEnvVar.diskPath)
+		}
 		containers = append(containers, corev1.Container{
 			Name:            "server",
 			Image:           args.importImage,
 			ImagePullPolicy: corev1.PullPolicy(args.pullPolicy),
-			Command:         []string{"/shared/server", "-p", "8100", "-image-dir", "/disk", "-ready-file", "/shared/ready", "-done-file", "/shared/done"},
+			Command:         serverCommand,
 			VolumeMounts: []corev1.VolumeMount{
 				{
 					MountPath: "/shared",
@@ -1151,12 +1209,47 @@ func makeImporterContainerSpec(args *importerPodArgs) []corev1.Container {
 			MountPath: common.ImporterProxyCertDir,
 		})
 	}
+	if args.podEnvVar.signatureConfigMap != "" || args.podEnvVar.signatureSecret != "" {
+		containers[0].VolumeMounts = append(containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      SignatureVolName,
+			MountPath: common.ImporterSignatureDir,
+			ReadOnly:  true,
+		})
+	}
+	if args.podEnvVar.checksumKeyringSecret != "" {
+		containers[0].VolumeMounts = append(containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      ChecksumKeyringVolName,
+			MountPath: common.ImporterChecksumKeyringDir,
+			ReadOnly:  true,
+		})
+	}
 	if args.podEnvVar.source == cc.SourceGCS && args.podEnvVar.secretName != "" {
 		containers[0].VolumeMounts = append(containers[0].VolumeMounts, corev1.VolumeMount{
 			Name:      SecretVolName,
 			MountPath: common.ImporterGoogleCredentialDir,
 		})
 	}
+	if args.podEnvVar.source == cc.SourceSFTP && args.podEnvVar.secretName != "" {
+		containers[0].VolumeMounts = append(containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      SecretVolName,
+			MountPath: common.ImporterSFTPCredentialDir,
+			ReadOnly:  true,
+		})
+	}
+	if args.podEnvVar.hostKeySecret != "" {
+		containers[0].VolumeMounts = append(containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      HostKeyVolName,
+			MountPath: common.ImporterSFTPHostKeyDir,
+			ReadOnly:  true,
+		})
+	}
+	if args.podEnvVar.nfsClaimName != "" {
+		containers[0].VolumeMounts = append(containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      NFSVolName,
+			MountPath: common.ImporterNFSDir,
+			ReadOnly:  true,
+		})
+	}
 	for index := range args.podEnvVar.secretExtraHeaders {
 		containers[0].VolumeMounts = append(containers[0].VolumeMounts, corev1.VolumeMount{
 			Name:      fmt.Sprintf(secretExtraHeadersVolumeName, index),
@@ -1228,9 +1321,31 @@ func makeImporterVolumeSpec(args *importerPodArgs) []corev1.Volume {
 	if args.podEnvVar.certConfigMapProxy != "" {
 		volumes = append(volumes, createConfigMapVolume(ProxyCertVolName, GetImportProxyConfigMapName(args.pvc.Name)))
 	}
-	if args.podEnvVar.source == cc.SourceGCS && args.podEnvVar.secretName != "" {
+	if args.podEnvVar.signatureConfigMap != "" {
+		volumes = append(volumes, createConfigMapVolume(SignatureVolName, args.podEnvVar.signatureConfigMap))
+	} else if args.podEnvVar.signatureSecret != "" {
+		volumes = append(volumes, createSecretVolume(SignatureVolName, args.podEnvVar.signatureSecret))
+	}
+	if args.podEnvVar.checksumKeyringSecret != "" {
+		volumes = append(volumes, createSecretVolume(ChecksumKeyringVolName, args.podEnvVar.checksumKeyringSecret))
+	}
+	if (args.podEnvVar.source == cc.SourceGCS || args.podEnvVar.source == cc.SourceSFTP) && args.podEnvVar.secretName != "" {
 		volumes = append(volumes, createSecretVolume(SecretVolName, args.podEnvVar.secretName))
 	}
+	if args.podEnvVar.hostKeySecret != "" {
+		volumes = append(volumes, createSecretVolume(HostKeyVolName, args.podEnvVar.hostKeySecret))
+	}
+	if args.podEnvVar.nfsClaimName != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: NFSVolName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: args.podEnvVar.nfsClaimName,
+					ReadOnly:  true,
+				},
+			},
+		})
+	}
 	for index, header := range args.podEnvVar.secretExtraHeaders {
 		volumes = append(volumes, corev1.Volume{
 			Name: fmt.Sprintf(secretExtraHeadersVolumeName, index),
@@ -1432,8 +1547,42 @@ func makeImportEnv(podEnvVar *importPodEnvVar, uid types.UID) []corev1.EnvVar {
 			Name:  common.ImporterChecksum,
 			Value: podEnvVar.checksum,
 		},
+		{
+			Name:  common.ImporterChecksumURL,
+			Value: podEnvVar.checksumURL,
+		},
+	}
+	if podEnvVar.checksumKeyringSecret != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterChecksumKeyring,
+			Value: path.Join(common.ImporterChecksumKeyringDir, common.ChecksumKeyringFile),
+		})
+	}
+	if podEnvVar.diskPath != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterDiskPath,
+			Value: podEnvVar.diskPath,
+		})
+	}
+	if podEnvVar.diskIndex != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterDiskIndex,
+			Value: podEnvVar.diskIndex,
+		})
+	}
+	if podEnvVar.signaturePolicy != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  common.ImporterSignaturePolicy,
+			Value: podEnvVar.signaturePolicy,
+		}, corev1.EnvVar{
+			Name:  common.ImporterSignatureIssuer,
+			Value: podEnvVar.signatureIssuer,
+		}, corev1.EnvVar{
+			Name:  common.ImporterSignatureSubject,
+			Value: podEnvVar.signatureSubject,
+		})
 	}
-	if podEnvVar.secretName != "" && podEnvVar.source != cc.SourceGCS {
+	if podEnvVar.secretName != "" && podEnvVar.source != cc.SourceGCS && podEnvVar.source != cc.SourceSFTP {
 		env = append(env, corev1.EnvVar{
 			Name: common.ImporterAccessKeyID,
 			ValueFrom: &corev1.EnvVarSource{
@@ -37,6 +37,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
+	"k8s.io/utils/ptr"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -1256,11 +1257,204 @@ var _ = Describe("Import test env", func() {
 			preallocation:             false,
 			registryImageArchitecture: "",
 			checksum:                  "",
+			checksumURL:               "https://www.example.com/SHA256SUMS",
 		}
 		Expect(reflect.DeepEqual(makeImportEnv(testEnvVar, mockUID), createImportTestEnv(testEnvVar, mockUID))).To(BeTrue())
 	})
 })
 
+var _ = Describe("Import signature verification", func() {
+	DescribeTable("should set the signature policy from the PVC annotations", func(annotations map[string]string, expected *importPodEnvVar) {
+		pvc := cc.CreatePvc("testPvc1", "default", annotations, nil)
+		podEnvVar := &importPodEnvVar{}
+		setSignaturePolicyEnvVars(pvc, podEnvVar)
+		Expect(podEnvVar).To(Equal(expected))
+	},
+		Entry("without signature verification", map[string]string{}, &importPodEnvVar{}),
+		Entry("with a public key in a ConfigMap",
+			map[string]string{cc.AnnSignaturePublicKeyConfigMap: "cosign-key"},
+			&importPodEnvVar{signaturePolicy: common.SignaturePolicyPublicKey, signatureConfigMap: "cosign-key"}),
+		Entry("with a public key in a Secret",
+			map[string]string{cc.AnnSignaturePublicKeySecret: "cosign-key"},
+			&importPodEnvVar{signaturePolicy: common.SignaturePolicyPublicKey, signatureSecret: "cosign-key"}),
+		Entry("with keyless verification",
+			map[string]string{cc.AnnSignatureTrustedRootConfigMap: "sigstore-root", cc.AnnSignatureIssuer: "https://accounts.example.com", cc.AnnSignatureSubject: "signer@example.com"},
+			&importPodEnvVar{signaturePolicy: common.SignaturePolicyKeyless, signatureConfigMap: "sigstore-root", signatureIssuer: "https://accounts.example.com", signatureSubject: "signer@example.com"}),
+	)
+
+	It("should mount the signature ConfigMap in the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{cc.AnnEndpoint: testEndPoint, cc.AnnImportPod: "podName"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podArgs := &importerPodArgs{
+			image:      testImage,
+			verbose:    "5",
+			pullPolicy: testPullPolicy,
+			podEnvVar: &importPodEnvVar{
+				imageSize:          "1G",
+				filesystemOverhead: "0.06",
+				signaturePolicy:    common.SignaturePolicyPublicKey,
+				signatureConfigMap: "cosign-key",
+			},
+			pvc: pvc,
+		}
+		pod, err := createImporterPod(context.TODO(), reconciler.log, reconciler.client, podArgs, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      SignatureVolName,
+			MountPath: common.ImporterSignatureDir,
+			ReadOnly:  true,
+		}))
+		Expect(pod.Spec.Volumes).To(ContainElement(createConfigMapVolume(SignatureVolName, "cosign-key")))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ImporterSignaturePolicy, Value: common.SignaturePolicyPublicKey}))
+	})
+
+	It("should mount the checksum keyring Secret in the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{cc.AnnEndpoint: testEndPoint, cc.AnnImportPod: "podName"}, nil)
+		reconciler := createImportReconciler(pvc)
+		podArgs := &importerPodArgs{
+			image:      testImage,
+			verbose:    "5",
+			pullPolicy: testPullPolicy,
+			podEnvVar: &importPodEnvVar{
+				imageSize:             "1G",
+				filesystemOverhead:    "0.06",
+				checksumURL:           "https://www.example.com/CHECKSUM",
+				checksumKeyringSecret: "keyring",
+			},
+			pvc: pvc,
+		}
+		pod, err := createImporterPod(context.TODO(), reconciler.log, reconciler.client, podArgs, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      ChecksumKeyringVolName,
+			MountPath: common.ImporterChecksumKeyringDir,
+			ReadOnly:  true,
+		}))
+		Expect(pod.Spec.Volumes).To(ContainElement(createSecretVolume(ChecksumKeyringVolName, "keyring")))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: common.ImporterChecksumKeyring, Value: "/checksum-keyring/keyring.asc"}))
+	})
+
+	DescribeTable("should detect permanent errors in the termination message", func(message *string, expected string) {
+		Expect(getFatalImportErrorReason(&common.TerminationMessage{Message: message})).To(Equal(expected))
+	},
+		Entry("with no message", nil, ""),
+		Entry("with a transient error", ptr.To("Unable to connect to http data source"), ""),
+		Entry("with a checksum mismatch", ptr.To("checksum mismatch: expected sha256:abc, calculated sha256:def"), "ChecksumError"),
+		Entry("with a signature verification failure", ptr.To("no valid signature found: signature verification failed"), "SignatureVerificationFailed"),
+	)
+})
+
+var _ = Describe("Import disk selection", func() {
+	It("should pass the selected disk to the importer", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:  testEndPoint,
+			cc.AnnDiskPath:  "disks/data.qcow2",
+			cc.AnnDiskIndex: "1",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.diskPath).To(Equal("disks/data.qcow2"))
+		Expect(podEnvVar.diskIndex).To(Equal("1"))
+		env := makeImportEnv(podEnvVar, types.UID("test-uid"))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ImporterDiskPath, Value: "disks/data.qcow2"}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: common.ImporterDiskIndex, Value: "1"}))
+	})
+
+	It("should pass the disk path to the server with the node pull method", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnSource:               cc.SourceRegistry,
+			cc.AnnRegistryImportMethod: string(cdiv1.RegistryPullNode),
+		}, nil)
+		containers := makeImporterContainerSpec(&importerPodArgs{
+			image:       testImage,
+			importImage: "registry:5000/test",
+			verbose:     "5",
+			pullPolicy:  testPullPolicy,
+			podEnvVar:   &importPodEnvVar{diskPath: "data.qcow2"},
+			pvc:         pvc,
+		})
+		Expect(containers).To(HaveLen(2))
+		Expect(containers[1].Command).To(HaveExactElements("/shared/server", "-p", "8100", "-image-dir", "/disk",
+			"-ready-file", "/shared/ready", "-done-file", "/shared/done", "-disk-path", "data.qcow2"))
+	})
+})
+
+var _ = Describe("Import from file shares", func() {
+	It("should mount the SFTP credentials and host keys in the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:      "sftp://fileserver/images/disk.img",
+			cc.AnnSource:        cc.SourceSFTP,
+			cc.AnnImportPod:     "podName",
+			cc.AnnHostKeySecret: "hostkeys",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podEnvVar.hostKeySecret).To(Equal("hostkeys"))
+		podEnvVar.secretName = "creds"
+		podArgs := &importerPodArgs{
+			image:      testImage,
+			verbose:    "5",
+			pullPolicy: testPullPolicy,
+			podEnvVar:  podEnvVar,
+			pvc:        pvc,
+		}
+		pod, err := createImporterPod(context.TODO(), reconciler.log, reconciler.client, podArgs, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElements(
+			corev1.VolumeMount{Name: SecretVolName, MountPath: common.ImporterSFTPCredentialDir, ReadOnly: true},
+			corev1.VolumeMount{Name: HostKeyVolName, MountPath: common.ImporterSFTPHostKeyDir, ReadOnly: true},
+		))
+		Expect(pod.Spec.Volumes).To(ContainElements(createSecretVolume(SecretVolName, "creds"), createSecretVolume(HostKeyVolName, "hostkeys")))
+		for _, env := range pod.Spec.Containers[0].Env {
+			Expect(env.Name).ToNot(Equal(common.ImporterAccessKeyID))
+		}
+	})
+
+	It("should mount the claim of the NFS export in the importer pod", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint:     "images/disk.img",
+			cc.AnnSource:       cc.SourceNFS,
+			cc.AnnNFSClaimName: "nfs-images",
+			cc.AnnImportPod:    "podName",
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		podEnvVar, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).ToNot(HaveOccurred())
+		podArgs := &importerPodArgs{
+			image:      testImage,
+			verbose:    "5",
+			pullPolicy: testPullPolicy,
+			podEnvVar:  podEnvVar,
+			pvc:        pvc,
+		}
+		pod, err := createImporterPod(context.TODO(), reconciler.log, reconciler.client, podArgs, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(
+			corev1.VolumeMount{Name: NFSVolName, MountPath: common.ImporterNFSDir, ReadOnly: true}))
+		Expect(pod.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name: NFSVolName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "nfs-images", ReadOnly: true},
+			},
+		}))
+		for _, volume := range pod.Spec.Volumes {
+			Expect(volume.NFS).To(BeNil())
+		}
+	})
+
+	It("should fail an NFS import without a claim name", func() {
+		pvc := cc.CreatePvc("testPvc1", "default", map[string]string{
+			cc.AnnEndpoint: "images/disk.img",
+			cc.AnnSource:   cc.SourceNFS,
+		}, nil)
+		reconciler := createImportReconciler(pvc)
+		_, err := reconciler.createImportEnvVar(pvc)
+		Expect(err).To(MatchError(ContainSubstring("has no claim name")))
+	})
+})
+
 var _ = Describe("getSecretName", func() {
 	It("should find a secret", func() {
 		pvcWithAnno := cc.CreatePvc("testPVCWithAnno", "default", map[string]string{cc.AnnSecret: "mysecret"}, nil)
@@ -1575,6 +1769,10 @@ func createImportTestEnv(podEnvVar *importPodEnvVar, uid string) []corev1.EnvVar
 			Name:  common.ImporterChecksum,
 			Value: podEnvVar.checksum,
 		},
+		{
+			Name:  common.ImporterChecksumURL,
+			Value: podEnvVar.checksumURL,
+		},
 	}
 
 	if podEnvVar.secretName != "" {
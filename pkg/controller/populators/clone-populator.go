@@ -18,7 +18,6 @@ package populators
 
 import (
 	"context"
-	"crypto/rsa"
 	"fmt"
 	"time"
 
@@ -44,6 +43,7 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/controller/clone"
 	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
 	featuregates "kubevirt.io/containerized-data-importer/pkg/feature-gates"
+	"kubevirt.io/containerized-data-importer/pkg/token"
 )
 
 const (
@@ -193,7 +193,7 @@ func NewClonePopulator(
 	clonerImage string,
 	pullPolicy string,
 	installerLabels map[string]string,
-	publicKey *rsa.PublicKey,
+	tokenKeys token.VerificationKeySource,
 ) (controller.Controller, error) {
 	client := mgr.GetClient()
 	reconciler := &ClonePopulatorReconciler{
@@ -206,7 +206,7 @@ func NewClonePopulator(
 			sourceKind:      cdiv1.VolumeCloneSourceRef,
 			installerLabels: installerLabels,
 		},
-		multiTokenValidator: cc.NewMultiTokenValidator(publicKey),
+		multiTokenValidator: cc.NewMultiTokenValidator(tokenKeys),
 	}
 
 	clonePopulator, err := controller.New(clonePopulatorName, mgr, controller.Options{
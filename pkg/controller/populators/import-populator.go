@@ -245,6 +245,18 @@ func (r *ImportPopulatorReconciler) updatePVCForPopulation(pvc *corev1.Persisten
 		cc.UpdateGCSAnnotations(annotations, gcs)
 		return
 	}
+	if sftp := volumeImportSource.Spec.Source.SFTP; sftp != nil {
+		cc.UpdateSFTPAnnotations(annotations, sftp)
+		return
+	}
+	if smb := volumeImportSource.Spec.Source.SMB; smb != nil {
+		cc.UpdateSMBAnnotations(annotations, smb)
+		return
+	}
+	if nfs := volumeImportSource.Spec.Source.NFS; nfs != nil {
+		cc.UpdateNFSAnnotations(annotations, nfs)
+		return
+	}
 	if registry := volumeImportSource.Spec.Source.Registry; registry != nil {
 		cc.UpdateRegistryAnnotations(annotations, registry)
 		return
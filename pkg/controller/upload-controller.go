@@ -98,6 +98,7 @@ type UploadReconciler struct {
 	clientCAFetcher     fetcher.CertBundleFetcher
 	featureGates        featuregates.FeatureGates
 	installerLabels     map[string]string
+	sourceClient        cc.CloneSourceClientFunc
 }
 
 // UploadPodArgs are the parameters required to create an upload pod
@@ -111,6 +112,10 @@ type UploadPodArgs struct {
 	Preallocation                   string
 	CryptoEnvVars                   CryptoEnvVars
 	Deadline                        *time.Time
+	// Export runs the upload server as an export server, mounting the PVC read only
+	Export bool
+	// CloneTransformation is the transformation of the disk image of the clone source, nil if it is cloned as is
+	CloneTransformation *cdiv1.CloneTransformation
 }
 
 // CryptoEnvVars holds the TLS crypto-related configurables for the upload server
@@ -329,8 +334,15 @@ func (r *UploadReconciler) getCloneRequestSourcePVC(targetPvc *corev1.Persistent
 	if !exists {
 		return nil, errors.New("error parsing clone request annotation")
 	}
+	sourceClient := r.client
+	if cluster := cc.GetCloneSourceCluster(targetPvc); cluster != nil {
+		var err error
+		if sourceClient, err = r.sourceClient(context.TODO(), r.client, targetPvc.Namespace, cluster); err != nil {
+			return nil, err
+		}
+	}
 	sourcePvc := &corev1.PersistentVolumeClaim{}
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, sourcePvc); err != nil {
+	if err := sourceClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, sourcePvc); err != nil {
 		return nil, errors.Wrap(err, "error getting clone source PVC")
 	}
 	if sourcePvc.Spec.VolumeMode != nil {
@@ -359,6 +371,11 @@ func (r *UploadReconciler) cleanup(pvc *corev1.PersistentVolumeClaim) error {
 		return err
 	}
 
+	// delete scratch PVC, it is not deleted with the pod
+	if err := r.deleteScratchPvc(pvc); err != nil {
+		return err
+	}
+
 	// delete pod
 	pod := &corev1.Pod{}
 	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: resourceName, Namespace: pvc.Namespace}, pod); err != nil {
@@ -392,15 +409,29 @@ func (r *UploadReconciler) findUploadPodForPvc(pvc *corev1.PersistentVolumeClaim
 }
 
 func (r *UploadReconciler) createUploadPodForPvc(pvc *corev1.PersistentVolumeClaim, podName, clientName string, isCloneTarget bool) (*corev1.Pod, error) {
+	return r.createServerPodForPvc(pvc, podName, clientName, createScratchPvcNameFromPvc(pvc, isCloneTarget), false)
+}
+
+// createServerPodForPvc creates the upload server pod of the PVC, the pod runs as an export server if export is set
+func (r *UploadReconciler) createServerPodForPvc(pvc *corev1.PersistentVolumeClaim, podName, clientName, scratchPVCName string, export bool) (*corev1.Pod, error) {
 	certConfig, err := operator.GetCertConfigWithDefaults(context.TODO(), r.client)
 	if err != nil {
 		return nil, err
 	}
 
+	// the clone source pod of a remote cluster may reach the service through another domain
+	var domains []string
+	if cluster := cc.GetCloneSourceCluster(pvc); cluster != nil && cluster.ServiceDomain != "" {
+		if err := cc.ValidateServiceDomain(cluster.ServiceDomain); err != nil {
+			return nil, err
+		}
+		domains = append(domains, cluster.ServiceDomain)
+	}
 	serverCert, serverKey, err := r.serverCertGenerator.MakeServerCert(
 		pvc.Namespace,
 		naming.GetServiceNameFromResourceName(podName),
 		certConfig.Server.Duration.Duration,
+		domains...,
 	)
 	if err != nil {
 		return nil, err
@@ -437,7 +468,7 @@ func (r *UploadReconciler) createUploadPodForPvc(pvc *corev1.PersistentVolumeCla
 	args := UploadPodArgs{
 		Name:               podName,
 		PVC:                pvc,
-		ScratchPVCName:     createScratchPvcNameFromPvc(pvc, isCloneTarget),
+		ScratchPVCName:     scratchPVCName,
 		ClientName:         clientName,
 		FilesystemOverhead: string(fsOverhead),
 		ServerCert:         serverCert,
@@ -446,6 +477,10 @@ func (r *UploadReconciler) createUploadPodForPvc(pvc *corev1.PersistentVolumeCla
 		Preallocation:      strconv.FormatBool(preallocationRequested),
 		CryptoEnvVars:      cryptoVars,
 		Deadline:           ptr.To(time.Now().Add(min(serverRefresh, clientRefresh))),
+		Export:             export,
+	}
+	if !export {
+		args.CloneTransformation = cc.GetCloneTransformation(pvc)
 	}
 
 	r.log.V(3).Info("Creating upload pod")
@@ -476,14 +511,16 @@ func (r *UploadReconciler) getOrCreateScratchPvc(pvc *corev1.PersistentVolumeCla
 		anno[cc.AnnBoundCondition] = "false"
 		anno[cc.AnnBoundConditionMessage] = "Creating scratch space"
 		anno[cc.AnnBoundConditionReason] = creatingScratch
-		// Scratch PVC doesn't exist yet, create it.
-		scratchPvc, err = createScratchPersistentVolumeClaim(r.client, pvc, pod, name, storageClassName, map[string]string{}, r.recorder)
+		// Scratch PVC doesn't exist yet, create it. It is owned by the PVC, so that the session of a resumable upload
+		// survives the upload pod.
+		scratchPvc, err = createScratchPersistentVolumeClaim(r.client, pvc, MakePVCOwnerReference(pvc), name, storageClassName, map[string]string{}, r.recorder)
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		if !metav1.IsControlledBy(scratchPvc, pod) {
-			return nil, errors.Errorf("%s scratch PVC not controlled by pod %s", scratchPvc.Name, pod.Name)
+		// Scratch PVCs created by previous versions are owned by the pod
+		if !metav1.IsControlledBy(scratchPvc, pvc) && !metav1.IsControlledBy(scratchPvc, pod) {
+			return nil, errors.Errorf("%s scratch PVC not controlled by pvc %s", scratchPvc.Name, pvc.Name)
 		}
 	}
 
@@ -527,6 +564,25 @@ func (r *UploadReconciler) deleteService(namespace, serviceName string) error {
 	return nil
 }
 
+func (r *UploadReconciler) deleteScratchPvc(pvc *corev1.PersistentVolumeClaim) error {
+	scratchPvc := &corev1.PersistentVolumeClaim{}
+	name := createScratchPvcNameFromPvc(pvc, false)
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: pvc.Namespace}, scratchPvc); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if scratchPvc.DeletionTimestamp == nil && metav1.IsControlledBy(scratchPvc, pvc) {
+		if err := r.client.Delete(context.TODO(), scratchPvc); cc.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, "error deleting scratch PVC")
+		}
+	}
+
+	return nil
+}
+
 func isPodReady(pod *corev1.Pod) bool {
 	if len(pod.Status.ContainerStatuses) == 0 {
 		return false
@@ -695,6 +751,7 @@ func NewUploadController(mgr manager.Manager, log logr.Logger, uploadImage, pull
 		clientCAFetcher:     clientCAFetcher,
 		featureGates:        featuregates.NewFeatureGates(client),
 		installerLabels:     installerLabels,
+		sourceClient:        cc.GetCloneSourceClient,
 	}
 	uploadController, err := controller.New("upload-controller", mgr, controller.Options{
 		MaxConcurrentReconciles: 3,
@@ -762,8 +819,17 @@ func UploadPossibleForPVC(pvc *corev1.PersistentVolumeClaim) error {
 
 // GetUploadServerURL returns the url the proxy should post to for a particular pvc
 func GetUploadServerURL(namespace, pvc, uploadPath string) string {
-	serviceName := createUploadServiceNameFromPvcName(pvc)
-	return fmt.Sprintf("https://%s.%s.svc:%d%s", serviceName, namespace, common.UploadServerPort, uploadPath)
+	return getUploadServerURLInDomain(namespace, pvc, "", uploadPath)
+}
+
+// getUploadServerURLInDomain returns the url of the upload server of the PVC in the domain of the services, like for a
+// clone source pod of a remote cluster, the domain of the local cluster is used when domain is empty
+func getUploadServerURLInDomain(namespace, pvc, domain, uploadPath string) string {
+	host := fmt.Sprintf("%s.%s.svc", createUploadServiceNameFromPvcName(pvc), namespace)
+	if domain != "" {
+		host += "." + domain
+	}
+	return fmt.Sprintf("https://%s:%d%s", host, common.UploadServerPort, uploadPath)
 }
 
 // createUploadServiceName returns the name given to upload service shortened if needed
@@ -784,7 +850,6 @@ func (r *UploadReconciler) makeUploadPodSpec(args UploadPodArgs, resourceRequire
 				common.CDILabelKey:              common.CDILabelValue,
 				common.CDIComponentLabel:        common.UploadServerCDILabel,
 				common.UploadServerServiceLabel: naming.GetServiceNameFromResourceName(args.Name),
-				common.UploadTargetLabel:        string(args.PVC.UID),
 			},
 			OwnerReferences: []metav1.OwnerReference{
 				MakePVCOwnerReference(args.PVC),
@@ -807,6 +872,10 @@ func (r *UploadReconciler) makeUploadPodSpec(args UploadPodArgs, resourceRequire
 		},
 	}
 
+	if !args.Export {
+		pod.Labels[common.UploadTargetLabel] = string(args.PVC.UID)
+	}
+
 	cc.CopyAllowedAnnotations(args.PVC, pod)
 	cc.SetNodeNameIfPopulator(args.PVC, &pod.Spec)
 	cc.SetRestrictedSecurityContext(&pod.Spec)
@@ -886,6 +955,26 @@ func (r *UploadReconciler) makeUploadPodContainers(args UploadPodArgs, resourceR
 			Value: args.Deadline.Format(time.RFC3339),
 		})
 	}
+	if args.Export {
+		containers[0].Env = append(containers[0].Env, corev1.EnvVar{
+			Name:  common.ExportMode,
+			Value: "true",
+		})
+	}
+	if transformation := args.CloneTransformation; transformation != nil {
+		if transformation.Resize {
+			containers[0].Env = append(containers[0].Env, corev1.EnvVar{
+				Name:  common.CloneResize,
+				Value: "true",
+			})
+		}
+		if transformation.LogicalSectorSize != nil {
+			containers[0].Env = append(containers[0].Env, corev1.EnvVar{
+				Name:  common.CloneLogicalSectorSize,
+				Value: strconv.Itoa(int(*transformation.LogicalSectorSize)),
+			})
+		}
+	}
 	if cc.GetVolumeMode(args.PVC) == corev1.PersistentVolumeBlock {
 		containers[0].VolumeDevices = append(containers[0].VolumeDevices, corev1.VolumeDevice{
 			Name:       cc.DataVolName,
@@ -899,6 +988,7 @@ func (r *UploadReconciler) makeUploadPodContainers(args UploadPodArgs, resourceR
 		containers[0].VolumeMounts = append(containers[0].VolumeMounts, corev1.VolumeMount{
 			Name:      cc.DataVolName,
 			MountPath: common.UploadServerDataDir,
+			ReadOnly:  args.Export,
 		})
 	}
 	if args.ScratchPVCName != "" {
@@ -928,7 +1018,7 @@ func (r *UploadReconciler) makeUploadPodVolumes(args UploadPodArgs) []corev1.Vol
 			VolumeSource: corev1.VolumeSource{
 				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
 					ClaimName: args.PVC.Name,
-					ReadOnly:  false,
+					ReadOnly:  args.Export,
 				},
 			},
 		},
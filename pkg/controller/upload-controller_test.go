@@ -26,6 +26,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -150,6 +151,7 @@ var _ = Describe("Upload controller reconcile loop", func() {
 		reconciler := createUploadReconciler(testPvc,
 			createUploadPod(testPvc),
 			createUploadService(testPvc),
+			createUploadScratchPvc(testPvc),
 		)
 		By("Verifying the pod and service exists")
 		uploadPod := &corev1.Pod{}
@@ -175,6 +177,10 @@ var _ = Describe("Upload controller reconcile loop", func() {
 		Expect(err).ToNot(HaveOccurred())
 		Expect(serviceList.Items).To(BeEmpty())
 
+		By("Verifying the scratch PVC no longer exists")
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1-scratch", Namespace: "default"}, &corev1.PersistentVolumeClaim{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
 	})
 
 	It("Should return nil and delete pod if deadline exceeded", func() {
@@ -193,6 +199,7 @@ var _ = Describe("Upload controller reconcile loop", func() {
 		reconciler := createUploadReconciler(testPvc,
 			pod,
 			createUploadService(testPvc),
+			createUploadScratchPvc(testPvc),
 		)
 
 		_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "testPvc1", Namespace: "default"}})
@@ -210,6 +217,10 @@ var _ = Describe("Upload controller reconcile loop", func() {
 		Expect(err).ToNot(HaveOccurred())
 		Expect(uploadService.Name).To(Equal(createUploadResourceName(testPvc.Name)))
 
+		By("Verifying the scratch PVC is kept for the next pod")
+		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1-scratch", Namespace: "default"}, &corev1.PersistentVolumeClaim{})
+		Expect(err).ToNot(HaveOccurred())
+
 		pvc := &corev1.PersistentVolumeClaim{}
 		err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1", Namespace: "default"}, pvc)
 		Expect(err).ToNot(HaveOccurred())
@@ -411,6 +422,23 @@ var _ = Describe("reconcilePVC loop", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("Should pass the clone transformation to the pod", func() {
+			testPvc := cc.CreatePvc(testPvcName, "default", map[string]string{cc.AnnCloneRequest: "default/testPvc2", AnnUploadPod: uploadResourceName, cc.AnnCloneResize: "true", cc.AnnCloneLogicalSectorSize: "4096"}, nil)
+			testPvcSource := cc.CreatePvc("testPvc2", "default", map[string]string{}, nil)
+			reconciler := createUploadReconciler(testPvc, testPvcSource)
+
+			_, err := reconciler.reconcilePVC(reconciler.log, testPvc, isClone)
+			Expect(err).ToNot(HaveOccurred())
+
+			uploadPod := &corev1.Pod{}
+			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: uploadResourceName, Namespace: "default"}, uploadPod)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(uploadPod.Spec.Containers[0].Env).To(ContainElements(
+				corev1.EnvVar{Name: common.CloneResize, Value: "true"},
+				corev1.EnvVar{Name: common.CloneLogicalSectorSize, Value: "4096"},
+			))
+		})
+
 		It("Should error if a POD with the same name exists, but is not owned by the PVC, if a PVC with all needed annotations is passed", func() {
 			pod := &corev1.Pod{
 				TypeMeta: metav1.TypeMeta{
@@ -502,6 +530,7 @@ var _ = Describe("reconcilePVC loop", func() {
 			scratchPvc := &corev1.PersistentVolumeClaim{}
 			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "testPvc1-scratch", Namespace: "default"}, scratchPvc)
 			Expect(err).ToNot(HaveOccurred())
+			Expect(metav1.IsControlledBy(scratchPvc, testPvc)).To(BeTrue())
 
 			secret := &corev1.Secret{}
 			err = reconciler.client.Get(context.TODO(), types.NamespacedName{Name: uploadResourceName, Namespace: "default"}, secret)
@@ -751,6 +780,12 @@ func createUploadPod(pvc *corev1.PersistentVolumeClaim) *corev1.Pod {
 	return pod
 }
 
+func createUploadScratchPvc(pvc *corev1.PersistentVolumeClaim) *corev1.PersistentVolumeClaim {
+	scratchPvc := cc.CreatePvc(pvc.Name+"-scratch", pvc.Namespace, nil, nil)
+	scratchPvc.OwnerReferences = []metav1.OwnerReference{MakePVCOwnerReference(pvc)}
+	return scratchPvc
+}
+
 func createUploadClonePod(pvc *corev1.PersistentVolumeClaim, clientName string) *corev1.Pod {
 	name := "cdi-upload-" + pvc.Name
 	requestImageSize, _ := cc.GetRequestedImageSize(pvc)
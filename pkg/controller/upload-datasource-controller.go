@@ -0,0 +1,388 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+)
+
+const (
+	uploadDataSourceControllerName = "upload-datasource-controller"
+
+	defaultUploadsToKeepPerDataSource = 3
+
+	// AnnUploadDataSourceUpdated is set on upload DataVolumes once their managed DataSource points to them
+	AnnUploadDataSourceUpdated = cc.AnnAPIGroup + "/storage.upload.dataSourceUpdated"
+
+	// UploadDataSourceUpdated is reason for event created when the managed DataSource of an upload is updated
+	UploadDataSourceUpdated = "UploadDataSourceUpdated"
+	// UploadDataSourceNotManaged is reason for event created when the DataSource of an upload is managed by a DataImportCron
+	UploadDataSourceNotManaged = "UploadDataSourceNotManaged"
+
+	// MessageUploadDataSourceUpdated provides a const to form the DataSource updated message
+	MessageUploadDataSourceUpdated = "DataSource %s updated to the %s %s"
+	// MessageUploadDataSourceNotManaged provides a const to form the DataSource not managed message
+	MessageUploadDataSourceNotManaged = "DataSource %s is managed by DataImportCron %s"
+)
+
+// UploadDataSourceReconciler points the managed DataSource of upload DataVolumes to the uploaded image once the upload
+// succeeds, snapshotting it first if the StorageProfile asks for it, and garbage collects the previous uploads
+type UploadDataSourceReconciler struct {
+	client          client.Client
+	recorder        record.EventRecorder
+	scheme          *runtime.Scheme
+	log             logr.Logger
+	installerLabels map[string]string
+}
+
+// Reconcile the reconcile loop for upload DataVolumes with a managed DataSource.
+func (r *UploadDataSourceReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("DataVolume", req.NamespacedName)
+
+	dv := &cdiv1.DataVolume{}
+	if err := r.client.Get(ctx, req.NamespacedName, dv); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	dataSourceName := getUploadDataSourceName(dv)
+	if dataSourceName == "" || dv.DeletionTimestamp != nil || dv.Status.Phase != cdiv1.Succeeded ||
+		dv.Annotations[AnnUploadDataSourceUpdated] == "true" {
+		return reconcile.Result{}, nil
+	}
+	log.V(1).Info("reconciling upload to DataSource", "dataSource", dataSourceName)
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(ctx, req.NamespacedName, pvc); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	storageProfile, err := r.getStorageProfile(ctx, pvc)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	format := cdiv1.DataImportCronSourceFormatPvc
+	if storageProfile != nil && storageProfile.Status.DataImportCronSourceFormat != nil {
+		format = *storageProfile.Status.DataImportCronSourceFormat
+	}
+
+	switch format {
+	case cdiv1.DataImportCronSourceFormatPvc:
+		updated, err := r.updateDataSource(ctx, dv, dataSourceName, format)
+		if err != nil || !updated {
+			return reconcile.Result{}, err
+		}
+		dvCopy := dv.DeepCopy()
+		cc.AddLabel(dv, common.UploadDataSourceLabel, dataSourceName)
+		cc.AddAnnotation(dv, AnnUploadDataSourceUpdated, "true")
+		cc.AddAnnotation(dv, AnnLastUseTime, time.Now().UTC().Format(time.RFC3339Nano))
+		if !reflect.DeepEqual(dv.ObjectMeta, dvCopy.ObjectMeta) {
+			if err := r.client.Update(ctx, dv); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+	case cdiv1.DataImportCronSourceFormatSnapshot:
+		snapshot, err := r.getOrCreateSnapshot(ctx, dv, pvc, storageProfile, dataSourceName)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if !cc.IsSnapshotReady(snapshot) {
+			// The DataSource is only pointed to the snapshot once it is ready, snapshot updates trigger a reconcile
+			log.V(1).Info("waiting for the snapshot of the upload to be ready", "snapshot", snapshot.Name)
+			return reconcile.Result{}, nil
+		}
+		updated, err := r.updateDataSource(ctx, dv, dataSourceName, format)
+		if err != nil || !updated {
+			return reconcile.Result{}, err
+		}
+	default:
+		return reconcile.Result{}, fmt.Errorf("unknown source format %s", format)
+	}
+
+	if err := r.garbageCollectUploads(ctx, dv, dataSourceName); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if format == cdiv1.DataImportCronSourceFormatSnapshot {
+		// The snapshot is the source of the DataSource now, like for DataImportCrons the DV/PVC is not needed anymore
+		log.Info("Deleting upload dv as its snapshot is ready", "name", dv.Name)
+		if err := r.client.Delete(ctx, dv); err != nil && !k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
+
+func getUploadDataSourceName(dv *cdiv1.DataVolume) string {
+	if dv.Spec.Source == nil || dv.Spec.Source.Upload == nil {
+		return ""
+	}
+	return dv.Spec.Source.Upload.ManagedDataSource
+}
+
+func (r *UploadDataSourceReconciler) getStorageProfile(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (*cdiv1.StorageProfile, error) {
+	scName := pvc.Spec.StorageClassName
+	if scName == nil || *scName == "" {
+		return nil, nil
+	}
+	storageProfile := &cdiv1.StorageProfile{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: *scName}, storageProfile); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return storageProfile, nil
+}
+
+func (r *UploadDataSourceReconciler) getOrCreateSnapshot(ctx context.Context, dv *cdiv1.DataVolume, pvc *corev1.PersistentVolumeClaim, storageProfile *cdiv1.StorageProfile, dataSourceName string) (*snapshotv1.VolumeSnapshot, error) {
+	snapshot := &snapshotv1.VolumeSnapshot{}
+	if err := r.client.Get(ctx, client.ObjectKeyFromObject(pvc), snapshot); err == nil || !k8serrors.IsNotFound(err) {
+		return snapshot, err
+	}
+
+	snapshot = &snapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pvc.Name,
+			Namespace: pvc.Namespace,
+		},
+		Spec: snapshotv1.VolumeSnapshotSpec{
+			Source: snapshotv1.VolumeSnapshotSource{
+				PersistentVolumeClaimName: &pvc.Name,
+			},
+		},
+	}
+	// storageProfile is never nil here, the snapshot format comes from it
+	snapshotClassName, err := getSnapshotClassForBootSource(pvc, storageProfile, r.log, r.client, r.recorder)
+	if err != nil {
+		return nil, err
+	}
+	if snapshotClassName != "" {
+		snapshot.Spec.VolumeSnapshotClassName = &snapshotClassName
+	}
+	util.SetRecommendedLabels(snapshot, r.installerLabels, common.CDIControllerName)
+	cc.CopyAllowedLabels(pvc.GetLabels(), snapshot, false)
+	cc.AddLabel(snapshot, common.UploadDataSourceLabel, dataSourceName)
+	cc.AddAnnotation(snapshot, AnnLastUseTime, time.Now().UTC().Format(time.RFC3339Nano))
+	pvcSize := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if size := inferAdvisedRestoreSizeForSnapshot(dv, snapshot, &pvcSize); size != nil {
+		cc.AddAnnotation(snapshot, cc.AnnAdvisedRestoreSize, size.String())
+	}
+	if pvc.Spec.VolumeMode != nil {
+		cc.AddAnnotation(snapshot, cc.AnnSourceVolumeMode, string(*pvc.Spec.VolumeMode))
+	}
+	if err := r.client.Create(ctx, snapshot); err != nil {
+		return nil, err
+	}
+	r.log.Info("Snapshot of upload created", "name", snapshot.Name, "namespace", snapshot.Namespace)
+	return snapshot, nil
+}
+
+// updateDataSource points the DataSource to the upload, it returns false if the DataSource is managed by a DataImportCron
+func (r *UploadDataSourceReconciler) updateDataSource(ctx context.Context, dv *cdiv1.DataVolume, name string, format cdiv1.DataImportCronSourceFormat) (bool, error) {
+	source := &cdiv1.DataVolumeSourcePVC{Namespace: dv.Namespace, Name: dv.Name}
+	kind := "pvc"
+	if format == cdiv1.DataImportCronSourceFormatSnapshot {
+		kind = "snapshot"
+	}
+
+	dataSource := &cdiv1.DataSource{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: dv.Namespace, Name: name}, dataSource); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return false, err
+		}
+		dataSource = &cdiv1.DataSource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: dv.Namespace,
+			},
+		}
+		util.SetRecommendedLabels(dataSource, r.installerLabels, common.CDIControllerName)
+		populateDataSource(format, dataSource, source)
+		if err := r.client.Create(ctx, dataSource); err != nil {
+			return false, err
+		}
+		r.recorder.Eventf(dv, corev1.EventTypeNormal, UploadDataSourceUpdated, MessageUploadDataSourceUpdated, name, kind, dv.Name)
+		return true, nil
+	}
+
+	if cronName := dataSource.Labels[common.DataImportCronLabel]; cronName != "" {
+		r.recorder.Eventf(dv, corev1.EventTypeWarning, UploadDataSourceNotManaged, MessageUploadDataSourceNotManaged, name, cronName)
+		return false, nil
+	}
+
+	dataSourceCopy := dataSource.DeepCopy()
+	populateDataSource(format, dataSource, source)
+	if !reflect.DeepEqual(dataSource.Spec, dataSourceCopy.Spec) {
+		// The source is replaced in a single update, so users of the DataSource never see it without one
+		if err := r.client.Update(ctx, dataSource); err != nil {
+			return false, err
+		}
+		r.recorder.Eventf(dv, corev1.EventTypeNormal, UploadDataSourceUpdated, MessageUploadDataSourceUpdated, name, kind, dv.Name)
+	}
+	return true, nil
+}
+
+// garbageCollectUploads deletes the oldest uploads of the DataSource, the uploads which were not published yet are not counted
+func (r *UploadDataSourceReconciler) garbageCollectUploads(ctx context.Context, dv *cdiv1.DataVolume, dataSourceName string) error {
+	maxUploads := defaultUploadsToKeepPerDataSource
+	if keep := dv.Spec.Source.Upload.UploadsToKeep; keep != nil && *keep > 0 {
+		maxUploads = int(*keep)
+	}
+	selector := client.MatchingLabels{common.UploadDataSourceLabel: dataSourceName}
+
+	dvList := &cdiv1.DataVolumeList{}
+	if err := r.client.List(ctx, dvList, client.InNamespace(dv.Namespace), selector); err != nil {
+		return err
+	}
+	var uploads []cdiv1.DataVolume
+	for _, upload := range dvList.Items {
+		if upload.Annotations[AnnUploadDataSourceUpdated] == "true" {
+			uploads = append(uploads, upload)
+		}
+	}
+	if len(uploads) > maxUploads {
+		sort.Slice(uploads, func(i, j int) bool {
+			return uploads[i].Annotations[AnnLastUseTime] > uploads[j].Annotations[AnnLastUseTime]
+		})
+		for i := range uploads[maxUploads:] {
+			upload := &uploads[maxUploads+i]
+			r.log.Info("Deleting old upload dv", "name", upload.Name, "uid", upload.UID)
+			if err := r.client.Delete(ctx, upload); err != nil && !k8serrors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	snapList := &snapshotv1.VolumeSnapshotList{}
+	if err := r.client.List(ctx, snapList, client.InNamespace(dv.Namespace), selector); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	if len(snapList.Items) > maxUploads {
+		sort.Slice(snapList.Items, func(i, j int) bool {
+			return snapList.Items[i].Annotations[AnnLastUseTime] > snapList.Items[j].Annotations[AnnLastUseTime]
+		})
+		for i := range snapList.Items[maxUploads:] {
+			snap := &snapList.Items[maxUploads+i]
+			r.log.Info("Deleting old upload snapshot", "name", snap.Name, "uid", snap.UID)
+			if err := r.client.Delete(ctx, snap); err != nil && !k8serrors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// NewUploadDataSourceController creates a new instance of the upload DataSource controller.
+func NewUploadDataSourceController(mgr manager.Manager, log logr.Logger, installerLabels map[string]string) (controller.Controller, error) {
+	reconciler := &UploadDataSourceReconciler{
+		client:          mgr.GetClient(),
+		recorder:        mgr.GetEventRecorderFor(uploadDataSourceControllerName),
+		scheme:          mgr.GetScheme(),
+		log:             log.WithName(uploadDataSourceControllerName),
+		installerLabels: installerLabels,
+	}
+	uploadDataSourceController, err := controller.New(uploadDataSourceControllerName, mgr, controller.Options{
+		MaxConcurrentReconciles: 3,
+		Reconciler:              reconciler,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := addUploadDataSourceControllerWatches(mgr, uploadDataSourceController); err != nil {
+		return nil, err
+	}
+	log.Info("Initialized upload DataSource controller")
+	return uploadDataSourceController, nil
+}
+
+func addUploadDataSourceControllerWatches(mgr manager.Manager, c controller.Controller) error {
+	if err := c.Watch(source.Kind(mgr.GetCache(), &cdiv1.DataVolume{},
+		&handler.TypedEnqueueRequestForObject[*cdiv1.DataVolume]{},
+		predicate.TypedFuncs[*cdiv1.DataVolume]{
+			CreateFunc: func(e event.TypedCreateEvent[*cdiv1.DataVolume]) bool {
+				return getUploadDataSourceName(e.Object) != ""
+			},
+			UpdateFunc: func(e event.TypedUpdateEvent[*cdiv1.DataVolume]) bool {
+				return getUploadDataSourceName(e.ObjectNew) != ""
+			},
+			DeleteFunc: func(event.TypedDeleteEvent[*cdiv1.DataVolume]) bool { return false },
+		},
+	)); err != nil {
+		return err
+	}
+
+	if err := mgr.GetClient().List(context.TODO(), &snapshotv1.VolumeSnapshotList{}); err != nil {
+		if meta.IsNoMatchError(err) {
+			// Back out if there's no point to attempt watch
+			return nil
+		}
+		if !cc.IsErrCacheNotStarted(err) {
+			return err
+		}
+	}
+	// The snapshots are named after the upload DataVolumes
+	if err := c.Watch(source.Kind(mgr.GetCache(), &snapshotv1.VolumeSnapshot{},
+		&handler.TypedEnqueueRequestForObject[*snapshotv1.VolumeSnapshot]{},
+		predicate.TypedFuncs[*snapshotv1.VolumeSnapshot]{
+			CreateFunc: func(event.TypedCreateEvent[*snapshotv1.VolumeSnapshot]) bool { return false },
+			UpdateFunc: func(e event.TypedUpdateEvent[*snapshotv1.VolumeSnapshot]) bool {
+				return e.ObjectNew.GetLabels()[common.UploadDataSourceLabel] != ""
+			},
+			DeleteFunc: func(event.TypedDeleteEvent[*snapshotv1.VolumeSnapshot]) bool { return false },
+		},
+	)); err != nil {
+		return err
+	}
+
+	return nil
+}
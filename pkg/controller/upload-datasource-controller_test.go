@@ -0,0 +1,226 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	cc "kubevirt.io/containerized-data-importer/pkg/controller/common"
+)
+
+var (
+	uploadDataSourceLog = logf.Log.WithName("upload-datasource-controller-test")
+)
+
+var _ = Describe("Upload DataSource controller reconcile loop", func() {
+	const (
+		testNamespace      = "default"
+		testDataSourceName = "golden"
+		testStorageClass   = "sc"
+	)
+
+	createUploadDataVolume := func(name string) *cdiv1.DataVolume {
+		return &cdiv1.DataVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testNamespace,
+			},
+			Spec: cdiv1.DataVolumeSpec{
+				Source: &cdiv1.DataVolumeSource{
+					Upload: &cdiv1.DataVolumeSourceUpload{
+						ManagedDataSource: testDataSourceName,
+						UploadsToKeep:     ptr.To[int32](1),
+					},
+				},
+			},
+			Status: cdiv1.DataVolumeStatus{
+				Phase: cdiv1.Succeeded,
+			},
+		}
+	}
+
+	createUploadPvc := func(name string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testNamespace,
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: ptr.To(testStorageClass),
+				VolumeMode:       ptr.To(corev1.PersistentVolumeFilesystem),
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("1Gi"),
+					},
+				},
+			},
+		}
+	}
+
+	createStorageProfile := func(format cdiv1.DataImportCronSourceFormat) *cdiv1.StorageProfile {
+		return &cdiv1.StorageProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        testStorageClass,
+				Annotations: map[string]string{cc.AnnSnapshotClassForDataImportCron: "vsc"},
+			},
+			Status: cdiv1.StorageProfileStatus{
+				DataImportCronSourceFormat: &format,
+			},
+		}
+	}
+
+	createReconciler := func(objects ...runtime.Object) *UploadDataSourceReconciler {
+		s := scheme.Scheme
+		_ = cdiv1.AddToScheme(s)
+		_ = snapshotv1.AddToScheme(s)
+
+		cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objects...).Build()
+		return &UploadDataSourceReconciler{
+			client:   cl,
+			recorder: record.NewFakeRecorder(10),
+			scheme:   s,
+			log:      uploadDataSourceLog,
+		}
+	}
+
+	reconcileDataVolume := func(r *UploadDataSourceReconciler, name string) {
+		_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: name}})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	getDataSource := func(r *UploadDataSourceReconciler) *cdiv1.DataSource {
+		dataSource := &cdiv1.DataSource{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testDataSourceName}, dataSource)
+		Expect(err).ToNot(HaveOccurred())
+		return dataSource
+	}
+
+	It("Should ignore an upload which did not succeed yet", func() {
+		dv := createUploadDataVolume("upload1")
+		dv.Status.Phase = cdiv1.UploadReady
+		r := createReconciler(dv, createUploadPvc("upload1"))
+		reconcileDataVolume(r, "upload1")
+
+		err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testDataSourceName}, &cdiv1.DataSource{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should point the DataSource to the uploaded pvc and delete the previous uploads", func() {
+		oldDv := createUploadDataVolume("upload1")
+		oldDv.Labels = map[string]string{common.UploadDataSourceLabel: testDataSourceName}
+		oldDv.Annotations = map[string]string{
+			AnnUploadDataSourceUpdated: "true",
+			AnnLastUseTime:             time.Now().Add(-time.Hour).UTC().Format(time.RFC3339Nano),
+		}
+		dataSource := &cdiv1.DataSource{
+			ObjectMeta: metav1.ObjectMeta{Name: testDataSourceName, Namespace: testNamespace},
+			Spec: cdiv1.DataSourceSpec{
+				Source: cdiv1.DataSourceSource{
+					PVC: &cdiv1.DataVolumeSourcePVC{Namespace: testNamespace, Name: "upload1"},
+				},
+			},
+		}
+		r := createReconciler(oldDv, createUploadDataVolume("upload2"), createUploadPvc("upload2"),
+			createStorageProfile(cdiv1.DataImportCronSourceFormatPvc), dataSource)
+		reconcileDataVolume(r, "upload2")
+
+		dataSource = getDataSource(r)
+		Expect(dataSource.Spec.Source.PVC).To(Equal(&cdiv1.DataVolumeSourcePVC{Namespace: testNamespace, Name: "upload2"}))
+
+		dv := &cdiv1.DataVolume{}
+		Expect(r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: "upload2"}, dv)).To(Succeed())
+		Expect(dv.Labels).To(HaveKeyWithValue(common.UploadDataSourceLabel, testDataSourceName))
+		Expect(dv.Annotations).To(HaveKeyWithValue(AnnUploadDataSourceUpdated, "true"))
+
+		err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: "upload1"}, &cdiv1.DataVolume{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should point the DataSource to the snapshot of the upload once it is ready", func() {
+		r := createReconciler(createUploadDataVolume("upload1"), createUploadPvc("upload1"),
+			createStorageProfile(cdiv1.DataImportCronSourceFormatSnapshot))
+		reconcileDataVolume(r, "upload1")
+
+		snapshot := &snapshotv1.VolumeSnapshot{}
+		snapshotKey := types.NamespacedName{Namespace: testNamespace, Name: "upload1"}
+		Expect(r.client.Get(context.TODO(), snapshotKey, snapshot)).To(Succeed())
+		Expect(*snapshot.Spec.Source.PersistentVolumeClaimName).To(Equal("upload1"))
+		Expect(*snapshot.Spec.VolumeSnapshotClassName).To(Equal("vsc"))
+		Expect(snapshot.Labels).To(HaveKeyWithValue(common.UploadDataSourceLabel, testDataSourceName))
+		Expect(snapshot.Annotations).To(HaveKeyWithValue(cc.AnnSourceVolumeMode, string(corev1.PersistentVolumeFilesystem)))
+		err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testDataSourceName}, &cdiv1.DataSource{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
+		snapshot.Status = &snapshotv1.VolumeSnapshotStatus{ReadyToUse: ptr.To(true)}
+		Expect(r.client.Update(context.TODO(), snapshot)).To(Succeed())
+		reconcileDataVolume(r, "upload1")
+
+		dataSource := getDataSource(r)
+		Expect(dataSource.Spec.Source.Snapshot).To(Equal(&cdiv1.DataVolumeSourceSnapshot{Namespace: testNamespace, Name: "upload1"}))
+		Expect(dataSource.Spec.Source.PVC).To(BeNil())
+		err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: "upload1"}, &cdiv1.DataVolume{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should not update a DataSource managed by a DataImportCron", func() {
+		dataSource := &cdiv1.DataSource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testDataSourceName,
+				Namespace: testNamespace,
+				Labels:    map[string]string{common.DataImportCronLabel: "cron"},
+			},
+			Spec: cdiv1.DataSourceSpec{
+				Source: cdiv1.DataSourceSource{
+					PVC: &cdiv1.DataVolumeSourcePVC{Namespace: testNamespace, Name: "imported"},
+				},
+			},
+		}
+		r := createReconciler(createUploadDataVolume("upload1"), createUploadPvc("upload1"),
+			createStorageProfile(cdiv1.DataImportCronSourceFormatPvc), dataSource)
+		reconcileDataVolume(r, "upload1")
+
+		Expect(getDataSource(r).Spec.Source.PVC.Name).To(Equal("imported"))
+		dv := &cdiv1.DataVolume{}
+		Expect(r.client.Get(context.TODO(), client.ObjectKey{Namespace: testNamespace, Name: "upload1"}, dv)).To(Succeed())
+		Expect(dv.Annotations).ToNot(HaveKey(AnnUploadDataSourceUpdated))
+		event := <-r.recorder.(*record.FakeRecorder).Events
+		Expect(event).To(ContainSubstring(UploadDataSourceNotManaged))
+	})
+})
@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const (
+	// uploadTokenRecordsCleanupPeriod is the period between two removals of the expired upload token records
+	uploadTokenRecordsCleanupPeriod = 10 * time.Minute
+	// uploadTokenRecordsGracePeriod keeps the records after their token expired, longer than the clock skew leeway of
+	// the upload proxy
+	uploadTokenRecordsGracePeriod = time.Minute
+)
+
+// uploadTokenRecordsCleaner removes the records of the expired upload tokens from the CDI namespace: the config maps
+// recording a used single use token, and the revocations with an expiry in the upload tokens config map
+type uploadTokenRecordsCleaner struct {
+	client    client.Client
+	log       logr.Logger
+	namespace string
+}
+
+// NewUploadTokenRecordsCleaner adds the periodic removal of the expired upload token records to the manager
+func NewUploadTokenRecordsCleaner(mgr manager.Manager, log logr.Logger, namespace string) error {
+	return mgr.Add(&uploadTokenRecordsCleaner{
+		client:    mgr.GetClient(),
+		log:       log.WithName("upload-token-records-cleaner"),
+		namespace: namespace,
+	})
+}
+
+// Start removes the expired records until the context is done, only the leader runs it
+func (c *uploadTokenRecordsCleaner) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, c.removeExpiredRecords, uploadTokenRecordsCleanupPeriod)
+	return nil
+}
+
+func (c *uploadTokenRecordsCleaner) removeExpiredRecords(ctx context.Context) {
+	expired := time.Now().Add(-uploadTokenRecordsGracePeriod)
+	c.removeExpiredUsedTokens(ctx, expired)
+	c.removeExpiredRevocations(ctx, expired)
+}
+
+func (c *uploadTokenRecordsCleaner) removeExpiredUsedTokens(ctx context.Context, expired time.Time) {
+	cms := &corev1.ConfigMapList{}
+	if err := c.client.List(ctx, cms, client.InNamespace(c.namespace), client.HasLabels{common.UsedUploadTokenLabel}); err != nil {
+		c.log.Error(err, "Unable to list the used upload token records")
+		return
+	}
+	for i := range cms.Items {
+		cm := &cms.Items[i]
+		expiry, err := strconv.ParseInt(cm.Data[common.UsedUploadTokenExpiryKey], 10, 64)
+		if err != nil {
+			// Keep the record, removing it would allow to use the token again
+			c.log.Info("Invalid expiry in used upload token record", "name", cm.Name)
+			continue
+		}
+		if !time.Unix(expiry, 0).Before(expired) {
+			continue
+		}
+		if err := c.client.Delete(ctx, cm); err != nil && !k8serrors.IsNotFound(err) {
+			c.log.Error(err, "Unable to remove the used upload token record", "name", cm.Name)
+		}
+	}
+}
+
+func (c *uploadTokenRecordsCleaner) removeExpiredRevocations(ctx context.Context, expired time.Time) {
+	cm := &corev1.ConfigMap{}
+	if err := c.client.Get(ctx, client.ObjectKey{Namespace: c.namespace, Name: common.UploadTokensConfigMap}, cm); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			c.log.Error(err, "Unable to get the upload tokens config map")
+		}
+		return
+	}
+	removed := false
+	for id, record := range cm.Data {
+		if uploadTokenRecordExpired(record, expired) {
+			delete(cm.Data, id)
+			removed = true
+		}
+	}
+	if !removed {
+		return
+	}
+	// A conflict with an admin revoking a token is retried on the next period
+	if err := c.client.Update(ctx, cm); err != nil {
+		c.log.Error(err, "Unable to remove the expired records of the upload tokens config map")
+	}
+}
+
+// uploadTokenRecordExpired returns true for the "<state>@<expiry unix time>" records of the upload tokens config map
+// expired before the given time, records without expiry are kept until they are removed
+func uploadTokenRecordExpired(record string, expired time.Time) bool {
+	_, expiry, found := strings.Cut(record, "@")
+	if !found {
+		return false
+	}
+	exp, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return false
+	}
+	return time.Unix(exp, 0).Before(expired)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+var _ = Describe("Upload token records cleaner", func() {
+	usedTokenRecord := func(name string, expiry time.Time, labeled bool) *corev1.ConfigMap {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      common.UsedUploadTokenPrefix + name,
+				Namespace: "cdi",
+			},
+			Data: map[string]string{
+				common.UsedUploadTokenIDKey:     name,
+				common.UsedUploadTokenExpiryKey: strconv.FormatInt(expiry.Unix(), 10),
+			},
+		}
+		if labeled {
+			cm.Labels = map[string]string{common.UsedUploadTokenLabel: ""}
+		}
+		return cm
+	}
+
+	It("Should remove the expired records only", func() {
+		now := time.Now()
+		tokens := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: common.UploadTokensConfigMap, Namespace: "cdi"},
+			Data: map[string]string{
+				"revoked":         "revoked",
+				"revoked-valid":   fmt.Sprintf("revoked@%d", now.Add(time.Hour).Unix()),
+				"revoked-expired": fmt.Sprintf("revoked@%d", now.Add(-time.Hour).Unix()),
+				"used-expired":    fmt.Sprintf("used@%d", now.Add(-time.Hour).Unix()),
+			},
+		}
+		invalid := usedTokenRecord("invalid", now, true)
+		invalid.Data[common.UsedUploadTokenExpiryKey] = "never"
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(
+			tokens,
+			invalid,
+			usedTokenRecord("valid", now.Add(time.Hour), true),
+			usedTokenRecord("leeway", now.Add(-time.Second), true),
+			usedTokenRecord("expired", now.Add(-time.Hour), true),
+			usedTokenRecord("unlabeled", now.Add(-time.Hour), false),
+		).Build()
+		c := &uploadTokenRecordsCleaner{client: cl, log: logf.Log, namespace: "cdi"}
+
+		c.removeExpiredRecords(context.TODO())
+
+		cms := &corev1.ConfigMapList{}
+		Expect(cl.List(context.TODO(), cms, client.InNamespace("cdi"))).To(Succeed())
+		names := []string{}
+		for _, cm := range cms.Items {
+			names = append(names, cm.Name)
+		}
+		Expect(names).To(ConsistOf(
+			common.UploadTokensConfigMap,
+			common.UsedUploadTokenPrefix+"invalid",
+			common.UsedUploadTokenPrefix+"valid",
+			common.UsedUploadTokenPrefix+"leeway",
+			common.UsedUploadTokenPrefix+"unlabeled",
+		))
+
+		Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(tokens), tokens)).To(Succeed())
+		Expect(tokens.Data).To(HaveLen(2))
+		Expect(tokens.Data).To(HaveKey("revoked"))
+		Expect(tokens.Data).To(HaveKey("revoked-valid"))
+	})
+
+	It("Should not fail without records", func() {
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		c := &uploadTokenRecordsCleaner{client: cl, log: logf.Log, namespace: "cdi"}
+		c.removeExpiredRecords(context.TODO())
+	})
+})
@@ -69,6 +69,14 @@ const (
 
 	// ProxyCertVolName is the name of the volumecontaining certs
 	ProxyCertVolName = "cdi-proxy-cert-vol"
+	// SignatureVolName is the name of the volume containing the image signature verification keys
+	SignatureVolName = "cdi-signature-vol"
+	// ChecksumKeyringVolName is the name of the volume containing the keyring verifying the checksum file
+	ChecksumKeyringVolName = "cdi-checksum-keyring-vol"
+	// HostKeyVolName is the name of the volume containing the SFTP server host keys
+	HostKeyVolName = "cdi-host-key-vol"
+	// NFSVolName is the name of the volume of the NFS export containing the imported file
+	NFSVolName = "cdi-nfs-vol"
 	// ClusterWideProxyAPIGroup is the APIGroup for OpenShift Cluster Wide Proxy
 	ClusterWideProxyAPIGroup = "config.openshift.io"
 	// ClusterWideProxyAPIKind is the APIKind for OpenShift Cluster Wide Proxy
@@ -123,9 +131,9 @@ func checkIfLabelExists(pvc *corev1.PersistentVolumeClaim, lbl string, val strin
 }
 
 // newScratchPersistentVolumeClaimSpec creates a new PVC based on the size of the passed in PVC.
-// It also sets the appropriate OwnerReferences on the resource
-// which allows handleObject to discover the pod resource that 'owns' it, and clean up when needed.
-func newScratchPersistentVolumeClaimSpec(pvc *corev1.PersistentVolumeClaim, pod *corev1.Pod, name, storageClassName string) *corev1.PersistentVolumeClaim {
+// It also sets the passed in owner reference on the resource, usually the pod using the scratch space,
+// which allows handleObject to discover the resource that 'owns' it, and clean up when needed.
+func newScratchPersistentVolumeClaimSpec(pvc *corev1.PersistentVolumeClaim, owner metav1.OwnerReference, name, storageClassName string) *corev1.PersistentVolumeClaim {
 	labels := map[string]string{
 		"app": "containerized-data-importer",
 	}
@@ -154,7 +162,7 @@ func newScratchPersistentVolumeClaimSpec(pvc *corev1.PersistentVolumeClaim, pod
 			Labels:      labels,
 			Annotations: annotations,
 			OwnerReferences: []metav1.OwnerReference{
-				MakePodOwnerReference(pod),
+				owner,
 			},
 		},
 		Spec: corev1.PersistentVolumeClaimSpec{
@@ -169,8 +177,8 @@ func newScratchPersistentVolumeClaimSpec(pvc *corev1.PersistentVolumeClaim, pod
 }
 
 // createScratchPersistentVolumeClaim creates and returns a pointer to a scratch PVC which is created based on the passed-in pvc and storage class name.
-func createScratchPersistentVolumeClaim(client client.Client, pvc *corev1.PersistentVolumeClaim, pod *corev1.Pod, name, storageClassName string, installerLabels map[string]string, recorder record.EventRecorder) (*corev1.PersistentVolumeClaim, error) {
-	scratchPvcSpec := newScratchPersistentVolumeClaimSpec(pvc, pod, name, storageClassName)
+func createScratchPersistentVolumeClaim(client client.Client, pvc *corev1.PersistentVolumeClaim, owner metav1.OwnerReference, name, storageClassName string, installerLabels map[string]string, recorder record.EventRecorder) (*corev1.PersistentVolumeClaim, error) {
+	scratchPvcSpec := newScratchPersistentVolumeClaimSpec(pvc, owner, name, storageClassName)
 
 	sizeRequest := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
 	scratchFsOverhead, err := GetFilesystemOverhead(context.TODO(), client, scratchPvcSpec)
@@ -372,6 +380,9 @@ func setAnnotationsFromPodWithPrefix(anno map[string]string, pod *corev1.Pod, te
 			if termMsg.PreallocationApplied != nil && *termMsg.PreallocationApplied {
 				anno[cc.AnnPreallocationApplied] = "true"
 			}
+			if termMsg.Checksum != nil {
+				anno[cc.AnnUploadChecksum] = *termMsg.Checksum
+			}
 		} else {
 			// Handle plain termination message (legacy)
 			anno[prefix+".message"] = simplifyKnownMessage(containerState.Terminated.Message)
@@ -212,6 +212,22 @@ var _ = Describe("setAnnotationsFromPod", func() {
 		Expect(result[AnnPreallocationApplied]).To(Equal("true"))
 	})
 
+	It("Should set the checksum of the upload", func() {
+		result := make(map[string]string)
+		testPod := CreateImporterTestPod(CreatePvc("test", metav1.NamespaceDefault, nil, nil), "test", nil)
+		testPod.Status = v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					State: v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{},
+					},
+				},
+			},
+		}
+		setAnnotationsFromPodWithPrefix(result, testPod, &common.TerminationMessage{Checksum: ptr.To("sha256:abcd")}, AnnRunningCondition)
+		Expect(result[AnnUploadChecksum]).To(Equal("sha256:abcd"))
+	})
+
 	It("Should set scratch space required status", func() {
 		result := make(map[string]string)
 		testPod := CreateImporterTestPod(CreatePvc("test", metav1.NamespaceDefault, nil, nil), "test", nil)
@@ -445,7 +461,7 @@ var _ = Describe("createScratchPersistentVolumeClaim", func() {
 		testPvc.Spec.Resources.Requests[v1.ResourceStorage] = resource.MustParse("1Gi")
 		name := "test-scratchspace-pvc"
 		pod := &v1.Pod{}
-		res, err := createScratchPersistentVolumeClaim(cl, testPvc, pod, name, scratchStorageClassName, nil, rec)
+		res, err := createScratchPersistentVolumeClaim(cl, testPvc, MakePodOwnerReference(pod), name, scratchStorageClassName, nil, rec)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(res).ToNot(BeNil())
 		Expect(res.Spec.Resources).ToNot(BeNil())
@@ -471,7 +487,7 @@ var _ = Describe("createScratchPersistentVolumeClaim", func() {
 		testPvc.Spec.VolumeMode = ptr.To[v1.PersistentVolumeMode](v1.PersistentVolumeBlock)
 		name := "test-scratchspace-pvc"
 		pod := &v1.Pod{}
-		res, err := createScratchPersistentVolumeClaim(cl, testPvc, pod, name, scratchStorageClassName, nil, rec)
+		res, err := createScratchPersistentVolumeClaim(cl, testPvc, MakePodOwnerReference(pod), name, scratchStorageClassName, nil, rec)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(res).ToNot(BeNil())
 		Expect(res.Spec.Resources).ToNot(BeNil())
@@ -493,7 +509,7 @@ var _ = Describe("createScratchPersistentVolumeClaim", func() {
 		testPvc.Spec.VolumeMode = ptr.To[v1.PersistentVolumeMode](v1.PersistentVolumeBlock)
 		name := "test-scratchspace-pvc"
 		pod := &v1.Pod{}
-		scratchPVC, err := createScratchPersistentVolumeClaim(cl, testPvc, pod, name, scratchStorageClassName, nil, rec)
+		scratchPVC, err := createScratchPersistentVolumeClaim(cl, testPvc, MakePodOwnerReference(pod), name, scratchStorageClassName, nil, rec)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(scratchPVC).ToNot(BeNil())
 		Expect(scratchPVC.GetLabels()[LabelExcludeFromVeleroBackup]).To(Equal("true"))
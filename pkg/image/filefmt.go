@@ -33,6 +33,25 @@ var knownHeaders = Headers{
 		SizeOff:     0,
 		SizeLen:     0,
 	},
+	"bz2": Header{
+		Format:      "bz2",
+		magicNumber: []byte("BZh"),
+		SizeOff:     0,
+		SizeLen:     0,
+	},
+	"lz4": Header{
+		Format:      "lz4",
+		magicNumber: []byte{0x04, 0x22, 0x4D, 0x18},
+		// the content size is optional in the frame header
+		SizeOff: 0,
+		SizeLen: 0,
+	},
+	"zip": Header{
+		Format:      "zip",
+		magicNumber: []byte{'P', 'K', 0x03, 0x04},
+		SizeOff:     0,
+		SizeLen:     0,
+	},
 	"qcow2": Header{
 		Format:      "qcow2",
 		magicNumber: []byte{'Q', 'F', 'I', 0xfb},
@@ -81,6 +100,9 @@ var knownHeaders = Headers{
 	},
 }
 
+// bzip2BlockMagic is the magic number of a bzip2 block, the BCD digits of pi
+var bzip2BlockMagic = []byte("1AY&SY")
+
 // Header represents our parameters for a file format header
 type Header struct {
 	Format      string
@@ -101,7 +123,16 @@ func CopyKnownHdrs() Headers {
 
 // Match performs a check to see if the provided byte slice matches the bytes in our header data
 func (h Header) Match(b []byte) bool {
-	return bytes.Equal(b[h.mgOffset:h.mgOffset+len(h.magicNumber)], h.magicNumber)
+	end := h.mgOffset + len(h.magicNumber)
+	if !bytes.Equal(b[h.mgOffset:end], h.magicNumber) {
+		return false
+	}
+	if h.Format == "bz2" {
+		// "BZh" is followed by the block size, '1' to '9', and the magic of the first block
+		return len(b) >= end+1+len(bzip2BlockMagic) && b[end] >= '1' && b[end] <= '9' &&
+			bytes.Equal(b[end+1:end+1+len(bzip2BlockMagic)], bzip2BlockMagic)
+	}
+	return true
 }
 
 // Size uses the Header receiver offset and length fields to extract, from the passed-in file header slice (b),
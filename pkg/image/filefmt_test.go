@@ -41,6 +41,22 @@ var _ = Describe("File format tests", func() {
 			Header{"xz", []byte{0xFD, 0x37, 0x7A, 0x58, 0x5A, 0x00}, 0, 0, 0},
 			[]byte{0xFD, 0x37, 0x7A, 0x58, 0x5A, 0x00},
 			true),
+		Entry("match bz2",
+			Header{"bz2", []byte("BZh"), 0, 0, 0},
+			[]byte("BZh91AY&SY"),
+			true),
+		Entry("failed match of bz2 without a block size",
+			Header{"bz2", []byte("BZh"), 0, 0, 0},
+			[]byte("BZh01AY&SY"),
+			false),
+		Entry("failed match of bz2 without a block magic",
+			Header{"bz2", []byte("BZh"), 0, 0, 0},
+			[]byte("BZh9 text file"),
+			false),
+		Entry("failed match of a short bz2 header",
+			Header{"bz2", []byte("BZh"), 0, 0, 0},
+			[]byte("BZh9"),
+			false),
 		Entry("failed match",
 			Header{"gz", []byte{0x1F, 0x8B}, 0, 0, 0},
 			[]byte{'Q', 'F', 'I', 0xfb},
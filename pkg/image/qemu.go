@@ -56,12 +56,14 @@ type ImgInfo struct {
 type QEMUOperations interface {
 	ConvertToRawStream(*url.URL, string, bool, string) error
 	Resize(string, resource.Quantity, bool) error
+	Shrink(string, resource.Quantity) error
 	Info(url *url.URL) (*ImgInfo, error)
 	Validate(*url.URL, int64) error
 	CreateBlankImage(string, resource.Quantity, bool) error
 	PreallocateBlankBlock(string, resource.Quantity) error
 	Rebase(backingFile string, delta string) error
 	Commit(image string) error
+	ConvertToQcow2(src, dest string) error
 }
 
 type qemuOperations struct {
@@ -190,6 +192,14 @@ func (o *qemuOperations) Resize(image string, size resource.Quantity, preallocat
 	return nil
 }
 
+// Shrink shrinks the raw image, discarding its data after size. The caller must have checked that no data is lost.
+func (o *qemuOperations) Shrink(image string, size resource.Quantity) error {
+	if err := o.cmd.Exec("resize", "-f", "raw", "--shrink", image, convertQuantityToQemuSize(size)); err != nil {
+		return errors.Wrapf(err, "Error shrinking image %s", image)
+	}
+	return nil
+}
+
 func checkOutputQemuImgInfo(output []byte, image string) (*ImgInfo, error) {
 	var info ImgInfo
 	err := json.Unmarshal(output, &info)
@@ -390,3 +400,17 @@ func (o *qemuOperations) Commit(image string) error {
 	klog.V(1).Infof("Committing %s to backing file...", image)
 	return o.cmd.ExecWithProgress("commit", "-p", image)
 }
+
+// ConvertToQcow2 converts a raw image to a qcow2 image
+func ConvertToQcow2(src, dest string) error {
+	return qemuInterface.ConvertToQcow2(src, dest)
+}
+
+func (o *qemuOperations) ConvertToQcow2(src, dest string) error {
+	klog.V(1).Infof("Converting %s to qcow2 image %s", src, dest)
+	if err := o.cmd.ExecWithProgress("convert", "-p", "-f", "raw", "-O", "qcow2", src, dest); err != nil {
+		os.Remove(dest)
+		return errors.Wrap(err, "could not convert image to qcow2")
+	}
+	return nil
+}
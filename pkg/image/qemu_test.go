@@ -256,6 +256,33 @@ var _ = Describe("Resize", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("Should not pass --shrink to qemu-img resize", func() {
+		quantity, err := resource.ParseQuantity("1Gi")
+		Expect(err).NotTo(HaveOccurred())
+		size := convertQuantityToQemuSize(quantity)
+		ops := newTestOpsWithRun(mockRunCmdStrict("", "", "resize", "-f", "raw", "image", size))
+		err = ops.Resize("image", quantity, false)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Should shrink the image with qemu-img resize --shrink", func() {
+		quantity, err := resource.ParseQuantity("1Gi")
+		Expect(err).NotTo(HaveOccurred())
+		size := convertQuantityToQemuSize(quantity)
+		ops := newTestOpsWithRun(mockRunCmdStrict("", "", "resize", "-f", "raw", "--shrink", "image", size))
+		err = ops.Shrink("image", quantity)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Should fail if qemu-img resize --shrink fails", func() {
+		quantity, err := resource.ParseQuantity("1Gi")
+		Expect(err).NotTo(HaveOccurred())
+		size := convertQuantityToQemuSize(quantity)
+		ops := newTestOpsWithRun(mockRunCmd("", "exit 1", "resize", "-f", "raw", "--shrink", "image", size))
+		err = ops.Shrink("image", quantity)
+		Expect(err).To(MatchError(ContainSubstring("Error shrinking image")))
+	})
+
 	It("Should fail if qemu-img resize fails", func() {
 		quantity, err := resource.ParseQuantity("10Gi")
 		Expect(err).NotTo(HaveOccurred())
@@ -538,6 +565,20 @@ var _ = Describe("Rebase and commit", func() {
 	})
 })
 
+var _ = Describe("Convert to qcow2", func() {
+	It("Should convert the raw image", func() {
+		ops := newTestOpsWithStream(mockRunCmdWithStreamingStrict("", "convert", "-p", "-f", "raw", "-O", "qcow2", "source", "dest"))
+		err := ops.ConvertToQcow2("source", "dest")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Should return the conversion error", func() {
+		ops := newTestOpsWithStream(mockRunCmdWithStreamingStrict("exit 1", "convert", "-p", "-f", "raw", "-O", "qcow2", "source", "dest"))
+		err := ops.ConvertToQcow2("source", "dest")
+		Expect(err).To(MatchError(ContainSubstring("could not convert image to qcow2")))
+	})
+})
+
 func mockRunCmd(output, errString string, checkArgs ...string) runCmdFunc {
 	return func(_ context.Context, name string, args ...string) (bytes []byte, err error) {
 		for _, ca := range checkArgs {
@@ -23,6 +23,12 @@ const (
 	ExtXz = ".xz"
 	// ExtZst is a constant for the .zst extenstion
 	ExtZst = ".zst"
+	// ExtBz2 is a constant for the .bz2 extenstion
+	ExtBz2 = ".bz2"
+	// ExtLz4 is a constant for the .lz4 extenstion
+	ExtLz4 = ".lz4"
+	// ExtZip is a constant for the .zip extenstion
+	ExtZip = ".zip"
 	// ExtTarXz is a constant for the .tar.xz extenstion
 	ExtTarXz = ExtTar + ExtXz
 	// ExtTarGz is a constant for the .tar.gz extenstion
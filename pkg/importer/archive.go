@@ -0,0 +1,453 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"archive/tar"
+	"encoding/hex"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util/checksum"
+)
+
+const (
+	// maxArchiveManifestSize is the maximum size of the manifest of an archive.
+	maxArchiveManifestSize = 4 << 20
+	// paxXattrPrefix is the prefix of the PAX records holding the extended attributes of an entry.
+	paxXattrPrefix = "SCHILY.xattr."
+
+	// ArchivePreservePermissions preserves the permissions of the archive entries
+	ArchivePreservePermissions = "permissions"
+	// ArchivePreserveOwnership preserves the owner and group of the archive entries
+	ArchivePreserveOwnership = "ownership"
+	// ArchivePreserveXattrs preserves the extended attributes of the archive entries
+	ArchivePreserveXattrs = "xattrs"
+
+	// ExtractedFileTypeFile is the type of an extracted regular file
+	ExtractedFileTypeFile = "file"
+	// ExtractedFileTypeDirectory is the type of an extracted directory
+	ExtractedFileTypeDirectory = "directory"
+	// ExtractedFileTypeSymlink is the type of an extracted symbolic link
+	ExtractedFileTypeSymlink = "symlink"
+	// ExtractedFileTypeHardlink is the type of an extracted hard link
+	ExtractedFileTypeHardlink = "hardlink"
+)
+
+// ArchiveOptions configures the extraction of an archive.
+type ArchiveOptions struct {
+	// Manifest is the path of a checksum file in the archive, listing the checksums of all its regular files. It must
+	// be the first entry of the archive, and is not extracted.
+	Manifest string
+	// PreservePermissions applies the permissions of the entries, instead of 0644 for files and 0755 for directories
+	PreservePermissions bool
+	// PreserveOwnership applies the owner and group of the entries
+	PreserveOwnership bool
+	// PreserveXattrs applies the extended attributes of the entries
+	PreserveXattrs bool
+}
+
+// DefaultArchiveOptions returns the options used when an archive upload doesn't set any, the permissions of the
+// entries are preserved but not their ownership, as tar does for non-root users.
+func DefaultArchiveOptions() *ArchiveOptions {
+	return &ArchiveOptions{PreservePermissions: true}
+}
+
+// SetPreserve selects the attributes to preserve from a comma separated list of ArchivePreservePermissions,
+// ArchivePreserveOwnership and ArchivePreserveXattrs. The attributes which are not listed are not preserved.
+func (o *ArchiveOptions) SetPreserve(value string) error {
+	o.PreservePermissions = false
+	o.PreserveOwnership = false
+	o.PreserveXattrs = false
+	for _, attribute := range strings.Split(value, ",") {
+		switch strings.TrimSpace(attribute) {
+		case ArchivePreservePermissions:
+			o.PreservePermissions = true
+		case ArchivePreserveOwnership:
+			o.PreserveOwnership = true
+		case ArchivePreserveXattrs:
+			o.PreserveXattrs = true
+		case "":
+		default:
+			return errors.Errorf("unknown archive attribute %q", attribute)
+		}
+	}
+	return nil
+}
+
+// ExtractedFile describes an entry extracted from an archive.
+type ExtractedFile struct {
+	// Name is the path of the entry, relative to the target directory
+	Name string `json:"name"`
+	// Type is the type of the entry
+	Type string `json:"type"`
+	// Size is the size of a regular file
+	Size int64 `json:"size,omitempty"`
+	// Checksum is the checksum of a regular file in the "algorithm:hash" format, using the algorithm of the manifest
+	// or sha256
+	Checksum string `json:"checksum,omitempty"`
+	// Link is the target of a link
+	Link string `json:"link,omitempty"`
+}
+
+type tarExtractor struct {
+	destDir string
+	options *ArchiveOptions
+	// manifest has the checksums of the files which were not extracted yet
+	manifest map[string]string
+	files    []ExtractedFile
+	// created are the paths created by the extraction, removed if it fails
+	created []string
+	// dirs are the extracted directories, their attributes are applied once their content is extracted
+	dirs []extractedDir
+}
+
+// extractedDir is a directory created or reused by the extraction, identified by its inode so that its attributes are
+// not applied to what replaced it since
+type extractedDir struct {
+	header *tar.Header
+	name   string
+	dev    uint64
+	ino    uint64
+}
+
+// ExtractTar extracts a tar archive to destDir and returns the extracted entries. The entries are validated against
+// the manifest of the archive if options have one, and the extracted entries are removed if the extraction fails.
+// Entries which would be written outside of destDir are rejected, device and fifo entries are skipped.
+func ExtractTar(r io.Reader, destDir string, options *ArchiveOptions) ([]ExtractedFile, error) {
+	if options == nil {
+		options = DefaultArchiveOptions()
+	}
+	klog.V(1).Infof("begin extracting archive to %s", destDir)
+	e := &tarExtractor{destDir: destDir, options: options}
+	if err := e.extract(tar.NewReader(r)); err != nil {
+		e.cleanup()
+		return nil, err
+	}
+	klog.V(1).Infof("extracted %d entries to %s", len(e.files), destDir)
+	return e.files, nil
+}
+
+func (e *tarExtractor) extract(tr *tar.Reader) error {
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "unable to read archive")
+		}
+		name := path.Clean(header.Name)
+		if name == "." && header.Typeflag == tar.TypeDir {
+			// The root of archives created with "tar -C dir ."
+			continue
+		}
+		if !filepath.IsLocal(name) {
+			return errors.Wrapf(ErrInvalidArchive, "entry %q is outside of the target directory", header.Name)
+		}
+		if e.options.Manifest != "" && e.manifest == nil {
+			if err := e.readManifest(tr, header, name); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := e.extractEntry(tr, header, name); err != nil {
+			return err
+		}
+	}
+	if e.options.Manifest != "" && e.manifest == nil {
+		return errors.Wrapf(ErrInvalidArchive, "manifest %q not found", e.options.Manifest)
+	}
+	if len(e.manifest) > 0 {
+		missing := make([]string, 0, len(e.manifest))
+		for name := range e.manifest {
+			missing = append(missing, name)
+		}
+		sort.Strings(missing)
+		return errors.Wrapf(ErrInvalidArchive, "files listed in the manifest are missing: %s", strings.Join(missing, ", "))
+	}
+	// Directories are extracted before their content, their attributes are applied last so that their content can
+	// be written to them and doesn't update their modification time
+	for i := len(e.dirs) - 1; i >= 0; i-- {
+		if err := e.applyDirAttributes(e.dirs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyDirAttributes applies the attributes of a directory if it is still the directory extracted, a later entry may
+// have replaced it, or one of its parents, with a symlink which must not be followed
+func (e *tarExtractor) applyDirAttributes(dir extractedDir) error {
+	current := e.destDir
+	for _, element := range strings.Split(dir.name, "/") {
+		current = filepath.Join(current, element)
+		info, err := os.Lstat(current)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err != nil || !info.IsDir() {
+			klog.Warningf("Not applying the attributes of directory %s, %s was replaced", dir.name, current)
+			return nil
+		}
+	}
+	var stat unix.Stat_t
+	if err := unix.Lstat(current, &stat); err != nil {
+		return errors.Wrapf(err, "unable to stat %s", dir.name)
+	}
+	if uint64(stat.Dev) != dir.dev || stat.Ino != dir.ino {
+		klog.Warningf("Not applying the attributes of directory %s, it was replaced", dir.name)
+		return nil
+	}
+	return e.applyAttributes(current, dir.header)
+}
+
+func (e *tarExtractor) readManifest(tr *tar.Reader, header *tar.Header, name string) error {
+	if name != path.Clean(e.options.Manifest) || header.Typeflag != tar.TypeReg {
+		return errors.Wrapf(ErrInvalidArchive, "manifest %q must be the first entry of the archive, found %q", e.options.Manifest, header.Name)
+	}
+	if header.Size > maxArchiveManifestSize {
+		return errors.Wrapf(ErrInvalidArchive, "manifest is larger than %d bytes", maxArchiveManifestSize)
+	}
+	manifest, err := checksum.ParseChecksumFile(tr)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidArchive, "invalid manifest: %v", err)
+	}
+	klog.V(1).Infof("validating archive against manifest %s with %d files", name, len(manifest))
+	e.manifest = manifest
+	return nil
+}
+
+func (e *tarExtractor) extractEntry(tr *tar.Reader, header *tar.Header, name string) error {
+	target := filepath.Join(e.destDir, name)
+	if err := e.prepareTarget(name, header.Typeflag == tar.TypeDir); err != nil {
+		return err
+	}
+
+	file := ExtractedFile{Name: name}
+	switch header.Typeflag {
+	case tar.TypeDir:
+		file.Type = ExtractedFileTypeDirectory
+		if err := os.Mkdir(target, 0700); err != nil {
+			if !os.IsExist(err) {
+				return errors.Wrapf(err, "unable to create directory %s", name)
+			}
+		} else {
+			e.created = append(e.created, target)
+		}
+		var stat unix.Stat_t
+		if err := unix.Lstat(target, &stat); err != nil {
+			return errors.Wrapf(err, "unable to stat directory %s", name)
+		}
+		e.dirs = append(e.dirs, extractedDir{header: header, name: name, dev: uint64(stat.Dev), ino: stat.Ino})
+		e.files = append(e.files, file)
+		return nil
+	case tar.TypeReg, tar.TypeGNUSparse:
+		file.Type = ExtractedFileTypeFile
+		file.Size = header.Size
+		sum, err := e.writeFile(tr, target, name)
+		if err != nil {
+			return err
+		}
+		file.Checksum = sum
+	case tar.TypeSymlink:
+		file.Type = ExtractedFileTypeSymlink
+		file.Link = header.Linkname
+		if err := os.Symlink(header.Linkname, target); err != nil {
+			return errors.Wrapf(err, "unable to create symlink %s", name)
+		}
+		e.created = append(e.created, target)
+	case tar.TypeLink:
+		linkName := path.Clean(header.Linkname)
+		if !filepath.IsLocal(linkName) {
+			return errors.Wrapf(ErrInvalidArchive, "hard link %q points outside of the target directory", header.Name)
+		}
+		file.Type = ExtractedFileTypeHardlink
+		file.Link = linkName
+		linked := e.findFile(linkName)
+		if linked == nil || linked.Type != ExtractedFileTypeFile {
+			return errors.Wrapf(ErrInvalidArchive, "hard link %q points to %q, which is not an extracted file", header.Name, header.Linkname)
+		}
+		file.Size = linked.Size
+		file.Checksum = linked.Checksum
+		if expected, ok := e.manifest[name]; ok {
+			if expected != file.Checksum {
+				return errors.Wrapf(ErrChecksumMismatch, "%s: expected %s, calculated %s", name, expected, file.Checksum)
+			}
+			delete(e.manifest, name)
+		}
+		if err := os.Link(filepath.Join(e.destDir, linkName), target); err != nil {
+			return errors.Wrapf(err, "unable to create hard link %s", name)
+		}
+		e.created = append(e.created, target)
+		// The attributes are shared with the linked file
+		e.files = append(e.files, file)
+		return nil
+	default:
+		klog.Warningf("Skipping archive entry %s of type %q", header.Name, header.Typeflag)
+		return nil
+	}
+
+	if err := e.applyAttributes(target, header); err != nil {
+		return err
+	}
+	e.files = append(e.files, file)
+	return nil
+}
+
+// prepareTarget creates the missing parent directories of an entry and removes the file it replaces. The parents are
+// checked not to be symlinks, so that an entry can't be written outside of the target directory through a symlink
+// extracted before it.
+func (e *tarExtractor) prepareTarget(name string, isDir bool) error {
+	parent := e.destDir
+	for _, element := range strings.Split(path.Dir(name), "/") {
+		if element == "." {
+			break
+		}
+		parent = filepath.Join(parent, element)
+		info, err := os.Lstat(parent)
+		if os.IsNotExist(err) {
+			if err := os.Mkdir(parent, 0755); err != nil {
+				return errors.Wrapf(err, "unable to create directory %s", parent)
+			}
+			e.created = append(e.created, parent)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return errors.Wrapf(ErrInvalidArchive, "entry %q is below %s, which is not a directory", name, parent)
+		}
+	}
+
+	target := filepath.Join(e.destDir, name)
+	info, err := os.Lstat(target)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if isDir && info.IsDir() {
+		return nil
+	}
+	// Like tar, an entry replaces the file extracted or found before it, a symlink is replaced rather than followed
+	if err := os.Remove(target); err != nil {
+		return errors.Wrapf(err, "unable to replace %s", name)
+	}
+	return nil
+}
+
+// writeFile writes a regular file and returns its checksum, it is validated if the archive has a manifest
+func (e *tarExtractor) writeFile(r io.Reader, target, name string) (string, error) {
+	algorithm := checksum.AlgorithmSHA256
+	expected := ""
+	if e.manifest != nil {
+		var ok bool
+		if expected, ok = e.manifest[name]; !ok {
+			return "", errors.Wrapf(ErrInvalidArchive, "%s is not listed in the manifest", name)
+		}
+		algorithm, _, _ = checksum.ParseAndValidate(expected)
+	}
+	hasher, err := createHasher(algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to create file %s", name)
+	}
+	e.created = append(e.created, target)
+	_, err = io.Copy(f, io.TeeReader(r, hasher))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to write file %s", name)
+	}
+
+	sum := algorithm + ":" + hex.EncodeToString(hasher.Sum(nil))
+	if expected != "" {
+		if sum != expected {
+			return "", errors.Wrapf(ErrChecksumMismatch, "%s: expected %s, calculated %s", name, expected, sum)
+		}
+		delete(e.manifest, name)
+	}
+	return sum, nil
+}
+
+func (e *tarExtractor) applyAttributes(target string, header *tar.Header) error {
+	isSymlink := header.Typeflag == tar.TypeSymlink
+	if e.options.PreserveOwnership {
+		if err := os.Lchown(target, header.Uid, header.Gid); err != nil {
+			return errors.Wrapf(err, "unable to set the owner of %s", header.Name)
+		}
+	}
+	if isSymlink {
+		return nil
+	}
+	if e.options.PreserveXattrs {
+		for key, value := range header.PAXRecords {
+			if attr, ok := strings.CutPrefix(key, paxXattrPrefix); ok {
+				if err := unix.Lsetxattr(target, attr, []byte(value), 0); err != nil {
+					return errors.Wrapf(err, "unable to set extended attribute %s of %s", attr, header.Name)
+				}
+			}
+		}
+	}
+	// The mode is set after the owner, which clears the setuid and setgid bits
+	mode := os.FileMode(0644)
+	if header.Typeflag == tar.TypeDir {
+		mode = 0755
+	}
+	if e.options.PreservePermissions {
+		mode = header.FileInfo().Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+	}
+	if err := os.Chmod(target, mode); err != nil {
+		return errors.Wrapf(err, "unable to set the mode of %s", header.Name)
+	}
+	return os.Chtimes(target, header.AccessTime, header.ModTime)
+}
+
+func (e *tarExtractor) findFile(name string) *ExtractedFile {
+	for i := len(e.files) - 1; i >= 0; i-- {
+		if e.files[i].Name == name {
+			return &e.files[i]
+		}
+	}
+	return nil
+}
+
+// cleanup removes the extracted entries, in reverse order so that directories are empty when they are removed
+func (e *tarExtractor) cleanup() {
+	for i := len(e.created) - 1; i >= 0; i-- {
+		if err := os.Remove(e.created[i]); err != nil && !os.IsNotExist(err) {
+			klog.Errorf("Unable to remove %s: %v", e.created[i], err)
+		}
+	}
+}
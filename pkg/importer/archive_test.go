@@ -0,0 +1,225 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/md5" //nolint:gosec // MD5 is used to test the checksums of the manifest
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+
+	gzip "github.com/klauspost/pgzip"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type testTarEntry struct {
+	name     string
+	typeflag byte
+	mode     int64
+	content  string
+	linkname string
+}
+
+func newTestTar(entries ...testTarEntry) []byte {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:     entry.name,
+			Typeflag: entry.typeflag,
+			Mode:     entry.mode,
+			Size:     int64(len(entry.content)),
+			Linkname: entry.linkname,
+		}
+		if header.Mode == 0 {
+			header.Mode = 0640
+		}
+		if entry.typeflag != tar.TypeReg {
+			header.Size = 0
+		}
+		Expect(tw.WriteHeader(header)).To(Succeed())
+		_, err := tw.Write([]byte(entry.content))
+		Expect(err).ToNot(HaveOccurred())
+	}
+	Expect(tw.Close()).To(Succeed())
+	return buf.Bytes()
+}
+
+func testSHA256Checksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+var _ = Describe("Archive extraction", func() {
+	var destDir string
+
+	BeforeEach(func() {
+		destDir = GinkgoT().TempDir()
+	})
+
+	It("Should extract the entries of the archive", func() {
+		archive := newTestTar(
+			testTarEntry{name: "./", typeflag: tar.TypeDir, mode: 0755},
+			testTarEntry{name: "./dir/", typeflag: tar.TypeDir, mode: 0750},
+			testTarEntry{name: "./dir/disk.img", typeflag: tar.TypeReg, mode: 0600, content: "disk"},
+			testTarEntry{name: "./link", typeflag: tar.TypeSymlink, linkname: "dir/disk.img"},
+			testTarEntry{name: "./hardlink", typeflag: tar.TypeLink, linkname: "./dir/disk.img"},
+			testTarEntry{name: "./fifo", typeflag: tar.TypeFifo},
+		)
+		files, err := ExtractTar(bytes.NewReader(archive), destDir, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(files).To(Equal([]ExtractedFile{
+			{Name: "dir", Type: ExtractedFileTypeDirectory},
+			{Name: "dir/disk.img", Type: ExtractedFileTypeFile, Size: 4, Checksum: testSHA256Checksum("disk")},
+			{Name: "link", Type: ExtractedFileTypeSymlink, Link: "dir/disk.img"},
+			{Name: "hardlink", Type: ExtractedFileTypeHardlink, Link: "dir/disk.img", Size: 4, Checksum: testSHA256Checksum("disk")},
+		}))
+
+		content, err := os.ReadFile(filepath.Join(destDir, "link"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("disk"))
+		info, err := os.Stat(filepath.Join(destDir, "dir", "disk.img"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+		info, err = os.Stat(filepath.Join(destDir, "dir"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0750)))
+		_, err = os.Lstat(filepath.Join(destDir, "fifo"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("Should not preserve the permissions unless requested", func() {
+		archive := newTestTar(testTarEntry{name: "disk.img", typeflag: tar.TypeReg, mode: 0600, content: "disk"})
+		_, err := ExtractTar(bytes.NewReader(archive), destDir, &ArchiveOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		info, err := os.Stat(filepath.Join(destDir, "disk.img"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0644)))
+	})
+
+	It("Should validate the files against the manifest", func() {
+		md5Sum := md5.Sum([]byte("readme")) //nolint:gosec // MD5 is used to test the checksums of the manifest
+		manifest := hex.EncodeToString(md5Sum[:]) + "  README\n" + testSHA256Checksum("disk")[len("sha256:"):] + "  ./dir/disk.img\n"
+		archive := newTestTar(
+			testTarEntry{name: "SHA256SUMS", typeflag: tar.TypeReg, content: manifest},
+			testTarEntry{name: "README", typeflag: tar.TypeReg, content: "readme"},
+			testTarEntry{name: "dir/disk.img", typeflag: tar.TypeReg, content: "disk"},
+		)
+		files, err := ExtractTar(bytes.NewReader(archive), destDir, &ArchiveOptions{Manifest: "SHA256SUMS"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(files).To(HaveLen(2))
+		Expect(files[0].Checksum).To(Equal("md5:" + hex.EncodeToString(md5Sum[:])))
+		_, err = os.Stat(filepath.Join(destDir, "SHA256SUMS"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	DescribeTable("Should reject an archive which doesn't match its manifest", func(expectedErr error, entries ...testTarEntry) {
+		manifest := testSHA256Checksum("disk")[len("sha256:"):] + "  dir/disk.img\n"
+		entries = append([]testTarEntry{{name: "SHA256SUMS", typeflag: tar.TypeReg, content: manifest}}, entries...)
+		_, err := ExtractTar(bytes.NewReader(newTestTar(entries...)), destDir, &ArchiveOptions{Manifest: "SHA256SUMS"})
+		Expect(err).To(MatchError(expectedErr))
+
+		By("Removing the extracted files")
+		dirEntries, err := os.ReadDir(destDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dirEntries).To(BeEmpty())
+	},
+		Entry("with a checksum mismatch", ErrChecksumMismatch,
+			testTarEntry{name: "dir/disk.img", typeflag: tar.TypeReg, content: "corrupted"}),
+		Entry("with a file which is not listed", ErrInvalidArchive,
+			testTarEntry{name: "dir/disk.img", typeflag: tar.TypeReg, content: "disk"},
+			testTarEntry{name: "extra", typeflag: tar.TypeReg, content: "extra"}),
+		Entry("with a missing file", ErrInvalidArchive,
+			testTarEntry{name: "dir/other.img", typeflag: tar.TypeDir}),
+	)
+
+	It("Should reject an archive whose manifest is not the first entry", func() {
+		archive := newTestTar(
+			testTarEntry{name: "disk.img", typeflag: tar.TypeReg, content: "disk"},
+			testTarEntry{name: "SHA256SUMS", typeflag: tar.TypeReg, content: ""},
+		)
+		_, err := ExtractTar(bytes.NewReader(archive), destDir, &ArchiveOptions{Manifest: "SHA256SUMS"})
+		Expect(err).To(MatchError(ErrInvalidArchive))
+	})
+
+	DescribeTable("Should reject entries outside of the target directory", func(entries ...testTarEntry) {
+		outside := GinkgoT().TempDir()
+		Expect(os.Symlink(outside, filepath.Join(destDir, "outside"))).To(Succeed())
+		_, err := ExtractTar(bytes.NewReader(newTestTar(entries...)), destDir, nil)
+		Expect(err).To(MatchError(ErrInvalidArchive))
+		dirEntries, err := os.ReadDir(outside)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dirEntries).To(BeEmpty())
+	},
+		Entry("with a relative path", testTarEntry{name: "../evil", typeflag: tar.TypeReg, content: "evil"}),
+		Entry("with an absolute path", testTarEntry{name: "/evil", typeflag: tar.TypeReg, content: "evil"}),
+		Entry("through a symlink", testTarEntry{name: "outside/evil", typeflag: tar.TypeReg, content: "evil"}),
+		Entry("through an extracted symlink",
+			testTarEntry{name: "escape", typeflag: tar.TypeSymlink, linkname: ".."},
+			testTarEntry{name: "escape/evil", typeflag: tar.TypeReg, content: "evil"}),
+		Entry("with a hard link", testTarEntry{name: "passwd", typeflag: tar.TypeLink, linkname: "../../etc/passwd"}),
+	)
+
+	It("Should not apply the attributes of a directory to the symlink which replaced it", func() {
+		outside := filepath.Join(GinkgoT().TempDir(), "outside")
+		Expect(os.WriteFile(outside, []byte("outside"), 0600)).To(Succeed())
+		archive := newTestTar(
+			testTarEntry{name: "a/", typeflag: tar.TypeDir, mode: 0777},
+			testTarEntry{name: "a", typeflag: tar.TypeSymlink, linkname: outside},
+		)
+		_, err := ExtractTar(bytes.NewReader(archive), destDir, &ArchiveOptions{PreservePermissions: true})
+		Expect(err).ToNot(HaveOccurred())
+		info, err := os.Stat(outside)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+		link, err := os.Readlink(filepath.Join(destDir, "a"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(link).To(Equal(outside))
+	})
+
+	It("Should extract a compressed archive upload", func() {
+		// The compressed archive must be larger than the header of the format readers
+		content := &bytes.Buffer{}
+		for i := 0; content.Len() < 4096; i++ {
+			sum := sha256.Sum256([]byte{byte(i)})
+			content.Write(sum[:])
+		}
+		buf := &bytes.Buffer{}
+		gz := gzip.NewWriter(buf)
+		_, err := gz.Write(newTestTar(testTarEntry{name: "disk.img", typeflag: tar.TypeReg, content: content.String()}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(gz.Close()).To(Succeed())
+
+		ud := NewArchiveUploadDataSource(io.NopCloser(buf), nil, nil)
+		defer ud.Close()
+		phase, err := ud.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataDir))
+		phase, err = ud.Transfer(destDir, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseComplete))
+		Expect(ud.ExtractedFiles()).To(Equal([]ExtractedFile{
+			{Name: "disk.img", Type: ExtractedFileTypeFile, Size: int64(content.Len()), Checksum: testSHA256Checksum(content.String())},
+		}))
+	})
+})
@@ -75,6 +75,14 @@ func (cv *ChecksumValidator) GetReader(r io.Reader) io.Reader {
 	return io.TeeReader(r, cv.hasher)
 }
 
+// Resume discards the calculated checksum and recalculates it from the passed in reader, which should
+// contain the data that was transferred before an interrupted transfer is resumed.
+func (cv *ChecksumValidator) Resume(r io.Reader) error {
+	cv.hasher.Reset()
+	_, err := io.Copy(cv.hasher, r)
+	return err
+}
+
 // Validate checks if the calculated checksum matches the expected checksum
 func (cv *ChecksumValidator) Validate() error {
 	calculatedChecksum := hex.EncodeToString(cv.hasher.Sum(nil))
@@ -93,6 +101,11 @@ func (cv *ChecksumValidator) Validate() error {
 	return nil
 }
 
+// Checksum returns the calculated checksum in the "algorithm:hash" format
+func (cv *ChecksumValidator) Checksum() string {
+	return cv.algorithm + ":" + hex.EncodeToString(cv.hasher.Sum(nil))
+}
+
 // Algorithm returns the hash algorithm being used
 func (cv *ChecksumValidator) Algorithm() string {
 	return cv.algorithm
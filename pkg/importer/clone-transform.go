@@ -0,0 +1,280 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"io"
+	"net/url"
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/disklayout"
+)
+
+// CloneTransformation describes the changes made to the disk image of a host assisted clone on the target
+type CloneTransformation struct {
+	// Resize fits the disk image to the size of the target, shrinking it if needed
+	Resize bool
+	// LogicalSectorSize is the logical sector size the GPT partition table is converted to, 0 to keep it
+	LogicalSectorSize int64
+}
+
+// IsSet returns true if the disk image is changed
+func (t CloneTransformation) IsSet() bool {
+	return t.Resize || t.LogicalSectorSize != 0
+}
+
+// CloneTransformDataSource is the interface data sources that change the disk image of a clone after it is transferred
+// to the target file should implement.
+type CloneTransformDataSource interface {
+	DataSourceInterface
+	// GetCloneTransformation returns the changes made to the disk image
+	GetCloneTransformation() CloneTransformation
+}
+
+// StreamToFileFunc writes the data of a stream to a file, it returns the bytes read and written
+type StreamToFileFunc func(r io.Reader, fileName string, preallocate bool) (int64, int64, error)
+
+// CloneDataSource contains the information needed to transform the raw disk image of a host assisted clone.
+// Sequence of phases:
+// 1. ProcessingPhaseInfo -> ProcessingPhaseTransferDataFile
+// 2a. ProcessingPhaseTransferDataFile -> ProcessingPhaseConvertSectorSize, when the logical sector size is changed
+// 2b. ProcessingPhaseTransferDataFile -> ProcessingPhaseResize
+// 3. ProcessingPhaseConvertSectorSize -> ProcessingPhaseResize
+type CloneDataSource struct {
+	// stream is the raw disk image of the clone source
+	stream io.ReadCloser
+	// streamToFile writes the stream to the target file
+	streamToFile StreamToFileFunc
+	// transformation is the changes made to the disk image
+	transformation CloneTransformation
+}
+
+// NewCloneDataSource creates a new instance of a CloneDataSource, streamToFile writes the disk image to the target
+func NewCloneDataSource(stream io.ReadCloser, streamToFile StreamToFileFunc, transformation CloneTransformation) *CloneDataSource {
+	return &CloneDataSource{
+		stream:         stream,
+		streamToFile:   streamToFile,
+		transformation: transformation,
+	}
+}
+
+// Info is called to get initial information about the data, the disk image is always raw.
+func (cd *CloneDataSource) Info() (ProcessingPhase, error) {
+	return ProcessingPhaseTransferDataFile, nil
+}
+
+// Transfer is not supported, the disk image is written to the target file.
+func (cd *CloneDataSource) Transfer(path string, preallocation bool) (ProcessingPhase, error) {
+	return ProcessingPhaseError, errors.New("clone transformations do not use scratch space")
+}
+
+// TransferFile is called to transfer the data from the source to the passed in file.
+func (cd *CloneDataSource) TransferFile(fileName string, preallocation bool) (ProcessingPhase, error) {
+	isDevice, err := IsDevice(fileName)
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	// a resized image is preallocated once it has its final size
+	preallocate := preallocation && (isDevice || !cd.transformation.Resize)
+	if _, _, err := cd.streamToFile(cd.stream, fileName, preallocate); err != nil {
+		return ProcessingPhaseError, err
+	}
+	if cd.transformation.LogicalSectorSize != 0 {
+		return ProcessingPhaseConvertSectorSize, nil
+	}
+	return ProcessingPhaseResize, nil
+}
+
+// GetURL returns nil, the disk image is not converted.
+func (cd *CloneDataSource) GetURL() *url.URL {
+	return nil
+}
+
+// GetTerminationMessage returns data to be serialized and used as the termination message of the importer.
+func (cd *CloneDataSource) GetTerminationMessage() *common.TerminationMessage {
+	return nil
+}
+
+// GetCloneTransformation returns the changes made to the disk image
+func (cd *CloneDataSource) GetCloneTransformation() CloneTransformation {
+	return cd.transformation
+}
+
+// Close closes the stream of the disk image.
+func (cd *CloneDataSource) Close() error {
+	return cd.stream.Close()
+}
+
+// openDisk opens the disk image or block device, it returns the size in bytes
+func openDisk(dataFile string) (*os.File, int64, error) {
+	f, err := os.OpenFile(dataFile, os.O_RDWR, 0)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "could not open %s", dataFile)
+	}
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		f.Close()
+		return nil, 0, errors.Wrapf(err, "could not get size of %s", dataFile)
+	}
+	return f, size, nil
+}
+
+// ConvertSectorSize converts the GPT partition table of the disk image to sectorSize logical sectors. Disk images
+// without partition table are left as is, MBR partition tables cannot be converted. Partitions or disk images holding
+// content which can't be used with sectorSize logical sectors are refused.
+func ConvertSectorSize(dataFile string, sectorSize int64) error {
+	f, size, err := openDisk(dataFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	current, err := disklayout.GPTSectorSize(f)
+	if err != nil {
+		return err
+	}
+	if current == 0 {
+		hasMBR, err := disklayout.HasMBRPartitions(f)
+		if err != nil {
+			return err
+		}
+		if hasMBR {
+			return errors.New("MBR partition tables cannot be converted to another logical sector size")
+		}
+		if err := disklayout.CheckSectorSize(f, sectorSize); err != nil {
+			return err
+		}
+		klog.V(1).Infof("No partition table in %s, keeping the logical sector size", dataFile)
+		return nil
+	}
+	if current == sectorSize {
+		klog.V(1).Infof("The partition table of %s already uses %d bytes sectors", dataFile, sectorSize)
+		return nil
+	}
+
+	klog.V(1).Infof("Converting the partition table of %s from %d to %d bytes sectors", dataFile, current, sectorSize)
+	if err := disklayout.RewriteGPT(f, util.RoundDown(size, sectorSize), sectorSize); err != nil {
+		return errors.Wrap(err, "could not convert the partition table")
+	}
+	return f.Sync()
+}
+
+// moveGPTBackup moves the backup GPT partition table of the disk image to its end after it was resized, disk images
+// without GPT partition table are left as is.
+func moveGPTBackup(dataFile string) error {
+	f, size, err := openDisk(dataFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sectorSize, err := disklayout.GPTSectorSize(f)
+	if err != nil || sectorSize == 0 {
+		return err
+	}
+	klog.V(3).Infof("Moving the backup partition table to the end of %s", dataFile)
+	if err := disklayout.RewriteGPT(f, util.RoundDown(size, sectorSize), sectorSize); err != nil {
+		return errors.Wrap(err, "could not move the backup partition table")
+	}
+	return f.Sync()
+}
+
+// ResizeImageToFit resizes the image to the requested size like ResizeImage, or to the available space when it is
+// smaller, but it shrinks images larger than that size. Images are only shrunk when their layout is known to be safe
+// to shrink and fits in the new size, see disklayout.UsedSize. The image is preallocated after it was resized.
+func ResizeImageToFit(dataFile, imageSize string, totalTargetSpace int64, preallocation bool) error {
+	if imageSize == "" {
+		return errors.New("Image resize called with blank resize")
+	}
+	dataFileURL, _ := url.Parse(dataFile)
+	info, err := qemuOperations.Info(dataFileURL)
+	if err != nil {
+		return err
+	}
+	newImageSizeQuantity := resource.MustParse(imageSize)
+	minSizeQuantity := util.MinQuantity(resource.NewScaledQuantity(totalTargetSpace, 0), &newImageSizeQuantity)
+	// whole sectors of either logical sector size
+	newSize := util.RoundDown(minSizeQuantity.Value(), disklayout.SectorSize4K)
+
+	switch {
+	case newSize == info.VirtualSize:
+		klog.V(1).Infof("No need to resize image. Requested size: %s, Image size: %d.\n", imageSize, info.VirtualSize)
+	case newSize < info.VirtualSize:
+		if err := validateShrink(dataFile, info.VirtualSize, newSize); err != nil {
+			return err
+		}
+		klog.V(1).Infof("Shrinking image size to: %d\n", newSize)
+		if err := qemuOperations.Shrink(dataFile, *resource.NewScaledQuantity(newSize, 0)); err != nil {
+			return err
+		}
+	default:
+		klog.V(1).Infof("Expanding image size to: %d\n", newSize)
+		if err := qemuOperations.Resize(dataFile, *resource.NewScaledQuantity(newSize, 0), false); err != nil {
+			return err
+		}
+	}
+	if err := moveGPTBackup(dataFile); err != nil {
+		return err
+	}
+	if preallocation {
+		return preallocateFile(dataFile)
+	}
+	return nil
+}
+
+// validateShrink returns an error unless the layout of the disk image is known, holds no data beyond its partitions or
+// its filesystem, and fits in newSize bytes
+func validateShrink(dataFile string, size, newSize int64) error {
+	f, err := os.Open(dataFile)
+	if err != nil {
+		return errors.Wrapf(err, "could not open %s", dataFile)
+	}
+	defer f.Close()
+
+	used, err := disklayout.UsedSize(f, size)
+	if errors.Is(err, disklayout.ErrUnknownLayout) {
+		return errors.Errorf("cannot shrink image of %d bytes to %d bytes, it has no known partition table or filesystem", size, newSize)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "cannot shrink image of %d bytes to %d bytes", size, newSize)
+	}
+	if used > newSize {
+		return errors.Errorf("cannot shrink image of %d bytes to %d bytes, its data uses %d bytes", size, newSize, used)
+	}
+	return nil
+}
+
+// preallocateFile allocates the blocks of the whole file
+func preallocateFile(dataFile string) error {
+	f, size, err := openDisk(dataFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := syscall.Fallocate(int(f.Fd()), 0, 0, size); err != nil {
+		return errors.Wrapf(err, "could not preallocate %s", dataFile)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"kubevirt.io/containerized-data-importer/pkg/image"
+	"kubevirt.io/containerized-data-importer/pkg/util/disklayout"
+)
+
+const mib = 1024 * 1024
+
+// newExtDisk returns a disk image of size bytes holding an ext4 filesystem of fsSize bytes
+func newExtDisk(size, fsSize int64) []byte {
+	disk := make([]byte, size)
+	sb := disk[1024:]
+	binary.LittleEndian.PutUint32(sb[4:], uint32(fsSize/4096))
+	binary.LittleEndian.PutUint32(sb[24:], 2)
+	binary.LittleEndian.PutUint16(sb[56:], 0xef53)
+	return disk
+}
+
+// newMBRDisk returns a disk image of size bytes with an MBR partition table
+func newMBRDisk(size int64) []byte {
+	disk := make([]byte, size)
+	entry := disk[446:]
+	entry[4] = 0x83
+	binary.LittleEndian.PutUint32(entry[8:], 2048)
+	binary.LittleEndian.PutUint32(entry[12:], 2048)
+	disk[510], disk[511] = 0x55, 0xaa
+	return disk
+}
+
+// imageInfo returns the qemu-img info of a raw image of size bytes
+func imageInfo(size int64) fakeInfoOpRetVal {
+	return fakeInfoOpRetVal{&image.ImgInfo{VirtualSize: size}, nil}
+}
+
+var _ = Describe("Clone data source", func() {
+	It("Info should return TransferDataFile", func() {
+		cd := NewCloneDataSource(io.NopCloser(&bytes.Buffer{}), StreamDataToFile, CloneTransformation{Resize: true})
+		Expect(cd.Info()).To(Equal(ProcessingPhaseTransferDataFile))
+		Expect(cd.GetURL()).To(BeNil())
+	})
+
+	DescribeTable("TransferFile should", func(transformation CloneTransformation, preallocation bool, expectedPhase ProcessingPhase, expectedPreallocation bool) {
+		var preallocated bool
+		streamToFile := func(r io.Reader, fileName string, preallocate bool) (int64, int64, error) {
+			preallocated = preallocate
+			return 0, 0, nil
+		}
+		cd := NewCloneDataSource(io.NopCloser(&bytes.Buffer{}), streamToFile, transformation)
+		Expect(cd.TransferFile(filepath.Join(os.TempDir(), "missing"), preallocation)).To(Equal(expectedPhase))
+		Expect(preallocated).To(Equal(expectedPreallocation))
+	},
+		Entry("resize after streaming", CloneTransformation{Resize: true}, false, ProcessingPhaseResize, false),
+		Entry("convert the sector size after streaming", CloneTransformation{LogicalSectorSize: 4096}, false, ProcessingPhaseConvertSectorSize, false),
+		Entry("preallocate while streaming when the image is not resized", CloneTransformation{LogicalSectorSize: 4096}, true, ProcessingPhaseConvertSectorSize, true),
+		Entry("preallocate after resizing", CloneTransformation{Resize: true}, true, ProcessingPhaseResize, false),
+	)
+})
+
+var _ = Describe("Clone transformations", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "clone-transform")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	writeDisk := func(disk []byte) string {
+		fileName := filepath.Join(tmpDir, "disk.img")
+		Expect(os.WriteFile(fileName, disk, 0600)).To(Succeed())
+		return fileName
+	}
+
+	Context("ConvertSectorSize", func() {
+		It("should keep disk images without partition table as is", func() {
+			disk := newExtDisk(4*mib, 4*mib)
+			fileName := writeDisk(disk)
+			Expect(ConvertSectorSize(fileName, 4096)).To(Succeed())
+			Expect(os.ReadFile(fileName)).To(Equal(disk))
+		})
+
+		It("should refuse disk images without partition table holding a filesystem with smaller blocks", func() {
+			disk := newExtDisk(4*mib, 4*mib)
+			binary.LittleEndian.PutUint32(disk[1024+24:], 0)
+			fileName := writeDisk(disk)
+			Expect(ConvertSectorSize(fileName, 4096)).To(MatchError(disklayout.ErrIncompatibleSectorSize))
+		})
+
+		It("should fail to convert MBR partition tables", func() {
+			fileName := writeDisk(newMBRDisk(4 * mib))
+			err := ConvertSectorSize(fileName, 4096)
+			Expect(err).To(MatchError(ContainSubstring("MBR partition tables cannot be converted")))
+		})
+	})
+
+	Context("ResizeImageToFit", func() {
+		It("should shrink the image when its filesystem fits", func() {
+			fileName := writeDisk(newExtDisk(16*mib, 4*mib))
+			qemuOperations := NewFakeQEMUOperations(nil, nil, imageInfo(16*mib), nil, nil, resource.NewScaledQuantity(8*mib, 0))
+			replaceQEMUOperations(qemuOperations, func() {
+				Expect(ResizeImageToFit(fileName, "8Mi", 32*mib, false)).To(Succeed())
+			})
+		})
+
+		It("should shrink the image to the available space", func() {
+			fileName := writeDisk(newExtDisk(16*mib, 4*mib))
+			qemuOperations := NewFakeQEMUOperations(nil, nil, imageInfo(16*mib), nil, nil, resource.NewScaledQuantity(6*mib, 0))
+			replaceQEMUOperations(qemuOperations, func() {
+				Expect(ResizeImageToFit(fileName, "8Mi", 6*mib, false)).To(Succeed())
+			})
+		})
+
+		It("should not shrink the image when its filesystem does not fit", func() {
+			fileName := writeDisk(newExtDisk(16*mib, 12*mib))
+			replaceQEMUOperations(NewFakeQEMUOperations(nil, nil, imageInfo(16*mib), nil, nil, nil), func() {
+				err := ResizeImageToFit(fileName, "8Mi", 32*mib, false)
+				Expect(err).To(MatchError(ContainSubstring("its data uses 12582912 bytes")))
+			})
+		})
+
+		It("should not shrink the image when it has data after its filesystem", func() {
+			disk := newExtDisk(16*mib, 4*mib)
+			disk[12*mib] = 1
+			fileName := writeDisk(disk)
+			replaceQEMUOperations(NewFakeQEMUOperations(nil, nil, imageInfo(16*mib), nil, nil, nil), func() {
+				err := ResizeImageToFit(fileName, "8Mi", 32*mib, false)
+				Expect(err).To(MatchError(disklayout.ErrUnsafeLayout))
+			})
+		})
+
+		It("should not shrink the image when its layout is unknown", func() {
+			fileName := writeDisk(make([]byte, 16*mib))
+			replaceQEMUOperations(NewFakeQEMUOperations(nil, nil, imageInfo(16*mib), nil, nil, nil), func() {
+				err := ResizeImageToFit(fileName, "8Mi", 32*mib, false)
+				Expect(err).To(MatchError(ContainSubstring("no known partition table or filesystem")))
+			})
+		})
+
+		It("should expand and preallocate the image", func() {
+			fileName := writeDisk(newExtDisk(4*mib, 4*mib))
+			qemuOperations := NewFakeQEMUOperations(nil, nil, imageInfo(4*mib), nil, nil, resource.NewScaledQuantity(8*mib, 0))
+			replaceQEMUOperations(qemuOperations, func() {
+				Expect(ResizeImageToFit(fileName, "8Mi", 32*mib, true)).To(Succeed())
+			})
+			var stat syscall.Stat_t
+			Expect(syscall.Stat(fileName, &stat)).To(Succeed())
+			Expect(stat.Blocks * 512).To(BeNumerically(">=", 4*mib))
+		})
+	})
+
+	It("should shrink a cloned image through the data processor", func() {
+		dataFile := filepath.Join(tmpDir, "disk.img")
+		stream := io.NopCloser(bytes.NewReader(newExtDisk(16*mib, 4*mib)))
+		cd := NewCloneDataSource(stream, StreamDataToFile, CloneTransformation{Resize: true})
+		dp := NewDataProcessor(cd, dataFile, tmpDir, "", "8Mi", 0, false, "")
+		qemuOperations := NewFakeQEMUOperations(nil, nil, imageInfo(16*mib), nil, nil, resource.NewScaledQuantity(8*mib, 0))
+		replaceQEMUOperations(qemuOperations, func() {
+			Expect(dp.ProcessData()).To(Succeed())
+		})
+	})
+})
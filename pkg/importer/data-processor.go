@@ -61,6 +61,8 @@ const (
 	ProcessingPhaseError ProcessingPhase = common.GenericError
 	// ProcessingPhaseMergeDelta is the phase in a multi-stage import where a delta image downloaded to scratch is applied to the base image
 	ProcessingPhaseMergeDelta ProcessingPhase = "MergeDelta"
+	// ProcessingPhaseConvertSectorSize is the phase in which the partition table of a cloned disk image is converted to another logical sector size
+	ProcessingPhaseConvertSectorSize ProcessingPhase = "ConvertSectorSize"
 )
 
 // may be overridden in tests
@@ -89,6 +91,25 @@ type ResumableDataSource interface {
 	GetResumePhase() ProcessingPhase
 }
 
+// VirtualSizeDataSource is the interface data sources that know the virtual size of the image before it is transferred
+// should implement, the size is validated against the target instead of inspecting the image.
+type VirtualSizeDataSource interface {
+	DataSourceInterface
+	// GetVirtualSize returns the virtual size of the image in bytes, 0 if unknown
+	GetVirtualSize() int64
+}
+
+// StreamConvertDataSource is the interface data sources that can convert the image to raw while it is transferred to
+// the target file should implement, instead of transferring it to the scratch space first.
+type StreamConvertDataSource interface {
+	VirtualSizeDataSource
+	// CanStreamConvert returns true if the image can be converted while it is transferred.
+	CanStreamConvert() bool
+	// TransferConvertFile is called to convert the data from the source to the file passed in. Data stored out of
+	// order in the image is kept in the scratch path, unless it is empty.
+	TransferConvertFile(fileName, scratchPath string, preallocation bool) (ProcessingPhase, error)
+}
+
 // DataProcessor holds the fields needed to process data from a data provider.
 type DataProcessor struct {
 	// currentPhase is the phase the processing is in currently.
@@ -113,9 +134,13 @@ type DataProcessor struct {
 	preallocationApplied bool
 	// phaseExecutors is a mapping from the given processing phase to its execution function. The function returns the next processing phase or error.
 	phaseExecutors map[ProcessingPhase]func() (ProcessingPhase, error)
+	// streamConvert is true when the source converts the image while transferring it to the target file
+	streamConvert bool
 	// cacheMode is the mode in which we choose the qemu-img cache mode:
 	// TRY_NONE = bypass page cache if the target supports it, otherwise, fall back to using page cache
 	cacheMode string
+	// phaseObserver is called with each phase the processing enters
+	phaseObserver func(ProcessingPhase)
 }
 
 // NewDataProcessor create a new instance of a data processor using the passed in data provider.
@@ -146,6 +171,17 @@ func (dp *DataProcessor) RegisterPhaseExecutor(pp ProcessingPhase, executor func
 	dp.phaseExecutors[pp] = executor
 }
 
+// SetPhaseObserver sets a function called with each phase the processing enters, and with the phase it stops at.
+func (dp *DataProcessor) SetPhaseObserver(observer func(ProcessingPhase)) {
+	dp.phaseObserver = observer
+}
+
+func (dp *DataProcessor) observePhase() {
+	if dp.phaseObserver != nil {
+		dp.phaseObserver(dp.currentPhase)
+	}
+}
+
 // ProcessData is the main synchronous processing loop
 func (dp *DataProcessor) ProcessData() error {
 	return dp.ProcessDataWithPause()
@@ -168,6 +204,8 @@ func (dp *DataProcessor) initDefaultPhases() {
 		pp, err := dp.source.Info()
 		if err != nil {
 			err = errors.Wrap(err, "Unable to obtain information about data source")
+		} else if pp == ProcessingPhaseTransferScratch {
+			pp, err = dp.streamConvertPhase()
 		}
 		return pp, err
 	})
@@ -189,6 +227,9 @@ func (dp *DataProcessor) initDefaultPhases() {
 		return pp, err
 	})
 	dp.RegisterPhaseExecutor(ProcessingPhaseTransferDataFile, func() (ProcessingPhase, error) {
+		if dp.streamConvert {
+			return dp.transferConvertFile()
+		}
 		pp, err := dp.source.TransferFile(dp.dataFile, dp.preallocation)
 		if err != nil {
 			err = errors.Wrap(err, "Unable to transfer source data to target file")
@@ -206,7 +247,11 @@ func (dp *DataProcessor) initDefaultPhases() {
 	dp.RegisterPhaseExecutor(ProcessingPhaseValidatePreScratch, func() (ProcessingPhase, error) {
 		pp := ProcessingPhaseTransferScratch
 		var err error
-		if sizeErr := dp.validate(dp.source.GetURL()); sizeErr != nil {
+		if vs, ok := dp.source.(VirtualSizeDataSource); ok && vs.GetVirtualSize() > 0 {
+			if err = dp.validateVirtualSize(vs.GetVirtualSize()); err != nil {
+				pp = ProcessingPhaseError
+			}
+		} else if sizeErr := dp.validate(dp.source.GetURL()); sizeErr != nil {
 			if errors.Is(sizeErr, ValidationSizeError{image.ErrLargerPVCRequired}) {
 				pp = ProcessingPhaseError
 				err = sizeErr
@@ -228,6 +273,13 @@ func (dp *DataProcessor) initDefaultPhases() {
 		}
 		return pp, err
 	})
+	dp.RegisterPhaseExecutor(ProcessingPhaseConvertSectorSize, func() (ProcessingPhase, error) {
+		pp, err := dp.convertSectorSize()
+		if err != nil {
+			err = errors.Wrap(err, "Unable to convert disk image to requested logical sector size")
+		}
+		return pp, err
+	})
 	dp.RegisterPhaseExecutor(ProcessingPhaseMergeDelta, func() (ProcessingPhase, error) {
 		pp, err := dp.merge()
 		if err != nil {
@@ -240,7 +292,9 @@ func (dp *DataProcessor) initDefaultPhases() {
 // ProcessDataWithPause is the main processing loop.
 func (dp *DataProcessor) ProcessDataWithPause() error {
 	visited := make(map[ProcessingPhase]bool, len(dp.phaseExecutors))
+	defer dp.observePhase()
 	for dp.currentPhase != ProcessingPhaseComplete && dp.currentPhase != ProcessingPhasePause {
+		dp.observePhase()
 		if visited[dp.currentPhase] {
 			err := errors.Errorf("loop detected on phase %s", dp.currentPhase)
 			klog.Errorf("%+v", err)
@@ -271,6 +325,48 @@ func (dp *DataProcessor) validate(url *url.URL) error {
 	return nil
 }
 
+// validateVirtualSize returns an error if the virtual size of the image is larger than the available space.
+func (dp *DataProcessor) validateVirtualSize(virtualSize int64) error {
+	if virtualSize > dp.availableSpace {
+		return ValidationSizeError{err: errors.Wrapf(image.ErrLargerPVCRequired, "virtual image size %d is larger than the reported available storage %d", virtualSize, dp.availableSpace)}
+	}
+	return nil
+}
+
+// streamConvertPhase returns the phase following the info phase when the source requested scratch space. If the
+// source can convert the image while transferring it, the image is transferred to the target file directly.
+func (dp *DataProcessor) streamConvertPhase() (ProcessingPhase, error) {
+	sc, ok := dp.source.(StreamConvertDataSource)
+	if !ok || !sc.CanStreamConvert() {
+		return ProcessingPhaseTransferScratch, nil
+	}
+	if err := dp.validateVirtualSize(sc.GetVirtualSize()); err != nil {
+		return ProcessingPhaseError, err
+	}
+	klog.V(1).Infoln("Converting the image while it is transferred to the target")
+	dp.streamConvert = true
+	return ProcessingPhaseTransferDataFile, nil
+}
+
+// transferConvertFile converts the image while it is transferred to the target file. The scratch space, if any, keeps
+// the data stored out of order, without it the transfer may require scratch space.
+func (dp *DataProcessor) transferConvertFile() (ProcessingPhase, error) {
+	scratchPath := ""
+	if size, _ := getAvailableSpaceFunc(dp.scratchDataDir); size > 0 {
+		scratchPath = dp.scratchDataDir
+	}
+	pp, err := dp.source.(StreamConvertDataSource).TransferConvertFile(dp.dataFile, scratchPath, dp.preallocation)
+	if errors.Is(err, ErrStreamConvert) && scratchPath == "" {
+		klog.Errorf("%v", err)
+		return pp, ErrRequiresScratchSpace
+	}
+	if err != nil {
+		return pp, errors.Wrap(err, "Unable to convert source data to target file")
+	}
+	dp.preallocationApplied = dp.preallocation
+	return pp, nil
+}
+
 // convert is called when convert the image from the url to a RAW disk image. Source formats include RAW/QCOW2 (Raw to raw conversion is a copy)
 func (dp *DataProcessor) convert(url *url.URL) (ProcessingPhase, error) {
 	err := dp.validate(url)
@@ -291,14 +387,41 @@ func (dp *DataProcessor) convert(url *url.URL) (ProcessingPhase, error) {
 	return ProcessingPhaseResize, nil
 }
 
+// convertSectorSize converts the partition table of a cloned disk image to the logical sector size of the transformation
+func (dp *DataProcessor) convertSectorSize() (ProcessingPhase, error) {
+	if err := ConvertSectorSize(dp.dataFile, dp.cloneTransformation().LogicalSectorSize); err != nil {
+		return ProcessingPhaseError, err
+	}
+	return ProcessingPhaseResize, nil
+}
+
+// cloneTransformation returns the changes the source makes to a cloned disk image
+func (dp *DataProcessor) cloneTransformation() CloneTransformation {
+	if ct, ok := dp.source.(CloneTransformDataSource); ok {
+		return ct.GetCloneTransformation()
+	}
+	return CloneTransformation{}
+}
+
 func (dp *DataProcessor) resize() (ProcessingPhase, error) {
 	size, _ := getAvailableSpaceBlockFunc(dp.dataFile)
 	klog.V(3).Infof("Available space in dataFile: %d", size)
 	isBlockDev := size >= int64(0)
+	fitImage := dp.cloneTransformation().Resize
+	if isBlockDev && fitImage {
+		// the size of block devices is fixed, the partition table is fitted to the device
+		if err := moveGPTBackup(dp.dataFile); err != nil {
+			return ProcessingPhaseError, errors.Wrap(err, "Resize of image failed")
+		}
+	}
 	if !isBlockDev {
 		if dp.requestImageSize != "" {
 			klog.V(3).Infoln("Resizing image")
-			err := ResizeImage(dp.dataFile, dp.requestImageSize, dp.getUsableSpace(), dp.preallocation)
+			resizeImage := ResizeImage
+			if fitImage {
+				resizeImage = ResizeImageToFit
+			}
+			err := resizeImage(dp.dataFile, dp.requestImageSize, dp.getUsableSpace(), dp.preallocation)
 			if err != nil {
 				return ProcessingPhaseError, errors.Wrap(err, "Resize of image failed")
 			}
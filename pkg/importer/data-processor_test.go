@@ -220,6 +220,21 @@ var _ = Describe("Data Processor", func() {
 		})
 	})
 
+	It("should report the phases to the phase observer", func() {
+		mdp := &MockDataProvider{
+			infoResponse:     ProcessingPhaseTransferDataFile,
+			transferResponse: ProcessingPhaseComplete,
+		}
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.06, false, "")
+		var observed []ProcessingPhase
+		dp.SetPhaseObserver(func(phase ProcessingPhase) {
+			observed = append(observed, phase)
+		})
+		err := dp.ProcessData()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(observed).To(Equal([]ProcessingPhase{ProcessingPhaseInfo, ProcessingPhaseTransferDataFile, ProcessingPhaseComplete}))
+	})
+
 	It("should fail when TransferDataFile fails", func() {
 		mdp := &MockDataProvider{
 			infoResponse:     ProcessingPhaseTransferDataFile,
@@ -572,6 +587,52 @@ var _ = Describe("ValidatePreScratch", func() {
 	})
 })
 
+type MockVirtualSizeDataProvider struct {
+	MockDataProvider
+	virtualSize int64
+}
+
+// GetVirtualSize returns the virtual size of the image.
+func (m *MockVirtualSizeDataProvider) GetVirtualSize() int64 {
+	return m.virtualSize
+}
+
+var _ = Describe("ValidatePreScratch with a known virtual size", func() {
+	var mdp *MockVirtualSizeDataProvider
+
+	BeforeEach(func() {
+		mdp = &MockVirtualSizeDataProvider{
+			MockDataProvider: MockDataProvider{
+				infoResponse:     ProcessingPhaseValidatePreScratch,
+				transferResponse: ProcessingPhaseComplete,
+			},
+		}
+	})
+
+	It("Should transfer to scratch space when the virtual size fits the target", func() {
+		mdp.virtualSize = 1024
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.06, false, "")
+		dp.availableSpace = 2048
+		// Validating the image instead of the virtual size would fail
+		qemuOperations := NewFakeQEMUOperations(nil, nil, fakeInfoOpRetVal{&fakeZeroImageInfo, nil}, image.ErrLargerPVCRequired, nil, nil)
+		replaceQEMUOperations(qemuOperations, func() {
+			Expect(dp.ProcessData()).To(Succeed())
+			Expect(mdp.transferPath).To(Equal("scratchDataDir"))
+		})
+	})
+
+	It("Should return an error when the virtual size is larger than the target", func() {
+		mdp.virtualSize = 4096
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.06, false, "")
+		dp.availableSpace = 2048
+		err := dp.ProcessData()
+		Expect(err).To(HaveOccurred())
+		Expect(errors.As(err, &ValidationSizeError{})).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("virtual image size 4096 is larger than the reported available storage 2048"))
+		Expect(mdp.calledPhases).To(HaveLen(1))
+	})
+})
+
 func replaceQEMUOperations(replacement image.QEMUOperations, f func()) {
 	orig := qemuOperations
 	if replacement != nil {
@@ -594,6 +655,17 @@ func (o *fakeQEMUOperations) Validate(*url.URL, int64) error {
 }
 
 func (o *fakeQEMUOperations) Resize(dest string, size resource.Quantity, preallocate bool) error {
+	if o.resizeQuantity != nil {
+		Expect(o.resizeQuantity.Cmp(size)).To(Equal(0), "sizes don't match %v, %v", o.resizeQuantity.String(), size.String())
+	}
+	// qemu-img refuses to shrink raw images without --shrink
+	if o.ret4.imgInfo != nil && size.Value() < o.ret4.imgInfo.VirtualSize {
+		return errors.New("use the --shrink option to shrink the image")
+	}
+	return o.e3
+}
+
+func (o *fakeQEMUOperations) Shrink(dest string, size resource.Quantity) error {
 	if o.resizeQuantity != nil {
 		Expect(o.resizeQuantity.Cmp(size)).To(Equal(0), "sizes don't match %v, %v", o.resizeQuantity.String(), size.String())
 	}
@@ -630,6 +702,10 @@ func (o *fakeQEMUOperations) PreallocateBlankBlock(dest string, size resource.Qu
 	return nil
 }
 
+func (o *fakeQEMUOperations) ConvertToQcow2(src, dest string) error {
+	return o.e2
+}
+
 func NewQEMUAllErrors() image.QEMUOperations {
 	err := errors.New("qemu should not be called from this test override with replaceQEMUOperations")
 	return NewFakeQEMUOperations(err, err, fakeInfoOpRetVal{nil, err}, err, err, nil)
@@ -643,3 +719,110 @@ func replaceAvailableSpaceBlockFunc(replacement func(string) (int64, error), f f
 	}()
 	f()
 }
+
+type MockStreamConvertDataProvider struct {
+	MockVirtualSizeDataProvider
+	canStreamConvert bool
+	convertErr       error
+	scratchPath      string
+}
+
+// CanStreamConvert returns true if the image can be converted while it is transferred.
+func (m *MockStreamConvertDataProvider) CanStreamConvert() bool {
+	return m.canStreamConvert
+}
+
+// TransferConvertFile is called to convert the data from the source to the passed in file.
+func (m *MockStreamConvertDataProvider) TransferConvertFile(fileName, scratchPath string, preallocation bool) (ProcessingPhase, error) {
+	m.calledPhases = append(m.calledPhases, ProcessingPhaseTransferDataFile)
+	m.transferFile = fileName
+	m.scratchPath = scratchPath
+	if m.convertErr != nil {
+		return ProcessingPhaseError, m.convertErr
+	}
+	return m.transferResponse, nil
+}
+
+var _ = Describe("Stream conversion", func() {
+	var mdp *MockStreamConvertDataProvider
+
+	BeforeEach(func() {
+		mdp = &MockStreamConvertDataProvider{
+			MockVirtualSizeDataProvider: MockVirtualSizeDataProvider{
+				MockDataProvider: MockDataProvider{
+					infoResponse:     ProcessingPhaseTransferScratch,
+					transferResponse: ProcessingPhaseComplete,
+				},
+				virtualSize: 1024,
+			},
+			canStreamConvert: true,
+		}
+	})
+
+	replaceAvailableSpace := func(scratchSize int64, f func()) {
+		orig := getAvailableSpaceFunc
+		getAvailableSpaceFunc = func(string) (int64, error) {
+			return scratchSize, nil
+		}
+		defer func() { getAvailableSpaceFunc = orig }()
+		f()
+	}
+
+	It("Should convert the image while it is transferred to the data file", func() {
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.06, true, "")
+		dp.availableSpace = 2048
+		replaceAvailableSpace(-1, func() {
+			Expect(dp.ProcessData()).To(Succeed())
+		})
+		Expect(mdp.calledPhases).To(Equal([]ProcessingPhase{ProcessingPhaseInfo, ProcessingPhaseTransferDataFile}))
+		Expect(mdp.transferFile).To(Equal("dest"))
+		Expect(mdp.transferPath).To(BeEmpty())
+		Expect(mdp.scratchPath).To(BeEmpty())
+		Expect(dp.PreallocationApplied()).To(BeTrue())
+	})
+
+	It("Should pass the scratch space to keep data stored out of order", func() {
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.06, false, "")
+		dp.availableSpace = 2048
+		replaceAvailableSpace(4096, func() {
+			Expect(dp.ProcessData()).To(Succeed())
+		})
+		Expect(mdp.scratchPath).To(Equal("scratchDataDir"))
+	})
+
+	It("Should transfer to scratch space when the image cannot be converted while it is transferred", func() {
+		mdp.canStreamConvert = false
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.06, false, "")
+		Expect(dp.ProcessData()).To(Succeed())
+		Expect(mdp.calledPhases).To(Equal([]ProcessingPhase{ProcessingPhaseInfo, ProcessingPhaseTransferScratch}))
+		Expect(mdp.transferPath).To(Equal("scratchDataDir"))
+	})
+
+	It("Should return an error when the virtual size is larger than the target", func() {
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.06, false, "")
+		dp.availableSpace = 512
+		err := dp.ProcessData()
+		Expect(errors.As(err, &ValidationSizeError{})).To(BeTrue())
+		Expect(mdp.calledPhases).To(Equal([]ProcessingPhase{ProcessingPhaseInfo}))
+	})
+
+	It("Should require scratch space when the conversion needs it", func() {
+		mdp.convertErr = errors.Wrap(ErrStreamConvert, "out of order")
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.06, false, "")
+		dp.availableSpace = 2048
+		replaceAvailableSpace(-1, func() {
+			Expect(dp.ProcessData()).To(Equal(ErrRequiresScratchSpace))
+		})
+	})
+
+	It("Should fail when the conversion fails with scratch space", func() {
+		mdp.convertErr = errors.Wrap(ErrStreamConvert, "out of order")
+		dp := NewDataProcessor(mdp, "dest", "dataDir", "scratchDataDir", "1G", 0.06, false, "")
+		dp.availableSpace = 2048
+		replaceAvailableSpace(4096, func() {
+			err := dp.ProcessData()
+			Expect(err).To(MatchError(ContainSubstring("Unable to convert source data to target file")))
+			Expect(err).ToNot(Equal(ErrRequiresScratchSpace))
+		})
+	})
+})
@@ -21,6 +21,12 @@ var ErrRequiresScratchSpace = fmt.Errorf(common.ScratchSpaceRequired)
 // ErrInvalidPath indicates that the path is invalid.
 var ErrInvalidPath = fmt.Errorf("invalid transfer path")
 
+// ErrStreamConvert indicates that the image cannot be converted while it is streamed, scratch space is required.
+var ErrStreamConvert = fmt.Errorf("image cannot be converted while it is streamed")
+
+// ErrInvalidArchive indicates that an archive can't be extracted safely or doesn't match its manifest.
+var ErrInvalidArchive = fmt.Errorf("invalid archive")
+
 // ImagePullFailedError indicates that the importer failed to pull an image; This error type wraps the actual error.
 type ImagePullFailedError struct {
 	err error
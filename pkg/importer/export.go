@@ -0,0 +1,365 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/image"
+)
+
+const (
+	// s3MaxParts is the maximum number of parts of a multipart upload
+	s3MaxParts = 10000
+	// containerDiskUser is the user owning the disk of a container disk, the qemu user of the virt-launcher pod
+	containerDiskUser = 107
+	// exportQcow2Name is the name of the qcow2 image converted in the scratch space
+	exportQcow2Name = "export.qcow2"
+)
+
+var (
+	// may be overridden in tests
+	newS3UploaderFunc  = getS3Uploader
+	exportConvertFunc  = image.ConvertToQcow2
+	s3ExportPartSize   = int64(16 * 1024 * 1024)
+	containerDiskMTime = time.Unix(0, 0)
+)
+
+// S3Uploader is the interface to the S3 client used to upload exported images.
+type S3Uploader interface {
+	CreateMultipartUpload(input *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(input *s3.UploadPartInput) (*s3.UploadPartOutput, error)
+	CompleteMultipartUpload(input *s3.CompleteMultipartUploadInput) (*s3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUpload(input *s3.AbortMultipartUploadInput) (*s3.AbortMultipartUploadOutput, error)
+}
+
+// ExportOptions are the options of an export of the image of a PVC.
+type ExportOptions struct {
+	// Source is the path of the image, a file or a block device
+	Source string
+	// ScratchDir is where the image is converted, required for qcow2 exports
+	ScratchDir string
+	// Format is the format of the exported image, raw or qcow2
+	Format cdiv1.ExportJobFormat
+	// Compress compresses the exported image with gzip, only for S3 exports
+	Compress bool
+	// Endpoint is the url of the S3 object or of the container disk
+	Endpoint string
+	// AccessKey and SecKey are the credentials of the target
+	AccessKey string
+	SecKey    string
+	// CertDir is the directory of the CA certificates of the target
+	CertDir string
+	// InsecureTLS skips the verification of the certificate of the registry
+	InsecureTLS bool
+}
+
+func getS3Uploader(endpoint, accessKey, secKey string, certDir string, urlScheme string) (S3Uploader, error) {
+	return newS3Service(endpoint, accessKey, secKey, certDir, urlScheme)
+}
+
+// openExportImage opens the image to export, converting it to qcow2 in the scratch space if needed. It returns the
+// image, its size and a function removing the converted image.
+func openExportImage(opts *ExportOptions) (*os.File, int64, func(), error) {
+	path := opts.Source
+	cleanup := func() {}
+	if opts.Format == cdiv1.ExportJobFormatQcow2 {
+		if opts.ScratchDir == "" {
+			return nil, 0, nil, errors.New("qcow2 exports require scratch space")
+		}
+		path = filepath.Join(opts.ScratchDir, exportQcow2Name)
+		if err := exportConvertFunc(opts.Source, path); err != nil {
+			return nil, 0, nil, err
+		}
+		cleanup = func() {
+			if err := os.Remove(path); err != nil {
+				klog.Warningf("could not remove %s: %v", path, err)
+			}
+		}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		cleanup()
+		return nil, 0, nil, errors.Wrapf(err, "unable to open %q", path)
+	}
+	// Seek works for files and block devices
+	size, err := f.Seek(0, io.SeekEnd)
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		cleanup()
+		return nil, 0, nil, errors.Wrapf(err, "unable to get the size of %q", path)
+	}
+	return f, size, cleanup, nil
+}
+
+// gzipReader returns a reader of the gzip compressed data of r
+func gzipReader(r io.Reader) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		gz := gzip.NewWriter(pw)
+		_, err := io.Copy(gz, r)
+		if err == nil {
+			err = gz.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// s3PartSize returns the size of the parts of the upload of an image, large enough for the image to fit in the
+// maximum number of parts
+func s3PartSize(size int64) int64 {
+	partSize := s3ExportPartSize
+	if minSize := (size + s3MaxParts - 1) / s3MaxParts; minSize > partSize {
+		const mib = 1024 * 1024
+		partSize = (minSize + mib - 1) / mib * mib
+	}
+	return partSize
+}
+
+// ExportToS3 uploads the image to the S3 object of the endpoint with a multipart upload, and returns the sha256
+// checksum of the uploaded object.
+func ExportToS3(opts *ExportOptions) (string, error) {
+	ep, err := ParseEndpoint(opts.Endpoint)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to parse endpoint %q", opts.Endpoint)
+	}
+	bucket, object := extractBucketAndObject(strings.Trim(ep.Path, "/"))
+	if bucket == "" || object == "" {
+		return "", errors.Errorf("endpoint %q has no bucket and object", opts.Endpoint)
+	}
+	svc, err := newS3UploaderFunc(ep.Host, opts.AccessKey, opts.SecKey, opts.CertDir, ep.Scheme)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not build s3 client for %q", ep.Host)
+	}
+
+	f, size, cleanup, err := openExportImage(opts)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	defer f.Close()
+
+	var reader io.Reader = f
+	if opts.Compress {
+		gz := gzipReader(f)
+		defer gz.Close()
+		reader = gz
+	}
+	hash := sha256.New()
+	reader = io.TeeReader(reader, hash)
+
+	upload, err := svc.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(object),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "could not create upload of s3 object \"%s/%s\"", bucket, object)
+	}
+	parts, err := uploadS3Parts(svc, upload, reader, s3PartSize(size))
+	if err == nil {
+		_, err = svc.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+			Bucket:          upload.Bucket,
+			Key:             upload.Key,
+			UploadId:        upload.UploadId,
+			MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+		})
+	}
+	if err != nil {
+		if _, abortErr := svc.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+			Bucket:   upload.Bucket,
+			Key:      upload.Key,
+			UploadId: upload.UploadId,
+		}); abortErr != nil {
+			klog.Warningf("could not abort upload of s3 object \"%s/%s\": %v", bucket, object, abortErr)
+		}
+		return "", errors.Wrapf(err, "could not upload s3 object \"%s/%s\"", bucket, object)
+	}
+	checksum := "sha256:" + hex.EncodeToString(hash.Sum(nil))
+	klog.Infof("Exported %s to s3 object \"%s/%s\", %s", opts.Source, bucket, object, checksum)
+	return checksum, nil
+}
+
+func uploadS3Parts(svc S3Uploader, upload *s3.CreateMultipartUploadOutput, reader io.Reader, partSize int64) ([]*s3.CompletedPart, error) {
+	var parts []*s3.CompletedPart
+	buf := make([]byte, partSize)
+	for partNumber := int64(1); ; partNumber++ {
+		n, err := io.ReadFull(reader, buf)
+		if err == io.EOF && partNumber > 1 {
+			return parts, nil
+		}
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, errors.Wrap(err, "unable to read the image")
+		}
+		if partNumber > s3MaxParts {
+			return nil, errors.Errorf("the image does not fit in %d parts", s3MaxParts)
+		}
+		output, uploadErr := svc.UploadPart(&s3.UploadPartInput{
+			Bucket:     upload.Bucket,
+			Key:        upload.Key,
+			UploadId:   upload.UploadId,
+			PartNumber: aws.Int64(partNumber),
+			Body:       bytes.NewReader(buf[:n]),
+		})
+		if uploadErr != nil {
+			return nil, errors.Wrapf(uploadErr, "could not upload part %d", partNumber)
+		}
+		parts = append(parts, &s3.CompletedPart{ETag: output.ETag, PartNumber: aws.Int64(partNumber)})
+		klog.V(1).Infof("Uploaded part %d of %d bytes", partNumber, n)
+		if err != nil {
+			// The last part was shorter than the part size
+			return parts, nil
+		}
+	}
+}
+
+// containerDiskLayer returns a reader of the gzip compressed tar layer of a container disk with the image, and a
+// digester of the uncompressed layer which is complete once the reader was read
+func containerDiskLayer(f io.Reader, size int64) (io.ReadCloser, digest.Digester) {
+	diffID := digest.Canonical.Digester()
+	pr, pw := io.Pipe()
+	go func() {
+		gz := gzip.NewWriter(pw)
+		tw := tar.NewWriter(io.MultiWriter(gz, diffID.Hash()))
+		err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeDir,
+			Name:     containerDiskImageDir + "/",
+			Mode:     0555,
+			Uid:      containerDiskUser,
+			Gid:      containerDiskUser,
+			ModTime:  containerDiskMTime,
+		})
+		if err == nil {
+			err = tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeReg,
+				Name:     containerDiskImageDir + "/" + common.DiskImageName,
+				Mode:     0440,
+				Size:     size,
+				Uid:      containerDiskUser,
+				Gid:      containerDiskUser,
+				ModTime:  containerDiskMTime,
+			})
+		}
+		if err == nil {
+			_, err = io.Copy(tw, f)
+		}
+		if err == nil {
+			err = tw.Close()
+		}
+		if err == nil {
+			err = gz.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr, diffID
+}
+
+// ExportToRegistry pushes the image as a container disk to the registry of the endpoint, and returns the digest of
+// the manifest of the container disk.
+func ExportToRegistry(opts *ExportOptions) (string, error) {
+	if !strings.HasPrefix(opts.Endpoint, cdiv1.RegistrySchemeDocker+"://") {
+		return "", errors.Errorf("invalid container disk url %q, only the %s scheme is supported", opts.Endpoint, cdiv1.RegistrySchemeDocker)
+	}
+	ref, err := parseImageName(opts.Endpoint)
+	if err != nil {
+		return "", errors.Wrap(err, "Could not parse image")
+	}
+	ctx, cancel := commandTimeoutContext()
+	defer cancel()
+	sys := buildSourceContext(opts.AccessKey, opts.SecKey, "", opts.CertDir, opts.InsecureTLS)
+	dest, err := ref.NewImageDestination(ctx, sys)
+	if err != nil {
+		return "", errors.Wrap(err, "Could not create image destination")
+	}
+	defer func() {
+		if err := dest.Close(); err != nil {
+			klog.Warningf("Could not close image destination: %v", err)
+		}
+	}()
+
+	f, size, cleanup, err := openExportImage(opts)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	defer f.Close()
+
+	layer, diffID := containerDiskLayer(f, size)
+	defer layer.Close()
+	layerInfo, err := dest.PutBlob(ctx, layer, types.BlobInfo{Size: -1, MediaType: imgspecv1.MediaTypeImageLayerGzip}, none.NoCache, false)
+	if err != nil {
+		return "", errors.Wrap(err, "could not push the layer")
+	}
+
+	config, err := json.Marshal(imgspecv1.Image{
+		Platform: imgspecv1.Platform{Architecture: runtime.GOARCH, OS: "linux"},
+		RootFS:   imgspecv1.RootFS{Type: "layers", DiffIDs: []digest.Digest{diffID.Digest()}},
+	})
+	if err != nil {
+		return "", err
+	}
+	configInfo, err := dest.PutBlob(ctx, bytes.NewReader(config), types.BlobInfo{Digest: digest.FromBytes(config), Size: int64(len(config)), MediaType: imgspecv1.MediaTypeImageConfig}, none.NoCache, true)
+	if err != nil {
+		return "", errors.Wrap(err, "could not push the config")
+	}
+
+	manifest, err := json.Marshal(imgspecv1.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: imgspecv1.MediaTypeImageManifest,
+		Config:    imgspecv1.Descriptor{MediaType: imgspecv1.MediaTypeImageConfig, Digest: configInfo.Digest, Size: configInfo.Size},
+		Layers:    []imgspecv1.Descriptor{{MediaType: imgspecv1.MediaTypeImageLayerGzip, Digest: layerInfo.Digest, Size: layerInfo.Size}},
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := dest.PutManifest(ctx, manifest, nil); err != nil {
+		return "", errors.Wrap(err, "could not push the manifest")
+	}
+	if err := dest.Commit(ctx, nil); err != nil {
+		return "", errors.Wrap(err, "could not commit the image")
+	}
+	manifestDigest := digest.FromBytes(manifest).String()
+	klog.Infof("Exported %s to %s, %s", opts.Source, opts.Endpoint, manifestDigest)
+	return manifestDigest, nil
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+// fakeS3Uploader stores the parts of multipart uploads
+type fakeS3Uploader struct {
+	bucket, key string
+	parts       [][]byte
+	completed   bool
+	aborted     bool
+	failPart    int64
+}
+
+func (u *fakeS3Uploader) CreateMultipartUpload(input *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error) {
+	u.bucket, u.key = aws.StringValue(input.Bucket), aws.StringValue(input.Key)
+	return &s3.CreateMultipartUploadOutput{Bucket: input.Bucket, Key: input.Key, UploadId: aws.String("upload")}, nil
+}
+
+func (u *fakeS3Uploader) UploadPart(input *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
+	partNumber := aws.Int64Value(input.PartNumber)
+	if partNumber == u.failPart {
+		return nil, errors.New("upload failed")
+	}
+	Expect(partNumber).To(BeEquivalentTo(len(u.parts) + 1))
+	data, err := io.ReadAll(input.Body)
+	Expect(err).ToNot(HaveOccurred())
+	u.parts = append(u.parts, data)
+	return &s3.UploadPartOutput{ETag: aws.String(fmt.Sprintf("etag%d", partNumber))}, nil
+}
+
+func (u *fakeS3Uploader) CompleteMultipartUpload(input *s3.CompleteMultipartUploadInput) (*s3.CompleteMultipartUploadOutput, error) {
+	Expect(input.MultipartUpload.Parts).To(HaveLen(len(u.parts)))
+	for i, part := range input.MultipartUpload.Parts {
+		Expect(aws.Int64Value(part.PartNumber)).To(BeEquivalentTo(i + 1))
+		Expect(aws.StringValue(part.ETag)).To(Equal(fmt.Sprintf("etag%d", i+1)))
+	}
+	u.completed = true
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+func (u *fakeS3Uploader) AbortMultipartUpload(input *s3.AbortMultipartUploadInput) (*s3.AbortMultipartUploadOutput, error) {
+	u.aborted = true
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func (u *fakeS3Uploader) object() []byte {
+	return bytes.Join(u.parts, nil)
+}
+
+func sha256Checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+var _ = Describe("Export", func() {
+	var (
+		tmpDir string
+		source string
+		data   []byte
+	)
+
+	BeforeEach(func() {
+		tmpDir = GinkgoT().TempDir()
+		source = filepath.Join(tmpDir, "disk.img")
+		// Random data, so the compressed layer is large enough for the format readers to detect the format
+		data = make([]byte, 16*1024)
+		_, err := rand.Read(data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.WriteFile(source, data, 0600)).To(Succeed())
+	})
+
+	Context("to S3", func() {
+		var uploader *fakeS3Uploader
+
+		BeforeEach(func() {
+			uploader = &fakeS3Uploader{}
+			origUploaderFunc, origPartSize := newS3UploaderFunc, s3ExportPartSize
+			DeferCleanup(func() {
+				newS3UploaderFunc, s3ExportPartSize = origUploaderFunc, origPartSize
+			})
+			newS3UploaderFunc = func(string, string, string, string, string) (S3Uploader, error) {
+				return uploader, nil
+			}
+			s3ExportPartSize = 4096
+		})
+
+		It("should upload the raw image in parts", func() {
+			checksum, err := ExportToS3(&ExportOptions{Source: source, Endpoint: "http://minio:9000/bucket/disks/disk.img"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(uploader.bucket).To(Equal("bucket"))
+			Expect(uploader.key).To(Equal("disks/disk.img"))
+			Expect(uploader.parts).To(HaveLen(4))
+			Expect(uploader.completed).To(BeTrue())
+			Expect(uploader.object()).To(Equal(data))
+			Expect(checksum).To(Equal(sha256Checksum(data)))
+		})
+
+		It("should upload a single part of an image smaller than the part size", func() {
+			s3ExportPartSize = int64(len(data))
+			_, err := ExportToS3(&ExportOptions{Source: source, Endpoint: "http://minio:9000/bucket/disk.img"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(uploader.parts).To(HaveLen(1))
+			Expect(uploader.object()).To(Equal(data))
+		})
+
+		It("should upload the compressed image", func() {
+			checksum, err := ExportToS3(&ExportOptions{Source: source, Endpoint: "http://minio:9000/bucket/disk.img.gz", Compress: true})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checksum).To(Equal(sha256Checksum(uploader.object())))
+			gz, err := gzip.NewReader(bytes.NewReader(uploader.object()))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(io.ReadAll(gz)).To(Equal(data))
+		})
+
+		It("should upload the qcow2 image converted in the scratch space", func() {
+			scratchDir := GinkgoT().TempDir()
+			origConvertFunc := exportConvertFunc
+			defer func() { exportConvertFunc = origConvertFunc }()
+			exportConvertFunc = func(src, dest string) error {
+				Expect(src).To(Equal(source))
+				Expect(filepath.Dir(dest)).To(Equal(scratchDir))
+				return os.WriteFile(dest, []byte("qcow2 image"), 0600)
+			}
+
+			_, err := ExportToS3(&ExportOptions{Source: source, ScratchDir: scratchDir, Format: cdiv1.ExportJobFormatQcow2, Endpoint: "http://minio:9000/bucket/disk.qcow2"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(uploader.object())).To(Equal("qcow2 image"))
+			Expect(os.ReadDir(scratchDir)).To(BeEmpty())
+		})
+
+		It("should not convert to qcow2 without scratch space", func() {
+			_, err := ExportToS3(&ExportOptions{Source: source, Format: cdiv1.ExportJobFormatQcow2, Endpoint: "http://minio:9000/bucket/disk.qcow2"})
+			Expect(err).To(MatchError(ContainSubstring("require scratch space")))
+		})
+
+		It("should abort the upload when a part fails", func() {
+			uploader.failPart = 2
+			_, err := ExportToS3(&ExportOptions{Source: source, Endpoint: "http://minio:9000/bucket/disk.img"})
+			Expect(err).To(MatchError(ContainSubstring("could not upload part 2")))
+			Expect(uploader.aborted).To(BeTrue())
+			Expect(uploader.completed).To(BeFalse())
+		})
+
+		It("should fail without an object in the endpoint", func() {
+			_, err := ExportToS3(&ExportOptions{Source: source, Endpoint: "http://minio:9000/bucket"})
+			Expect(err).To(MatchError(ContainSubstring("has no bucket and object")))
+		})
+	})
+
+	DescribeTable("s3PartSize", func(size, expected int64) {
+		Expect(s3PartSize(size)).To(Equal(expected))
+	},
+		Entry("small image", int64(1024), s3ExportPartSize),
+		Entry("image fitting in the maximum number of parts", s3MaxParts*s3ExportPartSize, s3ExportPartSize),
+		Entry("larger image", s3MaxParts*s3ExportPartSize+1, s3ExportPartSize+1024*1024),
+	)
+
+	Context("to a registry", func() {
+		var (
+			registry *fakeRegistry
+			server   *httptest.Server
+			endpoint string
+		)
+
+		BeforeEach(func() {
+			registry = newFakeRegistry()
+			server = httptest.NewTLSServer(registry)
+			DeferCleanup(server.Close)
+			DeferCleanup(func() {
+				Expect(registry.pushErrors()).To(BeEmpty())
+			})
+			endpoint = "docker://" + strings.TrimPrefix(server.URL, "https://") + "/disks/exported:latest"
+		})
+
+		It("should push a container disk with the image", func() {
+			manifestDigest, err := ExportToRegistry(&ExportOptions{Source: source, Endpoint: endpoint, InsecureTLS: true})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(registry.manifests).To(HaveKey("latest"))
+			Expect(manifestDigest).To(Equal(digest.FromBytes(registry.manifests["latest"].data).String()))
+
+			manifest := imgspecv1.Manifest{}
+			Expect(json.Unmarshal(registry.manifests["latest"].data, &manifest)).To(Succeed())
+			Expect(manifest.Layers).To(HaveLen(1))
+			gz, err := gzip.NewReader(bytes.NewReader(registry.blobs[manifest.Layers[0].Digest]))
+			Expect(err).ToNot(HaveOccurred())
+			tr := tar.NewReader(gz)
+			for _, name := range []string{"disk/", "disk/" + common.DiskImageName} {
+				hdr, err := tr.Next()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(hdr.Name).To(Equal(name))
+				Expect(hdr.Uid).To(Equal(containerDiskUser))
+			}
+
+			config := imgspecv1.Image{}
+			Expect(json.Unmarshal(registry.blobs[manifest.Config.Digest], &config)).To(Succeed())
+			Expect(config.RootFS.DiffIDs).To(HaveLen(1))
+
+			scratchDir := GinkgoT().TempDir()
+			_, err = CopyRegistryImage(endpoint, scratchDir, containerDiskImageDir, "", "", "", "", true, false, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(os.ReadFile(filepath.Join(scratchDir, containerDiskImageDir, common.DiskImageName))).To(Equal(data))
+		})
+
+		It("should only push to docker urls", func() {
+			_, err := ExportToRegistry(&ExportOptions{Source: source, Endpoint: "oci-archive:/tmp/disk.tar"})
+			Expect(err).To(MatchError(ContainSubstring("only the docker scheme is supported")))
+		})
+
+		It("should fail if the image does not exist", func() {
+			_, err := ExportToRegistry(&ExportOptions{Source: filepath.Join(tmpDir, "missing"), Endpoint: endpoint, InsecureTLS: true})
+			Expect(err).To(HaveOccurred())
+			Expect(registry.manifests).To(BeEmpty())
+		})
+	})
+})
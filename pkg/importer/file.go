@@ -14,6 +14,8 @@ import (
 	"golang.org/x/sys/unix"
 
 	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/util/sparse"
 )
 
 var (
@@ -209,6 +211,162 @@ func StreamDataToFile(r io.Reader, fileName string, preallocate bool) (int64, in
 	return bytesRead, bytesWritten, err
 }
 
+// StreamRangeToFile writes the data of r to the existing file or block device at the offset, it is used to write the
+// ranges of an image concurrently. Ranges of zeroes are punched as holes, unless preallocate is set.
+func StreamRangeToFile(r io.Reader, fileName string, offset int64, preallocate bool) (int64, int64, error) {
+	var bytesRead, bytesWritten int64
+	// The file is not opened exclusively, every range is written through its own file descriptor
+	outFile, err := os.OpenFile(fileName, os.O_WRONLY, 0)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "could not open file %q", fileName)
+	}
+	defer outFile.Close()
+
+	if _, err := outFile.Seek(offset, io.SeekStart); err != nil {
+		return 0, 0, errors.Wrapf(err, "could not seek to offset %d of %q", offset, fileName)
+	}
+	if !preallocate {
+		// The range may be written again after a failure, so zeroes must replace the previous data
+		bytesRead, bytesWritten, err = copyWithSparseCheckAt(outFile, r, offset, PunchHole)
+	} else {
+		bytesRead, err = io.Copy(outFile, r)
+		bytesWritten = bytesRead
+	}
+	klog.V(3).Infof("Read %d bytes, wrote %d bytes to %s at offset %d", bytesRead, bytesWritten, fileName, offset)
+	if err != nil {
+		if IsNoCapacityError(err) {
+			return bytesRead, bytesWritten, fmt.Errorf("unable to write to file: %w", err)
+		}
+		return bytesRead, bytesWritten, errors.Wrapf(err, "unable to write to %q at offset %d", fileName, offset)
+	}
+
+	return bytesRead, bytesWritten, outFile.Sync()
+}
+
+// StreamSparseToFile writes the image of the sparse stream r to fileName, zeroing the ranges which are not in the stream
+func StreamSparseToFile(r io.Reader, fileName string, preallocate bool) (int64, int64, error) {
+	var bytesRead, bytesWritten int64
+	outFile, err := OpenFileOrBlockDevice(fileName)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer outFile.Close()
+
+	zeroWriter := zeroWriterFunc(AppendZeroWithWrite)
+	if !preallocate {
+		isDevice, err := IsDevice(fileName)
+		if err != nil {
+			return 0, 0, err
+		}
+		zeroWriter = appendZeroWithTruncateFunc
+		if isDevice {
+			zeroWriter = PunchHole
+		}
+	}
+
+	bytesRead, bytesWritten, err = copySparseStream(outFile, r, zeroWriter)
+	klog.Infof("Read %d bytes, wrote %d bytes to %s", bytesRead, bytesWritten, outFile.Name())
+	if err != nil {
+		klog.Errorf("Unable to write file from sparse stream: %v\n", err)
+		os.Remove(outFile.Name())
+		if IsNoCapacityError(err) {
+			return bytesRead, bytesWritten, fmt.Errorf("unable to write to file: %w", err)
+		}
+		return bytesRead, bytesWritten, NewImagePullFailedError(err)
+	}
+
+	return bytesRead, bytesWritten, outFile.Sync()
+}
+
+// StreamDeltaToFile writes the extents of the sparse stream r to the existing file or block device fileName, the ranges
+// which are not in the stream are left unchanged
+func StreamDeltaToFile(r io.Reader, fileName string, _ bool) (int64, int64, error) {
+	outFile, err := os.OpenFile(fileName, os.O_WRONLY, 0)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "could not open file %q", fileName)
+	}
+	defer outFile.Close()
+
+	bytesWritten, err := copyDeltaStream(outFile, r)
+	klog.Infof("Wrote %d bytes to %s", bytesWritten, outFile.Name())
+	if err != nil {
+		klog.Errorf("Unable to write file from delta stream: %v\n", err)
+		if IsNoCapacityError(err) {
+			return bytesWritten, bytesWritten, fmt.Errorf("unable to write to file: %w", err)
+		}
+		return bytesWritten, bytesWritten, NewImagePullFailedError(err)
+	}
+
+	return bytesWritten, bytesWritten, outFile.Sync()
+}
+
+// copyDeltaStream writes the extents of the sparse stream src at their offset in dst
+func copyDeltaStream(dst *os.File, src io.Reader) (int64, error) {
+	var bytesWritten int64
+	sr, err := sparse.NewReader(src)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		extentOffset, _, err := sr.Next()
+		if errors.Is(err, io.EOF) {
+			return bytesWritten, nil
+		}
+		if err != nil {
+			return bytesWritten, err
+		}
+		if _, err := dst.Seek(extentOffset, io.SeekStart); err != nil {
+			return bytesWritten, errors.Wrapf(err, "unable to seek to %d", extentOffset)
+		}
+		n, err := io.Copy(dst, sr)
+		bytesWritten += n
+		if err != nil {
+			return bytesWritten, err
+		}
+	}
+}
+
+// copySparseStream writes the extents of the sparse stream src to dst, which must be positioned at its start, zeroing
+// the ranges between them with zeroWriter
+func copySparseStream(dst *os.File, src io.Reader, zeroWriter zeroWriterFunc) (int64, int64, error) {
+	var bytesRead, bytesWritten, offset int64
+	sr, err := sparse.NewReader(src)
+	if err != nil {
+		return 0, 0, err
+	}
+	zeroWriterFunc := zeroWriterWithFallback(zeroWriter)
+	zeroRange := func(end int64) error {
+		if end <= offset {
+			return nil
+		}
+		zbw, err := zeroWriterFunc(dst, offset, end-offset)
+		bytesWritten += zbw
+		offset = end
+		return err
+	}
+	for {
+		extentOffset, _, err := sr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return bytesRead, bytesWritten, err
+		}
+		if err := zeroRange(extentOffset); err != nil {
+			return bytesRead, bytesWritten, err
+		}
+		// The blocks of zeros were already skipped by the source
+		n, err := io.Copy(dst, sr)
+		bytesRead += n
+		bytesWritten += n
+		offset = extentOffset + n
+		if err != nil {
+			return bytesRead, bytesWritten, err
+		}
+	}
+	return bytesRead, bytesWritten, zeroRange(sr.Size())
+}
+
 type zeroWriterFunc func(*os.File, int64, int64) error
 
 func zeroWriterWithFallback(zwf zeroWriterFunc) func(dst *os.File, start, length int64) (int64, error) {
@@ -227,7 +385,12 @@ func zeroWriterWithFallback(zwf zeroWriterFunc) func(dst *os.File, start, length
 }
 
 func copyWithSparseCheck(dst *os.File, src io.Reader, zeroWriter zeroWriterFunc) (int64, int64, error) {
-	klog.Infof("copyWithSparseCheck to %s", dst.Name())
+	return copyWithSparseCheckAt(dst, src, 0, zeroWriter)
+}
+
+// copyWithSparseCheckAt copies src to dst, which must be positioned at offset
+func copyWithSparseCheckAt(dst *os.File, src io.Reader, offset int64, zeroWriter zeroWriterFunc) (int64, int64, error) {
+	klog.Infof("copyWithSparseCheck to %s at offset %d", dst.Name(), offset)
 	const buffSize = 32 * 1024
 	var bytesRead, bytesWritten int64
 	zeroBuf := make([]byte, buffSize)
@@ -246,7 +409,7 @@ func copyWithSparseCheck(dst *os.File, src io.Reader, zeroWriter zeroWriterFunc)
 			} else {
 				if bytesRead > writeOffset {
 					// func should seek to bytesRead before returning
-					zbw, ew = zeroWriterFunc(dst, writeOffset, bytesRead-writeOffset)
+					zbw, ew = zeroWriterFunc(dst, offset+writeOffset, bytesRead-writeOffset)
 					if ew != nil {
 						klog.Errorf("Error writing zeroes to destination file: %v", ew)
 						return bytesRead, bytesWritten, ew
@@ -282,7 +445,7 @@ func copyWithSparseCheck(dst *os.File, src io.Reader, zeroWriter zeroWriterFunc)
 		}
 	}
 	if bytesRead > writeOffset {
-		zbw, err := zeroWriterFunc(dst, writeOffset, bytesRead-writeOffset)
+		zbw, err := zeroWriterFunc(dst, offset+writeOffset, bytesRead-writeOffset)
 		if err != nil {
 			klog.Errorf("Error writing zeroes to destination file: %v", err)
 			return bytesRead, bytesWritten, err
@@ -10,6 +10,8 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/util/sparse"
 )
 
 var _ = Describe("All tests", func() {
@@ -227,4 +229,149 @@ var _ = Describe("All tests", func() {
 			})
 		})
 	})
+
+	Describe("StreamRangeToFile tests", func() {
+		const rangeSize = 64 * 1024
+
+		var destName string
+
+		BeforeEach(func() {
+			destName = filepath.Join(GinkgoT().TempDir(), "disk.img")
+			// Previous data in the file has to be replaced, zeroes included
+			Expect(os.WriteFile(destName, bytes.Repeat([]byte{0xFF}, rangeSize*4), 0600)).To(Succeed())
+		})
+
+		DescribeTable("Should write ranges concurrently", func(preallocate bool) {
+			var expected []byte
+			expected = append(expected, bytes.Repeat([]byte{0x55}, rangeSize)...)
+			expected = append(expected, make([]byte, rangeSize)...)
+			expected = append(expected, bytes.Repeat([]byte{0xAA}, rangeSize/2)...)
+			expected = append(expected, make([]byte, rangeSize/2)...)
+			expected = append(expected, make([]byte, rangeSize/2)...)
+			expected = append(expected, bytes.Repeat([]byte{0x11}, rangeSize/2)...)
+
+			errs := make(chan error, 4)
+			for i := 3; i >= 0; i-- {
+				go func(offset int64) {
+					defer GinkgoRecover()
+					bytesRead, _, err := StreamRangeToFile(bytes.NewReader(expected[offset:offset+rangeSize]), destName, offset, preallocate)
+					if err == nil {
+						Expect(bytesRead).To(Equal(int64(rangeSize)))
+					}
+					errs <- err
+				}(int64(i * rangeSize))
+			}
+			for i := 0; i < 4; i++ {
+				Expect(<-errs).ToNot(HaveOccurred())
+			}
+
+			written, err := os.ReadFile(destName)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bytes.Equal(written, expected)).To(BeTrue())
+		},
+			Entry("sparse", false),
+			Entry("preallocated", true),
+		)
+
+		It("Should fail if the file does not exist", func() {
+			_, _, err := StreamRangeToFile(bytes.NewReader([]byte{1}), destName+".missing", 0, false)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("could not open file"))
+		})
+	})
+
+	Describe("StreamSparseToFile tests", func() {
+		const extentSize = 64 * 1024
+
+		var destName string
+
+		BeforeEach(func() {
+			destName = filepath.Join(GinkgoT().TempDir(), "disk.img")
+		})
+
+		newSparseStream := func(size int64, extents map[int64]byte) ([]byte, []byte) {
+			image := make([]byte, size)
+			buf := &bytes.Buffer{}
+			w, err := sparse.NewWriter(buf, size)
+			Expect(err).ToNot(HaveOccurred())
+			for offset := int64(0); offset < size; offset += extentSize {
+				if b, ok := extents[offset]; ok {
+					data := bytes.Repeat([]byte{b}, extentSize)
+					copy(image[offset:], data)
+					Expect(w.WriteExtent(offset, data)).To(Succeed())
+				}
+			}
+			Expect(w.Close()).To(Succeed())
+			return buf.Bytes(), image
+		}
+
+		DescribeTable("Should write the image of the sparse stream", func(preallocate bool, extents map[int64]byte) {
+			const size = 8 * extentSize
+			stream, image := newSparseStream(size, extents)
+			bytesRead, bytesWritten, err := StreamSparseToFile(bytes.NewReader(stream), destName, preallocate)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bytesRead).To(Equal(int64(len(extents) * extentSize)))
+			Expect(bytesWritten).To(Equal(bytesRead))
+
+			written, err := os.ReadFile(destName)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bytes.Equal(written, image)).To(BeTrue())
+		},
+			Entry("without preallocation", false, map[int64]byte{extentSize: 1, 2 * extentSize: 2, 5 * extentSize: 3}),
+			Entry("without preallocation and leading data", false, map[int64]byte{0: 1, 3 * extentSize: 2}),
+			Entry("without data", false, map[int64]byte{}),
+			Entry("with preallocation", true, map[int64]byte{extentSize: 1, 7 * extentSize: 2}),
+		)
+
+		It("Should remove the file if the stream is invalid", func() {
+			stream, _ := newSparseStream(4*extentSize, map[int64]byte{0: 1})
+			_, _, err := StreamSparseToFile(bytes.NewReader(stream[:len(stream)-1]), destName, false)
+			Expect(err).To(HaveOccurred())
+			_, err = os.Stat(destName)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+	})
+
+	Describe("StreamDeltaToFile tests", func() {
+		const extentSize = 64 * 1024
+
+		var destName string
+
+		BeforeEach(func() {
+			destName = filepath.Join(GinkgoT().TempDir(), "disk.img")
+		})
+
+		It("Should only write the extents of the delta stream", func() {
+			const size = 4 * extentSize
+			image := bytes.Repeat([]byte{7}, size)
+			Expect(os.WriteFile(destName, image, 0600)).To(Succeed())
+
+			buf := &bytes.Buffer{}
+			w, err := sparse.NewWriter(buf, size)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(w.WriteExtent(extentSize, make([]byte, extentSize))).To(Succeed())
+			Expect(w.WriteExtent(3*extentSize, bytes.Repeat([]byte{9}, extentSize))).To(Succeed())
+			Expect(w.Close()).To(Succeed())
+			copy(image[extentSize:], make([]byte, extentSize))
+			copy(image[3*extentSize:], bytes.Repeat([]byte{9}, extentSize))
+
+			bytesRead, bytesWritten, err := StreamDeltaToFile(buf, destName, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bytesRead).To(Equal(int64(2 * extentSize)))
+			Expect(bytesWritten).To(Equal(bytesRead))
+
+			written, err := os.ReadFile(destName)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bytes.Equal(written, image)).To(BeTrue())
+		})
+
+		It("Should fail if the target does not exist", func() {
+			buf := &bytes.Buffer{}
+			w, err := sparse.NewWriter(buf, extentSize)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(w.Close()).To(Succeed())
+			_, _, err = StreamDeltaToFile(buf, destName, false)
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
@@ -0,0 +1,477 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hirochachacha/go-smb2"
+	"github.com/pkg/errors"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const (
+	sftpDefaultPort = "22"
+	smbDefaultPort  = "445"
+	// smbGuestUser is the user name used to access a share as guest
+	smbGuestUser = "guest"
+	// smbReadBufferSize is the size of the buffer filled by a single read from an SMB share
+	smbReadBufferSize = 4 * 1024 * 1024
+	// fileShareDialTimeout bounds the time to connect to a file server
+	fileShareDialTimeout = 30 * time.Second
+)
+
+// FileShareDataSource is the struct containing the information needed to import a file from an SFTP server, an SMB
+// share or an NFS export.
+// Sequence of phases:
+// 1. Info -> Transfer
+// 2. Transfer -> Convert
+type FileShareDataSource struct {
+	// Reader of the file, closing it closes the connection to the file server
+	fileReader io.ReadCloser
+	// Size of the file, 0 if unknown
+	size uint64
+	// stack of readers
+	readers *FormatReaders
+	// The image file in scratch space.
+	url *url.URL
+	// checksumValidator validates the checksum of downloaded data
+	checksumValidator *ChecksumValidator
+}
+
+// NewSFTPDataSource creates a new instance of the FileShareDataSource reading a file from an SFTP server. The user name,
+// and the password or private key, are read from the files of credentialDir. The server must present one of the host
+// keys of the known_hosts file in hostKeyDir.
+func NewSFTPDataSource(endpoint, credentialDir, hostKeyDir, checksum string) (*FileShareDataSource, error) {
+	ep, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse endpoint %q", endpoint)
+	}
+	config, err := sftpClientConfig(credentialDir, hostKeyDir)
+	if err != nil {
+		return nil, err
+	}
+	fileReader, size, err := openSFTPFile(ep, config)
+	if err != nil {
+		return nil, err
+	}
+	return newFileShareDataSource(fileReader, size, checksum)
+}
+
+// NewSMBDataSource creates a new instance of the FileShareDataSource reading a file from an SMB share. The share is
+// accessed as guest if no user name is passed in.
+func NewSMBDataSource(endpoint, accessKey, secKey, checksum string) (*FileShareDataSource, error) {
+	ep, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse endpoint %q", endpoint)
+	}
+	fileReader, size, err := openSMBFile(ep, accessKey, secKey)
+	if err != nil {
+		return nil, err
+	}
+	return newFileShareDataSource(fileReader, size, checksum)
+}
+
+// NewNFSDataSource creates a new instance of the FileShareDataSource reading a file from an NFS export. The volume
+// of the export is mounted at mountDir and filePath is relative to it.
+func NewNFSDataSource(filePath, mountDir, checksum string) (*FileShareDataSource, error) {
+	if !filepath.IsLocal(filePath) {
+		return nil, errors.Errorf("invalid NFS file path %q", filePath)
+	}
+	klog.Infof("Reading %s from the NFS export", filePath)
+	// OpenInRoot does not follow symlinks out of the volume
+	f, err := os.OpenInRoot(mountDir, filePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open %s", filePath)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, errors.Wrapf(err, "unable to stat %s", filePath)
+	}
+	return newFileShareDataSource(&fileShareReader{Reader: f, closers: []io.Closer{f}}, uint64(info.Size()), checksum)
+}
+
+func newFileShareDataSource(fileReader io.ReadCloser, size uint64, checksum string) (*FileShareDataSource, error) {
+	checksumValidator, err := NewChecksumValidator(checksum)
+	if err != nil {
+		fileReader.Close()
+		return nil, errors.Wrap(err, "invalid checksum")
+	}
+	if checksumValidator != nil {
+		klog.Infof("Checksum validation enabled: %s", checksum)
+	}
+	return &FileShareDataSource{
+		fileReader:        fileReader,
+		size:              size,
+		checksumValidator: checksumValidator,
+	}, nil
+}
+
+// Info is called to get initial information about the data.
+func (fd *FileShareDataSource) Info() (ProcessingPhase, error) {
+	var err error
+	fd.readers, err = NewFormatReaders(fd.fileReader, fd.size, fd.checksumValidator)
+	if err != nil {
+		klog.Errorf("Error creating readers: %v", err)
+		return ProcessingPhaseError, err
+	}
+	if !fd.readers.Convert {
+		// Downloading a raw file, we can write that directly to the target.
+		return ProcessingPhaseTransferDataFile, nil
+	}
+
+	return ProcessingPhaseTransferScratch, nil
+}
+
+// Transfer is called to transfer the data from the source to a temporary location.
+func (fd *FileShareDataSource) Transfer(path string, preallocation bool) (ProcessingPhase, error) {
+	file := filepath.Join(path, tempFile)
+	if err := CleanAll(file); err != nil {
+		return ProcessingPhaseError, err
+	}
+
+	size, _ := GetAvailableSpace(path)
+	if size <= int64(0) {
+		//Path provided is invalid.
+		return ProcessingPhaseError, ErrInvalidPath
+	}
+
+	fd.readers.StartProgressUpdate()
+	_, _, err := StreamDataToFile(fd.readers.TopReader(), file, preallocation)
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	// Verify checksum if specified
+	if err := fd.readers.ValidateChecksum(); err != nil {
+		return ProcessingPhaseError, fmt.Errorf("checksum validation failed: %w", err)
+	}
+	if fd.readers.ArchiveZip {
+		if err := extractZipImage(file, preallocation); err != nil {
+			return ProcessingPhaseError, err
+		}
+	}
+	// If streaming succeeded, then parsing the file into URL will also succeed, no need to check error status
+	fd.url, _ = url.Parse(file)
+	return ProcessingPhaseConvert, nil
+}
+
+// TransferFile is called to transfer the data from the source to the passed in file.
+func (fd *FileShareDataSource) TransferFile(fileName string, preallocation bool) (ProcessingPhase, error) {
+	if err := CleanAll(fileName); err != nil {
+		return ProcessingPhaseError, err
+	}
+
+	fd.readers.StartProgressUpdate()
+	_, _, err := StreamDataToFile(fd.readers.TopReader(), fileName, preallocation)
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	// Verify checksum if specified
+	if err := fd.readers.ValidateChecksum(); err != nil {
+		return ProcessingPhaseError, fmt.Errorf("checksum validation failed: %w", err)
+	}
+	return ProcessingPhaseResize, nil
+}
+
+// TransferConvertFile is called to convert the qcow2 image from the source to raw while it is transferred to the passed
+// in file.
+func (fd *FileShareDataSource) TransferConvertFile(fileName, scratchPath string, preallocation bool) (ProcessingPhase, error) {
+	if err := CleanAll(fileName); err != nil {
+		return ProcessingPhaseError, err
+	}
+	fd.readers.StartProgressUpdate()
+	if err := StreamQcow2ToFile(fd.readers.TopReader(), fileName, scratchPath, preallocation); err != nil {
+		return ProcessingPhaseError, err
+	}
+	// Verify checksum if specified
+	if err := fd.readers.ValidateChecksum(); err != nil {
+		return ProcessingPhaseError, fmt.Errorf("checksum validation failed: %w", err)
+	}
+	return ProcessingPhaseResize, nil
+}
+
+// CanStreamConvert returns true if the image is a qcow2 image which can be converted while it is transferred.
+func (fd *FileShareDataSource) CanStreamConvert() bool {
+	return fd.readers.StreamConvert
+}
+
+// GetVirtualSize returns the virtual size of a qcow2 image.
+func (fd *FileShareDataSource) GetVirtualSize() int64 {
+	return fd.readers.VirtualSize
+}
+
+// GetURL returns the url that the data processor can use when converting the data.
+func (fd *FileShareDataSource) GetURL() *url.URL {
+	return fd.url
+}
+
+// GetTerminationMessage returns data to be serialized and used as the termination message of the importer.
+func (fd *FileShareDataSource) GetTerminationMessage() *common.TerminationMessage {
+	return nil
+}
+
+// Close closes any readers or other open resources.
+func (fd *FileShareDataSource) Close() error {
+	var err error
+	if fd.readers != nil {
+		err = fd.readers.Close()
+	}
+	if closeErr := fd.fileReader.Close(); closeErr != nil {
+		err = closeErr
+	}
+	return err
+}
+
+// fileShareReader reads a file from a file server. Closing it closes the file and the connection to the server once.
+type fileShareReader struct {
+	io.Reader
+	// closers are closed in order
+	closers []io.Closer
+	once    sync.Once
+	err     error
+}
+
+func (r *fileShareReader) Close() error {
+	r.once.Do(func() {
+		for _, closer := range r.closers {
+			if err := closer.Close(); err != nil && r.err == nil {
+				r.err = err
+			}
+		}
+	})
+	return r.err
+}
+
+// closerFunc adapts a function to the io.Closer interface
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+// hostPort returns the host and port of the endpoint, with the default port if it has none
+func hostPort(ep *url.URL, defaultPort string) string {
+	port := ep.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	return net.JoinHostPort(ep.Hostname(), port)
+}
+
+// sftpClientConfig returns the ssh configuration to log in with the credentials of credentialDir, accepting only
+// the host keys of the known_hosts file in hostKeyDir.
+func sftpClientConfig(credentialDir, hostKeyDir string) (*ssh.ClientConfig, error) {
+	user, err := os.ReadFile(filepath.Join(credentialDir, common.KeyAccess))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the SFTP user name")
+	}
+	var auth []ssh.AuthMethod
+	if key, err := os.ReadFile(filepath.Join(credentialDir, common.KeySSHPrivateKey)); err == nil {
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to parse the SFTP private key")
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	} else if !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "unable to read the SFTP private key")
+	}
+	if password, err := os.ReadFile(filepath.Join(credentialDir, common.KeySecret)); err == nil {
+		auth = append(auth, ssh.Password(string(password)), ssh.KeyboardInteractive(
+			func(_, _ string, questions []string, _ []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i := range answers {
+					answers[i] = string(password)
+				}
+				return answers, nil
+			}))
+	} else if !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "unable to read the SFTP password")
+	}
+	if len(auth) == 0 {
+		return nil, errors.Errorf("the SFTP secret has neither a %s nor a %s key", common.KeySecret, common.KeySSHPrivateKey)
+	}
+
+	knownHosts := filepath.Join(hostKeyDir, common.KeyKnownHosts)
+	hostKeyCallback, err := knownhosts.New(knownHosts)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the SFTP host keys")
+	}
+	hostKeyAlgorithms, err := knownHostKeyAlgorithms(knownHosts)
+	if err != nil {
+		return nil, err
+	}
+	return &ssh.ClientConfig{
+		User:              string(user),
+		Auth:              auth,
+		HostKeyCallback:   hostKeyCallback,
+		HostKeyAlgorithms: hostKeyAlgorithms,
+		Timeout:           fileShareDialTimeout,
+	}, nil
+}
+
+// knownHostKeyAlgorithms returns the host key algorithms of the keys of the known_hosts file, so that a server
+// having several host keys presents one of the known ones. It returns nil, which allows all algorithms, if the file
+// lists certificate authorities.
+func knownHostKeyAlgorithms(knownHosts string) ([]string, error) {
+	data, err := os.ReadFile(knownHosts)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the SFTP host keys")
+	}
+	var algorithms []string
+	for len(data) > 0 {
+		marker, _, key, _, rest, err := ssh.ParseKnownHosts(data)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to parse the SFTP host keys")
+		}
+		data = rest
+		if marker == "@cert-authority" {
+			return nil, nil
+		}
+		keyAlgorithms := []string{key.Type()}
+		if key.Type() == ssh.KeyAlgoRSA {
+			keyAlgorithms = []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+		}
+		for _, algorithm := range keyAlgorithms {
+			if !slices.Contains(algorithms, algorithm) {
+				algorithms = append(algorithms, algorithm)
+			}
+		}
+	}
+	if len(algorithms) == 0 {
+		return nil, errors.New("the SFTP host key secret has no host key")
+	}
+	return algorithms, nil
+}
+
+// sftpPath returns the path of the file on the server, paths starting with /~/ are relative to the home directory
+func sftpPath(ep *url.URL) string {
+	return strings.TrimPrefix(ep.Path, "/~/")
+}
+
+// openSFTPFile opens the file of the sftp:// endpoint, and returns its size
+func openSFTPFile(ep *url.URL, config *ssh.ClientConfig) (io.ReadCloser, uint64, error) {
+	addr := hostPort(ep, sftpDefaultPort)
+	klog.Infof("Reading %s from SFTP server %s as %s", ep.Path, addr, config.User)
+	conn, err := ssh.Dial("tcp", addr, config)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "unable to connect to SFTP server %s", addr)
+	}
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, 0, errors.Wrap(err, "unable to start sftp subsystem")
+	}
+	filePath := sftpPath(ep)
+	f, err := client.Open(filePath)
+	if err != nil {
+		client.Close()
+		conn.Close()
+		return nil, 0, errors.Wrapf(err, "unable to open %q", filePath)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		client.Close()
+		conn.Close()
+		return nil, 0, errors.Wrapf(err, "unable to stat %q", filePath)
+	}
+	return &fileShareReader{Reader: f, closers: []io.Closer{f, client, conn}}, uint64(info.Size()), nil
+}
+
+// splitSMBPath returns the share name and the path of the file in the share
+func splitSMBPath(ep *url.URL) (string, string, error) {
+	share, file, found := strings.Cut(strings.TrimPrefix(ep.Path, "/"), "/")
+	if !found || share == "" || file == "" {
+		return "", "", errors.Errorf("SMB url %q does not contain a share name and a file", ep.String())
+	}
+	return share, file, nil
+}
+
+// openSMBFile opens the file of the smb:// endpoint, and returns its size
+func openSMBFile(ep *url.URL, user, password string) (io.ReadCloser, uint64, error) {
+	shareName, fileName, err := splitSMBPath(ep)
+	if err != nil {
+		return nil, 0, err
+	}
+	if user == "" {
+		user = smbGuestUser
+	}
+	addr := hostPort(ep, smbDefaultPort)
+	klog.Infof("Reading %s from share %s of SMB server %s as %s", fileName, shareName, addr, user)
+	conn, err := net.DialTimeout("tcp", addr, fileShareDialTimeout)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "unable to connect to SMB server %s", addr)
+	}
+	// The user name may be qualified with its domain, DOMAIN\user
+	domain, user, found := strings.Cut(user, `\`)
+	if !found {
+		domain, user = "", domain
+	}
+	dialer := &smb2.Dialer{
+		Initiator: &smb2.NTLMInitiator{
+			User:     user,
+			Password: password,
+			Domain:   domain,
+		},
+	}
+	session, err := dialer.Dial(conn)
+	if err != nil {
+		conn.Close()
+		return nil, 0, errors.Wrapf(err, "unable to log in to SMB server %s", addr)
+	}
+	reader := &fileShareReader{closers: []io.Closer{closerFunc(session.Logoff), conn}}
+	share, err := session.Mount(shareName)
+	if err != nil {
+		reader.Close()
+		return nil, 0, errors.Wrapf(err, "unable to mount SMB share %s", shareName)
+	}
+	reader.closers = append([]io.Closer{closerFunc(share.Umount)}, reader.closers...)
+	f, err := share.Open(fileName)
+	if err != nil {
+		reader.Close()
+		return nil, 0, errors.Wrapf(err, "unable to open %s", fileName)
+	}
+	reader.closers = append([]io.Closer{f}, reader.closers...)
+	info, err := f.Stat()
+	if err != nil {
+		reader.Close()
+		return nil, 0, errors.Wrapf(err, "unable to stat %s", fileName)
+	}
+	// A read from the share is a round trip to the server, read large blocks
+	reader.Reader = bufio.NewReaderSize(f, smbReadBufferSize)
+	return reader, uint64(info.Size()), nil
+}
@@ -0,0 +1,465 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pkg/errors"
+	"github.com/pkg/sftp"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const (
+	sftpTestUser     = "cdi"
+	sftpTestPassword = "secret"
+	// sftpTestHome is the directory of the paths relative to the home directory
+	sftpTestHome = "/home/cdi"
+	// sftpTestMaxRead is the largest read the test server replies to, like most servers
+	sftpTestMaxRead = 32 * 1024
+)
+
+// sftpTestServer is an SSH server with a read only SFTP subsystem serving files from memory
+type sftpTestServer struct {
+	listener net.Listener
+	hostKey  ssh.Signer
+	config   *ssh.ServerConfig
+	files    map[string][]byte
+	// maxRead limits the size of the data returned by a read
+	maxRead int
+}
+
+func newSFTPTestServer(files map[string][]byte, clientKey ssh.PublicKey) *sftpTestServer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	hostKey, err := ssh.NewSignerFromKey(key)
+	Expect(err).ToNot(HaveOccurred())
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == sftpTestUser && string(password) == sftpTestPassword {
+				return nil, nil
+			}
+			return nil, errors.New("invalid password")
+		},
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if clientKey != nil && conn.User() == sftpTestUser && bytes.Equal(key.Marshal(), clientKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("invalid key")
+		},
+	}
+	config.AddHostKey(hostKey)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+	s := &sftpTestServer{
+		listener: listener,
+		hostKey:  hostKey,
+		config:   config,
+		files:    files,
+		maxRead:  sftpTestMaxRead,
+	}
+	go s.serve()
+	return s
+}
+
+func (s *sftpTestServer) Close() {
+	s.listener.Close()
+}
+
+func (s *sftpTestServer) url(path string) string {
+	return "sftp://" + s.listener.Addr().String() + path
+}
+
+// knownHosts returns a known_hosts line for the server host key
+func (s *sftpTestServer) knownHosts() string {
+	return knownhosts.Line([]string{knownhosts.Normalize(s.listener.Addr().String())}, s.hostKey.PublicKey()) + "\n"
+}
+
+func (s *sftpTestServer) serve() {
+	defer GinkgoRecover()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.serveConn(conn)
+	}
+}
+
+func (s *sftpTestServer) serveConn(conn net.Conn) {
+	defer GinkgoRecover()
+	defer conn.Close()
+	_, channels, requests, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported channel")
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer GinkgoRecover()
+			for req := range channelRequests {
+				isSFTP := req.Type == "subsystem" && string(req.Payload[4:]) == "sftp"
+				_ = req.Reply(isSFTP, nil)
+				if isSFTP {
+					go s.serveSFTP(channel)
+				}
+			}
+		}()
+	}
+}
+
+func (s *sftpTestServer) serveSFTP(channel ssh.Channel) {
+	defer GinkgoRecover()
+	handler := &sftpTestHandler{files: s.files, maxRead: s.maxRead}
+	server := sftp.NewRequestServer(channel, sftp.Handlers{
+		FileGet:  handler,
+		FilePut:  handler,
+		FileCmd:  handler,
+		FileList: handler,
+	}, sftp.WithStartDirectory(sftpTestHome))
+	_ = server.Serve()
+	server.Close()
+}
+
+// sftpTestHandler serves read only files from memory
+type sftpTestHandler struct {
+	files map[string][]byte
+	// maxRead limits the size of the data returned by a read
+	maxRead int
+}
+
+func (h *sftpTestHandler) file(r *sftp.Request) ([]byte, error) {
+	data, found := h.files[r.Filepath]
+	if !found {
+		return nil, os.ErrNotExist
+	}
+	return data, nil
+}
+
+func (h *sftpTestHandler) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	data, err := h.file(r)
+	if err != nil {
+		return nil, err
+	}
+	return &shortReaderAt{ReaderAt: bytes.NewReader(data), maxRead: h.maxRead}, nil
+}
+
+func (h *sftpTestHandler) Filewrite(*sftp.Request) (io.WriterAt, error) {
+	return nil, os.ErrPermission
+}
+
+func (h *sftpTestHandler) Filecmd(*sftp.Request) error {
+	return os.ErrPermission
+}
+
+func (h *sftpTestHandler) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	if r.Method != "Stat" {
+		return nil, os.ErrPermission
+	}
+	data, err := h.file(r)
+	if err != nil {
+		return nil, err
+	}
+	return sftpTestLister{&sftpTestFileInfo{name: path.Base(r.Filepath), size: int64(len(data))}}, nil
+}
+
+// shortReaderAt returns at most maxRead bytes per read, like servers limiting the size of their replies
+type shortReaderAt struct {
+	io.ReaderAt
+	maxRead int
+}
+
+func (r *shortReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return r.ReaderAt.ReadAt(p[:min(len(p), r.maxRead)], off)
+}
+
+type sftpTestLister []os.FileInfo
+
+func (l sftpTestLister) ListAt(infos []os.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(l)) {
+		return 0, io.EOF
+	}
+	return copy(infos, l[offset:]), nil
+}
+
+type sftpTestFileInfo struct {
+	name string
+	size int64
+}
+
+func (fi *sftpTestFileInfo) Name() string       { return fi.name }
+func (fi *sftpTestFileInfo) Size() int64        { return fi.size }
+func (fi *sftpTestFileInfo) Mode() os.FileMode  { return 0444 }
+func (fi *sftpTestFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi *sftpTestFileInfo) IsDir() bool        { return false }
+func (fi *sftpTestFileInfo) Sys() interface{}   { return nil }
+
+// writeSecret writes the keys of a secret mounted as a volume
+func writeSecret(dir string, data map[string]string) {
+	for key, value := range data {
+		Expect(os.WriteFile(filepath.Join(dir, key), []byte(value), 0600)).To(Succeed())
+	}
+}
+
+var _ = Describe("File share data source", func() {
+	var (
+		fd            *FileShareDataSource
+		tmpDir        string
+		credentialDir string
+		hostKeyDir    string
+		server        *sftpTestServer
+		disk          []byte
+		diskChecksum  string
+	)
+
+	BeforeEach(func() {
+		tmpDir = GinkgoT().TempDir()
+		credentialDir = GinkgoT().TempDir()
+		hostKeyDir = GinkgoT().TempDir()
+		disk = make([]byte, 3*1024*1024+1234)
+		for i := range disk {
+			disk[i] = byte(i % 251)
+		}
+		sum := sha256.Sum256(disk)
+		diskChecksum = "sha256:" + hex.EncodeToString(sum[:])
+	})
+
+	AfterEach(func() {
+		if fd != nil {
+			fd.Close()
+			fd = nil
+		}
+		if server != nil {
+			server.Close()
+			server = nil
+		}
+	})
+
+	transferFile := func() []byte {
+		phase, err := fd.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferDataFile))
+		target := filepath.Join(tmpDir, "disk.img")
+		phase, err = fd.TransferFile(target, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseResize))
+		data, err := os.ReadFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		return data
+	}
+
+	Context("with SFTP", func() {
+		It("should import a file with a password", func() {
+			server = newSFTPTestServer(map[string][]byte{"/images/disk.img": disk}, nil)
+			writeSecret(credentialDir, map[string]string{common.KeyAccess: sftpTestUser, common.KeySecret: sftpTestPassword})
+			writeSecret(hostKeyDir, map[string]string{common.KeyKnownHosts: server.knownHosts()})
+			var err error
+			fd, err = NewSFTPDataSource(server.url("/images/disk.img"), credentialDir, hostKeyDir, diskChecksum)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fd.size).To(Equal(uint64(len(disk))))
+			Expect(transferFile()).To(Equal(disk))
+		})
+
+		It("should import a file with a private key, when the server returns short reads", func() {
+			_, key, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			signer, err := ssh.NewSignerFromKey(key)
+			Expect(err).ToNot(HaveOccurred())
+			block, err := ssh.MarshalPrivateKey(key, "")
+			Expect(err).ToNot(HaveOccurred())
+			server = newSFTPTestServer(map[string][]byte{sftpTestHome + "/disk.img": disk}, signer.PublicKey())
+			server.maxRead = 10000
+			writeSecret(credentialDir, map[string]string{common.KeyAccess: sftpTestUser, common.KeySSHPrivateKey: string(pem.EncodeToMemory(block))})
+			writeSecret(hostKeyDir, map[string]string{common.KeyKnownHosts: server.knownHosts()})
+			fd, err = NewSFTPDataSource(server.url("/~/disk.img"), credentialDir, hostKeyDir, diskChecksum)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(transferFile()).To(Equal(disk))
+		})
+
+		It("should refuse a server presenting an unknown host key", func() {
+			server = newSFTPTestServer(map[string][]byte{"/disk.img": disk}, nil)
+			other := newSFTPTestServer(nil, nil)
+			defer other.Close()
+			writeSecret(credentialDir, map[string]string{common.KeyAccess: sftpTestUser, common.KeySecret: sftpTestPassword})
+			knownHosts := knownhosts.Line([]string{knownhosts.Normalize(server.listener.Addr().String())}, other.hostKey.PublicKey())
+			writeSecret(hostKeyDir, map[string]string{common.KeyKnownHosts: knownHosts})
+			_, err := NewSFTPDataSource(server.url("/disk.img"), credentialDir, hostKeyDir, "")
+			Expect(err).To(MatchError(ContainSubstring("key mismatch")))
+		})
+
+		It("should fail with an invalid password", func() {
+			server = newSFTPTestServer(map[string][]byte{"/disk.img": disk}, nil)
+			writeSecret(credentialDir, map[string]string{common.KeyAccess: sftpTestUser, common.KeySecret: "invalid"})
+			writeSecret(hostKeyDir, map[string]string{common.KeyKnownHosts: server.knownHosts()})
+			_, err := NewSFTPDataSource(server.url("/disk.img"), credentialDir, hostKeyDir, "")
+			Expect(err).To(MatchError(ContainSubstring("unable to authenticate")))
+		})
+
+		It("should fail when the file does not exist", func() {
+			server = newSFTPTestServer(map[string][]byte{"/disk.img": disk}, nil)
+			writeSecret(credentialDir, map[string]string{common.KeyAccess: sftpTestUser, common.KeySecret: sftpTestPassword})
+			writeSecret(hostKeyDir, map[string]string{common.KeyKnownHosts: server.knownHosts()})
+			_, err := NewSFTPDataSource(server.url("/other.img"), credentialDir, hostKeyDir, "")
+			Expect(err).To(MatchError(ContainSubstring(`unable to open "/other.img"`)))
+		})
+
+		It("should fail when the checksum does not match", func() {
+			server = newSFTPTestServer(map[string][]byte{"/disk.img": disk}, nil)
+			writeSecret(credentialDir, map[string]string{common.KeyAccess: sftpTestUser, common.KeySecret: sftpTestPassword})
+			writeSecret(hostKeyDir, map[string]string{common.KeyKnownHosts: server.knownHosts()})
+			var err error
+			fd, err = NewSFTPDataSource(server.url("/disk.img"), credentialDir, hostKeyDir, "sha256:"+hex.EncodeToString(make([]byte, 32)))
+			Expect(err).ToNot(HaveOccurred())
+			_, err = fd.Info()
+			Expect(err).ToNot(HaveOccurred())
+			_, err = fd.TransferFile(filepath.Join(tmpDir, "disk.img"), false)
+			Expect(err).To(MatchError(ContainSubstring("checksum validation failed")))
+		})
+
+		DescribeTable("should fail to read the secrets", func(credentials, hostKeys map[string]string, expected string) {
+			writeSecret(credentialDir, credentials)
+			writeSecret(hostKeyDir, hostKeys)
+			_, err := sftpClientConfig(credentialDir, hostKeyDir)
+			Expect(err).To(MatchError(ContainSubstring(expected)))
+		},
+			Entry("without user", map[string]string{common.KeySecret: sftpTestPassword}, nil, "unable to read the SFTP user name"),
+			Entry("without password or key", map[string]string{common.KeyAccess: sftpTestUser}, nil, "neither a secretKey nor a ssh-privatekey key"),
+			Entry("with an invalid key", map[string]string{common.KeyAccess: sftpTestUser, common.KeySSHPrivateKey: "invalid"}, nil,
+				"unable to parse the SFTP private key"),
+			Entry("without host keys", map[string]string{common.KeyAccess: sftpTestUser, common.KeySecret: sftpTestPassword}, nil,
+				"unable to read the SFTP host keys"),
+			Entry("with an empty host key file", map[string]string{common.KeyAccess: sftpTestUser, common.KeySecret: sftpTestPassword},
+				map[string]string{common.KeyKnownHosts: "# no keys\n"}, "the SFTP host key secret has no host key"),
+		)
+
+		It("should restrict the host key algorithms to the known host keys", func() {
+			server = newSFTPTestServer(nil, nil)
+			writeSecret(hostKeyDir, map[string]string{common.KeyKnownHosts: server.knownHosts()})
+			Expect(knownHostKeyAlgorithms(filepath.Join(hostKeyDir, common.KeyKnownHosts))).To(Equal([]string{ssh.KeyAlgoED25519}))
+		})
+	})
+
+	Context("with SMB", func() {
+		DescribeTable("should split the share name and the file path", func(endpoint, share, file string) {
+			ep, err := url.Parse(endpoint)
+			Expect(err).ToNot(HaveOccurred())
+			s, f, err := splitSMBPath(ep)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s).To(Equal(share))
+			Expect(f).To(Equal(file))
+		},
+			Entry("with a file at the root of the share", "smb://server/images/disk.img", "images", "disk.img"),
+			Entry("with a file in a directory", "smb://server:1445/images/fedora/disk.qcow2", "images", "fedora/disk.qcow2"),
+		)
+
+		It("should fail without a file path", func() {
+			_, err := NewSMBDataSource("smb://server/images", "", "", "")
+			Expect(err).To(MatchError(ContainSubstring("does not contain a share name and a file")))
+		})
+
+		It("should fail when the server is not reachable", func() {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ToNot(HaveOccurred())
+			addr := listener.Addr().String()
+			listener.Close()
+			_, err = NewSMBDataSource("smb://"+addr+"/images/disk.img", "user", "password", "")
+			Expect(err).To(MatchError(ContainSubstring("unable to connect to SMB server " + addr)))
+		})
+	})
+
+	Context("with NFS", func() {
+		It("should import a file from the mounted volume", func() {
+			Expect(os.MkdirAll(filepath.Join(tmpDir, "images"), 0700)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(tmpDir, "images", "disk.raw"), disk, 0600)).To(Succeed())
+			var err error
+			fd, err = NewNFSDataSource("images/disk.raw", tmpDir, diskChecksum)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(transferFile()).To(Equal(disk))
+		})
+
+		It("should convert a qcow2 image while it is transferred", func() {
+			var err error
+			fd, err = NewNFSDataSource("fs-overhead.qcow2", TestImagesDir, "")
+			Expect(err).ToNot(HaveOccurred())
+			phase, err := fd.Info()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(phase).To(Equal(ProcessingPhaseTransferScratch))
+			Expect(fd.CanStreamConvert()).To(BeTrue())
+			target := filepath.Join(tmpDir, "disk.img")
+			phase, err = fd.TransferConvertFile(target, "", false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(phase).To(Equal(ProcessingPhaseResize))
+			Expect(sha256File(target)).To(Equal("f2e7d09dcc87cf4a8f96989d6479bdf5545cf0c84f9d5ea4cee4c6d099cdae6d"))
+		})
+
+		It("should fail when the file does not exist", func() {
+			_, err := NewNFSDataSource("missing.img", tmpDir, "")
+			Expect(err).To(MatchError(ContainSubstring("unable to open missing.img")))
+		})
+
+		It("should refuse a path outside the volume", func() {
+			_, err := NewNFSDataSource("../disk.img", tmpDir, "")
+			Expect(err).To(MatchError(ContainSubstring("invalid NFS file path")))
+		})
+
+		It("should not follow a symlink out of the volume", func() {
+			outside := GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(outside, "disk.raw"), disk, 0600)).To(Succeed())
+			Expect(os.Symlink(filepath.Join(outside, "disk.raw"), filepath.Join(tmpDir, "disk.raw"))).To(Succeed())
+			_, err := NewNFSDataSource("disk.raw", tmpDir, "")
+			Expect(err).To(MatchError(ContainSubstring("unable to open disk.raw")))
+		})
+	})
+
+	It("should close the connection once", func() {
+		closed := 0
+		reader := &fileShareReader{Reader: bytes.NewReader(disk), closers: []io.Closer{closerFunc(func() error {
+			closed++
+			return nil
+		})}}
+		Expect(reader.Close()).To(Succeed())
+		Expect(reader.Close()).To(Succeed())
+		Expect(closed).To(Equal(1))
+	})
+})
@@ -18,12 +18,14 @@ package importer
 
 import (
 	"bytes"
+	"compress/bzip2"
 	"encoding/hex"
 	"io"
 	"strconv"
 
 	"github.com/klauspost/compress/zstd"
 	gzip "github.com/klauspost/pgzip"
+	"github.com/pierrec/lz4/v4"
 	"github.com/pkg/errors"
 	"github.com/ulikunitz/xz"
 
@@ -57,10 +59,17 @@ type FormatReaders struct {
 	readers           []reader
 	buf               []byte // holds file headers
 	Convert           bool
+	StreamConvert     bool  // true if the image is a qcow2 image which can be converted while it is streamed
+	VirtualSize       int64 // virtual size of a qcow2 image
 	Archived          bool
 	ArchiveXz         bool
 	ArchiveGz         bool
 	ArchiveZstd       bool
+	ArchiveBz2        bool
+	ArchiveLz4        bool
+	ArchiveZip        bool
+	Tar               bool // true if the stream is a tar archive
+	OVA               bool // true if the stream is an OVA appliance, a tar archive starting with an OVF descriptor
 	progressReader    *prometheusutil.ProgressReader
 	checksumValidator *ChecksumValidator
 }
@@ -71,12 +80,16 @@ const (
 	rdrXz
 	rdrStream
 	rdrChecksum
+	rdrBz2
+	rdrLz4
 )
 
 // map scheme and format to rdrType
 var rdrTypM = map[string]int{
 	"gz":     rdrGz,
 	"xz":     rdrXz,
+	"bz2":    rdrBz2,
+	"lz4":    rdrLz4,
 	"stream": rdrStream,
 }
 
@@ -118,9 +131,11 @@ func (fr *FormatReaders) constructReaders(r io.ReadCloser) error {
 		}
 		klog.V(2).Infof("found header of type %q\n", hdr.Format)
 		// create format-specific reader and append it to dataStream readers stack
-		fr.fileFormatSelector(hdr)
-		// exit loop if hdr is qcow2
-		if hdr.Format == "qcow2" {
+		if err := fr.fileFormatSelector(hdr); err != nil {
+			return errors.WithMessagef(err, "could not process %s image", hdr.Format)
+		}
+		// exit loop if hdr is qcow2, or a zip archive which is not decompressed while it is streamed
+		if hdr.Format == "qcow2" || hdr.Format == "zip" {
 			break
 		}
 	}
@@ -156,7 +171,7 @@ func (fr *FormatReaders) TopReader() io.ReadCloser {
 
 // Based on the passed in header, append the format-specific reader to the readers stack,
 // and update the receiver Size field. Note: a bool is set in the receiver for qcow2 files.
-func (fr *FormatReaders) fileFormatSelector(hdr *image.Header) {
+func (fr *FormatReaders) fileFormatSelector(hdr *image.Header) error {
 	var r io.Reader
 	var err error
 	fFmt := hdr.Format
@@ -179,6 +194,22 @@ func (fr *FormatReaders) fileFormatSelector(hdr *image.Header) {
 			fr.Archived = true
 			fr.ArchiveXz = true
 		}
+	case "bz2":
+		r = bzip2.NewReader(fr.TopReader())
+		fr.Archived = true
+		fr.ArchiveBz2 = true
+	case "lz4":
+		// only the lz4 frame format is detected, not the legacy format
+		r = lz4.NewReader(fr.TopReader())
+		fr.Archived = true
+		fr.ArchiveLz4 = true
+	case "zip":
+		// archive/zip reads the central directory at the end of the archive, the archive is stored in scratch
+		// space and extracted there with extractZipImage
+		r = nil
+		fr.Archived = true
+		fr.ArchiveZip = true
+		fr.Convert = true
 	case "qcow2":
 		r, err = fr.qcow2NopReader(hdr)
 		fr.Convert = true
@@ -194,10 +225,24 @@ func (fr *FormatReaders) fileFormatSelector(hdr *image.Header) {
 	case "vhdx":
 		r = nil
 		fr.Convert = true
+	case "tar":
+		r = nil
+		fr.Tar = true
+		fr.OVA = isOVFDescriptor(tarEntryName(fr.buf))
+	}
+	if err != nil {
+		return err
 	}
-	if err == nil && r != nil {
+	if r != nil {
 		fr.appendReader(rdrTypM[fFmt], r)
 	}
+	return nil
+}
+
+// tarEntryName returns the name of the entry from a tar header.
+func tarEntryName(hdr []byte) string {
+	name, _, _ := bytes.Cut(hdr[:100], []byte{0})
+	return string(name)
 }
 
 // Return the gz reader and the size of the endpoint "through the eye" of the previous reader.
@@ -230,10 +275,16 @@ func (fr *FormatReaders) zstReader() (io.ReadCloser, error) {
 // Note: size is stored at offset 24 in the qcow2 header.
 func (fr *FormatReaders) qcow2NopReader(h *image.Header) (io.Reader, error) {
 	s := hex.EncodeToString(fr.buf[h.SizeOff : h.SizeOff+h.SizeLen])
-	_, err := strconv.ParseInt(s, 16, 64)
+	size, err := strconv.ParseInt(s, 16, 64)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to determine original qcow2 file size from %+v", s)
 	}
+	fr.VirtualSize = size
+	if _, err := parseQcow2Header(fr.buf); err != nil {
+		klog.V(2).Infof("qcow2 image cannot be converted while it is streamed: %v", err)
+	} else {
+		fr.StreamConvert = true
+	}
 	return nil, nil
 }
 
@@ -262,8 +313,9 @@ func (fr *FormatReaders) matchHeader(knownHdrs *image.Headers) (*image.Header, e
 	if err != nil {
 		return nil, err
 	}
-	// append multi-reader so that the header data can be re-read by subsequent readers
-	fr.appendReader(rdrMulti, bytes.NewReader(fr.buf))
+	// append multi-reader so that the header data can be re-read by subsequent readers. The header is copied since
+	// buf is overwritten by the next match, before a lazy reader like lz4 may have consumed it.
+	fr.appendReader(rdrMulti, bytes.NewReader(bytes.Clone(fr.buf)))
 
 	// loop through known headers until a match
 	for format, kh := range *knownHdrs {
@@ -1,7 +1,9 @@
 package importer
 
 import (
+	"bytes"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -11,6 +13,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/pierrec/lz4/v4"
 
 	"kubevirt.io/containerized-data-importer/pkg/image"
 	"kubevirt.io/containerized-data-importer/tests/utils"
@@ -24,12 +27,34 @@ var (
 	tinyCoreXzFilePath, _     = utils.FormatTestData(tinyCoreFilePath, os.TempDir(), image.ExtXz)
 	tinyCoreGzFilePath, _     = utils.FormatTestData(tinyCoreFilePath, os.TempDir(), image.ExtGz)
 	tinyCoreTarFilePath, _    = utils.FormatTestData(tinyCoreFilePath, os.TempDir(), image.ExtTar)
+	tinyCoreZipFilePath, _    = utils.FormatTestData(tinyCoreFilePath, os.TempDir(), image.ExtZip)
 	archiveFilePath, _        = utils.ArchiveFiles(archiveFileNameWithoutExt, os.TempDir(), tinyCoreFilePath, cirrosFilePath)
 	archiveFileNameWithoutExt = strings.TrimSuffix(archiveFileName, filepath.Ext(archiveFileName))
 	cirrosFilePath            = filepath.Join(imageDir, cirrosFileName)
 	stringRdr                 = strings.NewReader("test data for reader 1")
 )
 
+const testBz2Image = `
+QlpoOTFBWSZTWfji0YAAAEHJAAQQf+A/AEAC0ccoqq7MNUbKejTSfkoOMnqeG8oUhFU/TegJgqmIqbyYR6BVHW+YzPLyxTdBaABE
+eAAUhKqFIAERIAFISqhTaqoUqhaABEWABaEqoU4195JzOfXmsdO99x1P6e3WdwTCaZ0OjtKORpQUPSYMrcW3JO0vk8ZXjmBMMQ7G
+R8ycVMoG7eVluqg85Dz1X484KGaRnGqULautWB+akTjribh913cZQU8PEzwvi7Xba3OPZ45p9+BxEbewheCLZSamhTvYlsIbTQQ0
+56vziHpleKu3a7q+oQRh20nvG4FOIfe/I9fqu5gayQr/YrRI+jOeG7zfVY7v7DDJRMc+2NlEAzpg85Xo5qHiQ7VtnwkLVl4i7oSm
+EmohzsSuLimEpJctsvuiN/u7M39QnfaT9Mcn7QwSBIghcCSveuD0h6mMOfKdmR8sPtann7bjeOBJSaq2PJuc5C3zylsiF0jTGtJG
+Lz1wHnxqu6TIjZvbQ3RGgGjFP0LWDC+gk09tkA4R3ynbmTUvvWZwV6/vEe+iu4UTLvn0GoOT9B71lRF1s0W9dJMq9t6AJfhnYWVv
+yPJpTifQ9Lq0owND07oN5tlezXmAnvfhbTE3N8orxWRLG0g2ZaZZW46dDnhCc4/QuJL3n0kUczzGwzFaK8QblOXKiA8YnTG73arr
+KgN7Z0S2wVLnJ91RvOU1aiS9GSCcU7wmWOR2mP4EKsuHn3zDWkldMP7pY/JQrmSxfZxIXsxUpOjgE9TacpVred/C7kinChIfHFow
+AA==`
+
+// testBz2Content returns the content of testBz2Image, lines of pseudo random numbers, which compress to more than
+// the size of the header read by the format readers.
+func testBz2Content() []byte {
+	var content bytes.Buffer
+	for i := uint32(0); i < 120; i++ {
+		fmt.Fprintf(&content, "%08x\n", i*2654435761)
+	}
+	return content.Bytes()
+}
+
 var _ = Describe("Format Readers", func() {
 	var fr *FormatReaders
 	BeforeEach(func() {
@@ -62,6 +87,7 @@ var _ = Describe("Format Readers", func() {
 	},
 		Entry("successfully construct a xz reader", tinyCoreXzFilePath, 4, false, true, false),              // [stream, multi-r, xz, multi-r] convert = false
 		Entry("successfully construct a gz reader", tinyCoreGzFilePath, 4, false, true, false),              // [stream, multi-r, gz, multi-r] convert = false
+		Entry("successfully detect a zip archive", tinyCoreZipFilePath, 2, false, true, true),               // [stream, multi-r] convert = true
 		Entry("successfully return the base reader when archived", archiveFilePath, 3, false, false, false), // [stream, multi-r, multi-r] convert = false
 		Entry("successfully construct qcow2 reader", cirrosFilePath, 2, false, false, true),                 // [stream, multi-r] convert = true
 		Entry("successfully construct .iso reader", tinyCoreFilePath, 2, false, false, false),               // [stream, multi-r] convert = false
@@ -91,6 +117,40 @@ var _ = Describe("Format Readers", func() {
 		Entry("should append io.Multireader", rdrMulti, stringRdr, 3, false),
 	)
 
+	It("should decompress a bzip2 image", func() {
+		// bzip2 -c of testBz2Content()
+		bz2, err := base64.StdEncoding.DecodeString(testBz2Image)
+		Expect(err).ToNot(HaveOccurred())
+		fr, err = NewFormatReaders(io.NopCloser(bytes.NewReader(bz2)), uint64(0), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fr.Archived).To(BeTrue())
+		Expect(fr.ArchiveBz2).To(BeTrue())
+		Expect(io.ReadAll(fr.TopReader())).To(Equal(testBz2Content()))
+	})
+
+	It("should decompress an lz4 image", func() {
+		content := bytes.Repeat(testBz2Content(), 4)
+		var frame bytes.Buffer
+		w := lz4.NewWriter(&frame)
+		_, err := w.Write(content)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(w.Close()).To(Succeed())
+		fr, err = NewFormatReaders(io.NopCloser(&frame), uint64(0), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fr.Archived).To(BeTrue())
+		Expect(fr.ArchiveLz4).To(BeTrue())
+		Expect(io.ReadAll(fr.TopReader())).To(Equal(content))
+	})
+
+	It("should not match a text file starting with the bzip2 magic", func() {
+		text := append([]byte("BZh9 is not a bzip2 stream\n"), testBz2Content()...)
+		var err error
+		fr, err = NewFormatReaders(io.NopCloser(bytes.NewReader(text)), uint64(0), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fr.Archived).To(BeFalse())
+		Expect(io.ReadAll(fr.TopReader())).To(Equal(text))
+	})
+
 	It("should not crash on no progress reader", func() {
 		stringReader := io.NopCloser(strings.NewReader("This is a test string"))
 		testReader, err := NewFormatReaders(stringReader, uint64(0), nil)
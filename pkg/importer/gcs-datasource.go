@@ -2,6 +2,9 @@ package importer
 
 import (
 	"context"
+	"crypto/md5" //nolint:gosec // MD5 is only used to read the checksum stored with the object
+	"encoding/hex"
+	"fmt"
 	"io"
 	"net/url"
 	"path/filepath"
@@ -15,6 +18,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util/checksum"
 )
 
 const (
@@ -22,8 +26,11 @@ const (
 	gcsScheme    = "gs"
 )
 
-// Helper for unit-testing
-var newReaderFunc = getGcsObjectReader
+// Helpers for unit-testing
+var (
+	newReaderFunc      = getGcsObjectReader
+	objectChecksumFunc = getGcsObjectChecksum
+)
 
 // GCSDataSource is the struct containing the information needed to import from a GCS data source.
 // Sequence of phases:
@@ -40,10 +47,12 @@ type GCSDataSource struct {
 	readers *FormatReaders
 	// The image file in scratch space.
 	url *url.URL
+	// checksumValidator validates the checksum of downloaded data
+	checksumValidator *ChecksumValidator
 }
 
 // NewGCSDataSource creates a new instance of the GCSDataSource
-func NewGCSDataSource(endpoint, keyFile string) (*GCSDataSource, error) {
+func NewGCSDataSource(endpoint, keyFile, checksum string) (*GCSDataSource, error) {
 	klog.V(3).Infoln("GCS Importer: New Data Source")
 
 	// Placeholders
@@ -84,17 +93,33 @@ func NewGCSDataSource(endpoint, keyFile string) (*GCSDataSource, error) {
 		return nil, err
 	}
 
+	if checksum == "" {
+		if checksum = objectChecksumFunc(ctx, client, bucket, object); checksum != "" {
+			klog.Infof("No checksum specified, using object metadata checksum %s", checksum)
+		}
+	}
+	// Create checksum validator if checksum is provided
+	checksumValidator, err := NewChecksumValidator(checksum)
+	if err != nil {
+		gcsReader.Close()
+		return nil, errors.Wrap(err, "invalid checksum")
+	}
+	if checksumValidator != nil {
+		klog.Infof("Checksum validation enabled: %s", checksum)
+	}
+
 	return &GCSDataSource{
-		ep:        ep,
-		keyFile:   keyFile,
-		gcsReader: gcsReader,
+		ep:                ep,
+		keyFile:           keyFile,
+		gcsReader:         gcsReader,
+		checksumValidator: checksumValidator,
 	}, nil
 }
 
 // Info is called to get initial information about the data.
 func (sd *GCSDataSource) Info() (ProcessingPhase, error) {
 	var err error
-	sd.readers, err = NewFormatReaders(sd.gcsReader, uint64(0), nil)
+	sd.readers, err = NewFormatReaders(sd.gcsReader, uint64(0), sd.checksumValidator)
 	if err != nil {
 		klog.Errorf("GCS Importer: Error creating readers: %v", err)
 		return ProcessingPhaseError, err
@@ -129,6 +154,15 @@ func (sd *GCSDataSource) Transfer(path string, preallocation bool) (ProcessingPh
 		klog.V(3).Infoln("GCS Importer: Transfer Error: ", err)
 		return ProcessingPhaseError, err
 	}
+	// Verify checksum if specified
+	if err := sd.readers.ValidateChecksum(); err != nil {
+		return ProcessingPhaseError, fmt.Errorf("checksum validation failed: %w", err)
+	}
+	if sd.readers.ArchiveZip {
+		if err := extractZipImage(file, preallocation); err != nil {
+			return ProcessingPhaseError, err
+		}
+	}
 	// If streaming succeeded, then parsing the file into URL will also succeed, no need to check error status
 	sd.url, _ = url.Parse(file)
 	return ProcessingPhaseConvert, nil
@@ -144,9 +178,39 @@ func (sd *GCSDataSource) TransferFile(fileName string, preallocation bool) (Proc
 	if err != nil {
 		return ProcessingPhaseError, err
 	}
+	// Verify checksum if specified
+	if err := sd.readers.ValidateChecksum(); err != nil {
+		return ProcessingPhaseError, fmt.Errorf("checksum validation failed: %w", err)
+	}
 	return ProcessingPhaseResize, nil
 }
 
+// TransferConvertFile is called to convert the qcow2 image from the source to raw while it is transferred to the passed
+// in file.
+func (sd *GCSDataSource) TransferConvertFile(fileName, scratchPath string, preallocation bool) (ProcessingPhase, error) {
+	if err := CleanAll(fileName); err != nil {
+		return ProcessingPhaseError, err
+	}
+	if err := StreamQcow2ToFile(sd.readers.TopReader(), fileName, scratchPath, preallocation); err != nil {
+		return ProcessingPhaseError, err
+	}
+	// Verify checksum if specified
+	if err := sd.readers.ValidateChecksum(); err != nil {
+		return ProcessingPhaseError, fmt.Errorf("checksum validation failed: %w", err)
+	}
+	return ProcessingPhaseResize, nil
+}
+
+// CanStreamConvert returns true if the image is a qcow2 image which can be converted while it is transferred.
+func (sd *GCSDataSource) CanStreamConvert() bool {
+	return sd.readers.StreamConvert
+}
+
+// GetVirtualSize returns the virtual size of a qcow2 image.
+func (sd *GCSDataSource) GetVirtualSize() int64 {
+	return sd.readers.VirtualSize
+}
+
 // GetURL returns the url that the data processor can use when converting the data.
 func (sd *GCSDataSource) GetURL() *url.URL {
 	return sd.url
@@ -182,6 +246,26 @@ func getGcsObjectReader(ctx context.Context, client *storage.Client, bucket, obj
 	return client.Bucket(bucket).Object(object).NewReader(ctx)
 }
 
+// getGcsObjectChecksum returns the MD5 checksum stored with the object, if any. Composite objects only have a CRC32C
+// checksum, which the storage client already verifies while reading the whole object.
+func getGcsObjectChecksum(ctx context.Context, client *storage.Client, bucket, object string) string {
+	attrs, err := client.Bucket(bucket).Object(object).Attrs(ctx)
+	if err != nil {
+		klog.Warningf("GCS Importer: unable to get the object attributes, skipping checksum validation: %v", err)
+		return ""
+	}
+	return gcsObjectAttrsChecksum(attrs)
+}
+
+// gcsObjectAttrsChecksum returns the MD5 checksum of the object attributes when it describes the downloaded data.
+// Gzip encoded objects are decompressed in transit, so their stored digest does not match.
+func gcsObjectAttrsChecksum(attrs *storage.ObjectAttrs) string {
+	if attrs.ContentEncoding == "gzip" || len(attrs.MD5) != md5.Size {
+		return ""
+	}
+	return checksum.AlgorithmMD5 + ":" + hex.EncodeToString(attrs.MD5)
+}
+
 // Extract url in format gs://bucket/filename or gs://bucket/subdir/filename
 func extractGcsBucketAndObject(s string) (string, string) {
 	klog.V(3).Infoln("GCS Importer: Extracting GCS Bucket and Object")
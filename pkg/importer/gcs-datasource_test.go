@@ -1,6 +1,7 @@
 package importer
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"os"
@@ -21,6 +22,9 @@ var _ = Describe("Google Cloud Storage data source", func() {
 
 	BeforeEach(func() {
 		newReaderFunc = mockGcsObjectReader
+		objectChecksumFunc = func(ctx context.Context, client *storage.Client, bucket, object string) string {
+			return ""
+		}
 		tmpDir, err = os.MkdirTemp("", "scratch")
 		Expect(err).NotTo(HaveOccurred())
 		By("tmpDir: " + tmpDir)
@@ -54,31 +58,31 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	})
 
 	It("NewGCSDataSource should Error, when passed in an invalid endpoint", func() {
-		sd, err = NewGCSDataSource("thisisinvalid#$%#ep", "")
+		sd, err = NewGCSDataSource("thisisinvalid#$%#ep", "", "")
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("NewGCSDataSource should Pass, when passed in an valid https endpoint without authentication", func() {
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/Object.tmp", "")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/Object.tmp", "", "")
 		Expect(err).NotTo(HaveOccurred())
 	})
 
 	It("NewGCSDataSource should Pass, when passed in an valid gs endpoint without authentication", func() {
-		sd, err = NewGCSDataSource("gs://Bucket1/Object.tmp", "")
+		sd, err = NewGCSDataSource("gs://Bucket1/Object.tmp", "", "")
 		Expect(err).NotTo(HaveOccurred())
 	})
 
 	It("NewGCSDataSource should Pass, when passed in an valid https endpoint with authentication", func() {
 		var sampleCredential = filepath.Join(imageDir, "gcs-secret.txt")
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/Object.tmp", "gcs-secret")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/Object.tmp", "gcs-secret", "")
 		Expect(err).NotTo(HaveOccurred())
 	})
 
 	It("NewGCSDataSource should Pass, when passed in an valid gs endpoint with authentication", func() {
 		var sampleCredential = filepath.Join(imageDir, "gcs-secret.txt")
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
-		sd, err = NewGCSDataSource("gs://Bucket1/Object.tmp", "gcs-secret")
+		sd, err = NewGCSDataSource("gs://Bucket1/Object.tmp", "gcs-secret", "")
 		Expect(err).NotTo(HaveOccurred())
 	})
 
@@ -87,7 +91,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = file.Close()
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/content.tar", "")
+		sd, err = NewGCSDataSource("gs://Bucket1/content.tar", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -98,7 +102,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("Info should return TransferDataFile, when passed in a valid RAW image using anonymous client and GCS endpoint", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -109,7 +113,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("Info should return TransferScratch, when passed in a valid QCOW2 image using anonymous client and GCS endpoint", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -124,7 +128,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = file.Close()
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/content.tar", "gcs-secret")
+		sd, err = NewGCSDataSource("gs://Bucket1/content.tar", "gcs-secret", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -137,7 +141,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "gcs-secret")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "gcs-secret", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -150,7 +154,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "gcs-secret")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "gcs-secret", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -163,7 +167,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = file.Close()
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/content.tar", "")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/content.tar", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -174,7 +178,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("Info should return TransferDataFile, when passed in a valid RAW image using anonymous client and HTTP(s) endpoint", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -185,7 +189,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("Info should return TransferScratch, when passed in a valid QCOW2 image using anonymous client and HTTP(s) endpoint", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -200,7 +204,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = file.Close()
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/content.tar", "gcs-secret")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/content.tar", "gcs-secret", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -213,7 +217,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "gcs-secret")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "gcs-secret", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -226,7 +230,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "gcs-secret")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "gcs-secret", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -237,7 +241,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("TransferFile using anonymous client and GCS URL should succeed reading RAW image when writing to valid file", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -251,7 +255,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("TransferFile using anonymous client and GCS URL should succeed reading QCOW2 image when writing to valid file", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -265,7 +269,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("TransferFile using anonymous client and GCS should fail reading RAW image on streaming error", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -279,7 +283,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("TransferFile using anonymous client and GCS should fail reading QCOW2 image on streaming error", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -295,7 +299,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "gcs-secret")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "gcs-secret", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -311,7 +315,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "gcs-secret")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "gcs-secret", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -327,7 +331,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "gcs-secret")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros.raw", "gcs-secret", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -343,7 +347,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "gcs-secret")
+		sd, err = NewGCSDataSource("gs://Bucket1/cirros-qcow2.img", "gcs-secret", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -357,7 +361,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("TransferFile using anonymous client and HTTP(s) URL should succeed reading RAW image when writing to valid file", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -371,7 +375,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("TransferFile using anonymous client and HTTP(s) URL should succeed reading QCOW2 image when writing to valid file", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -385,7 +389,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("TransferFile using anonymous client and HTTP(s) should fail reading RAW image on streaming error", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -399,7 +403,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 	It("TransferFile using anonymous client and HTTP(s) should fail reading QCOW2 image on streaming error", func() {
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -415,7 +419,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "gcs-secret")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "gcs-secret", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -431,7 +435,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "gcs-secret")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "gcs-secret", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -447,7 +451,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros.raw"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "gcs-secret")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros.raw", "gcs-secret", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -463,7 +467,7 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", sampleCredential)
 		file, err := os.Open(filepath.Join(imageDir, "cirros-qcow2.img"))
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "gcs-secret")
+		sd, err = NewGCSDataSource("https://storage.cloud.google.com/Bucket1/cirros-qcow2.img", "gcs-secret", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.gcsReader = file
 		result, err := sd.Info()
@@ -473,6 +477,50 @@ var _ = Describe("Google Cloud Storage data source", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(ProcessingPhaseError).To(Equal(result))
 	})
+
+	DescribeTable("TransferFile should validate checksum", func(checksum, objectChecksum string, wantErr bool) {
+		data := bytes.Repeat([]byte{0x55}, 1024)
+		newReaderFunc = func(ctx context.Context, client *storage.Client, bucket, object string) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data)), nil
+		}
+		objectChecksumFunc = func(ctx context.Context, client *storage.Client, bucket, object string) string {
+			return objectChecksum
+		}
+		sd, err = NewGCSDataSource("gs://Bucket1/disk.raw", "", checksum)
+		Expect(err).NotTo(HaveOccurred())
+		result, err := sd.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
+		result, err = sd.TransferFile(filepath.Join(tmpDir, "file"), false)
+		if wantErr {
+			Expect(err).To(MatchError(ErrChecksumMismatch))
+			Expect(ProcessingPhaseError).To(Equal(result))
+		} else {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ProcessingPhaseResize).To(Equal(result))
+		}
+	},
+		Entry("succeed with matching checksum", "md5:"+md5Hex(bytes.Repeat([]byte{0x55}, 1024)), "", false),
+		Entry("fail with mismatching checksum", "md5:"+md5Hex([]byte("other")), "", true),
+		Entry("succeed with matching object checksum", "", "md5:"+md5Hex(bytes.Repeat([]byte{0x55}, 1024)), false),
+		Entry("fail with mismatching object checksum", "", "md5:"+md5Hex([]byte("other")), true),
+		Entry("prefer the specified checksum over the object checksum",
+			"md5:"+md5Hex(bytes.Repeat([]byte{0x55}, 1024)), "md5:"+md5Hex([]byte("other")), false),
+	)
+
+	DescribeTable("gcsObjectAttrsChecksum should", func(attrs *storage.ObjectAttrs, want string) {
+		Expect(gcsObjectAttrsChecksum(attrs)).To(Equal(want))
+	},
+		Entry("return the MD5 of the object", &storage.ObjectAttrs{MD5: md5Sum([]byte("data"))}, "md5:"+md5Hex([]byte("data"))),
+		Entry("return nothing for a composite object", &storage.ObjectAttrs{CRC32C: 1234}, ""),
+		Entry("return nothing for a gzip encoded object",
+			&storage.ObjectAttrs{MD5: md5Sum([]byte("data")), ContentEncoding: "gzip"}, ""),
+	)
+
+	It("NewGCSDataSource should fail with an invalid checksum", func() {
+		sd, err = NewGCSDataSource("gs://Bucket1/disk.raw", "", "sha256:invalid")
+		Expect(err).To(HaveOccurred())
+	})
 })
 
 // Create Cloud Storage Object Reader pointing to a sample image
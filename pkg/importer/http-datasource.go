@@ -17,6 +17,7 @@ limitations under the License.
 package importer
 
 import (
+	"archive/tar"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
@@ -35,6 +36,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"golang.org/x/crypto/openpgp"
 
 	"k8s.io/klog/v2"
 
@@ -42,6 +44,7 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/image"
 	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/checksum"
 )
 
 const (
@@ -51,6 +54,14 @@ const (
 	defaultUserAgent  = "cdi-golang-importer"
 	httpContentType   = "Content-Type"
 	httpContentLength = "Content-Length"
+	httpAcceptRanges  = "Accept-Ranges"
+	httpETag          = "Etag"
+	httpLastModified  = "Last-Modified"
+
+	// maxChecksumFileSize limits the size of checksum files fetched from a checksum URL.
+	maxChecksumFileSize = 4 << 20
+	// checksumFileTimeout is the time allowed to fetch a checksum file.
+	checksumFileTimeout = 2 * time.Minute
 )
 
 // HTTPDataSource is the data provider for http(s) endpoints.
@@ -63,6 +74,11 @@ const (
 // 2.  ValidatePreScratch -> TransferScratch.
 // 3a. Transfer -> Convert if content type is kubevirt
 // 3b. Transfer -> Complete if content type is archive (Transfer is called with the target instead of the scratch space). Non block PVCs only.
+// When the server supports range requests, the progress of the transfer to scratch space is recorded, and a restarted
+// importer resumes the transfer instead of starting over. This is not possible for compressed sources.
+// If the endpoint is an OVA appliance, the OVF descriptor is parsed in the Info phase, and the selected disk is extracted
+// to scratch space in the Transfer phase. The capacity of the disk in the descriptor is validated against the target
+// before the transfer.
 type HTTPDataSource struct {
 	httpReader io.ReadCloser
 	ctx        context.Context
@@ -78,19 +94,35 @@ type HTTPDataSource struct {
 	url *url.URL
 	// path to the custom CA. Empty if not used
 	customCA string
+	// credentials and extra headers, needed to reconnect to the endpoint when resuming a transfer.
+	accessKey          string
+	secKey             string
+	extraHeaders       []string
+	insecureSkipVerify bool
+	// resumeValidator is the strong ETag or Last-Modified value of the endpoint, empty if the server does not support range requests.
+	resumeValidator string
 	// true if we know `qemu-img` will fail to download this
 	brokenForQemuImg bool
 	// the content length reported by the http server.
 	contentLength uint64
 	// checksumValidator validates the checksum of downloaded data
 	checksumValidator *ChecksumValidator
-	n                 image.NbdkitOperation
+	// diskPath and diskIndex select the disk to import from a tar archive or an OVA appliance, diskIndex is -1 if unset.
+	diskPath  string
+	diskIndex int
+	// ova is the disk selected from the OVF descriptor, nil if the endpoint is not an OVA appliance.
+	ova *ovaDisk
+	// archiveReader reads the files of the tar archive or of the OVA appliance following the OVF descriptor, nil if
+	// the disk is not imported from an archive.
+	archiveReader *tar.Reader
+	n             image.NbdkitOperation
 }
 
 var createNbdkitCurl = image.NewNbdkitCurl
 
-// NewHTTPDataSource creates a new instance of the http data provider.
-func NewHTTPDataSource(endpoint, accessKey, secKey, certDir string, contentType cdiv1.DataVolumeContentType, checksum string, insecureSkipVerify bool) (*HTTPDataSource, error) {
+// NewHTTPDataSource creates a new instance of the http data provider. diskPath or diskIndex select the disk to import if
+// the endpoint is a tar archive or an OVA appliance, diskIndex is ignored if it is negative.
+func NewHTTPDataSource(endpoint, accessKey, secKey, certDir string, contentType cdiv1.DataVolumeContentType, checksum, diskPath string, diskIndex int, insecureSkipVerify bool) (*HTTPDataSource, error) {
 	ep, err := ParseEndpoint(endpoint)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to parse endpoint %q", endpoint)
@@ -103,7 +135,7 @@ func NewHTTPDataSource(endpoint, accessKey, secKey, certDir string, contentType
 		return nil, errors.Wrap(err, "Error getting extra headers for HTTP client")
 	}
 
-	httpReader, contentLength, brokenForQemuImg, err := createHTTPReader(ctx, ep, accessKey, secKey, certDir, extraHeaders, secretExtraHeaders, contentType, insecureSkipVerify)
+	httpReader, contentLength, brokenForQemuImg, resumeValidator, err := createHTTPReader(ctx, ep, accessKey, secKey, certDir, extraHeaders, secretExtraHeaders, contentType, insecureSkipVerify)
 	if err != nil {
 		cancel()
 		return nil, err
@@ -120,15 +152,22 @@ func NewHTTPDataSource(endpoint, accessKey, secKey, certDir string, contentType
 	}
 
 	httpSource := &HTTPDataSource{
-		ctx:               ctx,
-		cancel:            cancel,
-		httpReader:        httpReader,
-		contentType:       contentType,
-		endpoint:          ep,
-		customCA:          certDir,
-		brokenForQemuImg:  brokenForQemuImg,
-		contentLength:     contentLength,
-		checksumValidator: checksumValidator,
+		ctx:                ctx,
+		cancel:             cancel,
+		httpReader:         httpReader,
+		contentType:        contentType,
+		endpoint:           ep,
+		customCA:           certDir,
+		accessKey:          accessKey,
+		secKey:             secKey,
+		extraHeaders:       append(extraHeaders, secretExtraHeaders...),
+		insecureSkipVerify: insecureSkipVerify,
+		resumeValidator:    resumeValidator,
+		brokenForQemuImg:   brokenForQemuImg,
+		contentLength:      contentLength,
+		checksumValidator:  checksumValidator,
+		diskPath:           diskPath,
+		diskIndex:          diskIndex,
 	}
 	httpSource.n, err = createNbdkitCurl(nbdkitPid, accessKey, secKey, certDir, nbdkitSocket, extraHeaders, secretExtraHeaders)
 	if err != nil {
@@ -152,6 +191,19 @@ func (hs *HTTPDataSource) Info() (ProcessingPhase, error) {
 	if hs.contentType == cdiv1.DataVolumeArchive {
 		return ProcessingPhaseTransferDataDir, nil
 	}
+	if hs.readers.OVA {
+		return hs.readOVADescriptor()
+	}
+	if hs.readers.Tar && (hs.diskPath != "" || hs.diskIndex >= 0) {
+		klog.Infof("Endpoint is a tar archive")
+		hs.archiveReader = tar.NewReader(hs.readers.TopReader())
+		hs.url = nil
+		return ProcessingPhaseTransferScratch, nil
+	}
+	if hs.diskPath != "" || hs.diskIndex >= 0 {
+		return ProcessingPhaseError, errors.Errorf("disk %s selected, but the endpoint is not a tar archive or an OVA appliance",
+			hs.describeDiskSelection())
+	}
 	// RegistryPullNode (node pull) fast-path: data is streamed via nbdkit to qemu-img for conversion
 	// without going through Transfer/TransferFile. Checksum validation is not performed in this path;
 	// when checksum is specified, it is ignored for node-pull imports. See documentation for limitations.
@@ -172,9 +224,15 @@ func (hs *HTTPDataSource) Info() (ProcessingPhase, error) {
 // Transfer is called to transfer the data from the source to a scratch location.
 func (hs *HTTPDataSource) Transfer(path string, preallocation bool) (ProcessingPhase, error) {
 	if hs.contentType == cdiv1.DataVolumeKubeVirt {
+		if hs.archiveReader != nil {
+			return hs.transferArchiveDisk(path, preallocation)
+		}
 		file := filepath.Join(path, tempFile)
-		if err := CleanAll(file); err != nil {
-			return ProcessingPhaseError, err
+		offset := hs.getResumeOffset(file)
+		if offset == 0 {
+			if err := CleanAll(file, resumeMarkerPath(file)); err != nil {
+				return ProcessingPhaseError, err
+			}
 		}
 		size, err := GetAvailableSpace(path)
 		if err != nil || size <= 0 {
@@ -192,19 +250,25 @@ func (hs *HTTPDataSource) Transfer(path string, preallocation bool) (ProcessingP
 			}
 		}
 		hs.readers.StartProgressUpdate()
-		_, _, err = StreamDataToFile(hs.readers.TopReader(), file, preallocation)
-		if err != nil {
+		if err := hs.transferResumable(file, offset, preallocation); err != nil {
 			return ProcessingPhaseError, err
 		}
+		// The data is complete, a restart should not resume from here.
+		removeResumeMarker(file)
 		// Verify checksum if specified
 		if err := hs.readers.ValidateChecksum(); err != nil {
 			return ProcessingPhaseError, fmt.Errorf("checksum validation failed: %w", err)
 		}
+		if hs.readers.ArchiveZip {
+			if err := extractZipImage(file, preallocation); err != nil {
+				return ProcessingPhaseError, err
+			}
+		}
 		// If we successfully wrote to the file, then the parse will succeed.
 		hs.url, _ = url.Parse(file)
 		return ProcessingPhaseConvert, nil
 	} else if hs.contentType == cdiv1.DataVolumeArchive {
-		if err := util.UnArchiveTar(hs.readers.TopReader(), path); err != nil {
+		if _, err := ExtractTar(hs.readers.TopReader(), path, nil); err != nil {
 			return ProcessingPhaseError, errors.Wrap(err, "unable to untar files from endpoint")
 		}
 		// Verify checksum if specified
@@ -217,6 +281,95 @@ func (hs *HTTPDataSource) Transfer(path string, preallocation bool) (ProcessingP
 	return ProcessingPhaseError, errors.Errorf("Unknown content type: %s", hs.contentType)
 }
 
+// readOVADescriptor selects the disk to import from the OVF descriptor of the OVA appliance.
+func (hs *HTTPDataSource) readOVADescriptor() (ProcessingPhase, error) {
+	klog.Infof("Endpoint is an OVA appliance")
+	hs.archiveReader = tar.NewReader(hs.readers.TopReader())
+	disk, err := readOVADescriptor(hs.archiveReader, hs.diskPath, hs.diskIndex)
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	hs.ova = disk
+	hs.url = nil
+	return ProcessingPhaseValidatePreScratch, nil
+}
+
+// transferArchiveDisk extracts the selected disk of the tar archive or OVA appliance to scratch space. The disk of an
+// OVA appliance is usually a stream optimized VMDK, which has to be converted from a file.
+func (hs *HTTPDataSource) transferArchiveDisk(path string, preallocation bool) (ProcessingPhase, error) {
+	file := filepath.Join(path, tempFile)
+	if err := CleanAll(file, resumeMarkerPath(file)); err != nil {
+		return ProcessingPhaseError, err
+	}
+	size, err := GetAvailableSpace(path)
+	if err != nil || size <= 0 {
+		return ProcessingPhaseError, ErrInvalidPath
+	}
+	hs.readers.StartProgressUpdate()
+	disk, err := hs.findArchiveDisk()
+	if err != nil {
+		return ProcessingPhaseError, err
+	}
+	defer disk.Close()
+	if _, _, err := StreamDataToFile(disk, file, preallocation); err != nil {
+		return ProcessingPhaseError, err
+	}
+	// The checksum covers the whole archive, read the files following the disk as well
+	if hs.checksumValidator != nil {
+		if _, err := io.Copy(io.Discard, hs.readers.TopReader()); err != nil {
+			return ProcessingPhaseError, errors.Wrap(err, "unable to read archive")
+		}
+		if err := hs.readers.ValidateChecksum(); err != nil {
+			return ProcessingPhaseError, fmt.Errorf("checksum validation failed: %w", err)
+		}
+	}
+	// If we successfully wrote to the file, then the parse will succeed.
+	hs.url, _ = url.Parse(file)
+	return ProcessingPhaseConvert, nil
+}
+
+// findArchiveDisk advances the archive reader to the selected disk, and returns a reader of its content.
+func (hs *HTTPDataSource) findArchiveDisk() (io.ReadCloser, error) {
+	if hs.ova != nil {
+		return findOVADisk(hs.archiveReader, hs.ova)
+	}
+	selector := &fileSelector{path: hs.diskPath}
+	if hs.diskPath == "" {
+		selector.skip = hs.diskIndex
+	}
+	for {
+		hdr, err := hs.archiveReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.Errorf("disk %s not found in the archive", hs.describeDiskSelection())
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read archive")
+		}
+		if hdr.Typeflag != tar.TypeReg || !selector.selects(hdr.Name) {
+			klog.V(3).Infof("Skipping archive entry %s", hdr.Name)
+			continue
+		}
+		klog.Infof("Selected disk %s of the archive", hdr.Name)
+		return io.NopCloser(hs.archiveReader), nil
+	}
+}
+
+func (hs *HTTPDataSource) describeDiskSelection() string {
+	if hs.diskPath != "" {
+		return hs.diskPath
+	}
+	return fmt.Sprintf("at index %d", hs.diskIndex)
+}
+
+// GetVirtualSize returns the capacity of the OVA disk from the OVF descriptor, or 0 if the endpoint is not an OVA
+// appliance.
+func (hs *HTTPDataSource) GetVirtualSize() int64 {
+	if hs.ova == nil {
+		return 0
+	}
+	return hs.ova.capacity
+}
+
 // TransferFile is called to transfer the data from the source to the passed in file.
 func (hs *HTTPDataSource) TransferFile(fileName string, preallocation bool) (ProcessingPhase, error) {
 	if err := CleanAll(fileName); err != nil {
@@ -372,22 +525,28 @@ func addExtraheaders(req *http.Request, extraHeaders []string) {
 	req.Header.Add("User-Agent", defaultUserAgent)
 }
 
-func createHTTPReader(ctx context.Context, ep *url.URL, accessKey, secKey, certDir string, extraHeaders, secretExtraHeaders []string, contentType cdiv1.DataVolumeContentType, insecureSkipVerify bool) (io.ReadCloser, uint64, bool, error) {
+// checkRedirectFunc returns a redirect policy that re-adds the credentials and extra headers lost on redirects.
+func checkRedirectFunc(accessKey, secKey string, extraHeaders []string) func(*http.Request, []*http.Request) error {
+	return func(r *http.Request, via []*http.Request) error {
+		if len(accessKey) > 0 && len(secKey) > 0 {
+			r.SetBasicAuth(accessKey, secKey) // Redirects will lose basic auth, so reset them manually
+		}
+		addExtraheaders(r, extraHeaders)
+		return nil
+	}
+}
+
+func createHTTPReader(ctx context.Context, ep *url.URL, accessKey, secKey, certDir string, extraHeaders, secretExtraHeaders []string, contentType cdiv1.DataVolumeContentType, insecureSkipVerify bool) (io.ReadCloser, uint64, bool, string, error) {
 	var brokenForQemuImg bool
+	var resumeValidator string
 	client, err := createHTTPClient(certDir, insecureSkipVerify)
 	if err != nil {
-		return nil, uint64(0), false, errors.Wrap(err, "Error creating http client")
+		return nil, uint64(0), false, "", errors.Wrap(err, "Error creating http client")
 	}
 
 	allExtraHeaders := append(extraHeaders, secretExtraHeaders...)
 
-	client.CheckRedirect = func(r *http.Request, via []*http.Request) error {
-		if len(accessKey) > 0 && len(secKey) > 0 {
-			r.SetBasicAuth(accessKey, secKey) // Redirects will lose basic auth, so reset them manually
-		}
-		addExtraheaders(r, allExtraHeaders)
-		return nil
-	}
+	client.CheckRedirect = checkRedirectFunc(accessKey, secKey, allExtraHeaders)
 
 	total, err := getContentLength(client, ep, accessKey, secKey, allExtraHeaders)
 	if err != nil {
@@ -405,11 +564,11 @@ func createHTTPReader(ctx context.Context, ep *url.URL, accessKey, secKey, certD
 	klog.V(2).Infof("Attempting to get object %q via http client\n", ep.String())
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, uint64(0), true, errors.Wrap(err, "HTTP request errored")
+		return nil, uint64(0), true, "", errors.Wrap(err, "HTTP request errored")
 	}
 	if want := http.StatusOK; resp.StatusCode != want {
 		klog.Errorf("http: expected status code %d, got %d", want, resp.StatusCode)
-		return nil, uint64(0), true, errors.Errorf("expected status code %d, got %d. Status: %s", want, resp.StatusCode, resp.Status)
+		return nil, uint64(0), true, "", errors.Errorf("expected status code %d, got %d. Status: %s", want, resp.StatusCode, resp.Status)
 	}
 
 	if contentType == cdiv1.DataVolumeKubeVirt {
@@ -422,10 +581,12 @@ func createHTTPReader(ctx context.Context, ep *url.URL, accessKey, secKey, certD
 		}
 	}
 
-	acceptRanges, ok := resp.Header["Accept-Ranges"]
+	acceptRanges, ok := resp.Header[httpAcceptRanges]
 	if !ok || acceptRanges[0] == "none" {
 		klog.V(2).Infof("Accept-Ranges isn't bytes, avoiding qemu-img")
 		brokenForQemuImg = true
+	} else if acceptRanges[0] == "bytes" {
+		resumeValidator = getResumeValidator(resp.Header)
 	}
 
 	if total == 0 {
@@ -436,7 +597,156 @@ func createHTTPReader(ctx context.Context, ep *url.URL, accessKey, secKey, certD
 		Reader:  resp.Body,
 		Current: 0,
 	}
-	return countingReader, total, brokenForQemuImg, nil
+	return countingReader, total, brokenForQemuImg, resumeValidator, nil
+}
+
+// getResumeValidator returns the value to use in an If-Range header, to make sure a resumed transfer continues
+// reading the same content. If-Range does not allow weak ETags, fall back to Last-Modified in that case.
+func getResumeValidator(header http.Header) string {
+	if etag := header.Get(httpETag); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return header.Get(httpLastModified)
+}
+
+// resumable returns true if the transfer to scratch space can be resumed after a restart. This requires the
+// data to be written to the file as it is received, so compressed sources cannot be resumed.
+func (hs *HTTPDataSource) resumable() bool {
+	return hs.resumeValidator != "" && hs.contentLength > 0 && hs.readers != nil && !hs.readers.Archived
+}
+
+// getResumeOffset returns the offset to resume the transfer into the passed in file at, or 0 if the transfer
+// has to start from the beginning.
+func (hs *HTTPDataSource) getResumeOffset(fileName string) int64 {
+	if !hs.resumable() {
+		return 0
+	}
+	marker, err := readResumeMarker(fileName)
+	if err != nil {
+		klog.Warningf("Ignoring progress marker: %v", err)
+		return 0
+	}
+	if marker == nil {
+		return 0
+	}
+	if marker.Endpoint != hs.endpoint.String() || marker.Validator != hs.resumeValidator || marker.ContentLength != hs.contentLength {
+		klog.Infof("Source changed since the previous transfer, starting over")
+		return 0
+	}
+	if marker.Offset <= 0 || uint64(marker.Offset) >= hs.contentLength {
+		return 0
+	}
+	if _, err := os.Stat(fileName); err != nil {
+		return 0
+	}
+	return marker.Offset
+}
+
+// transferResumable streams the data into the passed in file, recording the progress so it can be resumed
+// if the importer is restarted. If offset is not 0, it attempts to resume the transfer at that offset.
+func (hs *HTTPDataSource) transferResumable(fileName string, offset int64, preallocation bool) error {
+	if !hs.resumable() {
+		_, _, err := StreamDataToFile(hs.readers.TopReader(), fileName, preallocation)
+		return err
+	}
+	marker := resumeMarker{
+		Endpoint:      hs.endpoint.String(),
+		Validator:     hs.resumeValidator,
+		ContentLength: hs.contentLength,
+	}
+	if offset > 0 {
+		r, err := hs.resumeReader(fileName, offset)
+		if err != nil {
+			return err
+		}
+		if r != nil {
+			klog.Infof("Resuming transfer at offset %d of %d", offset, hs.contentLength)
+			marker.Offset = offset
+			return streamDataToFileAt(newCheckpointReader(r, fileName, marker), fileName, offset, preallocation)
+		}
+		klog.Infof("Unable to resume transfer, starting over")
+		if err := CleanAll(fileName, resumeMarkerPath(fileName)); err != nil {
+			return err
+		}
+	}
+	return streamDataToFileAt(newCheckpointReader(hs.readers.TopReader(), fileName, marker), fileName, 0, preallocation)
+}
+
+// resumeReader requests the data starting at offset from the endpoint, and returns a reader that replaces the
+// top reader of the format readers. Returns nil if the server did not return the requested range.
+func (hs *HTTPDataSource) resumeReader(fileName string, offset int64) (io.Reader, error) {
+	body, err := hs.createRangeReader(offset)
+	if err != nil || body == nil {
+		return nil, err
+	}
+	// Anything after the offset might not have been written completely.
+	if err := os.Truncate(fileName, offset); err != nil {
+		body.Close()
+		return nil, errors.Wrapf(err, "could not truncate file %q", fileName)
+	}
+	if hs.checksumValidator != nil {
+		f, err := os.Open(fileName)
+		if err != nil {
+			body.Close()
+			return nil, err
+		}
+		err = hs.checksumValidator.Resume(io.LimitReader(f, offset))
+		f.Close()
+		if err != nil {
+			body.Close()
+			return nil, errors.Wrap(err, "unable to calculate checksum of previously transferred data")
+		}
+	}
+	// Swap the body of the original response, so progress and idle time are tracked on the new one.
+	countingReader := hs.httpReader.(*util.CountingReader)
+	countingReader.Reader.Close()
+	countingReader.Reader = body
+	var r io.Reader = countingReader
+	if hs.readers.progressReader != nil {
+		hs.readers.progressReader.Current = uint64(offset)
+		r = hs.readers.progressReader
+	}
+	if hs.checksumValidator != nil {
+		r = hs.checksumValidator.GetReader(r)
+	}
+	return r, nil
+}
+
+// createRangeReader requests the data starting at offset from the endpoint. Returns nil if the server
+// responds with anything but the requested range, for instance because the content changed.
+func (hs *HTTPDataSource) createRangeReader(offset int64) (io.ReadCloser, error) {
+	client, err := createHTTPClient(hs.customCA, hs.insecureSkipVerify)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating http client")
+	}
+	client.CheckRedirect = checkRedirectFunc(hs.accessKey, hs.secKey, hs.extraHeaders)
+
+	req, err := http.NewRequestWithContext(hs.ctx, http.MethodGet, hs.endpoint.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create HTTP request")
+	}
+	addExtraheaders(req, hs.extraHeaders)
+	if len(hs.accessKey) > 0 && len(hs.secKey) > 0 {
+		req.SetBasicAuth(hs.accessKey, hs.secKey)
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	req.Header.Set("If-Range", hs.resumeValidator)
+	klog.V(2).Infof("Attempting to get object %q starting at offset %d via http client\n", hs.endpoint.String(), offset)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "HTTP request errored")
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		klog.Infof("http: expected status code %d, got %d", http.StatusPartialContent, resp.StatusCode)
+		resp.Body.Close()
+		return nil, nil
+	}
+	if contentRange := resp.Header.Get("Content-Range"); !strings.HasPrefix(contentRange, fmt.Sprintf("bytes %d-", offset)) {
+		klog.Infof("http: unexpected Content-Range %q", contentRange)
+		resp.Body.Close()
+		return nil, nil
+	}
+	return resp.Body, nil
 }
 
 func (hs *HTTPDataSource) pollProgress(reader *util.CountingReader, idleTime, pollInterval time.Duration) {
@@ -574,6 +884,79 @@ func getExtraHeadersFromSecrets() ([]string, error) {
 	return secretExtraHeaders, err
 }
 
+// FetchChecksum downloads the checksum file at checksumURL and returns the checksum listed in it for the file name
+// of the endpoint, in "algorithm:hash" format. The credentials and extra headers used for the endpoint are only sent
+// along if the checksum file is hosted on the same server. When keyringFile is set, the checksum file must be
+// clearsigned by one of the armored PGP public keys it contains.
+func FetchChecksum(checksumURL, keyringFile, endpoint, accessKey, secKey, certDir string, insecureSkipVerify bool) (string, error) {
+	ep, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to parse endpoint %q", endpoint)
+	}
+	keyring, err := readChecksumKeyring(keyringFile)
+	if err != nil {
+		return "", err
+	}
+	checksumEp, err := url.Parse(checksumURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to parse checksum URL %q", checksumURL)
+	}
+	client, err := createHTTPClient(certDir, insecureSkipVerify)
+	if err != nil {
+		return "", errors.Wrap(err, "Error creating http client")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), checksumFileTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checksumEp.String(), nil)
+	if err != nil {
+		return "", errors.Wrap(err, "could not create checksum file request")
+	}
+	var allExtraHeaders []string
+	if checksumEp.Host == ep.Host {
+		extraHeaders, secretExtraHeaders, err := getExtraHeaders()
+		if err != nil {
+			return "", errors.Wrap(err, "Error getting extra headers for HTTP client")
+		}
+		allExtraHeaders = append(extraHeaders, secretExtraHeaders...)
+		if len(accessKey) > 0 && len(secKey) > 0 {
+			req.SetBasicAuth(accessKey, secKey)
+		}
+		client.CheckRedirect = checkRedirectFunc(accessKey, secKey, allExtraHeaders)
+	}
+	addExtraheaders(req, allExtraHeaders)
+
+	klog.V(1).Infof("Fetching checksum file %s", checksumEp.Redacted())
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "HTTP request for checksum file errored")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("expected status code 200 fetching checksum file, got %d. Status: %s", resp.StatusCode, resp.Status)
+	}
+
+	result, err := checksum.FindInChecksumFile(io.LimitReader(resp.Body, maxChecksumFileSize), path.Base(ep.Path), keyring)
+	if err != nil {
+		return "", err
+	}
+	klog.Infof("Found checksum %s for %s in checksum file", result, path.Base(ep.Path))
+	return result, nil
+}
+
+// readChecksumKeyring reads the keyring verifying the signature of the checksum file, if any
+func readChecksumKeyring(keyringFile string) (openpgp.EntityList, error) {
+	if keyringFile == "" {
+		return nil, nil
+	}
+	f, err := os.Open(keyringFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open checksum file keyring")
+	}
+	defer f.Close()
+	return checksum.ReadKeyring(f)
+}
+
 func getServerInfo(ctx context.Context, infoURL string) (*common.ServerInfo, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, infoURL, nil)
 	if err != nil {
@@ -1,6 +1,8 @@
 package importer
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/sha256"
 	"crypto/x509"
@@ -27,6 +29,7 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/util"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/triple"
+	"kubevirt.io/containerized-data-importer/pkg/util/checksum"
 )
 
 var (
@@ -76,14 +79,14 @@ var _ = Describe("Http data source", func() {
 	})
 
 	It("NewHTTPDataSource should fail when called with an invalid endpoint", func() {
-		_, err = NewHTTPDataSource("httpd://!@#$%^&*()dgsdd&3r53/invalid", "", "", "", cdiv1.DataVolumeKubeVirt, "", false)
+		_, err = NewHTTPDataSource("httpd://!@#$%^&*()dgsdd&3r53/invalid", "", "", "", cdiv1.DataVolumeKubeVirt, "", "", -1, false)
 		Expect(err).To(HaveOccurred())
 		Expect(strings.Contains(err.Error(), "unable to parse endpoint")).To(BeTrue())
 	})
 
 	It("NewHTTPDataSource should fail when called with an invalid certdir", func() {
 		image := ts.URL + "/" + cirrosFileName
-		_, err = NewHTTPDataSource(image, "", "", "/invaliddir", cdiv1.DataVolumeKubeVirt, "", false)
+		_, err = NewHTTPDataSource(image, "", "", "/invaliddir", cdiv1.DataVolumeKubeVirt, "", "", -1, false)
 		Expect(err).To(HaveOccurred())
 	})
 
@@ -92,7 +95,7 @@ var _ = Describe("Http data source", func() {
 		if image != "" {
 			image = ts.URL + "/" + image
 		}
-		dp, err = NewHTTPDataSource(image, "", "", "", contentType, "", false)
+		dp, err = NewHTTPDataSource(image, "", "", "", contentType, "", "", -1, false)
 		dp.brokenForQemuImg = brokenForQemuImg
 		Expect(err).NotTo(HaveOccurred())
 		newPhase, err := dp.Info()
@@ -117,7 +120,7 @@ var _ = Describe("Http data source", func() {
 		if image != "" {
 			image = ts.URL + "/" + image
 		}
-		dp, err = NewHTTPDataSource(image, "", "", "", contentType, "", false)
+		dp, err = NewHTTPDataSource(image, "", "", "", contentType, "", "", -1, false)
 		Expect(err).NotTo(HaveOccurred())
 		_, err := dp.Info()
 		Expect(err).NotTo(HaveOccurred())
@@ -152,7 +155,7 @@ var _ = Describe("Http data source", func() {
 	)
 
 	DescribeTable("should succeed when writing to a valid file with phase", func(expectedPhase ProcessingPhase, brokenForQemuImg bool, imageType string) {
-		dp, err = NewHTTPDataSource(ts.URL+"/"+imageType, "", "", "", cdiv1.DataVolumeKubeVirt, "", false)
+		dp, err = NewHTTPDataSource(ts.URL+"/"+imageType, "", "", "", cdiv1.DataVolumeKubeVirt, "", "", -1, false)
 		dp.brokenForQemuImg = brokenForQemuImg
 		Expect(err).NotTo(HaveOccurred())
 		result, err := dp.Info()
@@ -182,7 +185,7 @@ var _ = Describe("Http data source", func() {
 				w.WriteHeader(http.StatusInternalServerError)
 			}
 		}))
-		dp, err = NewHTTPDataSource(ts2.URL+"/"+tinyCoreGz, "", "", "", cdiv1.DataVolumeKubeVirt, "", false)
+		dp, err = NewHTTPDataSource(ts2.URL+"/"+tinyCoreGz, "", "", "", cdiv1.DataVolumeKubeVirt, "", "", -1, false)
 		Expect(err).NotTo(HaveOccurred())
 		_, err := dp.Info()
 		Expect(err).NotTo(HaveOccurred())
@@ -215,7 +218,7 @@ var _ = Describe("Http data source", func() {
 		It("should fail when created with invalid checksum format", func() {
 			image := ts.URL + "/" + cirrosFileName
 			// Invalid format: missing colon
-			_, err := NewHTTPDataSource(image, "", "", "", cdiv1.DataVolumeKubeVirt, "sha256abc123", false)
+			_, err := NewHTTPDataSource(image, "", "", "", cdiv1.DataVolumeKubeVirt, "sha256abc123", "", -1, false)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("invalid checksum"))
 		})
@@ -223,7 +226,7 @@ var _ = Describe("Http data source", func() {
 		It("should fail when created with unsupported checksum algorithm", func() {
 			image := ts.URL + "/" + cirrosFileName
 			// Unsupported algorithm
-			_, err := NewHTTPDataSource(image, "", "", "", cdiv1.DataVolumeKubeVirt, "crc32:12345678", false)
+			_, err := NewHTTPDataSource(image, "", "", "", cdiv1.DataVolumeKubeVirt, "crc32:12345678", "", -1, false)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("invalid checksum"))
 		})
@@ -231,7 +234,7 @@ var _ = Describe("Http data source", func() {
 		It("should succeed with valid checksum during transfer", func() {
 			image := ts.URL + "/" + cirrosFileName
 			checksum := "sha256:" + testDataSHA256
-			dp, err = NewHTTPDataSource(image, "", "", "", cdiv1.DataVolumeKubeVirt, checksum, false)
+			dp, err = NewHTTPDataSource(image, "", "", "", cdiv1.DataVolumeKubeVirt, checksum, "", -1, false)
 			Expect(err).NotTo(HaveOccurred())
 
 			_, err := dp.Info()
@@ -257,7 +260,7 @@ var _ = Describe("Http data source", func() {
 			image := ts.URL + "/" + cirrosFileName
 			// Use an incorrect checksum (all zeros)
 			incorrectChecksum := "sha256:0000000000000000000000000000000000000000000000000000000000000000"
-			dp, err = NewHTTPDataSource(image, "", "", "", cdiv1.DataVolumeKubeVirt, incorrectChecksum, false)
+			dp, err = NewHTTPDataSource(image, "", "", "", cdiv1.DataVolumeKubeVirt, incorrectChecksum, "", -1, false)
 			Expect(err).NotTo(HaveOccurred())
 
 			_, err := dp.Info()
@@ -277,7 +280,7 @@ var _ = Describe("Http data source", func() {
 			archiveChecksum := "sha256:" + archiveDataSHA256
 
 			image := ts.URL + "/" + diskimageTarFileName
-			dp, err = NewHTTPDataSource(image, "", "", "", cdiv1.DataVolumeArchive, archiveChecksum, false)
+			dp, err = NewHTTPDataSource(image, "", "", "", cdiv1.DataVolumeArchive, archiveChecksum, "", -1, false)
 			Expect(err).NotTo(HaveOccurred())
 
 			_, err := dp.Info()
@@ -292,7 +295,7 @@ var _ = Describe("Http data source", func() {
 			image := ts.URL + "/" + diskimageTarFileName
 			// Use an incorrect checksum
 			incorrectChecksum := "sha256:1111111111111111111111111111111111111111111111111111111111111111"
-			dp, err = NewHTTPDataSource(image, "", "", "", cdiv1.DataVolumeArchive, incorrectChecksum, false)
+			dp, err = NewHTTPDataSource(image, "", "", "", cdiv1.DataVolumeArchive, incorrectChecksum, "", -1, false)
 			Expect(err).NotTo(HaveOccurred())
 
 			_, err := dp.Info()
@@ -307,7 +310,7 @@ var _ = Describe("Http data source", func() {
 		It("should succeed with valid checksum during TransferFile", func() {
 			image := ts.URL + "/" + cirrosFileName
 			checksum := "sha256:" + testDataSHA256
-			dp, err = NewHTTPDataSource(image, "", "", "", cdiv1.DataVolumeKubeVirt, checksum, false)
+			dp, err = NewHTTPDataSource(image, "", "", "", cdiv1.DataVolumeKubeVirt, checksum, "", -1, false)
 			dp.brokenForQemuImg = true // Force TransferFile path
 			Expect(err).NotTo(HaveOccurred())
 
@@ -332,7 +335,7 @@ var _ = Describe("Http data source", func() {
 			image := ts.URL + "/" + cirrosFileName
 			// Use an incorrect checksum
 			incorrectChecksum := "sha256:2222222222222222222222222222222222222222222222222222222222222222"
-			dp, err = NewHTTPDataSource(image, "", "", "", cdiv1.DataVolumeKubeVirt, incorrectChecksum, false)
+			dp, err = NewHTTPDataSource(image, "", "", "", cdiv1.DataVolumeKubeVirt, incorrectChecksum, "", -1, false)
 			dp.brokenForQemuImg = true // Force TransferFile path
 			Expect(err).NotTo(HaveOccurred())
 
@@ -347,13 +350,350 @@ var _ = Describe("Http data source", func() {
 		})
 	})
 
+	Context("Resumable transfer", func() {
+		var (
+			rangeServer  *httptest.Server
+			rangeData    []byte
+			rangeHeaders []string
+		)
+
+		BeforeEach(func() {
+			flushRead = nil
+			rangeHeaders = nil
+			rangeData = make([]byte, 1<<20)
+			for i := range rangeData {
+				rangeData[i] = byte(i*7 + i/4096)
+			}
+			rangeServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				rangeHeaders = append(rangeHeaders, r.Header.Get("Range"))
+				w.Header().Set("Etag", `"v1"`)
+				http.ServeContent(w, r, "disk.img", time.Time{}, bytes.NewReader(rangeData))
+			}))
+		})
+
+		AfterEach(func() {
+			rangeServer.Close()
+		})
+
+		writePartialTransfer := func(prefix []byte, validator string) string {
+			file := filepath.Join(tmpDir, tempFile)
+			// Data after the recorded offset must be discarded.
+			data := append(bytes.Clone(prefix), []byte("garbage")...)
+			Expect(os.WriteFile(file, data, 0600)).To(Succeed())
+			Expect(writeResumeMarker(file, &resumeMarker{
+				Endpoint:      rangeServer.URL + "/disk.img",
+				Validator:     validator,
+				ContentLength: uint64(len(rangeData)),
+				Offset:        int64(len(prefix)),
+			})).To(Succeed())
+			return file
+		}
+
+		transfer := func(checksum string) (ProcessingPhase, error) {
+			dp, err = NewHTTPDataSource(rangeServer.URL+"/disk.img", "", "", "", cdiv1.DataVolumeKubeVirt, checksum, "", -1, false)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = dp.Info()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dp.resumeValidator).To(Equal(`"v1"`))
+			var phase ProcessingPhase
+			qemuOperations := NewFakeQEMUOperations(nil, nil, fakeInfoOpRetVal{&fakeZeroImageInfo, nil}, nil, nil, nil)
+			replaceQEMUOperations(qemuOperations, func() {
+				phase, err = dp.Transfer(tmpDir, false)
+			})
+			return phase, err
+		}
+
+		It("should resume at the recorded offset", func() {
+			// Zeroes instead of the real data prove the prefix was not transferred again.
+			file := writePartialTransfer(make([]byte, 4096), `"v1"`)
+			phase, err := transfer("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phase).To(Equal(ProcessingPhaseConvert))
+			Expect(rangeHeaders).To(ContainElement("bytes=4096-"))
+			result, err := os.ReadFile(file)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result[:4096]).To(Equal(make([]byte, 4096)))
+			Expect(result[4096:]).To(Equal(rangeData[4096:]))
+			Expect(resumeMarkerPath(file)).ToNot(BeAnExistingFile())
+		})
+
+		It("should validate the checksum over previously transferred data", func() {
+			hash := sha256.Sum256(rangeData)
+			file := writePartialTransfer(rangeData[:512<<10], `"v1"`)
+			phase, err := transfer("sha256:" + hex.EncodeToString(hash[:]))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phase).To(Equal(ProcessingPhaseConvert))
+			result, err := os.ReadFile(file)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(rangeData))
+		})
+
+		It("should start over when the source changed", func() {
+			file := writePartialTransfer(make([]byte, 4096), `"v0"`)
+			phase, err := transfer("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phase).To(Equal(ProcessingPhaseConvert))
+			Expect(rangeHeaders).ToNot(ContainElement("bytes=4096-"))
+			result, err := os.ReadFile(file)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(rangeData))
+		})
+
+		It("should record progress while transferring", func() {
+			origInterval := resumeCheckpointInterval
+			resumeCheckpointInterval = 64 << 10
+			defer func() {
+				resumeCheckpointInterval = origInterval
+			}()
+			dp, err = NewHTTPDataSource(rangeServer.URL+"/disk.img", "", "", "", cdiv1.DataVolumeKubeVirt, "", "", -1, false)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = dp.Info()
+			Expect(err).NotTo(HaveOccurred())
+			file := filepath.Join(tmpDir, tempFile)
+			Expect(dp.transferResumable(file, 0, false)).To(Succeed())
+			marker, err := readResumeMarker(file)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(marker).ToNot(BeNil())
+			Expect(marker.Validator).To(Equal(`"v1"`))
+			Expect(marker.Offset).To(BeNumerically(">=", resumeCheckpointInterval))
+			Expect(marker.Offset).To(BeNumerically("<", len(rangeData)))
+		})
+	})
+
+	Context("OVA appliance", func() {
+		var (
+			ovaServer *httptest.Server
+			ovaData   []byte
+		)
+
+		BeforeEach(func() {
+			flushRead = nil
+			var compressed bytes.Buffer
+			gzw := gzip.NewWriter(&compressed)
+			_, err := gzw.Write(cirrosData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gzw.Close()).To(Succeed())
+			ovf := strings.Replace(testOVF, `ovf:href="appliance-disk2.vmdk"`, `ovf:href="appliance-disk2.vmdk" ovf:compression="gzip"`, 1)
+			ovaData = createOVA(
+				ovaFile{name: "appliance.ovf", data: []byte(ovf)},
+				ovaFile{name: "appliance-disk1.vmdk", data: cirrosData},
+				ovaFile{name: "appliance-disk2.vmdk", data: compressed.Bytes()},
+			)
+			ovaServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.ServeContent(w, r, "appliance.ova", time.Time{}, bytes.NewReader(ovaData))
+			}))
+		})
+
+		AfterEach(func() {
+			ovaServer.Close()
+		})
+
+		transferOVA := func(diskPath string, diskIndex int, checksum string) (ProcessingPhase, error) {
+			dp, err = NewHTTPDataSource(ovaServer.URL+"/appliance.ova", "", "", "", cdiv1.DataVolumeKubeVirt, checksum, diskPath, diskIndex, false)
+			Expect(err).NotTo(HaveOccurred())
+			phase, err := dp.Info()
+			if err != nil {
+				return phase, err
+			}
+			Expect(phase).To(Equal(ProcessingPhaseValidatePreScratch))
+			return dp.Transfer(tmpDir, false)
+		}
+
+		DescribeTable("should extract the selected disk to scratch space", func(diskPath string, diskIndex int, capacity int64) {
+			phase, err := transferOVA(diskPath, diskIndex, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phase).To(Equal(ProcessingPhaseConvert))
+			Expect(dp.GetVirtualSize()).To(Equal(capacity))
+			Expect(dp.GetURL().String()).To(Equal(filepath.Join(tmpDir, tempFile)))
+			Expect(os.ReadFile(filepath.Join(tmpDir, tempFile))).To(Equal(cirrosData))
+		},
+			Entry("by default", "", -1, int64(16<<30)),
+			Entry("by path with gzip compression", "appliance-disk2.vmdk", -1, int64(8<<30)),
+			Entry("by index with gzip compression", "", 1, int64(8<<30)),
+		)
+
+		It("should validate the checksum of the appliance", func() {
+			hash := sha256.Sum256(ovaData)
+			phase, err := transferOVA("", -1, "sha256:"+hex.EncodeToString(hash[:]))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phase).To(Equal(ProcessingPhaseConvert))
+		})
+
+		It("should fail on a checksum mismatch", func() {
+			phase, err := transferOVA("", -1, "sha256:"+strings.Repeat("0", 64))
+			Expect(err).To(MatchError(ContainSubstring("checksum validation failed")))
+			Expect(phase).To(Equal(ProcessingPhaseError))
+		})
+
+		It("should fail if the disk is not in the descriptor", func() {
+			phase, err := transferOVA("appliance-disk4.vmdk", -1, "")
+			Expect(err).To(MatchError(ContainSubstring("OVA disk file appliance-disk4.vmdk not found")))
+			Expect(phase).To(Equal(ProcessingPhaseError))
+		})
+	})
+
+	Context("Tar archive", func() {
+		var (
+			archiveServer *httptest.Server
+			archiveData   []byte
+		)
+
+		BeforeEach(func() {
+			flushRead = nil
+			archiveData = createOVA(
+				ovaFile{name: "README", data: []byte("readme")},
+				ovaFile{name: "disks/root.img", data: []byte("root")},
+				ovaFile{name: "disks/data.qcow2", data: cirrosData},
+			)
+			archiveServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.ServeContent(w, r, "disks.tar", time.Time{}, bytes.NewReader(archiveData))
+			}))
+		})
+
+		AfterEach(func() {
+			archiveServer.Close()
+		})
+
+		transferArchive := func(diskPath string, diskIndex int, checksum string) (ProcessingPhase, error) {
+			dp, err = NewHTTPDataSource(archiveServer.URL+"/disks.tar", "", "", "", cdiv1.DataVolumeKubeVirt, checksum, diskPath, diskIndex, false)
+			Expect(err).NotTo(HaveOccurred())
+			phase, err := dp.Info()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phase).To(Equal(ProcessingPhaseTransferScratch))
+			return dp.Transfer(tmpDir, false)
+		}
+
+		DescribeTable("should extract the selected disk to scratch space", func(diskPath string, diskIndex int) {
+			phase, err := transferArchive(diskPath, diskIndex, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phase).To(Equal(ProcessingPhaseConvert))
+			Expect(dp.GetURL().String()).To(Equal(filepath.Join(tmpDir, tempFile)))
+			Expect(os.ReadFile(filepath.Join(tmpDir, tempFile))).To(Equal(cirrosData))
+		},
+			Entry("by path", "disks/data.qcow2", -1),
+			Entry("by path with a ./ prefix", "./disks/data.qcow2", -1),
+			Entry("by index", "", 2),
+		)
+
+		It("should validate the checksum of the archive", func() {
+			hash := sha256.Sum256(archiveData)
+			phase, err := transferArchive("disks/data.qcow2", -1, "sha256:"+hex.EncodeToString(hash[:]))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phase).To(Equal(ProcessingPhaseConvert))
+		})
+
+		DescribeTable("should fail if the disk is not in the archive", func(diskPath string, diskIndex int, expected string) {
+			phase, err := transferArchive(diskPath, diskIndex, "")
+			Expect(err).To(MatchError(ContainSubstring(expected)))
+			Expect(phase).To(Equal(ProcessingPhaseError))
+		},
+			Entry("by path", "disks/missing.img", -1, "disk disks/missing.img not found in the archive"),
+			Entry("by index", "", 3, "disk at index 3 not found in the archive"),
+		)
+
+		DescribeTable("should fail to select a disk if the endpoint is not an archive", func(diskPath string, diskIndex int, expected string) {
+			archiveData = bytes.Repeat([]byte{0x55}, 1024)
+			dp, err = NewHTTPDataSource(archiveServer.URL+"/disk.img", "", "", "", cdiv1.DataVolumeKubeVirt, "", diskPath, diskIndex, false)
+			Expect(err).NotTo(HaveOccurred())
+			phase, err := dp.Info()
+			Expect(err).To(MatchError(ContainSubstring(expected)))
+			Expect(phase).To(Equal(ProcessingPhaseError))
+		},
+			Entry("by path", "disks/data.qcow2", -1, "disk disks/data.qcow2 selected, but the endpoint is not a tar archive"),
+			Entry("by index", "", 0, "disk at index 0 selected, but the endpoint is not a tar archive"),
+		)
+
+		It("should extract all the files with the archive content type", func() {
+			dp, err = NewHTTPDataSource(archiveServer.URL+"/disks.tar", "", "", "", cdiv1.DataVolumeArchive, "", "", -1, false)
+			Expect(err).NotTo(HaveOccurred())
+			phase, err := dp.Info()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phase).To(Equal(ProcessingPhaseTransferDataDir))
+			phase, err = dp.Transfer(tmpDir, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phase).To(Equal(ProcessingPhaseComplete))
+			Expect(os.ReadFile(filepath.Join(tmpDir, "README"))).To(Equal([]byte("readme")))
+			Expect(os.ReadFile(filepath.Join(tmpDir, "disks", "data.qcow2"))).To(Equal(cirrosData))
+		})
+
+		It("should reject entries outside of the target directory with the archive content type", func() {
+			archiveData = createOVA(
+				ovaFile{name: "README", data: []byte("readme")},
+				ovaFile{name: "../escaped", data: []byte("escaped")},
+			)
+			target := filepath.Join(tmpDir, "target")
+			Expect(os.Mkdir(target, 0755)).To(Succeed())
+			dp, err = NewHTTPDataSource(archiveServer.URL+"/disks.tar", "", "", "", cdiv1.DataVolumeArchive, "", "", -1, false)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = dp.Info()
+			Expect(err).NotTo(HaveOccurred())
+			phase, err := dp.Transfer(target, false)
+			Expect(err).To(MatchError(ErrInvalidArchive))
+			Expect(phase).To(Equal(ProcessingPhaseError))
+			Expect(filepath.Join(tmpDir, "escaped")).ToNot(BeAnExistingFile())
+			Expect(filepath.Join(target, "README")).ToNot(BeAnExistingFile())
+		})
+	})
+
+	Context("Checksum file", func() {
+		const imageSHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+		var (
+			sumsServer  *httptest.Server
+			authHeaders []string
+		)
+
+		BeforeEach(func() {
+			authHeaders = nil
+			sumsServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+				switch r.URL.Path {
+				case "/images/SHA256SUMS":
+					fmt.Fprintf(w, "%s  other.img\n%s  disk.img\n", strings.Repeat("0", 64), imageSHA256)
+				case "/images/CHECKSUM":
+					fmt.Fprintf(w, "SHA256 (other.img) = %s\n", imageSHA256)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+		})
+
+		AfterEach(func() {
+			sumsServer.Close()
+		})
+
+		It("should return the checksum of the image", func() {
+			result, err := FetchChecksum(sumsServer.URL+"/images/SHA256SUMS", "", sumsServer.URL+"/images/disk.img", "user", "pass", "", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal("sha256:" + imageSHA256))
+			Expect(authHeaders).To(ConsistOf(Not(BeEmpty())))
+		})
+
+		It("should not send credentials to a different host", func() {
+			result, err := FetchChecksum(sumsServer.URL+"/images/SHA256SUMS", "", "http://images.example.com/images/disk.img", "user", "pass", "", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal("sha256:" + imageSHA256))
+			Expect(authHeaders).To(ConsistOf(BeEmpty()))
+		})
+
+		It("should fail when the image is not listed", func() {
+			_, err := FetchChecksum(sumsServer.URL+"/images/CHECKSUM", "", sumsServer.URL+"/images/disk.img", "", "", "", false)
+			Expect(err).To(MatchError(checksum.ErrFileNotListed))
+		})
+
+		It("should fail when the checksum file doesn't exist", func() {
+			_, err := FetchChecksum(sumsServer.URL+"/images/MD5SUMS", "", sumsServer.URL+"/images/disk.img", "", "", "", false)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("404"))
+		})
+	})
+
 	It("GetTerminationMessage should return nil when pullMethod is not node", func() {
 		Expect(os.Setenv(common.ImporterPullMethod, string(cdiv1.RegistryPullPod))).To(Succeed())
 		DeferCleanup(func() {
 			Expect(os.Unsetenv(common.ImporterPullMethod)).To(Succeed())
 		})
 
-		dp, err = NewHTTPDataSource(ts.URL+"/"+tinyCoreGz, "", "", "", cdiv1.DataVolumeKubeVirt, "", false)
+		dp, err = NewHTTPDataSource(ts.URL+"/"+tinyCoreGz, "", "", "", cdiv1.DataVolumeKubeVirt, "", "", -1, false)
 		Expect(err).NotTo(HaveOccurred())
 
 		termMsg := dp.GetTerminationMessage()
@@ -368,7 +708,7 @@ var _ = Describe("Http data source", func() {
 
 		ts2 := createTestServer(imageDir, emptyEnv)
 
-		dp, err = NewHTTPDataSource(ts2.URL+"/"+tinyCoreGz, "", "", "", cdiv1.DataVolumeKubeVirt, "", false)
+		dp, err = NewHTTPDataSource(ts2.URL+"/"+tinyCoreGz, "", "", "", cdiv1.DataVolumeKubeVirt, "", "", -1, false)
 		Expect(err).NotTo(HaveOccurred())
 
 		termMsg := dp.GetTerminationMessage()
@@ -390,7 +730,7 @@ var _ = Describe("Http data source", func() {
 			"INSTANCETYPE_KUBEVIRT_IO_DEFAULT_PREFERENCE=fedora",
 		})
 
-		dp, err = NewHTTPDataSource(ts2.URL+"/"+tinyCoreGz, "", "", "", cdiv1.DataVolumeKubeVirt, "", false)
+		dp, err = NewHTTPDataSource(ts2.URL+"/"+tinyCoreGz, "", "", "", cdiv1.DataVolumeKubeVirt, "", "", -1, false)
 		Expect(err).NotTo(HaveOccurred())
 
 		termMsg := dp.GetTerminationMessage()
@@ -466,7 +806,7 @@ var _ = Describe("Http client", func() {
 
 var _ = Describe("Http reader", func() {
 	It("should fail when passed an invalid cert directory", func() {
-		_, total, _, err := createHTTPReader(context.Background(), nil, "", "", "/invalid", nil, nil, cdiv1.DataVolumeKubeVirt, false)
+		_, total, _, _, err := createHTTPReader(context.Background(), nil, "", "", "/invalid", nil, nil, cdiv1.DataVolumeKubeVirt, false)
 		Expect(err).To(HaveOccurred())
 		Expect(uint64(0)).To(Equal(total))
 	})
@@ -483,7 +823,7 @@ var _ = Describe("Http reader", func() {
 		defer ts.Close()
 		ep, err := url.Parse(ts.URL)
 		Expect(err).ToNot(HaveOccurred())
-		r, total, _, err := createHTTPReader(context.Background(), ep, "user", "password", "", nil, nil, cdiv1.DataVolumeKubeVirt, false)
+		r, total, _, _, err := createHTTPReader(context.Background(), ep, "user", "password", "", nil, nil, cdiv1.DataVolumeKubeVirt, false)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(uint64(25)).To(Equal(total))
 		err = r.Close()
@@ -506,7 +846,7 @@ var _ = Describe("Http reader", func() {
 		defer ts.Close()
 		ep, err := url.Parse(ts.URL)
 		Expect(err).ToNot(HaveOccurred())
-		r, total, _, err := createHTTPReader(context.Background(), ep, "user", "password", "", nil, nil, cdiv1.DataVolumeKubeVirt, false)
+		r, total, _, _, err := createHTTPReader(context.Background(), ep, "user", "password", "", nil, nil, cdiv1.DataVolumeKubeVirt, false)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(uint64(25)).To(Equal(total))
 		err = r.Close()
@@ -528,7 +868,7 @@ var _ = Describe("Http reader", func() {
 		defer ts.Close()
 		ep, err := url.Parse(ts.URL)
 		Expect(err).ToNot(HaveOccurred())
-		r, total, brokenForQemuImg, err := createHTTPReader(context.Background(), ep, "", "", "", nil, nil, cdiv1.DataVolumeKubeVirt, false)
+		r, total, brokenForQemuImg, _, err := createHTTPReader(context.Background(), ep, "", "", "", nil, nil, cdiv1.DataVolumeKubeVirt, false)
 		Expect(brokenForQemuImg).To(BeFalse())
 		Expect(err).ToNot(HaveOccurred())
 		Expect(uint64(25)).To(Equal(total))
@@ -549,7 +889,7 @@ var _ = Describe("Http reader", func() {
 		defer ts.Close()
 		ep, err := url.Parse(ts.URL)
 		Expect(err).ToNot(HaveOccurred())
-		r, total, _, err := createHTTPReader(context.Background(), ep, "", "", "", nil, nil, cdiv1.DataVolumeKubeVirt, false)
+		r, total, _, _, err := createHTTPReader(context.Background(), ep, "", "", "", nil, nil, cdiv1.DataVolumeKubeVirt, false)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(uint64(0)).To(Equal(total))
 		err = r.Close()
@@ -573,7 +913,7 @@ var _ = Describe("Http reader", func() {
 		defer ts.Close()
 		ep, err := url.Parse(ts.URL)
 		Expect(err).ToNot(HaveOccurred())
-		r, total, brokenForQemuImg, err := createHTTPReader(context.Background(), ep, "", "", "", nil, nil, cdiv1.DataVolumeKubeVirt, false)
+		r, total, brokenForQemuImg, _, err := createHTTPReader(context.Background(), ep, "", "", "", nil, nil, cdiv1.DataVolumeKubeVirt, false)
 		Expect(brokenForQemuImg).To(BeTrue())
 		Expect(err).ToNot(HaveOccurred())
 		Expect(uint64(25)).To(Equal(total))
@@ -593,7 +933,7 @@ var _ = Describe("Http reader", func() {
 		defer ts.Close()
 		ep, err := url.Parse(ts.URL)
 		Expect(err).ToNot(HaveOccurred())
-		r, total, brokenForQemuImg, err := createHTTPReader(context.Background(), ep, "", "", "", nil, nil, cdiv1.DataVolumeKubeVirt, false)
+		r, total, brokenForQemuImg, _, err := createHTTPReader(context.Background(), ep, "", "", "", nil, nil, cdiv1.DataVolumeKubeVirt, false)
 		Expect(brokenForQemuImg).To(BeTrue())
 		Expect(err).ToNot(HaveOccurred())
 		Expect(uint64(25)).To(Equal(total))
@@ -608,7 +948,7 @@ var _ = Describe("Http reader", func() {
 		defer ts.Close()
 		ep, err := url.Parse(ts.URL)
 		Expect(err).ToNot(HaveOccurred())
-		_, total, _, err := createHTTPReader(context.Background(), ep, "", "", "", nil, nil, cdiv1.DataVolumeKubeVirt, false)
+		_, total, _, _, err := createHTTPReader(context.Background(), ep, "", "", "", nil, nil, cdiv1.DataVolumeKubeVirt, false)
 		Expect(err).To(HaveOccurred())
 		Expect(uint64(0)).To(Equal(total))
 		Expect("expected status code 200, got 500. Status: 500 Internal Server Error").To(Equal(err.Error()))
@@ -625,7 +965,7 @@ var _ = Describe("Http reader", func() {
 		defer ts.Close()
 		ep, err := url.Parse(ts.URL)
 		Expect(err).ToNot(HaveOccurred())
-		r, total, _, err := createHTTPReader(context.Background(), ep, "", "", "", []string{"Extra-Header: 123"}, nil, cdiv1.DataVolumeKubeVirt, false)
+		r, total, _, _, err := createHTTPReader(context.Background(), ep, "", "", "", []string{"Extra-Header: 123"}, nil, cdiv1.DataVolumeKubeVirt, false)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(uint64(0)).To(Equal(total))
 		err = r.Close()
@@ -639,7 +979,7 @@ var _ = Describe("Http reader", func() {
 		defer ts.Close()
 		ep, err := url.Parse(ts.URL)
 		Expect(err).ToNot(HaveOccurred())
-		_, total, _, err := createHTTPReader(context.Background(), ep, "", "", "", nil, nil, cdiv1.DataVolumeKubeVirt, false)
+		_, total, _, _, err := createHTTPReader(context.Background(), ep, "", "", "", nil, nil, cdiv1.DataVolumeKubeVirt, false)
 		Expect(err).To(HaveOccurred())
 		Expect(uint64(0)).To(Equal(total))
 	})
@@ -651,7 +991,7 @@ var _ = Describe("Http reader", func() {
 		defer ts.Close()
 		ep, err := url.Parse(ts.URL)
 		Expect(err).ToNot(HaveOccurred())
-		_, total, _, err := createHTTPReader(context.Background(), ep, "", "", "", nil, nil, cdiv1.DataVolumeKubeVirt, true)
+		_, total, _, _, err := createHTTPReader(context.Background(), ep, "", "", "", nil, nil, cdiv1.DataVolumeKubeVirt, true)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(uint64(0)).To(Equal(total))
 	})
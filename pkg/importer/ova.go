@@ -0,0 +1,231 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"archive/tar"
+	"encoding/xml"
+	"io"
+	"math/big"
+	"path"
+	"strconv"
+	"strings"
+
+	gzip "github.com/klauspost/pgzip"
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// maxOVFDescriptorSize is the maximum size of the OVF descriptor of an OVA appliance.
+	maxOVFDescriptorSize = 4 << 20
+	// ovfCompressionGzip is the compression attribute of files compressed with gzip
+	ovfCompressionGzip = "gzip"
+)
+
+// ovfEnvelope is the part of an OVF descriptor needed to locate the disks of an appliance.
+// https://www.dmtf.org/sites/default/files/standards/documents/DSP0243_2.1.1.pdf
+type ovfEnvelope struct {
+	Files []ovfFile `xml:"References>File"`
+	Disks []ovfDisk `xml:"DiskSection>Disk"`
+}
+
+type ovfFile struct {
+	ID          string `xml:"id,attr"`
+	Href        string `xml:"href,attr"`
+	Compression string `xml:"compression,attr"`
+	ChunkSize   string `xml:"chunkSize,attr"`
+}
+
+type ovfDisk struct {
+	DiskID                  string `xml:"diskId,attr"`
+	FileRef                 string `xml:"fileRef,attr"`
+	Capacity                string `xml:"capacity,attr"`
+	CapacityAllocationUnits string `xml:"capacityAllocationUnits,attr"`
+}
+
+// ovaDisk is the disk selected for import from an OVA appliance.
+type ovaDisk struct {
+	// name is the name of the disk file in the appliance.
+	name string
+	// compression is the compression of the disk file, empty if it is not compressed.
+	compression string
+	// capacity is the virtual size of the disk in bytes.
+	capacity int64
+}
+
+// isOVFDescriptor returns true if the name of a tar entry is an OVF descriptor.
+func isOVFDescriptor(name string) bool {
+	return strings.EqualFold(path.Ext(name), ".ovf")
+}
+
+// parseOVF parses an OVF descriptor.
+func parseOVF(r io.Reader) (*ovfEnvelope, error) {
+	envelope := &ovfEnvelope{}
+	if err := xml.NewDecoder(io.LimitReader(r, maxOVFDescriptorSize)).Decode(envelope); err != nil {
+		return nil, errors.Wrap(err, "unable to parse OVF descriptor")
+	}
+	return envelope, nil
+}
+
+// selectDisk returns the disk at diskIndex in the disk section, or the disk whose file is at diskPath in the appliance,
+// or the first disk with a file if neither is set.
+func (e *ovfEnvelope) selectDisk(diskPath string, diskIndex int) (*ovaDisk, error) {
+	if len(e.Disks) == 0 {
+		return nil, errors.New("OVF descriptor has no disks")
+	}
+	if diskIndex >= 0 {
+		if diskIndex >= len(e.Disks) {
+			return nil, errors.Errorf("OVA disk index %d out of range, the OVF descriptor has %d disks", diskIndex, len(e.Disks))
+		}
+		disk := e.Disks[diskIndex]
+		file := e.file(disk.FileRef)
+		if file == nil {
+			return nil, errors.Errorf("OVA disk %d (%s) has no file", diskIndex, disk.DiskID)
+		}
+		return newOVADisk(disk, file)
+	}
+	for _, disk := range e.Disks {
+		file := e.file(disk.FileRef)
+		if file == nil {
+			// Disks without a file are created empty when the appliance is deployed
+			continue
+		}
+		if diskPath != "" && path.Clean(diskPath) != path.Clean(file.Href) {
+			continue
+		}
+		return newOVADisk(disk, file)
+	}
+	if diskPath == "" {
+		return nil, errors.New("OVF descriptor has no disk with a file")
+	}
+	return nil, errors.Errorf("OVA disk file %s not found in the OVF descriptor", diskPath)
+}
+
+func newOVADisk(disk ovfDisk, file *ovfFile) (*ovaDisk, error) {
+	if file.ChunkSize != "" {
+		return nil, errors.Errorf("OVA disk file %s is split in chunks, which is not supported", file.Href)
+	}
+	if file.Compression != "" && file.Compression != ovfCompressionGzip {
+		return nil, errors.Errorf("OVA disk file %s has unsupported compression %s", file.Href, file.Compression)
+	}
+	capacity, err := parseOVFCapacity(disk.Capacity, disk.CapacityAllocationUnits)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid capacity of OVA disk %s", disk.DiskID)
+	}
+	return &ovaDisk{name: file.Href, compression: file.Compression, capacity: capacity}, nil
+}
+
+func (e *ovfEnvelope) file(id string) *ovfFile {
+	for i := range e.Files {
+		if e.Files[i].ID == id {
+			return &e.Files[i]
+		}
+	}
+	return nil
+}
+
+// parseOVFCapacity returns the capacity in bytes, from the capacity and the programmatic units of the OVF descriptor,
+// e.g. "byte * 2^30".
+func parseOVFCapacity(capacity, units string) (int64, error) {
+	value, ok := new(big.Int).SetString(strings.TrimSpace(capacity), 10)
+	if !ok || value.Sign() <= 0 {
+		return 0, errors.Errorf("invalid capacity %q", capacity)
+	}
+	if units != "" {
+		factors := strings.Split(units, "*")
+		if strings.TrimSpace(factors[0]) != "byte" {
+			return 0, errors.Errorf("invalid capacity units %q", units)
+		}
+		for _, factor := range factors[1:] {
+			multiplier, err := parseOVFUnitFactor(strings.TrimSpace(factor))
+			if err != nil {
+				return 0, errors.Wrapf(err, "invalid capacity units %q", units)
+			}
+			value.Mul(value, multiplier)
+		}
+	}
+	if !value.IsInt64() {
+		return 0, errors.Errorf("capacity %s %s is too large", capacity, units)
+	}
+	return value.Int64(), nil
+}
+
+// parseOVFUnitFactor parses a factor of programmatic units, either a number or a power like 2^30.
+func parseOVFUnitFactor(factor string) (*big.Int, error) {
+	base, exponent, isPower := strings.Cut(factor, "^")
+	b, err := strconv.ParseInt(strings.TrimSpace(base), 10, 64)
+	if err != nil || b <= 0 {
+		return nil, errors.Errorf("invalid factor %q", factor)
+	}
+	if !isPower {
+		return big.NewInt(b), nil
+	}
+	e, err := strconv.ParseInt(strings.TrimSpace(exponent), 10, 64)
+	if err != nil || e < 0 || e > 64 {
+		return nil, errors.Errorf("invalid factor %q", factor)
+	}
+	return new(big.Int).Exp(big.NewInt(b), big.NewInt(e), nil), nil
+}
+
+// readOVADescriptor reads the OVF descriptor, which is the first file of an OVA appliance, and selects the disk to
+// import, see selectDisk.
+func readOVADescriptor(tarReader *tar.Reader, diskPath string, diskIndex int) (*ovaDisk, error) {
+	hdr, err := tarReader.Next()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read OVA appliance")
+	}
+	if !isOVFDescriptor(hdr.Name) {
+		return nil, errors.Errorf("OVA appliance does not start with an OVF descriptor: %s", hdr.Name)
+	}
+	envelope, err := parseOVF(tarReader)
+	if err != nil {
+		return nil, err
+	}
+	disk, err := envelope.selectDisk(diskPath, diskIndex)
+	if err != nil {
+		return nil, err
+	}
+	klog.Infof("Selected OVA disk file %s, capacity %d bytes", disk.name, disk.capacity)
+	return disk, nil
+}
+
+// findOVADisk advances the tar reader to the file of the disk, and returns a reader of its uncompressed content.
+func findOVADisk(tarReader *tar.Reader, disk *ovaDisk) (io.ReadCloser, error) {
+	for {
+		hdr, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.Errorf("OVA disk file %s not found in the appliance", disk.name)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read OVA appliance")
+		}
+		if hdr.Typeflag != tar.TypeReg || path.Clean(hdr.Name) != path.Clean(disk.name) {
+			klog.V(3).Infof("Skipping OVA file %s", hdr.Name)
+			continue
+		}
+		if disk.compression == ovfCompressionGzip {
+			gz, err := gzip.NewReader(tarReader)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not create gzip reader")
+			}
+			return gz, nil
+		}
+		return io.NopCloser(tarReader), nil
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const testOVF = `<?xml version="1.0" encoding="UTF-8"?>
+<Envelope vmw:buildId="build-123" xmlns="http://schemas.dmtf.org/ovf/envelope/1" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1" xmlns:vmw="http://www.vmware.com/schema/ovf">
+  <References>
+    <File ovf:href="appliance-disk1.vmdk" ovf:id="file1" ovf:size="1024"/>
+    <File ovf:href="appliance-disk2.vmdk" ovf:id="file2" ovf:size="2048"/>
+  </References>
+  <DiskSection>
+    <Info>Virtual disk information</Info>
+    <Disk ovf:capacity="16" ovf:capacityAllocationUnits="byte * 2^30" ovf:diskId="vmdisk1" ovf:fileRef="file1" ovf:format="http://www.vmware.com/interfaces/specifications/vmdk.html#streamOptimized"/>
+    <Disk ovf:capacity="8" ovf:capacityAllocationUnits="byte * 2^30" ovf:diskId="vmdisk2" ovf:fileRef="file2" ovf:format="http://www.vmware.com/interfaces/specifications/vmdk.html#streamOptimized"/>
+    <Disk ovf:capacity="1073741824" ovf:diskId="vmdisk3"/>
+  </DiskSection>
+  <VirtualSystem ovf:id="appliance">
+    <Info>A virtual machine</Info>
+  </VirtualSystem>
+</Envelope>`
+
+type ovaFile struct {
+	name string
+	data []byte
+}
+
+// createOVA returns an OVA appliance containing the files, in order
+func createOVA(files ...ovaFile) []byte {
+	var ova bytes.Buffer
+	tw := tar.NewWriter(&ova)
+	for _, file := range files {
+		Expect(tw.WriteHeader(&tar.Header{Name: file.name, Mode: 0644, Size: int64(len(file.data)), Typeflag: tar.TypeReg})).To(Succeed())
+		_, err := tw.Write(file.data)
+		Expect(err).ToNot(HaveOccurred())
+	}
+	Expect(tw.Close()).To(Succeed())
+	return ova.Bytes()
+}
+
+var _ = Describe("OVF descriptor", func() {
+	DescribeTable("should select a disk", func(diskPath string, diskIndex int, expected *ovaDisk) {
+		envelope, err := parseOVF(strings.NewReader(testOVF))
+		Expect(err).ToNot(HaveOccurred())
+		disk, err := envelope.selectDisk(diskPath, diskIndex)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(disk).To(Equal(expected))
+	},
+		Entry("by default", "", -1, &ovaDisk{name: "appliance-disk1.vmdk", capacity: 16 << 30}),
+		Entry("by path", "appliance-disk2.vmdk", -1, &ovaDisk{name: "appliance-disk2.vmdk", capacity: 8 << 30}),
+		Entry("by path with a ./ prefix", "./appliance-disk2.vmdk", -1, &ovaDisk{name: "appliance-disk2.vmdk", capacity: 8 << 30}),
+		Entry("by index", "", 1, &ovaDisk{name: "appliance-disk2.vmdk", capacity: 8 << 30}),
+	)
+
+	DescribeTable("should fail to select a disk", func(ovf, diskPath string, diskIndex int, expected string) {
+		envelope, err := parseOVF(strings.NewReader(ovf))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = envelope.selectDisk(diskPath, diskIndex)
+		Expect(err).To(MatchError(ContainSubstring(expected)))
+	},
+		Entry("with a path that does not exist", testOVF, "appliance-disk4.vmdk", -1, "OVA disk file appliance-disk4.vmdk not found"),
+		Entry("with an index out of range", testOVF, "", 3, "OVA disk index 3 out of range"),
+		Entry("without a file", testOVF, "", 2, "OVA disk 2 (vmdisk3) has no file"),
+		Entry("without disks", `<Envelope><References/></Envelope>`, "", -1, "OVF descriptor has no disks"),
+		Entry("split in chunks",
+			`<Envelope><References><File href="disk.vmdk" id="file1" chunkSize="1024"/></References><DiskSection><Disk diskId="vmdisk1" fileRef="file1" capacity="1"/></DiskSection></Envelope>`,
+			"", -1, "split in chunks"),
+		Entry("with unsupported compression",
+			`<Envelope><References><File href="disk.vmdk" id="file1" compression="bzip2"/></References><DiskSection><Disk diskId="vmdisk1" fileRef="file1" capacity="1"/></DiskSection></Envelope>`,
+			"", -1, "unsupported compression bzip2"),
+	)
+
+	It("should fail to parse an invalid descriptor", func() {
+		_, err := parseOVF(strings.NewReader("<Envelope><References>"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("should parse the capacity", func(capacity, units string, expected int64) {
+		Expect(parseOVFCapacity(capacity, units)).To(Equal(expected))
+	},
+		Entry("without units", "1048576", "", int64(1048576)),
+		Entry("in bytes", "1048576", "byte", int64(1048576)),
+		Entry("in GiB", "16", "byte * 2^30", int64(16<<30)),
+		Entry("in KB", "4", "byte * 10^3", int64(4000)),
+		Entry("with several factors", "3", "byte * 2^10 * 512", int64(3*1024*512)),
+	)
+
+	DescribeTable("should fail to parse the capacity", func(capacity, units string) {
+		_, err := parseOVFCapacity(capacity, units)
+		Expect(err).To(HaveOccurred())
+	},
+		Entry("that is not a number", "${disk.size}", ""),
+		Entry("that is zero", "0", "byte"),
+		Entry("in bits", "16", "bit * 2^30"),
+		Entry("with an invalid factor", "16", "byte * two"),
+		Entry("that overflows", "16", "byte * 2^64"),
+	)
+})
+
+var _ = Describe("OVA appliance", func() {
+	It("should read the descriptor and find the disk", func() {
+		ova := createOVA(
+			ovaFile{name: "appliance.ovf", data: []byte(testOVF)},
+			ovaFile{name: "appliance.mf", data: []byte("SHA256(appliance.ovf)= 0000")},
+			ovaFile{name: "appliance-disk1.vmdk", data: []byte("disk1")},
+			ovaFile{name: "appliance-disk2.vmdk", data: []byte("disk2")},
+		)
+		tarReader := tar.NewReader(bytes.NewReader(ova))
+		disk, err := readOVADescriptor(tarReader, "appliance-disk2.vmdk", -1)
+		Expect(err).ToNot(HaveOccurred())
+		reader, err := findOVADisk(tarReader, disk)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(io.ReadAll(reader)).To(Equal([]byte("disk2")))
+	})
+
+	It("should fail if the appliance does not start with the descriptor", func() {
+		ova := createOVA(
+			ovaFile{name: "appliance-disk1.vmdk", data: []byte("disk1")},
+			ovaFile{name: "appliance.ovf", data: []byte(testOVF)},
+		)
+		_, err := readOVADescriptor(tar.NewReader(bytes.NewReader(ova)), "", -1)
+		Expect(err).To(MatchError(ContainSubstring("does not start with an OVF descriptor")))
+	})
+
+	It("should fail if the disk file is missing", func() {
+		ova := createOVA(ovaFile{name: "appliance.ovf", data: []byte(testOVF)})
+		tarReader := tar.NewReader(bytes.NewReader(ova))
+		disk, err := readOVADescriptor(tarReader, "", -1)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = findOVADisk(tarReader, disk)
+		Expect(err).To(MatchError(ContainSubstring("OVA disk file appliance-disk1.vmdk not found")))
+	})
+
+	It("should be detected by the format readers", func() {
+		ova := createOVA(
+			ovaFile{name: "appliance.ovf", data: []byte(testOVF)},
+			ovaFile{name: "appliance-disk1.vmdk", data: []byte("disk1")},
+		)
+		fr, err := NewFormatReaders(io.NopCloser(bytes.NewReader(ova)), 0, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fr.OVA).To(BeTrue())
+		Expect(fr.Tar).To(BeTrue())
+		Expect(fr.Convert).To(BeFalse())
+	})
+
+	It("should not detect other tar archives", func() {
+		archive := createOVA(ovaFile{name: "disk.img", data: []byte("disk")})
+		fr, err := NewFormatReaders(io.NopCloser(bytes.NewReader(archive)), 0, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fr.OVA).To(BeFalse())
+		Expect(fr.Tar).To(BeTrue())
+	})
+})
@@ -0,0 +1,744 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"compress/flate"
+	"container/heap"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/image"
+)
+
+// Conversion of qcow2 images to raw while they are read from a stream, without scratch space. The clusters of the
+// image are read in the order they are stored, and the data clusters are written to their offset in the raw image as
+// soon as the L2 table mapping them is known. Clusters read before the tables mapping them are kept in memory, up to
+// qcow2StreamMemoryLimit, and then in a file in the scratch space if there is one.
+// https://gitlab.com/qemu-project/qemu/-/blob/master/docs/interop/qcow2.txt
+
+const (
+	qcow2HeaderV2Len = 72
+	qcow2HeaderV3Len = 104
+
+	qcow2MinClusterBits = 9
+	qcow2MaxClusterBits = 21
+	// qcow2MaxL1Size is the maximum size of the L1 table accepted by qemu
+	qcow2MaxL1Size = 32 << 20
+
+	qcow2IncompatDirty         = 1 << 0
+	qcow2IncompatCorrupt       = 1 << 1
+	qcow2IncompatDataFile      = 1 << 2
+	qcow2IncompatCompression   = 1 << 3
+	qcow2IncompatExtendedL2    = 1 << 4
+	qcow2IncompatKnownFeatures = qcow2IncompatDirty | qcow2IncompatCorrupt | qcow2IncompatDataFile | qcow2IncompatCompression | qcow2IncompatExtendedL2
+
+	qcow2CompressionZlib = 0
+	qcow2CompressionZstd = 1
+
+	qcow2OffsetMask     = 0x00fffffffffffe00
+	qcow2FlagCompressed = 1 << 62
+	qcow2FlagZero       = 1 << 0
+
+	// qcow2WantCost is the memory accounted for each cluster waiting to be read
+	qcow2WantCost = 32
+)
+
+// qcow2StreamMemoryLimit is the maximum memory used to keep the clusters of a qcow2 image read before the tables that
+// map them, and to track the clusters still to be read. May be overridden in tests.
+var qcow2StreamMemoryLimit int64 = 128 << 20
+
+// qcow2Header holds the fields of the qcow2 header needed to read the image.
+type qcow2Header struct {
+	clusterBits     uint32
+	size            int64
+	l1Size          int64
+	l1TableOffset   int64
+	compressionType byte
+}
+
+// parseQcow2Header parses the qcow2 header and returns an error if the image cannot be converted while it is
+// streamed: images with a backing file, encryption, an external data file or extended L2 entries.
+func parseQcow2Header(hdr []byte) (*qcow2Header, error) {
+	if len(hdr) < qcow2HeaderV2Len || !bytes.Equal(hdr[:4], []byte{'Q', 'F', 'I', 0xfb}) {
+		return nil, errors.New("not a qcow2 image")
+	}
+	be := binary.BigEndian
+	version := be.Uint32(hdr[4:])
+	if version != 2 && version != 3 {
+		return nil, errors.Errorf("unsupported qcow2 version %d", version)
+	}
+	if be.Uint64(hdr[8:]) != 0 {
+		return nil, errors.New("qcow2 images with a backing file are not supported")
+	}
+	h := &qcow2Header{
+		clusterBits:   be.Uint32(hdr[20:]),
+		size:          int64(be.Uint64(hdr[24:])),
+		l1Size:        int64(be.Uint32(hdr[36:])),
+		l1TableOffset: int64(be.Uint64(hdr[40:])),
+	}
+	if h.clusterBits < qcow2MinClusterBits || h.clusterBits > qcow2MaxClusterBits {
+		return nil, errors.Errorf("invalid qcow2 cluster bits %d", h.clusterBits)
+	}
+	if h.size < 0 {
+		return nil, errors.Errorf("invalid qcow2 virtual size %d", h.size)
+	}
+	if be.Uint32(hdr[32:]) != 0 {
+		return nil, errors.New("encrypted qcow2 images are not supported")
+	}
+	if h.l1Size*8 > qcow2MaxL1Size {
+		return nil, errors.Errorf("qcow2 L1 table size %d is too large", h.l1Size)
+	}
+	if h.l1Size < divRoundUp(h.size, h.clusterSize()*h.l2Entries()) {
+		return nil, errors.Errorf("qcow2 L1 table size %d is too small for the virtual size %d", h.l1Size, h.size)
+	}
+	if h.l1Size > 0 && (h.l1TableOffset <= 0 || h.l1TableOffset%h.clusterSize() != 0) {
+		return nil, errors.Errorf("invalid qcow2 L1 table offset %d", h.l1TableOffset)
+	}
+	if version == 3 {
+		if len(hdr) < qcow2HeaderV3Len {
+			return nil, errors.New("qcow2 header is too short")
+		}
+		incompatible := be.Uint64(hdr[72:])
+		if incompatible&^qcow2IncompatKnownFeatures != 0 {
+			return nil, errors.Errorf("unsupported qcow2 incompatible features %#x", incompatible)
+		}
+		if incompatible&qcow2IncompatCorrupt != 0 {
+			return nil, errors.New("qcow2 image is marked corrupt")
+		}
+		if incompatible&qcow2IncompatDataFile != 0 {
+			return nil, errors.New("qcow2 images with an external data file are not supported")
+		}
+		if incompatible&qcow2IncompatExtendedL2 != 0 {
+			return nil, errors.New("qcow2 images with extended L2 entries are not supported")
+		}
+		if incompatible&qcow2IncompatCompression != 0 {
+			if headerLen := be.Uint32(hdr[100:]); headerLen <= qcow2HeaderV3Len || len(hdr) <= qcow2HeaderV3Len {
+				return nil, errors.New("qcow2 header is too short for the compression type")
+			}
+			h.compressionType = hdr[qcow2HeaderV3Len]
+			if h.compressionType != qcow2CompressionZlib && h.compressionType != qcow2CompressionZstd {
+				return nil, errors.Errorf("unsupported qcow2 compression type %d", h.compressionType)
+			}
+		}
+	}
+	return h, nil
+}
+
+func (h *qcow2Header) clusterSize() int64 {
+	return 1 << h.clusterBits
+}
+
+// l2Entries is the number of entries of an L2 table, which is one cluster.
+func (h *qcow2Header) l2Entries() int64 {
+	return h.clusterSize() / 8
+}
+
+func divRoundUp(n, d int64) int64 {
+	return (n + d - 1) / d
+}
+
+// qcow2Want is a cluster, or a compressed cluster, to be written at the guest offset once it is read from the host
+// offset.
+type qcow2Want struct {
+	// last is the offset of the last host cluster holding the data, the key of the heap
+	last  int64
+	host  int64
+	size  int64
+	guest int64
+	// compressed is true if host and size locate compressed data rather than a cluster
+	compressed bool
+}
+
+type qcow2WantHeap []qcow2Want
+
+func (h qcow2WantHeap) Len() int            { return len(h) }
+func (h qcow2WantHeap) Less(i, j int) bool  { return h[i].last < h[j].last }
+func (h qcow2WantHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *qcow2WantHeap) Push(x interface{}) { *h = append(*h, x.(qcow2Want)) }
+func (h *qcow2WantHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// qcow2StreamConverter converts a qcow2 stream to a raw image.
+type qcow2StreamConverter struct {
+	r   io.Reader
+	out *os.File
+	hdr *qcow2Header
+	// pos is the host offset of the next cluster read from r, the clusters before it were read
+	pos int64
+	// l1 is the L1 table, nil until it is read
+	l1 []uint64
+	// l2Pending maps the host offset of the L2 tables not read yet to their index in the L1 table
+	l2Pending map[int64]int64
+	// buffered holds the clusters read before it was known whether they hold data
+	buffered map[int64][]byte
+	// spill is the file in the scratch space holding the buffered clusters exceeding the memory limit, nil if there is
+	// no scratch space. spilled maps their host offset to their offset in the file.
+	spill     *os.File
+	spilled   map[int64]int64
+	spillSize int64
+	// keep counts the compressed clusters, not read entirely yet, which need a host cluster
+	keep  map[int64]int
+	wants qcow2WantHeap
+	// memory is the memory used by buffered clusters and wants
+	memory int64
+	// written has a bit set for each guest cluster written to the target
+	written []uint64
+	zstd    *zstd.Decoder
+}
+
+// StreamQcow2ToFile converts the qcow2 image read from r to a raw image in fileName, a new file or a block device.
+// Clusters stored before the tables mapping them are kept in scratchPath when they exceed the memory limit, if it is
+// not empty. Otherwise an error wrapping ErrStreamConvert is returned.
+func StreamQcow2ToFile(r io.Reader, fileName, scratchPath string, preallocate bool) error {
+	out, err := OpenFileOrBlockDevice(fileName)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	isDevice, err := IsDevice(fileName)
+	if err != nil {
+		return err
+	}
+
+	err = convertQcow2Stream(r, out, scratchPath, isDevice, preallocate)
+	if err != nil {
+		klog.Errorf("Unable to convert qcow2 stream: %v", err)
+		if !isDevice {
+			os.Remove(out.Name())
+		}
+		if IsNoCapacityError(err) || errors.Is(err, ErrStreamConvert) || errors.Is(err, image.ErrLargerPVCRequired) {
+			return err
+		}
+		return NewImagePullFailedError(err)
+	}
+	return out.Sync()
+}
+
+func convertQcow2Stream(r io.Reader, out *os.File, scratchPath string, isDevice, preallocate bool) error {
+	// The header fits in the first 512 bytes of the image, the smallest cluster size
+	hdrBuf := make([]byte, 1<<qcow2MinClusterBits)
+	if _, err := io.ReadFull(r, hdrBuf); err != nil {
+		return errors.Wrap(err, "could not read qcow2 header")
+	}
+	hdr, err := parseQcow2Header(hdrBuf)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Converting qcow2 stream to raw, virtual size %d, cluster size %d", hdr.size, hdr.clusterSize())
+	targetSize, err := qcow2TargetSize(out, isDevice)
+	if err != nil {
+		return err
+	}
+	if hdr.size > targetSize {
+		return errors.Wrapf(image.ErrLargerPVCRequired, "qcow2 virtual size %d is larger than the target size %d", hdr.size, targetSize)
+	}
+
+	c := &qcow2StreamConverter{
+		r:         io.MultiReader(bytes.NewReader(hdrBuf), r),
+		out:       out,
+		hdr:       hdr,
+		l2Pending: map[int64]int64{},
+		buffered:  map[int64][]byte{},
+		spilled:   map[int64]int64{},
+		keep:      map[int64]int{},
+	}
+	if isDevice {
+		// Only a device may hold previous data in the clusters not written, the bitmap counts in the memory limit
+		c.written = make([]uint64, divRoundUp(divRoundUp(hdr.size, hdr.clusterSize()), 64))
+		c.memory = int64(len(c.written)) * 8
+		if c.memory > qcow2StreamMemoryLimit {
+			return errors.Wrapf(ErrStreamConvert, "tracking the written qcow2 clusters needs more than %d bytes of memory", qcow2StreamMemoryLimit)
+		}
+	}
+	defer func() {
+		if c.zstd != nil {
+			c.zstd.Close()
+		}
+	}()
+	if scratchPath != "" {
+		if c.spill, err = os.CreateTemp(scratchPath, "qcow2-clusters-"); err != nil {
+			return errors.Wrap(err, "could not create file in scratch space")
+		}
+		defer func() {
+			c.spill.Close()
+			os.Remove(c.spill.Name())
+		}()
+	}
+
+	if !isDevice {
+		if err := out.Truncate(hdr.size); err != nil {
+			return errors.Wrap(err, "could not resize target file")
+		}
+		if preallocate {
+			if err := syscall.Fallocate(int(out.Fd()), 0, 0, hdr.size); err != nil {
+				return errors.Wrap(err, "could not preallocate target file")
+			}
+		}
+	}
+	if err := c.convert(); err != nil {
+		return err
+	}
+	if isDevice {
+		// The clusters not written may hold previous data of the device
+		return c.zeroUnwritten()
+	}
+	return nil
+}
+
+// qcow2TargetSize returns the size of the block device, or the space available for the file.
+func qcow2TargetSize(out *os.File, isDevice bool) (int64, error) {
+	if !isDevice {
+		return getAvailableSpaceFunc(filepath.Dir(out.Name()))
+	}
+	size, err := out.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get the size of the target device")
+	}
+	if _, err := out.Seek(0, io.SeekStart); err != nil {
+		return 0, errors.Wrap(err, "could not seek the target device")
+	}
+	return size, nil
+}
+
+// convert reads the clusters of the image until the end of the stream.
+func (c *qcow2StreamConverter) convert() error {
+	clusterSize := c.hdr.clusterSize()
+	if c.hdr.l1Size == 0 {
+		c.l1 = []uint64{}
+	}
+	for {
+		cluster := make([]byte, clusterSize)
+		n, err := io.ReadFull(c.r, cluster)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return errors.Wrap(err, "could not read qcow2 image")
+		}
+		// The image may end with a partial cluster, the rest reads as zeroes
+		offset := c.pos
+		c.pos += clusterSize
+		if err := c.processCluster(offset, cluster); err != nil {
+			return err
+		}
+		if n < len(cluster) {
+			break
+		}
+	}
+
+	// Compressed data may extend past the end of the image
+	for c.wants.Len() > 0 && c.wants[0].compressed && c.wants[0].host < c.pos {
+		want := heap.Pop(&c.wants).(qcow2Want)
+		if err := c.writeWant(want, nil); err != nil {
+			return err
+		}
+		c.unkeep(want)
+	}
+	if c.l1 == nil || len(c.l2Pending) > 0 || c.wants.Len() > 0 {
+		return errors.New("qcow2 image is truncated, data is missing")
+	}
+	return nil
+}
+
+// processCluster handles the cluster at the host offset.
+func (c *qcow2StreamConverter) processCluster(offset int64, cluster []byte) error {
+	if offset == 0 {
+		// The header cluster holds no data
+		return nil
+	}
+	if c.l1 == nil {
+		l1End := c.hdr.l1TableOffset + c.hdr.l1Size*8
+		if offset+int64(len(cluster)) < l1End {
+			return c.buffer(offset, cluster)
+		}
+		if err := c.buffer(offset, cluster); err != nil {
+			return err
+		}
+		return c.readL1()
+	}
+	if l1Index, ok := c.l2Pending[offset]; ok {
+		return c.readL2(offset, l1Index, cluster)
+	}
+
+	needed := c.keep[offset] > 0
+	mapped := false
+	for c.wants.Len() > 0 && c.wants[0].last == offset {
+		mapped = true
+		want := heap.Pop(&c.wants).(qcow2Want)
+		c.memory -= qcow2WantCost
+		if err := c.writeWant(want, cluster); err != nil {
+			return err
+		}
+		c.unkeep(want)
+	}
+	if needed || (!mapped && len(c.l2Pending) > 0) {
+		// Data of a compressed cluster spanning the next cluster, or maybe of an L2 table not read yet
+		return c.buffer(offset, cluster)
+	}
+	return nil
+}
+
+// buffer keeps a cluster in memory, or in the scratch space, until it is known whether it holds data.
+func (c *qcow2StreamConverter) buffer(offset int64, cluster []byte) error {
+	if c.spill != nil && c.memory+int64(len(cluster)) > qcow2StreamMemoryLimit {
+		if _, err := c.spill.WriteAt(cluster, c.spillSize); err != nil {
+			return errors.Wrap(err, "could not write qcow2 cluster to scratch space")
+		}
+		c.spilled[offset] = c.spillSize
+		c.spillSize += int64(len(cluster))
+		return nil
+	}
+	c.buffered[offset] = cluster
+	c.memory += int64(len(cluster))
+	return c.checkMemory()
+}
+
+func (c *qcow2StreamConverter) checkMemory() error {
+	if c.memory > qcow2StreamMemoryLimit {
+		return errors.Wrapf(ErrStreamConvert, "qcow2 clusters are stored out of order, more than %d bytes of memory needed", qcow2StreamMemoryLimit)
+	}
+	return nil
+}
+
+func (c *qcow2StreamConverter) release(offset int64) {
+	if cluster, ok := c.buffered[offset]; ok {
+		c.memory -= int64(len(cluster))
+		delete(c.buffered, offset)
+	}
+	delete(c.spilled, offset)
+}
+
+// isBuffered returns true if the cluster at the host offset is kept in memory or in the scratch space.
+func (c *qcow2StreamConverter) isBuffered(offset int64) bool {
+	_, inMemory := c.buffered[offset]
+	_, spilled := c.spilled[offset]
+	return inMemory || spilled
+}
+
+// bufferedCluster returns the cluster at the host offset kept in memory or in the scratch space, nil if it was not
+// kept.
+func (c *qcow2StreamConverter) bufferedCluster(offset int64) ([]byte, error) {
+	if cluster, ok := c.buffered[offset]; ok {
+		return cluster, nil
+	}
+	pos, ok := c.spilled[offset]
+	if !ok {
+		return nil, nil
+	}
+	cluster := make([]byte, c.hdr.clusterSize())
+	if _, err := c.spill.ReadAt(cluster, pos); err != nil {
+		return nil, errors.Wrap(err, "could not read qcow2 cluster from scratch space")
+	}
+	return cluster, nil
+}
+
+// readL1 reads the L1 table from the buffered clusters, and the L2 tables already read.
+func (c *qcow2StreamConverter) readL1() error {
+	clusterSize := c.hdr.clusterSize()
+	table, err := c.bufferedBytes(c.hdr.l1TableOffset, c.hdr.l1Size*8)
+	if err != nil {
+		return err
+	}
+	c.l1 = make([]uint64, c.hdr.l1Size)
+	for i := range c.l1 {
+		c.l1[i] = binary.BigEndian.Uint64(table[i*8:])
+	}
+	for offset := c.hdr.l1TableOffset; offset < c.hdr.l1TableOffset+c.hdr.l1Size*8; offset += clusterSize {
+		c.release(offset)
+	}
+	for i, entry := range c.l1 {
+		l2Offset := int64(entry & qcow2OffsetMask)
+		if l2Offset == 0 {
+			continue
+		}
+		if l2Offset%clusterSize != 0 {
+			return errors.Errorf("invalid qcow2 L2 table offset %d", l2Offset)
+		}
+		if _, ok := c.l2Pending[l2Offset]; ok {
+			return errors.Errorf("invalid qcow2 L2 table offset %d, used by several L1 entries", l2Offset)
+		}
+		c.l2Pending[l2Offset] = int64(i)
+	}
+	// The L2 tables stored before the end of the L1 table
+	for l2Offset, i := range c.l2Pending {
+		if l2Offset >= c.pos {
+			continue
+		}
+		cluster, err := c.bufferedCluster(l2Offset)
+		if err != nil {
+			return err
+		}
+		if cluster == nil {
+			return errors.Errorf("invalid qcow2 L2 table offset %d", l2Offset)
+		}
+		if err := c.readL2(l2Offset, i, cluster); err != nil {
+			return err
+		}
+	}
+	c.releaseUnknown()
+	return nil
+}
+
+// readL2 adds the clusters mapped by an L2 table to the wanted clusters, and writes the clusters already read.
+func (c *qcow2StreamConverter) readL2(l2Offset, l1Index int64, table []byte) error {
+	clusterSize := c.hdr.clusterSize()
+	delete(c.l2Pending, l2Offset)
+	c.release(l2Offset)
+	for i := int64(0); i < c.hdr.l2Entries(); i++ {
+		guest := (l1Index*c.hdr.l2Entries() + i) * clusterSize
+		if guest >= c.hdr.size {
+			break
+		}
+		entry := binary.BigEndian.Uint64(table[i*8:])
+		want := qcow2Want{guest: guest}
+		if entry&qcow2FlagCompressed != 0 {
+			x := 62 - (c.hdr.clusterBits - 8)
+			want.compressed = true
+			want.host = int64(entry & (1<<x - 1))
+			sectors := int64((entry>>x)&(1<<(c.hdr.clusterBits-8)-1)) + 1
+			want.size = sectors*512 - want.host%512
+		} else {
+			if entry&qcow2FlagZero != 0 {
+				continue
+			}
+			want.host = int64(entry & qcow2OffsetMask)
+			if want.host == 0 {
+				continue
+			}
+			if want.host%clusterSize != 0 {
+				return errors.Errorf("invalid qcow2 cluster offset %d", want.host)
+			}
+			want.size = clusterSize
+		}
+		first := want.host / clusterSize * clusterSize
+		want.last = (want.host + want.size - 1) / clusterSize * clusterSize
+		if want.last < c.pos || (want.compressed && first < c.pos) {
+			// Some data of the cluster was already read
+			if err := c.checkBuffered(first, want.last); err != nil {
+				return err
+			}
+		}
+		if want.last < c.pos {
+			if err := c.writeWant(want, nil); err != nil {
+				return err
+			}
+			continue
+		}
+		// Keep the host clusters of a compressed cluster until its last one is read
+		for offset := first; offset < want.last; offset += clusterSize {
+			c.keep[offset]++
+		}
+		heap.Push(&c.wants, want)
+		c.memory += qcow2WantCost
+	}
+	if len(c.l2Pending) == 0 {
+		c.releaseUnknown()
+	}
+	return c.checkMemory()
+}
+
+// checkBuffered returns an error if a cluster between first and last, already read, was not kept.
+func (c *qcow2StreamConverter) checkBuffered(first, last int64) error {
+	for offset := first; offset <= last && offset < c.pos; offset += c.hdr.clusterSize() {
+		if !c.isBuffered(offset) {
+			return errors.Errorf("invalid qcow2 cluster offset %d", offset)
+		}
+	}
+	return nil
+}
+
+// releaseUnknown releases the buffered clusters which hold no data, once all the L2 tables were read.
+func (c *qcow2StreamConverter) releaseUnknown() {
+	if c.l1 == nil || len(c.l2Pending) > 0 {
+		return
+	}
+	for offset := range c.buffered {
+		if c.keep[offset] == 0 {
+			c.release(offset)
+		}
+	}
+	for offset := range c.spilled {
+		if c.keep[offset] == 0 {
+			c.release(offset)
+		}
+	}
+}
+
+// bufferedBytes returns size bytes at the host offset, from the buffered clusters and the current cluster.
+func (c *qcow2StreamConverter) bufferedBytes(offset, size int64) ([]byte, error) {
+	clusterSize := c.hdr.clusterSize()
+	data := make([]byte, 0, size)
+	for cur := offset / clusterSize * clusterSize; int64(len(data)) < size; cur += clusterSize {
+		cluster, err := c.bufferedCluster(cur)
+		if err != nil {
+			return nil, err
+		}
+		if cluster == nil {
+			if cur < c.pos {
+				return nil, errors.Errorf("invalid qcow2 cluster offset %d", cur)
+			}
+			// Past the end of the image
+			break
+		}
+		start := int64(0)
+		if cur < offset {
+			start = offset - cur
+		}
+		end := clusterSize
+		if remaining := size - int64(len(data)); end-start > remaining {
+			end = start + remaining
+		}
+		data = append(data, cluster[start:end]...)
+	}
+	return data, nil
+}
+
+// writeWant writes a wanted cluster to the target, the last host cluster is passed in if it was not buffered.
+func (c *qcow2StreamConverter) writeWant(want qcow2Want, last []byte) error {
+	if last != nil {
+		c.buffered[want.last] = last
+		defer delete(c.buffered, want.last)
+	}
+	data, err := c.bufferedBytes(want.host, want.size)
+	if err != nil {
+		return err
+	}
+	if want.compressed {
+		if data, err = c.decompress(data); err != nil {
+			return errors.Wrapf(err, "could not decompress qcow2 cluster at offset %d", want.host)
+		}
+	}
+	if remaining := c.hdr.size - want.guest; int64(len(data)) > remaining {
+		data = data[:remaining]
+	}
+	if isZero(data) {
+		return nil
+	}
+	if _, err := c.out.WriteAt(data, want.guest); err != nil {
+		return errors.Wrapf(err, "could not write cluster at offset %d", want.guest)
+	}
+	if c.written != nil {
+		index := want.guest / c.hdr.clusterSize()
+		c.written[index/64] |= 1 << (index % 64)
+	}
+	return nil
+}
+
+// unkeep releases the host clusters kept for a compressed cluster which was written.
+func (c *qcow2StreamConverter) unkeep(want qcow2Want) {
+	if !want.compressed {
+		return
+	}
+	clusterSize := c.hdr.clusterSize()
+	for offset := want.host / clusterSize * clusterSize; offset < want.last; offset += clusterSize {
+		if c.keep[offset]--; c.keep[offset] <= 0 {
+			delete(c.keep, offset)
+			if len(c.l2Pending) == 0 {
+				c.release(offset)
+			}
+		}
+	}
+}
+
+// decompress returns the cluster decompressed from data, which may have trailing bytes.
+func (c *qcow2StreamConverter) decompress(data []byte) ([]byte, error) {
+	var r io.Reader
+	switch c.hdr.compressionType {
+	case qcow2CompressionZlib:
+		// raw deflate, without zlib header
+		r = flate.NewReader(bytes.NewReader(data))
+	case qcow2CompressionZstd:
+		if c.zstd == nil {
+			var err error
+			if c.zstd, err = zstd.NewReader(nil, zstd.WithDecoderConcurrency(1)); err != nil {
+				return nil, err
+			}
+		}
+		if err := c.zstd.Reset(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+		r = c.zstd
+	}
+	cluster := make([]byte, c.hdr.clusterSize())
+	if _, err := io.ReadFull(r, cluster); err != nil {
+		return nil, err
+	}
+	return cluster, nil
+}
+
+// zeroUnwritten zeroes the ranges of the target not written by the conversion.
+func (c *qcow2StreamConverter) zeroUnwritten() error {
+	clusterSize := c.hdr.clusterSize()
+	clusters := divRoundUp(c.hdr.size, clusterSize)
+	for start := int64(0); start < clusters; {
+		if c.written[start/64]&(1<<(start%64)) != 0 {
+			start++
+			continue
+		}
+		end := start + 1
+		for end < clusters && c.written[end/64]&(1<<(end%64)) == 0 {
+			end++
+		}
+		offset := start * clusterSize
+		length := end*clusterSize - offset
+		if offset+length > c.hdr.size {
+			length = c.hdr.size - offset
+		}
+		if err := zeroRange(c.out, offset, length); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+// zeroRange zeroes a range of a block device, by punching a hole or writing zeroes.
+func zeroRange(out *os.File, offset, length int64) error {
+	if _, err := out.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	if err := PunchHole(out, offset, length); err == nil {
+		return nil
+	}
+	if _, err := out.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	return AppendZeroWithWrite(out, offset, length)
+}
+
+func isZero(data []byte) bool {
+	for _, b := range data {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,415 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/klauspost/compress/zstd"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/image"
+)
+
+// qcow2TestImage builds a qcow2 image, the clusters are stored in the order they are added.
+type qcow2TestImage struct {
+	clusterBits     uint32
+	size            int64
+	compressionType byte
+	data            []byte
+	l1Offset        int64
+	l2Offsets       map[int64]int64
+	entries         map[int64]uint64
+}
+
+func newQcow2TestImage(clusterBits uint32, size int64) *qcow2TestImage {
+	img := &qcow2TestImage{
+		clusterBits: clusterBits,
+		size:        size,
+		l2Offsets:   map[int64]int64{},
+		entries:     map[int64]uint64{},
+	}
+	// header
+	img.allocate()
+	return img
+}
+
+func (img *qcow2TestImage) clusterSize() int64 {
+	return 1 << img.clusterBits
+}
+
+func (img *qcow2TestImage) l1Size() int64 {
+	return divRoundUp(img.size, img.clusterSize()*img.clusterSize()/8)
+}
+
+// allocate appends a cluster to the image and returns its offset.
+func (img *qcow2TestImage) allocate() int64 {
+	offset := divRoundUp(int64(len(img.data)), img.clusterSize()) * img.clusterSize()
+	img.data = append(img.data, make([]byte, offset+img.clusterSize()-int64(len(img.data)))...)
+	return offset
+}
+
+func (img *qcow2TestImage) addL1() {
+	img.l1Offset = img.allocate()
+	for i := int64(1); i < divRoundUp(img.l1Size()*8, img.clusterSize()); i++ {
+		img.allocate()
+	}
+}
+
+func (img *qcow2TestImage) addL2(l1Index int64) {
+	img.l2Offsets[l1Index] = img.allocate()
+}
+
+func (img *qcow2TestImage) addCluster(guestCluster int64, data []byte) {
+	offset := img.allocate()
+	copy(img.data[offset:], data)
+	img.entries[guestCluster] = uint64(offset)
+}
+
+func (img *qcow2TestImage) addZeroCluster(guestCluster int64) {
+	img.entries[guestCluster] = qcow2FlagZero
+}
+
+// addCompressedCluster appends the compressed cluster right after the previous data, which may be in the middle of
+// a cluster.
+func (img *qcow2TestImage) addCompressedCluster(guestCluster int64, data []byte) {
+	var compressed bytes.Buffer
+	if img.compressionType == qcow2CompressionZstd {
+		w, err := zstd.NewWriter(&compressed)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = w.Write(data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(w.Close()).To(Succeed())
+	} else {
+		w, err := flate.NewWriter(&compressed, flate.BestCompression)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = w.Write(data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(w.Close()).To(Succeed())
+	}
+	offset := int64(len(img.data))
+	img.data = append(img.data, compressed.Bytes()...)
+	sectors := divRoundUp(offset%512+int64(compressed.Len()), 512)
+	x := 62 - (img.clusterBits - 8)
+	img.entries[guestCluster] = qcow2FlagCompressed | uint64(sectors-1)<<x | uint64(offset)
+}
+
+// bytes returns the image with its header and tables.
+func (img *qcow2TestImage) bytes() []byte {
+	data := bytes.Clone(img.data)
+	be := binary.BigEndian
+	copy(data, []byte{'Q', 'F', 'I', 0xfb})
+	be.PutUint32(data[4:], 3)
+	be.PutUint32(data[20:], img.clusterBits)
+	be.PutUint64(data[24:], uint64(img.size))
+	be.PutUint32(data[36:], uint32(img.l1Size()))
+	be.PutUint64(data[40:], uint64(img.l1Offset))
+	be.PutUint32(data[100:], qcow2HeaderV3Len)
+	if img.compressionType != qcow2CompressionZlib {
+		be.PutUint64(data[72:], qcow2IncompatCompression)
+		be.PutUint32(data[100:], qcow2HeaderV3Len+8)
+		data[qcow2HeaderV3Len] = img.compressionType
+	}
+	for l1Index, l2Offset := range img.l2Offsets {
+		be.PutUint64(data[img.l1Offset+l1Index*8:], uint64(l2Offset))
+	}
+	l2Entries := img.clusterSize() / 8
+	for guestCluster, entry := range img.entries {
+		l2Offset, ok := img.l2Offsets[guestCluster/l2Entries]
+		Expect(ok).To(BeTrue())
+		be.PutUint64(data[l2Offset+guestCluster%l2Entries*8:], entry)
+	}
+	return data
+}
+
+// qcow2TestCluster returns the content of a guest cluster.
+func qcow2TestCluster(clusterSize, guestCluster int64) []byte {
+	return bytes.Repeat([]byte{byte('a' + guestCluster%26)}, int(clusterSize))
+}
+
+func sha256File(fileName string) string {
+	f, err := os.Open(fileName)
+	Expect(err).ToNot(HaveOccurred())
+	defer f.Close()
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	Expect(err).ToNot(HaveOccurred())
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+var _ = Describe("qcow2 stream conversion", func() {
+	const clusterSize = 512
+	var (
+		tmpDir   string
+		target   string
+		expected []byte
+	)
+
+	BeforeEach(func() {
+		tmpDir = GinkgoT().TempDir()
+		target = filepath.Join(tmpDir, "disk.img")
+		// 512 byte clusters, 64 clusters per L2 table, the last cluster is partial
+		expected = make([]byte, 150*clusterSize+100)
+	})
+
+	setCluster := func(guestCluster int64, data []byte) {
+		copy(expected[guestCluster*clusterSize:], data)
+	}
+
+	convert := func(img []byte, scratchPath string) error {
+		return StreamQcow2ToFile(bytes.NewReader(img), target, scratchPath, false)
+	}
+
+	expectConverted := func() {
+		converted, err := os.ReadFile(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(converted).To(HaveLen(len(expected)))
+		Expect(bytes.Equal(converted, expected)).To(BeTrue())
+	}
+
+	It("should convert an image with the tables before the data", func() {
+		img := newQcow2TestImage(9, int64(len(expected)))
+		img.addL1()
+		img.addL2(0)
+		for _, i := range []int64{0, 1, 5, 40} {
+			img.addCluster(i, qcow2TestCluster(clusterSize, i))
+			setCluster(i, qcow2TestCluster(clusterSize, i))
+		}
+		img.addZeroCluster(2)
+		// a cluster of zeroes is not written
+		img.addCluster(3, make([]byte, clusterSize))
+		img.addL2(2)
+		img.addCluster(149, qcow2TestCluster(clusterSize, 149))
+		setCluster(149, qcow2TestCluster(clusterSize, 149))
+		img.addCluster(150, qcow2TestCluster(clusterSize, 150))
+		copy(expected[150*clusterSize:], qcow2TestCluster(clusterSize, 150)[:100])
+
+		Expect(convert(img.bytes(), "")).To(Succeed())
+		expectConverted()
+	})
+
+	It("should convert an image with the data before the tables", func() {
+		img := newQcow2TestImage(9, int64(len(expected)))
+		img.addCluster(70, qcow2TestCluster(clusterSize, 70))
+		setCluster(70, qcow2TestCluster(clusterSize, 70))
+		img.addL2(0)
+		img.addCluster(3, qcow2TestCluster(clusterSize, 3))
+		setCluster(3, qcow2TestCluster(clusterSize, 3))
+		img.addL1()
+		img.addCluster(2, qcow2TestCluster(clusterSize, 2))
+		setCluster(2, qcow2TestCluster(clusterSize, 2))
+		img.addL2(1)
+
+		Expect(convert(img.bytes(), "")).To(Succeed())
+		expectConverted()
+	})
+
+	DescribeTable("should convert an image with compressed clusters", func(compressionType byte) {
+		img := newQcow2TestImage(9, int64(len(expected)))
+		img.compressionType = compressionType
+		img.addL1()
+		img.addL2(0)
+		for _, i := range []int64{0, 1, 2, 10} {
+			// not compressible, the compressed clusters span several host clusters
+			data := make([]byte, clusterSize)
+			for j := range data {
+				data[j] = byte(j*j*7 + int(i))
+			}
+			img.addCompressedCluster(i, data)
+			setCluster(i, data)
+		}
+		img.addCluster(11, qcow2TestCluster(clusterSize, 11))
+		setCluster(11, qcow2TestCluster(clusterSize, 11))
+		// the image ends with a compressed cluster, its sectors extend past the end of the image
+		img.addL2(2)
+		img.addCompressedCluster(130, qcow2TestCluster(clusterSize, 130))
+		setCluster(130, qcow2TestCluster(clusterSize, 130))
+
+		Expect(convert(img.bytes(), "")).To(Succeed())
+		expectConverted()
+	},
+		Entry("with zlib", byte(qcow2CompressionZlib)),
+		Entry("with zstd", byte(qcow2CompressionZstd)),
+	)
+
+	Context("with clusters before their L2 table exceeding the memory limit", func() {
+		var img []byte
+
+		BeforeEach(func() {
+			orig := qcow2StreamMemoryLimit
+			qcow2StreamMemoryLimit = 4 * clusterSize
+			DeferCleanup(func() {
+				qcow2StreamMemoryLimit = orig
+			})
+			qcow2Img := newQcow2TestImage(9, int64(len(expected)))
+			qcow2Img.addL1()
+			for i := int64(0); i < 10; i++ {
+				qcow2Img.addCluster(i, qcow2TestCluster(clusterSize, i))
+				setCluster(i, qcow2TestCluster(clusterSize, i))
+			}
+			qcow2Img.addL2(0)
+			img = qcow2Img.bytes()
+		})
+
+		It("should require scratch space", func() {
+			err := convert(img, "")
+			Expect(err).To(MatchError(ErrStreamConvert))
+			Expect(target).ToNot(BeAnExistingFile())
+		})
+
+		It("should keep the clusters in the scratch space", func() {
+			scratch := filepath.Join(tmpDir, "scratch")
+			Expect(os.Mkdir(scratch, 0755)).To(Succeed())
+			Expect(convert(img, scratch)).To(Succeed())
+			expectConverted()
+			Expect(os.ReadDir(scratch)).To(BeEmpty())
+		})
+	})
+
+	It("should refuse an image larger than the space available for the file", func() {
+		orig := getAvailableSpaceFunc
+		getAvailableSpaceFunc = func(string) (int64, error) {
+			return int64(len(expected)) - 1, nil
+		}
+		defer func() { getAvailableSpaceFunc = orig }()
+		img := newQcow2TestImage(9, int64(len(expected)))
+		img.addL1()
+		err := convert(img.bytes(), "")
+		Expect(err).To(MatchError(image.ErrLargerPVCRequired))
+		Expect(target).ToNot(BeAnExistingFile())
+	})
+
+	Context("with a block device target", func() {
+		// A regular file of the size of the device stands in for it
+		convertToDevice := func(img []byte, deviceSize int) error {
+			Expect(os.WriteFile(target, bytes.Repeat([]byte{0xff}, deviceSize), 0600)).To(Succeed())
+			out, err := os.OpenFile(target, os.O_RDWR, 0)
+			Expect(err).ToNot(HaveOccurred())
+			defer out.Close()
+			return convertQcow2Stream(bytes.NewReader(img), out, "", true, false)
+		}
+
+		It("should zero the clusters not written", func() {
+			img := newQcow2TestImage(9, int64(len(expected)))
+			img.addL1()
+			img.addL2(0)
+			img.addCluster(5, qcow2TestCluster(clusterSize, 5))
+			setCluster(5, qcow2TestCluster(clusterSize, 5))
+			Expect(convertToDevice(img.bytes(), len(expected))).To(Succeed())
+			expectConverted()
+		})
+
+		It("should refuse an image larger than the device", func() {
+			img := newQcow2TestImage(9, int64(len(expected)))
+			img.addL1()
+			err := convertToDevice(img.bytes(), len(expected)-clusterSize)
+			Expect(err).To(MatchError(image.ErrLargerPVCRequired))
+		})
+
+		It("should count the written clusters in the memory limit", func() {
+			orig := qcow2StreamMemoryLimit
+			// 151 clusters are tracked in 3 words
+			qcow2StreamMemoryLimit = 16
+			defer func() { qcow2StreamMemoryLimit = orig }()
+			img := newQcow2TestImage(9, int64(len(expected)))
+			img.addL1()
+			err := convertToDevice(img.bytes(), len(expected))
+			Expect(err).To(MatchError(ErrStreamConvert))
+		})
+	})
+
+	It("should fail to convert a truncated image", func() {
+		img := newQcow2TestImage(9, int64(len(expected)))
+		img.addL1()
+		img.addL2(0)
+		img.addCluster(0, qcow2TestCluster(clusterSize, 0))
+		img.addCluster(1, qcow2TestCluster(clusterSize, 1))
+		data := img.bytes()
+		err := convert(data[:len(data)-clusterSize], "")
+		Expect(err).To(MatchError(ContainSubstring("qcow2 image is truncated")))
+	})
+
+	It("should refuse an image with an L2 table used by several L1 entries", func() {
+		img := newQcow2TestImage(9, int64(len(expected)))
+		img.addL1()
+		img.addL2(0)
+		img.l2Offsets[1] = img.l2Offsets[0]
+		img.addCluster(0, qcow2TestCluster(clusterSize, 0))
+		err := convert(img.bytes(), "")
+		Expect(err).To(MatchError(ContainSubstring("used by several L1 entries")))
+	})
+
+	It("should convert an image created by qemu-img", func() {
+		f, err := os.Open(filepath.Join(TestImagesDir, "fs-overhead.qcow2"))
+		Expect(err).ToNot(HaveOccurred())
+		defer f.Close()
+		Expect(StreamQcow2ToFile(f, target, "", false)).To(Succeed())
+		Expect(sha256File(target)).To(Equal("f2e7d09dcc87cf4a8f96989d6479bdf5545cf0c84f9d5ea4cee4c6d099cdae6d"))
+	})
+
+	DescribeTable("should not convert", func(update func(hdr []byte), expected string) {
+		img := newQcow2TestImage(16, 1<<20)
+		img.addL1()
+		hdr := img.bytes()
+		update(hdr)
+		_, err := parseQcow2Header(hdr)
+		Expect(err).To(MatchError(ContainSubstring(expected)))
+
+		fr, err := NewFormatReaders(io.NopCloser(bytes.NewReader(hdr)), 0, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fr.Convert).To(BeTrue())
+		Expect(fr.StreamConvert).To(BeFalse())
+	},
+		Entry("an image with a backing file", func(hdr []byte) {
+			binary.BigEndian.PutUint64(hdr[8:], 512)
+		}, "backing file"),
+		Entry("an encrypted image", func(hdr []byte) {
+			binary.BigEndian.PutUint32(hdr[32:], 2)
+		}, "encrypted"),
+		Entry("an image with an external data file", func(hdr []byte) {
+			binary.BigEndian.PutUint64(hdr[72:], qcow2IncompatDataFile)
+		}, "external data file"),
+		Entry("an image with extended L2 entries", func(hdr []byte) {
+			binary.BigEndian.PutUint64(hdr[72:], qcow2IncompatExtendedL2)
+		}, "extended L2 entries"),
+		Entry("an image with an unknown incompatible feature", func(hdr []byte) {
+			binary.BigEndian.PutUint64(hdr[72:], 1<<10)
+		}, "unsupported qcow2 incompatible features"),
+		Entry("an image with an invalid cluster size", func(hdr []byte) {
+			binary.BigEndian.PutUint32(hdr[20:], 30)
+		}, "invalid qcow2 cluster bits 30"),
+	)
+
+	It("should be detected by the format readers", func() {
+		img := newQcow2TestImage(16, 1<<20)
+		img.addL1()
+		fr, err := NewFormatReaders(io.NopCloser(bytes.NewReader(img.bytes())), 0, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fr.Convert).To(BeTrue())
+		Expect(fr.StreamConvert).To(BeTrue())
+		Expect(fr.VirtualSize).To(Equal(int64(1 << 20)))
+	})
+})
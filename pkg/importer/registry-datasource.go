@@ -40,6 +40,8 @@ const (
 // Sequence of phases:
 // 1. Info -> Transfer
 // 2. Transfer -> Convert
+// It does not implement StreamConvertDataSource: the disk is only known once all the layers of the image were read,
+// since a later layer may replace or remove it, so it is always extracted to scratch space before it is converted.
 type RegistryDataSource struct {
 	endpoint          string
 	accessKey         string
@@ -47,6 +49,9 @@ type RegistryDataSource struct {
 	imageArchitecture string
 	certDir           string
 	insecureTLS       bool
+	signaturePolicy   *SignaturePolicy
+	diskPath          string
+	diskIndex         int
 	imageDir          string
 	//The discovered image file in scratch space.
 	url *url.URL
@@ -54,8 +59,10 @@ type RegistryDataSource struct {
 	info *types.ImageInspectInfo
 }
 
-// NewRegistryDataSource creates a new instance of the Registry Data Source.
-func NewRegistryDataSource(endpoint, accessKey, secKey, imageArchitecture, certDir string, insecureTLS bool) *RegistryDataSource {
+// NewRegistryDataSource creates a new instance of the Registry Data Source. If a signature policy is passed in, the
+// image is only imported if it has a signature that satisfies the policy. The disk to import from an image with
+// several disks is selected by diskPath relative to the disk directory, or by diskIndex if it is not negative.
+func NewRegistryDataSource(endpoint, accessKey, secKey, imageArchitecture, certDir string, insecureTLS bool, signaturePolicy *SignaturePolicy, diskPath string, diskIndex int) *RegistryDataSource {
 	allCertDir, err := CreateCertificateDir(certDir)
 	if err != nil {
 		klog.Infof("Error creating allCertDir %v", err)
@@ -74,6 +81,9 @@ func NewRegistryDataSource(endpoint, accessKey, secKey, imageArchitecture, certD
 		imageArchitecture: imageArchitecture,
 		certDir:           allCertDir,
 		insecureTLS:       insecureTLS,
+		signaturePolicy:   signaturePolicy,
+		diskPath:          diskPath,
+		diskIndex:         diskIndex,
 	}
 }
 
@@ -99,22 +109,40 @@ func (rd *RegistryDataSource) Transfer(path string, preallocation bool) (Process
 	}
 
 	klog.V(1).Infof("Copying registry image to scratch space.")
-	rd.info, err = CopyRegistryImage(rd.endpoint, path, containerDiskImageDir, rd.accessKey, rd.secKey, rd.imageArchitecture, rd.certDir, rd.insecureTLS, preallocation)
-	if err != nil {
-		return ProcessingPhaseError, errors.Wrapf(err, "Failed to read registry image")
-	}
+	if rd.diskPath == "" && rd.diskIndex < 0 {
+		rd.info, err = CopyRegistryImage(rd.endpoint, path, containerDiskImageDir, rd.accessKey, rd.secKey, rd.imageArchitecture, rd.certDir, rd.insecureTLS, preallocation, rd.signaturePolicy)
+		if err != nil {
+			return ProcessingPhaseError, errors.Wrapf(err, "Failed to read registry image")
+		}
 
-	imageFile, err := getImageFileName(rd.imageDir)
-	if err != nil {
-		return ProcessingPhaseError, errors.Wrapf(err, "Cannot locate image file")
-	}
+		imageFile, err := getImageFileName(rd.imageDir)
+		if err != nil {
+			return ProcessingPhaseError, errors.Wrapf(err, "Cannot locate image file")
+		}
 
-	// imageFile and rd.imageDir are both valid, thus the Join will be valid, and the parse will work, no need to check for parse errors
-	rd.url, _ = url.Parse(filepath.Join(rd.imageDir, imageFile))
+		// imageFile and rd.imageDir are both valid, thus the Join will be valid, and the parse will work, no need to check for parse errors
+		rd.url, _ = url.Parse(filepath.Join(rd.imageDir, imageFile))
+	} else {
+		selector := rd.diskSelector()
+		rd.info, err = copyRegistryImage(rd.endpoint, path, selector, rd.accessKey, rd.secKey, rd.imageArchitecture, rd.certDir, rd.insecureTLS, true, preallocation, rd.signaturePolicy)
+		if err != nil {
+			return ProcessingPhaseError, errors.Wrapf(err, "Failed to read registry image")
+		}
+		// The selected file was copied to its path in the image, which safeJoinPaths already validated
+		rd.url, _ = url.Parse(filepath.Join(path, selector.selected))
+	}
 	klog.V(3).Infof("Successfully found file. VM disk image filename is %s", rd.url.String())
 	return ProcessingPhaseConvert, nil
 }
 
+// diskSelector returns the selector of the disk chosen by path or index
+func (rd *RegistryDataSource) diskSelector() *fileSelector {
+	if rd.diskPath != "" {
+		return &fileSelector{path: filepath.Join(containerDiskImageDir, rd.diskPath)}
+	}
+	return &fileSelector{pathPrefix: containerDiskImageDir, skip: rd.diskIndex}
+}
+
 // TransferFile is called to transfer the data from the source to the passed in file.
 func (rd *RegistryDataSource) TransferFile(fileName string, preallocation bool) (ProcessingPhase, error) {
 	return ProcessingPhaseError, errors.New("Transferfile should not be called")
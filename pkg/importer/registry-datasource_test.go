@@ -1,6 +1,7 @@
 package importer
 
 import (
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -36,7 +37,7 @@ var _ = Describe("Registry data source", func() {
 	})
 
 	It("should return transfer after info is called", func() {
-		ds = NewRegistryDataSource("", "", "", "", "", true)
+		ds = NewRegistryDataSource("", "", "", "", "", true, nil, "", -1)
 		result, err := ds.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferScratch).To(Equal(result))
@@ -46,7 +47,7 @@ var _ = Describe("Registry data source", func() {
 		if scratchPath == "" {
 			scratchPath = tmpDir
 		}
-		ds = NewRegistryDataSource(ep, accKey, secKey, "", certDir, insecureRegistry)
+		ds = NewRegistryDataSource(ep, accKey, secKey, "", certDir, insecureRegistry, nil, "", -1)
 
 		// Need to pass in a real path if we don't want scratch space needed error.
 		result, err := ds.Transfer(scratchPath, false)
@@ -66,14 +67,14 @@ var _ = Describe("Registry data source", func() {
 	)
 
 	It("TransferFile should not be called", func() {
-		ds = NewRegistryDataSource("", "", "", "", "", true)
+		ds = NewRegistryDataSource("", "", "", "", "", true, nil, "", -1)
 		result, err := ds.TransferFile("file", false)
 		Expect(err).To(HaveOccurred())
 		Expect(ProcessingPhaseError).To(Equal(result))
 	})
 
 	It("GetTerminationMessage should contain labels collected from the image", func() {
-		ds = NewRegistryDataSource("", "", "", "", "", true)
+		ds = NewRegistryDataSource("", "", "", "", "", true, nil, "", -1)
 		ds.info = &types.ImageInspectInfo{
 			Env: []string{
 				"INSTANCETYPE_KUBEVIRT_IO_DEFAULT_INSTANCETYPE=u1.small",
@@ -89,13 +90,85 @@ var _ = Describe("Registry data source", func() {
 	})
 
 	It("Transfer should return error for bootc image", func() {
-		ds = NewRegistryDataSource("oci-archive:"+filepath.Join(imageDir, "bootc-registry-image.tar"), "", "", "", "", true)
+		ds = NewRegistryDataSource("oci-archive:"+filepath.Join(imageDir, "bootc-registry-image.tar"), "", "", "", "", true, nil, "", -1)
 		result, err := ds.Transfer(tmpDir, false)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("bootc image detected"))
 		Expect(ProcessingPhaseError).To(Equal(result))
 	})
 
+	Context("with disk selection", func() {
+		var (
+			registry *fakeRegistry
+			server   *httptest.Server
+			source   string
+		)
+
+		BeforeEach(func() {
+			registry = newFakeRegistry()
+			server = httptest.NewTLSServer(registry)
+			source = "docker://" + strings.TrimPrefix(server.URL, "https://") + "/test/disks:latest"
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		transferDisk := func(diskPath string, diskIndex int) (string, error) {
+			ds = NewRegistryDataSource(source, "", "", "", "", true, nil, diskPath, diskIndex)
+			result, err := ds.Transfer(tmpDir, false)
+			if err != nil {
+				Expect(result).To(Equal(ProcessingPhaseError))
+				return "", err
+			}
+			Expect(result).To(Equal(ProcessingPhaseConvert))
+			Expect(ds.GetURL().Path).To(HavePrefix(filepath.Join(tmpDir, containerDiskImageDir)))
+			return ds.GetURL().Path, nil
+		}
+
+		DescribeTable("should select a disk of a container image", func(diskPath string, diskIndex int, expected string) {
+			registry.addContainerImage("latest",
+				registry.addContainerDiskLayer("root.qcow2"),
+				registry.addContainerDiskLayer("data.qcow2", "data/logs.img"))
+			file, err := transferDisk(diskPath, diskIndex)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(file).To(Equal(filepath.Join(tmpDir, containerDiskImageDir, expected)))
+			Expect(os.ReadFile(file)).To(Equal(artifactDiskImage(expected)))
+			entries, err := os.ReadDir(filepath.Join(tmpDir, containerDiskImageDir))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+		},
+			Entry("by path", "data.qcow2", -1, "data.qcow2"),
+			Entry("by nested path", "data/logs.img", -1, "data/logs.img"),
+			Entry("by index in the first layer", "", 0, "root.qcow2"),
+			Entry("by index in a later layer", "", 2, "data/logs.img"),
+		)
+
+		DescribeTable("should select a disk of an artifact", func(diskPath string, diskIndex int, expected string) {
+			registry.addArtifact("latest", "application/vnd.example.disk.v1",
+				registry.addArtifactLayer("root.qcow2", artifactDiskImage("root.qcow2")),
+				registry.addArtifactLayer("data.qcow2", artifactDiskImage("data.qcow2")))
+			file, err := transferDisk(diskPath, diskIndex)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(file).To(Equal(filepath.Join(tmpDir, containerDiskImageDir, expected)))
+			Expect(os.ReadFile(file)).To(Equal(artifactDiskImage(expected)))
+		},
+			Entry("by path", "data.qcow2", -1, "data.qcow2"),
+			Entry("by index", "", 1, "data.qcow2"),
+		)
+
+		DescribeTable("should fail if the selected disk does not exist", func(diskPath string, diskIndex int) {
+			registry.addContainerImage("latest", registry.addContainerDiskLayer("root.qcow2", "data.qcow2"))
+			_, err := transferDisk(diskPath, diskIndex)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Failed to find VM disk image file"))
+		},
+			Entry("by path", "missing.qcow2", -1),
+			Entry("by path prefix", "data", -1),
+			Entry("by index", "", 2),
+		)
+	})
+
 	It("getImageFileName should return an error with non-existing image directory", func() {
 		_, err := getImageFileName("/invalid")
 		Expect(err).To(HaveOccurred())
@@ -0,0 +1,174 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// resumeMarkerSuffix is appended to the name of the file being written to get the name of its progress marker.
+	resumeMarkerSuffix = ".resume"
+)
+
+// may be overridden in tests
+var resumeCheckpointInterval = int64(256 << 20)
+
+// resumeMarker is persisted next to a partially written file, and records how far the transfer got so that a
+// restarted importer can continue where the previous one left off.
+type resumeMarker struct {
+	// Endpoint is the source the data was read from.
+	Endpoint string `json:"endpoint"`
+	// Validator is the strong ETag or Last-Modified value of the source, used to detect upstream changes.
+	Validator string `json:"validator"`
+	// ContentLength is the total size of the source.
+	ContentLength uint64 `json:"contentLength"`
+	// Offset is the number of bytes that are safely stored in the file.
+	Offset int64 `json:"offset"`
+}
+
+func resumeMarkerPath(fileName string) string {
+	return fileName + resumeMarkerSuffix
+}
+
+// readResumeMarker returns the progress marker of the passed in file, or nil if there is none.
+func readResumeMarker(fileName string) (*resumeMarker, error) {
+	data, err := os.ReadFile(resumeMarkerPath(fileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	marker := &resumeMarker{}
+	if err := json.Unmarshal(data, marker); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse progress marker of %s", fileName)
+	}
+	return marker, nil
+}
+
+// writeResumeMarker atomically replaces the progress marker of the passed in file.
+func writeResumeMarker(fileName string, marker *resumeMarker) error {
+	data, err := json.Marshal(marker)
+	if err != nil {
+		return err
+	}
+	markerPath := resumeMarkerPath(fileName)
+	tmpPath := markerPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, markerPath)
+}
+
+// removeResumeMarker removes the progress marker of the passed in file if it exists.
+func removeResumeMarker(fileName string) {
+	if err := os.Remove(resumeMarkerPath(fileName)); err != nil && !os.IsNotExist(err) {
+		klog.Warningf("Unable to remove progress marker of %s: %v", fileName, err)
+	}
+}
+
+// checkpointReader counts the bytes read from the source, and periodically flushes the target file and records
+// the amount of data that was written in the progress marker. This assumes the bytes read from the source are
+// written to the target file unmodified, and that everything returned by a Read was written before the next one.
+type checkpointReader struct {
+	r              io.Reader
+	fileName       string
+	marker         resumeMarker
+	lastCheckpoint int64
+}
+
+func newCheckpointReader(r io.Reader, fileName string, marker resumeMarker) *checkpointReader {
+	return &checkpointReader{
+		r:              r,
+		fileName:       fileName,
+		marker:         marker,
+		lastCheckpoint: marker.Offset,
+	}
+}
+
+// Read reads from the underlying reader, recording a checkpoint first if enough data has been written.
+func (cr *checkpointReader) Read(p []byte) (int, error) {
+	if cr.marker.Offset-cr.lastCheckpoint >= resumeCheckpointInterval {
+		// Best effort, failing to checkpoint only means more data has to be transferred again after a restart.
+		if err := cr.checkpoint(); err != nil {
+			klog.Warningf("Unable to record transfer progress: %v", err)
+		}
+	}
+	n, err := cr.r.Read(p)
+	cr.marker.Offset += int64(n)
+	return n, err
+}
+
+func (cr *checkpointReader) checkpoint() error {
+	// fsync applies to the whole file, not just the data written through this descriptor.
+	f, err := os.OpenFile(cr.fileName, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := writeResumeMarker(cr.fileName, &cr.marker); err != nil {
+		return err
+	}
+	klog.V(3).Infof("Recorded transfer progress of %s at offset %d", cr.fileName, cr.marker.Offset)
+	cr.lastCheckpoint = cr.marker.Offset
+	return nil
+}
+
+// streamDataToFileAt writes the data from the reader into the file starting at the passed in offset. Unlike
+// StreamDataToFile the file is kept on errors, so the transfer can be resumed later. Only the part of the
+// file written from the beginning is written sparse, resumed writes always write all the data.
+func streamDataToFileAt(r io.Reader, fileName string, offset int64, preallocate bool) error {
+	outFile, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY, os.ModePerm)
+	if err != nil {
+		return errors.Wrapf(err, "could not open file %q", fileName)
+	}
+	defer outFile.Close()
+	if err := outFile.Truncate(offset); err != nil {
+		return errors.Wrapf(err, "could not truncate file %q", fileName)
+	}
+	if _, err := outFile.Seek(offset, io.SeekStart); err != nil {
+		return errors.Wrapf(err, "could not seek file %q", fileName)
+	}
+
+	var bytesRead, bytesWritten int64
+	if offset == 0 && !preallocate {
+		bytesRead, bytesWritten, err = copyWithSparseCheck(outFile, r, appendZeroWithTruncateFunc)
+	} else {
+		bytesRead, err = io.Copy(outFile, r)
+		bytesWritten = bytesRead
+	}
+	klog.Infof("Read %d bytes, wrote %d bytes to %s starting at offset %d", bytesRead, bytesWritten, fileName, offset)
+	if err != nil {
+		klog.Errorf("Unable to write file from dataReader: %v\n", err)
+		if IsNoCapacityError(err) {
+			return fmt.Errorf("unable to write to file: %w", err)
+		}
+		return NewImagePullFailedError(err)
+	}
+	return outFile.Sync()
+}
@@ -0,0 +1,81 @@
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type failingReader struct {
+	r io.Reader
+}
+
+func (fr *failingReader) Read(p []byte) (int, error) {
+	n, err := fr.r.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection reset")
+	}
+	return n, err
+}
+
+var _ = Describe("Resumable transfers", func() {
+	var (
+		tmpDir   string
+		fileName string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "resume")
+		Expect(err).ToNot(HaveOccurred())
+		fileName = filepath.Join(tmpDir, "disk.img")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("should return nil when there is no progress marker", func() {
+		marker, err := readResumeMarker(fileName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(marker).To(BeNil())
+	})
+
+	It("should fail to read an invalid progress marker", func() {
+		Expect(os.WriteFile(resumeMarkerPath(fileName), []byte("{"), 0600)).To(Succeed())
+		_, err := readResumeMarker(fileName)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should write, read and remove a progress marker", func() {
+		marker := &resumeMarker{Endpoint: "http://example.com/disk.img", Validator: `"abc"`, ContentLength: 100, Offset: 50}
+		Expect(writeResumeMarker(fileName, marker)).To(Succeed())
+		read, err := readResumeMarker(fileName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(read).To(Equal(marker))
+		removeResumeMarker(fileName)
+		Expect(resumeMarkerPath(fileName)).ToNot(BeAnExistingFile())
+	})
+
+	It("should keep the file when the transfer fails", func() {
+		data := bytes.Repeat([]byte{0x55}, 4096)
+		err := streamDataToFileAt(&failingReader{r: bytes.NewReader(data)}, fileName, 0, false)
+		Expect(err).To(HaveOccurred())
+		result, err := os.ReadFile(fileName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(data))
+	})
+
+	It("should write after the offset and discard data beyond it", func() {
+		Expect(os.WriteFile(fileName, bytes.Repeat([]byte{0x55}, 4096), 0600)).To(Succeed())
+		Expect(streamDataToFileAt(bytes.NewReader(bytes.Repeat([]byte{0xAA}, 1024)), fileName, 1024, false)).To(Succeed())
+		result, err := os.ReadFile(fileName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(append(bytes.Repeat([]byte{0x55}, 1024), bytes.Repeat([]byte{0xAA}, 1024)...)))
+	})
+})
@@ -1,6 +1,11 @@
 package importer
 
 import (
+	"crypto/sha1" //nolint:gosec // SHA1 is only used to read the checksum stored with the object
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
 	"io"
 	"net/url"
 	"path/filepath"
@@ -16,6 +21,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util/checksum"
 )
 
 const (
@@ -23,6 +29,8 @@ const (
 	httpScheme  = "http"
 )
 
+var s3MD5ETagRegex = regexp.MustCompile(`^[0-9a-fA-F]{32}$`)
+
 // S3Client is the interface to the used S3 client.
 type S3Client interface {
 	GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error)
@@ -48,30 +56,46 @@ type S3DataSource struct {
 	readers *FormatReaders
 	// The image file in scratch space.
 	url *url.URL
+	// checksumValidator validates the checksum of downloaded data
+	checksumValidator *ChecksumValidator
 }
 
-// NewS3DataSource creates a new instance of the S3DataSource
-func NewS3DataSource(endpoint, accessKey, secKey string, certDir string) (*S3DataSource, error) {
+// NewS3DataSource creates a new instance of the S3DataSource. If no checksum is passed in, the checksum stored with
+// the object is used instead when it is known to describe the downloaded data.
+func NewS3DataSource(endpoint, accessKey, secKey string, certDir string, checksum string) (*S3DataSource, error) {
 	ep, err := ParseEndpoint(endpoint)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to parse endpoint %q", endpoint)
 	}
-	s3Reader, err := createS3Reader(ep, accessKey, secKey, certDir)
+	s3Reader, objectChecksum, err := createS3Reader(ep, accessKey, secKey, certDir)
 	if err != nil {
 		return nil, err
 	}
+	if checksum == "" && objectChecksum != "" {
+		klog.Infof("No checksum specified, using object metadata checksum %s", objectChecksum)
+		checksum = objectChecksum
+	}
+	checksumValidator, err := NewChecksumValidator(checksum)
+	if err != nil {
+		s3Reader.Close()
+		return nil, errors.Wrap(err, "invalid checksum")
+	}
+	if checksumValidator != nil {
+		klog.Infof("Checksum validation enabled: %s", checksum)
+	}
 	return &S3DataSource{
-		ep:        ep,
-		accessKey: accessKey,
-		secKey:    secKey,
-		s3Reader:  s3Reader,
+		ep:                ep,
+		accessKey:         accessKey,
+		secKey:            secKey,
+		s3Reader:          s3Reader,
+		checksumValidator: checksumValidator,
 	}, nil
 }
 
 // Info is called to get initial information about the data.
 func (sd *S3DataSource) Info() (ProcessingPhase, error) {
 	var err error
-	sd.readers, err = NewFormatReaders(sd.s3Reader, uint64(0), nil)
+	sd.readers, err = NewFormatReaders(sd.s3Reader, uint64(0), sd.checksumValidator)
 	if err != nil {
 		klog.Errorf("Error creating readers: %v", err)
 		return ProcessingPhaseError, err
@@ -101,6 +125,15 @@ func (sd *S3DataSource) Transfer(path string, preallocation bool) (ProcessingPha
 	if err != nil {
 		return ProcessingPhaseError, err
 	}
+	// Verify checksum if specified
+	if err := sd.readers.ValidateChecksum(); err != nil {
+		return ProcessingPhaseError, fmt.Errorf("checksum validation failed: %w", err)
+	}
+	if sd.readers.ArchiveZip {
+		if err := extractZipImage(file, preallocation); err != nil {
+			return ProcessingPhaseError, err
+		}
+	}
 	// If streaming succeeded, then parsing the file into URL will also succeed, no need to check error status
 	sd.url, _ = url.Parse(file)
 	return ProcessingPhaseConvert, nil
@@ -116,9 +149,39 @@ func (sd *S3DataSource) TransferFile(fileName string, preallocation bool) (Proce
 	if err != nil {
 		return ProcessingPhaseError, err
 	}
+	// Verify checksum if specified
+	if err := sd.readers.ValidateChecksum(); err != nil {
+		return ProcessingPhaseError, fmt.Errorf("checksum validation failed: %w", err)
+	}
+	return ProcessingPhaseResize, nil
+}
+
+// TransferConvertFile is called to convert the qcow2 image from the source to raw while it is transferred to the passed
+// in file.
+func (sd *S3DataSource) TransferConvertFile(fileName, scratchPath string, preallocation bool) (ProcessingPhase, error) {
+	if err := CleanAll(fileName); err != nil {
+		return ProcessingPhaseError, err
+	}
+	if err := StreamQcow2ToFile(sd.readers.TopReader(), fileName, scratchPath, preallocation); err != nil {
+		return ProcessingPhaseError, err
+	}
+	// Verify checksum if specified
+	if err := sd.readers.ValidateChecksum(); err != nil {
+		return ProcessingPhaseError, fmt.Errorf("checksum validation failed: %w", err)
+	}
 	return ProcessingPhaseResize, nil
 }
 
+// CanStreamConvert returns true if the image is a qcow2 image which can be converted while it is transferred.
+func (sd *S3DataSource) CanStreamConvert() bool {
+	return sd.readers.StreamConvert
+}
+
+// GetVirtualSize returns the virtual size of a qcow2 image.
+func (sd *S3DataSource) GetVirtualSize() int64 {
+	return sd.readers.VirtualSize
+}
+
 // GetURL returns the url that the data processor can use when converting the data.
 func (sd *S3DataSource) GetURL() *url.URL {
 	return sd.url
@@ -138,7 +201,8 @@ func (sd *S3DataSource) Close() error {
 	return err
 }
 
-func createS3Reader(ep *url.URL, accessKey, secKey string, certDir string) (io.ReadCloser, error) {
+// createS3Reader returns a reader of the object, and the checksum of the object derived from its metadata if available.
+func createS3Reader(ep *url.URL, accessKey, secKey string, certDir string) (io.ReadCloser, string, error) {
 	klog.V(3).Infoln("Using S3 client to get data")
 
 	endpoint := ep.Host
@@ -151,22 +215,69 @@ func createS3Reader(ep *url.URL, accessKey, secKey string, certDir string) (io.R
 	klog.V(1).Infof("object %s", object)
 	svc, err := newClientFunc(endpoint, accessKey, secKey, certDir, urlScheme)
 	if err != nil {
-		return nil, errors.Wrapf(err, "could not build s3 client for %q", ep.Host)
+		return nil, "", errors.Wrapf(err, "could not build s3 client for %q", ep.Host)
 	}
 
 	objInput := &s3.GetObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(object),
+		// Ask for the additional checksums of the object, which unlike the ETag do not depend on encryption
+		ChecksumMode: aws.String(s3.ChecksumModeEnabled),
 	}
 	objOutput, err := svc.GetObject(objInput)
 	if err != nil {
-		return nil, errors.Wrapf(err, "could not get s3 object: \"%s/%s\"", bucket, object)
+		return nil, "", errors.Wrapf(err, "could not get s3 object: \"%s/%s\"", bucket, object)
 	}
 	objectReader := objOutput.Body
-	return objectReader, nil
+	return objectReader, getS3ObjectChecksum(objOutput), nil
+}
+
+// getS3ObjectChecksum returns the checksum of the object derived from its metadata, or nothing when the metadata
+// does not provably describe the downloaded bytes. A full object SHA256 or SHA1 checksum is preferred. Otherwise the
+// ETag is only used when it is known to be the MD5 digest of the object: it is not for multipart uploads, for objects
+// encrypted with SSE-KMS or SSE-C, or for content encoded objects that may be decoded in transit.
+func getS3ObjectChecksum(objOutput *s3.GetObjectOutput) string {
+	if sum := s3FullObjectChecksum(checksum.AlgorithmSHA256, objOutput.ChecksumSHA256, sha256.Size); sum != "" {
+		return sum
+	}
+	if sum := s3FullObjectChecksum(checksum.AlgorithmSHA1, objOutput.ChecksumSHA1, sha1.Size); sum != "" {
+		return sum
+	}
+	if objOutput.ETag == nil || objOutput.PartsCount != nil {
+		return ""
+	}
+	if strings.HasPrefix(aws.StringValue(objOutput.ServerSideEncryption), s3.ServerSideEncryptionAwsKms) ||
+		objOutput.SSEKMSKeyId != nil || objOutput.SSECustomerAlgorithm != nil {
+		return ""
+	}
+	if aws.StringValue(objOutput.ContentEncoding) != "" {
+		return ""
+	}
+	etag := strings.Trim(aws.StringValue(objOutput.ETag), "\"")
+	if !s3MD5ETagRegex.MatchString(etag) {
+		return ""
+	}
+	return checksum.AlgorithmMD5 + ":" + strings.ToLower(etag)
+}
+
+// s3FullObjectChecksum converts a base64 encoded additional checksum to the checksum format, ignoring the composite
+// checksums of multipart uploads, which carry a part count suffix.
+func s3FullObjectChecksum(algorithm string, value *string, size int) string {
+	if value == nil {
+		return ""
+	}
+	digest, err := base64.StdEncoding.DecodeString(aws.StringValue(value))
+	if err != nil || len(digest) != size {
+		return ""
+	}
+	return algorithm + ":" + hex.EncodeToString(digest)
 }
 
 func getS3Client(endpoint, accessKey, secKey string, certDir string, urlScheme string) (S3Client, error) {
+	return newS3Service(endpoint, accessKey, secKey, certDir, urlScheme)
+}
+
+func newS3Service(endpoint, accessKey, secKey string, certDir string, urlScheme string) (*s3.S3, error) {
 	// Adding certs using CustomCABundle will overwrite the SystemCerts, so we opt by creating a custom HTTPClient
 	httpClient, err := createHTTPClient(certDir, false)
 
@@ -1,6 +1,9 @@
 package importer
 
 import (
+	"bytes"
+	"crypto/md5" //nolint:gosec // MD5 is only used to create a test checksum
+	"encoding/hex"
 	"io"
 	"os"
 	"path/filepath"
@@ -9,6 +12,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/pkg/errors"
 )
@@ -36,25 +40,25 @@ var _ = Describe("S3 data source", func() {
 	})
 
 	It("NewS3DataSource should Error, when passed in an invalid endpoint", func() {
-		sd, err = NewS3DataSource("thisisinvalid#$%#ep", "", "", "")
+		sd, err = NewS3DataSource("thisisinvalid#$%#ep", "", "", "", "")
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("NewS3DataSource should Error, when failing to create S3 client", func() {
 		newClientFunc = failMockS3Client
-		sd, err = NewS3DataSource("http://amazon.com", "", "", "")
+		sd, err = NewS3DataSource("http://amazon.com", "", "", "", "")
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("NewS3DataSource should Error, when failing to get object", func() {
 		newClientFunc = createErrMockS3Client
-		sd, err = NewS3DataSource("http://amazon.com", "", "", "")
+		sd, err = NewS3DataSource("http://amazon.com", "", "", "", "")
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("NewS3DataSource should fail when called with an invalid certdir", func() {
 		newClientFunc = getS3Client
-		sd, err = NewS3DataSource("http://amazon.com", "", "", "/invaliddir")
+		sd, err = NewS3DataSource("http://amazon.com", "", "", "/invaliddir", "")
 		Expect(err).To(HaveOccurred())
 	})
 
@@ -64,7 +68,7 @@ var _ = Describe("S3 data source", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = file.Close()
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.s3Reader = file
 		result, err := sd.Info()
@@ -76,7 +80,7 @@ var _ = Describe("S3 data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.s3Reader = file
 		result, err := sd.Info()
@@ -88,7 +92,7 @@ var _ = Describe("S3 data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		sd.s3Reader = file
 		result, err := sd.Info()
@@ -103,7 +107,7 @@ var _ = Describe("S3 data source", func() {
 		sourceFile, err := os.Open(fileName)
 		Expect(err).NotTo(HaveOccurred())
 
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		// Replace minio.Object with a reader we can use.
 		sd.s3Reader = sourceFile
@@ -137,7 +141,7 @@ var _ = Describe("S3 data source", func() {
 		sourceFile, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
 
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		// Replace minio.Object with a reader we can use.
 		sd.s3Reader = sourceFile
@@ -155,7 +159,7 @@ var _ = Describe("S3 data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		// Replace minio.Object with a reader we can use.
 		sd.s3Reader = file
@@ -171,7 +175,7 @@ var _ = Describe("S3 data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", "")
 		Expect(err).NotTo(HaveOccurred())
 		// Replace minio.Object with a reader we can use.
 		sd.s3Reader = file
@@ -183,6 +187,86 @@ var _ = Describe("S3 data source", func() {
 		Expect(ProcessingPhaseError).To(Equal(result))
 	})
 
+	DescribeTable("TransferFile should validate checksum", func(checksum string, wantErr bool) {
+		data := bytes.Repeat([]byte{0x55}, 1024)
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", checksum)
+		Expect(err).NotTo(HaveOccurred())
+		sd.s3Reader = io.NopCloser(bytes.NewReader(data))
+		result, err := sd.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
+		result, err = sd.TransferFile(filepath.Join(tmpDir, "file"), false)
+		if wantErr {
+			Expect(err).To(MatchError(ErrChecksumMismatch))
+			Expect(ProcessingPhaseError).To(Equal(result))
+		} else {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ProcessingPhaseResize).To(Equal(result))
+		}
+	},
+		Entry("succeed with matching checksum", "md5:"+md5Hex(bytes.Repeat([]byte{0x55}, 1024)), false),
+		Entry("fail with mismatching checksum", "md5:"+md5Hex([]byte("other")), true),
+	)
+
+	It("NewS3DataSource should fail with an invalid checksum", func() {
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", "md5:invalid")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("NewS3DataSource should use the object ETag if no checksum is specified", func() {
+		newClientFunc = createETagMockS3Client("\"" + md5Hex([]byte("other")) + "\"")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sd.checksumValidator).ToNot(BeNil())
+		sd.s3Reader = io.NopCloser(bytes.NewReader(bytes.Repeat([]byte{0x55}, 1024)))
+		_, err = sd.Info()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = sd.TransferFile(filepath.Join(tmpDir, "file"), false)
+		Expect(err).To(MatchError(ErrChecksumMismatch))
+	})
+
+	It("NewS3DataSource should prefer the specified checksum over the object ETag", func() {
+		data := bytes.Repeat([]byte{0x55}, 1024)
+		newClientFunc = createETagMockS3Client("\"" + md5Hex([]byte("other")) + "\"")
+		sd, err = NewS3DataSource("http://region.amazon.com/bucket-1/object-1", "", "", "", "md5:"+md5Hex(data))
+		Expect(err).NotTo(HaveOccurred())
+		sd.s3Reader = io.NopCloser(bytes.NewReader(data))
+		_, err = sd.Info()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = sd.TransferFile(filepath.Join(tmpDir, "file"), false)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	DescribeTable("getS3ObjectChecksum should", func(objOutput *s3.GetObjectOutput, want string) {
+		Expect(getS3ObjectChecksum(objOutput)).To(Equal(want))
+	},
+		Entry("return nothing without an ETag", &s3.GetObjectOutput{}, ""),
+		Entry("return the MD5 of a single part upload",
+			&s3.GetObjectOutput{ETag: aws.String("\"D41D8CD98F00B204E9800998ECF8427E\"")}, "md5:d41d8cd98f00b204e9800998ecf8427e"),
+		Entry("return nothing for a multipart upload",
+			&s3.GetObjectOutput{ETag: aws.String("\"d41d8cd98f00b204e9800998ecf8427e-2\"")}, ""),
+		Entry("return nothing for an SSE-KMS encrypted object",
+			&s3.GetObjectOutput{ETag: aws.String("\"d41d8cd98f00b204e9800998ecf8427e\""), ServerSideEncryption: aws.String(s3.ServerSideEncryptionAwsKms)}, ""),
+		Entry("return nothing for an SSE-C encrypted object",
+			&s3.GetObjectOutput{ETag: aws.String("\"d41d8cd98f00b204e9800998ecf8427e\""), SSECustomerAlgorithm: aws.String("AES256")}, ""),
+		Entry("return nothing for an SSE-KMS DSSE encrypted object",
+			&s3.GetObjectOutput{ETag: aws.String("\"d41d8cd98f00b204e9800998ecf8427e\""), ServerSideEncryption: aws.String(s3.ServerSideEncryptionAwsKmsDsse)}, ""),
+		Entry("return nothing for an object with a parts count",
+			&s3.GetObjectOutput{ETag: aws.String("\"d41d8cd98f00b204e9800998ecf8427e\""), PartsCount: aws.Int64(1)}, ""),
+		Entry("return nothing for a content encoded object",
+			&s3.GetObjectOutput{ETag: aws.String("\"d41d8cd98f00b204e9800998ecf8427e\""), ContentEncoding: aws.String("gzip")}, ""),
+		Entry("return the full object SHA256 checksum",
+			&s3.GetObjectOutput{
+				ETag:                 aws.String("\"d41d8cd98f00b204e9800998ecf8427e-2\""),
+				ServerSideEncryption: aws.String(s3.ServerSideEncryptionAwsKms),
+				ChecksumSHA256:       aws.String("47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="),
+			}, "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"),
+		Entry("return the full object SHA1 checksum",
+			&s3.GetObjectOutput{ChecksumSHA1: aws.String("2jmj7l5rSw0yVb/vlWAYkK/YBwk=")}, "sha1:da39a3ee5e6b4b0d3255bfef95601890afd80709"),
+		Entry("return nothing for a composite SHA256 checksum",
+			&s3.GetObjectOutput{ChecksumSHA256: aws.String("47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=-2")}, ""),
+	)
+
 	It("GetS3Client should return a real client", func() {
 		_, err := getS3Client("", "", "", "", "")
 		Expect(err).NotTo(HaveOccurred())
@@ -206,6 +290,16 @@ type MockS3Client struct {
 	secKey   string
 	certDir  string
 	doErr    bool
+	etag     string
+}
+
+func md5Sum(data []byte) []byte {
+	sum := md5.Sum(data) //nolint:gosec // MD5 is only used to create a test checksum
+	return sum[:]
+}
+
+func md5Hex(data []byte) string {
+	return hex.EncodeToString(md5Sum(data))
 }
 
 func failMockS3Client(endpoint, accKey, secKey string, certDir string, urlScheme string) (S3Client, error) {
@@ -227,9 +321,21 @@ func createErrMockS3Client(endpoint, accKey, secKey string, certDir string, urlS
 	}, nil
 }
 
+func createETagMockS3Client(etag string) func(string, string, string, string, string) (S3Client, error) {
+	return func(endpoint, accKey, secKey string, certDir string, urlScheme string) (S3Client, error) {
+		return &MockS3Client{
+			etag: etag,
+		}, nil
+	}
+}
+
 func (mc *MockS3Client) GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
 	if !mc.doErr {
-		return &s3.GetObjectOutput{}, nil
+		output := &s3.GetObjectOutput{}
+		if mc.etag != "" {
+			output.ETag = aws.String(mc.etag)
+		}
+		return output, nil
 	}
 	return nil, errors.New("Failed to get object")
 }
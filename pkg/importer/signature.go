@@ -0,0 +1,500 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/pkg/blobinfocache"
+	"github.com/containers/image/v5/types"
+	"github.com/docker/distribution/registry/api/errcode"
+	v2 "github.com/docker/distribution/registry/api/v2"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const (
+	cosignSignatureTagSuffix     = ".sig"
+	cosignSimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	cosignSignatureAnnotation    = "dev.cosignproject.cosign/signature"
+	cosignCertificateAnnotation  = "dev.sigstore.cosign/certificate"
+	cosignChainAnnotation        = "dev.sigstore.cosign/chain"
+	cosignBundleAnnotation       = "dev.sigstore.cosign/bundle"
+	cosignSignatureType          = "cosign container image signature"
+	rekorHashedRekordKind        = "hashedrekord"
+
+	// SignaturePublicKeyFile is the name of the PEM encoded cosign public key in the signature policy directory
+	SignaturePublicKeyFile = "cosign.pub"
+	// SignatureFulcioRootsFile is the name of the PEM encoded Fulcio certificates in the signature policy directory
+	SignatureFulcioRootsFile = "fulcio.crt"
+	// SignatureRekorKeyFile is the name of the PEM encoded Rekor public key in the signature policy directory
+	SignatureRekorKeyFile = "rekor.pub"
+
+	// maxSignaturePayloadSize limits the size of signature payloads read from the registry
+	maxSignaturePayloadSize = 1 << 20
+)
+
+var (
+	// Fulcio certificate extensions containing the OIDC issuer that authenticated the signer
+	oidFulcioIssuerV1 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidFulcioIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// ErrSignatureVerification is returned when a registry image has no signature that satisfies the signature policy.
+// Use errors.Is(err, ErrSignatureVerification) to check for this error type.
+var ErrSignatureVerification = errors.New("signature verification failed")
+
+// SignaturePolicy describes the cosign signature a registry image must have to be imported. Either publicKey is
+// set, or the fields used to verify keyless signatures.
+type SignaturePolicy struct {
+	publicKey crypto.PublicKey
+
+	fulcioRoots         *x509.CertPool
+	fulcioIntermediates *x509.CertPool
+	rekorKey            crypto.PublicKey
+	issuer              string
+	subject             string
+}
+
+// simpleSigningPayload is the part of the cosign signature payload that is verified.
+type simpleSigningPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// rekorBundle is the proof of inclusion in the Rekor transparency log attached to keyless signatures.
+type rekorBundle struct {
+	SignedEntryTimestamp []byte             `json:"SignedEntryTimestamp"`
+	Payload              rekorBundlePayload `json:"Payload"`
+}
+
+// rekorBundlePayload is signed by Rekor. The fields are ordered to match the canonical JSON encoding.
+type rekorBundlePayload struct {
+	Body           string `json:"body"`
+	IntegratedTime int64  `json:"integratedTime"`
+	LogID          string `json:"logID"`
+	LogIndex       int64  `json:"logIndex"`
+}
+
+// hashedRekord is the part of a Rekor hashedrekord entry that is verified.
+type hashedRekord struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Data struct {
+			Hash struct {
+				Algorithm string `json:"algorithm"`
+				Value     string `json:"value"`
+			} `json:"hash"`
+		} `json:"data"`
+		Signature struct {
+			Content   []byte `json:"content"`
+			PublicKey struct {
+				Content []byte `json:"content"`
+			} `json:"publicKey"`
+		} `json:"signature"`
+	} `json:"spec"`
+}
+
+// LoadSignaturePolicy reads the signature policy of the passed in type from the files in dir. Returns nil if no
+// policy type is passed in.
+func LoadSignaturePolicy(policyType, dir, issuer, subject string) (*SignaturePolicy, error) {
+	switch policyType {
+	case "":
+		return nil, nil
+	case common.SignaturePolicyPublicKey:
+		publicKey, err := readPublicKey(filepath.Join(dir, SignaturePublicKeyFile))
+		if err != nil {
+			return nil, err
+		}
+		return &SignaturePolicy{publicKey: publicKey}, nil
+	case common.SignaturePolicyKeyless:
+		if issuer == "" || subject == "" {
+			return nil, errors.New("keyless signature verification requires an issuer and a subject")
+		}
+		data, err := os.ReadFile(filepath.Join(dir, SignatureFulcioRootsFile))
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read Fulcio certificates")
+		}
+		certs, err := parseCertificates(data)
+		if err != nil || len(certs) == 0 {
+			return nil, errors.Errorf("no valid Fulcio certificates in %s", SignatureFulcioRootsFile)
+		}
+		roots, intermediates := x509.NewCertPool(), x509.NewCertPool()
+		for _, cert := range certs {
+			if isSelfSigned(cert) {
+				roots.AddCert(cert)
+			} else {
+				intermediates.AddCert(cert)
+			}
+		}
+		rekorKey, err := readPublicKey(filepath.Join(dir, SignatureRekorKeyFile))
+		if err != nil {
+			return nil, err
+		}
+		return &SignaturePolicy{
+			fulcioRoots:         roots,
+			fulcioIntermediates: intermediates,
+			rekorKey:            rekorKey,
+			issuer:              issuer,
+			subject:             subject,
+		}, nil
+	}
+	return nil, errors.Errorf("unknown signature policy %q", policyType)
+}
+
+// verifyImage verifies the image of the passed in source has a cosign signature that satisfies the policy. The
+// signature is looked up in the same repository, under the tag cosign derives from the manifest digest.
+func (p *SignaturePolicy) verifyImage(ctx context.Context, sys *types.SystemContext, src types.ImageSource) error {
+	named := src.Reference().DockerReference()
+	if named == nil {
+		return errors.Wrap(ErrSignatureVerification, "signatures can only be verified for images in a registry")
+	}
+	manifestBlob, _, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "unable to get image manifest")
+	}
+	imageDigest, err := manifest.Digest(manifestBlob)
+	if err != nil {
+		return errors.Wrap(err, "unable to calculate image digest")
+	}
+	klog.Infof("Verifying signature of %s@%s", named.Name(), imageDigest)
+
+	sigTag := imageDigest.Algorithm().String() + "-" + imageDigest.Encoded() + cosignSignatureTagSuffix
+	sigNamed, err := reference.WithTag(reference.TrimNamed(named), sigTag)
+	if err != nil {
+		return err
+	}
+	sigRef, err := docker.NewReference(sigNamed)
+	if err != nil {
+		return err
+	}
+	sigSrc, err := sigRef.NewImageSource(ctx, sys)
+	if err != nil {
+		if isManifestUnknownError(err) {
+			return errors.Wrapf(ErrSignatureVerification, "no signature found for %s", imageDigest)
+		}
+		return errors.Wrap(err, "unable to get image signatures")
+	}
+	defer closeImage(sigSrc)
+	sigManifestBlob, _, err := sigSrc.GetManifest(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "unable to get image signatures")
+	}
+	sigManifest, err := manifest.OCI1FromManifest(sigManifestBlob)
+	if err != nil {
+		return errors.Wrapf(ErrSignatureVerification, "invalid signature manifest: %v", err)
+	}
+
+	cache := blobinfocache.DefaultCache(sys)
+	var failures []string
+	for _, layer := range sigManifest.Layers {
+		if layer.MediaType != cosignSimpleSigningMediaType {
+			continue
+		}
+		payload, err := readSignaturePayload(ctx, sigSrc, types.BlobInfo{Digest: layer.Digest, Size: layer.Size}, cache)
+		if err != nil {
+			return err
+		}
+		if err := p.verifySignatureLayer(payload, layer.Annotations, imageDigest); err != nil {
+			klog.V(1).Infof("Signature %s rejected: %v", layer.Digest, err)
+			failures = append(failures, err.Error())
+			continue
+		}
+		klog.Infof("Signature %s of %s@%s verified", layer.Digest, named.Name(), imageDigest)
+		return nil
+	}
+	if len(failures) == 0 {
+		return errors.Wrapf(ErrSignatureVerification, "no signature found for %s", imageDigest)
+	}
+	return errors.Wrapf(ErrSignatureVerification, "no valid signature found for %s: %s", imageDigest, strings.Join(failures, "; "))
+}
+
+// verifySignatureLayer verifies a single cosign signature, and that the signed payload refers to the image digest.
+func (p *SignaturePolicy) verifySignatureLayer(payload []byte, annotations map[string]string, imageDigest digest.Digest) error {
+	sig, err := base64.StdEncoding.DecodeString(annotations[cosignSignatureAnnotation])
+	if err != nil || len(sig) == 0 {
+		return errors.New("missing or invalid signature annotation")
+	}
+	if p.publicKey != nil {
+		if err := verifySignature(p.publicKey, payload, sig); err != nil {
+			return err
+		}
+	} else if err := p.verifyKeyless(payload, sig, annotations); err != nil {
+		return err
+	}
+
+	signed := &simpleSigningPayload{}
+	if err := json.Unmarshal(payload, signed); err != nil {
+		return errors.Wrap(err, "invalid signature payload")
+	}
+	if signed.Critical.Type != cosignSignatureType {
+		return errors.Errorf("unexpected signature type %q", signed.Critical.Type)
+	}
+	if signed.Critical.Image.DockerManifestDigest != imageDigest.String() {
+		return errors.Errorf("signature is for %s", signed.Critical.Image.DockerManifestDigest)
+	}
+	return nil
+}
+
+// verifyKeyless verifies a signature made with a short lived Fulcio certificate. As the certificate has expired by
+// now, its validity is checked at the time the signature was recorded in the Rekor transparency log.
+func (p *SignaturePolicy) verifyKeyless(payload, sig []byte, annotations map[string]string) error {
+	certs, err := parseCertificates([]byte(annotations[cosignCertificateAnnotation]))
+	if err != nil || len(certs) == 0 {
+		return errors.New("missing or invalid signing certificate")
+	}
+	leaf := certs[0]
+
+	integratedTime, err := p.verifyRekorBundle(annotations[cosignBundleAnnotation], payload, sig, leaf)
+	if err != nil {
+		return err
+	}
+
+	intermediates := p.fulcioIntermediates.Clone()
+	chain, err := parseCertificates([]byte(annotations[cosignChainAnnotation]))
+	if err != nil {
+		return errors.Wrap(err, "invalid certificate chain")
+	}
+	for _, cert := range chain {
+		if !isSelfSigned(cert) {
+			intermediates.AddCert(cert)
+		}
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         p.fulcioRoots,
+		Intermediates: intermediates,
+		CurrentTime:   integratedTime,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return errors.Wrap(err, "signing certificate is not trusted")
+	}
+
+	if !certificateHasSubject(leaf, p.subject) {
+		return errors.Errorf("signing certificate was not issued to %q", p.subject)
+	}
+	issuer, err := certificateIssuer(leaf)
+	if err != nil {
+		return err
+	}
+	if issuer != p.issuer {
+		return errors.Errorf("signing certificate was issued by %q", issuer)
+	}
+
+	return verifySignature(leaf.PublicKey, payload, sig)
+}
+
+// verifyRekorBundle verifies the Rekor bundle is signed by the transparency log and refers to the signature, and
+// returns the time the signature was recorded in the log.
+func (p *SignaturePolicy) verifyRekorBundle(bundleJSON string, payload, sig []byte, leaf *x509.Certificate) (time.Time, error) {
+	if bundleJSON == "" {
+		return time.Time{}, errors.New("missing transparency log bundle")
+	}
+	bundle := &rekorBundle{}
+	if err := json.Unmarshal([]byte(bundleJSON), bundle); err != nil {
+		return time.Time{}, errors.Wrap(err, "invalid transparency log bundle")
+	}
+	canonicalPayload, err := json.Marshal(bundle.Payload)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := verifySignature(p.rekorKey, canonicalPayload, bundle.SignedEntryTimestamp); err != nil {
+		return time.Time{}, errors.Wrap(err, "transparency log bundle")
+	}
+
+	body, err := base64.StdEncoding.DecodeString(bundle.Payload.Body)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "invalid transparency log entry")
+	}
+	entry := &hashedRekord{}
+	if err := json.Unmarshal(body, entry); err != nil {
+		return time.Time{}, errors.Wrap(err, "invalid transparency log entry")
+	}
+	if entry.Kind != rekorHashedRekordKind {
+		return time.Time{}, errors.Errorf("unsupported transparency log entry kind %q", entry.Kind)
+	}
+	payloadHash := sha256.Sum256(payload)
+	if entry.Spec.Data.Hash.Algorithm != "sha256" || entry.Spec.Data.Hash.Value != hex.EncodeToString(payloadHash[:]) {
+		return time.Time{}, errors.New("transparency log entry does not match the signed payload")
+	}
+	if !bytes.Equal(entry.Spec.Signature.Content, sig) {
+		return time.Time{}, errors.New("transparency log entry does not match the signature")
+	}
+	entryCerts, err := parseCertificates(entry.Spec.Signature.PublicKey.Content)
+	if err != nil || len(entryCerts) == 0 || !entryCerts[0].Equal(leaf) {
+		return time.Time{}, errors.New("transparency log entry does not match the signing certificate")
+	}
+	return time.Unix(bundle.Payload.IntegratedTime, 0), nil
+}
+
+// verifySignature verifies a signature over data, using SHA-256 as digest for ECDSA and RSA keys like cosign does.
+func verifySignature(publicKey crypto.PublicKey, data, sig []byte) error {
+	hash := sha256.Sum256(data)
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, hash[:], sig) {
+			return errors.New("invalid signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], sig); err != nil {
+			return errors.New("invalid signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, data, sig) {
+			return errors.New("invalid signature")
+		}
+	default:
+		return errors.Errorf("unsupported public key type %T", publicKey)
+	}
+	return nil
+}
+
+func readSignaturePayload(ctx context.Context, src types.ImageSource, blob types.BlobInfo, cache types.BlobInfoCache) ([]byte, error) {
+	reader, _, err := src.GetBlob(ctx, blob, cache)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read signature")
+	}
+	defer reader.Close()
+	payload, err := io.ReadAll(io.LimitReader(reader, maxSignaturePayloadSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read signature")
+	}
+	if len(payload) > maxSignaturePayloadSize {
+		return nil, errors.Wrap(ErrSignatureVerification, "signature payload too large")
+	}
+	if blob.Digest.Validate() != nil || blob.Digest.Algorithm().FromBytes(payload) != blob.Digest {
+		return nil, errors.Wrapf(ErrSignatureVerification, "signature payload does not match digest %s", blob.Digest)
+	}
+	return payload, nil
+}
+
+func readPublicKey(fileName string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read public key %s", filepath.Base(fileName))
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Errorf("no PEM encoded public key in %s", filepath.Base(fileName))
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid public key in %s", filepath.Base(fileName))
+	}
+	return publicKey, nil
+}
+
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil
+}
+
+func certificateHasSubject(cert *x509.Certificate, subject string) bool {
+	for _, email := range cert.EmailAddresses {
+		if email == subject {
+			return true
+		}
+	}
+	for _, uri := range cert.URIs {
+		if uri.String() == subject {
+			return true
+		}
+	}
+	return false
+}
+
+func certificateIssuer(cert *x509.Certificate) (string, error) {
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oidFulcioIssuerV2) {
+			var issuer string
+			if _, err := asn1.Unmarshal(ext.Value, &issuer); err != nil {
+				return "", errors.Wrap(err, "invalid issuer in signing certificate")
+			}
+			return issuer, nil
+		}
+	}
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oidFulcioIssuerV1) {
+			return string(ext.Value), nil
+		}
+	}
+	return "", errors.New("signing certificate has no issuer")
+}
+
+// isManifestUnknownError returns true if the registry reported the requested manifest does not exist.
+func isManifestUnknownError(err error) bool {
+	var ec errcode.ErrorCoder
+	if errors.As(err, &ec) && ec.ErrorCode() == v2.ErrorCodeManifestUnknown {
+		return true
+	}
+	var e errcode.Error
+	return errors.As(err, &e) && e.ErrorCode() == errcode.ErrorCodeUnknown && strings.Contains(strings.ToLower(e.Message), "not found")
+}
+
+// String describes the policy for log messages.
+func (p *SignaturePolicy) String() string {
+	if p.publicKey != nil {
+		return "public key"
+	}
+	return fmt.Sprintf("keyless, issuer %q, subject %q", p.issuer, p.subject)
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+const (
+	testSignatureRepository = "test/image"
+	testSignatureIssuer     = "https://accounts.example.com"
+	testSignatureSubject    = "signer@example.com"
+)
+
+// addSignatures stores the signature layers under the tag cosign uses for the image digest
+func (r *fakeRegistry) addSignatures(imageDigest digest.Digest, layers ...imgspecv1.Descriptor) {
+	config := r.addBlob([]byte("{}"))
+	config.MediaType = imgspecv1.MediaTypeImageConfig
+	r.addImageManifest(imageDigest.Algorithm().String()+"-"+imageDigest.Encoded()+".sig", imgspecv1.Manifest{Config: config, Layers: layers})
+}
+
+func (r *fakeRegistry) addSignatureLayer(payload []byte, annotations map[string]string) imgspecv1.Descriptor {
+	layer := r.addBlob(payload)
+	layer.MediaType = cosignSimpleSigningMediaType
+	layer.Annotations = annotations
+	return layer
+}
+
+func newSignaturePayload(imageDigest digest.Digest) []byte {
+	return []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"%s"},"image":{"docker-manifest-digest":"%s"},"type":"%s"},"optional":null}`,
+		testSignatureRepository, imageDigest, cosignSignatureType))
+}
+
+func signPayload(key *ecdsa.PrivateKey, payload []byte) []byte {
+	hash := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	Expect(err).ToNot(HaveOccurred())
+	return sig
+}
+
+func newSigningKey() *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	return key
+}
+
+func writePEM(fileName, blockType string, data []byte) {
+	Expect(os.WriteFile(fileName, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), 0600)).To(Succeed())
+}
+
+func writePublicKey(fileName string, key crypto.PublicKey) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	Expect(err).ToNot(HaveOccurred())
+	writePEM(fileName, "PUBLIC KEY", der)
+}
+
+var _ = Describe("Signature verification", func() {
+	var (
+		registry    *fakeRegistry
+		server      *httptest.Server
+		sys         *types.SystemContext
+		policyDir   string
+		imageDigest digest.Digest
+	)
+
+	BeforeEach(func() {
+		var err error
+		registry = newFakeRegistry()
+		server = httptest.NewTLSServer(registry)
+		policyDir, err = os.MkdirTemp("", "signature")
+		Expect(err).ToNot(HaveOccurred())
+		sys = &types.SystemContext{
+			DockerInsecureSkipTLSVerify: types.NewOptionalBool(true),
+			BlobInfoCacheDir:            policyDir,
+			AuthFilePath:                filepath.Join(policyDir, "auth.json"),
+		}
+
+		config := registry.addBlob([]byte(`{"architecture":"amd64","os":"linux"}`))
+		config.MediaType = imgspecv1.MediaTypeImageConfig
+		layer := registry.addBlob([]byte("disk"))
+		layer.MediaType = imgspecv1.MediaTypeImageLayer
+		imageDigest = registry.addImageManifest("latest", imgspecv1.Manifest{Config: config, Layers: []imgspecv1.Descriptor{layer}})
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.RemoveAll(policyDir)
+	})
+
+	verifyImage := func(policy *SignaturePolicy) error {
+		named, err := reference.ParseNormalizedNamed(strings.TrimPrefix(server.URL, "https://") + "/" + testSignatureRepository + ":latest")
+		Expect(err).ToNot(HaveOccurred())
+		ref, err := docker.NewReference(named)
+		Expect(err).ToNot(HaveOccurred())
+		src, err := ref.NewImageSource(context.Background(), sys)
+		Expect(err).ToNot(HaveOccurred())
+		defer closeImage(src)
+		return policy.verifyImage(context.Background(), sys, src)
+	}
+
+	Context("with a public key", func() {
+		var signingKey *ecdsa.PrivateKey
+		var policy *SignaturePolicy
+
+		BeforeEach(func() {
+			var err error
+			signingKey = newSigningKey()
+			writePublicKey(filepath.Join(policyDir, SignaturePublicKeyFile), signingKey.Public())
+			policy, err = LoadSignaturePolicy(common.SignaturePolicyPublicKey, policyDir, "", "")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should accept an image with a valid signature", func() {
+			payload := newSignaturePayload(imageDigest)
+			registry.addSignatures(imageDigest, registry.addSignatureLayer(payload, map[string]string{
+				cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signPayload(signingKey, payload)),
+			}))
+			Expect(verifyImage(policy)).To(Succeed())
+		})
+
+		It("should accept an image if one of the signatures is valid", func() {
+			payload := newSignaturePayload(imageDigest)
+			registry.addSignatures(imageDigest,
+				registry.addSignatureLayer(payload, map[string]string{
+					cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signPayload(newSigningKey(), payload)),
+				}),
+				registry.addSignatureLayer(payload, map[string]string{
+					cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signPayload(signingKey, payload)),
+				}))
+			Expect(verifyImage(policy)).To(Succeed())
+		})
+
+		It("should reject an unsigned image", func() {
+			err := verifyImage(policy)
+			Expect(err).To(MatchError(ErrSignatureVerification))
+			Expect(err.Error()).To(ContainSubstring("no signature found"))
+		})
+
+		It("should reject an image signed with another key", func() {
+			payload := newSignaturePayload(imageDigest)
+			registry.addSignatures(imageDigest, registry.addSignatureLayer(payload, map[string]string{
+				cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signPayload(newSigningKey(), payload)),
+			}))
+			Expect(verifyImage(policy)).To(MatchError(ErrSignatureVerification))
+		})
+
+		It("should reject a signature of another image", func() {
+			payload := newSignaturePayload(digest.FromString("other image"))
+			registry.addSignatures(imageDigest, registry.addSignatureLayer(payload, map[string]string{
+				cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signPayload(signingKey, payload)),
+			}))
+			err := verifyImage(policy)
+			Expect(err).To(MatchError(ErrSignatureVerification))
+			Expect(err.Error()).To(ContainSubstring("signature is for"))
+		})
+
+		It("should reject a signature without the signature annotation", func() {
+			registry.addSignatures(imageDigest, registry.addSignatureLayer(newSignaturePayload(imageDigest), nil))
+			Expect(verifyImage(policy)).To(MatchError(ErrSignatureVerification))
+		})
+	})
+
+	Context("with keyless signatures", func() {
+		var (
+			caKey, rekorKey, signingKey *ecdsa.PrivateKey
+			caCert                      *x509.Certificate
+			notBefore                   time.Time
+			policy                      *SignaturePolicy
+		)
+
+		newLeafCertificate := func(subject, issuer string) []byte {
+			issuerExt, err := asn1.Marshal(issuer)
+			Expect(err).ToNot(HaveOccurred())
+			template := &x509.Certificate{
+				SerialNumber:   big.NewInt(2),
+				NotBefore:      notBefore,
+				NotAfter:       notBefore.Add(10 * time.Minute),
+				KeyUsage:       x509.KeyUsageDigitalSignature,
+				ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+				EmailAddresses: []string{subject},
+				ExtraExtensions: []pkix.Extension{
+					{Id: oidFulcioIssuerV2, Value: issuerExt},
+				},
+			}
+			der, err := x509.CreateCertificate(rand.Reader, template, caCert, signingKey.Public(), caKey)
+			Expect(err).ToNot(HaveOccurred())
+			return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+		}
+
+		newBundle := func(payload, sig, certPEM []byte, integratedTime time.Time) string {
+			hash := sha256.Sum256(payload)
+			entry := &hashedRekord{Kind: rekorHashedRekordKind}
+			entry.Spec.Data.Hash.Algorithm = "sha256"
+			entry.Spec.Data.Hash.Value = hex.EncodeToString(hash[:])
+			entry.Spec.Signature.Content = sig
+			entry.Spec.Signature.PublicKey.Content = certPEM
+			body, err := json.Marshal(entry)
+			Expect(err).ToNot(HaveOccurred())
+			bundle := &rekorBundle{
+				Payload: rekorBundlePayload{
+					Body:           base64.StdEncoding.EncodeToString(body),
+					IntegratedTime: integratedTime.Unix(),
+					LogID:          "c0d23d6ad406973f9559f3ba2d1ca01f84147d8ffc5b8445c224f98b9591801d",
+					LogIndex:       42,
+				},
+			}
+			canonicalPayload, err := json.Marshal(bundle.Payload)
+			Expect(err).ToNot(HaveOccurred())
+			bundle.SignedEntryTimestamp = signPayload(rekorKey, canonicalPayload)
+			data, err := json.Marshal(bundle)
+			Expect(err).ToNot(HaveOccurred())
+			return string(data)
+		}
+
+		addKeylessSignature := func(certPEM []byte, integratedTime time.Time) {
+			payload := newSignaturePayload(imageDigest)
+			sig := signPayload(signingKey, payload)
+			registry.addSignatures(imageDigest, registry.addSignatureLayer(payload, map[string]string{
+				cosignSignatureAnnotation:   base64.StdEncoding.EncodeToString(sig),
+				cosignCertificateAnnotation: string(certPEM),
+				cosignBundleAnnotation:      newBundle(payload, sig, certPEM, integratedTime),
+			}))
+		}
+
+		BeforeEach(func() {
+			caKey, rekorKey, signingKey = newSigningKey(), newSigningKey(), newSigningKey()
+			notBefore = time.Now().Add(-48 * time.Hour)
+			template := &x509.Certificate{
+				SerialNumber:          big.NewInt(1),
+				Subject:               pkix.Name{CommonName: "test fulcio"},
+				NotBefore:             notBefore.Add(-time.Hour),
+				NotAfter:              time.Now().Add(time.Hour),
+				KeyUsage:              x509.KeyUsageCertSign,
+				BasicConstraintsValid: true,
+				IsCA:                  true,
+			}
+			der, err := x509.CreateCertificate(rand.Reader, template, template, caKey.Public(), caKey)
+			Expect(err).ToNot(HaveOccurred())
+			caCert, err = x509.ParseCertificate(der)
+			Expect(err).ToNot(HaveOccurred())
+			writePEM(filepath.Join(policyDir, SignatureFulcioRootsFile), "CERTIFICATE", der)
+			writePublicKey(filepath.Join(policyDir, SignatureRekorKeyFile), rekorKey.Public())
+			policy, err = LoadSignaturePolicy(common.SignaturePolicyKeyless, policyDir, testSignatureIssuer, testSignatureSubject)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should accept a signature made while the certificate was valid", func() {
+			addKeylessSignature(newLeafCertificate(testSignatureSubject, testSignatureIssuer), notBefore.Add(time.Minute))
+			Expect(verifyImage(policy)).To(Succeed())
+		})
+
+		DescribeTable("should reject a signature", func(subject, issuer string, integratedTime time.Duration, errMessage string) {
+			addKeylessSignature(newLeafCertificate(subject, issuer), notBefore.Add(integratedTime))
+			err := verifyImage(policy)
+			Expect(err).To(MatchError(ErrSignatureVerification))
+			Expect(err.Error()).To(ContainSubstring(errMessage))
+		},
+			Entry("of another subject", "other@example.com", testSignatureIssuer, time.Minute, "was not issued to"),
+			Entry("of a subject authenticated by another issuer", testSignatureSubject, "https://other.example.com", time.Minute, "was issued by"),
+			Entry("recorded after the certificate expired", testSignatureSubject, testSignatureIssuer, time.Hour, "not trusted"),
+		)
+
+		It("should reject a signature with a tampered transparency log bundle", func() {
+			certPEM := newLeafCertificate(testSignatureSubject, testSignatureIssuer)
+			payload := newSignaturePayload(imageDigest)
+			sig := signPayload(signingKey, payload)
+			bundle := &rekorBundle{}
+			Expect(json.Unmarshal([]byte(newBundle(payload, sig, certPEM, notBefore.Add(time.Minute))), bundle)).To(Succeed())
+			bundle.Payload.IntegratedTime++
+			data, err := json.Marshal(bundle)
+			Expect(err).ToNot(HaveOccurred())
+			registry.addSignatures(imageDigest, registry.addSignatureLayer(payload, map[string]string{
+				cosignSignatureAnnotation:   base64.StdEncoding.EncodeToString(sig),
+				cosignCertificateAnnotation: string(certPEM),
+				cosignBundleAnnotation:      string(data),
+			}))
+			err = verifyImage(policy)
+			Expect(err).To(MatchError(ErrSignatureVerification))
+			Expect(err.Error()).To(ContainSubstring("transparency log bundle"))
+		})
+
+		It("should reject a signature without a transparency log bundle", func() {
+			payload := newSignaturePayload(imageDigest)
+			registry.addSignatures(imageDigest, registry.addSignatureLayer(payload, map[string]string{
+				cosignSignatureAnnotation:   base64.StdEncoding.EncodeToString(signPayload(signingKey, payload)),
+				cosignCertificateAnnotation: string(newLeafCertificate(testSignatureSubject, testSignatureIssuer)),
+			}))
+			Expect(verifyImage(policy)).To(MatchError(ErrSignatureVerification))
+		})
+	})
+
+	DescribeTable("LoadSignaturePolicy should fail", func(policyType, issuer, subject string) {
+		_, err := LoadSignaturePolicy(policyType, policyDir, issuer, subject)
+		Expect(err).To(HaveOccurred())
+	},
+		Entry("with an unknown policy type", "certificate", "", ""),
+		Entry("without a public key", common.SignaturePolicyPublicKey, "", ""),
+		Entry("without Fulcio certificates", common.SignaturePolicyKeyless, testSignatureIssuer, testSignatureSubject),
+		Entry("without a keyless subject", common.SignaturePolicyKeyless, testSignatureIssuer, ""),
+	)
+
+	It("LoadSignaturePolicy should return nil without a policy type", func() {
+		policy, err := LoadSignaturePolicy("", policyDir, "", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(policy).To(BeNil())
+	})
+})
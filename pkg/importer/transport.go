@@ -31,6 +31,7 @@ import (
 	"github.com/containers/image/v5/oci/archive"
 	"github.com/containers/image/v5/pkg/blobinfocache"
 	"github.com/containers/image/v5/types"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 
 	"k8s.io/klog/v2"
@@ -119,6 +120,46 @@ func hasPrefix(path string, pathPrefix string) bool {
 		strings.HasPrefix(path, "./"+pathPrefix)
 }
 
+// fileSelector selects the files copied from an image by their path in the image
+type fileSelector struct {
+	// pathPrefix selects all files under the prefix
+	pathPrefix string
+	// path selects a single file by its exact path, and takes precedence over pathPrefix
+	path string
+	// skip is the number of matching files skipped before files are selected
+	skip int
+	// selected is the path in the image of the last selected file
+	selected string
+}
+
+func (s *fileSelector) matches(name string) bool {
+	if s.path != "" {
+		return filepath.Clean(strings.TrimPrefix(name, "./")) == filepath.Clean(s.path)
+	}
+	return hasPrefix(name, s.pathPrefix)
+}
+
+// selects reports whether the file should be copied, counting the skipped matching files
+func (s *fileSelector) selects(name string) bool {
+	if !s.matches(name) {
+		return false
+	}
+	if s.skip > 0 {
+		klog.Infof("Skipping file '%v', %d more to skip", name, s.skip-1)
+		s.skip--
+		return false
+	}
+	s.selected = name
+	return true
+}
+
+func (s *fileSelector) String() string {
+	if s.path != "" {
+		return s.path
+	}
+	return s.pathPrefix
+}
+
 func isWhiteout(path string) bool {
 	return strings.HasPrefix(filepath.Base(path), whFilePrefix)
 }
@@ -131,7 +172,7 @@ func processLayer(ctx context.Context,
 	src types.ImageSource,
 	layer types.BlobInfo,
 	destDir string,
-	pathPrefix string,
+	selector *fileSelector,
 	cache types.BlobInfoCache,
 	stopAtFirst,
 	preallocation bool) (bool, error) {
@@ -160,7 +201,7 @@ func processLayer(ctx context.Context,
 			return false, fmt.Errorf("%w: %v", errReadingLayer, err)
 		}
 
-		if hasPrefix(hdr.Name, pathPrefix) && !isWhiteout(hdr.Name) && !isDir(hdr) {
+		if !isWhiteout(hdr.Name) && !isDir(hdr) && selector.selects(hdr.Name) {
 			klog.Infof("File '%v' found in the layer", hdr.Name)
 			destFile, err := safeJoinPaths(destDir, hdr.Name)
 			if err != nil {
@@ -201,8 +242,108 @@ func safeJoinPaths(dir, path string) (v string, err error) {
 	return "", fmt.Errorf("%s: %s", "content filepath is tainted", path)
 }
 
-func copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, imageArchitecture, certDir string, insecureRegistry, stopAtFirst, preallocation bool) (*types.ImageInspectInfo, error) {
-	klog.Infof("Downloading image from '%v', copying file from '%v' to '%v'", url, pathPrefix, destDir)
+// getInstanceManifest returns the manifest of the image, or of the image matching the platform of the system context if
+// the source is an image index.
+func getInstanceManifest(ctx context.Context, sys *types.SystemContext, src types.ImageSource) ([]byte, string, error) {
+	manifestBlob, manifestType, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if !manifest.MIMETypeIsMultiImage(manifestType) {
+		return manifestBlob, manifestType, nil
+	}
+	list, err := manifest.ListFromBlob(manifestBlob, manifestType)
+	if err != nil {
+		return nil, "", err
+	}
+	instance, err := list.ChooseInstance(sys)
+	if err != nil {
+		return nil, "", err
+	}
+	return src.GetManifest(ctx, &instance)
+}
+
+// getArtifactManifest returns the manifest if it describes an OCI artifact, like a disk image pushed with ORAS,
+// rather than a container image. Returns nil for container images.
+func getArtifactManifest(manifestBlob []byte, mimeType string) *manifest.OCI1 {
+	if manifest.NormalizedMIMEType(mimeType) != imgspecv1.MediaTypeImageManifest {
+		return nil
+	}
+	m, err := manifest.OCI1FromManifest(manifestBlob)
+	if err != nil {
+		return nil
+	}
+	if m.ArtifactType == "" && m.Config.MediaType == imgspecv1.MediaTypeImageConfig {
+		return nil
+	}
+	return m
+}
+
+// artifactLayerFileName returns the name of the file an artifact layer is stored as. ORAS records the name of the
+// pushed file in the title annotation, layers without one are named after their digest.
+func artifactLayerFileName(layer types.BlobInfo) string {
+	name := filepath.Base(layer.Annotations[imgspecv1.AnnotationTitle])
+	if name == "." || name == ".." || name == string(os.PathSeparator) {
+		return layer.Digest.Encoded()
+	}
+	return name
+}
+
+// processArtifactLayer copies an artifact layer to destDir. Unlike container image layers, artifact layers are the
+// disk image itself instead of a tar archive. The layer is stored as if it was a file in the disk directory of a
+// container disk image, so the selector applies to both.
+func processArtifactLayer(ctx context.Context,
+	src types.ImageSource,
+	layer types.BlobInfo,
+	destDir string,
+	selector *fileSelector,
+	cache types.BlobInfoCache,
+	preallocation bool) (bool, error) {
+	if layer.MediaType == imgspecv1.MediaTypeEmptyJSON {
+		return false, nil
+	}
+	name := filepath.Join(containerDiskImageDir, artifactLayerFileName(layer))
+	if !selector.selects(name) {
+		return false, nil
+	}
+
+	reader, _, err := src.GetBlob(ctx, layer, cache)
+	if err != nil {
+		klog.Errorf("%v: %v", errReadingLayer, err)
+		return false, fmt.Errorf("%w: %v", errReadingLayer, err)
+	}
+	fr, err := NewFormatReaders(reader, 0, nil)
+	if err != nil {
+		klog.Errorf("%v: %v", errReadingLayer, err)
+		return false, fmt.Errorf("%w: %v", errReadingLayer, err)
+	}
+	defer fr.Close()
+
+	klog.Infof("Artifact layer %s found, copying to '%v'", layer.Digest, name)
+	destFile, err := safeJoinPaths(destDir, name)
+	if err != nil {
+		klog.Errorf("Error sanitizing artifact path: %v", err)
+		return false, errors.Wrap(err, "Error sanitizing artifact path")
+	}
+	if err = os.MkdirAll(filepath.Dir(destFile), os.ModePerm); err != nil {
+		klog.Errorf("Error creating output file's directory: %v", err)
+		return false, errors.Wrap(err, "Error creating output file's directory")
+	}
+	if _, _, err := StreamDataToFile(fr.TopReader(), destFile, preallocation); err != nil {
+		klog.Errorf("Error copying file: %v", err)
+		return false, errors.Wrap(err, "Error copying file")
+	}
+	if fr.ArchiveZip {
+		if err := extractZipImage(destFile, preallocation); err != nil {
+			klog.Errorf("Error extracting file: %v", err)
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func copyRegistryImage(url, destDir string, selector *fileSelector, accessKey, secKey, imageArchitecture, certDir string, insecureRegistry, stopAtFirst, preallocation bool, signaturePolicy *SignaturePolicy) (*types.ImageInspectInfo, error) {
+	klog.Infof("Downloading image from '%v', copying file from '%v' to '%v'", url, selector, destDir)
 
 	ctx, cancel := commandTimeoutContext()
 	defer cancel()
@@ -214,6 +355,15 @@ func copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, imageArchite
 	}
 	defer closeImage(src)
 
+	// Verify the signature before anything is extracted from the image
+	if signaturePolicy != nil {
+		klog.Infof("Verifying image signature, policy: %s", signaturePolicy)
+		if err := signaturePolicy.verifyImage(ctx, srcCtx, src); err != nil {
+			klog.Errorf("Error verifying image signature: %v", err)
+			return nil, err
+		}
+	}
+
 	imgCloser, err := image.FromSource(ctx, srcCtx, src)
 	if err != nil {
 		klog.Errorf("Error retrieving image: %v", err)
@@ -221,6 +371,18 @@ func copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, imageArchite
 	}
 	defer imgCloser.Close()
 
+	cache := blobinfocache.DefaultCache(srcCtx)
+
+	// The platform of an artifact can only be selected from an image index, there is no config to validate
+	manifestBlob, manifestType, err := getInstanceManifest(ctx, srcCtx, src)
+	if err != nil {
+		klog.Errorf("Error retrieving image manifest: %v", err)
+		return nil, errors.Wrap(err, "Error retrieving image manifest")
+	}
+	if artifact := getArtifactManifest(manifestBlob, manifestType); artifact != nil {
+		return copyArtifact(ctx, src, artifact, destDir, selector, cache, stopAtFirst, preallocation)
+	}
+
 	// in the event that target is not a manifest list / image index
 	if srcCtx.ArchitectureChoice != "" {
 		if err := validateImagePlatformMatch(srcCtx, imgCloser); err != nil {
@@ -233,14 +395,13 @@ func copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, imageArchite
 		return nil, err
 	}
 
-	cache := blobinfocache.DefaultCache(srcCtx)
 	found := false
 	layers := imgCloser.LayerInfos()
 
 	for _, layer := range layers {
 		klog.Infof("Processing layer %+v", layer)
 
-		found, err = processLayer(ctx, src, layer, destDir, pathPrefix, cache, stopAtFirst, preallocation)
+		found, err = processLayer(ctx, src, layer, destDir, selector, cache, stopAtFirst, preallocation)
 		if found {
 			break
 		}
@@ -267,6 +428,43 @@ func copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, imageArchite
 	return info, nil
 }
 
+func copyArtifact(ctx context.Context,
+	src types.ImageSource,
+	artifact *manifest.OCI1,
+	destDir string,
+	selector *fileSelector,
+	cache types.BlobInfoCache,
+	stopAtFirst,
+	preallocation bool) (*types.ImageInspectInfo, error) {
+	klog.Infof("Image is an OCI artifact, artifact type: %q, config media type: %q", artifact.ArtifactType, artifact.Config.MediaType)
+
+	found := false
+	for _, layer := range artifact.LayerInfos() {
+		klog.Infof("Processing artifact layer %+v", layer.BlobInfo)
+
+		copied, err := processArtifactLayer(ctx, src, layer.BlobInfo, destDir, selector, cache, preallocation)
+		if err != nil {
+			if !errors.Is(err, errReadingLayer) {
+				return nil, err
+			}
+			// Skipping layer and trying the next one.
+			// Error already logged in processArtifactLayer
+			continue
+		}
+		found = found || copied
+		if found && stopAtFirst {
+			break
+		}
+	}
+
+	if !found {
+		klog.Errorf("Failed to find VM disk image file in the artifact")
+		return nil, errors.New("Failed to find VM disk image file in the artifact")
+	}
+
+	return &types.ImageInspectInfo{Labels: artifact.Annotations}, nil
+}
+
 const (
 	bootcImageLabel   = "containers.bootc"
 	ostreeBootLabel   = "ostree.bootable"
@@ -329,7 +527,8 @@ func GetImageDigest(url, accessKey, secKey, certDir string, insecureRegistry boo
 	return digest.String(), nil
 }
 
-// CopyRegistryImage download image from registry with docker image API. It will extract first file under the pathPrefix
+// CopyRegistryImage download image from registry with docker image API. It will extract first file under the pathPrefix.
+// If the image is an OCI artifact, the first layer is copied as the disk image file.
 // url: source registry url.
 // destDir: the scratch space destination.
 // pathPrefix: path to extract files from.
@@ -338,8 +537,9 @@ func GetImageDigest(url, accessKey, secKey, certDir string, insecureRegistry boo
 // imageArchitecture: image index filter for CPU architecture.
 // certDir: directory public CA keys are stored for registry identity verification
 // insecureRegistry: boolean if true will allow insecure registries.
-func CopyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, imageArchitecture, certDir string, insecureRegistry, preallocation bool) (*types.ImageInspectInfo, error) {
-	return copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, imageArchitecture, certDir, insecureRegistry, true, preallocation)
+// signaturePolicy: if not nil, the cosign signature of the image is verified before anything is extracted.
+func CopyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, imageArchitecture, certDir string, insecureRegistry, preallocation bool, signaturePolicy *SignaturePolicy) (*types.ImageInspectInfo, error) {
+	return copyRegistryImage(url, destDir, &fileSelector{pathPrefix: pathPrefix}, accessKey, secKey, imageArchitecture, certDir, insecureRegistry, true, preallocation, signaturePolicy)
 }
 
 // CopyRegistryImageAll download image from registry with docker image API. It will extract all files under the pathPrefix
@@ -351,5 +551,5 @@ func CopyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, imageArchite
 // certDir: directory public CA keys are stored for registry identity verification
 // insecureRegistry: boolean if true will allow insecure registries.
 func CopyRegistryImageAll(url, destDir, pathPrefix, accessKey, secKey, certDir string, insecureRegistry, preallocation bool) (*types.ImageInspectInfo, error) {
-	return copyRegistryImage(url, destDir, pathPrefix, accessKey, secKey, "", certDir, insecureRegistry, false, preallocation)
+	return copyRegistryImage(url, destDir, &fileSelector{pathPrefix: pathPrefix}, accessKey, secKey, "", certDir, insecureRegistry, false, preallocation, nil)
 }
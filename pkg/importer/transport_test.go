@@ -16,13 +16,219 @@ limitations under the License.
 package importer
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
+type fakeManifest struct {
+	mediaType string
+	data      []byte
+}
+
+// fakeRegistry serves manifests and blobs like a minimal OCI registry, regardless of the repository name.
+// Images can be pushed to it with monolithic blob uploads. The handler runs outside of the spec goroutine, the errors
+// of the pushes are recorded for the spec to check them.
+type fakeRegistry struct {
+	manifests map[string]fakeManifest
+	blobs     map[digest.Digest][]byte
+	uploads   map[string][]byte
+
+	errLock  sync.Mutex
+	pushErrs []error
+}
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{manifests: map[string]fakeManifest{}, blobs: map[digest.Digest][]byte{}, uploads: map[string][]byte{}}
+}
+
+func (r *fakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/v2/" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.servePush(w, req) {
+		return
+	}
+	if _, ref, ok := strings.Cut(req.URL.Path, "/manifests/"); ok {
+		m, ok := r.manifests[ref]
+		if !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[{"code":"MANIFEST_UNKNOWN","message":"manifest unknown"}]}`)
+			return
+		}
+		w.Header().Set("Content-Type", m.mediaType)
+		w.Header().Set("Docker-Content-Digest", digest.FromBytes(m.data).String())
+		_, _ = w.Write(m.data)
+		return
+	}
+	if _, d, ok := strings.Cut(req.URL.Path, "/blobs/"); ok {
+		if blob, ok := r.blobs[digest.Digest(d)]; ok {
+			_, _ = w.Write(blob)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+// pushErrors returns the errors of the requests pushing an image
+func (r *fakeRegistry) pushErrors() []error {
+	r.errLock.Lock()
+	defer r.errLock.Unlock()
+	return append([]error(nil), r.pushErrs...)
+}
+
+func (r *fakeRegistry) failPush(w http.ResponseWriter, err error) {
+	r.errLock.Lock()
+	r.pushErrs = append(r.pushErrs, err)
+	r.errLock.Unlock()
+	w.WriteHeader(http.StatusBadRequest)
+}
+
+// servePush handles the requests pushing an image, and returns false for other requests
+func (r *fakeRegistry) servePush(w http.ResponseWriter, req *http.Request) bool {
+	const uploadsPath = "/v2/uploads/"
+	switch {
+	case req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/blobs/uploads/"):
+		id := fmt.Sprintf("%d", len(r.uploads))
+		r.uploads[id] = nil
+		w.Header().Set("Location", uploadsPath+id)
+		w.WriteHeader(http.StatusAccepted)
+	case (req.Method == http.MethodPatch || req.Method == http.MethodPut) && strings.HasPrefix(req.URL.Path, uploadsPath):
+		id := strings.TrimPrefix(req.URL.Path, uploadsPath)
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			r.failPush(w, err)
+			return true
+		}
+		r.uploads[id] = append(r.uploads[id], data...)
+		if req.Method == http.MethodPatch {
+			w.Header().Set("Location", uploadsPath+id)
+			w.WriteHeader(http.StatusAccepted)
+			return true
+		}
+		d := digest.Digest(req.URL.Query().Get("digest"))
+		if uploaded := digest.FromBytes(r.uploads[id]); d != uploaded {
+			r.failPush(w, fmt.Errorf("digest %s of upload %s does not match its data %s", d, id, uploaded))
+			return true
+		}
+		r.blobs[d] = r.uploads[id]
+		w.WriteHeader(http.StatusCreated)
+	case req.Method == http.MethodHead && strings.Contains(req.URL.Path, "/blobs/"):
+		_, d, _ := strings.Cut(req.URL.Path, "/blobs/")
+		blob, ok := r.blobs[digest.Digest(d)]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return true
+		}
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(blob)))
+		w.WriteHeader(http.StatusOK)
+	case req.Method == http.MethodPut && strings.Contains(req.URL.Path, "/manifests/"):
+		_, ref, _ := strings.Cut(req.URL.Path, "/manifests/")
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			r.failPush(w, err)
+			return true
+		}
+		m := fakeManifest{mediaType: req.Header.Get("Content-Type"), data: data}
+		r.manifests[ref] = m
+		r.manifests[digest.FromBytes(data).String()] = m
+		w.Header().Set("Docker-Content-Digest", digest.FromBytes(data).String())
+		w.WriteHeader(http.StatusCreated)
+	default:
+		return false
+	}
+	return true
+}
+
+func (r *fakeRegistry) addBlob(data []byte) imgspecv1.Descriptor {
+	d := digest.FromBytes(data)
+	r.blobs[d] = data
+	return imgspecv1.Descriptor{Digest: d, Size: int64(len(data))}
+}
+
+// addManifest stores the manifest under ref, and under its digest
+func (r *fakeRegistry) addManifest(ref, mediaType string, manifest interface{}) digest.Digest {
+	data, err := json.Marshal(manifest)
+	Expect(err).ToNot(HaveOccurred())
+	d := digest.FromBytes(data)
+	r.manifests[ref] = fakeManifest{mediaType: mediaType, data: data}
+	r.manifests[d.String()] = fakeManifest{mediaType: mediaType, data: data}
+	return d
+}
+
+func (r *fakeRegistry) addImageManifest(ref string, manifest imgspecv1.Manifest) digest.Digest {
+	manifest.SchemaVersion = 2
+	manifest.MediaType = imgspecv1.MediaTypeImageManifest
+	return r.addManifest(ref, imgspecv1.MediaTypeImageManifest, manifest)
+}
+
+// addArtifact stores an artifact like ORAS pushes it, with an empty config and a layer per file
+func (r *fakeRegistry) addArtifact(ref, artifactType string, layers ...imgspecv1.Descriptor) digest.Digest {
+	config := r.addBlob([]byte("{}"))
+	config.MediaType = imgspecv1.MediaTypeEmptyJSON
+	return r.addImageManifest(ref, imgspecv1.Manifest{ArtifactType: artifactType, Config: config, Layers: layers})
+}
+
+func (r *fakeRegistry) addArtifactLayer(title string, data []byte) imgspecv1.Descriptor {
+	layer := r.addBlob(data)
+	layer.MediaType = "application/vnd.example.disk.layer.v1"
+	if title != "" {
+		layer.Annotations = map[string]string{imgspecv1.AnnotationTitle: title}
+	}
+	return layer
+}
+
+// addContainerDiskLayer stores a container image layer with a disk image file for each of the names in the disk
+// directory, the content of each file is derived from its name
+func (r *fakeRegistry) addContainerDiskLayer(names ...string) imgspecv1.Descriptor {
+	var layer bytes.Buffer
+	tw := tar.NewWriter(&layer)
+	for _, name := range names {
+		data := artifactDiskImage(name)
+		Expect(tw.WriteHeader(&tar.Header{Name: "disk/" + name, Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg})).To(Succeed())
+		_, err := tw.Write(data)
+		Expect(err).ToNot(HaveOccurred())
+	}
+	Expect(tw.Close()).To(Succeed())
+	desc := r.addBlob(layer.Bytes())
+	desc.MediaType = imgspecv1.MediaTypeImageLayer
+	return desc
+}
+
+// addContainerImage stores a linux/amd64 container image with the layers
+func (r *fakeRegistry) addContainerImage(ref string, layers ...imgspecv1.Descriptor) digest.Digest {
+	diffIDs := []digest.Digest{}
+	for _, layer := range layers {
+		diffIDs = append(diffIDs, layer.Digest)
+	}
+	configData, err := json.Marshal(imgspecv1.Image{
+		Platform: imgspecv1.Platform{Architecture: "amd64", OS: "linux"},
+		RootFS:   imgspecv1.RootFS{Type: "layers", DiffIDs: diffIDs},
+	})
+	Expect(err).ToNot(HaveOccurred())
+	config := r.addBlob(configData)
+	config.MediaType = imgspecv1.MediaTypeImageConfig
+	return r.addImageManifest(ref, imgspecv1.Manifest{Config: config, Layers: layers})
+}
+
 var _ = Describe("Registry Importer", func() {
 	source := "oci-archive:" + imageFile
 	malformedSource := "oci-archive:" + filepath.Join(imageDir, "malformed-registry-image.tar")
@@ -43,7 +249,7 @@ var _ = Describe("Registry Importer", func() {
 	})
 
 	DescribeTable("Should extract a single file", func(source string) {
-		info, err := CopyRegistryImage(source, tmpDir, "disk/cirros-0.3.4-x86_64-disk.img", "", "", "", "", false, false)
+		info, err := CopyRegistryImage(source, tmpDir, "disk/cirros-0.3.4-x86_64-disk.img", "", "", "", "", false, false, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(info).ToNot(BeNil())
 
@@ -65,7 +271,7 @@ var _ = Describe("Registry Importer", func() {
 		Expect(file).To(BeARegularFile())
 	})
 	It("Should return an error if a single file is not found", func() {
-		info, err := CopyRegistryImage(source, tmpDir, "disk/invalid.img", "", "", "", "", false, false)
+		info, err := CopyRegistryImage(source, tmpDir, "disk/invalid.img", "", "", "", "", false, false, nil)
 		Expect(err).To(HaveOccurred())
 		Expect(info).To(BeNil())
 
@@ -79,7 +285,7 @@ var _ = Describe("Registry Importer", func() {
 		Expect(info).To(BeNil())
 	})
 	DescribeTable("Should correctly assert image architecture", func(source string, architecture string, wantErr bool) {
-		info, err := CopyRegistryImage(source, tmpDir, "disk/", "", "", architecture, "", false, false)
+		info, err := CopyRegistryImage(source, tmpDir, "disk/", "", "", architecture, "", false, false, nil)
 		if wantErr {
 			Expect(err).To(HaveOccurred())
 			Expect(info).To(BeNil())
@@ -95,15 +301,137 @@ var _ = Describe("Registry Importer", func() {
 	)
 
 	It("Should detect a bootc image and return ErrBootcImageDetected", func() {
-		info, err := CopyRegistryImage(bootcSource, tmpDir, "disk/", "", "", "", "", false, false)
+		info, err := CopyRegistryImage(bootcSource, tmpDir, "disk/", "", "", "", "", false, false, nil)
 		Expect(err).To(HaveOccurred())
 		Expect(err).To(MatchError(ErrBootcImageDetected))
 		Expect(info).To(BeNil())
 	})
 
 	It("Should not detect a non-bootc image as bootc", func() {
-		info, err := CopyRegistryImage(source, tmpDir, "disk/cirros-0.3.4-x86_64-disk.img", "", "", "", "", false, false)
+		info, err := CopyRegistryImage(source, tmpDir, "disk/cirros-0.3.4-x86_64-disk.img", "", "", "", "", false, false, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(info).ToNot(BeNil())
 	})
 })
+
+// artifactDiskImage returns disk image data large enough for the format readers to detect its format
+func artifactDiskImage(content string) []byte {
+	return bytes.Repeat([]byte(content), 4096)
+}
+
+var _ = Describe("Registry Importer with OCI artifacts", func() {
+	const artifactType = "application/vnd.example.disk.v1"
+	var (
+		registry *fakeRegistry
+		server   *httptest.Server
+		tmpDir   string
+		source   string
+	)
+
+	BeforeEach(func() {
+		var err error
+		registry = newFakeRegistry()
+		server = httptest.NewTLSServer(registry)
+		source = "docker://" + strings.TrimPrefix(server.URL, "https://") + "/test/artifact:latest"
+		tmpDir, err = os.MkdirTemp("", "scratch")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.RemoveAll(tmpDir)
+	})
+
+	copyArtifact := func(architecture string) ([]byte, error) {
+		if _, err := CopyRegistryImage(source, tmpDir, containerDiskImageDir, "", "", architecture, "", true, false, nil); err != nil {
+			return nil, err
+		}
+		imageFile, err := getImageFileName(filepath.Join(tmpDir, containerDiskImageDir))
+		Expect(err).ToNot(HaveOccurred())
+		return os.ReadFile(filepath.Join(tmpDir, containerDiskImageDir, imageFile))
+	}
+
+	It("Should copy the disk image of an artifact", func() {
+		registry.addArtifact("latest", artifactType, registry.addArtifactLayer("fedora.qcow2", artifactDiskImage("disk image")))
+		Expect(copyArtifact("")).To(Equal(artifactDiskImage("disk image")))
+		Expect(filepath.Join(tmpDir, containerDiskImageDir, "fedora.qcow2")).To(BeARegularFile())
+	})
+
+	It("Should copy the disk image of an artifact without artifact type", func() {
+		config := registry.addBlob([]byte("{}"))
+		config.MediaType = "application/vnd.unknown.config.v1+json"
+		registry.addImageManifest("latest", imgspecv1.Manifest{
+			Config: config,
+			Layers: []imgspecv1.Descriptor{registry.addArtifactLayer("fedora.qcow2", artifactDiskImage("disk image"))},
+		})
+		Expect(copyArtifact("")).To(Equal(artifactDiskImage("disk image")))
+	})
+
+	It("Should decompress a compressed disk image", func() {
+		// Random data, so the compressed layer is large enough for the format readers to detect the format
+		data := make([]byte, 64*1024)
+		_, err := rand.Read(data)
+		Expect(err).ToNot(HaveOccurred())
+		var compressed bytes.Buffer
+		gzw := gzip.NewWriter(&compressed)
+		_, err = gzw.Write(data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(gzw.Close()).To(Succeed())
+		registry.addArtifact("latest", artifactType, registry.addArtifactLayer("fedora.qcow2.gz", compressed.Bytes()))
+		Expect(copyArtifact("")).To(Equal(data))
+	})
+
+	It("Should name a layer without title after its digest", func() {
+		layer := registry.addArtifactLayer("", artifactDiskImage("disk image"))
+		registry.addArtifact("latest", artifactType, layer)
+		Expect(copyArtifact("")).To(Equal(artifactDiskImage("disk image")))
+		Expect(filepath.Join(tmpDir, containerDiskImageDir, layer.Digest.Encoded())).To(BeARegularFile())
+	})
+
+	It("Should not write outside of the destination directory", func() {
+		registry.addArtifact("latest", artifactType, registry.addArtifactLayer("../../fedora.qcow2", artifactDiskImage("disk image")))
+		Expect(copyArtifact("")).To(Equal(artifactDiskImage("disk image")))
+		Expect(filepath.Join(tmpDir, containerDiskImageDir, "fedora.qcow2")).To(BeARegularFile())
+	})
+
+	It("Should skip empty layers", func() {
+		empty := registry.addBlob([]byte("{}"))
+		empty.MediaType = imgspecv1.MediaTypeEmptyJSON
+		registry.addArtifact("latest", artifactType, empty, registry.addArtifactLayer("fedora.qcow2", artifactDiskImage("disk image")))
+		Expect(copyArtifact("")).To(Equal(artifactDiskImage("disk image")))
+	})
+
+	It("Should return an error if the artifact has no layers", func() {
+		registry.addArtifact("latest", artifactType)
+		_, err := copyArtifact("")
+		Expect(err).To(MatchError(ContainSubstring("Failed to find VM disk image file in the artifact")))
+	})
+
+	DescribeTable("Should select the artifact by platform from an image index", func(architecture string, expected []byte, wantErr bool) {
+		amd64 := registry.addArtifact("amd64", artifactType, registry.addArtifactLayer("disk.img", artifactDiskImage("amd64 disk image")))
+		arm64 := registry.addArtifact("arm64", artifactType, registry.addArtifactLayer("disk.img", artifactDiskImage("arm64 disk image")))
+		index := imgspecv1.Index{
+			MediaType: imgspecv1.MediaTypeImageIndex,
+			Manifests: []imgspecv1.Descriptor{
+				{MediaType: imgspecv1.MediaTypeImageManifest, Digest: amd64, Size: int64(len(registry.manifests[amd64.String()].data)),
+					ArtifactType: artifactType, Platform: &imgspecv1.Platform{Architecture: "amd64", OS: "linux"}},
+				{MediaType: imgspecv1.MediaTypeImageManifest, Digest: arm64, Size: int64(len(registry.manifests[arm64.String()].data)),
+					ArtifactType: artifactType, Platform: &imgspecv1.Platform{Architecture: "arm64", OS: "linux"}},
+			},
+		}
+		index.SchemaVersion = 2
+		registry.addManifest("latest", imgspecv1.MediaTypeImageIndex, index)
+
+		data, err := copyArtifact(architecture)
+		if wantErr {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data).To(Equal(expected))
+	},
+		Entry("when architecture is amd64", "amd64", artifactDiskImage("amd64 disk image"), false),
+		Entry("when architecture is arm64", "arm64", artifactDiskImage("arm64 disk image"), false),
+		Entry("when architecture is not in the index", "s390x", nil, true),
+	)
+})
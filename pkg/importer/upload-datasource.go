@@ -1,8 +1,10 @@
 package importer
 
 import (
+	"fmt"
 	"io"
 	"net/url"
+	"os"
 	"path/filepath"
 
 	"github.com/pkg/errors"
@@ -11,13 +13,13 @@ import (
 
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
-	"kubevirt.io/containerized-data-importer/pkg/util"
 )
 
 // UploadDataSource contains all the information need to upload data into a data volume.
 // Sequence of phases:
 // 1a. ProcessingPhaseInfo -> ProcessingPhaseTransferScratch (In Info phase the format readers are configured) In case the readers don't contain a raw file.
-// 1b. ProcessingPhaseInfo -> ProcessingPhaseTransferDataFile, in the case the readers contain a raw file.
+// 1b. ProcessingPhaseInfo -> ProcessingPhaseTransferDataFile, in the case the readers contain a raw file, or a qcow2
+// file which is converted while it is transferred.
 // 2a. ProcessingPhaseTransferScratch -> ProcessingPhaseConvert
 // 2b. ProcessingPhaseTransferDataFile -> ProcessingPhaseResize
 type UploadDataSource struct {
@@ -27,23 +29,64 @@ type UploadDataSource struct {
 	readers *FormatReaders
 	// url to a file in scratch space.
 	url *url.URL
+	// file is the path of an image which was uploaded to disk already, the stream reads it
+	file string
 	// contentType expected from the upload content
 	contentType cdiv1.DataVolumeContentType
+	// checksumValidator validates the checksum of the uploaded data
+	checksumValidator *ChecksumValidator
+	// archiveOptions configures the extraction of archive uploads
+	archiveOptions *ArchiveOptions
+	// extractedFiles are the files extracted from an archive upload
+	extractedFiles []ExtractedFile
 }
 
-// NewUploadDataSource creates a new instance of an UploadDataSource
-func NewUploadDataSource(stream io.ReadCloser, contentType cdiv1.DataVolumeContentType) *UploadDataSource {
+// NewUploadDataSource creates a new instance of an UploadDataSource, the checksum of the uploaded data is validated if
+// checksumValidator is not nil
+func NewUploadDataSource(stream io.ReadCloser, contentType cdiv1.DataVolumeContentType, checksumValidator *ChecksumValidator) *UploadDataSource {
 	return &UploadDataSource{
-		stream:      stream,
-		contentType: contentType,
+		stream:            stream,
+		contentType:       contentType,
+		checksumValidator: checksumValidator,
 	}
 }
 
+// NewUploadFileDataSource creates a new instance of an UploadDataSource reading an image which was uploaded to file
+// already. An image which is not compressed is converted from the file instead of being copied to scratch space first.
+func NewUploadFileDataSource(file string, checksumValidator *ChecksumValidator) (*UploadDataSource, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open %s", file)
+	}
+	return &UploadDataSource{
+		stream:            f,
+		file:              file,
+		contentType:       cdiv1.DataVolumeKubeVirt,
+		checksumValidator: checksumValidator,
+	}, nil
+}
+
+// NewArchiveUploadDataSource creates a new instance of an UploadDataSource extracting an uploaded tar archive, which
+// may be compressed, the checksum of the uploaded data is validated if checksumValidator is not nil
+func NewArchiveUploadDataSource(stream io.ReadCloser, options *ArchiveOptions, checksumValidator *ChecksumValidator) *UploadDataSource {
+	return &UploadDataSource{
+		stream:            stream,
+		contentType:       cdiv1.DataVolumeArchive,
+		checksumValidator: checksumValidator,
+		archiveOptions:    options,
+	}
+}
+
+// ExtractedFiles returns the files extracted from an archive upload once it completed
+func (ud *UploadDataSource) ExtractedFiles() []ExtractedFile {
+	return ud.extractedFiles
+}
+
 // Info is called to get initial information about the data.
 func (ud *UploadDataSource) Info() (ProcessingPhase, error) {
 	var err error
 	// Hardcoded to only accept kubevirt content type.
-	ud.readers, err = NewFormatReaders(ud.stream, uint64(0), nil)
+	ud.readers, err = NewFormatReaders(ud.stream, uint64(0), ud.checksumValidator)
 	if err != nil {
 		klog.Errorf("Error creating readers: %v", err)
 		return ProcessingPhaseError, err
@@ -60,6 +103,9 @@ func (ud *UploadDataSource) Info() (ProcessingPhase, error) {
 
 // Transfer is called to transfer the data from the source to the passed in path.
 func (ud *UploadDataSource) Transfer(path string, preallocation bool) (ProcessingPhase, error) {
+	if ud.contentType == cdiv1.DataVolumeKubeVirt && ud.convertsInPlace() {
+		return ud.transferInPlace()
+	}
 	if ud.contentType == cdiv1.DataVolumeKubeVirt {
 		file := filepath.Join(path, tempFile)
 		if err := CleanAll(file); err != nil {
@@ -77,19 +123,54 @@ func (ud *UploadDataSource) Transfer(path string, preallocation bool) (Processin
 		if err != nil {
 			return ProcessingPhaseError, err
 		}
+		if err := ud.validateChecksum(file); err != nil {
+			return ProcessingPhaseError, err
+		}
+		if ud.readers.ArchiveZip {
+			if err := extractZipImage(file, preallocation); err != nil {
+				return ProcessingPhaseError, err
+			}
+		}
 		// If we successfully wrote to the file, then the parse will succeed.
 		ud.url, _ = url.Parse(file)
 		return ProcessingPhaseConvert, nil
 	} else if ud.contentType == cdiv1.DataVolumeArchive {
-		if err := util.UnArchiveTar(ud.readers.TopReader(), path); err != nil {
+		files, err := ExtractTar(ud.readers.TopReader(), path, ud.archiveOptions)
+		if err != nil {
 			return ProcessingPhaseError, errors.Wrap(err, "unable to untar files from endpoint")
 		}
+		if err := ud.validateChecksum(extractedPaths(path, files)...); err != nil {
+			return ProcessingPhaseError, err
+		}
+		ud.extractedFiles = files
 		ud.url = nil
 		return ProcessingPhaseComplete, nil
 	}
 	return ProcessingPhaseError, errors.Errorf("Unknown content type: %s", ud.contentType)
 }
 
+// convertsInPlace returns true if the image was uploaded to a file which can be converted as is, without decompressing
+// it to scratch space.
+func (ud *UploadDataSource) convertsInPlace() bool {
+	return ud.file != "" && !ud.readers.Archived
+}
+
+// transferInPlace validates the checksum of the uploaded file, which is then converted from where it is. The file is
+// kept if the checksum does not match, it is the data of the upload and not a copy.
+func (ud *UploadDataSource) transferInPlace() (ProcessingPhase, error) {
+	if ud.checksumValidator != nil {
+		if _, err := io.Copy(io.Discard, ud.readers.TopReader()); err != nil {
+			return ProcessingPhaseError, errors.Wrapf(err, "unable to read %s", ud.file)
+		}
+		if err := ud.readers.ValidateChecksum(); err != nil {
+			return ProcessingPhaseError, fmt.Errorf("checksum validation failed: %w", err)
+		}
+	}
+	// The path of an opened file parses
+	ud.url, _ = url.Parse(ud.file)
+	return ProcessingPhaseConvert, nil
+}
+
 // TransferFile is called to transfer the data from the source to the passed in file.
 func (ud *UploadDataSource) TransferFile(fileName string, preallocation bool) (ProcessingPhase, error) {
 	if err := CleanAll(fileName); err != nil {
@@ -99,11 +180,107 @@ func (ud *UploadDataSource) TransferFile(fileName string, preallocation bool) (P
 	if err != nil {
 		return ProcessingPhaseError, err
 	}
+	if err := ud.validateChecksum(fileName); err != nil {
+		return ProcessingPhaseError, err
+	}
 	// If we successfully wrote to the file, then the parse will succeed.
 	ud.url, _ = url.Parse(fileName)
 	return ProcessingPhaseResize, nil
 }
 
+// TransferConvertFile is called to convert the qcow2 image from the source to raw while it is transferred to the passed
+// in file.
+func (ud *UploadDataSource) TransferConvertFile(fileName, scratchPath string, preallocation bool) (ProcessingPhase, error) {
+	if err := CleanAll(fileName); err != nil {
+		return ProcessingPhaseError, err
+	}
+	if err := StreamQcow2ToFile(ud.readers.TopReader(), fileName, scratchPath, preallocation); err != nil {
+		return ProcessingPhaseError, err
+	}
+	if err := ud.validateChecksum(fileName); err != nil {
+		return ProcessingPhaseError, err
+	}
+	// If we successfully wrote to the file, then the parse will succeed.
+	ud.url, _ = url.Parse(fileName)
+	return ProcessingPhaseResize, nil
+}
+
+// validateChecksum validates the checksum of the uploaded data once all of it was read, the files written from the data
+// are removed, or zeroed on block devices, if the checksum does not match so that the target is not populated
+func (ud *UploadDataSource) validateChecksum(paths ...string) error {
+	if err := ud.readers.ValidateChecksum(); err != nil {
+		if cleanErr := discardUploadedData(paths...); cleanErr != nil {
+			klog.Errorf("Unable to remove the data of the upload: %v", cleanErr)
+			return fmt.Errorf("checksum validation failed: %w, the target may contain the invalid data: %v", err, cleanErr)
+		}
+		return fmt.Errorf("checksum validation failed: %w", err)
+	}
+	return nil
+}
+
+// unit test support to discard the data of regular files as if they were block devices
+var isDeviceFunc = IsDevice
+
+// discardUploadedData removes the files written from an upload, block devices can't be removed so their content is
+// zeroed instead
+func discardUploadedData(paths ...string) error {
+	for _, p := range paths {
+		isDevice, err := isDeviceFunc(p)
+		if err != nil {
+			return err
+		}
+		if !isDevice {
+			if err := CleanAll(p); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := zeroDevice(p); err != nil {
+			return errors.Wrapf(err, "unable to zero the block device %q", p)
+		}
+	}
+	return nil
+}
+
+// zeroDevice zeroes the whole block device, the written ranges are not known once the data was converted
+func zeroDevice(path string) error {
+	out, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	size, err := out.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Zeroing %d bytes of the block device %s", size, path)
+	if err := zeroRange(out, 0, size); err != nil {
+		return err
+	}
+	return out.Sync()
+}
+
+// extractedPaths returns the paths of the extracted files, the directories are listed after their content so that
+// they are empty when they are removed
+func extractedPaths(dir string, files []ExtractedFile) []string {
+	paths := make([]string, 0, len(files))
+	for i := len(files) - 1; i >= 0; i-- {
+		paths = append(paths, filepath.Join(dir, files[i].Name))
+	}
+	return paths
+}
+
+// CanStreamConvert returns true if the image is a qcow2 image which can be converted while it is transferred.
+func (ud *UploadDataSource) CanStreamConvert() bool {
+	// qemu-img converts an uploaded file without keeping the clusters stored out of order in scratch space
+	return ud.readers.StreamConvert && !ud.convertsInPlace()
+}
+
+// GetVirtualSize returns the virtual size of a qcow2 image.
+func (ud *UploadDataSource) GetVirtualSize() int64 {
+	return ud.readers.VirtualSize
+}
+
 // GetURL returns the url that the data processor can use when converting the data.
 func (ud *UploadDataSource) GetURL() *url.URL {
 	return ud.url
@@ -130,11 +307,13 @@ type AsyncUploadDataSource struct {
 	ResumePhase ProcessingPhase
 }
 
-// NewAsyncUploadDataSource creates a new instance of an UploadDataSource
-func NewAsyncUploadDataSource(stream io.ReadCloser) *AsyncUploadDataSource {
+// NewAsyncUploadDataSource creates a new instance of an UploadDataSource, the checksum of the uploaded data is validated
+// if checksumValidator is not nil
+func NewAsyncUploadDataSource(stream io.ReadCloser, checksumValidator *ChecksumValidator) *AsyncUploadDataSource {
 	return &AsyncUploadDataSource{
 		uploadDataSource: UploadDataSource{
-			stream: stream,
+			stream:            stream,
+			checksumValidator: checksumValidator,
 		},
 		ResumePhase: ProcessingPhaseInfo,
 	}
@@ -163,6 +342,14 @@ func (aud *AsyncUploadDataSource) Transfer(path string, preallocation bool) (Pro
 	if err != nil {
 		return ProcessingPhaseError, err
 	}
+	if err := aud.uploadDataSource.validateChecksum(file); err != nil {
+		return ProcessingPhaseError, err
+	}
+	if aud.uploadDataSource.readers.ArchiveZip {
+		if err := extractZipImage(file, preallocation); err != nil {
+			return ProcessingPhaseError, err
+		}
+	}
 	// If we successfully wrote to the file, then the parse will succeed.
 	aud.uploadDataSource.url, _ = url.Parse(file)
 	aud.ResumePhase = ProcessingPhaseConvert
@@ -178,12 +365,35 @@ func (aud *AsyncUploadDataSource) TransferFile(fileName string, preallocation bo
 	if err != nil {
 		return ProcessingPhaseError, err
 	}
+	if err := aud.uploadDataSource.validateChecksum(fileName); err != nil {
+		return ProcessingPhaseError, err
+	}
 	// If we successfully wrote to the file, then the parse will succeed.
 	aud.uploadDataSource.url, _ = url.Parse(fileName)
 	aud.ResumePhase = ProcessingPhaseResize
 	return ProcessingPhaseValidatePause, nil
 }
 
+// TransferConvertFile is called to convert the qcow2 image from the source to raw while it is transferred to the passed
+// in file.
+func (aud *AsyncUploadDataSource) TransferConvertFile(fileName, scratchPath string, preallocation bool) (ProcessingPhase, error) {
+	if _, err := aud.uploadDataSource.TransferConvertFile(fileName, scratchPath, preallocation); err != nil {
+		return ProcessingPhaseError, err
+	}
+	aud.ResumePhase = ProcessingPhaseResize
+	return ProcessingPhaseValidatePause, nil
+}
+
+// CanStreamConvert returns true if the image is a qcow2 image which can be converted while it is transferred.
+func (aud *AsyncUploadDataSource) CanStreamConvert() bool {
+	return aud.uploadDataSource.CanStreamConvert()
+}
+
+// GetVirtualSize returns the virtual size of a qcow2 image.
+func (aud *AsyncUploadDataSource) GetVirtualSize() int64 {
+	return aud.uploadDataSource.GetVirtualSize()
+}
+
 // Close closes any readers or other open resources.
 func (aud *AsyncUploadDataSource) Close() error {
 	return aud.uploadDataSource.Close()
@@ -203,3 +413,50 @@ func (aud *AsyncUploadDataSource) GetTerminationMessage() *common.TerminationMes
 func (aud *AsyncUploadDataSource) GetResumePhase() ProcessingPhase {
 	return aud.ResumePhase
 }
+
+// RangeUploadDataSource is the data source of a raw image whose ranges were uploaded directly to the target, only the
+// post transfer processing of the target is left.
+// Sequence of phases:
+// 1. ProcessingPhaseInfo -> ProcessingPhaseResize
+type RangeUploadDataSource struct {
+	// url to the target file.
+	url *url.URL
+}
+
+// NewRangeUploadDataSource creates a new instance of a RangeUploadDataSource
+func NewRangeUploadDataSource(fileName string) *RangeUploadDataSource {
+	url, _ := url.Parse(fileName)
+	return &RangeUploadDataSource{
+		url: url,
+	}
+}
+
+// Info is called to get initial information about the data.
+func (rud *RangeUploadDataSource) Info() (ProcessingPhase, error) {
+	return ProcessingPhaseResize, nil
+}
+
+// Transfer is not supported, the data was already written to the target.
+func (rud *RangeUploadDataSource) Transfer(path string, preallocation bool) (ProcessingPhase, error) {
+	return ProcessingPhaseError, errors.New("range uploads are written directly to the target")
+}
+
+// TransferFile is not supported, the data was already written to the target.
+func (rud *RangeUploadDataSource) TransferFile(fileName string, preallocation bool) (ProcessingPhase, error) {
+	return ProcessingPhaseError, errors.New("range uploads are written directly to the target")
+}
+
+// GetURL returns the url that the data processor can use when converting the data.
+func (rud *RangeUploadDataSource) GetURL() *url.URL {
+	return rud.url
+}
+
+// GetTerminationMessage returns data to be serialized and used as the termination message of the importer.
+func (rud *RangeUploadDataSource) GetTerminationMessage() *common.TerminationMessage {
+	return nil
+}
+
+// Close closes any readers or other open resources.
+func (rud *RangeUploadDataSource) Close() error {
+	return nil
+}
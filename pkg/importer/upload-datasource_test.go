@@ -1,6 +1,10 @@
 package importer
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"io"
 	"os"
 	"path/filepath"
@@ -43,7 +47,7 @@ var _ = Describe("Upload data source", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = file.Close()
 		Expect(err).NotTo(HaveOccurred())
-		ud = NewUploadDataSource(file, dvKubevirt)
+		ud = NewUploadDataSource(file, dvKubevirt, nil)
 		result, err := ud.Info()
 		Expect(err).To(HaveOccurred())
 		Expect(ProcessingPhaseError).To(Equal(result))
@@ -53,7 +57,7 @@ var _ = Describe("Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		ud = NewUploadDataSource(file, dvKubevirt)
+		ud = NewUploadDataSource(file, dvKubevirt, nil)
 		result, err := ud.Info()
 
 		Expect(err).NotTo(HaveOccurred())
@@ -64,7 +68,7 @@ var _ = Describe("Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(tinyCoreTarFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		ud = NewUploadDataSource(file, dvArchive)
+		ud = NewUploadDataSource(file, dvArchive, nil)
 		result, err := ud.Info()
 
 		Expect(err).NotTo(HaveOccurred())
@@ -75,7 +79,7 @@ var _ = Describe("Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		ud = NewUploadDataSource(file, dvKubevirt)
+		ud = NewUploadDataSource(file, dvKubevirt, nil)
 		result, err := ud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
@@ -88,7 +92,7 @@ var _ = Describe("Upload data source", func() {
 		sourceFile, err := os.Open(fileName)
 		Expect(err).NotTo(HaveOccurred())
 
-		ud = NewUploadDataSource(sourceFile, dvContentType)
+		ud = NewUploadDataSource(sourceFile, dvContentType, nil)
 		_, err = ud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		nextPhase, err := ud.Transfer(scratchPath, false)
@@ -118,7 +122,7 @@ var _ = Describe("Upload data source", func() {
 		sourceFile, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
 
-		ud = NewUploadDataSource(sourceFile, dvKubevirt)
+		ud = NewUploadDataSource(sourceFile, dvKubevirt, nil)
 		nextPhase, err := ud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferScratch).To(Equal(nextPhase))
@@ -133,7 +137,7 @@ var _ = Describe("Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		sourceFile, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		ud = NewUploadDataSource(sourceFile, dvKubevirt)
+		ud = NewUploadDataSource(sourceFile, dvKubevirt, nil)
 		result, err := ud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
@@ -142,11 +146,139 @@ var _ = Describe("Upload data source", func() {
 		Expect(ProcessingPhaseResize).To(Equal(result))
 	})
 
+	It("TransferFile should decompress a bzip2 image", func() {
+		bz2, err := base64.StdEncoding.DecodeString(testBz2Image)
+		Expect(err).NotTo(HaveOccurred())
+		ud = NewUploadDataSource(io.NopCloser(bytes.NewReader(bz2)), dvKubevirt, nil)
+		result, err := ud.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
+		result, err = ud.TransferFile(filepath.Join(tmpDir, "file"), false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ProcessingPhaseResize).To(Equal(result))
+		Expect(os.ReadFile(filepath.Join(tmpDir, "file"))).To(Equal(testBz2Content()))
+	})
+
+	DescribeTable("TransferFile should validate the checksum of the upload", func(corrupt bool) {
+		bz2, err := base64.StdEncoding.DecodeString(testBz2Image)
+		Expect(err).NotTo(HaveOccurred())
+		sum := sha256.Sum256(bz2)
+		if corrupt {
+			sum[0]++
+		}
+		validator, err := NewChecksumValidator("sha256:" + hex.EncodeToString(sum[:]))
+		Expect(err).NotTo(HaveOccurred())
+		ud = NewUploadDataSource(io.NopCloser(bytes.NewReader(bz2)), dvKubevirt, validator)
+		result, err := ud.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
+		fileName := filepath.Join(tmpDir, "file")
+		result, err = ud.TransferFile(fileName, false)
+		if corrupt {
+			Expect(err).To(MatchError(ErrChecksumMismatch))
+			Expect(ProcessingPhaseError).To(Equal(result))
+			// The target is not populated
+			Expect(fileName).ToNot(BeAnExistingFile())
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ProcessingPhaseResize).To(Equal(result))
+		Expect(validator.Checksum()).To(Equal("sha256:" + hex.EncodeToString(sum[:])))
+	},
+		Entry("with a matching checksum", false),
+		Entry("with a different checksum", true),
+	)
+
+	It("TransferFile should zero a block device target when the checksum does not match", func() {
+		defer func() { isDeviceFunc = IsDevice }()
+		fileName := filepath.Join(tmpDir, "disk")
+		// A block device can't be removed, a regular file stands in for it
+		isDeviceFunc = func(deviceName string) (bool, error) {
+			return deviceName == fileName, nil
+		}
+		bz2, err := base64.StdEncoding.DecodeString(testBz2Image)
+		Expect(err).NotTo(HaveOccurred())
+		sum := sha256.Sum256(bz2)
+		sum[0]++
+		validator, err := NewChecksumValidator("sha256:" + hex.EncodeToString(sum[:]))
+		Expect(err).NotTo(HaveOccurred())
+		ud = NewUploadDataSource(io.NopCloser(bytes.NewReader(bz2)), dvKubevirt, validator)
+		result, err := ud.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
+		result, err = ud.TransferFile(fileName, false)
+		Expect(err).To(MatchError(ErrChecksumMismatch))
+		Expect(ProcessingPhaseError).To(Equal(result))
+		// The device keeps its size, none of the uploaded data is left on it
+		data, err := os.ReadFile(fileName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(HaveLen(len(testBz2Content())))
+		Expect(data).To(Equal(make([]byte, len(data))))
+	})
+
+	It("TransferConvertFile should convert a qcow2 image", func() {
+		img := newQcow2TestImage(9, 4096)
+		img.addL1()
+		img.addL2(0)
+		img.addCluster(2, qcow2TestCluster(512, 2))
+		ud = NewUploadDataSource(io.NopCloser(bytes.NewReader(img.bytes())), dvKubevirt, nil)
+		result, err := ud.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseTransferScratch).To(Equal(result))
+		Expect(ud.CanStreamConvert()).To(BeTrue())
+		Expect(ud.GetVirtualSize()).To(Equal(int64(4096)))
+		result, err = ud.TransferConvertFile(filepath.Join(tmpDir, "file"), tmpDir, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ProcessingPhaseResize).To(Equal(result))
+		expected := make([]byte, 4096)
+		copy(expected[1024:], qcow2TestCluster(512, 2))
+		Expect(os.ReadFile(filepath.Join(tmpDir, "file"))).To(Equal(expected))
+	})
+
+	DescribeTable("Transfer should convert an uploaded qcow2 file where it is", func(corrupt bool) {
+		img := newQcow2TestImage(9, 4096)
+		img.addL1()
+		img.addL2(0)
+		img.addCluster(2, qcow2TestCluster(512, 2))
+		file := filepath.Join(tmpDir, "upload")
+		Expect(os.WriteFile(file, img.bytes(), 0600)).To(Succeed())
+		sum := sha256.Sum256(img.bytes())
+		if corrupt {
+			sum[0]++
+		}
+		validator, err := NewChecksumValidator("sha256:" + hex.EncodeToString(sum[:]))
+		Expect(err).NotTo(HaveOccurred())
+		ud, err = NewUploadFileDataSource(file, validator)
+		Expect(err).NotTo(HaveOccurred())
+		result, err := ud.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ProcessingPhaseTransferScratch).To(Equal(result))
+		// qemu-img converts the file, the clusters stored out of order are not copied to scratch space
+		Expect(ud.CanStreamConvert()).To(BeFalse())
+		scratchDir := filepath.Join(tmpDir, "scratch")
+		Expect(os.Mkdir(scratchDir, 0700)).To(Succeed())
+		result, err = ud.Transfer(scratchDir, false)
+		// The uploaded file is kept, it is not a copy
+		Expect(os.ReadFile(file)).To(Equal(img.bytes()))
+		Expect(os.ReadDir(scratchDir)).To(BeEmpty())
+		if corrupt {
+			Expect(err).To(MatchError(ErrChecksumMismatch))
+			Expect(ProcessingPhaseError).To(Equal(result))
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ProcessingPhaseConvert).To(Equal(result))
+		Expect(ud.GetURL().Path).To(Equal(file))
+	},
+		Entry("with a matching checksum", false),
+		Entry("with a different checksum", true),
+	)
+
 	It("TransferFile should fail on streaming error", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		sourceFile, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		ud = NewUploadDataSource(sourceFile, dvKubevirt)
+		ud = NewUploadDataSource(sourceFile, dvKubevirt, nil)
 		result, err := ud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
@@ -156,7 +288,7 @@ var _ = Describe("Upload data source", func() {
 	})
 
 	It("Close with nil stream should not fail", func() {
-		ud = NewUploadDataSource(nil, dvKubevirt)
+		ud = NewUploadDataSource(nil, dvKubevirt, nil)
 		err := ud.Close()
 		Expect(err).NotTo(HaveOccurred())
 	})
@@ -188,7 +320,7 @@ var _ = Describe("Async Upload data source", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = file.Close()
 		Expect(err).NotTo(HaveOccurred())
-		aud = NewAsyncUploadDataSource(file)
+		aud = NewAsyncUploadDataSource(file, nil)
 		result, err := aud.Info()
 		Expect(err).To(HaveOccurred())
 		Expect(ProcessingPhaseError).To(Equal(result))
@@ -198,7 +330,7 @@ var _ = Describe("Async Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		aud = NewAsyncUploadDataSource(file)
+		aud = NewAsyncUploadDataSource(file, nil)
 		result, err := aud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferScratch).To(Equal(result))
@@ -208,7 +340,7 @@ var _ = Describe("Async Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		file, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		aud = NewAsyncUploadDataSource(file)
+		aud = NewAsyncUploadDataSource(file, nil)
 		result, err := aud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
@@ -221,7 +353,7 @@ var _ = Describe("Async Upload data source", func() {
 		sourceFile, err := os.Open(fileName)
 		Expect(err).NotTo(HaveOccurred())
 
-		aud = NewAsyncUploadDataSource(sourceFile)
+		aud = NewAsyncUploadDataSource(sourceFile, nil)
 		nextPhase, err := aud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferScratch).To(Equal(nextPhase))
@@ -242,7 +374,7 @@ var _ = Describe("Async Upload data source", func() {
 		sourceFile, err := os.Open(cirrosFilePath)
 		Expect(err).NotTo(HaveOccurred())
 
-		aud = NewAsyncUploadDataSource(sourceFile)
+		aud = NewAsyncUploadDataSource(sourceFile, nil)
 		nextPhase, err := aud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferScratch).To(Equal(nextPhase))
@@ -257,7 +389,7 @@ var _ = Describe("Async Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		sourceFile, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		aud = NewAsyncUploadDataSource(sourceFile)
+		aud = NewAsyncUploadDataSource(sourceFile, nil)
 		result, err := aud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
@@ -271,7 +403,7 @@ var _ = Describe("Async Upload data source", func() {
 		// Don't need to defer close, since ud.Close will close the reader
 		sourceFile, err := os.Open(tinyCoreFilePath)
 		Expect(err).NotTo(HaveOccurred())
-		aud = NewAsyncUploadDataSource(sourceFile)
+		aud = NewAsyncUploadDataSource(sourceFile, nil)
 		result, err := aud.Info()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ProcessingPhaseTransferDataFile).To(Equal(result))
@@ -281,8 +413,29 @@ var _ = Describe("Async Upload data source", func() {
 	})
 
 	It("Close with nil stream should not fail", func() {
-		aud = NewAsyncUploadDataSource(nil)
+		aud = NewAsyncUploadDataSource(nil, nil)
 		err := aud.Close()
 		Expect(err).NotTo(HaveOccurred())
 	})
 })
+
+var _ = Describe("Range upload data source", func() {
+	It("Info should return Resize", func() {
+		rud := NewRangeUploadDataSource("/data/disk.img")
+		phase, err := rud.Info()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseResize))
+		Expect(rud.GetURL().Path).To(Equal("/data/disk.img"))
+		Expect(rud.Close()).To(Succeed())
+	})
+
+	It("Transfer and TransferFile should fail", func() {
+		rud := NewRangeUploadDataSource("/data/disk.img")
+		phase, err := rud.Transfer("/scratch", false)
+		Expect(err).To(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseError))
+		phase, err = rud.TransferFile("/data/disk.img", false)
+		Expect(err).To(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseError))
+	})
+})
@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"archive/zip"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+)
+
+const zipFlagEncrypted = 0x1
+
+// extractZipImage replaces the zip archive in file with the first file of the archive, other files are ignored. Only
+// the central directory at the end of the archive describes its files reliably, so the archive is read once it is
+// stored in scratch space.
+func extractZipImage(file string, preallocation bool) error {
+	archive, err := zip.OpenReader(file)
+	if err != nil {
+		return errors.Wrap(err, "could not open zip archive")
+	}
+	defer archive.Close()
+
+	var entry *zip.File
+	for _, f := range archive.File {
+		if !f.FileInfo().IsDir() {
+			entry = f
+			break
+		}
+	}
+	if entry == nil {
+		return errors.New("zip archive has no file")
+	}
+	if entry.Flags&zipFlagEncrypted != 0 {
+		return errors.Errorf("zip file %s is encrypted, which is not supported", entry.Name)
+	}
+	klog.V(2).Infof("zip: extracting %q\n", entry.Name)
+	rc, err := entry.Open()
+	if err != nil {
+		return errors.Wrapf(err, "could not read zip file %s", entry.Name)
+	}
+	defer rc.Close()
+
+	// the CRC-32 of the file is verified by archive/zip at the end of the file
+	extracted := file + ".unzip"
+	if _, _, err := StreamDataToFile(rc, extracted, preallocation); err != nil {
+		os.Remove(extracted)
+		return errors.Wrapf(err, "could not extract zip file %s", entry.Name)
+	}
+	if err := os.Rename(extracted, file); err != nil {
+		os.Remove(extracted)
+		return errors.Wrap(err, "could not replace zip archive")
+	}
+	return nil
+}
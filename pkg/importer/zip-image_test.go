@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// createTestZip returns a zip archive with a directory, which is skipped, followed by the files with the content of
+// zipTestContent.
+func createTestZip(files ...*zip.FileHeader) []byte {
+	var archive bytes.Buffer
+	w := zip.NewWriter(&archive)
+	_, err := w.Create("disk/")
+	Expect(err).ToNot(HaveOccurred())
+	for _, hdr := range files {
+		content := zipTestContent(hdr.Name)
+		var f io.Writer
+		if hdr.CRC32 != 0 || hdr.Flags != 0 {
+			// a raw file keeps the CRC-32 and flags of the header
+			hdr.CompressedSize64 = uint64(len(content))
+			hdr.UncompressedSize64 = uint64(len(content))
+			f, err = w.CreateRaw(hdr)
+		} else {
+			f, err = w.CreateHeader(hdr)
+		}
+		Expect(err).ToNot(HaveOccurred())
+		_, err = f.Write(content)
+		Expect(err).ToNot(HaveOccurred())
+	}
+	Expect(w.Close()).To(Succeed())
+	return archive.Bytes()
+}
+
+func zipTestContent(name string) []byte {
+	return append([]byte(name), bytes.Repeat([]byte("disk image "), 100)...)
+}
+
+var _ = Describe("zip image", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		tmpDir = GinkgoT().TempDir()
+	})
+
+	writeArchive := func(archive []byte) string {
+		file := filepath.Join(tmpDir, tempFile)
+		Expect(os.WriteFile(file, archive, 0600)).To(Succeed())
+		return file
+	}
+
+	DescribeTable("should extract the first file", func(method uint16) {
+		file := writeArchive(createTestZip(
+			&zip.FileHeader{Name: "disk/disk.img", Method: method},
+			&zip.FileHeader{Name: "README", Method: method},
+		))
+		Expect(extractZipImage(file, false)).To(Succeed())
+		Expect(os.ReadFile(file)).To(Equal(zipTestContent("disk/disk.img")))
+		_, err := os.Stat(file + ".unzip")
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	},
+		Entry("compressed with deflate", zip.Deflate),
+		Entry("stored without its size in the local header", zip.Store),
+	)
+
+	DescribeTable("should fail to extract", func(archive func() []byte, expected string) {
+		file := writeArchive(archive())
+		Expect(extractZipImage(file, false)).To(MatchError(ContainSubstring(expected)))
+		_, err := os.Stat(file + ".unzip")
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	},
+		Entry("a file with an invalid checksum", func() []byte {
+			return createTestZip(&zip.FileHeader{Name: "disk.img", Method: zip.Store, CRC32: 1234})
+		}, "checksum error"),
+		Entry("an encrypted file", func() []byte {
+			return createTestZip(&zip.FileHeader{Name: "disk.img", Method: zip.Store, Flags: zipFlagEncrypted})
+		}, "zip file disk.img is encrypted"),
+		Entry("a file with an unsupported compression", func() []byte {
+			content := zipTestContent("disk.img")
+			return createTestZip(&zip.FileHeader{Name: "disk.img", Method: 12, CRC32: crc32.ChecksumIEEE(content)})
+		}, "could not read zip file disk.img"),
+		Entry("an archive without files", func() []byte {
+			return createTestZip()
+		}, "zip archive has no file"),
+		Entry("a truncated archive", func() []byte {
+			archive := createTestZip(&zip.FileHeader{Name: "disk.img", Method: zip.Deflate})
+			return archive[:len(archive)-10]
+		}, "could not open zip archive"),
+	)
+
+	It("should be transferred to scratch space and extracted there", func() {
+		// the format readers read a header of 512 bytes, the file is stored to make the archive larger
+		archive := createTestZip(&zip.FileHeader{Name: "disk.img", Method: zip.Store})
+		ud := NewUploadDataSource(io.NopCloser(bytes.NewReader(archive)), cdiv1.DataVolumeKubeVirt, nil)
+		defer ud.Close()
+		phase, err := ud.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseTransferScratch))
+		phase, err = ud.Transfer(tmpDir, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phase).To(Equal(ProcessingPhaseConvert))
+		Expect(ud.GetURL().Path).To(Equal(filepath.Join(tmpDir, tempFile)))
+		Expect(os.ReadFile(filepath.Join(tmpDir, tempFile))).To(Equal(zipTestContent("disk.img")))
+	})
+})
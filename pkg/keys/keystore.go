@@ -18,6 +18,7 @@ package keys
 
 import (
 	"context"
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 
@@ -43,7 +44,7 @@ const (
 )
 
 // GetOrCreatePrivateKey gets or creates a private key secret
-func GetOrCreatePrivateKey(client kubernetes.Interface, namespace, secretName string, installerLabels map[string]string) (*rsa.PrivateKey, error) {
+func GetOrCreatePrivateKey(client kubernetes.Interface, namespace, secretName string, installerLabels map[string]string) (crypto.Signer, error) {
 	secret, err := client.CoreV1().Secrets(namespace).Get(context.TODO(), secretName, metav1.GetOptions{})
 	if err != nil {
 		if !k8serrors.IsNotFound(err) {
@@ -80,7 +81,7 @@ func GetOrCreatePrivateKey(client kubernetes.Interface, namespace, secretName st
 		return nil, errors.Wrap(err, "Secret missing private key")
 	}
 
-	return parsePrivateKey(bytes)
+	return parseSigningKey(bytes)
 }
 
 // newPrivateKeySecret returns a new private key secret
@@ -128,17 +129,3 @@ func newSecret(client kubernetes.Interface, namespace, secretName string, data m
 
 	return secret, nil
 }
-
-func parsePrivateKey(bytes []byte) (*rsa.PrivateKey, error) {
-	obj, err := cert.ParsePrivateKeyPEM(bytes)
-	if err != nil {
-		return nil, errors.Wrap(err, "Error parsing secret")
-	}
-
-	key, ok := obj.(*rsa.PrivateKey)
-	if !ok {
-		return nil, errors.New("Invalid pem format")
-	}
-
-	return key, nil
-}
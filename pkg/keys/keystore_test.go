@@ -136,7 +136,7 @@ var _ = Describe("Create Private Key", func() {
 		actions := []core.Action{}
 		actions = append(actions, secretGetAction(namespace, secret))
 		actions = append(actions, cdiConfigGetAction(namespace))
-		actions = append(actions, privateKeySecretCreateAction(namespace, secret, privateKey))
+		actions = append(actions, privateKeySecretCreateAction(namespace, secret, privateKey.(*rsa.PrivateKey)))
 
 		checkActions(actions, client.Actions())
 	})
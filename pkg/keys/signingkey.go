@@ -0,0 +1,463 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keys
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v3"
+	"github.com/pkg/errors"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/token"
+	"kubevirt.io/containerized-data-importer/pkg/util/cert"
+)
+
+// The key signing tokens is rotated in the secret holding it:
+// - the next key is generated and published in the verification keys, so all the components trust it before it is used
+// - once published long enough, the next key replaces the current key, which is kept in the verification keys during the overlap
+// - the keys which are not trusted anymore are removed from the verification keys
+
+const (
+	// KeyStoreNextPrivateKeyFile is the key in a secret containing the private key signing tokens after the next rotation
+	KeyStoreNextPrivateKeyFile = "id_rsa.next"
+
+	// KeyStoreVerificationKeysFile is the key in a secret containing the PEM encoded public keys trusted to verify tokens
+	KeyStoreVerificationKeysFile = "verification_keys.pem"
+
+	// AnnSigningKeyNotBefore is the annotation holding the time the current key started signing tokens
+	AnnSigningKeyNotBefore = "operator.cdi.kubevirt.io/signingKeyNotBefore"
+
+	// AnnNextSigningKeyPublished is the annotation holding the time the next key was published in the verification keys
+	AnnNextSigningKeyPublished = "operator.cdi.kubevirt.io/nextSigningKeyPublished"
+
+	// SigningKeyPublishDelay is how long a key is trusted before it signs tokens, so the mounted secrets are updated everywhere
+	SigningKeyPublishDelay = 5 * time.Minute
+
+	// pemHeaderNotAfter is the PEM header holding the time a rotated key stops being trusted
+	pemHeaderNotAfter = "Not-After"
+
+	// keySourceRefreshInterval is how long key sources cache the keys
+	keySourceRefreshInterval = 30 * time.Second
+)
+
+// SigningKeyConfig contains the tunables for the key signing tokens
+type SigningKeyConfig struct {
+	Algorithm cdiv1.TokenSigningKeyAlgorithm
+	// Lifetime is how long a key signs tokens before it is rotated
+	Lifetime time.Duration
+	// Overlap is how long a rotated key is still trusted to verify tokens
+	Overlap time.Duration
+}
+
+// GenerateSigningKey generates a new key signing tokens
+func GenerateSigningKey(algorithm cdiv1.TokenSigningKeyAlgorithm) (crypto.Signer, error) {
+	switch algorithm {
+	case cdiv1.TokenSigningKeyRSA, "":
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case cdiv1.TokenSigningKeyECDSA:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case cdiv1.TokenSigningKeyEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	default:
+		return nil, errors.Errorf("unsupported signing key algorithm %s", algorithm)
+	}
+}
+
+func keyAlgorithm(key crypto.Signer) cdiv1.TokenSigningKeyAlgorithm {
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		return cdiv1.TokenSigningKeyRSA
+	case *ecdsa.PrivateKey:
+		return cdiv1.TokenSigningKeyECDSA
+	case ed25519.PrivateKey:
+		return cdiv1.TokenSigningKeyEd25519
+	default:
+		return ""
+	}
+}
+
+// KeyID returns the ID of a key, the SHA-256 thumbprint of its public key
+func KeyID(key crypto.PublicKey) (string, error) {
+	jwk := jose.JSONWebKey{Key: key}
+	thumbprint, err := jwk.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return "", errors.Wrap(err, "Error computing key thumbprint")
+	}
+	return base64.RawURLEncoding.EncodeToString(thumbprint), nil
+}
+
+// EncodeSigningKeyPEM returns the PEM encoded private key, RSA keys keep the PKCS#1 format of the existing secrets
+func EncodeSigningKeyPEM(key crypto.Signer) ([]byte, error) {
+	if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+		return cert.EncodePrivateKeyPEM(rsaKey), nil
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error encoding private key")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: cert.PrivateKeyBlockType, Bytes: der}), nil
+}
+
+func encodePublicKeyPEM(key crypto.PublicKey, headers map[string]string) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error encoding public key")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: cert.PublicKeyBlockType, Headers: headers, Bytes: der}), nil
+}
+
+func parseSigningKey(bytes []byte) (crypto.Signer, error) {
+	obj, err := cert.ParsePrivateKeyPEM(bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing private key")
+	}
+	key, ok := obj.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("Invalid pem format")
+	}
+	return key, nil
+}
+
+type verificationKey struct {
+	key      crypto.PublicKey
+	notAfter *time.Time
+}
+
+func parseVerificationKeys(data []byte) ([]verificationKey, error) {
+	var keys []verificationKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != cert.PublicKeyBlockType {
+			continue
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error parsing public key")
+		}
+		vk := verificationKey{key: key}
+		if value, ok := block.Headers[pemHeaderNotAfter]; ok {
+			notAfter, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Invalid %s header", pemHeaderNotAfter)
+			}
+			vk.notAfter = &notAfter
+		}
+		keys = append(keys, vk)
+	}
+	return keys, nil
+}
+
+func encodeVerificationKeys(keys []verificationKey) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, vk := range keys {
+		var headers map[string]string
+		if vk.notAfter != nil {
+			headers = map[string]string{pemHeaderNotAfter: vk.notAfter.UTC().Format(time.RFC3339)}
+		}
+		b, err := encodePublicKeyPEM(vk.key, headers)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	return buf.Bytes(), nil
+}
+
+func parseTime(annotations map[string]string, key string) (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, annotations[key])
+	return t, err == nil
+}
+
+// EnsureSigningKey creates or rotates the key signing tokens held in the secret, and updates the trusted verification keys.
+// It returns true if the secret was modified and needs to be updated.
+func EnsureSigningKey(secret *v1.Secret, config SigningKeyConfig, now time.Time) (bool, error) {
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	if config.Algorithm == "" {
+		config.Algorithm = cdiv1.TokenSigningKeyRSA
+	}
+	now = now.UTC().Truncate(time.Second)
+	updated := false
+
+	var current, next crypto.Signer
+	var err error
+	if data, ok := secret.Data[KeyStorePrivateKeyFile]; ok {
+		if current, err = parseSigningKey(data); err != nil {
+			return false, err
+		}
+	} else {
+		if current, err = GenerateSigningKey(config.Algorithm); err != nil {
+			return false, errors.Wrap(err, "Error generating key")
+		}
+		delete(secret.Annotations, AnnSigningKeyNotBefore)
+		updated = true
+	}
+	if data, ok := secret.Data[KeyStoreNextPrivateKeyFile]; ok {
+		if next, err = parseSigningKey(data); err != nil {
+			return false, err
+		}
+	}
+
+	// Secrets created before the rotation start their lifetime now
+	notBefore, ok := parseTime(secret.Annotations, AnnSigningKeyNotBefore)
+	if !ok {
+		notBefore = now
+		updated = true
+	}
+	published, ok := parseTime(secret.Annotations, AnnNextSigningKeyPublished)
+	if next != nil && (!ok || keyAlgorithm(next) != config.Algorithm) {
+		// The next key is replaced if its publish time is unknown, or if the algorithm changed since it was generated
+		next = nil
+		updated = true
+	}
+
+	var retired []verificationKey
+	if data, ok := secret.Data[KeyStoreVerificationKeysFile]; ok {
+		keys, err := parseVerificationKeys(data)
+		if err != nil {
+			return false, err
+		}
+		for _, vk := range keys {
+			if vk.notAfter != nil && vk.notAfter.After(now) {
+				retired = append(retired, vk)
+			}
+		}
+	}
+
+	// Keys are rotated at the end of their lifetime, or right away when the algorithm changes
+	rotate := config.Lifetime > 0
+	rotateAt := notBefore.Add(config.Lifetime)
+	if keyAlgorithm(current) != config.Algorithm {
+		rotate = true
+		rotateAt = now
+	}
+	if !rotate {
+		next = nil
+	}
+	if rotate && next == nil && !now.Before(rotateAt.Add(-SigningKeyPublishDelay)) {
+		if next, err = GenerateSigningKey(config.Algorithm); err != nil {
+			return false, errors.Wrap(err, "Error generating key")
+		}
+		published = now
+		updated = true
+	}
+	if rotate && next != nil && !now.Before(rotateAt) && !now.Before(published.Add(SigningKeyPublishDelay)) {
+		notAfter := now.Add(config.Overlap)
+		retired = append(retired, verificationKey{key: current.Public(), notAfter: &notAfter})
+		current, next = next, nil
+		notBefore = now
+		updated = true
+	}
+
+	keys := []verificationKey{{key: current.Public()}}
+	if next != nil {
+		keys = append(keys, verificationKey{key: next.Public()})
+	}
+	keys = append(keys, retired...)
+	verificationKeys, err := encodeVerificationKeys(keys)
+	if err != nil {
+		return false, err
+	}
+	if !bytes.Equal(verificationKeys, secret.Data[KeyStoreVerificationKeysFile]) {
+		secret.Data[KeyStoreVerificationKeysFile] = verificationKeys
+		updated = true
+	}
+	if !updated {
+		return false, nil
+	}
+
+	if secret.Data[KeyStorePrivateKeyFile], err = EncodeSigningKeyPEM(current); err != nil {
+		return false, err
+	}
+	if secret.Data[KeyStorePublicKeyFile], err = encodePublicKeyPEM(current.Public(), nil); err != nil {
+		return false, err
+	}
+	secret.Annotations[AnnSigningKeyNotBefore] = notBefore.Format(time.RFC3339)
+	if next != nil {
+		if secret.Data[KeyStoreNextPrivateKeyFile], err = EncodeSigningKeyPEM(next); err != nil {
+			return false, err
+		}
+		secret.Annotations[AnnNextSigningKeyPublished] = published.Format(time.RFC3339)
+	} else {
+		delete(secret.Data, KeyStoreNextPrivateKeyFile)
+		delete(secret.Annotations, AnnNextSigningKeyPublished)
+	}
+
+	return true, nil
+}
+
+type keySet struct {
+	signingKey       *jose.JSONWebKey
+	verificationKeys []jose.JSONWebKey
+}
+
+// newKeySet parses the keys of a signing key secret, the verification keys fall back to the public key of secrets
+// created before the rotation
+func newKeySet(privateKey, publicKey, verificationKeys []byte, now time.Time) (*keySet, error) {
+	ks := &keySet{}
+	if privateKey != nil {
+		key, err := parseSigningKey(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		kid, err := KeyID(key.Public())
+		if err != nil {
+			return nil, err
+		}
+		ks.signingKey = &jose.JSONWebKey{Key: key, KeyID: kid}
+	}
+
+	if verificationKeys == nil {
+		verificationKeys = publicKey
+	}
+	keys, err := parseVerificationKeys(verificationKeys)
+	if err != nil {
+		return nil, err
+	}
+	for _, vk := range keys {
+		if vk.notAfter != nil && vk.notAfter.Before(now) {
+			continue
+		}
+		kid, err := KeyID(vk.key)
+		if err != nil {
+			return nil, err
+		}
+		ks.verificationKeys = append(ks.verificationKeys, jose.JSONWebKey{Key: vk.key, KeyID: kid})
+	}
+	if len(ks.verificationKeys) == 0 {
+		return nil, errors.New("no verification keys")
+	}
+	return ks, nil
+}
+
+// cachedKeySource loads the keys at most every keySourceRefreshInterval, and keeps the last keys if loading fails
+type cachedKeySource struct {
+	load func() (*keySet, error)
+
+	mutex    sync.Mutex
+	keys     *keySet
+	loadTime time.Time
+}
+
+func (s *cachedKeySource) getKeys() (*keySet, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.keys != nil && time.Since(s.loadTime) < keySourceRefreshInterval {
+		return s.keys, nil
+	}
+	keys, err := s.load()
+	if err != nil {
+		if s.keys == nil {
+			return nil, err
+		}
+		klog.Errorf("Unable to refresh token keys, using the previous keys: %v", err)
+		return s.keys, nil
+	}
+	s.keys, s.loadTime = keys, time.Now()
+	return keys, nil
+}
+
+func (s *cachedKeySource) SigningKey() (*jose.JSONWebKey, error) {
+	keys, err := s.getKeys()
+	if err != nil {
+		return nil, err
+	}
+	if keys.signingKey == nil {
+		return nil, errors.New("no signing key")
+	}
+	return keys.signingKey, nil
+}
+
+func (s *cachedKeySource) VerificationKeys() ([]jose.JSONWebKey, error) {
+	keys, err := s.getKeys()
+	if err != nil {
+		return nil, err
+	}
+	return keys.verificationKeys, nil
+}
+
+// NewSecretKeySource returns a token.KeySource reading the keys from the signing key secret
+func NewSecretKeySource(client kubernetes.Interface, namespace, secretName string) token.KeySource {
+	return &cachedKeySource{
+		load: func() (*keySet, error) {
+			secret, err := client.CoreV1().Secrets(namespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+			if err != nil {
+				return nil, errors.Wrap(err, "Error getting secret")
+			}
+			return newKeySet(secret.Data[KeyStorePrivateKeyFile], secret.Data[KeyStorePublicKeyFile], secret.Data[KeyStoreVerificationKeysFile], time.Now())
+		},
+	}
+}
+
+// NewFileKeySource returns a token.KeySource reading the keys from the directory the signing key secret is mounted in.
+// The private key is optional, components which only verify tokens don't mount it.
+func NewFileKeySource(dir string) token.KeySource {
+	return &cachedKeySource{
+		load: func() (*keySet, error) {
+			privateKey, err := readOptionalFile(filepath.Join(dir, KeyStorePrivateKeyFile))
+			if err != nil {
+				return nil, err
+			}
+			publicKey, err := readOptionalFile(filepath.Join(dir, KeyStorePublicKeyFile))
+			if err != nil {
+				return nil, err
+			}
+			verificationKeys, err := readOptionalFile(filepath.Join(dir, KeyStoreVerificationKeysFile))
+			if err != nil {
+				return nil, err
+			}
+			return newKeySet(privateKey, publicKey, verificationKeys, time.Now())
+		},
+	}
+}
+
+func readOptionalFile(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrapf(err, "Error reading %s", path)
+	}
+	return b, nil
+}
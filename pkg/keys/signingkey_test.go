@@ -0,0 +1,176 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keys
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/keys/keystest"
+	"kubevirt.io/containerized-data-importer/pkg/token"
+)
+
+func verificationKeyIDs(secret *v1.Secret, now time.Time) []string {
+	ks, err := newKeySet(nil, secret.Data[KeyStorePublicKeyFile], secret.Data[KeyStoreVerificationKeysFile], now)
+	Expect(err).ToNot(HaveOccurred())
+	ids := []string{}
+	for _, key := range ks.verificationKeys {
+		ids = append(ids, key.KeyID)
+	}
+	return ids
+}
+
+func signingKeyID(secret *v1.Secret) string {
+	key, err := parseSigningKey(secret.Data[KeyStorePrivateKeyFile])
+	Expect(err).ToNot(HaveOccurred())
+	id, err := KeyID(key.Public())
+	Expect(err).ToNot(HaveOccurred())
+	return id
+}
+
+var _ = Describe("Signing key rotation", func() {
+	config := SigningKeyConfig{
+		Algorithm: cdiv1.TokenSigningKeyRSA,
+		Lifetime:  24 * time.Hour,
+		Overlap:   time.Hour,
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	It("Should create a signing key", func() {
+		secret := &v1.Secret{}
+		updated, err := EnsureSigningKey(secret, config, start)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(secret.Data).To(HaveKey(KeyStorePrivateKeyFile))
+		Expect(secret.Data).To(HaveKey(KeyStorePublicKeyFile))
+		Expect(secret.Data).ToNot(HaveKey(KeyStoreNextPrivateKeyFile))
+		Expect(secret.Annotations).To(HaveKeyWithValue(AnnSigningKeyNotBefore, start.Format(time.RFC3339)))
+		Expect(verificationKeyIDs(secret, start)).To(Equal([]string{signingKeyID(secret)}))
+
+		updated, err = EnsureSigningKey(secret, config, start.Add(time.Hour))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("Should keep the key of an existing secret", func() {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		secret, err := keystest.NewPrivateKeySecret("default", "mysecret", privateKey)
+		Expect(err).ToNot(HaveOccurred())
+		keyID, err := KeyID(&privateKey.PublicKey)
+		Expect(err).ToNot(HaveOccurred())
+
+		updated, err := EnsureSigningKey(secret, config, start)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(signingKeyID(secret)).To(Equal(keyID))
+		Expect(secret.Annotations).To(HaveKeyWithValue(AnnSigningKeyNotBefore, start.Format(time.RFC3339)))
+		Expect(verificationKeyIDs(secret, start)).To(Equal([]string{keyID}))
+	})
+
+	It("Should publish the next key before rotating", func() {
+		secret := &v1.Secret{}
+		_, err := EnsureSigningKey(secret, config, start)
+		Expect(err).ToNot(HaveOccurred())
+		oldKeyID := signingKeyID(secret)
+
+		By("Publishing the next key")
+		now := start.Add(config.Lifetime - SigningKeyPublishDelay)
+		updated, err := EnsureSigningKey(secret, config, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(secret.Data).To(HaveKey(KeyStoreNextPrivateKeyFile))
+		Expect(signingKeyID(secret)).To(Equal(oldKeyID))
+		ids := verificationKeyIDs(secret, now)
+		Expect(ids).To(HaveLen(2))
+		Expect(ids[0]).To(Equal(oldKeyID))
+		newKeyID := ids[1]
+
+		By("Rotating the key")
+		now = start.Add(config.Lifetime)
+		updated, err = EnsureSigningKey(secret, config, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(secret.Data).ToNot(HaveKey(KeyStoreNextPrivateKeyFile))
+		Expect(signingKeyID(secret)).To(Equal(newKeyID))
+		Expect(secret.Annotations).To(HaveKeyWithValue(AnnSigningKeyNotBefore, now.Format(time.RFC3339)))
+		Expect(verificationKeyIDs(secret, now)).To(Equal([]string{newKeyID, oldKeyID}))
+
+		By("Removing the old key after the overlap")
+		Expect(verificationKeyIDs(secret, now.Add(config.Overlap+time.Second))).To(Equal([]string{newKeyID}))
+		now = now.Add(config.Overlap + time.Second)
+		updated, err = EnsureSigningKey(secret, config, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(verificationKeyIDs(secret, start)).To(Equal([]string{newKeyID}))
+	})
+
+	It("Should rotate the key when the algorithm changes", func() {
+		secret := &v1.Secret{}
+		_, err := EnsureSigningKey(secret, config, start)
+		Expect(err).ToNot(HaveOccurred())
+
+		ecdsaConfig := config
+		ecdsaConfig.Algorithm = cdiv1.TokenSigningKeyECDSA
+		_, err = EnsureSigningKey(secret, ecdsaConfig, start.Add(time.Minute))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.Data).To(HaveKey(KeyStoreNextPrivateKeyFile))
+
+		_, err = EnsureSigningKey(secret, ecdsaConfig, start.Add(time.Minute+SigningKeyPublishDelay))
+		Expect(err).ToNot(HaveOccurred())
+		key, err := parseSigningKey(secret.Data[KeyStorePrivateKeyFile])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(key).To(BeAssignableToTypeOf(&ecdsa.PrivateKey{}))
+	})
+
+	It("Should sign and verify tokens with the secret keys", func() {
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "mysecret", Namespace: "default"},
+		}
+		ed25519Config := config
+		ed25519Config.Algorithm = cdiv1.TokenSigningKeyEd25519
+		_, err := EnsureSigningKey(secret, ed25519Config, time.Now())
+		Expect(err).ToNot(HaveOccurred())
+
+		secretKeys := NewSecretKeySource(k8sfake.NewSimpleClientset(secret), "default", "mysecret")
+		g := token.NewGeneratorForKeys("issuer", secretKeys, time.Minute)
+		t, _, err := g.Generate(&token.Payload{Name: "fakepvc"})
+		Expect(err).ToNot(HaveOccurred())
+
+		dir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, KeyStorePublicKeyFile), secret.Data[KeyStorePublicKeyFile], 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, KeyStoreVerificationKeysFile), secret.Data[KeyStoreVerificationKeysFile], 0600)).To(Succeed())
+		fileKeys := NewFileKeySource(dir)
+		_, err = fileKeys.SigningKey()
+		Expect(err).To(HaveOccurred())
+		payload, err := token.NewValidatorForKeys("issuer", fileKeys, 0).Validate(t)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(payload.Name).To(Equal("fakepvc"))
+	})
+})
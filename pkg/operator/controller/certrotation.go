@@ -39,6 +39,7 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"kubevirt.io/containerized-data-importer/pkg/keys"
 	cdicerts "kubevirt.io/containerized-data-importer/pkg/operator/resources/cert"
 )
 
@@ -49,6 +50,7 @@ const (
 // CertManager is the client interface to the certificate manager/refresher
 type CertManager interface {
 	Sync(certs []cdicerts.CertificateDefinition) error
+	SyncSigningKey(def cdicerts.SigningKeyDefinition) error
 }
 
 type certListers struct {
@@ -63,6 +65,7 @@ type certManager struct {
 	k8sClient     kubernetes.Interface
 	informers     v1helpers.KubeInformersForNamespaces
 	eventRecorder events.Recorder
+	clock         clock.PassiveClock
 }
 
 type serializedCertConfig struct {
@@ -103,6 +106,7 @@ func newCertManager(client kubernetes.Interface, installNamespace string, clock
 		k8sClient:     client,
 		informers:     informers,
 		eventRecorder: eventRecorder,
+		clock:         clock,
 	}
 }
 
@@ -327,3 +331,40 @@ func (cm *certManager) ensureTarget(cd cdicerts.CertificateDefinition, ca *crypt
 
 	return nil
 }
+
+// SyncSigningKey creates the key signing tokens, and rotates it when it expires
+func (cm *certManager) SyncSigningKey(def cdicerts.SigningKeyDefinition) error {
+	listers, ok := cm.listerMap[def.Secret.Namespace]
+	if !ok {
+		return fmt.Errorf("no lister for namespace %s", def.Secret.Namespace)
+	}
+	exists := true
+	secret, err := listers.secretLister.Secrets(def.Secret.Namespace).Get(def.Secret.Name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		exists = false
+		secret = def.Secret
+	}
+	secret = secret.DeepCopy()
+
+	config := keys.SigningKeyConfig{
+		Algorithm: def.Algorithm,
+		Lifetime:  def.Lifetime,
+		Overlap:   def.Overlap,
+	}
+	updated, err := keys.EnsureSigningKey(secret, config, cm.clock.Now())
+	if err != nil || !updated {
+		return err
+	}
+
+	if !exists {
+		log.Info("Creating token signing key", "secret", secret.Name)
+		_, err = cm.k8sClient.CoreV1().Secrets(secret.Namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+		return err
+	}
+	log.Info("Updating token signing key", "secret", secret.Name, "notBefore", secret.Annotations[keys.AnnSigningKeyNotBefore])
+	_, err = cm.k8sClient.CoreV1().Secrets(secret.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
+	return err
+}
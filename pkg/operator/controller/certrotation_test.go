@@ -21,6 +21,7 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"kubevirt.io/containerized-data-importer/pkg/keys"
 	"kubevirt.io/containerized-data-importer/pkg/operator/resources/cert"
 )
 
@@ -70,6 +71,10 @@ func (tcm *fakeCertManager) Sync(certs []cert.CertificateDefinition) error {
 	return tcm.client.Update(context.TODO(), cm)
 }
 
+func (tcm *fakeCertManager) SyncSigningKey(cert.SigningKeyDefinition) error {
+	return nil
+}
+
 // creating certs is really CPU intensive so mocking out a CertManager to just create what we need
 func newFakeCertManager(crClient client.Client, namespace string) CertManager {
 	return &fakeCertManager{client: crClient, namespace: namespace}
@@ -291,4 +296,35 @@ var _ = Describe("Cert rotation tests", func() {
 			Expect(uploadClientConfig2).To(Equal(scc3))
 		})
 	})
+
+	It("should create and rotate the token signing key", func() {
+		client := fake.NewSimpleClientset()
+		cm := newCertManagerForTest(client, namespace).(*noInformerStartCertManager)
+		Expect(cm.Start(context.Background())).To(Succeed())
+		syncStoreWithClientCalls(client, cm, namespace)
+		fakeClock := cm.clock.(*clocktesting.FakePassiveClock)
+		getSecret := func() *corev1.Secret {
+			secret, err := client.CoreV1().Secrets(namespace).Get(context.TODO(), "cdi-api-signing-key", metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			return secret
+		}
+
+		def := cert.CreateSigningKeyDefinition(&cert.FactoryArgs{Namespace: namespace, SigningKeyDuration: pt(time.Hour)})
+		Expect(cm.SyncSigningKey(def)).To(Succeed())
+		secret := getSecret()
+		Expect(cm.informers.InformersFor(namespace).Core().V1().Secrets().Informer().GetStore().Add(secret)).To(Succeed())
+		privateKey := secret.Data[keys.KeyStorePrivateKeyFile]
+		Expect(privateKey).ToNot(BeEmpty())
+		Expect(secret.Data).ToNot(HaveKey(keys.KeyStoreNextPrivateKeyFile))
+
+		fakeClock.SetTime(fakeClock.Now().Add(time.Hour - keys.SigningKeyPublishDelay))
+		Expect(cm.SyncSigningKey(def)).To(Succeed())
+		Expect(getSecret().Data).To(HaveKey(keys.KeyStoreNextPrivateKeyFile))
+		Expect(getSecret().Data[keys.KeyStorePrivateKeyFile]).To(Equal(privateKey))
+
+		fakeClock.SetTime(fakeClock.Now().Add(keys.SigningKeyPublishDelay))
+		Expect(cm.SyncSigningKey(def)).To(Succeed())
+		Expect(getSecret().Data).ToNot(HaveKey(keys.KeyStoreNextPrivateKeyFile))
+		Expect(getSecret().Data[keys.KeyStorePrivateKeyFile]).ToNot(Equal(privateKey))
+	})
 })
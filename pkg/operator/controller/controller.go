@@ -319,6 +319,25 @@ func (r *ReconcileCDI) getCertificateDefinitions(cdi *cdiv1.CDI) []cdicerts.Cert
 	return cdicerts.CreateCertificateDefinitions(args)
 }
 
+func (r *ReconcileCDI) getSigningKeyDefinition(cdi *cdiv1.CDI) cdicerts.SigningKeyDefinition {
+	args := &cdicerts.FactoryArgs{Namespace: r.namespace}
+
+	if cdi != nil && cdi.Spec.CertConfig != nil && cdi.Spec.CertConfig.TokenSigningKey != nil {
+		config := cdi.Spec.CertConfig.TokenSigningKey
+		args.SigningKeyAlgorithm = config.Algorithm
+
+		if config.Duration != nil {
+			args.SigningKeyDuration = &config.Duration.Duration
+		}
+
+		if config.Overlap != nil {
+			args.SigningKeyOverlap = &config.Overlap.Duration
+		}
+	}
+
+	return cdicerts.CreateSigningKeyDefinition(args)
+}
+
 func (r *ReconcileCDI) getConfigMap() (*corev1.ConfigMap, error) {
 	cm := &corev1.ConfigMap{}
 	key := client.ObjectKey{Name: operator.ConfigMapName, Namespace: r.namespace}
@@ -84,7 +84,10 @@ func (r *ReconcileCDI) sync(cr client.Object, logger logr.Logger) error {
 	if cdi.DeletionTimestamp != nil {
 		return nil
 	}
-	return r.certManager.Sync(r.getCertificateDefinitions(cdi))
+	if err := r.certManager.Sync(r.getCertificateDefinitions(cdi)); err != nil {
+		return err
+	}
+	return r.certManager.SyncSigningKey(r.getSigningKeyDefinition(cdi))
 }
 
 func (r *ReconcileCDI) configMapOwnerDeleted(cm *corev1.ConfigMap) (bool, error) {
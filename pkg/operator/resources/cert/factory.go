@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/operator/resources/utils"
 )
 
@@ -41,6 +42,13 @@ const (
 	ClientLifetime = 24 * time.Hour
 	// ClientRefresh is the default refresh time for the client cert
 	ClientRefresh = 12 * time.Hour
+
+	// SigningKeyLifetime is the default lifetime for the key signing tokens
+	SigningKeyLifetime = 30 * 24 * time.Hour
+	// SigningKeyOverlap is the default time a rotated signing key is still trusted
+	SigningKeyOverlap = 7 * 24 * time.Hour
+
+	signingKeySecretName = "cdi-api-signing-key"
 )
 
 // FactoryArgs contains the required parameters to generate certs
@@ -58,6 +66,11 @@ type FactoryArgs struct {
 	ClientDuration *time.Duration
 	// Duration to subtract from cert NotAfter value
 	ClientRenewBefore *time.Duration
+
+	SigningKeyAlgorithm cdiv1.TokenSigningKeyAlgorithm
+	SigningKeyDuration  *time.Duration
+	// Duration a rotated signing key is still trusted
+	SigningKeyOverlap *time.Duration
 }
 
 // CertificateConfig contains cert configuration data
@@ -89,6 +102,14 @@ type CertificateDefinition struct {
 	TargetUser *string
 }
 
+// SigningKeyDefinition contains the data required to create/rotate the key signing tokens
+type SigningKeyDefinition struct {
+	Secret    *corev1.Secret
+	Algorithm cdiv1.TokenSigningKeyAlgorithm
+	Lifetime  time.Duration
+	Overlap   time.Duration
+}
+
 // CreateCertificateDefinitions creates certificate definitions
 func CreateCertificateDefinitions(args *FactoryArgs) []CertificateDefinition {
 	defs := createCertificateDefinitions()
@@ -146,6 +167,37 @@ func CreateCertificateDefinitions(args *FactoryArgs) []CertificateDefinition {
 	return defs
 }
 
+// CreateSigningKeyDefinition creates the definition of the key signing tokens
+func CreateSigningKeyDefinition(args *FactoryArgs) SigningKeyDefinition {
+	def := SigningKeyDefinition{
+		Secret: &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   signingKeySecretName,
+				Labels: utils.ResourceBuilder.WithCommonLabels(nil),
+			},
+			Type: corev1.SecretTypeOpaque,
+		},
+		Algorithm: cdiv1.TokenSigningKeyRSA,
+		Lifetime:  SigningKeyLifetime,
+		Overlap:   SigningKeyOverlap,
+	}
+	addNamespace(args.Namespace, def.Secret)
+
+	if args.SigningKeyAlgorithm != "" {
+		def.Algorithm = args.SigningKeyAlgorithm
+	}
+
+	if args.SigningKeyDuration != nil {
+		def.Lifetime = *args.SigningKeyDuration
+	}
+
+	if args.SigningKeyOverlap != nil {
+		def.Overlap = *args.SigningKeyOverlap
+	}
+
+	return def
+}
+
 func addNamespace(namespace string, obj metav1.Object) {
 	if obj.GetNamespace() == "" {
 		obj.SetNamespace(namespace)
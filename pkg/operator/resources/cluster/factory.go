@@ -64,6 +64,7 @@ func createCRDResources(args *FactoryArgs) []client.Object {
 		createVolumeImportSourceCRD(),
 		createVolumeUploadSourceCRD(),
 		createVolumeCloneSourceCRD(),
+		createExportJobCRD(),
 		createOvirtVolumePopulatorCRD(),
 		createOpenstackVolumePopulatorCRD(),
 	}
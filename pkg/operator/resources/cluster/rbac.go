@@ -49,6 +49,7 @@ func getAdminPolicyRules() []rbacv1.PolicyRule {
 				"volumeimportsources",
 				"volumeuploadsources",
 				"volumeclonesources",
+				"exportjobs",
 			},
 			Verbs: []string{
 				"*",
@@ -71,6 +72,7 @@ func getAdminPolicyRules() []rbacv1.PolicyRule {
 			},
 			Resources: []string{
 				"uploadtokenrequests",
+				"exporttokenrequests",
 			},
 			Verbs: []string{
 				"*",
@@ -113,6 +115,7 @@ func getViewPolicyRules() []rbacv1.PolicyRule {
 				"volumeimportsources",
 				"volumeuploadsources",
 				"volumeclonesources",
+				"exportjobs",
 			},
 			Verbs: []string{
 				"get",
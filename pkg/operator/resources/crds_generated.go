@@ -102,6 +102,27 @@ spec:
                           time that we will begin to attempt to renew the certificate.
                         type: string
                     type: object
+                  tokenSigningKey:
+                    description: |-
+                      TokenSigningKey configuration
+                      Keys signing upload and clone tokens are rotated, and trusted to verify tokens during the overlap
+                    properties:
+                      algorithm:
+                        description: Algorithm of the signing key, RSA by default
+                        enum:
+                        - RSA
+                        - ECDSA
+                        - Ed25519
+                        type: string
+                      duration:
+                        description: The requested 'duration' of the key, how long
+                          it signs new tokens before it is rotated
+                        type: string
+                      overlap:
+                        description: The amount of time a rotated key is still trusted
+                          to verify the tokens it signed
+                        type: string
+                    type: object
                 type: object
               cloneStrategyOverride:
                 description: 'Clone strategy override: should we use a host-assisted
@@ -2625,6 +2646,27 @@ spec:
                           time that we will begin to attempt to renew the certificate.
                         type: string
                     type: object
+                  tokenSigningKey:
+                    description: |-
+                      TokenSigningKey configuration
+                      Keys signing upload and clone tokens are rotated, and trusted to verify tokens during the overlap
+                    properties:
+                      algorithm:
+                        description: Algorithm of the signing key, RSA by default
+                        enum:
+                        - RSA
+                        - ECDSA
+                        - Ed25519
+                        type: string
+                      duration:
+                        description: The requested 'duration' of the key, how long
+                          it signs new tokens before it is rotated
+                        type: string
+                      overlap:
+                        description: The amount of time a rotated key is still trusted
+                          to verify the tokens it signed
+                        type: string
+                    type: object
                 type: object
               cloneStrategyOverride:
                 description: 'Clone strategy override: should we use a host-assisted
@@ -5731,6 +5773,29 @@ spec:
                           - previous
                           type: object
                         type: array
+                      cloneStrategy:
+                        description: CloneStrategy lists the strategies acceptable for a clone,
+                                              overriding the storage profile and the CDI clone strategy
+                        properties:
+                          fallback:
+                            description: |-
+                              Fallback is what happens when none of the strategies is possible, the clone either falls back to a host-assisted
+                              copy (HostAssisted, the default) or fails (Fail)
+                            enum:
+                            - HostAssisted
+                            - Fail
+                            type: string
+                          strategies:
+                            description: |-
+                              Strategies are the acceptable clone strategies in order of preference, the first one possible for the source and
+                              target is used. When empty, the strategy of the storage profile or the CDI clone strategy is the only one.
+                            items:
+                              description: CDICloneStrategy defines the preferred method for performing
+                                a CDI clone (override snapshot?)
+                              type: string
+                            type: array
+                            x-kubernetes-list-type: atomic
+                        type: object
                       contentType:
                         description: 'DataVolumeContentType options: "kubevirt", "archive"'
                         enum:
@@ -5962,6 +6027,12 @@ spec:
                             description: DataVolumeSourceGCS provides the parameters
                               to create a Data Volume from an GCS source
                             properties:
+                              checksum:
+                                description: |-
+                                  Checksum is the expected checksum of the object. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+                                  Supported algorithms: md5, sha1, sha256, sha512
+                                  If specified, the importer will verify the downloaded content matches this checksum
+                                type: string
                               secretRef:
                                 description: SecretRef provides the secret reference
                                   needed to access the GCS source
@@ -5989,6 +6060,31 @@ spec:
                                   Supported algorithms: md5, sha1, sha256, sha512
                                   If specified, the importer will verify the downloaded content matches this checksum
                                 type: string
+                              checksumKeyringSecretRef:
+                                description: |-
+                                  ChecksumKeyringSecretRef is the name of a Secret containing the armored PGP public keys trusted to sign the checksum file,
+                                  in the "keyring.asc" key. The checksum file must be clearsigned by one of them. Signed checksum files are rejected without
+                                  a keyring, since their signature cannot be verified. Requires ChecksumURL
+                                type: string
+                              checksumURL:
+                                description: |-
+                                  ChecksumURL is the URL of a checksum file, e.g. SHA256SUMS, in BSD or GNU coreutils format.
+                                  The importer fetches the file and verifies the downloaded content matches the checksum listed for the file name of the URL.
+                                  Mutually exclusive with Checksum
+                                type: string
+                              diskIndex:
+                                description: |-
+                                  DiskIndex selects the disk to import when the URL points to a tar archive or an OVA appliance with several disks,
+                                  by its zero based position in the OVF descriptor of an OVA appliance, or among the files of a tar archive.
+                                  Requires the kubevirt content type. Mutually exclusive with DiskPath
+                                format: int32
+                                type: integer
+                              diskPath:
+                                description: |-
+                                  DiskPath selects the disk to import when the URL points to a tar archive or an OVA appliance with several disks,
+                                  by its path in the archive, e.g. "disks/data.qcow2". Defaults to the first disk of the OVF descriptor of an OVA
+                                  appliance. Requires the kubevirt content type. Mutually exclusive with DiskIndex
+                                type: string
                               extraHeaders:
                                 description: ExtraHeaders is a list of strings containing
                                   extra headers to include with HTTP transfer requests
@@ -6043,16 +6139,88 @@ spec:
                             - diskId
                             - url
                             type: object
+                          nfs:
+                            description: |-
+                              DataVolumeSourceNFS provides the parameters to create a Data Volume from a file on an NFS export. The export is
+                              provided by the cluster administrator as a PersistentVolume, bound to a PVC in the namespace of the Data Volume
+                            properties:
+                              checksum:
+                                description: |-
+                                  Checksum is the expected checksum of the file. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+                                  Supported algorithms: md5, sha1, sha256, sha512
+                                  If specified, the importer will verify the downloaded content matches this checksum
+                                type: string
+                              claimName:
+                                description: |-
+                                  ClaimName is the name of the PVC bound to the PersistentVolume of the NFS export, in the namespace of the Data Volume.
+                                  It is mounted read only in the importer pod
+                                type: string
+                              path:
+                                description: Path is the path of the file relative
+                                  to the root of the volume, e.g. "images/disk.qcow2"
+                                type: string
+                            required:
+                            - claimName
+                            - path
+                            type: object
                           pvc:
                             description: DataVolumeSourcePVC provides the parameters
                               to create a Data Volume from an existing PVC
                             properties:
+                              cluster:
+                                description: Cluster references the remote
+                                  cluster of the source PVC, the source PVC is
+                                  in the local cluster when unset
+                                properties:
+                                  kubeconfigSecretRef:
+                                    description: KubeconfigSecretRef is the name
+                                      of the secret holding the kubeconfig of
+                                      the remote cluster in its "kubeconfig"
+                                      key. The secret is in the namespace of the
+                                      clone target.
+                                    type: string
+                                  serviceDomain:
+                                    description: ServiceDomain is the DNS domain
+                                      through which the remote cluster reaches
+                                      the services of the local cluster, like
+                                      clusterset.local with multi-cluster
+                                      services. When unset the remote cluster
+                                      must resolve the service names of the
+                                      local cluster.
+                                    type: string
+                                required:
+                                - kubeconfigSecretRef
+                                type: object
                               name:
                                 description: The name of the source PVC
                                 type: string
                               namespace:
                                 description: The namespace of the source PVC
                                 type: string
+                              transformation:
+                                description: Transformation changes the disk
+                                  image on the clone target, a clone with a
+                                  transformation is always host assisted
+                                properties:
+                                  logicalSectorSize:
+                                    description: LogicalSectorSize converts the
+                                      GPT partition table of the disk image to
+                                      logical sectors of 512 or 4096 bytes
+                                    enum:
+                                    - 512
+                                    - 4096
+                                    format: int32
+                                    type: integer
+                                  resize:
+                                    description: Resize fits the disk image to
+                                      the size of the target, shrinking it when
+                                      the target is smaller than the source.
+                                      Only filesystem targets can be smaller
+                                      than the source, and the partitions or the
+                                      filesystem of the disk image must fit in
+                                      them.
+                                    type: boolean
+                                type: object
                             required:
                             - name
                             - namespace
@@ -6065,6 +6233,17 @@ spec:
                                 description: CertConfigMap provides a reference to
                                   the Registry certs
                                 type: string
+                              diskIndex:
+                                description: |-
+                                  DiskIndex selects the disk to import from an image with several disks, by its zero based position in the order the
+                                  disks were added to the image. Not supported with the node pull method. Mutually exclusive with DiskPath
+                                format: int32
+                                type: integer
+                              diskPath:
+                                description: |-
+                                  DiskPath selects the disk to import from an image with several disks, by its path relative to the /disk directory,
+                                  or by its title for OCI artifacts, e.g. "data.qcow2". Mutually exclusive with DiskIndex
+                                type: string
                               imageStream:
                                 description: ImageStream is the name of image stream
                                   for import
@@ -6086,6 +6265,51 @@ spec:
                                 description: SecretRef provides the secret reference
                                   needed to access the Registry source
                                 type: string
+                              signatureVerification:
+                                description: SignatureVerification is the policy
+                                  used to verify the cosign signature of the
+                                  image before it is imported
+                                properties:
+                                  keyless:
+                                    description: Keyless verifies signatures
+                                      created with a short lived Fulcio
+                                      certificate and recorded in the Rekor
+                                      transparency log
+                                    properties:
+                                      issuer:
+                                        description: Issuer is the OIDC issuer
+                                          that authenticated the signer, e.g.
+                                          "https://token.actions.githubusercontent.com"
+                                        type: string
+                                      subject:
+                                        description: Subject is the email
+                                          address or URI identity of the signer
+                                        type: string
+                                      trustedRootConfigMapRef:
+                                        description: |-
+                                          TrustedRootConfigMapRef is the name of a ConfigMap containing the PEM encoded Fulcio certificates in the "fulcio.crt" key,
+                                          and the PEM encoded Rekor public key in the "rekor.pub" key
+                                        type: string
+                                    required:
+                                    - issuer
+                                    - subject
+                                    - trustedRootConfigMapRef
+                                    type: object
+                                  publicKey:
+                                    description: PublicKey verifies signatures
+                                      created with a cosign key pair
+                                    properties:
+                                      configMapRef:
+                                        description: ConfigMapRef is the name of
+                                          the ConfigMap containing the public
+                                          key
+                                        type: string
+                                      secretRef:
+                                        description: SecretRef is the name of
+                                          the Secret containing the public key
+                                        type: string
+                                    type: object
+                                type: object
                               url:
                                 description: 'URL is the url of the registry source
                                   (starting with the scheme: docker, oci-archive)'
@@ -6100,6 +6324,12 @@ spec:
                                   containing a Certificate Authority(CA) public key,
                                   and a base64 encoded pem certificate
                                 type: string
+                              checksum:
+                                description: |-
+                                  Checksum is the expected checksum of the object. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+                                  Supported algorithms: md5, sha1, sha256, sha512
+                                  If not specified, the importer verifies the downloaded content against the object ETag when it is an MD5 digest
+                                type: string
                               secretRef:
                                 description: SecretRef provides the secret reference
                                   needed to access the S3 source
@@ -6110,6 +6340,57 @@ spec:
                             required:
                             - url
                             type: object
+                          sftp:
+                            description: DataVolumeSourceSFTP provides the parameters
+                              to create a Data Volume from a file on an SFTP server
+                            properties:
+                              checksum:
+                                description: |-
+                                  Checksum is the expected checksum of the file. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+                                  Supported algorithms: md5, sha1, sha256, sha512
+                                  If specified, the importer will verify the downloaded content matches this checksum
+                                type: string
+                              hostKeySecretRef:
+                                description: |-
+                                  HostKeySecretRef provides the secret reference holding the host keys of the SFTP server in the known_hosts key,
+                                  in the OpenSSH known_hosts format. The import fails when the server presents a key that is not listed
+                                type: string
+                              secretRef:
+                                description: |-
+                                  SecretRef provides the secret reference needed to log in to the SFTP server. The secret holds the user name in
+                                  the accessKeyId key, and either the password in the secretKey key or a private key in the ssh-privatekey key
+                                type: string
+                              url:
+                                description: URL is the url of the file on the SFTP
+                                  server, e.g. "sftp://images.example.com:2222/images/disk.qcow2"
+                                type: string
+                            required:
+                            - hostKeySecretRef
+                            - secretRef
+                            - url
+                            type: object
+                          smb:
+                            description: DataVolumeSourceSMB provides the parameters
+                              to create a Data Volume from a file on an SMB share
+                            properties:
+                              checksum:
+                                description: |-
+                                  Checksum is the expected checksum of the file. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+                                  Supported algorithms: md5, sha1, sha256, sha512
+                                  If specified, the importer will verify the downloaded content matches this checksum
+                                type: string
+                              secretRef:
+                                description: |-
+                                  SecretRef provides the secret reference needed to access the SMB share. The secret holds the user name in the
+                                  accessKeyId key and the password in the secretKey key. The share is accessed as guest when it is not set
+                                type: string
+                              url:
+                                description: URL is the url of the file on the SMB
+                                  share, starting with the share name, e.g. "smb://fileserver/images/disk.qcow2"
+                                type: string
+                            required:
+                            - url
+                            type: object
                           snapshot:
                             description: DataVolumeSourceSnapshot provides the parameters
                               to create a Data Volume from an existing VolumeSnapshot
@@ -6125,8 +6406,25 @@ spec:
                             - namespace
                             type: object
                           upload:
-                            description: DataVolumeSourceUpload provides the parameters
-                              to create a Data Volume by uploading the source
+                            description: DataVolumeSourceUpload provides the
+                              parameters to create a Data Volume by uploading
+                              the source
+                            properties:
+                              managedDataSource:
+                                description: ManagedDataSource is the name of a
+                                  DataSource in the namespace of the DataVolume,
+                                  which is pointed to the uploaded image once
+                                  the upload succeeds. The image is snapshotted
+                                  first if the StorageProfile
+                                  DataImportCronSourceFormat is snapshot. The
+                                  DataSource is created if it does not exist.
+                                type: string
+                              uploadsToKeep:
+                                description: Number of uploads of the managed
+                                  DataSource to keep, including the current one.
+                                  Default is 3.
+                                format: int32
+                                type: integer
                             type: object
                           vddk:
                             description: DataVolumeSourceVDDK provides the parameters
@@ -6460,12 +6758,55 @@ spec:
               lastImportedPVC:
                 description: LastImportedPVC is the last imported PVC
                 properties:
+                  cluster:
+                    description: Cluster references the remote cluster of the
+                      source PVC, the source PVC is in the local cluster when
+                      unset
+                    properties:
+                      kubeconfigSecretRef:
+                        description: KubeconfigSecretRef is the name of the
+                          secret holding the kubeconfig of the remote cluster in
+                          its "kubeconfig" key. The secret is in the namespace
+                          of the clone target.
+                        type: string
+                      serviceDomain:
+                        description: ServiceDomain is the DNS domain through
+                          which the remote cluster reaches the services of the
+                          local cluster, like clusterset.local with
+                          multi-cluster services. When unset the remote cluster
+                          must resolve the service names of the local cluster.
+                        type: string
+                    required:
+                    - kubeconfigSecretRef
+                    type: object
                   name:
                     description: The name of the source PVC
                     type: string
                   namespace:
                     description: The namespace of the source PVC
                     type: string
+                  transformation:
+                    description: Transformation changes the disk image on the
+                      clone target, a clone with a transformation is always host
+                      assisted
+                    properties:
+                      logicalSectorSize:
+                        description: LogicalSectorSize converts the GPT
+                          partition table of the disk image to logical sectors
+                          of 512 or 4096 bytes
+                        enum:
+                        - 512
+                        - 4096
+                        format: int32
+                        type: integer
+                      resize:
+                        description: Resize fits the disk image to the size of
+                          the target, shrinking it when the target is smaller
+                          than the source. Only filesystem targets can be
+                          smaller than the source, and the partitions or the
+                          filesystem of the disk image must fit in them.
+                        type: boolean
+                    type: object
                 required:
                 - name
                 - namespace
@@ -6556,12 +6897,57 @@ spec:
                     description: DataVolumeSourcePVC provides the parameters to create
                       a Data Volume from an existing PVC
                     properties:
+                      cluster:
+                        description: Cluster references the remote cluster of
+                          the source PVC, the source PVC is in the local cluster
+                          when unset
+                        properties:
+                          kubeconfigSecretRef:
+                            description: KubeconfigSecretRef is the name of the
+                              secret holding the kubeconfig of the remote
+                              cluster in its "kubeconfig" key. The secret is in
+                              the namespace of the clone target.
+                            type: string
+                          serviceDomain:
+                            description: ServiceDomain is the DNS domain through
+                              which the remote cluster reaches the services of
+                              the local cluster, like clusterset.local with
+                              multi-cluster services. When unset the remote
+                              cluster must resolve the service names of the
+                              local cluster.
+                            type: string
+                        required:
+                        - kubeconfigSecretRef
+                        type: object
                       name:
                         description: The name of the source PVC
                         type: string
                       namespace:
                         description: The namespace of the source PVC
                         type: string
+                      transformation:
+                        description: Transformation changes the disk image on
+                          the clone target, a clone with a transformation is
+                          always host assisted
+                        properties:
+                          logicalSectorSize:
+                            description: LogicalSectorSize converts the GPT
+                              partition table of the disk image to logical
+                              sectors of 512 or 4096 bytes
+                            enum:
+                            - 512
+                            - 4096
+                            format: int32
+                            type: integer
+                          resize:
+                            description: Resize fits the disk image to the size
+                              of the target, shrinking it when the target is
+                              smaller than the source. Only filesystem targets
+                              can be smaller than the source, and the partitions
+                              or the filesystem of the disk image must fit in
+                              them.
+                            type: boolean
+                        type: object
                     required:
                     - name
                     - namespace
@@ -6638,12 +7024,57 @@ spec:
                     description: DataVolumeSourcePVC provides the parameters to create
                       a Data Volume from an existing PVC
                     properties:
+                      cluster:
+                        description: Cluster references the remote cluster of
+                          the source PVC, the source PVC is in the local cluster
+                          when unset
+                        properties:
+                          kubeconfigSecretRef:
+                            description: KubeconfigSecretRef is the name of the
+                              secret holding the kubeconfig of the remote
+                              cluster in its "kubeconfig" key. The secret is in
+                              the namespace of the clone target.
+                            type: string
+                          serviceDomain:
+                            description: ServiceDomain is the DNS domain through
+                              which the remote cluster reaches the services of
+                              the local cluster, like clusterset.local with
+                              multi-cluster services. When unset the remote
+                              cluster must resolve the service names of the
+                              local cluster.
+                            type: string
+                        required:
+                        - kubeconfigSecretRef
+                        type: object
                       name:
                         description: The name of the source PVC
                         type: string
                       namespace:
                         description: The namespace of the source PVC
                         type: string
+                      transformation:
+                        description: Transformation changes the disk image on
+                          the clone target, a clone with a transformation is
+                          always host assisted
+                        properties:
+                          logicalSectorSize:
+                            description: LogicalSectorSize converts the GPT
+                              partition table of the disk image to logical
+                              sectors of 512 or 4096 bytes
+                            enum:
+                            - 512
+                            - 4096
+                            format: int32
+                            type: integer
+                          resize:
+                            description: Resize fits the disk image to the size
+                              of the target, shrinking it when the target is
+                              smaller than the source. Only filesystem targets
+                              can be smaller than the source, and the partitions
+                              or the filesystem of the disk image must fit in
+                              them.
+                            type: boolean
+                        type: object
                     required:
                     - name
                     - namespace
@@ -6759,6 +7190,29 @@ spec:
                   - previous
                   type: object
                 type: array
+              cloneStrategy:
+                description: CloneStrategy lists the strategies acceptable for a clone,
+                              overriding the storage profile and the CDI clone strategy
+                properties:
+                  fallback:
+                    description: |-
+                      Fallback is what happens when none of the strategies is possible, the clone either falls back to a host-assisted
+                      copy (HostAssisted, the default) or fails (Fail)
+                    enum:
+                    - HostAssisted
+                    - Fail
+                    type: string
+                  strategies:
+                    description: |-
+                      Strategies are the acceptable clone strategies in order of preference, the first one possible for the source and
+                      target is used. When empty, the strategy of the storage profile or the CDI clone strategy is the only one.
+                    items:
+                      description: CDICloneStrategy defines the preferred method for performing
+                        a CDI clone (override snapshot?)
+                      type: string
+                    type: array
+                    x-kubernetes-list-type: atomic
+                type: object
               contentType:
                 description: 'DataVolumeContentType options: "kubevirt", "archive"'
                 enum:
@@ -6987,6 +7441,12 @@ spec:
                     description: DataVolumeSourceGCS provides the parameters to create
                       a Data Volume from an GCS source
                     properties:
+                      checksum:
+                        description: |-
+                          Checksum is the expected checksum of the object. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+                          Supported algorithms: md5, sha1, sha256, sha512
+                          If specified, the importer will verify the downloaded content matches this checksum
+                        type: string
                       secretRef:
                         description: SecretRef provides the secret reference needed
                           to access the GCS source
@@ -7013,6 +7473,31 @@ spec:
                           Supported algorithms: md5, sha1, sha256, sha512
                           If specified, the importer will verify the downloaded content matches this checksum
                         type: string
+                      checksumKeyringSecretRef:
+                        description: |-
+                          ChecksumKeyringSecretRef is the name of a Secret containing the armored PGP public keys trusted to sign the checksum file,
+                          in the "keyring.asc" key. The checksum file must be clearsigned by one of them. Signed checksum files are rejected without
+                          a keyring, since their signature cannot be verified. Requires ChecksumURL
+                        type: string
+                      checksumURL:
+                        description: |-
+                          ChecksumURL is the URL of a checksum file, e.g. SHA256SUMS, in BSD or GNU coreutils format.
+                          The importer fetches the file and verifies the downloaded content matches the checksum listed for the file name of the URL.
+                          Mutually exclusive with Checksum
+                        type: string
+                      diskIndex:
+                        description: |-
+                          DiskIndex selects the disk to import when the URL points to a tar archive or an OVA appliance with several disks,
+                          by its zero based position in the OVF descriptor of an OVA appliance, or among the files of a tar archive.
+                          Requires the kubevirt content type. Mutually exclusive with DiskPath
+                        format: int32
+                        type: integer
+                      diskPath:
+                        description: |-
+                          DiskPath selects the disk to import when the URL points to a tar archive or an OVA appliance with several disks,
+                          by its path in the archive, e.g. "disks/data.qcow2". Defaults to the first disk of the OVF descriptor of an OVA
+                          appliance. Requires the kubevirt content type. Mutually exclusive with DiskIndex
+                        type: string
                       extraHeaders:
                         description: ExtraHeaders is a list of strings containing
                           extra headers to include with HTTP transfer requests
@@ -7067,16 +7552,85 @@ spec:
                     - diskId
                     - url
                     type: object
+                  nfs:
+                    description: |-
+                      DataVolumeSourceNFS provides the parameters to create a Data Volume from a file on an NFS export. The export is
+                      provided by the cluster administrator as a PersistentVolume, bound to a PVC in the namespace of the Data Volume
+                    properties:
+                      checksum:
+                        description: |-
+                          Checksum is the expected checksum of the file. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+                          Supported algorithms: md5, sha1, sha256, sha512
+                          If specified, the importer will verify the downloaded content matches this checksum
+                        type: string
+                      claimName:
+                        description: |-
+                          ClaimName is the name of the PVC bound to the PersistentVolume of the NFS export, in the namespace of the Data Volume.
+                          It is mounted read only in the importer pod
+                        type: string
+                      path:
+                        description: Path is the path of the file relative to the
+                          root of the volume, e.g. "images/disk.qcow2"
+                        type: string
+                    required:
+                    - claimName
+                    - path
+                    type: object
                   pvc:
                     description: DataVolumeSourcePVC provides the parameters to create
                       a Data Volume from an existing PVC
                     properties:
+                      cluster:
+                        description: Cluster references the remote cluster of
+                          the source PVC, the source PVC is in the local cluster
+                          when unset
+                        properties:
+                          kubeconfigSecretRef:
+                            description: KubeconfigSecretRef is the name of the
+                              secret holding the kubeconfig of the remote
+                              cluster in its "kubeconfig" key. The secret is in
+                              the namespace of the clone target.
+                            type: string
+                          serviceDomain:
+                            description: ServiceDomain is the DNS domain through
+                              which the remote cluster reaches the services of
+                              the local cluster, like clusterset.local with
+                              multi-cluster services. When unset the remote
+                              cluster must resolve the service names of the
+                              local cluster.
+                            type: string
+                        required:
+                        - kubeconfigSecretRef
+                        type: object
                       name:
                         description: The name of the source PVC
                         type: string
                       namespace:
                         description: The namespace of the source PVC
                         type: string
+                      transformation:
+                        description: Transformation changes the disk image on
+                          the clone target, a clone with a transformation is
+                          always host assisted
+                        properties:
+                          logicalSectorSize:
+                            description: LogicalSectorSize converts the GPT
+                              partition table of the disk image to logical
+                              sectors of 512 or 4096 bytes
+                            enum:
+                            - 512
+                            - 4096
+                            format: int32
+                            type: integer
+                          resize:
+                            description: Resize fits the disk image to the size
+                              of the target, shrinking it when the target is
+                              smaller than the source. Only filesystem targets
+                              can be smaller than the source, and the partitions
+                              or the filesystem of the disk image must fit in
+                              them.
+                            type: boolean
+                        type: object
                     required:
                     - name
                     - namespace
@@ -7089,6 +7643,17 @@ spec:
                         description: CertConfigMap provides a reference to the Registry
                           certs
                         type: string
+                      diskIndex:
+                        description: |-
+                          DiskIndex selects the disk to import from an image with several disks, by its zero based position in the order the
+                          disks were added to the image. Not supported with the node pull method. Mutually exclusive with DiskPath
+                        format: int32
+                        type: integer
+                      diskPath:
+                        description: |-
+                          DiskPath selects the disk to import from an image with several disks, by its path relative to the /disk directory,
+                          or by its title for OCI artifacts, e.g. "data.qcow2". Mutually exclusive with DiskIndex
+                        type: string
                       imageStream:
                         description: ImageStream is the name of image stream for import
                         type: string
@@ -7109,6 +7674,49 @@ spec:
                         description: SecretRef provides the secret reference needed
                           to access the Registry source
                         type: string
+                      signatureVerification:
+                        description: SignatureVerification is the policy used to
+                          verify the cosign signature of the image before it is
+                          imported
+                        properties:
+                          keyless:
+                            description: Keyless verifies signatures created
+                              with a short lived Fulcio certificate and recorded
+                              in the Rekor transparency log
+                            properties:
+                              issuer:
+                                description: Issuer is the OIDC issuer that
+                                  authenticated the signer, e.g.
+                                  "https://token.actions.githubusercontent.com"
+                                type: string
+                              subject:
+                                description: Subject is the email address or URI
+                                  identity of the signer
+                                type: string
+                              trustedRootConfigMapRef:
+                                description: |-
+                                  TrustedRootConfigMapRef is the name of a ConfigMap containing the PEM encoded Fulcio certificates in the "fulcio.crt" key,
+                                  and the PEM encoded Rekor public key in the "rekor.pub" key
+                                type: string
+                            required:
+                            - issuer
+                            - subject
+                            - trustedRootConfigMapRef
+                            type: object
+                          publicKey:
+                            description: PublicKey verifies signatures created
+                              with a cosign key pair
+                            properties:
+                              configMapRef:
+                                description: ConfigMapRef is the name of the
+                                  ConfigMap containing the public key
+                                type: string
+                              secretRef:
+                                description: SecretRef is the name of the Secret
+                                  containing the public key
+                                type: string
+                            type: object
+                        type: object
                       url:
                         description: 'URL is the url of the registry source (starting
                           with the scheme: docker, oci-archive)'
@@ -7123,6 +7731,12 @@ spec:
                           a Certificate Authority(CA) public key, and a base64 encoded
                           pem certificate
                         type: string
+                      checksum:
+                        description: |-
+                          Checksum is the expected checksum of the object. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+                          Supported algorithms: md5, sha1, sha256, sha512
+                          If not specified, the importer verifies the downloaded content against the object ETag when it is an MD5 digest
+                        type: string
                       secretRef:
                         description: SecretRef provides the secret reference needed
                           to access the S3 source
@@ -7133,6 +7747,57 @@ spec:
                     required:
                     - url
                     type: object
+                  sftp:
+                    description: DataVolumeSourceSFTP provides the parameters to create
+                      a Data Volume from a file on an SFTP server
+                    properties:
+                      checksum:
+                        description: |-
+                          Checksum is the expected checksum of the file. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+                          Supported algorithms: md5, sha1, sha256, sha512
+                          If specified, the importer will verify the downloaded content matches this checksum
+                        type: string
+                      hostKeySecretRef:
+                        description: |-
+                          HostKeySecretRef provides the secret reference holding the host keys of the SFTP server in the known_hosts key,
+                          in the OpenSSH known_hosts format. The import fails when the server presents a key that is not listed
+                        type: string
+                      secretRef:
+                        description: |-
+                          SecretRef provides the secret reference needed to log in to the SFTP server. The secret holds the user name in
+                          the accessKeyId key, and either the password in the secretKey key or a private key in the ssh-privatekey key
+                        type: string
+                      url:
+                        description: URL is the url of the file on the SFTP server,
+                          e.g. "sftp://images.example.com:2222/images/disk.qcow2"
+                        type: string
+                    required:
+                    - hostKeySecretRef
+                    - secretRef
+                    - url
+                    type: object
+                  smb:
+                    description: DataVolumeSourceSMB provides the parameters to create
+                      a Data Volume from a file on an SMB share
+                    properties:
+                      checksum:
+                        description: |-
+                          Checksum is the expected checksum of the file. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+                          Supported algorithms: md5, sha1, sha256, sha512
+                          If specified, the importer will verify the downloaded content matches this checksum
+                        type: string
+                      secretRef:
+                        description: |-
+                          SecretRef provides the secret reference needed to access the SMB share. The secret holds the user name in the
+                          accessKeyId key and the password in the secretKey key. The share is accessed as guest when it is not set
+                        type: string
+                      url:
+                        description: URL is the url of the file on the SMB share,
+                          starting with the share name, e.g. "smb://fileserver/images/disk.qcow2"
+                        type: string
+                    required:
+                    - url
+                    type: object
                   snapshot:
                     description: DataVolumeSourceSnapshot provides the parameters
                       to create a Data Volume from an existing VolumeSnapshot
@@ -7148,8 +7813,22 @@ spec:
                     - namespace
                     type: object
                   upload:
-                    description: DataVolumeSourceUpload provides the parameters to
-                      create a Data Volume by uploading the source
+                    description: DataVolumeSourceUpload provides the parameters
+                      to create a Data Volume by uploading the source
+                    properties:
+                      managedDataSource:
+                        description: ManagedDataSource is the name of a
+                          DataSource in the namespace of the DataVolume, which
+                          is pointed to the uploaded image once the upload
+                          succeeds. The image is snapshotted first if the
+                          StorageProfile DataImportCronSourceFormat is snapshot.
+                          The DataSource is created if it does not exist.
+                        type: string
+                      uploadsToKeep:
+                        description: Number of uploads of the managed DataSource
+                          to keep, including the current one. Default is 3.
+                        format: int32
+                        type: integer
                     type: object
                   vddk:
                     description: DataVolumeSourceVDDK provides the parameters to create
@@ -7425,6 +8104,169 @@ status:
     plural: ""
   conditions: null
   storedVersions: null
+`,
+	"exportjob": `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  annotations:
+    controller-gen.kubebuilder.io/version: v0.17.2
+  creationTimestamp: null
+  name: exportjobs.cdi.kubevirt.io
+spec:
+  group: cdi.kubevirt.io
+  names:
+    categories:
+    - all
+    kind: ExportJob
+    listKind: ExportJobList
+    plural: exportjobs
+    shortNames:
+    - ej
+    - ejs
+    singular: exportjob
+  scope: Namespaced
+  versions:
+  - additionalPrinterColumns:
+    - description: The phase of the export
+      jsonPath: .status.phase
+      name: Phase
+      type: string
+    - jsonPath: .metadata.creationTimestamp
+      name: Age
+      type: date
+    name: v1beta1
+    schema:
+      openAPIV3Schema:
+        description: ExportJob exports the contents of a PVC to object storage or
+          to a container registry
+        properties:
+          apiVersion:
+            description: |-
+              APIVersion defines the versioned schema of this representation of an object.
+              Servers should convert recognized schemas to the latest internal value, and
+              may reject unrecognized values.
+              More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources
+            type: string
+          kind:
+            description: |-
+              Kind is a string value representing the REST resource this object represents.
+              Servers may infer this from the endpoint the client submits requests to.
+              Cannot be updated.
+              In CamelCase.
+              More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+            type: string
+          metadata:
+            type: object
+          spec:
+            description: ExportJobSpec defines specification for ExportJob
+            properties:
+              compress:
+                description: |-
+                  Compress compresses the exported image with gzip. Only supported for S3 targets, the layers of container disks
+                  are always compressed
+                type: boolean
+              format:
+                description: Format is the format of the exported image, raw (default)
+                  or qcow2
+                type: string
+              source:
+                description: Source is the PersistentVolumeClaim or DataVolume exported,
+                  in the namespace of the ExportJob
+                properties:
+                  apiGroup:
+                    description: |-
+                      APIGroup is the group for the resource being referenced.
+                      If APIGroup is not specified, the specified Kind must be in the core API group.
+                      For any other third-party types, APIGroup is required.
+                    type: string
+                  kind:
+                    description: Kind is the type of resource being referenced
+                    type: string
+                  name:
+                    description: Name is the name of resource being referenced
+                    type: string
+                required:
+                - kind
+                - name
+                type: object
+                x-kubernetes-map-type: atomic
+              target:
+                description: Target is where the contents of the source are exported
+                  to
+                properties:
+                  registry:
+                    description: ExportJobTargetRegistry pushes the exported image
+                      as a container disk
+                    properties:
+                      certConfigMap:
+                        description: CertConfigMap provides a reference to the Registry
+                          certs
+                        type: string
+                      secretRef:
+                        description: SecretRef provides the secret reference needed
+                          to access the Registry target
+                        type: string
+                      url:
+                        description: URL is the url of the container disk image (starting
+                          with the docker scheme), e.g. docker://registry:5000/disks/fedora:latest
+                        type: string
+                    required:
+                    - url
+                    type: object
+                  s3:
+                    description: ExportJobTargetS3 uploads the exported image as an
+                      S3 object
+                    properties:
+                      certConfigMap:
+                        description: CertConfigMap is a configmap reference, containing
+                          a Certificate Authority(CA) public key, and a base64 encoded
+                          pem certificate
+                        type: string
+                      secretRef:
+                        description: SecretRef provides the secret reference needed
+                          to access the S3 target, it has the same keys as for S3 sources
+                        type: string
+                      url:
+                        description: URL is the url of the S3 object the image is
+                          uploaded to
+                        type: string
+                    required:
+                    - url
+                    type: object
+                type: object
+            required:
+            - source
+            - target
+            type: object
+          status:
+            description: ExportJobStatus provides the most recently observed status
+              of the ExportJob
+            properties:
+              digest:
+                description: Digest is the digest of the exported data, the sha256
+                  of the S3 object or the manifest digest of the container disk
+                type: string
+              message:
+                description: Message is a human-readable message about the phase,
+                  like why the export failed
+                type: string
+              phase:
+                description: Phase is the current phase of the export
+                type: string
+            type: object
+        required:
+        - spec
+        type: object
+    served: true
+    storage: true
+    subresources:
+      status: {}
+status:
+  acceptedNames:
+    kind: ""
+    plural: ""
+  conditions: null
+  storedVersions: null
 `,
 	"objecttransfer": `apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
@@ -7957,6 +8799,29 @@ spec:
           spec:
             description: VolumeCloneSourceSpec defines the Spec field for VolumeCloneSource
             properties:
+              cloneStrategy:
+                description: CloneStrategy lists the strategies acceptable for the clone,
+                              overriding the storage profile and the CDI clone strategy
+                properties:
+                  fallback:
+                    description: |-
+                      Fallback is what happens when none of the strategies is possible, the clone either falls back to a host-assisted
+                      copy (HostAssisted, the default) or fails (Fail)
+                    enum:
+                    - HostAssisted
+                    - Fail
+                    type: string
+                  strategies:
+                    description: |-
+                      Strategies are the acceptable clone strategies in order of preference, the first one possible for the source and
+                      target is used. When empty, the strategy of the storage profile or the CDI clone strategy is the only one.
+                    items:
+                      description: CDICloneStrategy defines the preferred method for performing
+                        a CDI clone (override snapshot?)
+                      type: string
+                    type: array
+                    x-kubernetes-list-type: atomic
+                type: object
               preallocation:
                 description: Preallocation controls whether storage for the target
                   PVC should be allocated in advance.
@@ -8086,6 +8951,12 @@ spec:
                     description: DataVolumeSourceGCS provides the parameters to create
                       a Data Volume from an GCS source
                     properties:
+                      checksum:
+                        description: |-
+                          Checksum is the expected checksum of the object. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+                          Supported algorithms: md5, sha1, sha256, sha512
+                          If specified, the importer will verify the downloaded content matches this checksum
+                        type: string
                       secretRef:
                         description: SecretRef provides the secret reference needed
                           to access the GCS source
@@ -8112,6 +8983,31 @@ spec:
                           Supported algorithms: md5, sha1, sha256, sha512
                           If specified, the importer will verify the downloaded content matches this checksum
                         type: string
+                      checksumKeyringSecretRef:
+                        description: |-
+                          ChecksumKeyringSecretRef is the name of a Secret containing the armored PGP public keys trusted to sign the checksum file,
+                          in the "keyring.asc" key. The checksum file must be clearsigned by one of them. Signed checksum files are rejected without
+                          a keyring, since their signature cannot be verified. Requires ChecksumURL
+                        type: string
+                      checksumURL:
+                        description: |-
+                          ChecksumURL is the URL of a checksum file, e.g. SHA256SUMS, in BSD or GNU coreutils format.
+                          The importer fetches the file and verifies the downloaded content matches the checksum listed for the file name of the URL.
+                          Mutually exclusive with Checksum
+                        type: string
+                      diskIndex:
+                        description: |-
+                          DiskIndex selects the disk to import when the URL points to a tar archive or an OVA appliance with several disks,
+                          by its zero based position in the OVF descriptor of an OVA appliance, or among the files of a tar archive.
+                          Requires the kubevirt content type. Mutually exclusive with DiskPath
+                        format: int32
+                        type: integer
+                      diskPath:
+                        description: |-
+                          DiskPath selects the disk to import when the URL points to a tar archive or an OVA appliance with several disks,
+                          by its path in the archive, e.g. "disks/data.qcow2". Defaults to the first disk of the OVF descriptor of an OVA
+                          appliance. Requires the kubevirt content type. Mutually exclusive with DiskIndex
+                        type: string
                       extraHeaders:
                         description: ExtraHeaders is a list of strings containing
                           extra headers to include with HTTP transfer requests
@@ -8166,6 +9062,30 @@ spec:
                     - diskId
                     - url
                     type: object
+                  nfs:
+                    description: |-
+                      DataVolumeSourceNFS provides the parameters to create a Data Volume from a file on an NFS export. The export is
+                      provided by the cluster administrator as a PersistentVolume, bound to a PVC in the namespace of the Data Volume
+                    properties:
+                      checksum:
+                        description: |-
+                          Checksum is the expected checksum of the file. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+                          Supported algorithms: md5, sha1, sha256, sha512
+                          If specified, the importer will verify the downloaded content matches this checksum
+                        type: string
+                      claimName:
+                        description: |-
+                          ClaimName is the name of the PVC bound to the PersistentVolume of the NFS export, in the namespace of the Data Volume.
+                          It is mounted read only in the importer pod
+                        type: string
+                      path:
+                        description: Path is the path of the file relative to the
+                          root of the volume, e.g. "images/disk.qcow2"
+                        type: string
+                    required:
+                    - claimName
+                    - path
+                    type: object
                   registry:
                     description: DataVolumeSourceRegistry provides the parameters
                       to create a Data Volume from an registry source
@@ -8174,6 +9094,17 @@ spec:
                         description: CertConfigMap provides a reference to the Registry
                           certs
                         type: string
+                      diskIndex:
+                        description: |-
+                          DiskIndex selects the disk to import from an image with several disks, by its zero based position in the order the
+                          disks were added to the image. Not supported with the node pull method. Mutually exclusive with DiskPath
+                        format: int32
+                        type: integer
+                      diskPath:
+                        description: |-
+                          DiskPath selects the disk to import from an image with several disks, by its path relative to the /disk directory,
+                          or by its title for OCI artifacts, e.g. "data.qcow2". Mutually exclusive with DiskIndex
+                        type: string
                       imageStream:
                         description: ImageStream is the name of image stream for import
                         type: string
@@ -8194,6 +9125,49 @@ spec:
                         description: SecretRef provides the secret reference needed
                           to access the Registry source
                         type: string
+                      signatureVerification:
+                        description: SignatureVerification is the policy used to
+                          verify the cosign signature of the image before it is
+                          imported
+                        properties:
+                          keyless:
+                            description: Keyless verifies signatures created
+                              with a short lived Fulcio certificate and recorded
+                              in the Rekor transparency log
+                            properties:
+                              issuer:
+                                description: Issuer is the OIDC issuer that
+                                  authenticated the signer, e.g.
+                                  "https://token.actions.githubusercontent.com"
+                                type: string
+                              subject:
+                                description: Subject is the email address or URI
+                                  identity of the signer
+                                type: string
+                              trustedRootConfigMapRef:
+                                description: |-
+                                  TrustedRootConfigMapRef is the name of a ConfigMap containing the PEM encoded Fulcio certificates in the "fulcio.crt" key,
+                                  and the PEM encoded Rekor public key in the "rekor.pub" key
+                                type: string
+                            required:
+                            - issuer
+                            - subject
+                            - trustedRootConfigMapRef
+                            type: object
+                          publicKey:
+                            description: PublicKey verifies signatures created
+                              with a cosign key pair
+                            properties:
+                              configMapRef:
+                                description: ConfigMapRef is the name of the
+                                  ConfigMap containing the public key
+                                type: string
+                              secretRef:
+                                description: SecretRef is the name of the Secret
+                                  containing the public key
+                                type: string
+                            type: object
+                        type: object
                       url:
                         description: 'URL is the url of the registry source (starting
                           with the scheme: docker, oci-archive)'
@@ -8208,6 +9182,12 @@ spec:
                           a Certificate Authority(CA) public key, and a base64 encoded
                           pem certificate
                         type: string
+                      checksum:
+                        description: |-
+                          Checksum is the expected checksum of the object. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+                          Supported algorithms: md5, sha1, sha256, sha512
+                          If not specified, the importer verifies the downloaded content against the object ETag when it is an MD5 digest
+                        type: string
                       secretRef:
                         description: SecretRef provides the secret reference needed
                           to access the S3 source
@@ -8218,6 +9198,57 @@ spec:
                     required:
                     - url
                     type: object
+                  sftp:
+                    description: DataVolumeSourceSFTP provides the parameters to create
+                      a Data Volume from a file on an SFTP server
+                    properties:
+                      checksum:
+                        description: |-
+                          Checksum is the expected checksum of the file. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+                          Supported algorithms: md5, sha1, sha256, sha512
+                          If specified, the importer will verify the downloaded content matches this checksum
+                        type: string
+                      hostKeySecretRef:
+                        description: |-
+                          HostKeySecretRef provides the secret reference holding the host keys of the SFTP server in the known_hosts key,
+                          in the OpenSSH known_hosts format. The import fails when the server presents a key that is not listed
+                        type: string
+                      secretRef:
+                        description: |-
+                          SecretRef provides the secret reference needed to log in to the SFTP server. The secret holds the user name in
+                          the accessKeyId key, and either the password in the secretKey key or a private key in the ssh-privatekey key
+                        type: string
+                      url:
+                        description: URL is the url of the file on the SFTP server,
+                          e.g. "sftp://images.example.com:2222/images/disk.qcow2"
+                        type: string
+                    required:
+                    - hostKeySecretRef
+                    - secretRef
+                    - url
+                    type: object
+                  smb:
+                    description: DataVolumeSourceSMB provides the parameters to create
+                      a Data Volume from a file on an SMB share
+                    properties:
+                      checksum:
+                        description: |-
+                          Checksum is the expected checksum of the file. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+                          Supported algorithms: md5, sha1, sha256, sha512
+                          If specified, the importer will verify the downloaded content matches this checksum
+                        type: string
+                      secretRef:
+                        description: |-
+                          SecretRef provides the secret reference needed to access the SMB share. The secret holds the user name in the
+                          accessKeyId key and the password in the secretKey key. The share is accessed as guest when it is not set
+                        type: string
+                      url:
+                        description: URL is the url of the file on the SMB share,
+                          starting with the share name, e.g. "smb://fileserver/images/disk.qcow2"
+                        type: string
+                    required:
+                    - url
+                    type: object
                   vddk:
                     description: DataVolumeSourceVDDK provides the parameters to create
                       a Data Volume from a Vmware source
@@ -26,11 +26,11 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"kubevirt.io/containerized-data-importer/pkg/common"
-	"kubevirt.io/containerized-data-importer/pkg/controller"
 	utils "kubevirt.io/containerized-data-importer/pkg/operator/resources/utils"
 	"kubevirt.io/containerized-data-importer/pkg/util"
 	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/api"
@@ -266,7 +266,7 @@ func createControllerDeployment(controllerImage, importerImage, clonerImage, ovi
 	container.VolumeMounts = []corev1.VolumeMount{
 		{
 			Name:      "cdi-api-signing-key",
-			MountPath: controller.TokenKeyDir,
+			MountPath: common.TokenKeyDir,
 		},
 		{
 			Name:      "uploadserver-ca-cert",
@@ -295,23 +295,8 @@ func createControllerDeployment(controllerImage, importerImage, clonerImage, ovi
 	deployment.Spec.Template.Spec.Containers = []corev1.Container{container}
 	deployment.Spec.Template.Spec.Volumes = []corev1.Volume{
 		{
-			Name: "cdi-api-signing-key",
-			VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName: "cdi-api-signing-key",
-					Items: []corev1.KeyToPath{
-						{
-							Key:  "id_rsa.pub",
-							Path: "id_rsa.pub",
-						},
-						{
-							Key:  "id_rsa",
-							Path: "id_rsa",
-						},
-					},
-					DefaultMode: &defaultMode,
-				},
-			},
+			Name:         "cdi-api-signing-key",
+			VolumeSource: signingKeyVolumeSource(&defaultMode, "id_rsa.pub", "id_rsa"),
 		},
 		{
 			Name: "uploadserver-ca-cert",
@@ -389,6 +374,41 @@ func createControllerDeployment(controllerImage, importerImage, clonerImage, ovi
 	return deployment
 }
 
+// signingKeyVolumeSource projects the keys of the signing key secret, and the verification keys if the secret has them
+// already. The secret of a previous release has no verification keys, the public key is trusted alone until the
+// operator adds them.
+func signingKeyVolumeSource(defaultMode *int32, keys ...string) corev1.VolumeSource {
+	items := make([]corev1.KeyToPath, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, corev1.KeyToPath{Key: key, Path: key})
+	}
+	return corev1.VolumeSource{
+		Projected: &corev1.ProjectedVolumeSource{
+			Sources: []corev1.VolumeProjection{
+				{
+					Secret: &corev1.SecretProjection{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "cdi-api-signing-key"},
+						Items:                items,
+					},
+				},
+				{
+					Secret: &corev1.SecretProjection{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "cdi-api-signing-key"},
+						Items: []corev1.KeyToPath{
+							{
+								Key:  "verification_keys.pem",
+								Path: "verification_keys.pem",
+							},
+						},
+						Optional: ptr.To(true),
+					},
+				},
+			},
+			DefaultMode: defaultMode,
+		},
+	}
+}
+
 func createPrometheusService() *corev1.Service {
 	service := utils.ResourceBuilder.CreateService(common.PrometheusServiceName, common.PrometheusLabelKey, common.PrometheusLabelValue, nil)
 	service.Spec.Ports = []corev1.ServicePort{
@@ -80,6 +80,17 @@ func getUploadProxyNamespacedRules() []rbacv1.PolicyRule {
 				"get",
 			},
 		},
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"configmaps",
+			},
+			Verbs: []string{
+				"create",
+			},
+		},
 	}
 }
 
@@ -103,19 +114,6 @@ func createUploadProxyDeployment(image, verbosity, pullPolicy string, imagePullS
 	deployment.Spec.Template.SetLabels(labels)
 
 	container := utils.CreateContainer(common.CDIUploadProxyResourceName, image, verbosity, pullPolicy)
-	container.Env = []corev1.EnvVar{
-		{
-			Name: "APISERVER_PUBLIC_KEY",
-			ValueFrom: &corev1.EnvVarSource{
-				SecretKeyRef: &corev1.SecretKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: "cdi-api-signing-key",
-					},
-					Key: "id_rsa.pub",
-				},
-			},
-		},
-	}
 	container.ReadinessProbe = &corev1.Probe{
 		ProbeHandler: corev1.ProbeHandler{
 			HTTPGet: &corev1.HTTPGetAction{
@@ -134,6 +132,11 @@ func createUploadProxyDeployment(image, verbosity, pullPolicy string, imagePullS
 		TimeoutSeconds:      1,
 	}
 	container.VolumeMounts = []corev1.VolumeMount{
+		{
+			Name:      "cdi-api-signing-key",
+			MountPath: common.TokenKeyDir,
+			ReadOnly:  true,
+		},
 		{
 			Name:      "server-cert",
 			MountPath: "/var/run/certs/cdi-uploadproxy-server-cert",
@@ -154,6 +157,10 @@ func createUploadProxyDeployment(image, verbosity, pullPolicy string, imagePullS
 
 	deployment.Spec.Template.Spec.Containers = []corev1.Container{container}
 	deployment.Spec.Template.Spec.Volumes = []corev1.Volume{
+		{
+			Name:         "cdi-api-signing-key",
+			VolumeSource: signingKeyVolumeSource(&defaultMode, "id_rsa.pub"),
+		},
 		{
 			Name: "server-cert",
 			VolumeSource: corev1.VolumeSource{
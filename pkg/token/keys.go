@@ -0,0 +1,109 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2026 Red Hat, Inc.
+ *
+ */
+
+package token
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+
+	jose "github.com/go-jose/go-jose/v3"
+	"github.com/pkg/errors"
+)
+
+// SigningKeySource provides the key signing new tokens, so it can be rotated
+type SigningKeySource interface {
+	// SigningKey returns the private key signing new tokens, its KeyID is set in the header of the tokens
+	SigningKey() (*jose.JSONWebKey, error)
+}
+
+// VerificationKeySource provides the keys trusted to verify tokens, so they can be rotated
+type VerificationKeySource interface {
+	// VerificationKeys returns the public keys trusted to verify tokens
+	VerificationKeys() ([]jose.JSONWebKey, error)
+}
+
+// KeySource provides the keys to both sign and verify tokens
+type KeySource interface {
+	SigningKeySource
+	VerificationKeySource
+}
+
+type staticKeySource struct {
+	signingKey       *jose.JSONWebKey
+	verificationKeys []jose.JSONWebKey
+}
+
+// NewStaticKeySource returns a KeySource for a single private key, which is never rotated
+func NewStaticKeySource(key crypto.Signer) KeySource {
+	return &staticKeySource{
+		signingKey:       &jose.JSONWebKey{Key: key},
+		verificationKeys: []jose.JSONWebKey{{Key: key.Public()}},
+	}
+}
+
+// NewStaticVerificationKeySource returns a VerificationKeySource for a single public key, which is never rotated
+func NewStaticVerificationKeySource(key crypto.PublicKey) VerificationKeySource {
+	return &staticKeySource{
+		verificationKeys: []jose.JSONWebKey{{Key: key}},
+	}
+}
+
+func (s *staticKeySource) SigningKey() (*jose.JSONWebKey, error) {
+	if s.signingKey == nil {
+		return nil, errors.New("no signing key")
+	}
+	return s.signingKey, nil
+}
+
+func (s *staticKeySource) VerificationKeys() ([]jose.JSONWebKey, error) {
+	return s.verificationKeys, nil
+}
+
+// SignatureAlgorithm returns the algorithm of the tokens signed with the key
+func SignatureAlgorithm(key interface{}) (jose.SignatureAlgorithm, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey, *rsa.PublicKey:
+		return jose.PS256, nil
+	case *ecdsa.PrivateKey:
+		return ecdsaSignatureAlgorithm(k.Curve)
+	case *ecdsa.PublicKey:
+		return ecdsaSignatureAlgorithm(k.Curve)
+	case ed25519.PrivateKey, ed25519.PublicKey:
+		return jose.EdDSA, nil
+	default:
+		return "", errors.Errorf("unsupported key type %T", key)
+	}
+}
+
+func ecdsaSignatureAlgorithm(curve elliptic.Curve) (jose.SignatureAlgorithm, error) {
+	switch curve {
+	case elliptic.P256():
+		return jose.ES256, nil
+	case elliptic.P384():
+		return jose.ES384, nil
+	case elliptic.P521():
+		return jose.ES512, nil
+	default:
+		return "", errors.Errorf("unsupported curve %s", curve.Params().Name)
+	}
+}
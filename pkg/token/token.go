@@ -36,6 +36,9 @@ const (
 
 	// OperationUpload is the type of token for uploading to a PVC
 	OperationUpload Operation = "Upload"
+
+	// OperationExport is the type of token for exporting the contents of a PVC
+	OperationExport Operation = "Export"
 )
 
 // Operation is the type of the token
@@ -48,6 +51,18 @@ type Payload struct {
 	Namespace string                      `json:"namespace,omitempty"`
 	Resource  metav1.GroupVersionResource `json:"resource,omitempty"`
 	Params    map[string]string           `json:"params,omitempty"`
+	// SingleUse tokens are only accepted for one request
+	SingleUse bool `json:"singleUse,omitempty"`
+	// ClientCIDRs restricts the addresses of the clients allowed to use the token
+	ClientCIDRs []string `json:"clientCIDRs,omitempty"`
+
+	// ID is the unique ID (jti) of the token, tokens with an ID can be tracked and revoked
+	ID string `json:"-"`
+	// IssuedAt and Expiry are set when the token is generated or validated
+	IssuedAt time.Time `json:"-"`
+	Expiry   time.Time `json:"-"`
+	// Lifetime overrides the lifetime of the generator
+	Lifetime time.Duration `json:"-"`
 }
 
 // Validator validates tokens
@@ -57,13 +72,18 @@ type Validator interface {
 
 type validator struct {
 	issuer string
-	key    *rsa.PublicKey
+	keys   VerificationKeySource
 	leeway time.Duration
 }
 
 // NewValidator return a new Validator implementation
 func NewValidator(issuer string, key *rsa.PublicKey, leeway time.Duration) Validator {
-	return &validator{issuer: issuer, key: key, leeway: leeway}
+	return NewValidatorForKeys(issuer, NewStaticVerificationKeySource(key), leeway)
+}
+
+// NewValidatorForKeys returns a new Validator trusting the keys of the source, which may be rotated
+func NewValidatorForKeys(issuer string, keys VerificationKeySource, leeway time.Duration) Validator {
+	return &validator{issuer: issuer, keys: keys, leeway: leeway}
 }
 
 // Validate checks the token signature and returns the contents
@@ -73,10 +93,30 @@ func (v *validator) Validate(token string) (*Payload, error) {
 		return nil, err
 	}
 
+	keys, err := v.keys.VerificationKeys()
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting token verification keys")
+	}
+
+	var kid string
+	if len(tok.Headers) > 0 {
+		kid = tok.Headers[0].KeyID
+	}
+
 	public := &jwt.Claims{}
 	private := &Payload{}
 
-	if err = tok.Claims(v.key, public, private); err != nil {
+	// Tokens signed before keys had IDs are checked against all the keys
+	err = errors.Errorf("no key %q to verify the token", kid)
+	for _, key := range keys {
+		if kid != "" && key.KeyID != "" && key.KeyID != kid {
+			continue
+		}
+		if err = tok.Claims(key.Key, public, private); err == nil {
+			break
+		}
+	}
+	if err != nil {
 		return nil, err
 	}
 
@@ -89,41 +129,93 @@ func (v *validator) Validate(token string) (*Payload, error) {
 		return nil, err
 	}
 
+	setClaims(private, public)
+
 	return private, nil
 }
 
+// KeyID returns the ID of the key which signed the token from its kid header, the signature is not verified
+func KeyID(token string) (string, error) {
+	tok, err := jwt.ParseSigned(token)
+	if err != nil {
+		return "", err
+	}
+	if len(tok.Headers) == 0 {
+		return "", nil
+	}
+	return tok.Headers[0].KeyID, nil
+}
+
+func setClaims(payload *Payload, claims *jwt.Claims) {
+	payload.ID = claims.ID
+	if claims.IssuedAt != nil {
+		payload.IssuedAt = claims.IssuedAt.Time()
+	}
+	if claims.Expiry != nil {
+		payload.Expiry = claims.Expiry.Time()
+	}
+}
+
 // Generator generates tokens
 type Generator interface {
-	Generate(*Payload) (string, error)
+	// Generate returns the signed token and its contents, with the claims it was issued with
+	Generate(*Payload) (string, *Payload, error)
 }
 
 type generator struct {
 	issuer   string
-	key      *rsa.PrivateKey
+	keys     SigningKeySource
 	lifetime time.Duration
 }
 
 // NewGenerator returns a new Generator
 func NewGenerator(issuer string, key *rsa.PrivateKey, lifetime time.Duration) Generator {
-	return &generator{issuer: issuer, key: key, lifetime: lifetime}
+	return NewGeneratorForKeys(issuer, NewStaticKeySource(key), lifetime)
 }
 
-// Generate generates a token from the given parameters
-func (g *generator) Generate(payload *Payload) (string, error) {
-	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.PS256, Key: g.key}, nil)
+// NewGeneratorForKeys returns a new Generator signing tokens with the current key of the source
+func NewGeneratorForKeys(issuer string, keys SigningKeySource, lifetime time.Duration) Generator {
+	return &generator{issuer: issuer, keys: keys, lifetime: lifetime}
+}
+
+// Generate generates a token from the given parameters, the payload is not modified
+func (g *generator) Generate(payload *Payload) (string, *Payload, error) {
+	key, err := g.keys.SigningKey()
+	if err != nil {
+		return "", nil, errors.Wrap(err, "error getting token signing key")
+	}
+	alg, err := SignatureAlgorithm(key.Key)
 	if err != nil {
-		return "", errors.Wrap(err, "error creating JWT signer")
+		return "", nil, err
+	}
+	// The signer sets the kid header to the KeyID of the key
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: alg, Key: key}, nil)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "error creating JWT signer")
 	}
 
 	t := time.Now()
-
-	return jwt.Signed(signer).
+	lifetime := g.lifetime
+	if payload.Lifetime > 0 {
+		lifetime = payload.Lifetime
+	}
+	claims := &jwt.Claims{
+		ID:        payload.ID,
+		Issuer:    g.issuer,
+		IssuedAt:  jwt.NewNumericDate(t),
+		NotBefore: jwt.NewNumericDate(t),
+		Expiry:    jwt.NewNumericDate(t.Add(lifetime)),
+	}
+	token, err := jwt.Signed(signer).
 		Claims(payload).
-		Claims(&jwt.Claims{
-			Issuer:    g.issuer,
-			IssuedAt:  jwt.NewNumericDate(t),
-			NotBefore: jwt.NewNumericDate(t),
-			Expiry:    jwt.NewNumericDate(t.Add(g.lifetime)),
-		}).
+		Claims(claims).
 		CompactSerialize()
+	if err != nil {
+		return "", nil, err
+	}
+
+	issued := *payload
+	issued.Lifetime = 0
+	setClaims(&issued, claims)
+	return token, &issued, nil
 }
@@ -19,11 +19,16 @@
 package token
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"reflect"
 	"time"
 
+	jose "github.com/go-jose/go-jose/v3"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -59,14 +64,57 @@ var _ = Describe("Token test", func() {
 
 		g := NewGenerator(issuer, key, 5*time.Minute)
 
-		signedToken, err := g.Generate(tokenData)
+		signedToken, issued, err := g.Generate(tokenData)
 		Expect(err).ToNot(HaveOccurred())
+		Expect(issued.Expiry.Sub(issued.IssuedAt)).To(Equal(5 * time.Minute))
+		Expect(tokenData.Expiry.IsZero()).To(BeTrue())
 
 		validator := NewValidator(issuer, &key.PublicKey, 0)
 
 		payload, err := validator.Validate(signedToken)
 		Expect(err).ToNot(HaveOccurred())
-		Expect(reflect.DeepEqual(tokenData, payload)).To(BeTrue())
+		Expect(reflect.DeepEqual(issued, payload)).To(BeTrue())
+	})
+
+	It("Token with ID, lifetime and client restrictions", func() {
+		issuer := "issuer"
+
+		key, err := generateTestKey()
+		Expect(err).ToNot(HaveOccurred())
+
+		tokenData := &Payload{
+			Operation:   OperationUpload,
+			Name:        "fakepvc",
+			Namespace:   "fakenamespace",
+			ID:          "token-id",
+			SingleUse:   true,
+			ClientCIDRs: []string{"10.0.0.0/8"},
+			Lifetime:    time.Hour,
+		}
+
+		g := NewGenerator(issuer, key, 5*time.Minute)
+
+		signedToken, issued, err := g.Generate(tokenData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(issued.ID).To(Equal("token-id"))
+		Expect(issued.Expiry.Sub(issued.IssuedAt)).To(Equal(time.Hour))
+
+		validator := NewValidator(issuer, &key.PublicKey, 0)
+
+		payload, err := validator.Validate(signedToken)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(payload.ID).To(Equal("token-id"))
+		Expect(payload.SingleUse).To(BeTrue())
+		Expect(payload.ClientCIDRs).To(Equal([]string{"10.0.0.0/8"}))
+		Expect(payload.IssuedAt).To(Equal(issued.IssuedAt))
+		Expect(payload.Expiry).To(Equal(issued.Expiry))
+
+		otherToken, _, err := g.Generate(&Payload{Operation: OperationUpload, Name: "fakepvc", Namespace: "fakenamespace"})
+		Expect(err).ToNot(HaveOccurred())
+		payload, err = validator.Validate(otherToken)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(payload.ID).To(BeEmpty())
+		Expect(payload.Expiry.Sub(payload.IssuedAt)).To(Equal(5 * time.Minute))
 	})
 
 	It("Token timeout", func() {
@@ -88,7 +136,7 @@ var _ = Describe("Token test", func() {
 
 		g := NewGenerator(issuer, key, 200*time.Millisecond)
 
-		signedToken, err := g.Generate(tokenData)
+		signedToken, _, err := g.Generate(tokenData)
 		Expect(err).ToNot(HaveOccurred())
 
 		validator := NewValidator(issuer, &key.PublicKey, 0)
@@ -116,7 +164,7 @@ var _ = Describe("Token test", func() {
 
 		g := NewGenerator("foo", key, 5*time.Minute)
 
-		signedToken, err := g.Generate(tokenData)
+		signedToken, _, err := g.Generate(tokenData)
 		Expect(err).ToNot(HaveOccurred())
 
 		validator := NewValidator("bar", &key.PublicKey, 0)
@@ -147,7 +195,7 @@ var _ = Describe("Token test", func() {
 
 		g := NewGenerator(issuer, key, 5*time.Minute)
 
-		signedToken, err := g.Generate(tokenData)
+		signedToken, _, err := g.Generate(tokenData)
 		Expect(err).ToNot(HaveOccurred())
 
 		validator := NewValidator(issuer, &key2.PublicKey, 0)
@@ -157,4 +205,67 @@ var _ = Describe("Token test", func() {
 		_, err = validator.Validate(signedToken)
 		Expect(err).To(HaveOccurred())
 	})
+
+	DescribeTable("Rotated keys", func(generateKey func() (crypto.Signer, error)) {
+		issuer := "issuer"
+
+		oldKey, err := generateKey()
+		Expect(err).ToNot(HaveOccurred())
+		newKey, err := generateKey()
+		Expect(err).ToNot(HaveOccurred())
+		otherKey, err := generateKey()
+		Expect(err).ToNot(HaveOccurred())
+
+		keys := &testKeySource{
+			signingKey: &jose.JSONWebKey{Key: oldKey, KeyID: "old"},
+			verificationKeys: []jose.JSONWebKey{
+				{Key: newKey.Public(), KeyID: "new"},
+				{Key: oldKey.Public(), KeyID: "old"},
+			},
+		}
+		g := NewGeneratorForKeys(issuer, keys, 5*time.Minute)
+		validator := NewValidatorForKeys(issuer, keys, 0)
+
+		oldToken, _, err := g.Generate(&Payload{Operation: OperationUpload, Name: "fakepvc"})
+		Expect(err).ToNot(HaveOccurred())
+		keys.signingKey = &jose.JSONWebKey{Key: newKey, KeyID: "new"}
+		newToken, _, err := g.Generate(&Payload{Operation: OperationUpload, Name: "fakepvc"})
+		Expect(err).ToNot(HaveOccurred())
+		keys.signingKey = &jose.JSONWebKey{Key: otherKey, KeyID: "old"}
+		otherToken, _, err := g.Generate(&Payload{Operation: OperationUpload, Name: "fakepvc"})
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, t := range []string{oldToken, newToken} {
+			payload, err := validator.Validate(t)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(payload.Name).To(Equal("fakepvc"))
+		}
+		_, err = validator.Validate(otherToken)
+		Expect(err).To(HaveOccurred())
+
+		// Once the old key is not trusted anymore its tokens are rejected
+		keys.verificationKeys = keys.verificationKeys[:1]
+		_, err = validator.Validate(oldToken)
+		Expect(err).To(HaveOccurred())
+	},
+		Entry("RSA", func() (crypto.Signer, error) { return generateTestKey() }),
+		Entry("ECDSA", func() (crypto.Signer, error) { return ecdsa.GenerateKey(elliptic.P256(), rand.Reader) }),
+		Entry("Ed25519", func() (crypto.Signer, error) {
+			_, key, err := ed25519.GenerateKey(rand.Reader)
+			return key, err
+		}),
+	)
 })
+
+type testKeySource struct {
+	signingKey       *jose.JSONWebKey
+	verificationKeys []jose.JSONWebKey
+}
+
+func (s *testKeySource) SigningKey() (*jose.JSONWebKey, error) {
+	return s.signingKey, nil
+}
+
+func (s *testKeySource) VerificationKeys() ([]jose.JSONWebKey, error) {
+	return s.verificationKeys, nil
+}
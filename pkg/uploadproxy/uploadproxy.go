@@ -3,12 +3,16 @@ package uploadproxy
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"html"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -46,6 +50,9 @@ const (
 	proxyRequestTimeout = 24 * time.Hour
 
 	uploadTokenLeeway = 10 * time.Second
+
+	// podErrorReason is the reason of the running condition when the upload server exited with an error
+	podErrorReason = "Error"
 )
 
 // Server is the public interface to the upload proxy
@@ -65,12 +72,15 @@ type ClientCreator interface {
 
 type urlLookupFunc func(string, string, string) string
 type uploadPossibleFunc func(*v1.PersistentVolumeClaim) error
+type exportPossibleFunc func(*v1.PersistentVolumeClaim) error
 
 type uploadProxyApp struct {
 	bindAddress string
 	bindPort    uint
 
 	client kubernetes.Interface
+	// namespace is the CDI namespace, keeping the upload token records
+	namespace string
 
 	cdiConfigTLSWatcher cryptowatch.CdiConfigTLSWatcher
 
@@ -83,8 +93,10 @@ type uploadProxyApp struct {
 	handler http.Handler
 
 	// test hooks
-	urlResolver    urlLookupFunc
-	uploadPossible uploadPossibleFunc
+	urlResolver       urlLookupFunc
+	uploadPossible    uploadPossibleFunc
+	exportURLResolver urlLookupFunc
+	exportPossible    exportPossibleFunc
 }
 
 type clientCreator struct {
@@ -94,16 +106,26 @@ type clientCreator struct {
 
 var authHeaderMatcher = regexp.MustCompile(`(?i)^Bearer\s+([A-Za-z0-9\-\._~\+\/]+)$`)
 
+var errTokenUsed = errors.New("token already used")
+
+const (
+	// usedTokenState is the state of the single use tokens recorded as used in the upload tokens config map by
+	// previous versions
+	usedTokenState = "used"
+	// revokedTokenState is the state of the revoked tokens in the upload tokens config map
+	revokedTokenState = "revoked"
+)
+
 // NewUploadProxy returns an initialized uploadProxyApp
 func NewUploadProxy(bindAddress string,
 	bindPort uint,
-	apiServerPublicKey string,
+	tokenKeys token.VerificationKeySource,
 	cdiConfigTLSWatcher cryptowatch.CdiConfigTLSWatcher,
 	certWatcher CertWatcher,
 	clientCertFetcher fetcher.CertFetcher,
 	serverCAFetcher fetcher.CertBundleFetcher,
-	client kubernetes.Interface) (Server, error) {
-	var err error
+	client kubernetes.Interface,
+	namespace string) (Server, error) {
 	app := &uploadProxyApp{
 		bindAddress:         bindAddress,
 		bindPort:            bindPort,
@@ -111,13 +133,13 @@ func NewUploadProxy(bindAddress string,
 		certWatcher:         certWatcher,
 		clientCreator:       &clientCreator{certFetcher: clientCertFetcher, bundleFetcher: serverCAFetcher},
 		client:              client,
+		namespace:           namespace,
 		urlResolver:         controller.GetUploadServerURL,
 		uploadPossible:      controller.UploadPossibleForPVC,
-	}
-	// retrieve RSA key used by apiserver to sign tokens
-	err = app.getSigningKey(apiServerPublicKey)
-	if err != nil {
-		return nil, errors.Errorf("unable to retrieve apiserver signing key: %v", errors.WithStack(err))
+		exportURLResolver:   controller.GetExportServerURL,
+		exportPossible:      controller.ExportPossibleForPVC,
+		// the keys used by apiserver to sign tokens are rotated, so they are looked up on each validation
+		tokenValidator: token.NewValidatorForKeys(common.UploadTokenIssuer, tokenKeys, uploadTokenLeeway),
 	}
 
 	app.initHandler()
@@ -167,7 +189,23 @@ func (app *uploadProxyApp) initHandler() {
 	for _, path := range common.ProxyPaths {
 		mux.HandleFunc(path, app.handleUploadRequest)
 	}
-	app.handler = cors.AllowAll().Handler(mux)
+	mux.HandleFunc(common.UploadPathStatus, app.handleStatusRequest)
+	mux.HandleFunc(common.ExportPath, app.handleExportRequest)
+	corsOptions := cors.Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{
+			http.MethodHead,
+			http.MethodGet,
+			http.MethodPost,
+			http.MethodPut,
+			http.MethodPatch,
+			http.MethodDelete,
+		},
+		AllowedHeaders: []string{"*"},
+		// Let browsers read the state of resumable uploads and the size of sparse exports
+		ExposedHeaders: []string{"Location", common.UploadOffsetHeader, common.UploadLengthHeader, common.ExportLengthHeader},
+	}
+	app.handler = cors.New(corsOptions).Handler(mux)
 }
 
 func (app *uploadProxyApp) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -181,35 +219,179 @@ func (app *uploadProxyApp) handleHealthzRequest(w http.ResponseWriter, r *http.R
 	}
 }
 
-func (app *uploadProxyApp) handleUploadRequest(w http.ResponseWriter, r *http.Request) {
+// validateToken returns the payload of the token of the request for the operation, or writes the error response and
+// returns false if the token is invalid
+func (app *uploadProxyApp) validateToken(w http.ResponseWriter, r *http.Request, operation token.Operation) (*token.Payload, bool) {
 	tokenHeader := r.Header.Get("Authorization")
 	if tokenHeader == "" {
 		w.WriteHeader(http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	match := authHeaderMatcher.FindStringSubmatch(tokenHeader)
 	if len(match) != 2 {
 		w.WriteHeader(http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	tokenData, err := app.tokenValidator.Validate(match[1])
 	if err != nil {
 		w.WriteHeader(http.StatusUnauthorized)
-		return
+		return nil, false
 	}
 
-	if tokenData.Operation != token.OperationUpload ||
+	if tokenData.Operation != operation ||
 		tokenData.Name == "" ||
 		tokenData.Namespace == "" ||
 		tokenData.Resource.Resource != "persistentvolumeclaims" {
 		klog.Errorf("Bad token %+v", tokenData)
 		w.WriteHeader(http.StatusBadRequest)
-		return
+		return nil, false
+	}
+
+	if !clientAllowed(r.RemoteAddr, tokenData.ClientCIDRs) {
+		klog.Errorf("Rejecting token %s used from %s", tokenData.ID, r.RemoteAddr)
+		w.WriteHeader(http.StatusForbidden)
+		return nil, false
+	}
+
+	revoked, err := app.tokenRevoked(r.Context(), tokenData)
+	if err != nil {
+		klog.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return nil, false
+	}
+	if revoked {
+		klog.Errorf("Rejecting revoked token %s for pvc %s/%s", tokenData.ID, tokenData.Namespace, tokenData.Name)
+		w.WriteHeader(http.StatusUnauthorized)
+		return nil, false
 	}
 
 	klog.V(1).Infof("Received valid token: pvc: %s, namespace: %s", tokenData.Name, tokenData.Namespace)
+	return tokenData, true
+}
+
+// clientAllowed returns true if the address of the client is in one of the CIDRs the token is restricted to
+func clientAllowed(remoteAddr string, clientCIDRs []string) bool {
+	if len(clientCIDRs) == 0 {
+		return true
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, cidr := range clientCIDRs {
+		if !strings.Contains(cidr, "/") {
+			if ip.Equal(net.ParseIP(cidr)) {
+				return true
+			}
+			continue
+		}
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil && ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenRevoked returns true if the ID of the token was revoked in the upload tokens config map, or the token was
+// issued before the tokens of its PVC were revoked
+func (app *uploadProxyApp) tokenRevoked(ctx context.Context, tokenData *token.Payload) (bool, error) {
+	if tokenData.ID != "" {
+		cm, err := app.client.CoreV1().ConfigMaps(app.namespace).Get(ctx, common.UploadTokensConfigMap, metav1.GetOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return false, err
+		}
+		if err == nil {
+			state, _ := parseTokenRecord(cm.Data[tokenData.ID])
+			// Single use tokens used to be recorded in the same config map
+			if state == revokedTokenState || (state == usedTokenState && tokenData.SingleUse) {
+				return true, nil
+			}
+		}
+	}
+
+	pvc, err := app.client.CoreV1().PersistentVolumeClaims(tokenData.Namespace).Get(ctx, tokenData.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			// Requests for missing PVCs are rejected by the handlers
+			return false, nil
+		}
+		return false, err
+	}
+	if notBefore, ok := pvc.Annotations[cc.AnnUploadTokensNotBefore]; ok {
+		t, err := time.Parse(time.RFC3339, notBefore)
+		if err != nil {
+			klog.Errorf("Invalid %s annotation on pvc %s/%s: %v", cc.AnnUploadTokensNotBefore, pvc.Namespace, pvc.Name, err)
+			// Fail closed, the owner of the PVC wanted to revoke tokens
+			return true, nil
+		}
+		if tokenData.IssuedAt.Before(t) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// parseTokenRecord returns the state and the expiry of a token recorded in the upload tokens config map, records
+// are "<state>@<expiry unix time>", or just "<state>" for records kept until they are removed
+func parseTokenRecord(record string) (string, *time.Time) {
+	state, expiry, found := strings.Cut(record, "@")
+	if !found {
+		return state, nil
+	}
+	exp, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return state, nil
+	}
+	t := time.Unix(exp, 0)
+	return state, &t
+}
+
+// useToken records the use of a single use token in its own config map of the CDI namespace, it returns errTokenUsed
+// if the token was already used. Creating the record is atomic, so a token can't be used twice even with several
+// proxies. The CDI controller removes the records of expired tokens.
+func (app *uploadProxyApp) useToken(ctx context.Context, tokenData *token.Payload) error {
+	if tokenData.ID == "" {
+		return errors.New("single use token without ID")
+	}
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      usedTokenRecordName(tokenData.ID),
+			Namespace: app.namespace,
+			Labels: map[string]string{
+				common.CDIComponentLabel:    common.CDIUploadProxyResourceName,
+				common.UsedUploadTokenLabel: "",
+			},
+		},
+		Data: map[string]string{
+			common.UsedUploadTokenIDKey:     tokenData.ID,
+			common.UsedUploadTokenExpiryKey: strconv.FormatInt(tokenData.Expiry.Unix(), 10),
+		},
+	}
+	_, err := app.client.CoreV1().ConfigMaps(app.namespace).Create(ctx, cm, metav1.CreateOptions{})
+	if k8serrors.IsAlreadyExists(err) {
+		return errTokenUsed
+	}
+	return err
+}
+
+// usedTokenRecordName returns the name of the config map recording the use of a token, token IDs are hashed as they
+// are not necessarily valid object names
+func usedTokenRecordName(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return common.UsedUploadTokenPrefix + hex.EncodeToString(sum[:])
+}
+
+func (app *uploadProxyApp) handleUploadRequest(w http.ResponseWriter, r *http.Request) {
+	tokenData, ok := app.validateToken(w, r, token.OperationUpload)
+	if !ok {
+		return
+	}
 
 	pvc, err := app.uploadReady(tokenData.Name, tokenData.Namespace)
 	if err != nil {
@@ -235,9 +417,125 @@ func (app *uploadProxyApp) handleUploadRequest(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	if tokenData.SingleUse {
+		if err := app.useToken(r.Context(), tokenData); err != nil {
+			klog.Errorf("handleUploadRequest: can't use single use token %s: %v", tokenData.ID, err)
+			if errors.Is(err, errTokenUsed) {
+				w.WriteHeader(http.StatusUnauthorized)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+	}
+
+	// The checksum of the token is passed to the upload server, replacing any value set by the client
+	r.Header.Del(common.UploadChecksumHeader)
+	if checksum := tokenData.Params[common.UploadChecksumParam]; checksum != "" {
+		r.Header.Set(common.UploadChecksumHeader, checksum)
+	}
+
 	app.proxyUploadRequest(uploadPath, w, r)
 }
 
+// handleStatusRequest returns the status of the upload to the PVC of the token. The upload server reports the
+// progress of the upload while it runs, the PVC annotations tell the result once the upload pod completed or failed.
+func (app *uploadProxyApp) handleStatusRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	tokenData, ok := app.validateToken(w, r, token.OperationUpload)
+	if !ok {
+		return
+	}
+
+	pvc, err := app.client.CoreV1().PersistentVolumeClaims(tokenData.Namespace).Get(r.Context(), tokenData.Name, metav1.GetOptions{})
+	if err != nil {
+		klog.Error(err)
+		if k8serrors.IsNotFound(err) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	status := uploadStatusFromPVC(pvc)
+	if status == nil && populators.IsPVCDataSourceRefKind(pvc, cdiv1.VolumeUploadSourceRef) {
+		// The upload server of the populator writes to PVC'
+		pvc, err = app.getPopulationPVC(r.Context(), pvc, tokenData.Namespace)
+		if err != nil {
+			klog.Error(err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if pvc == nil {
+			status = &common.UploadStatus{Phase: common.UploadPhasePending}
+		}
+	}
+	if status == nil {
+		status, err = app.getUploadServerStatus(app.urlResolver(pvc.Namespace, pvc.Name, common.UploadPathStatus))
+		if err != nil {
+			klog.Error(err)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, err = fmt.Fprint(w, html.EscapeString(err.Error()))
+			if err != nil {
+				klog.Errorf("handleStatusRequest: failed to send error response: %v", err)
+			}
+			return
+		}
+		status.Restarts, _ = strconv.Atoi(pvc.Annotations[cc.AnnPodRestarts])
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		klog.Errorf("handleStatusRequest: failed to send response; %v", err)
+	}
+}
+
+func (app *uploadProxyApp) getUploadServerStatus(statusURL string) (*common.UploadStatus, error) {
+	client, err := app.clientCreator.CreateClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating http client")
+	}
+	resp, err := client.Get(statusURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting the status of the upload server")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code %d getting the status of the upload server", resp.StatusCode)
+	}
+	status := &common.UploadStatus{}
+	if err := json.NewDecoder(resp.Body).Decode(status); err != nil {
+		return nil, errors.Wrap(err, "error decoding the status of the upload server")
+	}
+	return status, nil
+}
+
+// uploadStatusFromPVC returns the status of the upload from the annotations of the PVC, or nil if the upload server
+// is running and has to be asked for it
+func uploadStatusFromPVC(pvc *v1.PersistentVolumeClaim) *common.UploadStatus {
+	restarts, _ := strconv.Atoi(pvc.Annotations[cc.AnnPodRestarts])
+	status := &common.UploadStatus{Restarts: restarts}
+	switch {
+	case pvc.Annotations[cc.AnnPodPhase] == string(v1.PodSucceeded):
+		status.Phase = common.UploadPhaseSucceeded
+		status.Checksum = pvc.Annotations[cc.AnnUploadChecksum]
+	case pvc.Annotations[cc.AnnPodPhase] == string(v1.PodFailed),
+		pvc.Annotations[cc.AnnRunningCondition] == "false" && pvc.Annotations[cc.AnnRunningConditionReason] == podErrorReason:
+		status.Phase = common.UploadPhaseFailed
+		status.Message = pvc.Annotations[cc.AnnRunningConditionMessage]
+	default:
+		if ready, _ := strconv.ParseBool(pvc.Annotations[cc.AnnPodReady]); ready {
+			return nil
+		}
+		status.Phase = common.UploadPhasePending
+	}
+	return status
+}
+
 func (app *uploadProxyApp) resolveUploadPath(pvc *v1.PersistentVolumeClaim, pvcName, defaultPath string) (string, error) {
 	var path string
 	contentType := pvc.Annotations[cc.AnnContentType]
@@ -245,6 +543,12 @@ func (app *uploadProxyApp) resolveUploadPath(pvc *v1.PersistentVolumeClaim, pvcN
 	case string(cdiv1.DataVolumeKubeVirt), "":
 		path = defaultPath
 	case string(cdiv1.DataVolumeArchive):
+		if strings.HasPrefix(defaultPath, common.UploadPathResumable) {
+			return "", fmt.Errorf("rejecting upload request for PVC %s - resumable uploads of archives are not supported", pvcName)
+		}
+		if defaultPath == common.UploadPathRange {
+			return "", fmt.Errorf("rejecting upload request for PVC %s - range uploads of archives are not supported", pvcName)
+		}
 		if strings.Contains(defaultPath, "alpha") {
 			path = common.UploadArchiveAlphaPath
 		} else {
@@ -294,6 +598,58 @@ func (app *uploadProxyApp) uploadReady(pvcName, pvcNamespace string) (*v1.Persis
 	return pvc, err
 }
 
+// handleExportRequest streams the contents of the PVC of the token from its export server, the query parameters
+// selecting the format of the export are passed to the export server
+func (app *uploadProxyApp) handleExportRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	tokenData, ok := app.validateToken(w, r, token.OperationExport)
+	if !ok {
+		return
+	}
+
+	pvc, err := app.exportReady(tokenData.Name, tokenData.Namespace)
+	if err != nil {
+		klog.Error(err)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		// Return the error to the caller in the body.
+		_, err = fmt.Fprint(w, html.EscapeString(err.Error()))
+		if err != nil {
+			klog.Errorf("handleExportRequest: failed to send error response: %v", err)
+		}
+		return
+	}
+
+	exportURL := app.exportURLResolver(pvc.Namespace, pvc.Name, common.ExportPath)
+	if r.URL.RawQuery != "" {
+		exportURL += "?" + r.URL.RawQuery
+	}
+	app.proxyUploadRequest(exportURL, w, r)
+}
+
+func (app *uploadProxyApp) exportReady(pvcName, pvcNamespace string) (*v1.PersistentVolumeClaim, error) {
+	var pvc *v1.PersistentVolumeClaim
+	err := wait.PollUntilContextTimeout(context.TODO(), waitReadyImterval, waitReadyTime, true, func(ctx context.Context) (bool, error) {
+		var err error
+		pvc, err = app.client.CoreV1().PersistentVolumeClaims(pvcNamespace).Get(ctx, pvcName, metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return false, fmt.Errorf("rejecting Export Request for PVC %s that doesn't exist", pvcName)
+			}
+			return false, err
+		}
+		if err := app.exportPossible(pvc); err != nil {
+			return false, err
+		}
+		ready, _ := strconv.ParseBool(pvc.Annotations[cc.AnnExportPodReady])
+		return ready, nil
+	})
+
+	return pvc, err
+}
+
 func (app *uploadProxyApp) proxyUploadRequest(uploadPath string, w http.ResponseWriter, r *http.Request) {
 	client, err := app.clientCreator.CreateClient()
 	if err != nil {
@@ -324,16 +680,6 @@ func (app *uploadProxyApp) proxyUploadRequest(uploadPath string, w http.Response
 	}
 }
 
-func (app *uploadProxyApp) getSigningKey(publicKeyPEM string) error {
-	publicKey, err := controller.DecodePublicKey([]byte(publicKeyPEM))
-	if err != nil {
-		return err
-	}
-
-	app.tokenValidator = token.NewValidator(common.UploadTokenIssuer, publicKey, uploadTokenLeeway)
-	return nil
-}
-
 func (app *uploadProxyApp) Start() error {
 	return app.startTLS()
 }
@@ -1,22 +1,27 @@
 package uploadproxy
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/token"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert"
@@ -51,14 +56,44 @@ func (*validateFailure) Validate(string) (*token.Payload, error) {
 	return nil, fmt.Errorf("Bad token")
 }
 
-func getPublicKeyEncoded() string {
-	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
-	Expect(err).ToNot(HaveOccurred())
+type validateSuccessWithParams struct {
+	params map[string]string
+}
 
-	publicKeyPem, err := cert.EncodePublicKeyPEM(&privateKey.PublicKey)
-	Expect(err).ToNot(HaveOccurred())
+func (v *validateSuccessWithParams) Validate(t string) (*token.Payload, error) {
+	payload, err := (&validateSuccess{}).Validate(t)
+	payload.Params = v.params
+	return payload, err
+}
+
+type validateRestrictedSuccess struct {
+	singleUse   bool
+	clientCIDRs []string
+	issuedAt    time.Time
+}
 
-	return string(publicKeyPem)
+func (v *validateRestrictedSuccess) Validate(t string) (*token.Payload, error) {
+	payload, err := (&validateSuccess{}).Validate(t)
+	payload.ID = "token-id"
+	payload.SingleUse = v.singleUse
+	payload.ClientCIDRs = v.clientCIDRs
+	payload.IssuedAt = v.issuedAt
+	payload.Expiry = time.Now().Add(5 * time.Minute)
+	return payload, err
+}
+
+type validateExportSuccess struct{}
+
+func (*validateExportSuccess) Validate(t string) (*token.Payload, error) {
+	payload, err := (&validateSuccess{}).Validate(t)
+	payload.Operation = token.OperationExport
+	return payload, err
+}
+
+func getTokenKey() *rsa.PrivateKey {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).ToNot(HaveOccurred())
+	return privateKey
 }
 
 func getHTTPClientConfig() *httpClientConfig {
@@ -134,13 +169,17 @@ func createApp() *uploadProxyApp {
 }
 
 var _ = Describe("Certificate functions", func() {
-	It("Get signing key", func() {
-		publicKeyPEM := getPublicKeyEncoded()
-		app := createApp()
+	It("Validate tokens signed with the apiserver key", func() {
+		privateKey := getTokenKey()
+		server, err := NewUploadProxy("127.0.0.1", 0, token.NewStaticVerificationKeySource(&privateKey.PublicKey), nil, nil, nil, nil, k8sfake.NewSimpleClientset(), "cdi")
+		Expect(err).ToNot(HaveOccurred())
+		app := server.(*uploadProxyApp)
 
-		err := app.getSigningKey(publicKeyPEM)
+		t, _, err := token.NewGenerator(common.UploadTokenIssuer, privateKey, time.Minute).Generate(&token.Payload{Name: "testpvc"})
+		Expect(err).ToNot(HaveOccurred())
+		payload, err := app.tokenValidator.Validate(t)
 		Expect(err).ToNot(HaveOccurred())
-		Expect(app.tokenValidator).ToNot(BeNil())
+		Expect(payload.Name).To(Equal("testpvc"))
 	})
 
 	It("Get upload server client", func() {
@@ -184,6 +223,7 @@ func setupProxyTests(handler http.HandlerFunc) (*uploadProxyApp, *httptest.Serve
 	objects = append(objects, pvc)
 	app := createApp()
 	app.client = k8sfake.NewSimpleClientset(objects...)
+	app.namespace = "cdi"
 	app.tokenValidator = &validateSuccess{}
 	app.urlResolver = urlResolver
 	app.clientCreator = &fakeClientCreator{client: server.Client()}
@@ -241,6 +281,213 @@ var _ = Describe("submit request and check status", func() {
 		Entry("Test OK", http.StatusOK),
 		Entry("Test error", http.StatusInternalServerError),
 	)
+	DescribeTable("Test proxy resumable and range uploads", func(method, path string) {
+		var proxiedMethod, proxiedPath string
+		app, server := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxiedMethod, proxiedPath = r.Method, r.URL.Path
+			w.Header().Set(common.UploadOffsetHeader, "4")
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		app.uploadPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+		app.urlResolver = func(_, _, uploadPath string) string {
+			return server.URL + uploadPath
+		}
+
+		req, err := http.NewRequest(method, path, strings.NewReader("data"))
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer valid")
+		req.Header.Set("Origin", "foo.bar.com")
+		rr := httptest.NewRecorder()
+		app.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusNoContent))
+		Expect(rr.Header().Get(common.UploadOffsetHeader)).To(Equal("4"))
+		Expect(rr.Header().Get("Access-Control-Expose-Headers")).To(ContainSubstring(common.UploadOffsetHeader))
+		Expect(proxiedMethod).To(Equal(method))
+		Expect(proxiedPath).To(Equal(path))
+	},
+		Entry("Test create session", http.MethodPost, common.UploadPathResumable),
+		Entry("Test query offset", http.MethodHead, common.UploadPathResumable+"/0123abcd"),
+		Entry("Test upload chunk", http.MethodPatch, common.UploadPathResumable+"/0123abcd"),
+		Entry("Test upload chunk with range", http.MethodPut, common.UploadPathResumable+"/0123abcd"),
+		Entry("Test delete session", http.MethodDelete, common.UploadPathResumable+"/0123abcd"),
+		Entry("Test upload range", http.MethodPut, common.UploadPathRange),
+	)
+	DescribeTable("Test proxy passes the checksum of the token", func(params map[string]string, clientChecksum, expectedChecksum string) {
+		var proxiedChecksum string
+		app, server := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxiedChecksum = r.Header.Get(common.UploadChecksumHeader)
+			w.WriteHeader(http.StatusOK)
+		}))
+		app.uploadPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+		app.urlResolver = func(_, _, uploadPath string) string {
+			return server.URL + uploadPath
+		}
+		app.tokenValidator = &validateSuccessWithParams{params: params}
+
+		req := newProxyRequest(common.UploadPathSync, "Bearer valid")
+		if clientChecksum != "" {
+			req.Header.Set(common.UploadChecksumHeader, clientChecksum)
+		}
+		submitRequestAndCheckStatus(req, http.StatusOK, app)
+		Expect(proxiedChecksum).To(Equal(expectedChecksum))
+	},
+		Entry("Test token with checksum", map[string]string{common.UploadChecksumParam: "sha256:abcd"}, "", "sha256:abcd"),
+		Entry("Test token with checksum overriding the client", map[string]string{common.UploadChecksumParam: "sha256:abcd"}, "md5:1234", "sha256:abcd"),
+		Entry("Test token without checksum", nil, "md5:1234", ""),
+	)
+	DescribeTable("Test proxy rejects archive uploads", func(path, expectedError string) {
+		app, _ := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("Archive upload should not be proxied")
+		}))
+		app.uploadPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+		pvc, err := app.client.CoreV1().PersistentVolumeClaims("default").Get(context.TODO(), "testpvc", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		pvc.Annotations["cdi.kubevirt.io/storage.contentType"] = string(cdiv1.DataVolumeArchive)
+		_, err = app.client.CoreV1().PersistentVolumeClaims("default").Update(context.TODO(), pvc, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		req := newProxyRequest(path, "Bearer valid")
+		submitRequestAndCheckStatusAndBody(req, http.StatusServiceUnavailable, regexp.MustCompile(expectedError), app)
+	},
+		Entry("Test resumable upload", common.UploadPathResumable, "resumable uploads of archives are not supported"),
+		Entry("Test range upload", common.UploadPathRange, "range uploads of archives are not supported"),
+	)
+	DescribeTable("Test proxy upload status", func(annotations map[string]string, expectedStatus *common.UploadStatus) {
+		var proxiedMethod, proxiedPath string
+		app, server := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxiedMethod, proxiedPath = r.Method, r.URL.Path
+			Expect(json.NewEncoder(w).Encode(&common.UploadStatus{
+				Phase:           common.UploadPhaseProcessing,
+				BytesReceived:   4,
+				ProcessingPhase: "Resize",
+			})).To(Succeed())
+		}))
+		app.urlResolver = func(_, _, uploadPath string) string {
+			return server.URL + uploadPath
+		}
+		pvc, err := app.client.CoreV1().PersistentVolumeClaims("default").Get(context.TODO(), "testpvc", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		for key, value := range annotations {
+			pvc.Annotations[key] = value
+		}
+		_, err = app.client.CoreV1().PersistentVolumeClaims("default").Update(context.TODO(), pvc, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		req, err := http.NewRequest(http.MethodGet, common.UploadPathStatus, nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer valid")
+		rr := httptest.NewRecorder()
+		app.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		status := &common.UploadStatus{}
+		Expect(json.Unmarshal(rr.Body.Bytes(), status)).To(Succeed())
+		Expect(status).To(Equal(expectedStatus))
+		if expectedStatus.Phase == common.UploadPhaseProcessing {
+			Expect(proxiedMethod).To(Equal(http.MethodGet))
+			Expect(proxiedPath).To(Equal(common.UploadPathStatus))
+		} else {
+			Expect(proxiedPath).To(BeEmpty())
+		}
+	},
+		Entry("Test upload server running", map[string]string{"cdi.kubevirt.io/storage.pod.restarts": "1"},
+			&common.UploadStatus{Phase: common.UploadPhaseProcessing, BytesReceived: 4, ProcessingPhase: "Resize", Restarts: 1}),
+		Entry("Test upload server not ready", map[string]string{"cdi.kubevirt.io/storage.pod.ready": "false"},
+			&common.UploadStatus{Phase: common.UploadPhasePending}),
+		Entry("Test upload succeeded", map[string]string{
+			"cdi.kubevirt.io/storage.pod.phase":       "Succeeded",
+			"cdi.kubevirt.io/storage.pod.ready":       "false",
+			"cdi.kubevirt.io/storage.upload.checksum": "sha256:abcd",
+		}, &common.UploadStatus{Phase: common.UploadPhaseSucceeded, Checksum: "sha256:abcd"}),
+		Entry("Test upload server failed", map[string]string{
+			"cdi.kubevirt.io/storage.pod.ready":                 "false",
+			"cdi.kubevirt.io/storage.pod.restarts":              "2",
+			"cdi.kubevirt.io/storage.condition.running":         "false",
+			"cdi.kubevirt.io/storage.condition.running.reason":  "Error",
+			"cdi.kubevirt.io/storage.condition.running.message": "Unable to convert source data to target format",
+		}, &common.UploadStatus{Phase: common.UploadPhaseFailed, Message: "Unable to convert source data to target format", Restarts: 2}),
+	)
+	DescribeTable("Test proxy token restrictions", func(validator *validateRestrictedSuccess, annotations, tokens map[string]string, statusCode int) {
+		app, _ := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		app.uploadPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+		app.tokenValidator = validator
+		pvc, err := app.client.CoreV1().PersistentVolumeClaims("default").Get(context.TODO(), "testpvc", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		for key, value := range annotations {
+			pvc.Annotations[key] = value
+		}
+		_, err = app.client.CoreV1().PersistentVolumeClaims("default").Update(context.TODO(), pvc, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		if tokens != nil {
+			cm := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: common.UploadTokensConfigMap, Namespace: "cdi"}, Data: tokens}
+			_, err = app.client.CoreV1().ConfigMaps("cdi").Create(context.TODO(), cm, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		req := newProxyRequest(common.UploadPathSync, "Bearer valid")
+		req.RemoteAddr = "192.0.2.1:1234"
+		submitRequestAndCheckStatus(req, statusCode, app)
+	},
+		Entry("Test unrestricted token", &validateRestrictedSuccess{}, nil, nil, http.StatusOK),
+		Entry("Test client in CIDR", &validateRestrictedSuccess{clientCIDRs: []string{"10.0.0.0/8", "192.0.2.0/24"}}, nil, nil, http.StatusOK),
+		Entry("Test client address", &validateRestrictedSuccess{clientCIDRs: []string{"192.0.2.1"}}, nil, nil, http.StatusOK),
+		Entry("Test client not in CIDR", &validateRestrictedSuccess{clientCIDRs: []string{"10.0.0.0/8"}}, nil, nil, http.StatusForbidden),
+		Entry("Test revoked token", &validateRestrictedSuccess{}, nil,
+			map[string]string{"other-id": "revoked", "token-id": "revoked"}, http.StatusUnauthorized),
+		Entry("Test other token revoked", &validateRestrictedSuccess{}, nil,
+			map[string]string{"other-id": "revoked"}, http.StatusOK),
+		Entry("Test token issued before revocation", &validateRestrictedSuccess{issuedAt: time.Now().Add(-time.Hour)},
+			map[string]string{"cdi.kubevirt.io/storage.upload.tokensNotBefore": time.Now().Format(time.RFC3339)}, nil, http.StatusUnauthorized),
+		Entry("Test token issued after revocation", &validateRestrictedSuccess{issuedAt: time.Now()},
+			map[string]string{"cdi.kubevirt.io/storage.upload.tokensNotBefore": time.Now().Add(-time.Hour).Format(time.RFC3339)}, nil, http.StatusOK),
+		Entry("Test single use token used by a previous version", &validateRestrictedSuccess{singleUse: true}, nil,
+			map[string]string{"token-id": fmt.Sprintf("used@%d", time.Now().Add(time.Hour).Unix())}, http.StatusUnauthorized),
+	)
+	It("Test proxy single use token", func() {
+		app, _ := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		app.uploadPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+		app.tokenValidator = &validateRestrictedSuccess{singleUse: true}
+
+		submitRequestAndCheckStatus(newProxyRequest(common.UploadPathSync, "Bearer valid"), http.StatusOK, app)
+		cms, err := app.client.CoreV1().ConfigMaps("cdi").List(context.TODO(), metav1.ListOptions{LabelSelector: common.UsedUploadTokenLabel})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cms.Items).To(HaveLen(1))
+		Expect(cms.Items[0].Name).To(HavePrefix(common.UsedUploadTokenPrefix))
+		Expect(cms.Items[0].Data).To(HaveKeyWithValue(common.UsedUploadTokenIDKey, "token-id"))
+		Expect(cms.Items[0].Data).To(HaveKey(common.UsedUploadTokenExpiryKey))
+		submitRequestAndCheckStatus(newProxyRequest(common.UploadPathSync, "Bearer valid"), http.StatusUnauthorized, app)
+
+		// Nothing is recorded in the shared config map
+		_, err = app.client.CoreV1().ConfigMaps("cdi").Get(context.TODO(), common.UploadTokensConfigMap, metav1.GetOptions{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
+		pvc, err := app.client.CoreV1().PersistentVolumeClaims("default").Get(context.TODO(), "testpvc", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.Annotations).To(HaveLen(2))
+	})
+	It("Test proxy upload status of a missing PVC", func() {
+		app, _ := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("Status of a missing PVC should not be proxied")
+		}))
+		Expect(app.client.CoreV1().PersistentVolumeClaims("default").Delete(context.TODO(), "testpvc", metav1.DeleteOptions{})).To(Succeed())
+
+		req, err := http.NewRequest(http.MethodGet, common.UploadPathStatus, nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer valid")
+		submitRequestAndCheckStatus(req, http.StatusNotFound, app)
+	})
+	It("Test proxy upload status with an invalid token", func() {
+		app := createApp()
+		app.tokenValidator = &validateFailure{}
+
+		req, err := http.NewRequest(http.MethodGet, common.UploadPathStatus, nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer valid")
+		submitRequestAndCheckStatus(req, http.StatusUnauthorized, app)
+	})
 	It("Invalid token", func() {
 		app := createApp()
 		app.tokenValidator = &validateFailure{}
@@ -281,6 +528,73 @@ var _ = Describe("submit request and check status", func() {
 		submitRequestAndCheckStatus(req, http.StatusOK, nil)
 	})
 
+	It("Test proxy export", func() {
+		var proxiedMethod, proxiedPath, proxiedQuery string
+		app, server := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxiedMethod, proxiedPath, proxiedQuery = r.Method, r.URL.Path, r.URL.RawQuery
+			w.Header().Set(common.ExportLengthHeader, "4")
+			_, err := w.Write([]byte("data"))
+			Expect(err).ToNot(HaveOccurred())
+		}))
+		app.tokenValidator = &validateExportSuccess{}
+		app.exportPossible = func(*v1.PersistentVolumeClaim) error { return nil }
+		app.exportURLResolver = func(_, _, exportPath string) string {
+			return server.URL + exportPath
+		}
+		pvc, err := app.client.CoreV1().PersistentVolumeClaims("default").Get(context.TODO(), "testpvc", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		pvc.Annotations["cdi.kubevirt.io/storage.export.pod.ready"] = "true"
+		_, err = app.client.CoreV1().PersistentVolumeClaims("default").Update(context.TODO(), pvc, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		req, err := http.NewRequest(http.MethodGet, common.ExportPath+"?format=raw&sparse=true", nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer valid")
+		req.Header.Set("Origin", "foo.bar.com")
+		rr := httptest.NewRecorder()
+		app.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Body.String()).To(Equal("data"))
+		Expect(rr.Header().Get("Access-Control-Expose-Headers")).To(ContainSubstring(common.ExportLengthHeader))
+		Expect(proxiedMethod).To(Equal(http.MethodGet))
+		Expect(proxiedPath).To(Equal(common.ExportPath))
+		Expect(proxiedQuery).To(Equal("format=raw&sparse=true"))
+	})
+	It("Test proxy export with an upload token", func() {
+		app, _ := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("Export with an upload token should not be proxied")
+		}))
+
+		req, err := http.NewRequest(http.MethodGet, common.ExportPath, nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer valid")
+		submitRequestAndCheckStatus(req, http.StatusBadRequest, app)
+	})
+	It("Test proxy export not possible", func() {
+		app, _ := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("Export that is not possible should not be proxied")
+		}))
+		app.tokenValidator = &validateExportSuccess{}
+		app.exportPossible = func(*v1.PersistentVolumeClaim) error { return fmt.Errorf("PVC testpvc is not an export source") }
+
+		req, err := http.NewRequest(http.MethodGet, common.ExportPath, nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer valid")
+		submitRequestAndCheckStatusAndBody(req, http.StatusServiceUnavailable, regexp.MustCompile("not an export source"), app)
+	})
+	It("Test proxy export of a missing PVC", func() {
+		app, _ := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("Export of a missing PVC should not be proxied")
+		}))
+		app.tokenValidator = &validateExportSuccess{}
+		Expect(app.client.CoreV1().PersistentVolumeClaims("default").Delete(context.TODO(), "testpvc", metav1.DeleteOptions{})).To(Succeed())
+
+		req, err := http.NewRequest(http.MethodGet, common.ExportPath, nil)
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer valid")
+		submitRequestAndCheckStatusAndBody(req, http.StatusServiceUnavailable, regexp.MustCompile("rejecting Export Request for PVC testpvc"), app)
+	})
+
 	It("Upload server is unavailable", func() {
 		app, server := setupProxyTests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			panic("Server is down, this should not be called")
@@ -0,0 +1,315 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2026 The CDI Authors.
+ *
+ */
+
+package uploadserver
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/image"
+	"kubevirt.io/containerized-data-importer/pkg/util/sparse"
+)
+
+// An export server reads the image from the PVC and sends it to the client, it runs until its deadline:
+//   - GET common.ExportPath sends the image, the common.ExportFormatParam query parameter selects the format of the
+//     image: raw (default), gzip or qcow2. Raw and qcow2 exports support Range requests.
+//   - The qcow2 image is converted once in the scratch space and kept for the next exports, until the size or the
+//     modification time of the source changes. A HEAD request doesn't start the conversion.
+//   - The common.ExportSparseParam query parameter of raw exports only sends the data of the image, as a
+//     multipart/byteranges response with a part for each range of data. The size of the image is in the
+//     common.ExportLengthHeader header, the rest of the image is zero.
+
+const (
+	// exportMaxPartSize is the maximum size of a part of a sparse export
+	exportMaxPartSize = 4 * 1024 * 1024
+
+	qcow2ContentType = "application/x-qemu-disk"
+)
+
+// may be overridden in tests
+var exportConvertFunc = image.ConvertToQcow2
+
+func writeExportError(w http.ResponseWriter, status int, err error) {
+	klog.Errorf("Export failed: %v", err)
+	w.WriteHeader(status)
+	if _, writeErr := fmt.Fprintf(w, "Export failed: %s", err.Error()); writeErr != nil {
+		klog.Errorf("failed to send response; %v", writeErr)
+	}
+}
+
+// parseExportParams returns the format of the export and whether it is sparse
+func parseExportParams(r *http.Request) (string, bool, error) {
+	query := r.URL.Query()
+	format := query.Get(common.ExportFormatParam)
+	switch format {
+	case "":
+		format = common.ExportFormatRaw
+	case common.ExportFormatRaw, common.ExportFormatGzip, common.ExportFormatQcow2:
+	default:
+		return "", false, errors.Errorf("unsupported export format %q", format)
+	}
+
+	sparseExport := false
+	if value := query.Get(common.ExportSparseParam); value != "" {
+		var err error
+		if sparseExport, err = strconv.ParseBool(value); err != nil {
+			return "", false, errors.Errorf("invalid %s parameter %q", common.ExportSparseParam, value)
+		}
+	}
+	if sparseExport && format != common.ExportFormatRaw {
+		return "", false, errors.Errorf("sparse exports are only supported for the %s format", common.ExportFormatRaw)
+	}
+	return format, sparseExport, nil
+}
+
+// openExportSource opens the image of the PVC and returns its size, the image is a file or a block device
+func openExportSource(path string) (*os.File, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "unable to open %q", path)
+	}
+	size, err := f.Seek(0, io.SeekEnd)
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		return nil, 0, errors.Wrapf(err, "unable to get the size of %q", path)
+	}
+	return f, size, nil
+}
+
+func (app *uploadServerApp) startExport() {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	app.exports++
+}
+
+func (app *uploadServerApp) endExport() {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	app.exports--
+}
+
+func (app *uploadServerApp) exportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if !app.validateClient(w, r) {
+		return
+	}
+	format, sparseExport, err := parseExportParams(r)
+	if err != nil {
+		writeExportError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	app.startExport()
+	defer app.endExport()
+
+	source, size, err := openExportSource(app.config.Destination)
+	if err != nil {
+		writeExportError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer source.Close()
+
+	klog.Infof("Exporting %s as %s, sparse: %t", app.config.Destination, format, sparseExport)
+	switch {
+	case format == common.ExportFormatQcow2:
+		app.exportQcow2(w, r, source, size)
+		return
+	case format == common.ExportFormatGzip:
+		err = exportGzip(w, r, source)
+	case sparseExport:
+		err = exportSparse(w, r, source, size)
+	default:
+		w.Header().Set("Content-Type", "application/octet-stream")
+		http.ServeContent(w, r, "", time.Time{}, source)
+	}
+	if err != nil {
+		// The response was already started, the client gets a truncated response
+		klog.Errorf("Export of %s failed: %v", app.config.Destination, err)
+		return
+	}
+	klog.Infof("Exported %s", app.config.Destination)
+}
+
+func exportGzip(w http.ResponseWriter, r *http.Request, source io.Reader) error {
+	w.Header().Set("Content-Type", "application/gzip")
+	if r.Method == http.MethodHead {
+		return nil
+	}
+	gz := gzip.NewWriter(w)
+	if _, err := io.Copy(gz, source); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// qcow2Export is the qcow2 image converted from a generation of the source, identified by its size and modification
+// time. The source is mounted read only by the export server.
+type qcow2Export struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// openQcow2Image opens the qcow2 image converted from the source in the scratch space, and returns the modification
+// time of the source it was converted from. The source is converted if it changed since the last conversion and
+// convert is set, nil is returned otherwise. Conversions are serialized, exports wait for the running one, but a
+// request which doesn't convert doesn't wait and gets nil.
+func (app *uploadServerApp) openQcow2Image(source *os.File, size int64, convert bool) (*os.File, time.Time, error) {
+	info, err := source.Stat()
+	if err != nil {
+		return nil, time.Time{}, errors.Wrapf(err, "unable to stat %q", app.config.Destination)
+	}
+	if !convert {
+		if !app.qcow2Mutex.TryLock() {
+			return nil, time.Time{}, nil
+		}
+	} else {
+		app.qcow2Mutex.Lock()
+	}
+	defer app.qcow2Mutex.Unlock()
+
+	if q := app.qcow2; q == nil || q.size != size || !q.modTime.Equal(info.ModTime()) {
+		if !convert {
+			return nil, time.Time{}, nil
+		}
+		if q != nil {
+			// exports still sending the previous image keep it open
+			os.Remove(q.path)
+			app.qcow2 = nil
+		}
+		tmp, err := os.CreateTemp(app.config.ScratchDir, "export-*.qcow2")
+		if err != nil {
+			return nil, time.Time{}, errors.Wrap(err, "unable to create the qcow2 image")
+		}
+		tmp.Close()
+		klog.Infof("Converting %s to qcow2", app.config.Destination)
+		if err := exportConvertFunc(app.config.Destination, tmp.Name()); err != nil {
+			os.Remove(tmp.Name())
+			return nil, time.Time{}, err
+		}
+		app.qcow2 = &qcow2Export{path: tmp.Name(), size: size, modTime: info.ModTime()}
+	}
+	f, err := os.Open(app.qcow2.path)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "unable to open the qcow2 image")
+	}
+	return f, app.qcow2.modTime, nil
+}
+
+// exportQcow2 sends the image converted to qcow2 in the scratch space. A HEAD request doesn't convert the image, it
+// is answered without the length of the image when it is not converted yet.
+func (app *uploadServerApp) exportQcow2(w http.ResponseWriter, r *http.Request, source *os.File, size int64) {
+	if app.config.ScratchDir == "" {
+		writeExportError(w, http.StatusBadRequest, errors.New("qcow2 exports require scratch space"))
+		return
+	}
+	f, modTime, err := app.openQcow2Image(source, size, r.Method != http.MethodHead)
+	if err != nil {
+		writeExportError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", qcow2ContentType)
+	if f == nil {
+		w.Header().Set("Accept-Ranges", "bytes")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	defer f.Close()
+
+	http.ServeContent(w, r, "", modTime, f)
+	klog.Infof("Exported %s as qcow2", app.config.Destination)
+}
+
+// sparseWriter writes the data of the image as the parts of a multipart/byteranges response, merging contiguous
+// blocks of data in parts of up to exportMaxPartSize
+type sparseWriter struct {
+	mw    *multipart.Writer
+	size  int64
+	start int64
+	data  []byte
+}
+
+func (s *sparseWriter) write(offset int64, block []byte) error {
+	if len(s.data) > 0 && (s.start+int64(len(s.data)) != offset || len(s.data)+len(block) > exportMaxPartSize) {
+		if err := s.flush(); err != nil {
+			return err
+		}
+	}
+	if len(s.data) == 0 {
+		s.start = offset
+	}
+	s.data = append(s.data, block...)
+	return nil
+}
+
+func (s *sparseWriter) flush() error {
+	if len(s.data) == 0 {
+		return nil
+	}
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", "application/octet-stream")
+	header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", s.start, s.start+int64(len(s.data))-1, s.size))
+	part, err := s.mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(s.data); err != nil {
+		return err
+	}
+	s.data = s.data[:0]
+	return nil
+}
+
+// exportSparse sends the data of the image, skipping the holes of the source and the blocks of zeros
+func exportSparse(w http.ResponseWriter, r *http.Request, source *os.File, size int64) error {
+	mw := multipart.NewWriter(w)
+	w.Header().Set("Content-Type", "multipart/byteranges; boundary="+mw.Boundary())
+	w.Header().Set(common.ExportLengthHeader, strconv.FormatInt(size, 10))
+	if r.Method == http.MethodHead {
+		return nil
+	}
+
+	sw := &sparseWriter{mw: mw, size: size, data: make([]byte, 0, exportMaxPartSize)}
+	if err := sparse.Scan(source, source, size, sw.write); err != nil {
+		return errors.Wrap(err, "unable to read the image")
+	}
+	if err := sw.flush(); err != nil {
+		return err
+	}
+	return mw.Close()
+}
@@ -0,0 +1,260 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2026 The CDI Authors.
+ *
+ */
+
+package uploadserver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/util/sparse"
+	cryptowatch "kubevirt.io/containerized-data-importer/pkg/util/tls-crypto-watch"
+)
+
+const exportImageSize = 1024 * 1024
+
+func newExportServer(source, scratchDir string) *uploadServerApp {
+	config := &Config{
+		Insecure:     true,
+		BindAddress:  "127.0.0.1",
+		Destination:  source,
+		Export:       true,
+		ScratchDir:   scratchDir,
+		CryptoConfig: *cryptowatch.DefaultCryptoConfig(),
+	}
+	return NewUploadServer(config).(*uploadServerApp)
+}
+
+func sendExportRequest(server *uploadServerApp, method, query string, headers map[string]string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(method, common.ExportPath+query, nil)
+	Expect(err).ToNot(HaveOccurred())
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	rr := httptest.NewRecorder()
+	server.ServeHTTP(rr, req)
+	return rr
+}
+
+// readSparseExport rebuilds the image from the parts of a sparse export, it returns the image and the number of parts
+func readSparseExport(rr *httptest.ResponseRecorder) ([]byte, int) {
+	mediaType, params, err := mime.ParseMediaType(rr.Header().Get("Content-Type"))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(mediaType).To(Equal("multipart/byteranges"))
+	size, err := strconv.ParseInt(rr.Header().Get(common.ExportLengthHeader), 10, 64)
+	Expect(err).ToNot(HaveOccurred())
+
+	image := make([]byte, size)
+	parts := 0
+	mr := multipart.NewReader(rr.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		Expect(err).ToNot(HaveOccurred())
+		var start, end, total int64
+		_, err = fmt.Sscanf(part.Header.Get("Content-Range"), "bytes %d-%d/%d", &start, &end, &total)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(total).To(Equal(size))
+		data, err := io.ReadAll(part)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data).To(HaveLen(int(end - start + 1)))
+		copy(image[start:], data)
+		parts++
+	}
+	return image, parts
+}
+
+var _ = Describe("Export", func() {
+	var (
+		source string
+		data   []byte
+	)
+
+	BeforeEach(func() {
+		source = filepath.Join(GinkgoT().TempDir(), "disk.img")
+		data = make([]byte, exportImageSize)
+		copy(data, "data at the start")
+		copy(data[exportImageSize/2:], "data in the middle")
+		Expect(os.WriteFile(source, data, 0600)).To(Succeed())
+	})
+
+	It("should export the raw image", func() {
+		rr := sendExportRequest(newExportServer(source, ""), http.MethodGet, "", nil)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Header().Get("Content-Length")).To(Equal(strconv.Itoa(exportImageSize)))
+		Expect(rr.Body.Bytes()).To(Equal(data))
+	})
+
+	It("should export a range of the raw image", func() {
+		rr := sendExportRequest(newExportServer(source, ""), http.MethodGet, "?format=raw", map[string]string{"Range": "bytes=5-11"})
+		Expect(rr.Code).To(Equal(http.StatusPartialContent))
+		Expect(rr.Body.String()).To(Equal("at the "))
+	})
+
+	It("should export the gzip compressed image", func() {
+		rr := sendExportRequest(newExportServer(source, ""), http.MethodGet, "?format=gzip", nil)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Header().Get("Content-Type")).To(Equal("application/gzip"))
+		gz, err := gzip.NewReader(rr.Body)
+		Expect(err).ToNot(HaveOccurred())
+		exported, err := io.ReadAll(gz)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exported).To(Equal(data))
+	})
+
+	Context("qcow2", func() {
+		var (
+			scratchDir  string
+			conversions int
+		)
+
+		BeforeEach(func() {
+			scratchDir = GinkgoT().TempDir()
+			conversions = 0
+			origConvertFunc := exportConvertFunc
+			DeferCleanup(func() { exportConvertFunc = origConvertFunc })
+			exportConvertFunc = func(src, dest string) error {
+				Expect(src).To(Equal(source))
+				Expect(filepath.Dir(dest)).To(Equal(scratchDir))
+				conversions++
+				return os.WriteFile(dest, []byte(fmt.Sprintf("qcow2 image %d", conversions)), 0600)
+			}
+		})
+
+		It("should export the qcow2 image converted in the scratch space", func() {
+			rr := sendExportRequest(newExportServer(source, scratchDir), http.MethodGet, "?format=qcow2", nil)
+			Expect(rr.Code).To(Equal(http.StatusOK))
+			Expect(rr.Header().Get("Content-Type")).To(Equal(qcow2ContentType))
+			Expect(rr.Body.String()).To(Equal("qcow2 image 1"))
+			Expect(os.ReadDir(scratchDir)).To(HaveLen(1))
+		})
+
+		It("should convert the image once and serve the next requests from it", func() {
+			server := newExportServer(source, scratchDir)
+			rr := sendExportRequest(server, http.MethodGet, "?format=qcow2", nil)
+			Expect(rr.Body.String()).To(Equal("qcow2 image 1"))
+
+			rr = sendExportRequest(server, http.MethodGet, "?format=qcow2", map[string]string{"Range": "bytes=6-10"})
+			Expect(rr.Code).To(Equal(http.StatusPartialContent))
+			Expect(rr.Body.String()).To(Equal("image"))
+
+			rr = sendExportRequest(server, http.MethodHead, "?format=qcow2", nil)
+			Expect(rr.Code).To(Equal(http.StatusOK))
+			Expect(rr.Header().Get("Content-Length")).To(Equal("13"))
+			Expect(conversions).To(Equal(1))
+		})
+
+		It("should convert the image again when the source changed", func() {
+			server := newExportServer(source, scratchDir)
+			rr := sendExportRequest(server, http.MethodGet, "?format=qcow2", nil)
+			Expect(rr.Body.String()).To(Equal("qcow2 image 1"))
+
+			Expect(os.WriteFile(source, append(data, data...), 0600)).To(Succeed())
+			rr = sendExportRequest(server, http.MethodGet, "?format=qcow2", nil)
+			Expect(rr.Body.String()).To(Equal("qcow2 image 2"))
+			Expect(os.ReadDir(scratchDir)).To(HaveLen(1))
+		})
+
+		It("should answer HEAD requests without converting the image", func() {
+			rr := sendExportRequest(newExportServer(source, scratchDir), http.MethodHead, "?format=qcow2", nil)
+			Expect(rr.Code).To(Equal(http.StatusOK))
+			Expect(rr.Header().Get("Content-Type")).To(Equal(qcow2ContentType))
+			Expect(rr.Header().Get("Content-Length")).To(BeEmpty())
+			Expect(conversions).To(BeZero())
+			Expect(os.ReadDir(scratchDir)).To(BeEmpty())
+		})
+	})
+
+	It("should not export a qcow2 image without scratch space", func() {
+		rr := sendExportRequest(newExportServer(source, ""), http.MethodGet, "?format=qcow2", nil)
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should only send the data of a sparse export", func() {
+		rr := sendExportRequest(newExportServer(source, ""), http.MethodGet, "?sparse=true", nil)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		image, parts := readSparseExport(rr)
+		Expect(image).To(Equal(data))
+		Expect(parts).To(Equal(2))
+	})
+
+	It("should split the data of a sparse export in parts", func() {
+		data = bytes.Repeat([]byte{1}, exportMaxPartSize+sparse.BlockSize)
+		Expect(os.WriteFile(source, data, 0600)).To(Succeed())
+
+		rr := sendExportRequest(newExportServer(source, ""), http.MethodGet, "?sparse=true", nil)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		image, parts := readSparseExport(rr)
+		Expect(image).To(Equal(data))
+		Expect(parts).To(Equal(2))
+	})
+
+	It("should send the size of an empty sparse export", func() {
+		Expect(os.Truncate(source, 0)).To(Succeed())
+		Expect(os.Truncate(source, exportImageSize)).To(Succeed())
+
+		rr := sendExportRequest(newExportServer(source, ""), http.MethodGet, "?sparse=true", nil)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		image, parts := readSparseExport(rr)
+		Expect(image).To(Equal(make([]byte, exportImageSize)))
+		Expect(parts).To(BeZero())
+	})
+
+	DescribeTable("should reject invalid parameters", func(query string) {
+		rr := sendExportRequest(newExportServer(source, ""), http.MethodGet, query, nil)
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+	},
+		Entry("unknown format", "?format=vmdk"),
+		Entry("invalid sparse", "?sparse=maybe"),
+		Entry("sparse gzip", "?format=gzip&sparse=true"),
+	)
+
+	It("should fail if the source does not exist", func() {
+		rr := sendExportRequest(newExportServer(filepath.Join(GinkgoT().TempDir(), "missing"), ""), http.MethodGet, "", nil)
+		Expect(rr.Code).To(Equal(http.StatusInternalServerError))
+	})
+
+	It("should only support GET and HEAD", func() {
+		rr := sendExportRequest(newExportServer(source, ""), http.MethodPost, "", nil)
+		Expect(rr.Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+
+	It("should not accept uploads", func() {
+		req, err := http.NewRequest(http.MethodPost, common.UploadPathSync, bytes.NewReader(data))
+		Expect(err).ToNot(HaveOccurred())
+		rr := httptest.NewRecorder()
+		newExportServer(source, "").ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusNotFound))
+	})
+})
@@ -0,0 +1,86 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uploadserver
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"os"
+
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/importer"
+	"kubevirt.io/containerized-data-importer/pkg/util/sparse"
+)
+
+// may be overridden in tests
+var isBlockDevice = importer.IsDevice
+
+// hashesHandler sends the hashes of the blocks of the existing block device of the target, so that a delta clone only
+// sends the blocks which differ. No upload may start while the hashes are computed.
+func (app *uploadServerApp) hashesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if !app.validateClient(w, r) {
+		return
+	}
+
+	isDevice, err := isBlockDevice(app.config.Destination)
+	if err != nil || !isDevice {
+		// The client falls back to sending all the data
+		klog.Infof("Not sending hashes, %s is not a block device: %v", app.config.Destination, err)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if !app.startUpload(w) {
+		return
+	}
+	defer app.endUpload()
+
+	device, err := os.Open(app.config.Destination)
+	if err != nil {
+		klog.Errorf("Unable to open %s: %v", app.config.Destination, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	defer device.Close()
+	size, err := device.Seek(0, io.SeekEnd)
+	if err == nil {
+		_, err = device.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		klog.Errorf("Unable to get the size of %s: %v", app.config.Destination, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	klog.Infof("Sending the hashes of the %d bytes of %s", size, app.config.Destination)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	bw := bufio.NewWriter(w)
+	if err := sparse.WriteHashes(bw, bufio.NewReaderSize(device, sparse.HashBlockSize), size); err != nil {
+		// The status was already sent, the client fails reading the truncated hashes
+		klog.Errorf("Unable to send the hashes of %s: %v", app.config.Destination, err)
+		return
+	}
+	if err := bw.Flush(); err != nil {
+		klog.Errorf("hashesHandler: failed to send response; %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uploadserver
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/importer"
+	"kubevirt.io/containerized-data-importer/pkg/util/sparse"
+)
+
+var _ = Describe("Upload hashes", func() {
+	var (
+		server *uploadServerApp
+		image  []byte
+	)
+
+	BeforeEach(func() {
+		image = bytes.Repeat([]byte{1, 2, 3}, sparse.HashBlockSize)
+		server = newServer()
+		server.config.Destination = filepath.Join(GinkgoT().TempDir(), "disk.img")
+		Expect(os.WriteFile(server.config.Destination, image, 0600)).To(Succeed())
+
+		isBlockDevice = func(string) (bool, error) { return true, nil }
+		DeferCleanup(func() {
+			isBlockDevice = importer.IsDevice
+		})
+	})
+
+	getHashes := func() *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodGet, common.UploadPathHashes, nil)
+		Expect(err).ToNot(HaveOccurred())
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		return rr
+	}
+
+	It("should send the hashes of the blocks of the target", func() {
+		rr := getHashes()
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		hashes, err := sparse.ReadHashes(rr.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hashes.Size()).To(Equal(int64(len(image))))
+		for offset := int64(0); offset < int64(len(image)); offset += sparse.HashBlockSize {
+			Expect(hashes.Matches(offset, image[offset:min(offset+sparse.HashBlockSize, int64(len(image)))])).To(BeTrue())
+		}
+		Expect(server.uploading).To(BeFalse())
+	})
+
+	It("should not send the hashes of a target which is not a block device", func() {
+		isBlockDevice = importer.IsDevice
+		Expect(getHashes().Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should not send the hashes during an upload", func() {
+		server.uploading = true
+		Expect(getHashes().Code).To(Equal(http.StatusServiceUnavailable))
+	})
+
+	It("should only allow GET", func() {
+		req, err := http.NewRequest(http.MethodPost, common.UploadPathHashes, nil)
+		Expect(err).ToNot(HaveOccurred())
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+})
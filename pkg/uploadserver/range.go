@@ -0,0 +1,322 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2026 The CDI Authors.
+ *
+ */
+
+package uploadserver
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/image"
+	"kubevirt.io/containerized-data-importer/pkg/importer"
+)
+
+// A range upload writes a raw image directly to the target, the client sends the ranges of the image concurrently:
+//   - PUT common.UploadPathRange with a Content-Range header writes a range, the size of the image must be given in
+//     the header and be the same for all the ranges.
+//   - The target is processed once all of the image has been written, the request writing the last range returns
+//     after the processing.
+//
+// The ranges are kept in memory, all of them have to be sent again if the upload server restarts.
+
+// may be overridden in tests
+var rangeProcessorFunc = newRangeUploadProcessor
+
+// byteRange is the range [start, end) of the image
+type byteRange struct {
+	start, end int64
+}
+
+func (r byteRange) overlaps(other byteRange) bool {
+	return r.start < other.end && other.start < r.end
+}
+
+// rangeUpload is the state of a range upload
+type rangeUpload struct {
+	// total is the size of the image
+	total int64
+	// written are the ranges written and synced to the target, sorted and merged
+	written []byteRange
+	// inflight are the ranges being written
+	inflight []byteRange
+}
+
+// addWritten adds the range to the written ranges, merging it with the ranges it overlaps or touches
+func (u *rangeUpload) addWritten(r byteRange) {
+	written := make([]byteRange, 0, len(u.written)+1)
+	for _, w := range u.written {
+		if w.end < r.start || r.end < w.start {
+			written = append(written, w)
+			continue
+		}
+		r.start = min(r.start, w.start)
+		r.end = max(r.end, w.end)
+	}
+	written = append(written, r)
+	sort.Slice(written, func(i, j int) bool { return written[i].start < written[j].start })
+	u.written = written
+}
+
+func (u *rangeUpload) removeInflight(r byteRange) {
+	for i, inflight := range u.inflight {
+		if inflight == r {
+			u.inflight = append(u.inflight[:i], u.inflight[i+1:]...)
+			return
+		}
+	}
+}
+
+func (u *rangeUpload) overlapsInflight(r byteRange) bool {
+	for _, inflight := range u.inflight {
+		if inflight.overlaps(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// received returns the number of bytes of the image written to the target
+func (u *rangeUpload) received() int64 {
+	var received int64
+	for _, w := range u.written {
+		received += w.end - w.start
+	}
+	return received
+}
+
+func (u *rangeUpload) complete() bool {
+	return len(u.written) == 1 && u.written[0] == byteRange{0, u.total}
+}
+
+func writeRangeError(w http.ResponseWriter, status int, err error) {
+	klog.Errorf("Range upload failed: %v", err)
+	w.WriteHeader(status)
+	if _, writeErr := fmt.Fprintf(w, "Range upload failed: %s", err.Error()); writeErr != nil {
+		klog.Errorf("failed to send response; %v", writeErr)
+	}
+}
+
+// prepareRangeTarget checks that the image fits in the target, and creates an empty sparse file of the size of the
+// image for filesystem targets
+func prepareRangeTarget(dest string, total int64) (int, error) {
+	size, err := importer.GetAvailableSpaceBlock(dest)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if size >= 0 {
+		if total > size {
+			return http.StatusBadRequest, errors.Errorf("the image size %d is larger than the block device size %d", total, size)
+		}
+		return 0, nil
+	}
+
+	available, err := importer.GetAvailableSpace(filepath.Dir(dest))
+	if err != nil {
+		return http.StatusInternalServerError, errors.Wrap(err, "unable to get the available space of the target")
+	}
+	if total > available {
+		return http.StatusBadRequest, errors.Errorf("the image size %d is larger than the available storage %d", total, available)
+	}
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return http.StatusInternalServerError, errors.Wrapf(err, "unable to create %q", dest)
+	}
+	defer f.Close()
+	// Drop the data left by a previous run of the upload server
+	if err := f.Truncate(0); err != nil {
+		return http.StatusInternalServerError, errors.Wrapf(err, "unable to truncate %q", dest)
+	}
+	if err := f.Truncate(total); err != nil {
+		return http.StatusInternalServerError, errors.Wrapf(err, "unable to truncate %q", dest)
+	}
+	return 0, nil
+}
+
+// checkRawImage returns an error if the beginning of the image is the header of a format which is not raw
+func checkRawImage(r *bufio.Reader) error {
+	hdr, err := r.Peek(image.MaxExpectedHdrSize)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	buf := make([]byte, image.MaxExpectedHdrSize)
+	copy(buf, hdr)
+	for _, knownHdr := range image.CopyKnownHdrs() {
+		if knownHdr.Match(buf) {
+			return errors.Errorf("range uploads only support raw images, got a %s image", knownHdr.Format)
+		}
+	}
+	return nil
+}
+
+// startRange adds the range to the ranges being written, starting the range upload with the first range
+func (app *uploadServerApp) startRange(w http.ResponseWriter, r byteRange, total int64) (*rangeUpload, bool) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	if app.processing || (app.uploading && app.rangeUpload == nil) {
+		klog.Warning("Got range upload request during an upload")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return nil, false
+	}
+	if app.done {
+		klog.Warning("Got range upload request after already done")
+		w.WriteHeader(http.StatusConflict)
+		return nil, false
+	}
+	if app.session != nil {
+		writeRangeError(w, http.StatusConflict, errors.Errorf("the resumable upload session %s exists", app.session.ID))
+		return nil, false
+	}
+
+	if app.rangeUpload == nil {
+		if status, err := prepareRangeTarget(app.config.Destination, total); err != nil {
+			writeRangeError(w, status, err)
+			return nil, false
+		}
+		app.rangeUpload = &rangeUpload{total: total}
+		klog.Infof("Started range upload of %d bytes to %s", total, app.config.Destination)
+	}
+	upload := app.rangeUpload
+	if total != upload.total {
+		writeRangeError(w, http.StatusBadRequest, errors.Errorf("the image size %d does not match the size of the upload %d", total, upload.total))
+		return nil, false
+	}
+	if upload.overlapsInflight(r) {
+		writeRangeError(w, http.StatusConflict, errors.Errorf("the range %d-%d overlaps a range being written", r.start, r.end-1))
+		return nil, false
+	}
+
+	upload.inflight = append(upload.inflight, r)
+	app.uploading = true
+	return upload, true
+}
+
+// endRange removes the range from the ranges being written, it returns true if the image was completely written and
+// has to be processed
+func (app *uploadServerApp) endRange(upload *rangeUpload, r byteRange, written bool) bool {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	upload.removeInflight(r)
+	if written {
+		upload.addWritten(r)
+		klog.V(3).Infof("Wrote range %d-%d, %d of %d bytes written", r.start, r.end-1, upload.received(), upload.total)
+	}
+	if len(upload.inflight) > 0 {
+		return false
+	}
+	app.uploading = false
+	if !upload.complete() {
+		return false
+	}
+	app.processing = true
+	return true
+}
+
+func (app *uploadServerApp) rangeUploadHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if !app.validateClient(w, r) {
+		return
+	}
+	start, size, total, err := parseChunkRange(r)
+	if err != nil {
+		writeRangeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if total < 0 {
+		writeRangeError(w, http.StatusBadRequest, errors.New("the size of the image is required in the Content-Range header"))
+		return
+	}
+	// The ranges are not read in order, so the checksum of the image can not be calculated while it is written
+	if expected, _ := expectedChecksum(r); expected != "" {
+		writeRangeError(w, http.StatusBadRequest, errors.New("checksum validation is not supported for range uploads"))
+		return
+	}
+
+	body := bufio.NewReaderSize(io.LimitReader(r.Body, size), image.MaxExpectedHdrSize)
+	if start == 0 {
+		if err := checkRawImage(body); err != nil {
+			writeRangeError(w, http.StatusUnsupportedMediaType, err)
+			return
+		}
+	}
+
+	rng := byteRange{start: start, end: start + size}
+	upload, ok := app.startRange(w, rng, total)
+	if !ok {
+		return
+	}
+
+	n, _, writeErr := importer.StreamRangeToFile(body, app.config.Destination, start, app.config.Preallocation)
+	if writeErr == nil && n != size {
+		writeErr = errors.Errorf("the range %d-%d ended after %d bytes", start, rng.end-1, n)
+	}
+	if !app.endRange(upload, rng, writeErr == nil) {
+		if writeErr != nil {
+			writeRangeError(w, http.StatusInternalServerError, writeErr)
+			return
+		}
+		w.Header().Set(common.UploadLengthHeader, strconv.FormatInt(total, 10))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	klog.Infof("All %d bytes written, processing %s", total, app.config.Destination)
+	progress := app.startProgress(total)
+	preallocationApplied, err := rangeProcessorFunc(app.config.Destination, app.config.ImageSize, app.config.FilesystemOverhead, app.config.Preallocation, progress)
+
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	app.processing = false
+
+	if err != nil {
+		// The ranges are kept, writing any range again processes the target again
+		progress.err = err
+		handleStreamError(w, err)
+		return
+	}
+
+	app.done = true
+	app.preallocationApplied = preallocationApplied
+	close(app.doneChan)
+
+	klog.Infof("Wrote data to %s", app.config.Destination)
+}
+
+func newRangeUploadProcessor(dest, imageSize string, filesystemOverhead float64, preallocation bool, progress *uploadProgress) (bool, error) {
+	rds := importer.NewRangeUploadDataSource(dest)
+	processor := importer.NewDataProcessor(rds, dest, common.ImporterVolumePath, common.ScratchDataDir, imageSize, filesystemOverhead, preallocation, "")
+	processor.SetPhaseObserver(progress.setPhase)
+	err := processor.ProcessData()
+	return processor.PreallocationApplied(), err
+}
@@ -0,0 +1,211 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2026 The CDI Authors.
+ *
+ */
+
+package uploadserver
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+)
+
+func putRange(server *uploadServerApp, data []byte, start, total int) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(http.MethodPut, common.UploadPathRange, bytes.NewReader(data))
+	Expect(err).ToNot(HaveOccurred())
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, start+len(data)-1, total))
+	rr := httptest.NewRecorder()
+	server.ServeHTTP(rr, req)
+	return rr
+}
+
+var _ = Describe("Range upload", func() {
+	const rangeSize = 64 * 1024
+
+	var (
+		server       *uploadServerApp
+		image        []byte
+		processed    int
+		processorErr error
+	)
+
+	BeforeEach(func() {
+		server = newServer()
+		server.config.Destination = filepath.Join(GinkgoT().TempDir(), "disk.img")
+		image = nil
+		for i := 0; i < 4; i++ {
+			image = append(image, bytes.Repeat([]byte{byte(i)}, rangeSize)...)
+		}
+		processed = 0
+		processorErr = nil
+
+		origProcessorFunc := rangeProcessorFunc
+		rangeProcessorFunc = func(dest, imageSize string, filesystemOverhead float64, preallocation bool, progress *uploadProgress) (bool, error) {
+			processed++
+			return preallocation, processorErr
+		}
+		DeferCleanup(func() {
+			rangeProcessorFunc = origProcessorFunc
+		})
+	})
+
+	imageRange := func(i int) []byte {
+		return image[i*rangeSize : (i+1)*rangeSize]
+	}
+
+	expectImageWritten := func() {
+		written, err := os.ReadFile(server.config.Destination)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(written, image)).To(BeTrue())
+	}
+
+	It("should write the ranges concurrently", func() {
+		codes := make(chan int, 4)
+		for i := 0; i < 4; i++ {
+			go func(i int) {
+				defer GinkgoRecover()
+				codes <- putRange(server, imageRange(i), i*rangeSize, len(image)).Code
+			}(i)
+		}
+		var received []int
+		for i := 0; i < 4; i++ {
+			received = append(received, <-codes)
+		}
+
+		// A single request completes the upload, it may have been any of them
+		Expect(received).To(ContainElement(http.StatusOK))
+		Expect(received).To(ContainElements(http.StatusNoContent, http.StatusNoContent, http.StatusNoContent))
+		Expect(processed).To(Equal(1))
+		Expect(server.done).To(BeTrue())
+		Expect(server.uploading).To(BeFalse())
+		expectImageWritten()
+	})
+
+	It("should process the image once all the ranges are written", func() {
+		for _, i := range []int{3, 0, 1} {
+			rr := putRange(server, imageRange(i), i*rangeSize, len(image))
+			Expect(rr.Code).To(Equal(http.StatusNoContent))
+			Expect(rr.Header().Get(common.UploadLengthHeader)).To(Equal(fmt.Sprint(len(image))))
+		}
+		Expect(processed).To(BeZero())
+		Expect(server.done).To(BeFalse())
+
+		// Writing a range again replaces it
+		Expect(putRange(server, make([]byte, rangeSize), rangeSize, len(image)).Code).To(Equal(http.StatusNoContent))
+		Expect(putRange(server, imageRange(1), rangeSize, len(image)).Code).To(Equal(http.StatusNoContent))
+
+		Expect(putRange(server, imageRange(2), 2*rangeSize, len(image)).Code).To(Equal(http.StatusOK))
+		Expect(processed).To(Equal(1))
+		Expect(server.done).To(BeTrue())
+		expectImageWritten()
+	})
+
+	It("should process the image again if the processing failed", func() {
+		processorErr = errors.New("processing failed")
+		for i := 0; i < 3; i++ {
+			Expect(putRange(server, imageRange(i), i*rangeSize, len(image)).Code).To(Equal(http.StatusNoContent))
+		}
+		Expect(putRange(server, imageRange(3), 3*rangeSize, len(image)).Code).To(Equal(http.StatusInternalServerError))
+		Expect(server.done).To(BeFalse())
+		Expect(server.processing).To(BeFalse())
+
+		processorErr = nil
+		Expect(putRange(server, imageRange(3), 3*rangeSize, len(image)).Code).To(Equal(http.StatusOK))
+		Expect(processed).To(Equal(2))
+		Expect(server.done).To(BeTrue())
+	})
+
+	It("should reject other uploads during a range upload", func() {
+		Expect(putRange(server, imageRange(0), 0, len(image)).Code).To(Equal(http.StatusNoContent))
+
+		req, err := http.NewRequest(http.MethodPost, common.UploadPathSync, bytes.NewReader(image))
+		Expect(err).ToNot(HaveOccurred())
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusConflict))
+	})
+
+	DescribeTable("should reject ranges", func(expectedStatus int, prepare func(), send func() *httptest.ResponseRecorder) {
+		prepare()
+		rr := send()
+		Expect(rr.Code).To(Equal(expectedStatus))
+		Expect(processed).To(BeZero())
+	},
+		Entry("without the size of the image", http.StatusBadRequest, func() {}, func() *httptest.ResponseRecorder {
+			req, _ := http.NewRequest(http.MethodPut, common.UploadPathRange, bytes.NewReader(imageRange(0)))
+			req.Header.Set("Content-Range", fmt.Sprintf("bytes 0-%d/*", rangeSize-1))
+			rr := httptest.NewRecorder()
+			server.ServeHTTP(rr, req)
+			return rr
+		}),
+		Entry("with a different size of the image", http.StatusBadRequest, func() {
+			Expect(putRange(server, imageRange(0), 0, len(image)).Code).To(Equal(http.StatusNoContent))
+		}, func() *httptest.ResponseRecorder {
+			return putRange(server, imageRange(1), rangeSize, len(image)+1)
+		}),
+		Entry("overlapping a range being written", http.StatusConflict, func() {
+			Expect(putRange(server, imageRange(0), 0, len(image)).Code).To(Equal(http.StatusNoContent))
+			server.rangeUpload.inflight = []byteRange{{start: rangeSize + 10, end: rangeSize + 20}}
+		}, func() *httptest.ResponseRecorder {
+			return putRange(server, imageRange(1), rangeSize, len(image))
+		}),
+		Entry("of a qcow2 image", http.StatusUnsupportedMediaType, func() {}, func() *httptest.ResponseRecorder {
+			return putRange(server, append([]byte("QFI\xfb"), imageRange(0)[4:]...), 0, len(image))
+		}),
+		Entry("of an image larger than the target", http.StatusBadRequest, func() {}, func() *httptest.ResponseRecorder {
+			return putRange(server, imageRange(0), 0, 1<<62)
+		}),
+		Entry("during an upload", http.StatusServiceUnavailable, func() { server.uploading = true }, func() *httptest.ResponseRecorder {
+			return putRange(server, imageRange(0), 0, len(image))
+		}),
+		Entry("after the upload is done", http.StatusConflict, func() { server.done = true }, func() *httptest.ResponseRecorder {
+			return putRange(server, imageRange(0), 0, len(image))
+		}),
+		Entry("with a resumable upload session", http.StatusConflict, func() { server.session = &uploadSession{ID: "session"} }, func() *httptest.ResponseRecorder {
+			return putRange(server, imageRange(0), 0, len(image))
+		}),
+		Entry("with an unsupported method", http.StatusMethodNotAllowed, func() {}, func() *httptest.ResponseRecorder {
+			req, _ := http.NewRequest(http.MethodPost, common.UploadPathRange, bytes.NewReader(imageRange(0)))
+			rr := httptest.NewRecorder()
+			server.ServeHTTP(rr, req)
+			return rr
+		}),
+	)
+
+	DescribeTable("should merge the written ranges", func(written []byteRange, r byteRange, expected []byteRange) {
+		upload := &rangeUpload{total: 100, written: written}
+		upload.addWritten(r)
+		Expect(upload.written).To(Equal(expected))
+	},
+		Entry("first range", nil, byteRange{10, 20}, []byteRange{{10, 20}}),
+		Entry("disjoint range", []byteRange{{30, 40}}, byteRange{10, 20}, []byteRange{{10, 20}, {30, 40}}),
+		Entry("adjacent ranges", []byteRange{{0, 10}, {20, 30}}, byteRange{10, 20}, []byteRange{{0, 30}}),
+		Entry("overlapping ranges", []byteRange{{0, 15}, {18, 30}, {50, 60}}, byteRange{10, 20}, []byteRange{{0, 30}, {50, 60}}),
+		Entry("range written again", []byteRange{{0, 30}}, byteRange{10, 20}, []byteRange{{0, 30}}),
+	)
+})
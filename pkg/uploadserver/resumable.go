@@ -0,0 +1,540 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2026 The CDI Authors.
+ *
+ */
+
+package uploadserver
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/importer"
+)
+
+// A resumable upload stages the image in the session directory, which is on the scratch volume, before processing it.
+// An image which is not compressed is converted from the session directory, without another copy in scratch space:
+//   - POST common.UploadPathResumable creates the session, the size of the image may be given in the Upload-Length
+//     header, and the checksum of the image in the headers read by expectedChecksum. The URL of the session is
+//     returned in the Location header.
+//   - PATCH <session URL> with an Upload-Offset header, or PUT <session URL> with a Content-Range header, appends a
+//     chunk. The offset of the chunk must be the current offset of the upload.
+//   - HEAD <session URL> returns the current offset in the Upload-Offset header, to resume after a failure.
+//   - POST <session URL> processes the image once all of it has been received.
+//   - DELETE <session URL> discards the session.
+//
+// The offset is only updated once a chunk is synced to disk, so the session survives restarts of the upload server.
+// The scratch volume is kept until the upload completes, so the session also survives the upload pod being replaced.
+const (
+	uploadSessionDirName   = "upload-session"
+	uploadSessionStateFile = "session.json"
+	uploadSessionDataFile  = "data"
+)
+
+// may be overridden in tests
+var sessionProcessorFunc = newSessionProcessor
+
+var contentRangeMatcher = regexp.MustCompile(`^bytes (\d+)-(\d+)/(\d+|\*)$`)
+
+// uploadSession is the state of a resumable upload, persisted in the session directory
+type uploadSession struct {
+	ID string `json:"id"`
+	// Length is the size of the image, -1 until it is known
+	Length int64 `json:"length"`
+	// Offset is the size of the data received and synced
+	Offset int64 `json:"offset"`
+	// Checksum is the checksum the image must match, if any
+	Checksum string `json:"checksum,omitempty"`
+
+	dir string
+}
+
+func uploadSessionDir(sessionDir string) string {
+	return filepath.Join(sessionDir, uploadSessionDirName)
+}
+
+// newUploadSession creates a session, replacing the files of any previous session
+func newUploadSession(sessionDir string, length int64, checksum string) (*uploadSession, error) {
+	dir := uploadSessionDir(sessionDir)
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, errors.Wrap(err, "unable to remove the previous upload session")
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, errors.Wrap(err, "unable to create the upload session directory")
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, errors.Wrap(err, "unable to generate the upload session id")
+	}
+	session := &uploadSession{
+		ID:       hex.EncodeToString(id),
+		Length:   length,
+		Checksum: checksum,
+		dir:      dir,
+	}
+	f, err := os.Create(session.dataPath())
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create the upload session data file")
+	}
+	if err := f.Close(); err != nil {
+		return nil, errors.Wrap(err, "unable to create the upload session data file")
+	}
+	if err := session.save(); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// loadUploadSession loads the session left by a previous run of the upload server, it returns nil if there is none
+func loadUploadSession(sessionDir string) (*uploadSession, error) {
+	dir := uploadSessionDir(sessionDir)
+	data, err := os.ReadFile(filepath.Join(dir, uploadSessionStateFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the upload session")
+	}
+	session := &uploadSession{dir: dir}
+	if err := json.Unmarshal(data, session); err != nil {
+		return nil, errors.Wrap(err, "unable to parse the upload session")
+	}
+	info, err := os.Stat(session.dataPath())
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to stat the upload session data file")
+	}
+	if info.Size() < session.Offset {
+		return nil, errors.Errorf("the upload session data file is smaller than the upload offset %d", session.Offset)
+	}
+	klog.Infof("Resuming upload session %s at offset %d", session.ID, session.Offset)
+	return session, nil
+}
+
+func removeUploadSession(sessionDir string) error {
+	return os.RemoveAll(uploadSessionDir(sessionDir))
+}
+
+func (s *uploadSession) dataPath() string {
+	return filepath.Join(s.dir, uploadSessionDataFile)
+}
+
+func (s *uploadSession) url() string {
+	return common.UploadPathResumable + "/" + s.ID
+}
+
+// save writes the state of the session, replacing the previous state atomically
+func (s *uploadSession) save() error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal the upload session")
+	}
+	stateFile := filepath.Join(s.dir, uploadSessionStateFile)
+	f, err := os.Create(stateFile + ".tmp")
+	if err != nil {
+		return errors.Wrap(err, "unable to save the upload session")
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return errors.Wrap(err, "unable to save the upload session")
+	}
+	if err := f.Sync(); err != nil {
+		return errors.Wrap(err, "unable to save the upload session")
+	}
+	return errors.Wrap(os.Rename(f.Name(), stateFile), "unable to save the upload session")
+}
+
+// write appends the data of r at the offset of the session and syncs it, it returns the number of bytes written
+func (s *uploadSession) write(r io.Reader) (int64, error) {
+	f, err := os.OpenFile(s.dataPath(), os.O_WRONLY, 0)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to open the upload session data file")
+	}
+	defer f.Close()
+	// Drop what is left of a chunk which was not synced
+	if err := f.Truncate(s.Offset); err != nil {
+		return 0, errors.Wrap(err, "unable to truncate the upload session data file")
+	}
+	if _, err := f.Seek(s.Offset, io.SeekStart); err != nil {
+		return 0, errors.Wrap(err, "unable to seek in the upload session data file")
+	}
+	n, copyErr := io.Copy(f, r)
+	if err := f.Sync(); err != nil {
+		return 0, errors.Wrap(err, "unable to sync the upload session data file")
+	}
+	return n, copyErr
+}
+
+func setUploadSessionHeaders(w http.ResponseWriter, session *uploadSession) {
+	w.Header().Set(common.UploadOffsetHeader, strconv.FormatInt(session.Offset, 10))
+	if session.Length >= 0 {
+		w.Header().Set(common.UploadLengthHeader, strconv.FormatInt(session.Length, 10))
+	}
+}
+
+// parseUploadLength parses the Upload-Length header, it returns -1 if the header is not set
+func parseUploadLength(value string) (int64, error) {
+	if value == "" {
+		return -1, nil
+	}
+	length, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || length < 0 {
+		return 0, errors.Errorf("invalid %s header %q", common.UploadLengthHeader, value)
+	}
+	return length, nil
+}
+
+// parseChunkRange returns the offset and the size of the chunk sent by the request, and the size of the image, the
+// sizes are -1 if they are not known
+func parseChunkRange(r *http.Request) (int64, int64, int64, error) {
+	if r.Method == http.MethodPut {
+		contentRange := r.Header.Get("Content-Range")
+		match := contentRangeMatcher.FindStringSubmatch(contentRange)
+		if match == nil {
+			return 0, 0, 0, errors.Errorf("invalid Content-Range header %q", contentRange)
+		}
+		start, err1 := strconv.ParseInt(match[1], 10, 64)
+		end, err2 := strconv.ParseInt(match[2], 10, 64)
+		if err1 != nil || err2 != nil || end < start {
+			return 0, 0, 0, errors.Errorf("invalid Content-Range header %q", contentRange)
+		}
+		total := int64(-1)
+		if match[3] != "*" {
+			var err error
+			if total, err = strconv.ParseInt(match[3], 10, 64); err != nil || end >= total {
+				return 0, 0, 0, errors.Errorf("invalid Content-Range header %q", contentRange)
+			}
+		}
+		size := end - start + 1
+		if r.ContentLength >= 0 && r.ContentLength != size {
+			return 0, 0, 0, errors.Errorf("the Content-Length %d does not match the Content-Range header %q", r.ContentLength, contentRange)
+		}
+		return start, size, total, nil
+	}
+
+	value := r.Header.Get(common.UploadOffsetHeader)
+	offset, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || offset < 0 {
+		return 0, 0, 0, errors.Errorf("invalid %s header %q", common.UploadOffsetHeader, value)
+	}
+	total, err := parseUploadLength(r.Header.Get(common.UploadLengthHeader))
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return offset, r.ContentLength, total, nil
+}
+
+func writeSessionError(w http.ResponseWriter, status int, err error) {
+	klog.Errorf("Resumable upload failed: %v", err)
+	w.WriteHeader(status)
+	if _, writeErr := fmt.Fprintf(w, "Resumable upload failed: %s", err.Error()); writeErr != nil {
+		klog.Errorf("failed to send response; %v", writeErr)
+	}
+}
+
+// getSession returns the session with the id, or nil if there is none
+func (app *uploadServerApp) getSession(id string) *uploadSession {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	if app.session == nil || app.session.ID != id {
+		return nil
+	}
+	return app.session
+}
+
+func (app *uploadServerApp) endUpload() {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	app.uploading = false
+}
+
+func (app *uploadServerApp) createSessionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if !app.validateClient(w, r) {
+		return
+	}
+	length, err := parseUploadLength(r.Header.Get(common.UploadLengthHeader))
+	if err != nil {
+		writeSessionError(w, http.StatusBadRequest, err)
+		return
+	}
+	// The validator is created when the session is processed, the checksum is validated now to fail early
+	checksum, err := expectedChecksum(r)
+	if err == nil {
+		_, err = importer.NewChecksumValidator(checksum)
+	}
+	if err != nil {
+		writeSessionError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	if app.uploading || app.processing {
+		klog.Warning("Got upload session request during an upload")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	if app.done {
+		klog.Warning("Got upload session request after already done")
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	if app.rangeUpload != nil {
+		klog.Warning("Got upload session request during a range upload")
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	if app.session != nil {
+		// A single upload populates the volume, the existing session has to be resumed or deleted first
+		klog.Warningf("Got upload session request while session %s exists", app.session.ID)
+		w.Header().Set("Location", app.session.url())
+		setUploadSessionHeaders(w, app.session)
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	session, err := newUploadSession(app.config.SessionDir, length, checksum)
+	if err != nil {
+		writeSessionError(w, http.StatusInternalServerError, err)
+		return
+	}
+	app.session = session
+	klog.Infof("Created upload session %s", session.ID)
+
+	w.Header().Set("Location", session.url())
+	setUploadSessionHeaders(w, session)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (app *uploadServerApp) sessionHandler(w http.ResponseWriter, r *http.Request) {
+	if !app.validateClient(w, r) {
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, common.UploadPathResumable+"/")
+	switch r.Method {
+	case http.MethodHead:
+		app.sessionOffsetHandler(w, id)
+	case http.MethodPatch, http.MethodPut:
+		app.sessionChunkHandler(w, r, id)
+	case http.MethodPost:
+		app.sessionFinalizeHandler(w, id)
+	case http.MethodDelete:
+		app.sessionDeleteHandler(w, id)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (app *uploadServerApp) sessionOffsetHandler(w http.ResponseWriter, id string) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	if app.done {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	if app.session == nil || app.session.ID != id {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	setUploadSessionHeaders(w, app.session)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (app *uploadServerApp) sessionChunkHandler(w http.ResponseWriter, r *http.Request, id string) {
+	offset, size, total, err := parseChunkRange(r)
+	if err != nil {
+		writeSessionError(w, http.StatusBadRequest, err)
+		return
+	}
+	if !app.startUpload(w) {
+		return
+	}
+	defer app.endUpload()
+
+	// The session is only modified while uploading
+	session := app.getSession(id)
+	if session == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if offset != session.Offset {
+		klog.Warningf("Got chunk at offset %d, expected offset %d", offset, session.Offset)
+		setUploadSessionHeaders(w, session)
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	length := session.Length
+	if total >= 0 {
+		if length >= 0 && total != length {
+			writeSessionError(w, http.StatusBadRequest, errors.Errorf("the upload length %d does not match the session length %d", total, length))
+			return
+		}
+		length = total
+	}
+	if length >= 0 && size >= 0 && offset+size > length {
+		writeSessionError(w, http.StatusBadRequest, errors.Errorf("the chunk ends after the end of the upload at %d", length))
+		return
+	}
+
+	body := io.Reader(r.Body)
+	if size >= 0 {
+		body = io.LimitReader(body, size)
+	} else if length >= 0 {
+		body = io.LimitReader(body, length-offset)
+	}
+	n, writeErr := session.write(body)
+
+	app.mutex.Lock()
+	session.Offset += n
+	session.Length = length
+	app.mutex.Unlock()
+	if err := session.save(); err != nil {
+		writeSessionError(w, http.StatusInternalServerError, err)
+		return
+	}
+	klog.V(3).Infof("Wrote %d bytes to upload session %s, offset %d", n, session.ID, session.Offset)
+
+	setUploadSessionHeaders(w, session)
+	if writeErr != nil {
+		writeSessionError(w, http.StatusInternalServerError, writeErr)
+		return
+	}
+	if size < 0 && length >= 0 && session.Offset == length {
+		if extra, _ := r.Body.Read(make([]byte, 1)); extra > 0 {
+			writeSessionError(w, http.StatusBadRequest, errors.Errorf("the chunk ends after the end of the upload at %d", length))
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (app *uploadServerApp) sessionFinalizeHandler(w http.ResponseWriter, id string) {
+	if !app.startUpload(w) {
+		return
+	}
+
+	session := app.getSession(id)
+	if session == nil {
+		app.endUpload()
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if session.Length >= 0 && session.Offset != session.Length {
+		app.endUpload()
+		klog.Warningf("Got finalize request for upload session %s at offset %d of %d", session.ID, session.Offset, session.Length)
+		setUploadSessionHeaders(w, session)
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	klog.Infof("Processing upload session %s, %d bytes", session.ID, session.Offset)
+	checksumValidator, err := importer.NewChecksumValidator(session.Checksum)
+	if err != nil {
+		app.endUpload()
+		writeSessionError(w, http.StatusBadRequest, err)
+		return
+	}
+	// The data file may have the beginning of a chunk which was not synced, it is processed in place
+	if err := os.Truncate(session.dataPath(), session.Offset); err != nil {
+		app.endUpload()
+		writeSessionError(w, http.StatusInternalServerError, errors.Wrap(err, "unable to truncate the upload session data file"))
+		return
+	}
+	// All the data of the session was received already
+	progress := app.startProgress(session.Offset)
+	progress.received.Store(session.Offset)
+	progress.complete.Store(true)
+	preallocationApplied, err := sessionProcessorFunc(session.dataPath(), app.config.Destination, app.config.ImageSize, app.config.FilesystemOverhead, app.config.Preallocation, checksumValidator, progress)
+
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	app.uploading = false
+
+	if err != nil {
+		// The session is kept so that the upload can be processed again, or deleted
+		progress.err = err
+		handleStreamError(w, err)
+		return
+	}
+
+	if err := removeUploadSession(app.config.SessionDir); err != nil {
+		klog.Errorf("Unable to remove the upload session: %v", err)
+	}
+	app.session = nil
+	app.done = true
+	app.preallocationApplied = preallocationApplied
+	if checksumValidator != nil {
+		app.checksum = checksumValidator.Checksum()
+	}
+	close(app.doneChan)
+
+	klog.Infof("Wrote data to %s", app.config.Destination)
+}
+
+func (app *uploadServerApp) sessionDeleteHandler(w http.ResponseWriter, id string) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	if app.uploading || app.processing {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	if app.session == nil || app.session.ID != id {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err := removeUploadSession(app.config.SessionDir); err != nil {
+		writeSessionError(w, http.StatusInternalServerError, err)
+		return
+	}
+	klog.Infof("Deleted upload session %s", app.session.ID)
+	app.session = nil
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// newSessionProcessor processes the image uploaded to file, the file is converted where it is unless it is compressed
+func newSessionProcessor(file, dest, imageSize string, filesystemOverhead float64, preallocation bool, checksumValidator *importer.ChecksumValidator, progress *uploadProgress) (bool, error) {
+	uds, err := importer.NewUploadFileDataSource(file, checksumValidator)
+	if err != nil {
+		return false, err
+	}
+	defer uds.Close()
+	processor := importer.NewDataProcessor(uds, dest, common.ImporterVolumePath, common.ScratchDataDir, imageSize, filesystemOverhead, preallocation, "")
+	processor.SetPhaseObserver(progress.setPhase)
+	err = processor.ProcessData()
+	return processor.PreallocationApplied(), err
+}
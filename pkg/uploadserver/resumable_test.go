@@ -0,0 +1,251 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2026 The CDI Authors.
+ *
+ */
+
+package uploadserver
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/importer"
+)
+
+func newResumableServer(sessionDir string) *uploadServerApp {
+	server := newServer()
+	server.config.SessionDir = sessionDir
+	return NewUploadServer(server.config).(*uploadServerApp)
+}
+
+func sendSessionRequest(server *uploadServerApp, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(method, path, strings.NewReader(body))
+	Expect(err).ToNot(HaveOccurred())
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	rr := httptest.NewRecorder()
+	server.ServeHTTP(rr, req)
+	return rr
+}
+
+func createSession(server *uploadServerApp, length string) string {
+	headers := map[string]string{}
+	if length != "" {
+		headers[common.UploadLengthHeader] = length
+	}
+	rr := sendSessionRequest(server, http.MethodPost, common.UploadPathResumable, "", headers)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+	Expect(rr.Header().Get(common.UploadOffsetHeader)).To(Equal("0"))
+	location := rr.Header().Get("Location")
+	Expect(location).To(HavePrefix(common.UploadPathResumable + "/"))
+	return location
+}
+
+func patchChunk(server *uploadServerApp, location string, offset int, data string) *httptest.ResponseRecorder {
+	return sendSessionRequest(server, http.MethodPatch, location, data, map[string]string{common.UploadOffsetHeader: fmt.Sprint(offset)})
+}
+
+func replaceSessionProcessorFunc(replacement func(string, string, string, float64, bool, *importer.ChecksumValidator, *uploadProgress) (bool, error), f func()) {
+	origProcessorFunc := sessionProcessorFunc
+	sessionProcessorFunc = replacement
+	defer func() {
+		sessionProcessorFunc = origProcessorFunc
+	}()
+	f()
+}
+
+var _ = Describe("Resumable upload", func() {
+	var (
+		sessionDir string
+		uploaded   []byte
+	)
+
+	BeforeEach(func() {
+		sessionDir = GinkgoT().TempDir()
+		uploaded = nil
+	})
+
+	withCapturingProcessor := func(f func()) {
+		replaceSessionProcessorFunc(func(file, dest, imageSize string, filesystemOverhead float64, preallocation bool, checksumValidator *importer.ChecksumValidator, progress *uploadProgress) (bool, error) {
+			var err error
+			uploaded, err = os.ReadFile(file)
+			return false, err
+		}, f)
+	}
+
+	It("should upload an image in chunks", func() {
+		withCapturingProcessor(func() {
+			server := newResumableServer(sessionDir)
+			location := createSession(server, "12")
+
+			rr := patchChunk(server, location, 0, "data")
+			Expect(rr.Code).To(Equal(http.StatusNoContent))
+			Expect(rr.Header().Get(common.UploadOffsetHeader)).To(Equal("4"))
+			Expect(rr.Header().Get(common.UploadLengthHeader)).To(Equal("12"))
+
+			rr = sendSessionRequest(server, http.MethodPut, location, "more", map[string]string{"Content-Range": "bytes 4-7/12"})
+			Expect(rr.Code).To(Equal(http.StatusNoContent))
+			Expect(rr.Header().Get(common.UploadOffsetHeader)).To(Equal("8"))
+
+			rr = sendSessionRequest(server, http.MethodHead, location, "", nil)
+			Expect(rr.Code).To(Equal(http.StatusOK))
+			Expect(rr.Header().Get(common.UploadOffsetHeader)).To(Equal("8"))
+
+			rr = patchChunk(server, location, 8, "data")
+			Expect(rr.Code).To(Equal(http.StatusNoContent))
+
+			rr = sendSessionRequest(server, http.MethodPost, location, "", nil)
+			Expect(rr.Code).To(Equal(http.StatusOK))
+			Expect(string(uploaded)).To(Equal("datamoredata"))
+			Expect(server.done).To(BeTrue())
+			Expect(uploadSessionDir(sessionDir)).ToNot(BeADirectory())
+		})
+	})
+
+	It("should resume the upload after a restart of the server", func() {
+		withCapturingProcessor(func() {
+			server := newResumableServer(sessionDir)
+			location := createSession(server, "")
+			Expect(patchChunk(server, location, 0, "data").Code).To(Equal(http.StatusNoContent))
+
+			// The end of a chunk which was not synced before the restart is dropped
+			f, err := os.OpenFile(server.session.dataPath(), os.O_WRONLY|os.O_APPEND, 0)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = f.WriteString("garbage")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(f.Close()).To(Succeed())
+
+			server = newResumableServer(sessionDir)
+			rr := sendSessionRequest(server, http.MethodHead, location, "", nil)
+			Expect(rr.Code).To(Equal(http.StatusOK))
+			Expect(rr.Header().Get(common.UploadOffsetHeader)).To(Equal("4"))
+			Expect(rr.Header().Get(common.UploadLengthHeader)).To(BeEmpty())
+
+			Expect(patchChunk(server, location, 4, "more").Code).To(Equal(http.StatusNoContent))
+			Expect(sendSessionRequest(server, http.MethodPost, location, "", nil).Code).To(Equal(http.StatusOK))
+			Expect(string(uploaded)).To(Equal("datamore"))
+		})
+	})
+
+	It("should reject a chunk at the wrong offset", func() {
+		server := newResumableServer(sessionDir)
+		location := createSession(server, "")
+		Expect(patchChunk(server, location, 0, "data").Code).To(Equal(http.StatusNoContent))
+
+		rr := patchChunk(server, location, 2, "data")
+		Expect(rr.Code).To(Equal(http.StatusConflict))
+		Expect(rr.Header().Get(common.UploadOffsetHeader)).To(Equal("4"))
+	})
+
+	It("should reject a chunk beyond the upload length", func() {
+		server := newResumableServer(sessionDir)
+		location := createSession(server, "6")
+
+		rr := patchChunk(server, location, 0, "too long")
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+		Expect(server.session.Offset).To(BeZero())
+	})
+
+	DescribeTable("should reject invalid chunk headers", func(method string, headers map[string]string) {
+		server := newResumableServer(sessionDir)
+		location := createSession(server, "4")
+
+		rr := sendSessionRequest(server, method, location, "data", headers)
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+	},
+		Entry("PATCH without offset", http.MethodPatch, map[string]string{}),
+		Entry("PATCH with negative offset", http.MethodPatch, map[string]string{common.UploadOffsetHeader: "-1"}),
+		Entry("PATCH with a different length", http.MethodPatch, map[string]string{common.UploadOffsetHeader: "0", common.UploadLengthHeader: "8"}),
+		Entry("PUT without Content-Range", http.MethodPut, map[string]string{}),
+		Entry("PUT with a range ending after the total", http.MethodPut, map[string]string{"Content-Range": "bytes 0-4/4"}),
+		Entry("PUT with a range not matching the body", http.MethodPut, map[string]string{"Content-Range": "bytes 0-1/4"}),
+	)
+
+	It("should not process an incomplete upload", func() {
+		server := newResumableServer(sessionDir)
+		location := createSession(server, "8")
+		Expect(patchChunk(server, location, 0, "data").Code).To(Equal(http.StatusNoContent))
+
+		rr := sendSessionRequest(server, http.MethodPost, location, "", nil)
+		Expect(rr.Code).To(Equal(http.StatusConflict))
+		Expect(rr.Header().Get(common.UploadOffsetHeader)).To(Equal("4"))
+		Expect(server.done).To(BeFalse())
+	})
+
+	It("should keep the session when the processing fails", func() {
+		replaceSessionProcessorFunc(func(string, string, string, float64, bool, *importer.ChecksumValidator, *uploadProgress) (bool, error) {
+			return false, fmt.Errorf("Error using datastream")
+		}, func() {
+			server := newResumableServer(sessionDir)
+			location := createSession(server, "")
+			Expect(patchChunk(server, location, 0, "data").Code).To(Equal(http.StatusNoContent))
+
+			rr := sendSessionRequest(server, http.MethodPost, location, "", nil)
+			Expect(rr.Code).To(Equal(http.StatusInternalServerError))
+			Expect(server.done).To(BeFalse())
+			Expect(sendSessionRequest(server, http.MethodHead, location, "", nil).Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	It("should return the existing session instead of creating another one", func() {
+		server := newResumableServer(sessionDir)
+		location := createSession(server, "")
+
+		rr := sendSessionRequest(server, http.MethodPost, common.UploadPathResumable, "", nil)
+		Expect(rr.Code).To(Equal(http.StatusConflict))
+		Expect(rr.Header().Get("Location")).To(Equal(location))
+	})
+
+	It("should delete the session", func() {
+		server := newResumableServer(sessionDir)
+		location := createSession(server, "")
+
+		Expect(sendSessionRequest(server, http.MethodDelete, location, "", nil).Code).To(Equal(http.StatusNoContent))
+		Expect(sendSessionRequest(server, http.MethodHead, location, "", nil).Code).To(Equal(http.StatusNotFound))
+		Expect(uploadSessionDir(sessionDir)).ToNot(BeADirectory())
+		createSession(server, "")
+	})
+
+	DescribeTable("should reject requests", func(method string, expectedStatus int, prepare func(*uploadServerApp)) {
+		server := newResumableServer(sessionDir)
+		location := createSession(server, "")
+		prepare(server)
+
+		rr := sendSessionRequest(server, method, location, "data", map[string]string{common.UploadOffsetHeader: "0"})
+		Expect(rr.Code).To(Equal(expectedStatus))
+	},
+		Entry("of an unknown session", http.MethodPatch, http.StatusNotFound, func(server *uploadServerApp) { server.session.ID = "other" }),
+		Entry("during an upload", http.MethodPatch, http.StatusServiceUnavailable, func(server *uploadServerApp) { server.uploading = true }),
+		Entry("to delete during an upload", http.MethodDelete, http.StatusServiceUnavailable, func(server *uploadServerApp) { server.uploading = true }),
+		Entry("after the upload is done", http.MethodPatch, http.StatusConflict, func(server *uploadServerApp) { server.done = true }),
+		Entry("with an unsupported method", http.MethodGet, http.StatusMethodNotAllowed, func(*uploadServerApp) {}),
+	)
+
+	It("should not support resumable uploads without a session directory", func() {
+		server := newServer()
+		rr := sendSessionRequest(server, http.MethodPost, common.UploadPathResumable, "", nil)
+		Expect(rr.Code).To(Equal(http.StatusNotFound))
+	})
+})
@@ -0,0 +1,159 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2026 The CDI Authors.
+ *
+ */
+
+package uploadserver
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/importer"
+)
+
+// uploadProgress is the progress of the transfer and processing of an upload, it is updated while the upload runs
+type uploadProgress struct {
+	// total is the size of the upload, -1 if it is not known
+	total int64
+	// received is the amount of data read from the upload
+	received atomic.Int64
+	// complete is set once all the data of the upload was read
+	complete atomic.Bool
+	// phase is the importer.ProcessingPhase of the processing
+	phase atomic.Value
+	// err is the error the upload failed with, it is protected by the mutex of the server
+	err error
+}
+
+func newUploadProgress(total int64) *uploadProgress {
+	return &uploadProgress{total: total}
+}
+
+func (p *uploadProgress) setPhase(phase importer.ProcessingPhase) {
+	p.phase.Store(phase)
+}
+
+func (p *uploadProgress) processingPhase() string {
+	phase, _ := p.phase.Load().(importer.ProcessingPhase)
+	return string(phase)
+}
+
+// reader returns a reader counting the data read from the stream
+func (p *uploadProgress) reader(stream io.ReadCloser) io.ReadCloser {
+	return &progressReader{ReadCloser: stream, progress: p}
+}
+
+type progressReader struct {
+	io.ReadCloser
+	progress *uploadProgress
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.progress.received.Add(int64(n))
+	if err == io.EOF {
+		r.progress.complete.Store(true)
+	}
+	return n, err
+}
+
+// uploadLength returns the size of the upload sent in the body of the request, or -1 if it is not known
+func uploadLength(r *http.Request) int64 {
+	// The body of form uploads has the other parts of the form
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil && strings.HasPrefix(mediaType, "multipart/") {
+		return -1
+	}
+	return r.ContentLength
+}
+
+// startProgress starts tracking the progress of an upload, replacing the progress of the previous one
+func (app *uploadServerApp) startProgress(total int64) *uploadProgress {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	app.progress = newUploadProgress(total)
+	return app.progress
+}
+
+// status returns the status of the upload, the caller must hold the mutex
+func (app *uploadServerApp) status() *common.UploadStatus {
+	status := &common.UploadStatus{Phase: common.UploadPhasePending}
+	progress := app.progress
+
+	switch {
+	case app.rangeUpload != nil:
+		status.BytesReceived = app.rangeUpload.received()
+		status.BytesTotal = ptr.To(app.rangeUpload.total)
+	case app.session != nil:
+		status.BytesReceived = app.session.Offset
+		if app.session.Length >= 0 {
+			status.BytesTotal = ptr.To(app.session.Length)
+		}
+	case progress != nil:
+		status.BytesReceived = progress.received.Load()
+		if progress.total >= 0 {
+			status.BytesTotal = ptr.To(progress.total)
+		}
+	}
+	if progress != nil {
+		status.ProcessingPhase = progress.processingPhase()
+	}
+
+	switch {
+	case app.done:
+		status.Phase = common.UploadPhaseSucceeded
+		status.Checksum = app.checksum
+	case app.processing, app.uploading && progress != nil && progress.complete.Load():
+		status.Phase = common.UploadPhaseProcessing
+	case app.uploading:
+		status.Phase = common.UploadPhaseUploading
+	case progress != nil && progress.err != nil:
+		status.Phase = common.UploadPhaseFailed
+		status.Message = progress.err.Error()
+	case app.session != nil, app.rangeUpload != nil:
+		// The upload continues with the next chunk or range
+		status.Phase = common.UploadPhaseUploading
+	}
+	return status
+}
+
+func (app *uploadServerApp) statusHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if !app.validateClient(w, r) {
+		return
+	}
+
+	app.mutex.Lock()
+	status := app.status()
+	app.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		klog.Errorf("statusHandler: failed to send response; %v", err)
+	}
+}
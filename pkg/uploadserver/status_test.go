@@ -0,0 +1,146 @@
+/*
+ * This file is part of the CDI project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2026 The CDI Authors.
+ *
+ */
+
+package uploadserver
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/utils/ptr"
+
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"kubevirt.io/containerized-data-importer/pkg/common"
+	"kubevirt.io/containerized-data-importer/pkg/importer"
+)
+
+func getStatus(server *uploadServerApp) *common.UploadStatus {
+	req, err := http.NewRequest(http.MethodGet, common.UploadPathStatus, nil)
+	Expect(err).ToNot(HaveOccurred())
+	rr := httptest.NewRecorder()
+	server.ServeHTTP(rr, req)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+	Expect(rr.Header().Get("Content-Type")).To(Equal("application/json"))
+
+	status := &common.UploadStatus{}
+	Expect(json.Unmarshal(rr.Body.Bytes(), status)).To(Succeed())
+	return status
+}
+
+var _ = Describe("Upload status", func() {
+	It("should be pending before the upload", func() {
+		Expect(getStatus(newServer())).To(Equal(&common.UploadStatus{Phase: common.UploadPhasePending}))
+	})
+
+	It("should report the progress of the processing", func() {
+		read := make(chan struct{})
+		release := make(chan struct{})
+		replaceProcessorFunc(func(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string, dvContentType cdiv1.DataVolumeContentType, checksumValidator *importer.ChecksumValidator, progress *uploadProgress) (bool, error) {
+			defer stream.Close()
+			progress.setPhase(importer.ProcessingPhaseTransferDataFile)
+			if _, err := io.ReadAll(stream); err != nil {
+				return false, err
+			}
+			progress.setPhase(importer.ProcessingPhaseResize)
+			close(read)
+			<-release
+			return false, nil
+		}, func() {
+			server := newServer()
+			done := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				defer close(done)
+				req, err := http.NewRequest(http.MethodPost, common.UploadPathSync, strings.NewReader("data"))
+				Expect(err).ToNot(HaveOccurred())
+				rr := httptest.NewRecorder()
+				server.ServeHTTP(rr, req)
+				Expect(rr.Code).To(Equal(http.StatusOK))
+			}()
+
+			<-read
+			Expect(getStatus(server)).To(Equal(&common.UploadStatus{
+				Phase:           common.UploadPhaseProcessing,
+				BytesReceived:   4,
+				BytesTotal:      ptr.To[int64](4),
+				ProcessingPhase: string(importer.ProcessingPhaseResize),
+			}))
+			close(release)
+			<-done
+
+			status := getStatus(server)
+			Expect(status.Phase).To(Equal(common.UploadPhaseSucceeded))
+			Expect(status.BytesReceived).To(Equal(int64(4)))
+		})
+	})
+
+	It("should report the failure of the upload", func() {
+		withProcessorFailure(func() {
+			server := newServer()
+			req, err := http.NewRequest(http.MethodPost, common.UploadPathSync, strings.NewReader("data"))
+			Expect(err).ToNot(HaveOccurred())
+			rr := httptest.NewRecorder()
+			server.ServeHTTP(rr, req)
+			Expect(rr.Code).To(Equal(http.StatusInternalServerError))
+
+			status := getStatus(server)
+			Expect(status.Phase).To(Equal(common.UploadPhaseFailed))
+			Expect(status.Message).To(Equal("Error using datastream"))
+		})
+	})
+
+	It("should report the data received by a resumable upload", func() {
+		server := newResumableServer(GinkgoT().TempDir())
+		location := createSession(server, "12")
+		Expect(patchChunk(server, location, 0, "data").Code).To(Equal(http.StatusNoContent))
+
+		Expect(getStatus(server)).To(Equal(&common.UploadStatus{
+			Phase:         common.UploadPhaseUploading,
+			BytesReceived: 4,
+			BytesTotal:    ptr.To[int64](12),
+		}))
+	})
+
+	It("should report the data received by a range upload", func() {
+		server := newServer()
+		server.config.Destination = filepath.Join(GinkgoT().TempDir(), "disk.img")
+		Expect(putRange(server, make([]byte, 1024), 2048, 4096).Code).To(Equal(http.StatusNoContent))
+
+		Expect(getStatus(server)).To(Equal(&common.UploadStatus{
+			Phase:         common.UploadPhaseUploading,
+			BytesReceived: 1024,
+			BytesTotal:    ptr.To[int64](4096),
+		}))
+	})
+
+	It("should only support GET", func() {
+		req, err := http.NewRequest(http.MethodPost, common.UploadPathStatus, nil)
+		Expect(err).ToNot(HaveOccurred())
+		rr := httptest.NewRecorder()
+		newServer().ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+})
@@ -24,6 +24,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -42,7 +43,7 @@ import (
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
 	"kubevirt.io/containerized-data-importer/pkg/importer"
-	"kubevirt.io/containerized-data-importer/pkg/util"
+	"kubevirt.io/containerized-data-importer/pkg/util/checksum"
 	cryptowatch "kubevirt.io/containerized-data-importer/pkg/util/tls-crypto-watch"
 )
 
@@ -66,6 +67,17 @@ type Config struct {
 
 	Deadline *time.Time
 
+	// SessionDir is the directory keeping the data of resumable uploads, resumable uploads are disabled if it is empty
+	SessionDir string
+
+	// Export runs the server as an export server, sending the image at Destination instead of receiving it
+	Export bool
+	// ScratchDir is the directory keeping the temporary images of exports
+	ScratchDir string
+
+	// CloneTransformation is the changes made to the disk image of a host assisted clone
+	CloneTransformation importer.CloneTransformation
+
 	CryptoConfig cryptowatch.CryptoConfig
 }
 
@@ -74,6 +86,8 @@ type RunResult struct {
 	CloneTarget          bool
 	PreallocationApplied bool
 	DeadlinePassed       bool
+	// Checksum is the checksum of the uploaded data, if it was validated
+	Checksum string
 }
 
 // UploadServer is the interface to uploadServerApp
@@ -89,9 +103,22 @@ type uploadServerApp struct {
 	done                 bool
 	preallocationApplied bool
 	cloneTarget          bool
+	checksum             string
 	doneChan             chan struct{}
 	errChan              chan error
 	mutex                sync.Mutex
+	// session is the resumable upload in progress
+	session *uploadSession
+	// rangeUpload is the range upload in progress
+	rangeUpload *rangeUpload
+	// progress is the progress of the last upload
+	progress *uploadProgress
+	// exports is the number of exports in progress
+	exports int
+	// qcow2Mutex serializes the conversions of qcow2 exports
+	qcow2Mutex sync.Mutex
+	// qcow2 is the image converted by the last qcow2 export
+	qcow2 *qcow2Export
 }
 
 type imageReadCloser func(*http.Request) (io.ReadCloser, error)
@@ -99,6 +126,8 @@ type imageReadCloser func(*http.Request) (io.ReadCloser, error)
 // may be overridden in tests
 var uploadProcessorFunc = newUploadStreamProcessor
 var uploadProcessorFuncAsync = newAsyncUploadStreamProcessor
+var archiveProcessorFunc = newArchiveStreamProcessor
+var cloneTransformProcessorFunc = newCloneTransformProcessor
 
 func bodyReadCloser(r *http.Request) (io.ReadCloser, error) {
 	return r.Body, nil
@@ -129,7 +158,59 @@ func formReadCloser(r *http.Request) (io.ReadCloser, error) {
 }
 
 func isCloneTarget(contentType string) bool {
-	return contentType == common.BlockdeviceClone || contentType == common.FilesystemCloneContentType
+	return contentType == common.BlockdeviceClone || contentType == common.BlockdeviceSparseClone ||
+		contentType == common.BlockdeviceDeltaClone || contentType == common.FilesystemCloneContentType
+}
+
+// expectedChecksum returns the checksum the uploaded data must match in the "algorithm:hash" format, the checksum of
+// the upload token is set by the upload proxy and takes precedence over the digest headers
+func expectedChecksum(r *http.Request) (string, error) {
+	if value := r.Header.Get(common.UploadChecksumHeader); value != "" {
+		return value, nil
+	}
+	for _, header := range []string{"Content-Digest", "Digest"} {
+		if value := r.Header.Get(header); value != "" {
+			expected, err := checksum.FromDigestHeader(value)
+			return expected, errors.Wrapf(err, "invalid %s header", header)
+		}
+	}
+	return "", nil
+}
+
+// newChecksumValidator returns the validator of the checksum expected by the request, or nil if there is none
+func newChecksumValidator(r *http.Request, contentType string) (*importer.ChecksumValidator, error) {
+	expected, err := expectedChecksum(r)
+	if err != nil || expected == "" {
+		return nil, err
+	}
+	if isCloneTarget(contentType) {
+		return nil, errors.New("checksum validation is not supported for clones")
+	}
+	validator, err := importer.NewChecksumValidator(expected)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid checksum")
+	}
+	return validator, nil
+}
+
+// newArchiveOptions returns the options of an archive upload from the request headers
+func newArchiveOptions(r *http.Request) (*importer.ArchiveOptions, error) {
+	options := importer.DefaultArchiveOptions()
+	options.Manifest = r.Header.Get(common.UploadArchiveManifestHeader)
+	if preserve, ok := r.Header[http.CanonicalHeaderKey(common.UploadArchivePreserveHeader)]; ok {
+		if err := options.SetPreserve(strings.Join(preserve, ",")); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s header", common.UploadArchivePreserveHeader)
+		}
+	}
+	return options, nil
+}
+
+func writeChecksumError(w http.ResponseWriter, err error) {
+	klog.Errorf("Rejecting upload: %v", err)
+	w.WriteHeader(http.StatusBadRequest)
+	if _, writeErr := fmt.Fprintf(w, "Rejecting upload: %s", err.Error()); writeErr != nil {
+		klog.Errorf("failed to send response; %v", writeErr)
+	}
 }
 
 // NewUploadServer returns a new instance of uploadServerApp
@@ -144,6 +225,12 @@ func NewUploadServer(config *Config) UploadServer {
 	}
 
 	server.mux.HandleFunc(healthzPath, server.healthzHandler)
+	if config.Export {
+		server.mux.HandleFunc(common.ExportPath, server.exportHandler)
+		return server
+	}
+	server.mux.HandleFunc(common.UploadPathStatus, server.statusHandler)
+	server.mux.HandleFunc(common.UploadPathHashes, server.hashesHandler)
 	for _, path := range common.SyncUploadPaths {
 		server.mux.HandleFunc(path, server.uploadHandler(bodyReadCloser))
 	}
@@ -159,6 +246,21 @@ func NewUploadServer(config *Config) UploadServer {
 	for _, path := range common.AsyncUploadFormPaths {
 		server.mux.HandleFunc(path, server.uploadHandlerAsync(formReadCloser))
 	}
+	if config.SessionDir != "" {
+		session, err := loadUploadSession(config.SessionDir)
+		if err != nil {
+			klog.Errorf("Unable to load the resumable upload session, discarding it: %v", err)
+			if err := removeUploadSession(config.SessionDir); err != nil {
+				klog.Errorf("Unable to remove the resumable upload session: %v", err)
+			}
+		}
+		server.session = session
+		server.mux.HandleFunc(common.UploadPathResumable, server.createSessionHandler)
+		server.mux.HandleFunc(common.UploadPathResumable+"/", server.sessionHandler)
+	}
+	for _, path := range common.RangeUploadPaths {
+		server.mux.HandleFunc(path, server.rangeUploadHandler)
+	}
 
 	return server
 }
@@ -221,7 +323,7 @@ func (app *uploadServerApp) Run() (*RunResult, error) {
 		app.mutex.Lock()
 		defer app.mutex.Unlock()
 		for {
-			if app.uploading || app.processing {
+			if app.uploading || app.processing || app.exports > 0 {
 				klog.Info("waiting for upload to finish")
 				app.mutex.Unlock()
 				time.Sleep(2 * time.Second)
@@ -239,6 +341,7 @@ func (app *uploadServerApp) Run() (*RunResult, error) {
 	result := &RunResult{
 		CloneTarget:          app.cloneTarget,
 		PreallocationApplied: app.preallocationApplied,
+		Checksum:             app.checksum,
 	}
 
 	return result, nil
@@ -299,6 +402,11 @@ func (app *uploadServerApp) validateShouldHandleRequest(w http.ResponseWriter, r
 		return false
 	}
 
+	return app.validateClient(w, r) && app.startUpload(w)
+}
+
+// validateClient checks that the request comes from the upload proxy
+func (app *uploadServerApp) validateClient(w http.ResponseWriter, r *http.Request) bool {
 	if r.TLS != nil {
 		if len(r.TLS.VerifiedChains) == 0 {
 			w.WriteHeader(http.StatusUnauthorized)
@@ -325,6 +433,11 @@ func (app *uploadServerApp) validateShouldHandleRequest(w http.ResponseWriter, r
 		klog.V(3).Infof("Handling HTTP connection")
 	}
 
+	return true
+}
+
+// startUpload marks the server as uploading, unless an upload is already in progress or done
+func (app *uploadServerApp) startUpload(w http.ResponseWriter) bool {
 	app.mutex.Lock()
 	defer app.mutex.Unlock()
 
@@ -340,6 +453,13 @@ func (app *uploadServerApp) validateShouldHandleRequest(w http.ResponseWriter, r
 		return false
 	}
 
+	if app.rangeUpload != nil {
+		// The range upload already wrote to the target
+		klog.Warning("Got upload request during a range upload")
+		w.WriteHeader(http.StatusConflict)
+		return false
+	}
+
 	app.uploading = true
 
 	return true
@@ -360,18 +480,27 @@ func (app *uploadServerApp) uploadHandlerAsync(irc imageReadCloser) http.Handler
 
 		klog.Infof("Content type header is %q\n", cdiContentType)
 
+		checksumValidator, err := newChecksumValidator(r, cdiContentType)
+		if err != nil {
+			app.endUpload()
+			writeChecksumError(w, err)
+			return
+		}
+
 		readCloser, err := irc(r)
 		if err != nil {
 			w.WriteHeader(http.StatusBadRequest)
 		}
 
-		processor, err := uploadProcessorFuncAsync(readCloser, app.config.Destination, app.config.ImageSize, app.config.FilesystemOverhead, app.config.Preallocation, cdiContentType)
+		progress := app.startProgress(uploadLength(r))
+		processor, err := uploadProcessorFuncAsync(progress.reader(readCloser), app.config.Destination, app.config.ImageSize, app.config.FilesystemOverhead, app.config.Preallocation, cdiContentType, checksumValidator, progress)
 
 		app.mutex.Lock()
 		defer app.mutex.Unlock()
 		app.uploading = false
 
 		if err != nil {
+			progress.err = err
 			handleStreamError(w, err)
 			return
 		}
@@ -386,6 +515,7 @@ func (app *uploadServerApp) uploadHandlerAsync(irc imageReadCloser) http.Handler
 			app.processing = false
 			if err != nil {
 				klog.Errorf("Error during resumed processing: %v", err)
+				progress.err = err
 				app.errChan <- err
 				return
 			}
@@ -393,6 +523,9 @@ func (app *uploadServerApp) uploadHandlerAsync(irc imageReadCloser) http.Handler
 			app.done = true
 			app.preallocationApplied = processor.PreallocationApplied()
 			app.cloneTarget = isCloneTarget(cdiContentType)
+			if checksumValidator != nil {
+				app.checksum = checksumValidator.Checksum()
+			}
 			klog.Infof("Wrote data to %s", app.config.Destination)
 		}()
 
@@ -409,18 +542,44 @@ func (app *uploadServerApp) processUpload(irc imageReadCloser, w http.ResponseWr
 
 	klog.Infof("Content type header is %q\n", cdiContentType)
 
+	checksumValidator, err := newChecksumValidator(r, cdiContentType)
+	if err != nil {
+		app.endUpload()
+		writeChecksumError(w, err)
+		return
+	}
+
+	var archiveOptions *importer.ArchiveOptions
+	if dvContentType == cdiv1.DataVolumeArchive {
+		if archiveOptions, err = newArchiveOptions(r); err != nil {
+			app.endUpload()
+			writeChecksumError(w, err)
+			return
+		}
+	}
+
 	readCloser, err := irc(r)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 	}
 
-	preallocationApplied, err := uploadProcessorFunc(readCloser, app.config.Destination, app.config.ImageSize, app.config.FilesystemOverhead, app.config.Preallocation, cdiContentType, dvContentType)
+	progress := app.startProgress(uploadLength(r))
+	var preallocationApplied bool
+	var extractedFiles []importer.ExtractedFile
+	if archiveOptions != nil {
+		extractedFiles, err = archiveProcessorFunc(progress.reader(readCloser), archiveOptions, checksumValidator, progress)
+	} else if isCloneTarget(cdiContentType) && app.config.CloneTransformation.IsSet() {
+		preallocationApplied, err = cloneTransformProcessorFunc(progress.reader(readCloser), app.config.Destination, app.config.ImageSize, app.config.FilesystemOverhead, app.config.Preallocation, cdiContentType, app.config.CloneTransformation, progress)
+	} else {
+		preallocationApplied, err = uploadProcessorFunc(progress.reader(readCloser), app.config.Destination, app.config.ImageSize, app.config.FilesystemOverhead, app.config.Preallocation, cdiContentType, dvContentType, checksumValidator, progress)
+	}
 
 	app.mutex.Lock()
 	defer app.mutex.Unlock()
 	app.uploading = false
 
 	if err != nil {
+		progress.err = err
 		handleStreamError(w, err)
 		return
 	}
@@ -428,15 +587,35 @@ func (app *uploadServerApp) processUpload(irc imageReadCloser, w http.ResponseWr
 	app.done = true
 	app.preallocationApplied = preallocationApplied
 	app.cloneTarget = isCloneTarget(cdiContentType)
+	if checksumValidator != nil {
+		app.checksum = checksumValidator.Checksum()
+	}
 	close(app.doneChan)
 
-	if dvContentType == cdiv1.DataVolumeArchive {
-		klog.Infof("Wrote archive data")
+	if archiveOptions != nil {
+		klog.Infof("Wrote archive data, %d files", len(extractedFiles))
+		writeArchiveUploadResponse(w, extractedFiles)
 	} else {
 		klog.Infof("Wrote data to %s", app.config.Destination)
 	}
 }
 
+// ArchiveUploadResponse is the response to a successful archive upload
+type ArchiveUploadResponse struct {
+	// Files are the files extracted from the archive
+	Files []importer.ExtractedFile `json:"files"`
+}
+
+func writeArchiveUploadResponse(w http.ResponseWriter, files []importer.ExtractedFile) {
+	if files == nil {
+		files = []importer.ExtractedFile{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&ArchiveUploadResponse{Files: files}); err != nil {
+		klog.Errorf("failed to send response; %v", err)
+	}
+}
+
 func (app *uploadServerApp) uploadHandler(irc imageReadCloser) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		app.processUpload(irc, w, r, cdiv1.DataVolumeKubeVirt)
@@ -449,29 +628,41 @@ func (app *uploadServerApp) uploadArchiveHandler(irc imageReadCloser) http.Handl
 	}
 }
 
-func newAsyncUploadStreamProcessor(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, sourceContentType string) (*importer.DataProcessor, error) {
+func newAsyncUploadStreamProcessor(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, sourceContentType string, checksumValidator *importer.ChecksumValidator, progress *uploadProgress) (*importer.DataProcessor, error) {
 	if isCloneTarget(sourceContentType) {
 		return nil, fmt.Errorf("async clone not supported")
 	}
 
-	uds := importer.NewAsyncUploadDataSource(newContentReader(stream, sourceContentType))
+	uds := importer.NewAsyncUploadDataSource(newContentReader(stream, sourceContentType), checksumValidator)
 	processor := importer.NewDataProcessor(uds, dest, common.ImporterVolumePath, common.ScratchDataDir, imageSize, filesystemOverhead, preallocation, "")
+	processor.SetPhaseObserver(progress.setPhase)
 	return processor, processor.ProcessDataWithPause()
 }
 
-func newUploadStreamProcessor(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, sourceContentType string, dvContentType cdiv1.DataVolumeContentType) (bool, error) {
+func newUploadStreamProcessor(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, sourceContentType string, dvContentType cdiv1.DataVolumeContentType, checksumValidator *importer.ChecksumValidator, progress *uploadProgress) (bool, error) {
 	stream = newContentReader(stream, sourceContentType)
 	if isCloneTarget(sourceContentType) {
 		return cloneProcessor(stream, sourceContentType, dest, preallocation)
 	}
 
 	// Clone block device to block device or file system
-	uds := importer.NewUploadDataSource(stream, dvContentType)
+	uds := importer.NewUploadDataSource(stream, dvContentType, checksumValidator)
 	processor := importer.NewDataProcessor(uds, dest, common.ImporterVolumePath, common.ScratchDataDir, imageSize, filesystemOverhead, preallocation, "")
+	processor.SetPhaseObserver(progress.setPhase)
 	err := processor.ProcessData()
 	return processor.PreallocationApplied(), err
 }
 
+func newArchiveStreamProcessor(stream io.ReadCloser, options *importer.ArchiveOptions, checksumValidator *importer.ChecksumValidator, progress *uploadProgress) ([]importer.ExtractedFile, error) {
+	uds := importer.NewArchiveUploadDataSource(stream, options, checksumValidator)
+	processor := importer.NewDataProcessor(uds, "", common.ImporterVolumePath, common.ScratchDataDir, "", 0, false, "")
+	processor.SetPhaseObserver(progress.setPhase)
+	if err := processor.ProcessData(); err != nil {
+		return nil, err
+	}
+	return uds.ExtractedFiles(), nil
+}
+
 func cloneProcessor(stream io.ReadCloser, contentType, dest string, preallocate bool) (bool, error) {
 	if contentType == common.FilesystemCloneContentType {
 		if dest != common.WriteBlockPath {
@@ -488,7 +679,7 @@ func cloneProcessor(stream io.ReadCloser, contentType, dest string, preallocate
 
 	defer stream.Close()
 
-	_, _, err := importer.StreamDataToFile(stream, dest, preallocate)
+	_, _, err := cloneStreamToFile(contentType)(stream, dest, preallocate)
 	if err != nil {
 		return false, err
 	}
@@ -496,9 +687,45 @@ func cloneProcessor(stream io.ReadCloser, contentType, dest string, preallocate
 	return false, nil
 }
 
+// cloneStreamToFile returns the function writing the stream of a clone of contentType to the target
+func cloneStreamToFile(contentType string) importer.StreamToFileFunc {
+	switch contentType {
+	case common.BlockdeviceSparseClone:
+		return importer.StreamSparseToFile
+	case common.BlockdeviceDeltaClone:
+		return importer.StreamDeltaToFile
+	}
+	return importer.StreamDataToFile
+}
+
+// newCloneTransformProcessor writes the disk image of a host assisted clone to dest and transforms it, filesystem
+// clones are written as a disk image to filesystem targets too
+func newCloneTransformProcessor(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string, transformation importer.CloneTransformation, progress *uploadProgress) (bool, error) {
+	stream = newContentReader(stream, contentType)
+	if contentType == common.BlockdeviceDeltaClone {
+		stream.Close()
+		return false, errors.New("delta clones cannot be transformed")
+	}
+	if contentType == common.FilesystemCloneContentType {
+		tarImageReader, err := newTarDiskImageReader(stream)
+		if err != nil {
+			stream.Close()
+			return false, err
+		}
+		stream = tarImageReader
+	}
+
+	cds := importer.NewCloneDataSource(stream, cloneStreamToFile(contentType), transformation)
+	defer cds.Close()
+	processor := importer.NewDataProcessor(cds, dest, common.ImporterVolumePath, "", imageSize, filesystemOverhead, preallocation, "")
+	processor.SetPhaseObserver(progress.setPhase)
+	err := processor.ProcessData()
+	return processor.PreallocationApplied(), err
+}
+
 func fileToFileCloneProcessor(stream io.ReadCloser) (bool, error) {
 	defer stream.Close()
-	if err := util.UnArchiveTar(stream, common.ImporterVolumePath); err != nil {
+	if _, err := importer.ExtractTar(stream, common.ImporterVolumePath, nil); err != nil {
 		return false, errors.Wrapf(err, "error unarchiving to %s", common.ImporterVolumePath)
 	}
 	return true, nil
@@ -577,6 +804,8 @@ func handleStreamError(w http.ResponseWriter, err error) {
 	if importer.IsNoCapacityError(err) {
 		w.WriteHeader(http.StatusBadRequest)
 		err = fmt.Errorf("effective image size is larger than the reported available storage: %w", err)
+	} else if errors.Is(err, importer.ErrChecksumMismatch) || errors.Is(err, importer.ErrInvalidArchive) {
+		w.WriteHeader(http.StatusBadRequest)
 	} else {
 		w.WriteHeader(http.StatusInternalServerError)
 	}
@@ -23,6 +23,7 @@ import (
 	"bytes"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -34,6 +35,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/golang/snappy"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -42,6 +45,7 @@ import (
 	"kubevirt.io/containerized-data-importer/pkg/importer"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert"
 	"kubevirt.io/containerized-data-importer/pkg/util/cert/triple"
+	"kubevirt.io/containerized-data-importer/pkg/util/sparse"
 	cryptowatch "kubevirt.io/containerized-data-importer/pkg/util/tls-crypto-watch"
 )
 
@@ -118,11 +122,11 @@ func newHTTPClient(clientKeyPair *triple.KeyPair, serverCACert *x509.Certificate
 	return client
 }
 
-func saveProcessorSuccess(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string, dvContentType cdiv1.DataVolumeContentType) (bool, error) {
+func saveProcessorSuccess(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string, dvContentType cdiv1.DataVolumeContentType, checksumValidator *importer.ChecksumValidator, progress *uploadProgress) (bool, error) {
 	return false, nil
 }
 
-func saveProcessorFailure(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string, dvContentType cdiv1.DataVolumeContentType) (bool, error) {
+func saveProcessorFailure(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string, dvContentType cdiv1.DataVolumeContentType, checksumValidator *importer.ChecksumValidator, progress *uploadProgress) (bool, error) {
 	return false, fmt.Errorf("Error using datastream")
 }
 
@@ -134,7 +138,7 @@ func withProcessorFailure(f func()) {
 	replaceProcessorFunc(saveProcessorFailure, f)
 }
 
-func replaceProcessorFunc(replacement func(io.ReadCloser, string, string, float64, bool, string, cdiv1.DataVolumeContentType) (bool, error), f func()) {
+func replaceProcessorFunc(replacement func(io.ReadCloser, string, string, float64, bool, string, cdiv1.DataVolumeContentType, *importer.ChecksumValidator, *uploadProgress) (bool, error), f func()) {
 	origProcessorFunc := uploadProcessorFunc
 	uploadProcessorFunc = replacement
 	defer func() {
@@ -143,6 +147,21 @@ func replaceProcessorFunc(replacement func(io.ReadCloser, string, string, float6
 	f()
 }
 
+func withArchiveProcessorFailure(f func()) {
+	replaceArchiveProcessorFunc(func(io.ReadCloser, *importer.ArchiveOptions, *importer.ChecksumValidator, *uploadProgress) ([]importer.ExtractedFile, error) {
+		return nil, fmt.Errorf("Error using datastream")
+	}, f)
+}
+
+func replaceArchiveProcessorFunc(replacement func(io.ReadCloser, *importer.ArchiveOptions, *importer.ChecksumValidator, *uploadProgress) ([]importer.ExtractedFile, error), f func()) {
+	origArchiveProcessorFunc := archiveProcessorFunc
+	archiveProcessorFunc = replacement
+	defer func() {
+		archiveProcessorFunc = origArchiveProcessorFunc
+	}()
+	f()
+}
+
 type AsyncMockDataSource struct {
 }
 
@@ -186,11 +205,11 @@ func (amd *AsyncMockDataSource) GetResumePhase() importer.ProcessingPhase {
 	return importer.ProcessingPhaseComplete
 }
 
-func saveAsyncProcessorSuccess(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string) (*importer.DataProcessor, error) {
+func saveAsyncProcessorSuccess(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string, checksumValidator *importer.ChecksumValidator, progress *uploadProgress) (*importer.DataProcessor, error) {
 	return importer.NewDataProcessor(&AsyncMockDataSource{}, "", "", "", "", 0.06, false, ""), nil
 }
 
-func saveAsyncProcessorFailure(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string) (*importer.DataProcessor, error) {
+func saveAsyncProcessorFailure(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string, checksumValidator *importer.ChecksumValidator, progress *uploadProgress) (*importer.DataProcessor, error) {
 	return importer.NewDataProcessor(&AsyncMockDataSource{}, "", "", "", "", 0.06, false, ""), fmt.Errorf("Error using datastream")
 }
 
@@ -202,7 +221,7 @@ func withAsyncProcessorFailure(f func()) {
 	replaceAsyncProcessorFunc(saveAsyncProcessorFailure, f)
 }
 
-func replaceAsyncProcessorFunc(replacement func(io.ReadCloser, string, string, float64, bool, string) (*importer.DataProcessor, error), f func()) {
+func replaceAsyncProcessorFunc(replacement func(io.ReadCloser, string, string, float64, bool, string, *importer.ChecksumValidator, *uploadProgress) (*importer.DataProcessor, error), f func()) {
 	origProcessorFuncAsync := uploadProcessorFuncAsync
 	uploadProcessorFuncAsync = replacement
 	defer func() {
@@ -350,9 +369,51 @@ var _ = Describe("Upload server tests", func() {
 	},
 		Entry("async", withAsyncProcessorFailure, common.UploadPathAsync),
 		Entry("sync", withProcessorFailure, common.UploadPathSync),
-		Entry("archive", withProcessorFailure, common.UploadArchivePath),
+		Entry("archive", withArchiveProcessorFailure, common.UploadArchivePath),
 	)
 
+	It("Should return the extracted files of an archive upload", func() {
+		var options *importer.ArchiveOptions
+		files := []importer.ExtractedFile{{Name: "disk.img", Type: importer.ExtractedFileTypeFile, Size: 4, Checksum: "sha256:abc"}}
+		replaceArchiveProcessorFunc(func(stream io.ReadCloser, o *importer.ArchiveOptions, checksumValidator *importer.ChecksumValidator, progress *uploadProgress) ([]importer.ExtractedFile, error) {
+			options = o
+			return files, nil
+		}, func() {
+			req, err := http.NewRequest(http.MethodPost, common.UploadArchivePath, strings.NewReader("data"))
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set(common.UploadArchiveManifestHeader, "SHA256SUMS")
+			req.Header.Set(common.UploadArchivePreserveHeader, "ownership, xattrs")
+
+			rr := httptest.NewRecorder()
+			server := newServer()
+			server.ServeHTTP(rr, req)
+
+			Expect(rr.Code).To(Equal(http.StatusOK))
+			Expect(options).To(Equal(&importer.ArchiveOptions{Manifest: "SHA256SUMS", PreserveOwnership: true, PreserveXattrs: true}))
+			response := &ArchiveUploadResponse{}
+			Expect(json.Unmarshal(rr.Body.Bytes(), response)).To(Succeed())
+			Expect(response.Files).To(Equal(files))
+		})
+	})
+
+	It("Should reject an archive upload with invalid attributes to preserve", func() {
+		replaceArchiveProcessorFunc(func(io.ReadCloser, *importer.ArchiveOptions, *importer.ChecksumValidator, *uploadProgress) ([]importer.ExtractedFile, error) {
+			Fail("archive should not be processed")
+			return nil, nil
+		}, func() {
+			req, err := http.NewRequest(http.MethodPost, common.UploadArchivePath, strings.NewReader("data"))
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set(common.UploadArchivePreserveHeader, "acls")
+
+			rr := httptest.NewRecorder()
+			server := newServer()
+			server.ServeHTTP(rr, req)
+
+			Expect(rr.Code).To(Equal(http.StatusBadRequest))
+			Expect(server.uploading).To(BeFalse())
+		})
+	})
+
 	DescribeTable("Stream fail form", func(processorFunc func(func()), uploadPath string) {
 		processorFunc(func() {
 			req := newFormRequest(uploadPath)
@@ -434,6 +495,123 @@ var _ = Describe("Upload server tests", func() {
 			Fail("Timed out waiting for server to exit")
 		}
 	})
+
+	Context("with a checksum", func() {
+		const dataSHA256 = "sha256:3a6eb0790f39ac87c94f3856b2dd2c5d110e6811602261a9a923d3bb23adc8b7"
+
+		// validatingProcessor reads the stream through the validator like the upload data source
+		validatingProcessor := func(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string, dvContentType cdiv1.DataVolumeContentType, checksumValidator *importer.ChecksumValidator, progress *uploadProgress) (bool, error) {
+			defer stream.Close()
+			if checksumValidator == nil {
+				return false, nil
+			}
+			if _, err := io.Copy(io.Discard, checksumValidator.GetReader(stream)); err != nil {
+				return false, err
+			}
+			return false, checksumValidator.Validate()
+		}
+
+		DescribeTable("should validate the checksum of the upload", func(headers map[string]string, expectedStatus int, expectedChecksum string) {
+			replaceProcessorFunc(validatingProcessor, func() {
+				req, err := http.NewRequest(http.MethodPost, common.UploadPathSync, strings.NewReader("data"))
+				Expect(err).ToNot(HaveOccurred())
+				for key, value := range headers {
+					req.Header.Set(key, value)
+				}
+				rr := httptest.NewRecorder()
+
+				server := newServer()
+				server.ServeHTTP(rr, req)
+
+				Expect(rr.Code).To(Equal(expectedStatus))
+				Expect(server.done).To(Equal(expectedStatus == http.StatusOK))
+				Expect(server.uploading).To(BeFalse())
+				Expect(server.checksum).To(Equal(expectedChecksum))
+			})
+		},
+			Entry("without a checksum", map[string]string{}, http.StatusOK, ""),
+			Entry("of the upload token", map[string]string{common.UploadChecksumHeader: dataSHA256}, http.StatusOK, dataSHA256),
+			Entry("in a Content-Digest header", map[string]string{"Content-Digest": "sha-256=:Om6weQ85rIfJTzhWst0sXREOaBFgImGpqSPTuyOtyLc=:"}, http.StatusOK, dataSHA256),
+			Entry("in a Digest header", map[string]string{"Digest": "MD5=jXd/OF09/siBXSD3SWAm3A=="}, http.StatusOK, "md5:8d777f385d3dfec8815d20f7496026dc"),
+			Entry("of the upload token over the digest headers", map[string]string{
+				common.UploadChecksumHeader: dataSHA256,
+				"Content-Digest":            "md5=:AAAAAAAAAAAAAAAAAAAAAA==:",
+			}, http.StatusOK, dataSHA256),
+			Entry("not matching", map[string]string{common.UploadChecksumHeader: "sha256:" + strings.Repeat("0", 64)}, http.StatusBadRequest, ""),
+			Entry("invalid", map[string]string{common.UploadChecksumHeader: "sha256:invalid"}, http.StatusBadRequest, ""),
+			Entry("with an unsupported algorithm", map[string]string{"Content-Digest": "unixsum=:AAAA:"}, http.StatusBadRequest, ""),
+			Entry("of a clone", map[string]string{
+				common.UploadChecksumHeader:    dataSHA256,
+				common.UploadContentTypeHeader: common.BlockdeviceClone,
+			}, http.StatusBadRequest, ""),
+		)
+
+		It("should report the checksum in the result", func() {
+			replaceProcessorFunc(validatingProcessor, func() {
+				server := newServer()
+				server.config.Insecure = true
+				ch := make(chan *RunResult)
+				go func() {
+					defer GinkgoRecover()
+					result, err := server.Run()
+					Expect(err).ToNot(HaveOccurred())
+					ch <- result
+				}()
+
+				req, err := http.NewRequest(http.MethodPost, common.UploadPathSync, strings.NewReader("data"))
+				Expect(err).ToNot(HaveOccurred())
+				req.Header.Set(common.UploadChecksumHeader, dataSHA256)
+				rr := httptest.NewRecorder()
+				server.ServeHTTP(rr, req)
+				Expect(rr.Code).To(Equal(http.StatusOK))
+
+				Eventually(ch, 10*time.Second).Should(Receive(HaveField("Checksum", dataSHA256)))
+			})
+		})
+
+		It("should validate the checksum given when creating a resumable upload session", func() {
+			sessionProcessor := func(file, dest, imageSize string, filesystemOverhead float64, preallocation bool, checksumValidator *importer.ChecksumValidator, progress *uploadProgress) (bool, error) {
+				f, err := os.Open(file)
+				if err != nil {
+					return false, err
+				}
+				return validatingProcessor(f, dest, imageSize, filesystemOverhead, preallocation, "", cdiv1.DataVolumeKubeVirt, checksumValidator, progress)
+			}
+			replaceSessionProcessorFunc(sessionProcessor, func() {
+				server := newResumableServer(GinkgoT().TempDir())
+				rr := sendSessionRequest(server, http.MethodPost, common.UploadPathResumable, "", map[string]string{common.UploadChecksumHeader: "sha256:" + strings.Repeat("0", 64)})
+				Expect(rr.Code).To(Equal(http.StatusCreated))
+				location := rr.Header().Get("Location")
+				Expect(patchChunk(server, location, 0, "data").Code).To(Equal(http.StatusNoContent))
+
+				rr = sendSessionRequest(server, http.MethodPost, location, "", nil)
+				Expect(rr.Code).To(Equal(http.StatusBadRequest))
+				Expect(rr.Body.String()).To(ContainSubstring("checksum mismatch"))
+				Expect(server.done).To(BeFalse())
+				Expect(server.session).ToNot(BeNil())
+			})
+		})
+
+		It("should reject an invalid checksum when creating a resumable upload session", func() {
+			server := newResumableServer(GinkgoT().TempDir())
+			rr := sendSessionRequest(server, http.MethodPost, common.UploadPathResumable, "", map[string]string{"Digest": "SHA-256=invalid"})
+			Expect(rr.Code).To(Equal(http.StatusBadRequest))
+			Expect(server.session).To(BeNil())
+		})
+
+		It("should reject range uploads", func() {
+			server := newServer()
+			server.config.Destination = filepath.Join(GinkgoT().TempDir(), "disk.img")
+			req, err := http.NewRequest(http.MethodPut, common.UploadPathRange, strings.NewReader("data"))
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set("Content-Range", "bytes 0-3/4")
+			req.Header.Set(common.UploadChecksumHeader, dataSHA256)
+			rr := httptest.NewRecorder()
+			server.ServeHTTP(rr, req)
+			Expect(rr.Code).To(Equal(http.StatusBadRequest))
+			Expect(server.rangeUpload).To(BeNil())
+		})
+	})
 })
 
 func newFormRequest(path string) *http.Request {
@@ -456,3 +634,89 @@ func newFormRequest(path string) *http.Request {
 
 	return req
 }
+
+var _ = Describe("Clone processor", func() {
+	It("Should write the image of a sparse block device clone", func() {
+		const size = 4 * sparse.BlockSize
+		data := bytes.Repeat([]byte{1}, sparse.BlockSize)
+		buf := &bytes.Buffer{}
+		sbw := snappy.NewBufferedWriter(buf)
+		sw, err := sparse.NewWriter(sbw, size)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sw.WriteExtent(2*sparse.BlockSize, data)).To(Succeed())
+		Expect(sw.Close()).To(Succeed())
+		Expect(sbw.Close()).To(Succeed())
+
+		dest := filepath.Join(GinkgoT().TempDir(), "disk.img")
+		_, err = newUploadStreamProcessor(io.NopCloser(buf), dest, "", 0, false, common.BlockdeviceSparseClone, cdiv1.DataVolumeKubeVirt, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		expected := make([]byte, size)
+		copy(expected[2*sparse.BlockSize:], data)
+		written, err := os.ReadFile(dest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(written, expected)).To(BeTrue())
+	})
+
+	It("Should only write the changed blocks of a delta block device clone", func() {
+		const size = 4 * sparse.BlockSize
+		dest := filepath.Join(GinkgoT().TempDir(), "disk.img")
+		expected := bytes.Repeat([]byte{2}, size)
+		Expect(os.WriteFile(dest, expected, 0600)).To(Succeed())
+
+		data := bytes.Repeat([]byte{1}, sparse.BlockSize)
+		buf := &bytes.Buffer{}
+		sbw := snappy.NewBufferedWriter(buf)
+		sw, err := sparse.NewWriter(sbw, size)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sw.WriteExtent(sparse.BlockSize, data)).To(Succeed())
+		Expect(sw.Close()).To(Succeed())
+		Expect(sbw.Close()).To(Succeed())
+
+		_, err = newUploadStreamProcessor(io.NopCloser(buf), dest, "", 0, false, common.BlockdeviceDeltaClone, cdiv1.DataVolumeKubeVirt, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		copy(expected[sparse.BlockSize:], data)
+		written, err := os.ReadFile(dest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(written, expected)).To(BeTrue())
+	})
+
+	DescribeTable("Should transform the image of a clone only when a transformation is set", func(contentType string, transformation importer.CloneTransformation, expectTransform bool) {
+		var transformed bool
+		orig := cloneTransformProcessorFunc
+		cloneTransformProcessorFunc = func(stream io.ReadCloser, dest, imageSize string, filesystemOverhead float64, preallocation bool, contentType string, transformation importer.CloneTransformation, progress *uploadProgress) (bool, error) {
+			transformed = true
+			return true, nil
+		}
+		defer func() {
+			cloneTransformProcessorFunc = orig
+		}()
+		withProcessorSuccess(func() {
+			req, err := http.NewRequest(http.MethodPost, common.UploadPathSync, strings.NewReader("data"))
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set(common.UploadContentTypeHeader, contentType)
+			rr := httptest.NewRecorder()
+
+			server := newServer()
+			server.config.CloneTransformation = transformation
+			server.ServeHTTP(rr, req)
+
+			Expect(rr.Code).To(Equal(http.StatusOK))
+			Expect(transformed).To(Equal(expectTransform))
+			Expect(server.preallocationApplied).To(Equal(expectTransform))
+		})
+	},
+		Entry("resized block device clone", common.BlockdeviceClone, importer.CloneTransformation{Resize: true}, true),
+		Entry("sector size converted filesystem clone", common.FilesystemCloneContentType, importer.CloneTransformation{LogicalSectorSize: 4096}, true),
+		Entry("block device clone", common.BlockdeviceClone, importer.CloneTransformation{}, false),
+		Entry("upload", "", importer.CloneTransformation{Resize: true}, false),
+	)
+
+	It("Should not transform the image of a delta block device clone", func() {
+		dest := filepath.Join(GinkgoT().TempDir(), "disk.img")
+		_, err := newCloneTransformProcessor(io.NopCloser(&bytes.Buffer{}), dest, "", 0, false, common.BlockdeviceDeltaClone, importer.CloneTransformation{Resize: true}, &uploadProgress{})
+		Expect(err).To(MatchError("delta clones cannot be transformed"))
+		Expect(dest).ToNot(BeAnExistingFile())
+	})
+})
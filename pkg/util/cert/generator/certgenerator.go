@@ -15,7 +15,7 @@ import (
 // CertGenerator is an interface for creating certs
 type CertGenerator interface {
 	MakeClientCert(name string, groups []string, duration time.Duration) ([]byte, []byte, error)
-	MakeServerCert(namespace, service string, duration time.Duration) ([]byte, []byte, error)
+	MakeServerCert(namespace, service string, duration time.Duration, domains ...string) ([]byte, []byte, error)
 }
 
 // FetchCertGenerator fetches and generates certs
@@ -39,14 +39,17 @@ func (cg *FetchCertGenerator) MakeClientCert(name string, groups []string, durat
 	return certKeyPair.GetPEMBytes()
 }
 
-// MakeServerCert generates a server cert
-func (cg *FetchCertGenerator) MakeServerCert(namespace, service string, duration time.Duration) ([]byte, []byte, error) {
+// MakeServerCert generates a server cert, valid for the names of the service in the cluster and in each of domains
+func (cg *FetchCertGenerator) MakeServerCert(namespace, service string, duration time.Duration, domains ...string) ([]byte, []byte, error) {
 	ca, err := cg.getCA()
 	if err != nil {
 		return nil, nil, err
 	}
 
 	hostnames := sets.New(serviceToHostnames(namespace, service)...)
+	for _, domain := range domains {
+		hostnames.Insert(fmt.Sprintf("%s.%s.svc.%s", service, namespace, domain))
+	}
 	certKeyPair, err := ca.MakeServerCertForDuration(hostnames, duration)
 	if err != nil {
 		return nil, nil, err
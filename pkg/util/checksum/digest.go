@@ -0,0 +1,68 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checksum
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// digestAlgorithms maps the HTTP digest algorithm names to the names used by CDI, strongest first
+var digestAlgorithms = []struct {
+	name, algorithm string
+}{
+	{"sha-512", AlgorithmSHA512},
+	{"sha-256", AlgorithmSHA256},
+	// RFC 3230 names SHA-1 "SHA"
+	{"sha", AlgorithmSHA1},
+	{"md5", AlgorithmMD5},
+}
+
+// FromDigestHeader returns the strongest digest of a Content-Digest (RFC 9530) or Digest (RFC 3230) header value in
+// the "algorithm:hash" format, e.g. "sha-256=:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=:" is returned as
+// "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855".
+func FromDigestHeader(header string) (string, error) {
+	digests := map[string]string{}
+	for _, member := range strings.Split(header, ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(member), "=")
+		if !found {
+			return "", errors.Errorf("invalid digest %q", member)
+		}
+		// Content-Digest values are structured field byte sequences, enclosed in colons
+		digests[strings.ToLower(name)] = strings.TrimSuffix(strings.TrimPrefix(value, ":"), ":")
+	}
+
+	for _, digestAlgorithm := range digestAlgorithms {
+		value, ok := digests[digestAlgorithm.name]
+		if !ok {
+			continue
+		}
+		hash, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid %s digest %q", digestAlgorithm.name, value)
+		}
+		checksum := digestAlgorithm.algorithm + ":" + hex.EncodeToString(hash)
+		if _, _, err := ParseAndValidate(checksum); err != nil {
+			return "", err
+		}
+		return checksum, nil
+	}
+	return "", errors.Errorf("no supported algorithm in digest %q: supported algorithms are: sha-512, sha-256, sha, md5", header)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checksum
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	// Digests of the empty string
+	testSHA256Digest = "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+	testMD5Digest    = "1B2M2Y8AsgTpgAmY7PhCfg=="
+)
+
+var _ = Describe("FromDigestHeader", func() {
+	DescribeTable("should return the checksum of the digest", func(header, want string) {
+		checksum, err := FromDigestHeader(header)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checksum).To(Equal(want))
+	},
+		Entry("in Content-Digest format", "sha-256=:"+testSHA256Digest+":", "sha256:"+testSHA256),
+		Entry("in Digest format", "SHA-256="+testSHA256Digest, "sha256:"+testSHA256),
+		Entry("with the strongest algorithm", "md5=:"+testMD5Digest+":, sha-256=:"+testSHA256Digest+":", "sha256:"+testSHA256),
+		Entry("with unsupported algorithms", "unixsum=30637, MD5="+testMD5Digest, "md5:"+testMD5),
+	)
+
+	DescribeTable("should fail", func(header, wantErr string) {
+		_, err := FromDigestHeader(header)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(wantErr))
+	},
+		Entry("without a value", "sha-256", "invalid digest"),
+		Entry("with an invalid encoding", "sha-256=:not base64:", "invalid sha-256 digest"),
+		Entry("with a wrong length", "sha-256=:"+testMD5Digest+":", "invalid sha256 hash length"),
+		Entry("without a supported algorithm", "unixsum=30637", "no supported algorithm"),
+	)
+})
@@ -0,0 +1,220 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checksum
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/clearsign"
+)
+
+const (
+	pgpSignedMessageHeader = "-----BEGIN PGP SIGNED MESSAGE-----"
+	pgpSignatureHeader     = "-----BEGIN PGP SIGNATURE-----"
+
+	// maxChecksumFileLineLength limits the length of a single line of a checksum file
+	maxChecksumFileLineLength = 64 * 1024
+)
+
+var (
+	// bsdChecksumLineRegex matches lines like "SHA256 (disk.img) = <hash>" as written by BSD tools and coreutils --tag.
+	bsdChecksumLineRegex = regexp.MustCompile(`^([A-Za-z0-9-]+) ?\((.+)\) ?= ?([0-9a-fA-F]+)$`)
+	// gnuChecksumLineRegex matches lines like "<hash>  disk.img" or "<hash> *disk.img" as written by coreutils.
+	gnuChecksumLineRegex = regexp.MustCompile(`^(\\?)([0-9a-fA-F]+) [ *](.+)$`)
+)
+
+// ErrFileNotListed is returned when a checksum file doesn't contain a checksum for the requested file.
+var ErrFileNotListed = errors.New("file not listed in checksum file")
+
+// ErrUnverifiedSignature is returned for a signed checksum file when there is no keyring to verify its signature.
+var ErrUnverifiedSignature = errors.New("checksum file is signed, but no keyring is configured to verify the signature")
+
+// ReadKeyring reads the armored PGP public keys trusted to sign checksum files.
+func ReadKeyring(r io.Reader) (openpgp.EntityList, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read PGP keyring")
+	}
+	return keyring, nil
+}
+
+// FindInChecksumFile returns the checksum of the passed in file from a checksum file in BSD or GNU
+// coreutils format, like SHA256SUMS or CHECKSUM files published by distributions. The result is
+// in "algorithm:hash" format. With a keyring the file must be clearsigned by one of its keys, without
+// one signed files are rejected since their signature can't be verified.
+// Entries are matched on the exact file name first, and on the base name of the entry second.
+func FindInChecksumFile(r io.Reader, fileName string, keyring openpgp.EntityList) (string, error) {
+	content, err := verifiedContent(r, keyring)
+	if err != nil {
+		return "", err
+	}
+	var exactMatch, baseNameMatch string
+	err = scanChecksumFile(bytes.NewReader(content), func(name, checksum string) bool {
+		if strings.TrimPrefix(name, "./") == fileName {
+			exactMatch = checksum
+			return false
+		}
+		if baseNameMatch == "" && path.Base(name) == fileName {
+			baseNameMatch = checksum
+		}
+		return true
+	})
+	if err != nil {
+		return "", err
+	}
+	if exactMatch != "" {
+		return exactMatch, nil
+	}
+	if baseNameMatch == "" {
+		return "", errors.Wrapf(ErrFileNotListed, "no checksum found for %q", fileName)
+	}
+	return baseNameMatch, nil
+}
+
+// ParseChecksumFile returns the checksums in "algorithm:hash" format of all the files listed in a
+// checksum file, keyed by their cleaned relative path. A file listed twice is rejected, and so are
+// signed files since there is no keyring to verify them.
+func ParseChecksumFile(r io.Reader) (map[string]string, error) {
+	content, err := verifiedContent(r, nil)
+	if err != nil {
+		return nil, err
+	}
+	checksums := map[string]string{}
+	var duplicate string
+	err = scanChecksumFile(bytes.NewReader(content), func(name, checksum string) bool {
+		name = path.Clean(strings.TrimPrefix(name, "./"))
+		if _, ok := checksums[name]; ok {
+			duplicate = name
+			return false
+		}
+		checksums[name] = checksum
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if duplicate != "" {
+		return nil, errors.Errorf("%q is listed more than once in checksum file", duplicate)
+	}
+	return checksums, nil
+}
+
+// verifiedContent reads a checksum file. With a keyring the file must be clearsigned by one of its keys and the
+// signed text is returned, without one signed files are rejected.
+func verifiedContent(r io.Reader, keyring openpgp.EntityList) ([]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read checksum file")
+	}
+	if len(keyring) == 0 {
+		if bytes.Contains(data, []byte(pgpSignedMessageHeader)) || bytes.Contains(data, []byte(pgpSignatureHeader)) {
+			return nil, ErrUnverifiedSignature
+		}
+		return data, nil
+	}
+	block, _ := clearsign.Decode(data)
+	if block == nil {
+		return nil, errors.New("checksum file is not clearsigned")
+	}
+	if _, err := openpgp.CheckDetachedSignature(keyring, bytes.NewReader(block.Bytes), block.ArmoredSignature.Body); err != nil {
+		return nil, errors.Wrap(err, "invalid checksum file signature")
+	}
+	return block.Plaintext, nil
+}
+
+// scanChecksumFile calls fn with the file name and checksum of each entry of a checksum file, until fn returns false.
+func scanChecksumFile(r io.Reader, fn func(name, checksum string) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxChecksumFileLineLength)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		name, checksum, ok := parseChecksumLine(line)
+		if !ok {
+			continue
+		}
+		if !fn(name, checksum) {
+			return nil
+		}
+	}
+	return errors.Wrap(scanner.Err(), "unable to read checksum file")
+}
+
+// parseChecksumLine returns the file name and the checksum in "algorithm:hash" format of a checksum
+// file line, or false if the line is not a valid checksum line.
+func parseChecksumLine(line string) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", false
+	}
+
+	var algorithm, name, hash string
+	if m := bsdChecksumLineRegex.FindStringSubmatch(line); m != nil {
+		algorithm = normalizeAlgorithm(m[1])
+		name = m[2]
+		hash = m[3]
+	} else if m := gnuChecksumLineRegex.FindStringSubmatch(line); m != nil {
+		hash = m[2]
+		name = m[3]
+		if m[1] != "" {
+			// File names containing backslashes or newlines are escaped
+			name = strings.NewReplacer(`\\`, `\`, `\n`, "\n").Replace(name)
+		}
+		algorithm = algorithmForHashLength(len(hash))
+	} else {
+		return "", "", false
+	}
+
+	checksum := algorithm + ":" + hash
+	if _, _, err := ParseAndValidate(checksum); err != nil {
+		return "", "", false
+	}
+	return name, strings.ToLower(checksum), true
+}
+
+// normalizeAlgorithm maps BSD and OpenSSL style algorithm names, e.g. "SHA256" or "SHA2-256" to the names used by CDI.
+func normalizeAlgorithm(algorithm string) string {
+	algorithm = strings.ToLower(strings.ReplaceAll(algorithm, "-", ""))
+	switch algorithm {
+	case "sha2256":
+		return AlgorithmSHA256
+	case "sha2512":
+		return AlgorithmSHA512
+	}
+	return algorithm
+}
+
+// algorithmForHashLength guesses the algorithm of a GNU coreutils style entry, which doesn't name it.
+func algorithmForHashLength(length int) string {
+	switch length {
+	case 32:
+		return AlgorithmMD5
+	case 40:
+		return AlgorithmSHA1
+	case 64:
+		return AlgorithmSHA256
+	case 128:
+		return AlgorithmSHA512
+	}
+	return ""
+}
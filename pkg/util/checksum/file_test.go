@@ -0,0 +1,177 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checksum
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/openpgp/clearsign"
+)
+
+const (
+	testSHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	testMD5    = "d41d8cd98f00b204e9800998ecf8427e"
+	otherSHA   = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+)
+
+var _ = Describe("FindInChecksumFile", func() {
+	DescribeTable("should find the checksum of the file", func(content, fileName, want string) {
+		checksum, err := FindInChecksumFile(strings.NewReader(content), fileName, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checksum).To(Equal(want))
+	},
+		Entry("in GNU format",
+			otherSHA+"  other.img\n"+testSHA256+"  disk.img\n", "disk.img", "sha256:"+testSHA256),
+		Entry("in GNU binary mode format",
+			testSHA256+" *disk.img\n", "disk.img", "sha256:"+testSHA256),
+		Entry("in GNU format with md5 hash",
+			testMD5+"  disk.img\n", "disk.img", "md5:"+testMD5),
+		Entry("in GNU format with uppercase hash and CRLF line endings",
+			strings.ToUpper(testSHA256)+"  disk.img\r\n", "disk.img", "sha256:"+testSHA256),
+		Entry("in GNU format with escaped file name",
+			"\\"+testSHA256+"  dir\\\\disk.img\n", "dir\\disk.img", "sha256:"+testSHA256),
+		Entry("in BSD format",
+			"SHA256 (other.img) = "+otherSHA+"\nSHA256 (disk.img) = "+testSHA256+"\n", "disk.img", "sha256:"+testSHA256),
+		Entry("in OpenSSL format",
+			"SHA2-256(disk.img)= "+testSHA256+"\n", "disk.img", "sha256:"+testSHA256),
+		Entry("with comments",
+			"# disk.img: 1024 bytes\nSHA256 (disk.img) = "+testSHA256+"\n", "disk.img", "sha256:"+testSHA256),
+		Entry("with a leading ./",
+			testSHA256+"  ./disk.img\n", "disk.img", "sha256:"+testSHA256),
+		Entry("in a subdirectory",
+			testSHA256+"  images/disk.img\n", "disk.img", "sha256:"+testSHA256),
+		Entry("preferring the exact name",
+			otherSHA+"  images/disk.img\n"+testSHA256+"  disk.img\n", "disk.img", "sha256:"+testSHA256),
+	)
+
+	DescribeTable("should fail", func(content, fileName string) {
+		_, err := FindInChecksumFile(strings.NewReader(content), fileName, nil)
+		Expect(err).To(MatchError(ErrFileNotListed))
+	},
+		Entry("when the file is not listed", otherSHA+"  other.img\n", "disk.img"),
+		Entry("when the file is only listed with an unsupported algorithm", "SHA384 (disk.img) = "+otherSHA+"\n", "disk.img"),
+		Entry("when the hash has an unexpected length", "abc123  disk.img\n", "disk.img"),
+		Entry("when the file is empty", "", "disk.img"),
+	)
+
+	DescribeTable("should reject a signed file without a keyring", func(content string) {
+		_, err := FindInChecksumFile(strings.NewReader(content), "disk.img", nil)
+		Expect(err).To(MatchError(ErrUnverifiedSignature))
+	},
+		Entry("when it is clearsigned",
+			"-----BEGIN PGP SIGNED MESSAGE-----\nHash: SHA256\n\n"+
+				"SHA256 (disk.img) = "+testSHA256+"\n"+
+				"-----BEGIN PGP SIGNATURE-----\n\niQIzBAEBCAAdFiEE\n-----END PGP SIGNATURE-----\n"),
+		Entry("when the file is only listed in the signature", "-----BEGIN PGP SIGNATURE-----\n"+testSHA256+"  disk.img\n-----END PGP SIGNATURE-----\n"),
+	)
+
+	Context("with a keyring", func() {
+		const content = "# " + otherSHA + "  disk.img\n" + "SHA256 (disk.img) = " + testSHA256 + "\n"
+		var (
+			signer  *openpgp.Entity
+			keyring openpgp.EntityList
+		)
+
+		newEntity := func() *openpgp.Entity {
+			entity, err := openpgp.NewEntity("test", "", "test@example.com", nil)
+			Expect(err).NotTo(HaveOccurred())
+			return entity
+		}
+
+		clearsigned := func(entity *openpgp.Entity, text string) string {
+			var buf bytes.Buffer
+			w, err := clearsign.Encode(&buf, entity.PrivateKey, nil)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write([]byte(text))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(w.Close()).To(Succeed())
+			return buf.String()
+		}
+
+		BeforeEach(func() {
+			signer = newEntity()
+			var buf bytes.Buffer
+			w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(signer.Serialize(w)).To(Succeed())
+			Expect(w.Close()).To(Succeed())
+			keyring, err = ReadKeyring(&buf)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keyring).To(HaveLen(1))
+		})
+
+		It("should find the checksum in a file signed by a key of the keyring", func() {
+			checksum, err := FindInChecksumFile(strings.NewReader(clearsigned(signer, content)), "disk.img", keyring)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(checksum).To(Equal("sha256:" + testSHA256))
+		})
+
+		It("should reject a file signed by another key", func() {
+			_, err := FindInChecksumFile(strings.NewReader(clearsigned(newEntity(), content)), "disk.img", keyring)
+			Expect(err).To(MatchError(ContainSubstring("invalid checksum file signature")))
+		})
+
+		It("should reject a file modified after it was signed", func() {
+			signed := strings.Replace(clearsigned(signer, content), testSHA256, otherSHA, 1)
+			_, err := FindInChecksumFile(strings.NewReader(signed), "disk.img", keyring)
+			Expect(err).To(MatchError(ContainSubstring("invalid checksum file signature")))
+		})
+
+		It("should reject a file which is not signed", func() {
+			_, err := FindInChecksumFile(strings.NewReader(content), "disk.img", keyring)
+			Expect(err).To(MatchError(ContainSubstring("not clearsigned")))
+		})
+
+		It("should ignore the text around the signed message", func() {
+			signed := testSHA256 + "  other.img\n" + clearsigned(signer, content) + otherSHA + "  disk.img\n"
+			checksum, err := FindInChecksumFile(strings.NewReader(signed), "disk.img", keyring)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(checksum).To(Equal("sha256:" + testSHA256))
+		})
+	})
+})
+
+var _ = Describe("ParseChecksumFile", func() {
+	It("should return the checksums of all the files", func() {
+		content := testSHA256 + "  ./images/disk.img\n# comment\n" + "MD5 (README) = " + testMD5 + "\n"
+		checksums, err := ParseChecksumFile(strings.NewReader(content))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checksums).To(Equal(map[string]string{
+			"images/disk.img": "sha256:" + testSHA256,
+			"README":          "md5:" + testMD5,
+		}))
+	})
+
+	It("should reject a signed file", func() {
+		content := "-----BEGIN PGP SIGNED MESSAGE-----\nHash: SHA256\n\n" + testSHA256 + "  disk.img\n" +
+			"-----BEGIN PGP SIGNATURE-----\n\niQIzBAEBCAAdFiEE\n-----END PGP SIGNATURE-----\n"
+		_, err := ParseChecksumFile(strings.NewReader(content))
+		Expect(err).To(MatchError(ErrUnverifiedSignature))
+	})
+
+	It("should reject a file listed twice", func() {
+		content := testSHA256 + "  disk.img\n" + otherSHA + "  ./disk.img\n"
+		_, err := ParseChecksumFile(strings.NewReader(content))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,643 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package disklayout reads the partition table or the filesystem at the start of raw disk images, to find how much of
+// the disk holds data and to rewrite GPT partition tables for another logical sector size or disk size.
+//
+// A disk is only considered safe to shrink when its whole layout is known: the ext2/3/4 and XFS filesystems and the
+// LVM physical volumes record their size, which must fit in the disk or in the partition holding them, and the part
+// of the disk after the partitions or the filesystem must not hold any data.
+//
+// GPT partition tables are found at the second logical sector of the disk, which tells the logical sector size they
+// were written for. MBR partition tables don't tell it, their partitions are assumed to use the largest sector size
+// which fits the disk.
+//
+// Rewriting a GPT partition table for another logical sector size does not touch the partitions, so it is refused when
+// they hold a filesystem whose own sectors or blocks are smaller than the new logical sector size, or a BIOS boot
+// partition, whose boot loader addresses the disk in sectors of the size it was installed for.
+package disklayout
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// SectorSize512 is the logical sector size of 512n and 512e disks
+	SectorSize512 = 512
+	// SectorSize4K is the logical sector size of 4Kn disks
+	SectorSize4K = 4096
+
+	mbrSize             = 512
+	mbrPartitionsOffset = 446
+	mbrPartitionSize    = 16
+	mbrPartitions       = 4
+	mbrTypeGPT          = 0xee
+
+	gptSignature     = "EFI PART"
+	gptHeaderSize    = 92
+	gptMinEntrySize  = 128
+	gptMaxEntries    = 1024 * 1024
+	gptEntryStartLBA = 32
+	gptEntryEndLBA   = 40
+
+	extSuperblockOffset = 1024
+	extMagic            = 0xef53
+	extFeature64Bit     = 0x80
+	xfsMagic            = "XFSB"
+	xfsSectorSizeOffset = 102
+	fatBytesPerSector   = 11
+
+	lvmLabel        = "LABEL ONE"
+	lvmType         = "LVM2 001"
+	lvmLabelSectors = 4
+
+	// zeroCheckSize is the size of the reads checking that the end of a disk holds no data
+	zeroCheckSize = 1024 * 1024
+)
+
+var (
+	// ErrUnknownLayout is returned when the disk has no known partition table or filesystem
+	ErrUnknownLayout = errors.New("unknown disk layout")
+	// ErrNoGPT is returned when rewriting the GPT partition table of a disk which has none
+	ErrNoGPT = errors.New("no GPT partition table")
+	// ErrUnsafeLayout is returned when the disk holds data which the partition table or the filesystem don't account for
+	ErrUnsafeLayout = errors.New("disk layout not safe to shrink")
+	// ErrIncompatibleSectorSize is returned when the content of the disk can't be used with another logical sector size
+	ErrIncompatibleSectorSize = errors.New("disk content not compatible with the logical sector size")
+
+	sectorSizes = []int64{SectorSize512, SectorSize4K}
+
+	// biosBootPartitionType is the GPT partition type GUID 21686148-6449-6E6F-744E-656564454649, in its mixed endian
+	// encoding
+	biosBootPartitionType = []byte{0x48, 0x61, 0x68, 0x21, 0x49, 0x64, 0x6f, 0x6e, 0x74, 0x4e, 0x65, 0x65, 0x64, 0x45, 0x46, 0x49}
+)
+
+// File is a disk image or block device which can be read and written at any offset
+type File interface {
+	io.ReaderAt
+	io.WriterAt
+}
+
+// gpt is a GPT partition table, read from its primary header
+type gpt struct {
+	sectorSize int64
+	header     []byte
+	entries    []byte
+}
+
+func (g *gpt) uint64At(offset int) uint64 {
+	return binary.LittleEndian.Uint64(g.header[offset:])
+}
+
+func (g *gpt) entrySize() int {
+	return int(binary.LittleEndian.Uint32(g.header[84:]))
+}
+
+// entriesSectors returns the number of sectors of size sectorSize holding the partition entries
+func (g *gpt) entriesSectors(sectorSize int64) int64 {
+	return (int64(len(g.entries)) + sectorSize - 1) / sectorSize
+}
+
+// forEachPartition calls fn with the index, the first and the last sector of the partitions in use
+func (g *gpt) forEachPartition(fn func(i int, first, last uint64) error) error {
+	size := g.entrySize()
+	for i := 0; i*size < len(g.entries); i++ {
+		entry := g.entries[i*size : (i+1)*size]
+		if bytes.Equal(entry[:16], make([]byte, 16)) {
+			continue
+		}
+		first := binary.LittleEndian.Uint64(entry[gptEntryStartLBA:])
+		last := binary.LittleEndian.Uint64(entry[gptEntryEndLBA:])
+		if last < first {
+			return errors.Errorf("invalid GPT partition %d", i+1)
+		}
+		if err := fn(i, first, last); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// partitionsBounds returns the offset of the first partition and the end of the last one, in bytes
+func (g *gpt) partitionsBounds() (int64, int64, error) {
+	start, end := int64(-1), int64(0)
+	err := g.forEachPartition(func(_ int, first, last uint64) error {
+		if s := int64(first) * g.sectorSize; start < 0 || s < start {
+			start = s
+		}
+		if e := int64(last+1) * g.sectorSize; e > end {
+			end = e
+		}
+		return nil
+	})
+	return start, end, err
+}
+
+// checkSectorSize returns ErrIncompatibleSectorSize if a partition can't be used with sectorSize logical sectors
+func (g *gpt) checkSectorSize(r io.ReaderAt, sectorSize int64) error {
+	size := g.entrySize()
+	return g.forEachPartition(func(i int, first, last uint64) error {
+		if bytes.Equal(g.entries[i*size:i*size+16], biosBootPartitionType) {
+			return errors.Wrapf(ErrIncompatibleSectorSize, "partition %d is a BIOS boot partition", i+1)
+		}
+		start, end := int64(first)*g.sectorSize, int64(last+1)*g.sectorSize
+		if err := CheckSectorSize(io.NewSectionReader(r, start, end-start), sectorSize); err != nil {
+			return errors.Wrapf(err, "partition %d", i+1)
+		}
+		return nil
+	})
+}
+
+// readAt reads len(b) bytes at offset, it returns false if the disk is too small
+func readAt(r io.ReaderAt, b []byte, offset int64) (bool, error) {
+	n, err := r.ReadAt(b, offset)
+	if n == len(b) {
+		return true, nil
+	}
+	if errors.Is(err, io.EOF) {
+		return false, nil
+	}
+	return false, errors.Wrapf(err, "error reading disk at %d", offset)
+}
+
+func checksum(b []byte, crcOffset int) uint32 {
+	c := make([]byte, len(b))
+	copy(c, b)
+	binary.LittleEndian.PutUint32(c[crcOffset:], 0)
+	return crc32.ChecksumIEEE(c)
+}
+
+// readGPT reads the GPT partition table written for sectorSize, it returns nil if there is none
+func readGPT(r io.ReaderAt, sectorSize int64) (*gpt, error) {
+	header := make([]byte, gptHeaderSize)
+	if ok, err := readAt(r, header, sectorSize); !ok || err != nil {
+		return nil, err
+	}
+	if string(header[:len(gptSignature)]) != gptSignature {
+		return nil, nil
+	}
+
+	headerSize := int64(binary.LittleEndian.Uint32(header[12:]))
+	if headerSize < gptHeaderSize || headerSize > sectorSize {
+		return nil, errors.Errorf("invalid GPT header size %d", headerSize)
+	}
+	header = make([]byte, headerSize)
+	if ok, err := readAt(r, header, sectorSize); !ok || err != nil {
+		return nil, errors.Errorf("truncated GPT header")
+	}
+	if checksum(header, 16) != binary.LittleEndian.Uint32(header[16:]) {
+		return nil, errors.Errorf("invalid GPT header checksum")
+	}
+
+	g := &gpt{sectorSize: sectorSize, header: header}
+	numEntries := int64(binary.LittleEndian.Uint32(header[80:]))
+	entrySize := int64(g.entrySize())
+	if entrySize < gptMinEntrySize || entrySize%8 != 0 || numEntries*entrySize > gptMaxEntries {
+		return nil, errors.Errorf("invalid GPT partition entries, %d of size %d", numEntries, entrySize)
+	}
+	g.entries = make([]byte, numEntries*entrySize)
+	if ok, err := readAt(r, g.entries, int64(g.uint64At(72))*sectorSize); !ok || err != nil {
+		return nil, errors.Errorf("truncated GPT partition entries")
+	}
+	if crc32.ChecksumIEEE(g.entries) != binary.LittleEndian.Uint32(header[88:]) {
+		return nil, errors.Errorf("invalid GPT partition entries checksum")
+	}
+	return g, nil
+}
+
+// findGPT returns the GPT partition table of the disk, or nil if there is none
+func findGPT(r io.ReaderAt) (*gpt, error) {
+	for _, sectorSize := range sectorSizes {
+		g, err := readGPT(r, sectorSize)
+		if g != nil || err != nil {
+			return g, err
+		}
+	}
+	return nil, nil
+}
+
+// GPTSectorSize returns the logical sector size the GPT partition table of the disk was written for, or 0 if it has
+// no GPT partition table
+func GPTSectorSize(r io.ReaderAt) (int64, error) {
+	g, err := findGPT(r)
+	if g == nil || err != nil {
+		return 0, err
+	}
+	return g.sectorSize, nil
+}
+
+// readMBRPartitions returns the first sector and the number of sectors of the partitions of the MBR partition table, or
+// nil if the disk has no MBR partition table
+func readMBRPartitions(r io.ReaderAt) ([][2]int64, error) {
+	mbr := make([]byte, mbrSize)
+	if ok, err := readAt(r, mbr, 0); !ok || err != nil {
+		return nil, err
+	}
+	if mbr[mbrSize-2] != 0x55 || mbr[mbrSize-1] != 0xaa {
+		return nil, nil
+	}
+	var partitions [][2]int64
+	for i := 0; i < mbrPartitions; i++ {
+		entry := mbr[mbrPartitionsOffset+i*mbrPartitionSize:]
+		// boot sectors of filesystems have the signature too, but not the status of partitions
+		if entry[0] != 0 && entry[0] != 0x80 {
+			return nil, nil
+		}
+		if entry[4] == mbrTypeGPT {
+			return nil, errors.New("protective MBR without GPT partition table")
+		}
+		first := int64(binary.LittleEndian.Uint32(entry[8:]))
+		sectors := int64(binary.LittleEndian.Uint32(entry[12:]))
+		if entry[4] != 0 && sectors > 0 {
+			partitions = append(partitions, [2]int64{first, sectors})
+		}
+	}
+	return partitions, nil
+}
+
+// HasMBRPartitions returns true if the disk has an MBR partition table with partitions
+func HasMBRPartitions(r io.ReaderAt) (bool, error) {
+	partitions, err := readMBRPartitions(r)
+	return len(partitions) > 0, err
+}
+
+// contentSize returns the size of the ext2/3/4 or XFS filesystem or of the LVM physical volume at the start of the disk
+// or partition, or 0 if there is none
+func contentSize(r io.ReaderAt) (int64, error) {
+	size, err := filesystemSize(r)
+	if size > 0 || err != nil {
+		return size, err
+	}
+	return lvmSize(r)
+}
+
+// lvmSize returns the size of the LVM physical volume at the start of the disk, or 0 if there is none. The label is in
+// one of the first four sectors, and points to the header of the physical volume, holding its size after its UUID.
+func lvmSize(r io.ReaderAt) (int64, error) {
+	label := make([]byte, SectorSize512)
+	for i := int64(0); i < lvmLabelSectors; i++ {
+		if ok, err := readAt(r, label, i*SectorSize512); !ok || err != nil {
+			return 0, err
+		}
+		if string(label[:len(lvmLabel)]) != lvmLabel || string(label[24:24+len(lvmType)]) != lvmType {
+			continue
+		}
+		offset := int64(binary.LittleEndian.Uint32(label[20:]))
+		if offset+40 > SectorSize512 {
+			return 0, errors.Errorf("invalid LVM label")
+		}
+		size := binary.LittleEndian.Uint64(label[offset+32:])
+		if size == 0 || size > math.MaxInt64 {
+			return 0, errors.Errorf("invalid LVM physical volume size %d", size)
+		}
+		return int64(size), nil
+	}
+	return 0, nil
+}
+
+// filesystemSize returns the size of the ext2/3/4 or XFS filesystem at the start of the disk, or 0 if there is none
+func filesystemSize(r io.ReaderAt) (int64, error) {
+	sb := make([]byte, 1024)
+	if ok, err := readAt(r, sb, extSuperblockOffset); err != nil {
+		return 0, err
+	} else if ok && binary.LittleEndian.Uint16(sb[56:]) == extMagic {
+		blocks := uint64(binary.LittleEndian.Uint32(sb[4:]))
+		if binary.LittleEndian.Uint32(sb[96:])&extFeature64Bit != 0 {
+			blocks |= uint64(binary.LittleEndian.Uint32(sb[0x150:])) << 32
+		}
+		return int64(blocks) * (1024 << binary.LittleEndian.Uint32(sb[24:])), nil
+	}
+
+	if ok, err := readAt(r, sb[:16], 0); err != nil {
+		return 0, err
+	} else if ok && string(sb[:len(xfsMagic)]) == xfsMagic {
+		return int64(binary.BigEndian.Uint64(sb[8:])) * int64(binary.BigEndian.Uint32(sb[4:])), nil
+	}
+	return 0, nil
+}
+
+// filesystemSectorSize returns the name and the sector or block size of the ext2/3/4, XFS or FAT filesystem at the
+// start of the disk, or 0 if there is none. The filesystem can't be used on disks with larger logical sectors.
+func filesystemSectorSize(r io.ReaderAt) (string, int64, error) {
+	sb := make([]byte, 1024)
+	if ok, err := readAt(r, sb, extSuperblockOffset); err != nil {
+		return "", 0, err
+	} else if ok && binary.LittleEndian.Uint16(sb[56:]) == extMagic {
+		return "ext", int64(1024) << binary.LittleEndian.Uint32(sb[24:]), nil
+	}
+
+	if ok, err := readAt(r, sb[:mbrSize], 0); err != nil || !ok {
+		return "", 0, err
+	}
+	if string(sb[:len(xfsMagic)]) == xfsMagic {
+		return "XFS", int64(binary.BigEndian.Uint16(sb[xfsSectorSizeOffset:])), nil
+	}
+	// the boot sector of FAT filesystems starts with a jump instruction and names the FAT type at offset 54, or 82 for
+	// FAT32
+	if sb[mbrSize-2] == 0x55 && sb[mbrSize-1] == 0xaa && (sb[0] == 0xeb || sb[0] == 0xe9) &&
+		(bytes.HasPrefix(sb[54:], []byte("FAT")) || bytes.HasPrefix(sb[82:], []byte("FAT"))) {
+		return "FAT", int64(binary.LittleEndian.Uint16(sb[fatBytesPerSector:])), nil
+	}
+	return "", 0, nil
+}
+
+// CheckSectorSize returns ErrIncompatibleSectorSize if the filesystem at the start of the disk or partition uses
+// sectors or blocks smaller than sectorSize
+func CheckSectorSize(r io.ReaderAt, sectorSize int64) error {
+	name, size, err := filesystemSectorSize(r)
+	if err != nil || name == "" {
+		return err
+	}
+	if size < sectorSize {
+		return errors.Wrapf(ErrIncompatibleSectorSize, "%s filesystem with %d bytes sectors", name, size)
+	}
+	return nil
+}
+
+// UsedSize returns the size the disk of size bytes can be shrunk to without losing its partitions, or its filesystem or
+// LVM physical volume when it has no partition table. ErrUnknownLayout is returned when the disk has none of them, and
+// ErrUnsafeLayout when a partition holds a filesystem or physical volume larger than itself, or when the disk holds
+// data after its partitions, filesystem or physical volume.
+func UsedSize(r io.ReaderAt, size int64) (int64, error) {
+	g, err := findGPT(r)
+	if err != nil {
+		return 0, err
+	}
+	if g != nil {
+		return gptUsedSize(r, g, size)
+	}
+
+	partitions, err := readMBRPartitions(r)
+	if err != nil {
+		return 0, err
+	}
+	if len(partitions) > 0 {
+		return mbrUsedSize(r, partitions, size)
+	}
+
+	used, err := contentSize(r)
+	if err != nil {
+		return 0, err
+	}
+	if used == 0 {
+		return 0, ErrUnknownLayout
+	}
+	if err := checkZero(r, used, size); err != nil {
+		return 0, err
+	}
+	return used, nil
+}
+
+func gptUsedSize(r io.ReaderAt, g *gpt, size int64) (int64, error) {
+	err := g.forEachPartition(func(i int, first, last uint64) error {
+		return checkPartition(r, i, int64(first)*g.sectorSize, int64(last+1)*g.sectorSize)
+	})
+	if err != nil {
+		return 0, err
+	}
+	_, used, err := g.partitionsBounds()
+	if err != nil {
+		return 0, err
+	}
+	// room for the primary and backup tables
+	tables := (g.entriesSectors(g.sectorSize) + 1) * g.sectorSize
+	used = max(used, g.sectorSize+tables)
+	// the backup table is written again at the end of the shrunk disk
+	backupStart := int64(g.uint64At(48)+1) * g.sectorSize
+	backupEnd := int64(g.uint64At(32)+1) * g.sectorSize
+	if backupStart < used || backupEnd < backupStart {
+		backupStart, backupEnd = size, size
+	}
+	if err := checkZero(r, used, backupStart); err != nil {
+		return 0, err
+	}
+	if err := checkZero(r, backupEnd, size); err != nil {
+		return 0, err
+	}
+	return used + tables, nil
+}
+
+func mbrUsedSize(r io.ReaderAt, partitions [][2]int64, size int64) (int64, error) {
+	var end int64
+	for _, p := range partitions {
+		end = max(end, p[0]+p[1])
+	}
+	// the sector size is not known, keep the largest one the partitions fit in
+	sectorSize := sectorSizes[0]
+	for i := len(sectorSizes) - 1; i > 0; i-- {
+		if end*sectorSizes[i] <= size {
+			sectorSize = sectorSizes[i]
+			break
+		}
+	}
+	for i, p := range partitions {
+		if err := checkPartition(r, i, p[0]*sectorSize, (p[0]+p[1])*sectorSize); err != nil {
+			return 0, err
+		}
+	}
+	used := end * sectorSize
+	if err := checkZero(r, used, size); err != nil {
+		return 0, err
+	}
+	return used, nil
+}
+
+// checkPartition returns ErrUnsafeLayout if the filesystem or physical volume of the partition is larger than it
+func checkPartition(r io.ReaderAt, i int, start, end int64) error {
+	content, err := contentSize(io.NewSectionReader(r, start, end-start))
+	if err != nil {
+		return errors.Wrapf(err, "partition %d", i+1)
+	}
+	if content > end-start {
+		return errors.Wrapf(ErrUnsafeLayout, "partition %d of %d bytes holds %d bytes of data", i+1, end-start, content)
+	}
+	return nil
+}
+
+// checkZero returns ErrUnsafeLayout if the disk holds data between start and end
+func checkZero(r io.ReaderAt, start, end int64) error {
+	buf := make([]byte, zeroCheckSize)
+	zero := make([]byte, zeroCheckSize)
+	for offset := nextData(r, start); offset < end; offset = nextData(r, offset) {
+		n := min(int64(len(buf)), end-offset)
+		read, err := r.ReadAt(buf[:n], offset)
+		if !bytes.Equal(buf[:read], zero[:read]) {
+			return errors.Wrapf(ErrUnsafeLayout, "data found at offset %d after the partitions or the filesystem", offset)
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "error reading disk at %d", offset)
+		}
+		offset += n
+	}
+	return nil
+}
+
+// nextData returns the offset of the next data of the disk from offset, skipping the holes of sparse files
+func nextData(r io.ReaderAt, offset int64) int64 {
+	f, ok := r.(interface{ Fd() uintptr })
+	if !ok {
+		return offset
+	}
+	next, err := unix.Seek(int(f.Fd()), offset, unix.SEEK_DATA)
+	if errors.Is(err, unix.ENXIO) {
+		// no data after offset
+		return math.MaxInt64
+	}
+	if err != nil {
+		return offset
+	}
+	return next
+}
+
+// RewriteGPT writes the GPT partition table of the disk of size bytes again for sectorSize logical sectors, with its
+// backup at the end of the disk. It converts the partition table to another logical sector size, or moves its backup
+// after the disk was resized. The partitions must be aligned to sectorSize and fit in the disk, and their content must
+// be compatible with sectorSize.
+func RewriteGPT(f File, size, sectorSize int64) error {
+	if sectorSize != SectorSize512 && sectorSize != SectorSize4K {
+		return errors.Errorf("unsupported logical sector size %d", sectorSize)
+	}
+	if size%sectorSize != 0 {
+		return errors.Errorf("disk size %d is not a multiple of the logical sector size %d", size, sectorSize)
+	}
+	g, err := findGPT(f)
+	if err != nil {
+		return err
+	}
+	if g == nil {
+		return ErrNoGPT
+	}
+	if sectorSize != g.sectorSize {
+		if err := g.checkSectorSize(f, sectorSize); err != nil {
+			return err
+		}
+	}
+
+	entriesSectors := g.entriesSectors(sectorSize)
+	if size/sectorSize < 2*entriesSectors+4 {
+		return errors.Errorf("disk size %d is too small for a GPT partition table", size)
+	}
+	lastLBA := uint64(size/sectorSize - 1)
+	firstUsableLBA := uint64(2 + entriesSectors)
+	lastUsableLBA := lastLBA - uint64(entriesSectors) - 1
+
+	entries := make([]byte, len(g.entries))
+	copy(entries, g.entries)
+	err = g.forEachPartition(func(i int, first, last uint64) error {
+		start, end := first*uint64(g.sectorSize), (last+1)*uint64(g.sectorSize)
+		if start%uint64(sectorSize) != 0 || end%uint64(sectorSize) != 0 {
+			return errors.Errorf("partition %d is not aligned to %d bytes sectors", i+1, sectorSize)
+		}
+		first, last = start/uint64(sectorSize), end/uint64(sectorSize)-1
+		if first < firstUsableLBA || last > lastUsableLBA {
+			return errors.Errorf("partition %d does not fit in a disk of %d bytes", i+1, size)
+		}
+		entry := entries[i*g.entrySize():]
+		binary.LittleEndian.PutUint64(entry[gptEntryStartLBA:], first)
+		binary.LittleEndian.PutUint64(entry[gptEntryEndLBA:], last)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	partitionsStart, partitionsEnd, err := g.partitionsBounds()
+	if err != nil {
+		return err
+	}
+
+	makeHeader := func(myLBA, alternateLBA, entriesLBA uint64) []byte {
+		header := make([]byte, sectorSize)
+		copy(header, g.header)
+		binary.LittleEndian.PutUint64(header[24:], myLBA)
+		binary.LittleEndian.PutUint64(header[32:], alternateLBA)
+		binary.LittleEndian.PutUint64(header[40:], firstUsableLBA)
+		binary.LittleEndian.PutUint64(header[48:], lastUsableLBA)
+		binary.LittleEndian.PutUint64(header[72:], entriesLBA)
+		binary.LittleEndian.PutUint32(header[88:], crc32.ChecksumIEEE(entries))
+		binary.LittleEndian.PutUint32(header[16:], checksum(header[:len(g.header)], 16))
+		return header
+	}
+
+	// clear the previous tables, outside of the partitions
+	headEnd := max(int64(g.uint64At(40))*g.sectorSize, int64(firstUsableLBA)*sectorSize)
+	if partitionsStart >= 0 {
+		headEnd = min(headEnd, partitionsStart)
+	}
+	if err := zeroRange(f, mbrSize, min(headEnd, size)); err != nil {
+		return err
+	}
+	oldBackupStart := int64(g.uint64At(48)+1) * g.sectorSize
+	oldBackupEnd := int64(g.uint64At(32)+1) * g.sectorSize
+	if oldBackupStart >= partitionsEnd {
+		if err := zeroRange(f, oldBackupStart, min(oldBackupEnd, size)); err != nil {
+			return err
+		}
+	}
+
+	primary := append(makeHeader(1, lastLBA, 2), entries...)
+	if _, err := f.WriteAt(primary, sectorSize); err != nil {
+		return errors.Wrap(err, "error writing primary GPT")
+	}
+	backupEntriesLBA := lastUsableLBA + 1
+	backup := make([]byte, (entriesSectors+1)*sectorSize)
+	copy(backup, entries)
+	copy(backup[entriesSectors*sectorSize:], makeHeader(lastLBA, 1, backupEntriesLBA))
+	if _, err := f.WriteAt(backup, int64(backupEntriesLBA)*sectorSize); err != nil {
+		return errors.Wrap(err, "error writing backup GPT")
+	}
+	return updateProtectiveMBR(f, lastLBA)
+}
+
+// updateProtectiveMBR sets the size of the protective partition of the MBR to the sectors of the disk after the MBR
+func updateProtectiveMBR(f File, lastLBA uint64) error {
+	mbr := make([]byte, mbrSize)
+	if _, err := readAt(f, mbr, 0); err != nil {
+		return err
+	}
+	if mbr[mbrSize-2] != 0x55 || mbr[mbrSize-1] != 0xaa {
+		return nil
+	}
+	for i := 0; i < mbrPartitions; i++ {
+		entry := mbr[mbrPartitionsOffset+i*mbrPartitionSize:]
+		if entry[4] == mbrTypeGPT {
+			binary.LittleEndian.PutUint32(entry[12:], uint32(min(lastLBA, 0xffffffff)))
+		}
+	}
+	if _, err := f.WriteAt(mbr, 0); err != nil {
+		return errors.Wrap(err, "error writing protective MBR")
+	}
+	return nil
+}
+
+func zeroRange(f File, start, end int64) error {
+	if end <= start {
+		return nil
+	}
+	if _, err := f.WriteAt(make([]byte, end-start), start); err != nil {
+		return errors.Wrapf(err, "error clearing disk at %d", start)
+	}
+	return nil
+}
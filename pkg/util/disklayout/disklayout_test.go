@@ -0,0 +1,393 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package disklayout
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	mib = 1024 * 1024
+
+	testEntries   = 128
+	testEntrySize = 128
+)
+
+// memDisk is a disk image in memory
+type memDisk []byte
+
+func (d memDisk) ReadAt(p []byte, off int64) (int, error) {
+	if off >= int64(len(d)) {
+		return 0, io.EOF
+	}
+	n := copy(p, d[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (d memDisk) WriteAt(p []byte, off int64) (int, error) {
+	if off+int64(len(p)) > int64(len(d)) {
+		return 0, io.ErrShortWrite
+	}
+	return copy(d[off:], p), nil
+}
+
+type testPartition struct {
+	first, last uint64
+}
+
+// newGPTDisk creates a disk with a protective MBR and a GPT partition table for sectorSize
+func newGPTDisk(size, sectorSize int64, partitions ...testPartition) memDisk {
+	disk := make(memDisk, size)
+	lastLBA := uint64(size/sectorSize - 1)
+	entriesSectors := uint64((testEntries*testEntrySize + sectorSize - 1) / sectorSize)
+
+	mbr := disk[mbrPartitionsOffset:]
+	mbr[4] = mbrTypeGPT
+	binary.LittleEndian.PutUint32(mbr[8:], 1)
+	binary.LittleEndian.PutUint32(mbr[12:], uint32(lastLBA))
+	disk[510], disk[511] = 0x55, 0xaa
+
+	entries := make([]byte, testEntries*testEntrySize)
+	for i, p := range partitions {
+		entry := entries[i*testEntrySize:]
+		entry[0] = byte(i + 1)
+		binary.LittleEndian.PutUint64(entry[gptEntryStartLBA:], p.first)
+		binary.LittleEndian.PutUint64(entry[gptEntryEndLBA:], p.last)
+	}
+	header := func(myLBA, alternateLBA, entriesLBA uint64) []byte {
+		h := make([]byte, gptHeaderSize)
+		copy(h, gptSignature)
+		binary.LittleEndian.PutUint32(h[8:], 0x00010000)
+		binary.LittleEndian.PutUint32(h[12:], gptHeaderSize)
+		binary.LittleEndian.PutUint64(h[24:], myLBA)
+		binary.LittleEndian.PutUint64(h[32:], alternateLBA)
+		binary.LittleEndian.PutUint64(h[40:], 2+entriesSectors)
+		binary.LittleEndian.PutUint64(h[48:], lastLBA-entriesSectors-1)
+		binary.LittleEndian.PutUint64(h[72:], entriesLBA)
+		binary.LittleEndian.PutUint32(h[80:], testEntries)
+		binary.LittleEndian.PutUint32(h[84:], testEntrySize)
+		binary.LittleEndian.PutUint32(h[88:], crc32.ChecksumIEEE(entries))
+		binary.LittleEndian.PutUint32(h[16:], crc32.ChecksumIEEE(h))
+		return h
+	}
+	copy(disk[sectorSize:], header(1, lastLBA, 2))
+	copy(disk[2*sectorSize:], entries)
+	copy(disk[int64(lastLBA-entriesSectors)*sectorSize:], entries)
+	copy(disk[int64(lastLBA)*sectorSize:], header(lastLBA, 1, lastLBA-entriesSectors))
+	return disk
+}
+
+// putExt writes the superblock of an ext4 filesystem of size bytes at offset
+func putExt(disk memDisk, offset, size int64) {
+	sb := disk[offset+extSuperblockOffset:]
+	binary.LittleEndian.PutUint32(sb[4:], uint32(size/4096))
+	binary.LittleEndian.PutUint32(sb[24:], 2)
+	binary.LittleEndian.PutUint16(sb[56:], extMagic)
+}
+
+// putLVM writes the label of an LVM physical volume of size bytes at offset
+func putLVM(disk memDisk, offset, size int64) {
+	label := disk[offset+SectorSize512:]
+	copy(label, lvmLabel)
+	binary.LittleEndian.PutUint32(label[20:], 32)
+	copy(label[24:], lvmType)
+	binary.LittleEndian.PutUint64(label[32+32:], uint64(size))
+}
+
+// putXFS writes the superblock of an XFS filesystem with sectorSize sectors at offset
+func putXFS(disk memDisk, offset, sectorSize int64) {
+	sb := disk[offset:]
+	copy(sb, xfsMagic)
+	binary.BigEndian.PutUint32(sb[4:], 4096)
+	binary.BigEndian.PutUint64(sb[8:], 1024)
+	binary.BigEndian.PutUint16(sb[xfsSectorSizeOffset:], uint16(sectorSize))
+}
+
+// putFAT writes the boot sector of a FAT32 filesystem with sectorSize sectors at offset
+func putFAT(disk memDisk, offset, sectorSize int64) {
+	bs := disk[offset:]
+	bs[0] = 0xeb
+	binary.LittleEndian.PutUint16(bs[fatBytesPerSector:], uint16(sectorSize))
+	copy(bs[82:], "FAT32   ")
+	bs[510], bs[511] = 0x55, 0xaa
+}
+
+// setPartitionType sets the type GUID of partition i in the primary GPT partition table for sectorSize
+func setPartitionType(disk memDisk, sectorSize int64, i int, typeGUID []byte) {
+	entries := disk[2*sectorSize : 2*sectorSize+testEntries*testEntrySize]
+	copy(entries[i*testEntrySize:], typeGUID)
+	header := disk[sectorSize : sectorSize+gptHeaderSize]
+	binary.LittleEndian.PutUint32(header[88:], crc32.ChecksumIEEE(entries))
+	binary.LittleEndian.PutUint32(header[16:], checksum(header, 16))
+}
+
+func readPartitions(disk memDisk, sectorSize int64) []testPartition {
+	g, err := readGPT(disk, sectorSize)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	ExpectWithOffset(1, g).ToNot(BeNil())
+	var partitions []testPartition
+	ExpectWithOffset(1, g.forEachPartition(func(_ int, first, last uint64) error {
+		partitions = append(partitions, testPartition{first, last})
+		return nil
+	})).To(Succeed())
+	return partitions
+}
+
+func readBackupHeader(disk memDisk, sectorSize int64) []byte {
+	header := disk[int64(len(disk))-sectorSize:][:gptHeaderSize]
+	ExpectWithOffset(1, string(header[:len(gptSignature)])).To(Equal(gptSignature))
+	ExpectWithOffset(1, checksum(header, 16)).To(Equal(binary.LittleEndian.Uint32(header[16:])))
+	return header
+}
+
+var _ = Describe("Disk layout", func() {
+	DescribeTable("should find the logical sector size of GPT partition tables", func(sectorSize int64) {
+		disk := newGPTDisk(16*mib, sectorSize, testPartition{uint64(mib / sectorSize), uint64(8*mib/sectorSize - 1)})
+		Expect(GPTSectorSize(disk)).To(Equal(sectorSize))
+	},
+		Entry("with 512 bytes sectors", int64(SectorSize512)),
+		Entry("with 4096 bytes sectors", int64(SectorSize4K)),
+	)
+
+	It("should not find the logical sector size without GPT partition table", func() {
+		Expect(GPTSectorSize(make(memDisk, mib))).To(BeZero())
+	})
+
+	It("should fail on a corrupted GPT header", func() {
+		disk := newGPTDisk(16*mib, SectorSize512)
+		disk[SectorSize512+40]++
+		_, err := GPTSectorSize(disk)
+		Expect(err).To(MatchError(ContainSubstring("checksum")))
+	})
+
+	Context("UsedSize", func() {
+		It("should keep the GPT partitions and the backup table", func() {
+			disk := newGPTDisk(16*mib, SectorSize512, testPartition{2048, 8*2048 - 1}, testPartition{8 * 2048, 10*2048 - 1})
+			Expect(UsedSize(disk, 16*mib)).To(Equal(int64(10*mib + 33*SectorSize512)))
+		})
+
+		It("should keep the MBR partitions", func() {
+			disk := make(memDisk, 16*mib)
+			entry := disk[mbrPartitionsOffset+mbrPartitionSize:]
+			entry[4] = 0x83
+			binary.LittleEndian.PutUint32(entry[8:], 2048)
+			binary.LittleEndian.PutUint32(entry[12:], 6*2048)
+			disk[510], disk[511] = 0x55, 0xaa
+			Expect(HasMBRPartitions(disk)).To(BeTrue())
+			Expect(UsedSize(disk, 16*mib)).To(Equal(int64(7 * mib)))
+		})
+
+		It("should keep the MBR partitions with 4096 bytes sectors if they fit", func() {
+			disk := make(memDisk, 16*mib)
+			entry := disk[mbrPartitionsOffset:]
+			entry[4] = 0x83
+			binary.LittleEndian.PutUint32(entry[8:], 256)
+			binary.LittleEndian.PutUint32(entry[12:], 256)
+			disk[510], disk[511] = 0x55, 0xaa
+			Expect(UsedSize(disk, 16*mib)).To(Equal(int64(2 * mib)))
+		})
+
+		It("should keep the ext4 filesystem", func() {
+			disk := make(memDisk, 16*mib)
+			sb := disk[extSuperblockOffset:]
+			binary.LittleEndian.PutUint32(sb[4:], 2048)
+			binary.LittleEndian.PutUint32(sb[24:], 2)
+			binary.LittleEndian.PutUint16(sb[56:], extMagic)
+			Expect(UsedSize(disk, 16*mib)).To(Equal(int64(8 * mib)))
+		})
+
+		It("should keep the XFS filesystem", func() {
+			disk := make(memDisk, 16*mib)
+			copy(disk, xfsMagic)
+			binary.BigEndian.PutUint32(disk[4:], 4096)
+			binary.BigEndian.PutUint64(disk[8:], 1024)
+			Expect(UsedSize(disk, 16*mib)).To(Equal(int64(4 * mib)))
+		})
+
+		It("should keep the LVM physical volume", func() {
+			disk := make(memDisk, 16*mib)
+			putLVM(disk, 0, 12*mib)
+			Expect(UsedSize(disk, 16*mib)).To(Equal(int64(12 * mib)))
+		})
+
+		It("should fail on unknown layouts", func() {
+			_, err := UsedSize(make(memDisk, mib), mib)
+			Expect(err).To(MatchError(ErrUnknownLayout))
+		})
+
+		DescribeTable("should fail on data after the layout", func(disk memDisk) {
+			disk[14*mib] = 1
+			_, err := UsedSize(disk, 16*mib)
+			Expect(err).To(MatchError(ErrUnsafeLayout))
+		},
+			Entry("with GPT partitions", newGPTDisk(16*mib, SectorSize512, testPartition{2048, 8*2048 - 1})),
+			Entry("with a filesystem", func() memDisk {
+				disk := make(memDisk, 16*mib)
+				putExt(disk, 0, 8*mib)
+				return disk
+			}()),
+			Entry("with an LVM physical volume", func() memDisk {
+				disk := make(memDisk, 16*mib)
+				putLVM(disk, 0, 12*mib)
+				return disk
+			}()),
+		)
+
+		DescribeTable("should fail on partitions holding more data than their size", func(put func(memDisk, int64, int64)) {
+			disk := newGPTDisk(16*mib, SectorSize512, testPartition{2048, 8*2048 - 1})
+			put(disk, mib, 12*mib)
+			_, err := UsedSize(disk, 16*mib)
+			Expect(err).To(MatchError(ErrUnsafeLayout))
+			Expect(err).To(MatchError(ContainSubstring("partition 1 of 7340032 bytes holds 12582912 bytes")))
+		},
+			Entry("with a filesystem", putExt),
+			Entry("with an LVM physical volume", putLVM),
+		)
+
+		It("should keep GPT partitions holding a filesystem which fits", func() {
+			disk := newGPTDisk(16*mib, SectorSize512, testPartition{2048, 8*2048 - 1})
+			putExt(disk, mib, 7*mib)
+			Expect(UsedSize(disk, 16*mib)).To(Equal(int64(8*mib + 33*SectorSize512)))
+		})
+
+		It("should skip the holes of sparse disk images", func() {
+			f, err := os.CreateTemp("", "disklayout")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.Remove(f.Name())
+			defer f.Close()
+			disk := make(memDisk, mib)
+			putExt(disk, 0, 8*mib)
+			_, err = f.WriteAt(disk, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(f.Truncate(1024 * mib)).To(Succeed())
+			Expect(UsedSize(f, 1024*mib)).To(Equal(int64(8 * mib)))
+
+			_, err = f.WriteAt([]byte{1}, 1000*mib)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = UsedSize(f, 1024*mib)
+			Expect(err).To(MatchError(ContainSubstring("data found at offset")))
+		})
+	})
+
+	Context("RewriteGPT", func() {
+		It("should convert 512 bytes sectors to 4096 bytes sectors", func() {
+			disk := newGPTDisk(16*mib, SectorSize512, testPartition{2048, 8*2048 - 1}, testPartition{8 * 2048, 12*2048 - 1})
+			data := bytes.Repeat([]byte{0xab}, SectorSize4K)
+			copy(disk[mib:], data)
+
+			Expect(RewriteGPT(disk, 16*mib, SectorSize4K)).To(Succeed())
+			Expect(GPTSectorSize(disk)).To(Equal(int64(SectorSize4K)))
+			Expect(readPartitions(disk, SectorSize4K)).To(Equal([]testPartition{{256, 8*256 - 1}, {8 * 256, 12*256 - 1}}))
+			Expect(string(disk[SectorSize512 : SectorSize512+len(gptSignature)])).ToNot(Equal(gptSignature))
+			Expect([]byte(disk[mib : mib+SectorSize4K])).To(Equal(data))
+
+			backup := readBackupHeader(disk, SectorSize4K)
+			Expect(binary.LittleEndian.Uint64(backup[24:])).To(Equal(uint64(16*256 - 1)))
+			Expect(binary.LittleEndian.Uint32(disk[mbrPartitionsOffset+12:])).To(Equal(uint32(16*256 - 1)))
+		})
+
+		It("should convert 4096 bytes sectors to 512 bytes sectors", func() {
+			disk := newGPTDisk(16*mib, SectorSize4K, testPartition{256, 8*256 - 1})
+			Expect(RewriteGPT(disk, 16*mib, SectorSize512)).To(Succeed())
+			Expect(GPTSectorSize(disk)).To(Equal(int64(SectorSize512)))
+			Expect(readPartitions(disk, SectorSize512)).To(Equal([]testPartition{{2048, 8*2048 - 1}}))
+			readBackupHeader(disk, SectorSize512)
+		})
+
+		It("should move the backup table to the end of a grown disk", func() {
+			disk := newGPTDisk(8*mib, SectorSize512, testPartition{2048, 4*2048 - 1})
+			disk = append(disk, make(memDisk, 8*mib)...)
+			Expect(RewriteGPT(disk, 16*mib, SectorSize512)).To(Succeed())
+			Expect(readPartitions(disk, SectorSize512)).To(Equal([]testPartition{{2048, 4*2048 - 1}}))
+			backup := readBackupHeader(disk, SectorSize512)
+			Expect(binary.LittleEndian.Uint64(backup[24:])).To(Equal(uint64(16*2048 - 1)))
+			Expect(string(disk[8*mib-SectorSize512 : 8*mib-SectorSize512+len(gptSignature)])).ToNot(Equal(gptSignature))
+		})
+
+		It("should move the backup table to the end of a shrunk disk", func() {
+			disk := newGPTDisk(16*mib, SectorSize512, testPartition{2048, 4*2048 - 1})
+			disk = disk[:8*mib]
+			Expect(RewriteGPT(disk, 8*mib, SectorSize512)).To(Succeed())
+			backup := readBackupHeader(disk, SectorSize512)
+			Expect(binary.LittleEndian.Uint64(backup[24:])).To(Equal(uint64(8*2048 - 1)))
+		})
+
+		It("should fail if the partitions are not aligned to the sector size", func() {
+			disk := newGPTDisk(16*mib, SectorSize512, testPartition{2049, 8*2048 - 1})
+			err := RewriteGPT(disk, 16*mib, SectorSize4K)
+			Expect(err).To(MatchError(ContainSubstring("partition 1 is not aligned")))
+		})
+
+		It("should fail if the partitions do not fit in the disk", func() {
+			disk := newGPTDisk(16*mib, SectorSize512, testPartition{2048, 14*2048 - 1})
+			err := RewriteGPT(disk[:8*mib], 8*mib, SectorSize512)
+			Expect(err).To(MatchError(ContainSubstring("partition 1 does not fit")))
+		})
+
+		It("should fail without GPT partition table", func() {
+			Expect(RewriteGPT(make(memDisk, mib), mib, SectorSize4K)).To(MatchError(ErrNoGPT))
+		})
+
+		DescribeTable("should refuse to convert partitions not compatible with 4096 bytes sectors", func(put func(memDisk), message string) {
+			disk := newGPTDisk(16*mib, SectorSize512, testPartition{2048, 4*2048 - 1}, testPartition{4 * 2048, 8*2048 - 1})
+			put(disk)
+			before := bytes.Clone(disk)
+			err := RewriteGPT(disk, 16*mib, SectorSize4K)
+			Expect(err).To(MatchError(ErrIncompatibleSectorSize))
+			Expect(err).To(MatchError(ContainSubstring(message)))
+			Expect(bytes.Equal(disk, before)).To(BeTrue())
+		},
+			Entry("with an XFS filesystem with 512 bytes sectors", func(disk memDisk) { putXFS(disk, 4*mib, SectorSize512) },
+				"partition 2: XFS filesystem with 512 bytes sectors"),
+			Entry("with a FAT filesystem with 512 bytes sectors", func(disk memDisk) { putFAT(disk, mib, SectorSize512) },
+				"partition 1: FAT filesystem with 512 bytes sectors"),
+			Entry("with an ext4 filesystem with 1024 bytes blocks", func(disk memDisk) {
+				putExt(disk, mib, 2*mib)
+				binary.LittleEndian.PutUint32(disk[mib+extSuperblockOffset+24:], 0)
+			}, "partition 1: ext filesystem with 1024 bytes sectors"),
+			Entry("with a BIOS boot partition", func(disk memDisk) { setPartitionType(disk, SectorSize512, 0, biosBootPartitionType) },
+				"partition 1 is a BIOS boot partition"),
+		)
+
+		It("should convert partitions holding filesystems with 4096 bytes sectors", func() {
+			disk := newGPTDisk(16*mib, SectorSize512, testPartition{2048, 4*2048 - 1}, testPartition{4 * 2048, 8*2048 - 1},
+				testPartition{8 * 2048, 12*2048 - 1})
+			putFAT(disk, mib, SectorSize4K)
+			putXFS(disk, 4*mib, SectorSize4K)
+			putExt(disk, 8*mib, 4*mib)
+			Expect(RewriteGPT(disk, 16*mib, SectorSize4K)).To(Succeed())
+			Expect(GPTSectorSize(disk)).To(Equal(int64(SectorSize4K)))
+		})
+
+		It("should move the backup table of a disk with a BIOS boot partition", func() {
+			disk := newGPTDisk(8*mib, SectorSize512, testPartition{2048, 4*2048 - 1})
+			setPartitionType(disk, SectorSize512, 0, biosBootPartitionType)
+			disk = append(disk, make(memDisk, 8*mib)...)
+			Expect(RewriteGPT(disk, 16*mib, SectorSize512)).To(Succeed())
+		})
+	})
+})
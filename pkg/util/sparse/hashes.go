@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparse
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// HashBlockSize is the size of the blocks hashed to find the data which differs between two images
+const HashBlockSize = 256 * 1024
+
+const hashesMagic = "CDIHASH1"
+
+// Hashes are the SHA-256 hashes of the blocks of an image.
+//
+// A stream of hashes starts with a header holding the magic, the size of the image and the size of the blocks,
+// followed by the hashes of the blocks in order, the last block may be shorter than the others. The integers are
+// unsigned 64 bits big endian.
+type Hashes struct {
+	size      int64
+	blockSize int64
+	sums      []byte
+}
+
+// WriteHashes writes the hashes of the blocks of the first size bytes of r to w
+func WriteHashes(w io.Writer, r io.Reader, size int64) error {
+	header := make([]byte, len(hashesMagic)+16)
+	copy(header, hashesMagic)
+	binary.BigEndian.PutUint64(header[len(hashesMagic):], uint64(size))
+	binary.BigEndian.PutUint64(header[len(hashesMagic)+8:], HashBlockSize)
+	if _, err := w.Write(header); err != nil {
+		return errors.Wrap(err, "unable to write the hashes header")
+	}
+
+	block := make([]byte, HashBlockSize)
+	for offset := int64(0); offset < size; {
+		n := int(min(HashBlockSize, size-offset))
+		if _, err := io.ReadFull(r, block[:n]); err != nil {
+			return errors.Wrapf(err, "unable to read the block at %d", offset)
+		}
+		sum := sha256.Sum256(block[:n])
+		if _, err := w.Write(sum[:]); err != nil {
+			return errors.Wrapf(err, "unable to write the hash of the block at %d", offset)
+		}
+		offset += int64(n)
+	}
+	return nil
+}
+
+// ReadHashes reads the stream of hashes of r
+func ReadHashes(r io.Reader) (*Hashes, error) {
+	header := make([]byte, len(hashesMagic)+16)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, errors.Wrap(err, "unable to read the hashes header")
+	}
+	if string(header[:len(hashesMagic)]) != hashesMagic {
+		return nil, errors.Wrap(ErrInvalidStream, "unexpected hashes magic")
+	}
+	size := binary.BigEndian.Uint64(header[len(hashesMagic):])
+	blockSize := binary.BigEndian.Uint64(header[len(hashesMagic)+8:])
+	if size > uint64(1<<63-1) || blockSize == 0 || blockSize > MaxExtentSize {
+		return nil, errors.Wrapf(ErrInvalidStream, "invalid size %d or block size %d", size, blockSize)
+	}
+
+	blocks := (size + blockSize - 1) / blockSize
+	sums := bytes.NewBuffer(nil)
+	if n, err := io.CopyN(sums, r, int64(blocks*sha256.Size)); err != nil {
+		return nil, errors.Wrapf(err, "unable to read the hashes, read %d of %d blocks", n/sha256.Size, blocks)
+	}
+	return &Hashes{size: int64(size), blockSize: int64(blockSize), sums: sums.Bytes()}, nil
+}
+
+// Size returns the size of the hashed image
+func (h *Hashes) Size() int64 {
+	return h.size
+}
+
+// BlockSize returns the size of the hashed blocks
+func (h *Hashes) BlockSize() int64 {
+	return h.blockSize
+}
+
+// Matches returns true if the hashed image has the same data as block at offset, offset must be a multiple of the
+// block size
+func (h *Hashes) Matches(offset int64, block []byte) bool {
+	if offset%h.blockSize != 0 || offset+int64(len(block)) > h.size {
+		return false
+	}
+	// Only the last block of the hashed image may be shorter
+	if int64(len(block)) != min(h.blockSize, h.size-offset) {
+		return false
+	}
+	index := offset / h.blockSize * sha256.Size
+	sum := sha256.Sum256(block)
+	return bytes.Equal(h.sums[index:index+sha256.Size], sum[:])
+}
+
+// EncodeDelta writes the sparse stream of the blocks of the first size bytes of r which differ from the hashed image
+// to w. The blocks missing from the stream are unchanged, unlike the holes of the stream written by Encode.
+func EncodeDelta(w io.Writer, r io.Reader, size int64, hashes *Hashes) error {
+	sw, err := NewWriter(w, size)
+	if err != nil {
+		return err
+	}
+	block := make([]byte, hashes.blockSize)
+	for offset := int64(0); offset < size; {
+		n := int(min(hashes.blockSize, size-offset))
+		if _, err := io.ReadFull(r, block[:n]); err != nil {
+			return errors.Wrapf(err, "unable to read the block at %d", offset)
+		}
+		if !hashes.Matches(offset, block[:n]) {
+			if err := sw.WriteExtent(offset, block[:n]); err != nil {
+				return err
+			}
+		}
+		offset += int64(n)
+	}
+	return sw.Close()
+}
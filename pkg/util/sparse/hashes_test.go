@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparse
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Delta streams", func() {
+	const size = 4*HashBlockSize + 100
+
+	newImage := func() []byte {
+		image := make([]byte, size)
+		for i := range image {
+			image[i] = byte(i % 251)
+		}
+		return image
+	}
+
+	hashImage := func(image []byte) *Hashes {
+		buf := &bytes.Buffer{}
+		Expect(WriteHashes(buf, bytes.NewReader(image), int64(len(image)))).To(Succeed())
+		hashes, err := ReadHashes(buf)
+		Expect(err).ToNot(HaveOccurred())
+		return hashes
+	}
+
+	It("should read the hashes it wrote", func() {
+		image := newImage()
+		hashes := hashImage(image)
+		Expect(hashes.Size()).To(Equal(int64(size)))
+		Expect(hashes.BlockSize()).To(Equal(int64(HashBlockSize)))
+		Expect(hashes.Matches(0, image[:HashBlockSize])).To(BeTrue())
+		Expect(hashes.Matches(4*HashBlockSize, image[4*HashBlockSize:])).To(BeTrue())
+		Expect(hashes.Matches(HashBlockSize, image[:HashBlockSize])).To(BeFalse())
+		Expect(hashes.Matches(1, image[1:HashBlockSize+1])).To(BeFalse())
+		Expect(hashes.Matches(4*HashBlockSize, image[4*HashBlockSize:size-1])).To(BeFalse())
+	})
+
+	It("should reject invalid hashes", func() {
+		_, err := ReadHashes(bytes.NewReader([]byte("CDISPRS1AAAAAAAAAAAAAAAA")))
+		Expect(err).To(MatchError(ErrInvalidStream))
+
+		buf := &bytes.Buffer{}
+		Expect(WriteHashes(buf, bytes.NewReader(newImage()), size)).To(Succeed())
+		_, err = ReadHashes(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should only stream the blocks which differ", func() {
+		target := newImage()
+		source := newImage()
+		source[HashBlockSize+10] = 0xff
+		copy(source[3*HashBlockSize:], make([]byte, HashBlockSize))
+
+		buf := &bytes.Buffer{}
+		Expect(EncodeDelta(buf, bytes.NewReader(source), size, hashImage(target))).To(Succeed())
+		streamSize, extents, err := readStream(buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(streamSize).To(Equal(int64(size)))
+		Expect(extents).To(Equal([]testExtent{
+			{offset: HashBlockSize, data: source[HashBlockSize : 2*HashBlockSize]},
+			{offset: 3 * HashBlockSize, data: source[3*HashBlockSize : 4*HashBlockSize]},
+		}))
+	})
+
+	It("should stream the blocks past the end of a smaller target", func() {
+		source := newImage()
+		target := source[:2*HashBlockSize+1]
+
+		buf := &bytes.Buffer{}
+		Expect(EncodeDelta(buf, bytes.NewReader(source), size, hashImage(target))).To(Succeed())
+		_, extents, err := readStream(buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(extents).To(Equal([]testExtent{
+			{offset: 2 * HashBlockSize, data: source[2*HashBlockSize:]},
+		}))
+	})
+})
@@ -0,0 +1,291 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sparse finds the data of sparse images and streams it without the holes.
+//
+// A sparse stream starts with a header holding the magic and the size of the image, followed by extents of data. Each
+// extent has a header holding its offset and length, followed by its data. The extents are in ascending order and
+// don't overlap, the rest of the image is zero. The stream ends with an extent of length 0 at the end of the image.
+// The integers are unsigned 64 bits big endian.
+package sparse
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// BlockSize is the size of the blocks checked for zeros
+	BlockSize = 64 * 1024
+	// MaxExtentSize is the maximum size of an extent of a sparse stream
+	MaxExtentSize = 4 * 1024 * 1024
+
+	magic = "CDISPRS1"
+)
+
+// ErrInvalidStream is returned when reading a malformed sparse stream
+var ErrInvalidStream = errors.New("invalid sparse stream")
+
+var zeroBlock = make([]byte, BlockSize)
+
+// NextData returns the offset of the data at or after offset, or size if there is no more data. The offset is returned
+// if the holes of the source can not be found, like for block devices.
+func NextData(f *os.File, offset, size int64) int64 {
+	data, err := unix.Seek(int(f.Fd()), offset, unix.SEEK_DATA)
+	if errors.Is(err, unix.ENXIO) {
+		return size
+	}
+	if err != nil {
+		return offset
+	}
+	return data
+}
+
+// NextHole returns the offset of the hole at or after offset, or size if the holes of the source can not be found
+func NextHole(f *os.File, offset, size int64) int64 {
+	hole, err := unix.Seek(int(f.Fd()), offset, unix.SEEK_HOLE)
+	if err != nil {
+		return size
+	}
+	return min(hole, size)
+}
+
+// AllocatedBytes returns the number of bytes of the first size bytes of f which are not in a hole, this is size if the
+// holes of f can not be found
+func AllocatedBytes(f *os.File, size int64) int64 {
+	var allocated int64
+	for offset := NextData(f, 0, size); offset < size; offset = NextData(f, offset, size) {
+		end := NextHole(f, offset, size)
+		allocated += end - offset
+		offset = end
+	}
+	return allocated
+}
+
+// IsZero returns true if block only has zeros
+func IsZero(block []byte) bool {
+	for len(block) > 0 {
+		n := min(len(block), BlockSize)
+		if !bytes.Equal(block[:n], zeroBlock[:n]) {
+			return false
+		}
+		block = block[n:]
+	}
+	return true
+}
+
+// Scan calls fn with the blocks of data of the first size bytes of f, skipping the holes of f and the blocks of
+// zeros. The data is read from r, which must read f from its current offset, so that a reader counting the bytes read
+// from f can be used. The block is only valid until fn returns.
+func Scan(f *os.File, r io.Reader, size int64, fn func(offset int64, block []byte) error) error {
+	block := make([]byte, BlockSize)
+	for offset := int64(0); offset < size; {
+		dataStart := NextData(f, offset, size)
+		if dataStart >= size {
+			break
+		}
+		dataEnd := NextHole(f, dataStart, size)
+		if _, err := f.Seek(dataStart, io.SeekStart); err != nil {
+			return errors.Wrapf(err, "unable to seek to %d", dataStart)
+		}
+		for offset = dataStart; offset < dataEnd; {
+			n := int(min(BlockSize, dataEnd-offset))
+			if _, err := io.ReadFull(r, block[:n]); err != nil {
+				return errors.Wrapf(err, "unable to read the data at %d", offset)
+			}
+			if !IsZero(block[:n]) {
+				if err := fn(offset, block[:n]); err != nil {
+					return err
+				}
+			}
+			offset += int64(n)
+		}
+	}
+	return nil
+}
+
+// Encode writes the sparse stream of the first size bytes of f to w, reading the data from r like Scan
+func Encode(w io.Writer, f *os.File, r io.Reader, size int64) error {
+	sw, err := NewWriter(w, size)
+	if err != nil {
+		return err
+	}
+	if err := Scan(f, r, size, sw.WriteExtent); err != nil {
+		return err
+	}
+	return sw.Close()
+}
+
+// Writer writes a sparse stream, merging contiguous data in extents of up to MaxExtentSize
+type Writer struct {
+	w     io.Writer
+	size  int64
+	start int64
+	end   int64
+	data  []byte
+}
+
+// NewWriter writes the header of the sparse stream of an image of size bytes to w
+func NewWriter(w io.Writer, size int64) (*Writer, error) {
+	header := make([]byte, len(magic)+8)
+	copy(header, magic)
+	binary.BigEndian.PutUint64(header[len(magic):], uint64(size))
+	if _, err := w.Write(header); err != nil {
+		return nil, errors.Wrap(err, "unable to write the sparse stream header")
+	}
+	return &Writer{w: w, size: size, data: make([]byte, 0, MaxExtentSize)}, nil
+}
+
+// WriteExtent writes data at offset, the extents must be written in ascending order
+func (w *Writer) WriteExtent(offset int64, data []byte) error {
+	if offset < w.end || offset+int64(len(data)) > w.size {
+		return errors.Errorf("extent %d-%d is out of order or outside of the image", offset, offset+int64(len(data)))
+	}
+	for len(data) > 0 {
+		if len(w.data) > 0 && (w.start+int64(len(w.data)) != offset || len(w.data) == MaxExtentSize) {
+			if err := w.flush(); err != nil {
+				return err
+			}
+		}
+		if len(w.data) == 0 {
+			w.start = offset
+		}
+		n := min(len(data), MaxExtentSize-len(w.data))
+		w.data = append(w.data, data[:n]...)
+		offset += int64(n)
+		data = data[n:]
+	}
+	w.end = offset
+	return nil
+}
+
+// Close writes the pending data and the end of the stream, it does not close the underlying writer
+func (w *Writer) Close() error {
+	if err := w.flush(); err != nil {
+		return err
+	}
+	return w.writeExtentHeader(w.size, 0)
+}
+
+func (w *Writer) flush() error {
+	if len(w.data) == 0 {
+		return nil
+	}
+	if err := w.writeExtentHeader(w.start, int64(len(w.data))); err != nil {
+		return err
+	}
+	if _, err := w.w.Write(w.data); err != nil {
+		return errors.Wrapf(err, "unable to write the extent at %d", w.start)
+	}
+	w.data = w.data[:0]
+	return nil
+}
+
+func (w *Writer) writeExtentHeader(offset, length int64) error {
+	header := make([]byte, 16)
+	binary.BigEndian.PutUint64(header, uint64(offset))
+	binary.BigEndian.PutUint64(header[8:], uint64(length))
+	if _, err := w.w.Write(header); err != nil {
+		return errors.Wrapf(err, "unable to write the extent header at %d", offset)
+	}
+	return nil
+}
+
+// Reader reads a sparse stream, Next advances to the next extent and Read reads its data
+type Reader struct {
+	r         io.Reader
+	size      int64
+	end       int64
+	remaining int64
+	done      bool
+}
+
+// NewReader reads the header of the sparse stream of r
+func NewReader(r io.Reader) (*Reader, error) {
+	header := make([]byte, len(magic)+8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, errors.Wrap(err, "unable to read the sparse stream header")
+	}
+	if string(header[:len(magic)]) != magic {
+		return nil, errors.Wrap(ErrInvalidStream, "unexpected magic")
+	}
+	size := binary.BigEndian.Uint64(header[len(magic):])
+	if size > uint64(1<<63-1) {
+		return nil, errors.Wrapf(ErrInvalidStream, "invalid size %d", size)
+	}
+	return &Reader{r: r, size: int64(size)}, nil
+}
+
+// Size returns the size of the image
+func (r *Reader) Size() int64 {
+	return r.size
+}
+
+// Next returns the offset and length of the next extent, skipping the unread data of the current one. It returns
+// io.EOF at the end of the stream.
+func (r *Reader) Next() (int64, int64, error) {
+	if r.done {
+		return 0, 0, io.EOF
+	}
+	if r.remaining > 0 {
+		if _, err := io.CopyN(io.Discard, r.r, r.remaining); err != nil {
+			return 0, 0, errors.Wrap(err, "unable to skip the extent")
+		}
+		r.remaining = 0
+	}
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(r.r, header); err != nil {
+		return 0, 0, errors.Wrap(err, "unable to read the extent header")
+	}
+	offset, length := binary.BigEndian.Uint64(header), binary.BigEndian.Uint64(header[8:])
+	if offset < uint64(r.end) || offset > uint64(r.size) || length > uint64(r.size)-offset {
+		return 0, 0, errors.Wrapf(ErrInvalidStream, "extent of %d bytes at %d is out of order or outside of the image", length, offset)
+	}
+	if length == 0 {
+		if offset != uint64(r.size) {
+			return 0, 0, errors.Wrapf(ErrInvalidStream, "empty extent at %d", offset)
+		}
+		r.done = true
+		return 0, 0, io.EOF
+	}
+	r.end = int64(offset + length)
+	r.remaining = int64(length)
+	return int64(offset), int64(length), nil
+}
+
+// Read reads the data of the current extent, it returns io.EOF at the end of the extent
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.remaining == 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err := r.r.Read(p)
+	r.remaining -= int64(n)
+	if errors.Is(err, io.EOF) && r.remaining > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	if err == nil && r.remaining == 0 {
+		err = io.EOF
+	}
+	return n, err
+}
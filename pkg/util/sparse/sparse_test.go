@@ -0,0 +1,148 @@
+/*
+Copyright 2026 The CDI Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparse
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type testExtent struct {
+	offset int64
+	data   []byte
+}
+
+func readStream(r io.Reader) (int64, []testExtent, error) {
+	sr, err := NewReader(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var extents []testExtent
+	for {
+		offset, length, err := sr.Next()
+		if err == io.EOF {
+			return sr.Size(), extents, nil
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+		data, err := io.ReadAll(sr)
+		if err != nil {
+			return 0, nil, err
+		}
+		Expect(data).To(HaveLen(int(length)))
+		extents = append(extents, testExtent{offset: offset, data: data})
+	}
+}
+
+var _ = Describe("Sparse streams", func() {
+	const size = 16 * BlockSize
+
+	var image *os.File
+
+	BeforeEach(func() {
+		var err error
+		image, err = os.Create(filepath.Join(GinkgoT().TempDir(), "disk.img"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(image.Truncate(size)).To(Succeed())
+		DeferCleanup(image.Close)
+	})
+
+	writeImage := func(offset int64, data []byte) {
+		_, err := image.WriteAt(data, offset)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	It("Should only send the data of the image", func() {
+		writeImage(BlockSize, bytes.Repeat([]byte{1}, 2*BlockSize))
+		// A block of zeros which is allocated is skipped as well
+		writeImage(4*BlockSize, make([]byte, BlockSize))
+		writeImage(8*BlockSize+10, []byte("data"))
+
+		Expect(AllocatedBytes(image, size)).To(BeNumerically("<=", 4*BlockSize))
+		buf := &bytes.Buffer{}
+		Expect(Encode(buf, image, image, size)).To(Succeed())
+
+		streamSize, extents, err := readStream(buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(streamSize).To(Equal(int64(size)))
+		Expect(extents).To(HaveLen(2))
+		Expect(extents[0]).To(Equal(testExtent{offset: BlockSize, data: bytes.Repeat([]byte{1}, 2*BlockSize)}))
+		Expect(extents[1].offset).To(Equal(int64(8 * BlockSize)))
+		Expect(extents[1].data[10:14]).To(Equal([]byte("data")))
+		Expect(IsZero(extents[1].data[14:])).To(BeTrue())
+	})
+
+	It("Should split the data in extents of up to MaxExtentSize", func() {
+		buf := &bytes.Buffer{}
+		w, err := NewWriter(buf, 2*MaxExtentSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(w.WriteExtent(10, bytes.Repeat([]byte{1}, MaxExtentSize+10))).To(Succeed())
+		Expect(w.Close()).To(Succeed())
+
+		_, extents, err := readStream(buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(extents).To(HaveLen(2))
+		Expect(extents[0].offset).To(Equal(int64(10)))
+		Expect(extents[0].data).To(HaveLen(MaxExtentSize))
+		Expect(extents[1].offset).To(Equal(int64(10 + MaxExtentSize)))
+		Expect(extents[1].data).To(HaveLen(10))
+	})
+
+	It("Should reject extents written out of order", func() {
+		w, err := NewWriter(io.Discard, size)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(w.WriteExtent(BlockSize, []byte{1})).To(Succeed())
+		Expect(w.WriteExtent(0, []byte{1})).ToNot(Succeed())
+		Expect(w.WriteExtent(size, []byte{1})).ToNot(Succeed())
+	})
+
+	DescribeTable("Should reject an invalid stream", func(extents ...[2]uint64) {
+		buf := &bytes.Buffer{}
+		buf.WriteString(magic)
+		Expect(binary.Write(buf, binary.BigEndian, uint64(size))).To(Succeed())
+		for _, extent := range extents {
+			Expect(binary.Write(buf, binary.BigEndian, extent)).To(Succeed())
+			buf.Write(make([]byte, extent[1]))
+		}
+		_, _, err := readStream(buf)
+		Expect(err).To(MatchError(ErrInvalidStream))
+	},
+		Entry("with overlapping extents", [2]uint64{0, 10}, [2]uint64{5, 10}),
+		Entry("with an extent outside of the image", [2]uint64{size - 5, 10}),
+		Entry("with an early end", [2]uint64{0, 10}, [2]uint64{10, 0}),
+	)
+
+	It("Should reject a truncated stream", func() {
+		buf := &bytes.Buffer{}
+		w, err := NewWriter(buf, size)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(w.WriteExtent(0, []byte("data"))).To(Succeed())
+		Expect(w.Close()).To(Succeed())
+
+		_, _, err = readStream(bytes.NewReader(buf.Bytes()[:buf.Len()-20]))
+		Expect(err).To(MatchError(io.ErrUnexpectedEOF))
+		_, err = NewReader(bytes.NewReader([]byte("qcow2")))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -2,7 +2,6 @@ package util
 
 import (
 	"bufio"
-	"bytes"
 	"crypto/md5" //nolint:gosec // This is not a security-sensitive use case
 	"encoding/base64"
 	"encoding/hex"
@@ -11,7 +10,6 @@ import (
 	"math"
 	"math/rand"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -21,7 +19,6 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/klog/v2"
 
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"kubevirt.io/containerized-data-importer/pkg/common"
@@ -101,30 +98,6 @@ func MinQuantity(availableSpace, imageSize *resource.Quantity) resource.Quantity
 	return *imageSize
 }
 
-// UnArchiveTar unarchives a tar file and streams its files
-// using the specified io.Reader to the specified destination.
-func UnArchiveTar(reader io.Reader, destDir string) error {
-	klog.V(1).Infof("begin untar to %s...\n", destDir)
-	untar := exec.Command("/usr/bin/tar", "--preserve-permissions", "--no-same-owner", "-xvC", destDir)
-	untar.Stdin = reader
-	var outBuf, errBuf bytes.Buffer
-	untar.Stdout = &outBuf
-	untar.Stderr = &errBuf
-	klog.V(1).Infof("running untar cmd: %v\n", untar.Args)
-	err := untar.Start()
-	if err != nil {
-		return err
-	}
-	err = untar.Wait()
-	if err != nil {
-		klog.V(3).Infof("STDOUT\n%s\n", outBuf.String())
-		klog.V(3).Infof("STDERR\n%s\n", errBuf.String())
-		klog.Errorf("%s\n", err.Error())
-		return err
-	}
-	return nil
-}
-
 // WriteTerminationMessage writes the passed in message to the default termination message file
 func WriteTerminationMessage(message string) error {
 	return WriteTerminationMessageToFile(common.PodTerminationMessageFile, message)
@@ -73,7 +73,10 @@ func (dv *DataVolume) AuthorizeUser(requestNamespace, requestName string, proxy
 		sourceNamespace = targetNamespace
 	}
 
-	_, err = proxy.GetNamespace(sourceNamespace)
+	// the source namespace of a remote clone is in the remote cluster
+	if cloneSourceHandler.CloneType != remotePvcClone {
+		_, err = proxy.GetNamespace(sourceNamespace)
+	}
 	if err != nil {
 		if k8serrors.IsNotFound(err) && noTokenOkay {
 			// no token needed, likely since no source namespace
@@ -136,7 +139,10 @@ func (dv *DataVolume) AuthorizeSA(requestNamespace, requestName string, proxy Au
 		sourceNamespace = targetNamespace
 	}
 
-	_, err = proxy.GetNamespace(sourceNamespace)
+	// the source namespace of a remote clone is in the remote cluster
+	if cloneSourceHandler.CloneType != remotePvcClone {
+		_, err = proxy.GetNamespace(sourceNamespace)
+	}
 	if err != nil {
 		if k8serrors.IsNotFound(err) && noTokenOkay {
 			// no token needed, likely since no source namespace
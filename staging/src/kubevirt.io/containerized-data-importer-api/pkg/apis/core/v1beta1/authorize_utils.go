@@ -54,6 +54,15 @@ func newCloneSourceHandler(dataVolume *DataVolume, dsGet dsGetFunc) (CloneSource
 	}
 
 	switch {
+	case pvcSource != nil && pvcSource.Cluster != nil:
+		return CloneSourceHandler{
+			CloneType:         remotePvcClone,
+			TokenResource:     tokenResourcePvc,
+			UserCloneAuthFunc: canUserUseKubeconfigSecret(pvcSource.Cluster.KubeconfigSecretRef),
+			SACloneAuthFunc:   canServiceAccountUseKubeconfigSecret(pvcSource.Cluster.KubeconfigSecretRef),
+			SourceName:        pvcSource.Name,
+			SourceNamespace:   pvcSource.Namespace,
+		}, nil
 	case pvcSource != nil:
 		return CloneSourceHandler{
 			CloneType:         pvcClone,
@@ -99,6 +108,7 @@ const (
 	noClone cloneType = iota
 	pvcClone
 	snapshotClone
+	remotePvcClone
 )
 
 // CloneSourceHandler is a helper around determining the
@@ -125,6 +135,7 @@ type CloneAuthResponse struct {
 }
 
 type createSarFunc func(*authorization.SubjectAccessReview) (*authorization.SubjectAccessReview, error)
+type createSelfSarFunc func(*authorization.SelfSubjectAccessReview) (*authorization.SelfSubjectAccessReview, error)
 type dsGetFunc func(string, string) (*DataSource, error)
 
 // AuthorizationHelperProxy proxies calls to APIs used for DV authorization
@@ -228,6 +239,99 @@ func CanServiceAccountCloneSnapshot(createSar createSarFunc, pvcNamespace, pvcNa
 	return sendSubjectAccessReviewsSnapshot(createSar, pvcNamespace, pvcName, sarSpec)
 }
 
+// canUserUseKubeconfigSecret returns a UserCloneAuthFunc checking if a user can read the kubeconfig secret of the remote
+// cluster of a clone source, the secret is in the target namespace
+func canUserUseKubeconfigSecret(secretName string) UserCloneAuthFunc {
+	return func(createSar createSarFunc, sourceNamespace, pvcName, targetNamespace string, userInfo authentication.UserInfo) (bool, string, error) {
+		var newExtra map[string]authorization.ExtraValue
+		if len(userInfo.Extra) > 0 {
+			newExtra = make(map[string]authorization.ExtraValue)
+			for k, v := range userInfo.Extra {
+				newExtra[k] = authorization.ExtraValue(v)
+			}
+		}
+
+		sarSpec := authorization.SubjectAccessReviewSpec{
+			User:   userInfo.Username,
+			Groups: userInfo.Groups,
+			Extra:  newExtra,
+		}
+
+		return sendSubjectAccessReviewKubeconfigSecret(createSar, targetNamespace, secretName, sarSpec)
+	}
+}
+
+// canServiceAccountUseKubeconfigSecret returns a ServiceAccountCloneAuthFunc checking if a ServiceAccount can read the
+// kubeconfig secret of the remote cluster of a clone source, the secret is in the namespace of the ServiceAccount
+func canServiceAccountUseKubeconfigSecret(secretName string) ServiceAccountCloneAuthFunc {
+	return func(createSar createSarFunc, pvcNamespace, pvcName, saNamespace, saName string) (bool, string, error) {
+		user := fmt.Sprintf("system:serviceaccount:%s:%s", saNamespace, saName)
+
+		sarSpec := authorization.SubjectAccessReviewSpec{
+			User: user,
+			Groups: []string{
+				"system:serviceaccounts",
+				"system:serviceaccounts:" + saNamespace,
+				"system:authenticated",
+			},
+		}
+
+		return sendSubjectAccessReviewKubeconfigSecret(createSar, saNamespace, secretName, sarSpec)
+	}
+}
+
+// CanClonePVCInCluster checks if the user sending the SelfSubjectAccessReviews, like the user of the kubeconfig of a
+// remote cluster, has "appropriate" permission to clone from the given PVC of its cluster
+func CanClonePVCInCluster(createSelfSar createSelfSarFunc, namespace, name string) (bool, string, error) {
+	createSar := func(sar *authorization.SubjectAccessReview) (*authorization.SubjectAccessReview, error) {
+		selfSar := &authorization.SelfSubjectAccessReview{
+			Spec: authorization.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: sar.Spec.ResourceAttributes,
+			},
+		}
+		response, err := createSelfSar(selfSar)
+		if err != nil {
+			return nil, err
+		}
+		sar.Status = response.Status
+		return sar, nil
+	}
+
+	allowed, _, err := sendSubjectAccessReviewsPvc(createSar, namespace, name, authorization.SubjectAccessReviewSpec{})
+	if err != nil || allowed {
+		return allowed, "", err
+	}
+
+	return false, fmt.Sprintf("Cluster user has insufficient permissions in clone source namespace %s", namespace), nil
+}
+
+func sendSubjectAccessReviewKubeconfigSecret(createSar createSarFunc, namespace, name string, sarSpec authorization.SubjectAccessReviewSpec) (bool, string, error) {
+	sar := &authorization.SubjectAccessReview{
+		Spec: sarSpec,
+	}
+	sar.Spec.ResourceAttributes = &authorization.ResourceAttributes{
+		Namespace: namespace,
+		Verb:      "get",
+		Resource:  "secrets",
+		Name:      name,
+	}
+
+	klog.V(3).Infof("Sending SubjectAccessReview %+v", sar)
+
+	response, err := createSar(sar)
+	if err != nil {
+		return false, "", err
+	}
+
+	klog.V(3).Infof("SubjectAccessReview response %+v", response)
+
+	if !response.Status.Allowed {
+		return false, fmt.Sprintf("User %s has insufficient permissions to use the kubeconfig secret %s/%s", sarSpec.User, namespace, name), nil
+	}
+
+	return true, "", nil
+}
+
 func sendSubjectAccessReviewsPvc(createSar createSarFunc, namespace, name string, sarSpec authorization.SubjectAccessReviewSpec) (bool, string, error) {
 	allowed := false
 
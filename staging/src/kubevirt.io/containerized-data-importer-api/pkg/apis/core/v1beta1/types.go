@@ -68,6 +68,9 @@ type DataVolumeSpec struct {
 	FinalCheckpoint bool `json:"finalCheckpoint,omitempty"`
 	// Preallocation controls whether storage for DataVolumes should be allocated in advance.
 	Preallocation *bool `json:"preallocation,omitempty"`
+	// CloneStrategy lists the strategies acceptable for a clone, overriding the storage profile and the CDI clone strategy
+	// +optional
+	CloneStrategy *CloneStrategyPolicy `json:"cloneStrategy,omitempty"`
 }
 
 // StorageSpec defines the Storage type specification
@@ -129,11 +132,14 @@ const (
 	DataVolumeArchive DataVolumeContentType = "archive"
 )
 
-// DataVolumeSource represents the source for our Data Volume, this can be HTTP, Imageio, S3, GCS, Registry or an existing PVC
+// DataVolumeSource represents the source for our Data Volume, this can be HTTP, Imageio, S3, GCS, SFTP, SMB, NFS, Registry or an existing PVC
 type DataVolumeSource struct {
 	HTTP     *DataVolumeSourceHTTP     `json:"http,omitempty"`
 	S3       *DataVolumeSourceS3       `json:"s3,omitempty"`
 	GCS      *DataVolumeSourceGCS      `json:"gcs,omitempty"`
+	SFTP     *DataVolumeSourceSFTP     `json:"sftp,omitempty"`
+	SMB      *DataVolumeSourceSMB      `json:"smb,omitempty"`
+	NFS      *DataVolumeSourceNFS      `json:"nfs,omitempty"`
 	Registry *DataVolumeSourceRegistry `json:"registry,omitempty"`
 	PVC      *DataVolumeSourcePVC      `json:"pvc,omitempty"`
 	Upload   *DataVolumeSourceUpload   `json:"upload,omitempty"`
@@ -149,6 +155,37 @@ type DataVolumeSourcePVC struct {
 	Namespace string `json:"namespace"`
 	// The name of the source PVC
 	Name string `json:"name"`
+	// Cluster references the remote cluster of the source PVC, the source PVC is in the local cluster when unset
+	// +optional
+	Cluster *CloneSourceCluster `json:"cluster,omitempty"`
+	// Transformation changes the disk image on the clone target, a clone with a transformation is always host assisted
+	// +optional
+	Transformation *CloneTransformation `json:"transformation,omitempty"`
+}
+
+// CloneTransformation describes the changes made to the disk image of the clone source on the clone target
+type CloneTransformation struct {
+	// Resize fits the disk image to the size of the target, shrinking it when the target is smaller than the source.
+	// Only filesystem targets can be smaller than the source, and the partitions or the filesystem of the disk image
+	// must fit in them.
+	// +optional
+	Resize bool `json:"resize,omitempty"`
+	// LogicalSectorSize converts the GPT partition table of the disk image to logical sectors of 512 or 4096 bytes
+	// +kubebuilder:validation:Enum=512;4096
+	// +optional
+	LogicalSectorSize *int32 `json:"logicalSectorSize,omitempty"`
+}
+
+// CloneSourceCluster references the remote cluster of a clone source
+type CloneSourceCluster struct {
+	// KubeconfigSecretRef is the name of the secret holding the kubeconfig of the remote cluster in its "kubeconfig" key.
+	// The secret is in the namespace of the clone target.
+	KubeconfigSecretRef string `json:"kubeconfigSecretRef"`
+	// ServiceDomain is the DNS domain through which the remote cluster reaches the services of the local cluster, like
+	// clusterset.local with multi-cluster services. When unset the remote cluster must resolve the service names of the
+	// local cluster.
+	// +optional
+	ServiceDomain string `json:"serviceDomain,omitempty"`
 }
 
 // DataVolumeSourceSnapshot provides the parameters to create a Data Volume from an existing VolumeSnapshot
@@ -174,6 +211,14 @@ type DataVolumeBlankImage struct{}
 
 // DataVolumeSourceUpload provides the parameters to create a Data Volume by uploading the source
 type DataVolumeSourceUpload struct {
+	// ManagedDataSource is the name of a DataSource in the namespace of the DataVolume, which is pointed to the uploaded
+	// image once the upload succeeds. The image is snapshotted first if the StorageProfile DataImportCronSourceFormat
+	// is snapshot. The DataSource is created if it does not exist.
+	// +optional
+	ManagedDataSource string `json:"managedDataSource,omitempty"`
+	// Number of uploads of the managed DataSource to keep, including the current one. Default is 3.
+	// +optional
+	UploadsToKeep *int32 `json:"uploadsToKeep,omitempty"`
 }
 
 // DataVolumeSourceS3 provides the parameters to create a Data Volume from an S3 source
@@ -185,6 +230,11 @@ type DataVolumeSourceS3 struct {
 	// CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate
 	// +optional
 	CertConfigMap string `json:"certConfigMap,omitempty"`
+	// Checksum is the expected checksum of the object. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+	// Supported algorithms: md5, sha1, sha256, sha512
+	// If not specified, the importer verifies the downloaded content against the object ETag when it is an MD5 digest
+	// +optional
+	Checksum string `json:"checksum,omitempty"`
 }
 
 // DataVolumeSourceGCS provides the parameters to create a Data Volume from an GCS source
@@ -193,6 +243,58 @@ type DataVolumeSourceGCS struct {
 	URL string `json:"url"`
 	//SecretRef provides the secret reference needed to access the GCS source
 	SecretRef string `json:"secretRef,omitempty"`
+	// Checksum is the expected checksum of the object. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+	// Supported algorithms: md5, sha1, sha256, sha512
+	// If specified, the importer will verify the downloaded content matches this checksum
+	// +optional
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// DataVolumeSourceSFTP provides the parameters to create a Data Volume from a file on an SFTP server
+type DataVolumeSourceSFTP struct {
+	//URL is the url of the file on the SFTP server, e.g. "sftp://images.example.com:2222/images/disk.qcow2"
+	URL string `json:"url"`
+	//SecretRef provides the secret reference needed to log in to the SFTP server. The secret holds the user name in
+	//the accessKeyId key, and either the password in the secretKey key or a private key in the ssh-privatekey key
+	SecretRef string `json:"secretRef"`
+	//HostKeySecretRef provides the secret reference holding the host keys of the SFTP server in the known_hosts key,
+	//in the OpenSSH known_hosts format. The import fails when the server presents a key that is not listed
+	HostKeySecretRef string `json:"hostKeySecretRef"`
+	// Checksum is the expected checksum of the file. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+	// Supported algorithms: md5, sha1, sha256, sha512
+	// If specified, the importer will verify the downloaded content matches this checksum
+	// +optional
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// DataVolumeSourceSMB provides the parameters to create a Data Volume from a file on an SMB share
+type DataVolumeSourceSMB struct {
+	//URL is the url of the file on the SMB share, starting with the share name, e.g. "smb://fileserver/images/disk.qcow2"
+	URL string `json:"url"`
+	//SecretRef provides the secret reference needed to access the SMB share. The secret holds the user name in the
+	//accessKeyId key and the password in the secretKey key. The share is accessed as guest when it is not set
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+	// Checksum is the expected checksum of the file. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+	// Supported algorithms: md5, sha1, sha256, sha512
+	// If specified, the importer will verify the downloaded content matches this checksum
+	// +optional
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// DataVolumeSourceNFS provides the parameters to create a Data Volume from a file on an NFS export. The export is
+// provided by the cluster administrator as a PersistentVolume, bound to a PVC in the namespace of the Data Volume
+type DataVolumeSourceNFS struct {
+	//ClaimName is the name of the PVC bound to the PersistentVolume of the NFS export, in the namespace of the Data Volume.
+	//It is mounted read only in the importer pod
+	ClaimName string `json:"claimName"`
+	//Path is the path of the file relative to the root of the volume, e.g. "images/disk.qcow2"
+	Path string `json:"path"`
+	// Checksum is the expected checksum of the file. Format: "algorithm:hash", e.g., "sha256:1234abcd..." or "md5:5678efgh..."
+	// Supported algorithms: md5, sha1, sha256, sha512
+	// If specified, the importer will verify the downloaded content matches this checksum
+	// +optional
+	Checksum string `json:"checksum,omitempty"`
 }
 
 // DataVolumeSourceRegistry provides the parameters to create a Data Volume from an registry source
@@ -215,6 +317,50 @@ type DataVolumeSourceRegistry struct {
 	//Platform describes the minimum runtime requirements of the image
 	// +optional
 	Platform *PlatformOptions `json:"platform,omitempty"`
+	//SignatureVerification is the policy used to verify the cosign signature of the image before it is imported
+	// +optional
+	SignatureVerification *RegistrySignatureVerification `json:"signatureVerification,omitempty"`
+	//DiskPath selects the disk to import from an image with several disks, by its path relative to the /disk directory,
+	//or by its title for OCI artifacts, e.g. "data.qcow2". Mutually exclusive with DiskIndex
+	// +optional
+	DiskPath *string `json:"diskPath,omitempty"`
+	//DiskIndex selects the disk to import from an image with several disks, by its zero based position in the order the
+	//disks were added to the image. Not supported with the node pull method. Mutually exclusive with DiskPath
+	// +optional
+	DiskIndex *int32 `json:"diskIndex,omitempty"`
+}
+
+// RegistrySignatureVerification defines how the cosign signature of a registry image is verified before it is imported.
+// Exactly one of PublicKey and Keyless must be set
+type RegistrySignatureVerification struct {
+	// PublicKey verifies signatures created with a cosign key pair
+	// +optional
+	PublicKey *SignaturePublicKey `json:"publicKey,omitempty"`
+	// Keyless verifies signatures created with a short lived Fulcio certificate and recorded in the Rekor transparency log
+	// +optional
+	Keyless *SignatureKeyless `json:"keyless,omitempty"`
+}
+
+// SignaturePublicKey references the PEM encoded public key stored in the "cosign.pub" key of a ConfigMap or Secret.
+// Exactly one of ConfigMapRef and SecretRef must be set
+type SignaturePublicKey struct {
+	// ConfigMapRef is the name of the ConfigMap containing the public key
+	// +optional
+	ConfigMapRef *string `json:"configMapRef,omitempty"`
+	// SecretRef is the name of the Secret containing the public key
+	// +optional
+	SecretRef *string `json:"secretRef,omitempty"`
+}
+
+// SignatureKeyless defines the trusted roots and the identity of the signer for keyless signatures
+type SignatureKeyless struct {
+	// TrustedRootConfigMapRef is the name of a ConfigMap containing the PEM encoded Fulcio certificates in the "fulcio.crt" key,
+	// and the PEM encoded Rekor public key in the "rekor.pub" key
+	TrustedRootConfigMapRef string `json:"trustedRootConfigMapRef"`
+	// Issuer is the OIDC issuer that authenticated the signer, e.g. "https://token.actions.githubusercontent.com"
+	Issuer string `json:"issuer"`
+	// Subject is the email address or URI identity of the signer
+	Subject string `json:"subject"`
 }
 
 type PlatformOptions struct {
@@ -261,6 +407,26 @@ type DataVolumeSourceHTTP struct {
 	// If specified, the importer will verify the downloaded content matches this checksum
 	// +optional
 	Checksum string `json:"checksum,omitempty"`
+	// ChecksumURL is the URL of a checksum file, e.g. SHA256SUMS, in BSD or GNU coreutils format.
+	// The importer fetches the file and verifies the downloaded content matches the checksum listed for the file name of the URL.
+	// Mutually exclusive with Checksum
+	// +optional
+	ChecksumURL string `json:"checksumURL,omitempty"`
+	// ChecksumKeyringSecretRef is the name of a Secret containing the armored PGP public keys trusted to sign the checksum file,
+	// in the "keyring.asc" key. The checksum file must be clearsigned by one of them. Signed checksum files are rejected without
+	// a keyring, since their signature cannot be verified. Requires ChecksumURL
+	// +optional
+	ChecksumKeyringSecretRef string `json:"checksumKeyringSecretRef,omitempty"`
+	// DiskPath selects the disk to import when the URL points to a tar archive or an OVA appliance with several disks,
+	// by its path in the archive, e.g. "disks/data.qcow2". Defaults to the first disk of the OVF descriptor of an OVA
+	// appliance. Requires the kubevirt content type. Mutually exclusive with DiskIndex
+	// +optional
+	DiskPath *string `json:"diskPath,omitempty"`
+	// DiskIndex selects the disk to import when the URL points to a tar archive or an OVA appliance with several disks,
+	// by its zero based position in the OVF descriptor of an OVA appliance, or among the files of a tar archive.
+	// Requires the kubevirt content type. Mutually exclusive with DiskPath
+	// +optional
+	DiskIndex *int32 `json:"diskIndex,omitempty"`
 	// InsecureSkipVerify is a flag to skip certificate verification for the HTTP endpoint
 	// +optional
 	InsecureSkipVerify *bool `json:"insecureSkipVerify,omitempty"`
@@ -429,6 +595,8 @@ const (
 	DataVolumeBound DataVolumeConditionType = "Bound"
 	// DataVolumeRunning is the condition that indicates if the import/upload/clone container is running.
 	DataVolumeRunning DataVolumeConditionType = "Running"
+	// DataVolumeCloneStrategy is the condition that indicates the strategy of a clone, and why the preferred ones were not possible.
+	DataVolumeCloneStrategy DataVolumeConditionType = "CloneStrategy"
 )
 
 // DataVolumeCloneSourceSubresource is the subresource checked for permission to clone
@@ -739,6 +907,9 @@ type ImportSourceType struct {
 	S3       *DataVolumeSourceS3       `json:"s3,omitempty"`
 	Registry *DataVolumeSourceRegistry `json:"registry,omitempty"`
 	GCS      *DataVolumeSourceGCS      `json:"gcs,omitempty"`
+	SFTP     *DataVolumeSourceSFTP     `json:"sftp,omitempty"`
+	SMB      *DataVolumeSourceSMB      `json:"smb,omitempty"`
+	NFS      *DataVolumeSourceNFS      `json:"nfs,omitempty"`
 	Blank    *DataVolumeBlankImage     `json:"blank,omitempty"`
 	Imageio  *DataVolumeSourceImageIO  `json:"imageio,omitempty"`
 	VDDK     *DataVolumeSourceVDDK     `json:"vddk,omitempty"`
@@ -828,6 +999,10 @@ type VolumeCloneSourceSpec struct {
 	// PriorityClassName is the priorityclass for the claim
 	// +optional
 	PriorityClassName *string `json:"priorityClassName,omitempty"`
+
+	// CloneStrategy lists the strategies acceptable for the clone, overriding the storage profile and the CDI clone strategy
+	// +optional
+	CloneStrategy *CloneStrategyPolicy `json:"cloneStrategy,omitempty"`
 }
 
 // VolumeCloneSourceList provides the needed parameters to do request a list of VolumeCloneSources from the system
@@ -839,6 +1014,115 @@ type VolumeCloneSourceList struct {
 	Items []VolumeCloneSource `json:"items"`
 }
 
+// ExportJob exports the contents of a PVC to object storage or to a container registry
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:shortName=ej;ejs,categories=all
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The phase of the export"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+type ExportJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ExportJobSpec `json:"spec"`
+	// +optional
+	Status ExportJobStatus `json:"status,omitempty"`
+}
+
+// ExportJobSpec defines specification for ExportJob
+type ExportJobSpec struct {
+	// Source is the PersistentVolumeClaim or DataVolume exported, in the namespace of the ExportJob
+	Source corev1.TypedLocalObjectReference `json:"source"`
+	// Target is where the contents of the source are exported to
+	Target ExportJobTarget `json:"target"`
+	// Format is the format of the exported image, raw (default) or qcow2
+	// +optional
+	Format *ExportJobFormat `json:"format,omitempty"`
+	// Compress compresses the exported image with gzip. Only supported for S3 targets, the layers of container disks
+	// are always compressed
+	// +optional
+	Compress *bool `json:"compress,omitempty"`
+}
+
+// ExportJobTarget is the target of an ExportJob, exactly one of the targets has to be set
+type ExportJobTarget struct {
+	// +optional
+	S3 *ExportJobTargetS3 `json:"s3,omitempty"`
+	// +optional
+	Registry *ExportJobTargetRegistry `json:"registry,omitempty"`
+}
+
+// ExportJobTargetS3 uploads the exported image as an S3 object
+type ExportJobTargetS3 struct {
+	//URL is the url of the S3 object the image is uploaded to
+	URL string `json:"url"`
+	//SecretRef provides the secret reference needed to access the S3 target, it has the same keys as for S3 sources
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+	// CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate
+	// +optional
+	CertConfigMap string `json:"certConfigMap,omitempty"`
+}
+
+// ExportJobTargetRegistry pushes the exported image as a container disk
+type ExportJobTargetRegistry struct {
+	//URL is the url of the container disk image (starting with the docker scheme), e.g. docker://registry:5000/disks/fedora:latest
+	URL string `json:"url"`
+	//SecretRef provides the secret reference needed to access the Registry target
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+	//CertConfigMap provides a reference to the Registry certs
+	// +optional
+	CertConfigMap string `json:"certConfigMap,omitempty"`
+}
+
+// ExportJobFormat is the format of the image exported by an ExportJob
+type ExportJobFormat string
+
+const (
+	// ExportJobFormatRaw exports the raw image
+	ExportJobFormatRaw ExportJobFormat = "raw"
+	// ExportJobFormatQcow2 exports the image converted to qcow2
+	ExportJobFormatQcow2 ExportJobFormat = "qcow2"
+)
+
+// ExportJobPhase is the phase of an ExportJob
+type ExportJobPhase string
+
+const (
+	// ExportJobPending is the phase of an ExportJob waiting for its source
+	ExportJobPending ExportJobPhase = "Pending"
+	// ExportJobRunning is the phase of an ExportJob while the source is exported
+	ExportJobRunning ExportJobPhase = "Running"
+	// ExportJobSucceeded is the phase of an ExportJob which exported its source
+	ExportJobSucceeded ExportJobPhase = "Succeeded"
+	// ExportJobFailed is the (terminal) phase of an ExportJob which failed
+	ExportJobFailed ExportJobPhase = "Failed"
+)
+
+// ExportJobStatus provides the most recently observed status of the ExportJob
+type ExportJobStatus struct {
+	// Phase is the current phase of the export
+	Phase ExportJobPhase `json:"phase,omitempty"`
+	// Message is a human-readable message about the phase, like why the export failed
+	Message string `json:"message,omitempty"`
+	// Digest is the digest of the exported data, the sha256 of the S3 object or the manifest digest of the container disk
+	Digest string `json:"digest,omitempty"`
+}
+
+// ExportJobList provides the needed parameters to do request a list of ExportJobs from the system
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ExportJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	// Items provides a list of ExportJobs
+	Items []ExportJob `json:"items"`
+}
+
 // this has to be here otherwise informer-gen doesn't recognize it
 // see https://github.com/kubernetes/code-generator/issues/59
 // +genclient:nonNamespaced
@@ -883,6 +1167,38 @@ type CDICertConfig struct {
 	// Client configuration
 	// Certs are rotated and discarded
 	Client *CertConfig `json:"client,omitempty"`
+
+	// TokenSigningKey configuration
+	// Keys signing upload and clone tokens are rotated, and trusted to verify tokens during the overlap
+	TokenSigningKey *TokenSigningKeyConfig `json:"tokenSigningKey,omitempty"`
+}
+
+// TokenSigningKeyAlgorithm is the algorithm of the key signing upload and clone tokens
+type TokenSigningKeyAlgorithm string
+
+const (
+	// TokenSigningKeyRSA is a 2048 bits RSA key
+	TokenSigningKeyRSA TokenSigningKeyAlgorithm = "RSA"
+	// TokenSigningKeyECDSA is an ECDSA key on the P-256 curve
+	TokenSigningKeyECDSA TokenSigningKeyAlgorithm = "ECDSA"
+	// TokenSigningKeyEd25519 is an Ed25519 key
+	TokenSigningKeyEd25519 TokenSigningKeyAlgorithm = "Ed25519"
+)
+
+// TokenSigningKeyConfig contains the tunables for the key signing upload and clone tokens
+type TokenSigningKeyConfig struct {
+	// Algorithm of the signing key, RSA by default
+	// +kubebuilder:validation:Enum=RSA;ECDSA;Ed25519
+	// +optional
+	Algorithm TokenSigningKeyAlgorithm `json:"algorithm,omitempty"`
+
+	// The requested 'duration' of the key, how long it signs new tokens before it is rotated
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// The amount of time a rotated key is still trusted to verify the tokens it signed
+	// +optional
+	Overlap *metav1.Duration `json:"overlap,omitempty"`
 }
 
 // CDISpec defines our specification for the CDI installation
@@ -938,6 +1254,31 @@ const (
 	CloneStrategyCsiClone CDICloneStrategy = "csi-clone"
 )
 
+// CloneStrategyPolicy is the ordered list of the strategies acceptable for a clone
+type CloneStrategyPolicy struct {
+	// Strategies are the acceptable clone strategies in order of preference, the first one possible for the source and
+	// target is used. When empty, the strategy of the storage profile or the CDI clone strategy is the only one.
+	// +optional
+	// +listType=atomic
+	Strategies []CDICloneStrategy `json:"strategies,omitempty"`
+	// Fallback is what happens when none of the strategies is possible, the clone either falls back to a host-assisted
+	// copy (HostAssisted, the default) or fails (Fail)
+	// +optional
+	// +kubebuilder:validation:Enum="HostAssisted";"Fail"
+	Fallback *CloneFallback `json:"fallback,omitempty"`
+}
+
+// CloneFallback defines what happens when none of the acceptable clone strategies is possible
+type CloneFallback string
+
+const (
+	// CloneFallbackHostAssisted falls back to a host-assisted copy
+	CloneFallbackHostAssisted CloneFallback = "HostAssisted"
+
+	// CloneFallbackFail fails the clone
+	CloneFallbackFail CloneFallback = "Fail"
+)
+
 // CustomizeComponents defines patches for components deployed by the CDI operator.
 type CustomizeComponents struct {
 	// +listType=atomic
@@ -22,6 +22,7 @@ func (DataVolumeSpec) SwaggerDoc() map[string]string {
 		"checkpoints":        "Checkpoints is a list of DataVolumeCheckpoints, representing stages in a multistage import.",
 		"finalCheckpoint":    "FinalCheckpoint indicates whether the current DataVolumeCheckpoint is the final checkpoint.",
 		"preallocation":      "Preallocation controls whether storage for DataVolumes should be allocated in advance.",
+		"cloneStrategy":      "CloneStrategy lists the strategies acceptable for a clone, overriding the storage profile and the CDI clone strategy\n+optional",
 	}
 }
 
@@ -49,15 +50,33 @@ func (DataVolumeCheckpoint) SwaggerDoc() map[string]string {
 
 func (DataVolumeSource) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"": "DataVolumeSource represents the source for our Data Volume, this can be HTTP, Imageio, S3, GCS, Registry or an existing PVC",
+		"": "DataVolumeSource represents the source for our Data Volume, this can be HTTP, Imageio, S3, GCS, SFTP, SMB, NFS, Registry or an existing PVC",
 	}
 }
 
 func (DataVolumeSourcePVC) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":          "DataVolumeSourcePVC provides the parameters to create a Data Volume from an existing PVC",
-		"namespace": "The namespace of the source PVC",
-		"name":      "The name of the source PVC",
+		"":               "DataVolumeSourcePVC provides the parameters to create a Data Volume from an existing PVC",
+		"namespace":      "The namespace of the source PVC",
+		"name":           "The name of the source PVC",
+		"cluster":        "Cluster references the remote cluster of the source PVC, the source PVC is in the local cluster when unset\n+optional",
+		"transformation": "Transformation changes the disk image on the clone target, a clone with a transformation is always host assisted\n+optional",
+	}
+}
+
+func (CloneTransformation) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                  "CloneTransformation describes the changes made to the disk image of the clone source on the clone target",
+		"resize":            "Resize fits the disk image to the size of the target, shrinking it when the target is smaller than the source.\nOnly filesystem targets can be smaller than the source, and the partitions or the filesystem of the disk image\nmust fit in them.\n+optional",
+		"logicalSectorSize": "LogicalSectorSize converts the GPT partition table of the disk image to logical sectors of 512 or 4096 bytes\n+kubebuilder:validation:Enum=512;4096\n+optional",
+	}
+}
+
+func (CloneSourceCluster) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                    "CloneSourceCluster references the remote cluster of a clone source",
+		"kubeconfigSecretRef": "KubeconfigSecretRef is the name of the secret holding the kubeconfig of the remote cluster in its \"kubeconfig\" key.\nThe secret is in the namespace of the clone target.",
+		"serviceDomain":       "ServiceDomain is the DNS domain through which the remote cluster reaches the services of the local cluster, like\nclusterset.local with multi-cluster services. When unset the remote cluster must resolve the service names of the\nlocal cluster.\n+optional",
 	}
 }
 
@@ -85,7 +104,9 @@ func (DataVolumeBlankImage) SwaggerDoc() map[string]string {
 
 func (DataVolumeSourceUpload) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"": "DataVolumeSourceUpload provides the parameters to create a Data Volume by uploading the source",
+		"":                  "DataVolumeSourceUpload provides the parameters to create a Data Volume by uploading the source",
+		"managedDataSource": "ManagedDataSource is the name of a DataSource in the namespace of the DataVolume, which is pointed to the uploaded\nimage once the upload succeeds. The image is snapshotted first if the StorageProfile DataImportCronSourceFormat\nis snapshot. The DataSource is created if it does not exist.\n+optional",
+		"uploadsToKeep":     "Number of uploads of the managed DataSource to keep, including the current one. Default is 3.\n+optional",
 	}
 }
 
@@ -95,6 +116,7 @@ func (DataVolumeSourceS3) SwaggerDoc() map[string]string {
 		"url":           "URL is the url of the S3 source",
 		"secretRef":     "SecretRef provides the secret reference needed to access the S3 source",
 		"certConfigMap": "CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate\n+optional",
+		"checksum":      "Checksum is the expected checksum of the object. Format: \"algorithm:hash\", e.g., \"sha256:1234abcd...\" or \"md5:5678efgh...\"\nSupported algorithms: md5, sha1, sha256, sha512\nIf not specified, the importer verifies the downloaded content against the object ETag when it is an MD5 digest\n+optional",
 	}
 }
 
@@ -103,18 +125,75 @@ func (DataVolumeSourceGCS) SwaggerDoc() map[string]string {
 		"":          "DataVolumeSourceGCS provides the parameters to create a Data Volume from an GCS source",
 		"url":       "URL is the url of the GCS source",
 		"secretRef": "SecretRef provides the secret reference needed to access the GCS source",
+		"checksum":  "Checksum is the expected checksum of the object. Format: \"algorithm:hash\", e.g., \"sha256:1234abcd...\" or \"md5:5678efgh...\"\nSupported algorithms: md5, sha1, sha256, sha512\nIf specified, the importer will verify the downloaded content matches this checksum\n+optional",
+	}
+}
+
+func (DataVolumeSourceSFTP) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                 "DataVolumeSourceSFTP provides the parameters to create a Data Volume from a file on an SFTP server",
+		"url":              "URL is the url of the file on the SFTP server, e.g. \"sftp://images.example.com:2222/images/disk.qcow2\"",
+		"secretRef":        "SecretRef provides the secret reference needed to log in to the SFTP server. The secret holds the user name in\nthe accessKeyId key, and either the password in the secretKey key or a private key in the ssh-privatekey key",
+		"hostKeySecretRef": "HostKeySecretRef provides the secret reference holding the host keys of the SFTP server in the known_hosts key,\nin the OpenSSH known_hosts format. The import fails when the server presents a key that is not listed",
+		"checksum":         "Checksum is the expected checksum of the file. Format: \"algorithm:hash\", e.g., \"sha256:1234abcd...\" or \"md5:5678efgh...\"\nSupported algorithms: md5, sha1, sha256, sha512\nIf specified, the importer will verify the downloaded content matches this checksum\n+optional",
+	}
+}
+
+func (DataVolumeSourceSMB) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "DataVolumeSourceSMB provides the parameters to create a Data Volume from a file on an SMB share",
+		"url":       "URL is the url of the file on the SMB share, starting with the share name, e.g. \"smb://fileserver/images/disk.qcow2\"",
+		"secretRef": "SecretRef provides the secret reference needed to access the SMB share. The secret holds the user name in the\naccessKeyId key and the password in the secretKey key. The share is accessed as guest when it is not set\n+optional",
+		"checksum":  "Checksum is the expected checksum of the file. Format: \"algorithm:hash\", e.g., \"sha256:1234abcd...\" or \"md5:5678efgh...\"\nSupported algorithms: md5, sha1, sha256, sha512\nIf specified, the importer will verify the downloaded content matches this checksum\n+optional",
+	}
+}
+
+func (DataVolumeSourceNFS) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "DataVolumeSourceNFS provides the parameters to create a Data Volume from a file on an NFS export. The export is\nprovided by the cluster administrator as a PersistentVolume, bound to a PVC in the namespace of the Data Volume",
+		"claimName": "ClaimName is the name of the PVC bound to the PersistentVolume of the NFS export, in the namespace of the Data Volume.\nIt is mounted read only in the importer pod",
+		"path":      "Path is the path of the file relative to the root of the volume, e.g. \"images/disk.qcow2\"",
+		"checksum":  "Checksum is the expected checksum of the file. Format: \"algorithm:hash\", e.g., \"sha256:1234abcd...\" or \"md5:5678efgh...\"\nSupported algorithms: md5, sha1, sha256, sha512\nIf specified, the importer will verify the downloaded content matches this checksum\n+optional",
 	}
 }
 
 func (DataVolumeSourceRegistry) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":              "DataVolumeSourceRegistry provides the parameters to create a Data Volume from an registry source",
-		"url":           "URL is the url of the registry source (starting with the scheme: docker, oci-archive)\n+optional",
-		"imageStream":   "ImageStream is the name of image stream for import\n+optional",
-		"pullMethod":    "PullMethod can be either \"pod\" (default import), or \"node\" (node docker cache based import)\n+optional",
-		"secretRef":     "SecretRef provides the secret reference needed to access the Registry source\n+optional",
-		"certConfigMap": "CertConfigMap provides a reference to the Registry certs\n+optional",
-		"platform":      "Platform describes the minimum runtime requirements of the image\n+optional",
+		"":                      "DataVolumeSourceRegistry provides the parameters to create a Data Volume from an registry source",
+		"url":                   "URL is the url of the registry source (starting with the scheme: docker, oci-archive)\n+optional",
+		"imageStream":           "ImageStream is the name of image stream for import\n+optional",
+		"pullMethod":            "PullMethod can be either \"pod\" (default import), or \"node\" (node docker cache based import)\n+optional",
+		"secretRef":             "SecretRef provides the secret reference needed to access the Registry source\n+optional",
+		"certConfigMap":         "CertConfigMap provides a reference to the Registry certs\n+optional",
+		"platform":              "Platform describes the minimum runtime requirements of the image\n+optional",
+		"signatureVerification": "SignatureVerification is the policy used to verify the cosign signature of the image before it is imported\n+optional",
+		"diskPath":              "DiskPath selects the disk to import from an image with several disks, by its path relative to the /disk directory,\nor by its title for OCI artifacts, e.g. \"data.qcow2\". Mutually exclusive with DiskIndex\n+optional",
+		"diskIndex":             "DiskIndex selects the disk to import from an image with several disks, by its zero based position in the order the\ndisks were added to the image. Not supported with the node pull method. Mutually exclusive with DiskPath\n+optional",
+	}
+}
+
+func (RegistrySignatureVerification) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "RegistrySignatureVerification defines how the cosign signature of a registry image is verified before it is imported.\nExactly one of PublicKey and Keyless must be set",
+		"publicKey": "PublicKey verifies signatures created with a cosign key pair\n+optional",
+		"keyless":   "Keyless verifies signatures created with a short lived Fulcio certificate and recorded in the Rekor transparency log\n+optional",
+	}
+}
+
+func (SignaturePublicKey) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":             "SignaturePublicKey references the PEM encoded public key stored in the \"cosign.pub\" key of a ConfigMap or Secret.\nExactly one of ConfigMapRef and SecretRef must be set",
+		"configMapRef": "ConfigMapRef is the name of the ConfigMap containing the public key\n+optional",
+		"secretRef":    "SecretRef is the name of the Secret containing the public key\n+optional",
+	}
+}
+
+func (SignatureKeyless) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                        "SignatureKeyless defines the trusted roots and the identity of the signer for keyless signatures",
+		"trustedRootConfigMapRef": "TrustedRootConfigMapRef is the name of a ConfigMap containing the PEM encoded Fulcio certificates in the \"fulcio.crt\" key,\nand the PEM encoded Rekor public key in the \"rekor.pub\" key",
+		"issuer":                  "Issuer is the OIDC issuer that authenticated the signer, e.g. \"https://token.actions.githubusercontent.com\"",
+		"subject":                 "Subject is the email address or URI identity of the signer",
 	}
 }
 
@@ -126,14 +205,18 @@ func (PlatformOptions) SwaggerDoc() map[string]string {
 
 func (DataVolumeSourceHTTP) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":                   "DataVolumeSourceHTTP can be either an http or https endpoint, with an optional basic auth user name and password, and an optional configmap containing additional CAs",
-		"url":                "URL is the URL of the http(s) endpoint",
-		"secretRef":          "SecretRef A Secret reference, the secret should contain accessKeyId (user name) base64 encoded, and secretKey (password) also base64 encoded\n+optional",
-		"certConfigMap":      "CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate\n+optional",
-		"extraHeaders":       "ExtraHeaders is a list of strings containing extra headers to include with HTTP transfer requests\n+optional",
-		"secretExtraHeaders": "SecretExtraHeaders is a list of Secret references, each containing an extra HTTP header that may include sensitive information\n+optional",
-		"checksum":           "Checksum is the expected checksum of the file. Format: \"algorithm:hash\", e.g., \"sha256:1234abcd...\" or \"md5:5678efgh...\"\nSupported algorithms: md5, sha1, sha256, sha512\nIf specified, the importer will verify the downloaded content matches this checksum\n+optional",
-		"insecureSkipVerify": "InsecureSkipVerify is a flag to skip certificate verification for the HTTP endpoint\n+optional",
+		"":                         "DataVolumeSourceHTTP can be either an http or https endpoint, with an optional basic auth user name and password, and an optional configmap containing additional CAs",
+		"url":                      "URL is the URL of the http(s) endpoint",
+		"secretRef":                "SecretRef A Secret reference, the secret should contain accessKeyId (user name) base64 encoded, and secretKey (password) also base64 encoded\n+optional",
+		"certConfigMap":            "CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate\n+optional",
+		"extraHeaders":             "ExtraHeaders is a list of strings containing extra headers to include with HTTP transfer requests\n+optional",
+		"secretExtraHeaders":       "SecretExtraHeaders is a list of Secret references, each containing an extra HTTP header that may include sensitive information\n+optional",
+		"checksum":                 "Checksum is the expected checksum of the file. Format: \"algorithm:hash\", e.g., \"sha256:1234abcd...\" or \"md5:5678efgh...\"\nSupported algorithms: md5, sha1, sha256, sha512\nIf specified, the importer will verify the downloaded content matches this checksum\n+optional",
+		"checksumURL":              "ChecksumURL is the URL of a checksum file, e.g. SHA256SUMS, in BSD or GNU coreutils format.\nThe importer fetches the file and verifies the downloaded content matches the checksum listed for the file name of the URL.\nMutually exclusive with Checksum\n+optional",
+		"checksumKeyringSecretRef": "ChecksumKeyringSecretRef is the name of a Secret containing the armored PGP public keys trusted to sign the checksum file,\nin the \"keyring.asc\" key. The checksum file must be clearsigned by one of them. Signed checksum files are rejected without\na keyring, since their signature cannot be verified. Requires ChecksumURL\n+optional",
+		"diskPath":                 "DiskPath selects the disk to import when the URL points to a tar archive or an OVA appliance with several disks,\nby its path in the archive, e.g. \"disks/data.qcow2\". Defaults to the first disk of the OVF descriptor of an OVA\nappliance. Requires the kubevirt content type. Mutually exclusive with DiskIndex\n+optional",
+		"diskIndex":                "DiskIndex selects the disk to import when the URL points to a tar archive or an OVA appliance with several disks,\nby its zero based position in the OVF descriptor of an OVA appliance, or among the files of a tar archive.\nRequires the kubevirt content type. Mutually exclusive with DiskPath\n+optional",
+		"insecureSkipVerify":       "InsecureSkipVerify is a flag to skip certificate verification for the HTTP endpoint\n+optional",
 	}
 }
 
@@ -420,6 +503,7 @@ func (VolumeCloneSourceSpec) SwaggerDoc() map[string]string {
 		"source":            "Source is the src of the data to be cloned to the target PVC",
 		"preallocation":     "Preallocation controls whether storage for the target PVC should be allocated in advance.\n+optional",
 		"priorityClassName": "PriorityClassName is the priorityclass for the claim\n+optional",
+		"cloneStrategy":     "CloneStrategy lists the strategies acceptable for the clone, overriding the storage profile and the CDI clone strategy\n+optional",
 	}
 }
 
@@ -430,6 +514,65 @@ func (VolumeCloneSourceList) SwaggerDoc() map[string]string {
 	}
 }
 
+func (ExportJob) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":       "ExportJob exports the contents of a PVC to object storage or to a container registry\n+genclient\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+kubebuilder:object:root=true\n+kubebuilder:storageversion\n+kubebuilder:resource:shortName=ej;ejs,categories=all\n+kubebuilder:printcolumn:name=\"Phase\",type=\"string\",JSONPath=\".status.phase\",description=\"The phase of the export\"\n+kubebuilder:printcolumn:name=\"Age\",type=\"date\",JSONPath=\".metadata.creationTimestamp\"\n+kubebuilder:subresource:status",
+		"status": "+optional",
+	}
+}
+
+func (ExportJobSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":         "ExportJobSpec defines specification for ExportJob",
+		"source":   "Source is the PersistentVolumeClaim or DataVolume exported, in the namespace of the ExportJob",
+		"target":   "Target is where the contents of the source are exported to",
+		"format":   "Format is the format of the exported image, raw (default) or qcow2\n+optional",
+		"compress": "Compress compresses the exported image with gzip. Only supported for S3 targets, the layers of container disks\nare always compressed\n+optional",
+	}
+}
+
+func (ExportJobTarget) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":         "ExportJobTarget is the target of an ExportJob, exactly one of the targets has to be set",
+		"s3":       "+optional",
+		"registry": "+optional",
+	}
+}
+
+func (ExportJobTargetS3) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "ExportJobTargetS3 uploads the exported image as an S3 object",
+		"url":           "URL is the url of the S3 object the image is uploaded to",
+		"secretRef":     "SecretRef provides the secret reference needed to access the S3 target, it has the same keys as for S3 sources\n+optional",
+		"certConfigMap": "CertConfigMap is a configmap reference, containing a Certificate Authority(CA) public key, and a base64 encoded pem certificate\n+optional",
+	}
+}
+
+func (ExportJobTargetRegistry) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "ExportJobTargetRegistry pushes the exported image as a container disk",
+		"url":           "URL is the url of the container disk image (starting with the docker scheme), e.g. docker://registry:5000/disks/fedora:latest",
+		"secretRef":     "SecretRef provides the secret reference needed to access the Registry target\n+optional",
+		"certConfigMap": "CertConfigMap provides a reference to the Registry certs\n+optional",
+	}
+}
+
+func (ExportJobStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":        "ExportJobStatus provides the most recently observed status of the ExportJob",
+		"phase":   "Phase is the current phase of the export",
+		"message": "Message is a human-readable message about the phase, like why the export failed",
+		"digest":  "Digest is the digest of the exported data, the sha256 of the S3 object or the manifest digest of the container disk",
+	}
+}
+
+func (ExportJobList) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":      "ExportJobList provides the needed parameters to do request a list of ExportJobs from the system\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object",
+		"items": "Items provides a list of ExportJobs",
+	}
+}
+
 func (CDI) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":       "CDI is the CDI Operator CRD\n+genclient\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+kubebuilder:object:root=true\n+kubebuilder:storageversion\n+kubebuilder:resource:shortName=cdi;cdis,scope=Cluster\n+kubebuilder:printcolumn:name=\"Age\",type=\"date\",JSONPath=\".metadata.creationTimestamp\"\n+kubebuilder:printcolumn:name=\"Phase\",type=\"string\",JSONPath=\".status.phase\"",
@@ -447,10 +590,20 @@ func (CertConfig) SwaggerDoc() map[string]string {
 
 func (CDICertConfig) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":       "CDICertConfig has the CertConfigs for CDI",
-		"ca":     "CA configuration\nCA certs are kept in the CA bundle as long as they are valid",
-		"server": "Server configuration\nCerts are rotated and discarded",
-		"client": "Client configuration\nCerts are rotated and discarded",
+		"":                "CDICertConfig has the CertConfigs for CDI",
+		"ca":              "CA configuration\nCA certs are kept in the CA bundle as long as they are valid",
+		"server":          "Server configuration\nCerts are rotated and discarded",
+		"client":          "Client configuration\nCerts are rotated and discarded",
+		"tokenSigningKey": "TokenSigningKey configuration\nKeys signing upload and clone tokens are rotated, and trusted to verify tokens during the overlap",
+	}
+}
+
+func (TokenSigningKeyConfig) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "TokenSigningKeyConfig contains the tunables for the key signing upload and clone tokens",
+		"algorithm": "Algorithm of the signing key, RSA by default\n+kubebuilder:validation:Enum=RSA;ECDSA;Ed25519\n+optional",
+		"duration":  "The requested 'duration' of the key, how long it signs new tokens before it is rotated\n+optional",
+		"overlap":   "The amount of time a rotated key is still trusted to verify the tokens it signed\n+optional",
 	}
 }
 
@@ -477,6 +630,14 @@ func (ComponentConfig) SwaggerDoc() map[string]string {
 	}
 }
 
+func (CloneStrategyPolicy) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":           "CloneStrategyPolicy is the ordered list of the strategies acceptable for a clone",
+		"strategies": "Strategies are the acceptable clone strategies in order of preference, the first one possible for the source and\ntarget is used. When empty, the strategy of the storage profile or the CDI clone strategy is the only one.\n+optional\n+listType=atomic",
+		"fallback":   "Fallback is what happens when none of the strategies is possible, the clone either falls back to a host-assisted\ncopy (HostAssisted, the default) or fails (Fail)\n+optional\n+kubebuilder:validation:Enum=\"HostAssisted\";\"Fail\"",
+	}
+}
+
 func (CustomizeComponents) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":        "CustomizeComponents defines patches for components deployed by the CDI operator.",
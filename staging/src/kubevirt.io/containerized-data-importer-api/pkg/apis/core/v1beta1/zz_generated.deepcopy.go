@@ -73,6 +73,11 @@ func (in *CDICertConfig) DeepCopyInto(out *CDICertConfig) {
 		*out = new(CertConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TokenSigningKey != nil {
+		in, out := &in.TokenSigningKey, &out.TokenSigningKey
+		*out = new(TokenSigningKeyConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -415,6 +420,69 @@ func (in *ClaimPropertySet) DeepCopy() *ClaimPropertySet {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSourceCluster) DeepCopyInto(out *CloneSourceCluster) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSourceCluster.
+func (in *CloneSourceCluster) DeepCopy() *CloneSourceCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSourceCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneStrategyPolicy) DeepCopyInto(out *CloneStrategyPolicy) {
+	*out = *in
+	if in.Strategies != nil {
+		in, out := &in.Strategies, &out.Strategies
+		*out = make([]CDICloneStrategy, len(*in))
+		copy(*out, *in)
+	}
+	if in.Fallback != nil {
+		in, out := &in.Fallback, &out.Fallback
+		*out = new(CloneFallback)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneStrategyPolicy.
+func (in *CloneStrategyPolicy) DeepCopy() *CloneStrategyPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneStrategyPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneTransformation) DeepCopyInto(out *CloneTransformation) {
+	*out = *in
+	if in.LogicalSectorSize != nil {
+		in, out := &in.LogicalSectorSize, &out.LogicalSectorSize
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneTransformation.
+func (in *CloneTransformation) DeepCopy() *CloneTransformation {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneTransformation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentConfig) DeepCopyInto(out *ComponentConfig) {
 	*out = *in
@@ -650,7 +718,7 @@ func (in *DataImportCronStatus) DeepCopyInto(out *DataImportCronStatus) {
 	if in.LastImportedPVC != nil {
 		in, out := &in.LastImportedPVC, &out.LastImportedPVC
 		*out = new(DataVolumeSourcePVC)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.LastExecutionTimestamp != nil {
 		in, out := &in.LastExecutionTimestamp, &out.LastExecutionTimestamp
@@ -785,7 +853,7 @@ func (in *DataSourceSource) DeepCopyInto(out *DataSourceSource) {
 	if in.PVC != nil {
 		in, out := &in.PVC, &out.PVC
 		*out = new(DataVolumeSourcePVC)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Snapshot != nil {
 		in, out := &in.Snapshot, &out.Snapshot
@@ -980,6 +1048,21 @@ func (in *DataVolumeSource) DeepCopyInto(out *DataVolumeSource) {
 		*out = new(DataVolumeSourceGCS)
 		**out = **in
 	}
+	if in.SFTP != nil {
+		in, out := &in.SFTP, &out.SFTP
+		*out = new(DataVolumeSourceSFTP)
+		**out = **in
+	}
+	if in.SMB != nil {
+		in, out := &in.SMB, &out.SMB
+		*out = new(DataVolumeSourceSMB)
+		**out = **in
+	}
+	if in.NFS != nil {
+		in, out := &in.NFS, &out.NFS
+		*out = new(DataVolumeSourceNFS)
+		**out = **in
+	}
 	if in.Registry != nil {
 		in, out := &in.Registry, &out.Registry
 		*out = new(DataVolumeSourceRegistry)
@@ -988,12 +1071,12 @@ func (in *DataVolumeSource) DeepCopyInto(out *DataVolumeSource) {
 	if in.PVC != nil {
 		in, out := &in.PVC, &out.PVC
 		*out = new(DataVolumeSourcePVC)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Upload != nil {
 		in, out := &in.Upload, &out.Upload
 		*out = new(DataVolumeSourceUpload)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Blank != nil {
 		in, out := &in.Blank, &out.Blank
@@ -1057,6 +1140,16 @@ func (in *DataVolumeSourceHTTP) DeepCopyInto(out *DataVolumeSourceHTTP) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.DiskPath != nil {
+		in, out := &in.DiskPath, &out.DiskPath
+		*out = new(string)
+		**out = **in
+	}
+	if in.DiskIndex != nil {
+		in, out := &in.DiskIndex, &out.DiskIndex
+		*out = new(int32)
+		**out = **in
+	}
 	if in.InsecureSkipVerify != nil {
 		in, out := &in.InsecureSkipVerify, &out.InsecureSkipVerify
 		*out = new(bool)
@@ -1096,9 +1189,35 @@ func (in *DataVolumeSourceImageIO) DeepCopy() *DataVolumeSourceImageIO {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceNFS) DeepCopyInto(out *DataVolumeSourceNFS) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceNFS.
+func (in *DataVolumeSourceNFS) DeepCopy() *DataVolumeSourceNFS {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceNFS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataVolumeSourcePVC) DeepCopyInto(out *DataVolumeSourcePVC) {
 	*out = *in
+	if in.Cluster != nil {
+		in, out := &in.Cluster, &out.Cluster
+		*out = new(CloneSourceCluster)
+		**out = **in
+	}
+	if in.Transformation != nil {
+		in, out := &in.Transformation, &out.Transformation
+		*out = new(CloneTransformation)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1166,6 +1285,21 @@ func (in *DataVolumeSourceRegistry) DeepCopyInto(out *DataVolumeSourceRegistry)
 		*out = new(PlatformOptions)
 		**out = **in
 	}
+	if in.SignatureVerification != nil {
+		in, out := &in.SignatureVerification, &out.SignatureVerification
+		*out = new(RegistrySignatureVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiskPath != nil {
+		in, out := &in.DiskPath, &out.DiskPath
+		*out = new(string)
+		**out = **in
+	}
+	if in.DiskIndex != nil {
+		in, out := &in.DiskIndex, &out.DiskIndex
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -1195,6 +1329,38 @@ func (in *DataVolumeSourceS3) DeepCopy() *DataVolumeSourceS3 {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceSFTP) DeepCopyInto(out *DataVolumeSourceSFTP) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceSFTP.
+func (in *DataVolumeSourceSFTP) DeepCopy() *DataVolumeSourceSFTP {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceSFTP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSourceSMB) DeepCopyInto(out *DataVolumeSourceSMB) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSourceSMB.
+func (in *DataVolumeSourceSMB) DeepCopy() *DataVolumeSourceSMB {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSourceSMB)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataVolumeSourceSnapshot) DeepCopyInto(out *DataVolumeSourceSnapshot) {
 	*out = *in
@@ -1214,6 +1380,11 @@ func (in *DataVolumeSourceSnapshot) DeepCopy() *DataVolumeSourceSnapshot {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataVolumeSourceUpload) DeepCopyInto(out *DataVolumeSourceUpload) {
 	*out = *in
+	if in.UploadsToKeep != nil {
+		in, out := &in.UploadsToKeep, &out.UploadsToKeep
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -1276,6 +1447,11 @@ func (in *DataVolumeSpec) DeepCopyInto(out *DataVolumeSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.CloneStrategy != nil {
+		in, out := &in.CloneStrategy, &out.CloneStrategy
+		*out = new(CloneStrategyPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1312,6 +1488,169 @@ func (in *DataVolumeStatus) DeepCopy() *DataVolumeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportJob) DeepCopyInto(out *ExportJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportJob.
+func (in *ExportJob) DeepCopy() *ExportJob {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExportJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportJobList) DeepCopyInto(out *ExportJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ExportJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportJobList.
+func (in *ExportJobList) DeepCopy() *ExportJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExportJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportJobSpec) DeepCopyInto(out *ExportJobSpec) {
+	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
+	in.Target.DeepCopyInto(&out.Target)
+	if in.Format != nil {
+		in, out := &in.Format, &out.Format
+		*out = new(ExportJobFormat)
+		**out = **in
+	}
+	if in.Compress != nil {
+		in, out := &in.Compress, &out.Compress
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportJobSpec.
+func (in *ExportJobSpec) DeepCopy() *ExportJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportJobStatus) DeepCopyInto(out *ExportJobStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportJobStatus.
+func (in *ExportJobStatus) DeepCopy() *ExportJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportJobTarget) DeepCopyInto(out *ExportJobTarget) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(ExportJobTargetS3)
+		**out = **in
+	}
+	if in.Registry != nil {
+		in, out := &in.Registry, &out.Registry
+		*out = new(ExportJobTargetRegistry)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportJobTarget.
+func (in *ExportJobTarget) DeepCopy() *ExportJobTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportJobTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportJobTargetRegistry) DeepCopyInto(out *ExportJobTargetRegistry) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportJobTargetRegistry.
+func (in *ExportJobTargetRegistry) DeepCopy() *ExportJobTargetRegistry {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportJobTargetRegistry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportJobTargetS3) DeepCopyInto(out *ExportJobTargetS3) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportJobTargetS3.
+func (in *ExportJobTargetS3) DeepCopy() *ExportJobTargetS3 {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportJobTargetS3)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FilesystemOverhead) DeepCopyInto(out *FilesystemOverhead) {
 	*out = *in
@@ -1431,6 +1770,21 @@ func (in *ImportSourceType) DeepCopyInto(out *ImportSourceType) {
 		*out = new(DataVolumeSourceGCS)
 		**out = **in
 	}
+	if in.SFTP != nil {
+		in, out := &in.SFTP, &out.SFTP
+		*out = new(DataVolumeSourceSFTP)
+		**out = **in
+	}
+	if in.SMB != nil {
+		in, out := &in.SMB, &out.SMB
+		*out = new(DataVolumeSourceSMB)
+		**out = **in
+	}
+	if in.NFS != nil {
+		in, out := &in.NFS, &out.NFS
+		*out = new(DataVolumeSourceNFS)
+		**out = **in
+	}
 	if in.Blank != nil {
 		in, out := &in.Blank, &out.Blank
 		*out = new(DataVolumeBlankImage)
@@ -1671,6 +2025,74 @@ func (in *PlatformOptions) DeepCopy() *PlatformOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistrySignatureVerification) DeepCopyInto(out *RegistrySignatureVerification) {
+	*out = *in
+	if in.PublicKey != nil {
+		in, out := &in.PublicKey, &out.PublicKey
+		*out = new(SignaturePublicKey)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Keyless != nil {
+		in, out := &in.Keyless, &out.Keyless
+		*out = new(SignatureKeyless)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistrySignatureVerification.
+func (in *RegistrySignatureVerification) DeepCopy() *RegistrySignatureVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistrySignatureVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SignatureKeyless) DeepCopyInto(out *SignatureKeyless) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SignatureKeyless.
+func (in *SignatureKeyless) DeepCopy() *SignatureKeyless {
+	if in == nil {
+		return nil
+	}
+	out := new(SignatureKeyless)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SignaturePublicKey) DeepCopyInto(out *SignaturePublicKey) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(string)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SignaturePublicKey.
+func (in *SignaturePublicKey) DeepCopy() *SignaturePublicKey {
+	if in == nil {
+		return nil
+	}
+	out := new(SignaturePublicKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageProfile) DeepCopyInto(out *StorageProfile) {
 	*out = *in
@@ -1946,6 +2368,32 @@ func (in *TLSSecurityProfile) DeepCopy() *TLSSecurityProfile {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenSigningKeyConfig) DeepCopyInto(out *TokenSigningKeyConfig) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Overlap != nil {
+		in, out := &in.Overlap, &out.Overlap
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenSigningKeyConfig.
+func (in *TokenSigningKeyConfig) DeepCopy() *TokenSigningKeyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenSigningKeyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TransferSource) DeepCopyInto(out *TransferSource) {
 	*out = *in
@@ -2069,6 +2517,11 @@ func (in *VolumeCloneSourceSpec) DeepCopyInto(out *VolumeCloneSourceSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.CloneStrategy != nil {
+		in, out := &in.CloneStrategy, &out.CloneStrategy
+		*out = new(CloneStrategyPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
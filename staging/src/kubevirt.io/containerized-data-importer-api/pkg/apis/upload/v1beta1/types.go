@@ -38,12 +38,30 @@ type UploadTokenRequest struct {
 type UploadTokenRequestSpec struct {
 	// PvcName is the name of the PVC to upload to
 	PvcName string `json:"pvcName"`
+	// Checksum is the checksum the uploaded data must match, in the "algorithm:hash" format, e.g. "sha256:<hash>".
+	// Supported algorithms are md5, sha1, sha256 and sha512
+	// +optional
+	Checksum string `json:"checksum,omitempty"`
+	// Lifetime is how long the token is valid, 5 minutes by default and at most 24 hours
+	// +optional
+	Lifetime *metav1.Duration `json:"lifetime,omitempty"`
+	// SingleUse tokens are only accepted for one upload request, resumable and range uploads need several requests
+	// +optional
+	SingleUse bool `json:"singleUse,omitempty"`
+	// ClientCIDRs restricts the clients allowed to use the token to the given address ranges (or single addresses)
+	// +optional
+	// +listType=atomic
+	ClientCIDRs []string `json:"clientCIDRs,omitempty"`
 }
 
 // UploadTokenRequestStatus stores the status of a token request
 type UploadTokenRequestStatus struct {
 	// Token is a JWT token to be inserted in "Authentication Bearer header"
 	Token string `json:"token,omitempty"`
+	// TokenID is the unique ID of the token, it can be used to revoke the token
+	TokenID string `json:"tokenID,omitempty"`
+	// ExpirationTimestamp is the time the token expires
+	ExpirationTimestamp *metav1.Time `json:"expirationTimestamp,omitempty"`
 }
 
 // UploadTokenRequestList contains a list of UploadTokenRequests
@@ -55,3 +73,39 @@ type UploadTokenRequestList struct {
 	// Items contains a list of UploadTokenRequests
 	Items []UploadTokenRequest `json:"items"`
 }
+
+// ExportTokenRequest is the CR used to initiate a CDI export
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ExportTokenRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Spec contains the parameters of the request
+	Spec ExportTokenRequestSpec `json:"spec"`
+
+	// Status contains the status of the request
+	Status ExportTokenRequestStatus `json:"status"`
+}
+
+// ExportTokenRequestSpec defines the parameters of the token request
+type ExportTokenRequestSpec struct {
+	// PvcName is the name of the PVC to export
+	PvcName string `json:"pvcName"`
+}
+
+// ExportTokenRequestStatus stores the status of a token request
+type ExportTokenRequestStatus struct {
+	// Token is a JWT token to be inserted in "Authentication Bearer header"
+	Token string `json:"token,omitempty"`
+}
+
+// ExportTokenRequestList contains a list of ExportTokenRequests
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ExportTokenRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items contains a list of ExportTokenRequests
+	Items []ExportTokenRequest `json:"items"`
+}
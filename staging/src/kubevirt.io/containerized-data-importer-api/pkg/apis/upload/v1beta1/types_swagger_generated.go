@@ -12,15 +12,21 @@ func (UploadTokenRequest) SwaggerDoc() map[string]string {
 
 func (UploadTokenRequestSpec) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":        "UploadTokenRequestSpec defines the parameters of the token request",
-		"pvcName": "PvcName is the name of the PVC to upload to",
+		"":            "UploadTokenRequestSpec defines the parameters of the token request",
+		"pvcName":     "PvcName is the name of the PVC to upload to",
+		"checksum":    "Checksum is the checksum the uploaded data must match, in the \"algorithm:hash\" format, e.g. \"sha256:<hash>\".\nSupported algorithms are md5, sha1, sha256 and sha512\n+optional",
+		"lifetime":    "Lifetime is how long the token is valid, 5 minutes by default and at most 24 hours\n+optional",
+		"singleUse":   "SingleUse tokens are only accepted for one upload request, resumable and range uploads need several requests\n+optional",
+		"clientCIDRs": "ClientCIDRs restricts the clients allowed to use the token to the given address ranges (or single addresses)\n+optional\n+listType=atomic",
 	}
 }
 
 func (UploadTokenRequestStatus) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":      "UploadTokenRequestStatus stores the status of a token request",
-		"token": "Token is a JWT token to be inserted in \"Authentication Bearer header\"",
+		"":                    "UploadTokenRequestStatus stores the status of a token request",
+		"token":               "Token is a JWT token to be inserted in \"Authentication Bearer header\"",
+		"tokenID":             "TokenID is the unique ID of the token, it can be used to revoke the token",
+		"expirationTimestamp": "ExpirationTimestamp is the time the token expires",
 	}
 }
 
@@ -30,3 +36,32 @@ func (UploadTokenRequestList) SwaggerDoc() map[string]string {
 		"items": "Items contains a list of UploadTokenRequests",
 	}
 }
+
+func (ExportTokenRequest) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":       "ExportTokenRequest is the CR used to initiate a CDI export\n+genclient\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object",
+		"spec":   "Spec contains the parameters of the request",
+		"status": "Status contains the status of the request",
+	}
+}
+
+func (ExportTokenRequestSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":        "ExportTokenRequestSpec defines the parameters of the token request",
+		"pvcName": "PvcName is the name of the PVC to export",
+	}
+}
+
+func (ExportTokenRequestStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":      "ExportTokenRequestStatus stores the status of a token request",
+		"token": "Token is a JWT token to be inserted in \"Authentication Bearer header\"",
+	}
+}
+
+func (ExportTokenRequestList) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":      "ExportTokenRequestList contains a list of ExportTokenRequests\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object",
+		"items": "Items contains a list of ExportTokenRequests",
+	}
+}
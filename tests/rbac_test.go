@@ -221,6 +221,7 @@ var _ = Describe("Aggregated role definition tests", Serial, func() {
 				"volumeimportsources",
 				"volumeuploadsources",
 				"volumeclonesources",
+				"exportjobs",
 			},
 			Verbs: []string{
 				"*",
@@ -243,6 +244,7 @@ var _ = Describe("Aggregated role definition tests", Serial, func() {
 			},
 			Resources: []string{
 				"uploadtokenrequests",
+				"exporttokenrequests",
 			},
 			Verbs: []string{
 				"*",
@@ -267,6 +269,7 @@ var _ = Describe("Aggregated role definition tests", Serial, func() {
 				"volumeimportsources",
 				"volumeuploadsources",
 				"volumeclonesources",
+				"exportjobs",
 			},
 			Verbs: []string{
 				"get",
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"archive/tar"
+	"archive/zip"
 	"compress/gzip"
 	"io"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
 	"github.com/pkg/errors"
 	"github.com/ulikunitz/xz"
 
@@ -21,6 +23,9 @@ var formatTable = map[string]func(string, string, string) (string, error){
 	image.ExtXz:    toXz,
 	image.ExtZst:   toZst,
 	image.ExtTar:   toTar,
+	image.ExtZip:   toZip,
+	image.ExtBz2:   toBz2,
+	image.ExtLz4:   toLz4,
 	image.ExtQcow2: convertUsingQemuImg,
 	image.ExtVmdk:  convertUsingQemuImg,
 	image.ExtVdi:   convertUsingQemuImg,
@@ -160,6 +165,63 @@ func toXz(src, tgtDir, ext string) (string, error) {
 	return tgtPath, nil
 }
 
+func toZip(src, tgtDir, ext string) (string, error) {
+	tgtFile, tgtPath, _ := createTargetFile(src, tgtDir, image.ExtZip)
+	defer tgtFile.Close()
+
+	w := zip.NewWriter(tgtFile)
+	defer w.Close()
+
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error opening file %s", src)
+	}
+	defer srcFile.Close()
+
+	zf, err := w.Create(filepath.Base(src))
+	if err != nil {
+		return "", errors.Wrapf(err, "Error adding file %s to %s", src, tgtPath)
+	}
+	_, err = io.Copy(zf, srcFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error writing to file %s", tgtPath)
+	}
+	return tgtPath, nil
+}
+
+func toLz4(src, tgtDir, ext string) (string, error) {
+	tgtFile, tgtPath, _ := createTargetFile(src, tgtDir, image.ExtLz4)
+	defer tgtFile.Close()
+
+	w := lz4.NewWriter(tgtFile)
+	defer w.Close()
+
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error opening file %s", src)
+	}
+	defer srcFile.Close()
+
+	_, err = io.Copy(w, srcFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error writing to file %s", tgtPath)
+	}
+	return tgtPath, nil
+}
+
+// toBz2 compresses the file with the bzip2 command, compress/bzip2 has no writer.
+func toBz2(src, tgtDir, ext string) (string, error) {
+	tgtFile, tgtPath, _ := createTargetFile(src, tgtDir, image.ExtBz2)
+	defer tgtFile.Close()
+
+	cmd := exec.Command("bzip2", "-c", src)
+	cmd.Stdout = tgtFile
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "OS command `bzip2 -c %s` errored", src)
+	}
+	return tgtPath, nil
+}
+
 func convertUsingQemuImg(srcfile, tgtDir, ext string) (string, error) {
 	base := strings.TrimSuffix(filepath.Base(srcfile), ".iso")
 	tgt := filepath.Join(tgtDir, base+ext)
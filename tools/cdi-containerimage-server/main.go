@@ -24,7 +24,20 @@ func printFiles(dir string) error {
 	})
 }
 
-func getImageFilename(dir string) (string, error) {
+func getImageFilename(dir, diskPath string) (string, error) {
+	if diskPath != "" {
+		if !filepath.IsLocal(diskPath) {
+			return "", errors.Errorf("Invalid disk path %s", diskPath)
+		}
+		fi, err := os.Stat(filepath.Join(dir, diskPath))
+		if err != nil {
+			return "", err
+		}
+		if !fi.Mode().IsRegular() {
+			return "", errors.Errorf("Disk path %s is not a file", diskPath)
+		}
+		return filepath.ToSlash(filepath.Clean(diskPath)), nil
+	}
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return "", err
@@ -47,6 +60,7 @@ func isBootcImage() bool {
 func main() {
 	port := flag.Int("p", 8100, "server port")
 	directory := flag.String("image-dir", ".", "directory to serve")
+	diskPath := flag.String("disk-path", "", "path of the disk to serve relative to the image directory, if it contains several disks")
 	readyFile := flag.String("ready-file", "/shared/ready", "file to create when ready for connections")
 	doneFile := flag.String("done-file", "/shared/done", "file created when the client is done")
 	flag.Parse()
@@ -58,7 +72,7 @@ func main() {
 	if err := printFiles(*directory); err != nil {
 		log.Fatalf("Failed walking the directory %s: %v", *directory, err)
 	}
-	imageFilename, err := getImageFilename(*directory)
+	imageFilename, err := getImageFilename(*directory, *diskPath)
 	if err != nil {
 		log.Fatalf("Failed get image filename in %s: %v", *directory, err)
 	}